@@ -0,0 +1,81 @@
+/**
+ * Tests for the IR contract
+ */
+
+import { describe, it, expect } from "vitest";
+import type { SymbolRecord } from "@langchain/ir-schema";
+
+import { checkIRContract, type IRContractOutput } from "../ir-contract.js";
+
+function symbol(overrides: Partial<SymbolRecord> = {}): SymbolRecord {
+  return {
+    id: "pkg_py_core:Client",
+    packageId: "pkg_py_core",
+    language: "python",
+    kind: "class",
+    name: "Client",
+    qualifiedName: "core.Client",
+    display: { name: "Client", qualified: "core.Client" },
+    signature: "class Client",
+    docs: { summary: "An API client." },
+    source: { repo: "langchain-ai/core", sha: "0000000", path: "core/client.py", line: 1 },
+    urls: { canonical: "/python/core/Client" },
+    tags: { stability: "stable", visibility: "public" },
+    ...overrides,
+  } as SymbolRecord;
+}
+
+function output(symbols: SymbolRecord[]): IRContractOutput {
+  return {
+    package: {
+      packageId: "pkg_py_core",
+      language: "python",
+      ecosystem: "python",
+      version: "1.0.0",
+    },
+    symbols,
+  };
+}
+
+describe("checkIRContract", () => {
+  it("accepts output the pipeline can read", () => {
+    expect(checkIRContract(output([symbol()]), "python")).toEqual([]);
+  });
+
+  it("flags package headers named for another language", () => {
+    const messages = checkIRContract(output([symbol()]), "typescript").map((i) => i.message);
+    expect(messages).toEqual([
+      `package.packageId must start with "pkg_js_"`,
+      `package.language must be "typescript"`,
+      `package.ecosystem must be "javascript"`,
+      `language must be "typescript"`,
+    ]);
+  });
+
+  it("flags duplicate IDs and unknown kinds and visibilities", () => {
+    const broken = symbol({
+      kind: "struct" as never,
+      tags: { stability: "stable", visibility: "exported" as never },
+    });
+    const issues = checkIRContract(output([symbol(), broken]), "python");
+    expect(issues).toEqual([
+      { symbolId: "pkg_py_core:Client", message: "duplicate symbol id" },
+      { symbolId: "pkg_py_core:Client", message: `unknown kind "struct"` },
+      { symbolId: "pkg_py_core:Client", message: `unknown visibility "exported"` },
+    ]);
+  });
+
+  it("flags method members and type references to missing symbols", () => {
+    const client = symbol({
+      members: [
+        { name: "get", refId: "pkg_py_core:Client.get", kind: "method", visibility: "public" },
+      ],
+      typeRefs: [{ name: "Session", refId: "pkg_py_core:Session" }],
+    });
+    const messages = checkIRContract(output([client]), "python").map((i) => i.message);
+    expect(messages).toEqual([
+      `member "get" refers to missing symbol pkg_py_core:Client.get`,
+      `type reference "Session" refers to missing symbol pkg_py_core:Session`,
+    ]);
+  });
+});
@@ -83,6 +83,7 @@ import {
 } from "../subpage-processor.js";
 import { PROJECTS, CONFIG_LANGUAGES } from "../constants.js";
 import { buildRelatedDocs } from "../related-docs-builder.js";
+import { PACKAGE_ID_ECOSYSTEMS } from "../ir-contract.js";
 
 const __dirname = path.dirname(fileURLToPath(import.meta.url));
 
@@ -835,13 +836,7 @@ function runCommand(
  * Normalize package name to a valid ID.
  */
 function normalizePackageId(name: string, language: SymbolLanguage): string {
-  const ecosystemMap: Record<SymbolLanguage, string> = {
-    python: "py",
-    typescript: "js",
-    java: "java",
-    go: "go",
-  };
-  const ecosystem = ecosystemMap[language] || language;
+  const ecosystem = PACKAGE_ID_ECOSYSTEMS[language] || language;
   const normalized = name.replace(/^@/, "").replace(/\//g, "_").replace(/-/g, "_");
   return `pkg_${ecosystem}_${normalized}`;
 }
@@ -85,3 +85,11 @@ export {
 
 // Blob utilities
 export { getBlobBaseUrl, type GetBlobBaseUrlOptions } from "./blob-utils.js";
+
+// IR contract
+export {
+  checkIRContract,
+  PACKAGE_ID_ECOSYSTEMS,
+  type IRContractIssue,
+  type IRContractOutput,
+} from "./ir-contract.js";
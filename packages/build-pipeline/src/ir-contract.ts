@@ -0,0 +1,226 @@
+/**
+ * IR Contract
+ *
+ * The fields and invariants of an extractor's `symbols.json` that the build
+ * pipeline relies on: package IDs as `build-ir` names them, symbol IDs it
+ * shards and names symbol blobs by, the qualified names it routes and looks
+ * symbols up by, the visibility and summary the catalog reads, and member
+ * and type references the renderers resolve. Extractors check their output
+ * against it, so interop breaks surface in their own tests rather than at
+ * upload time. The kinds, visibilities, stabilities, and required symbol
+ * fields list exactly `@langchain/ir-schema`'s, so a schema change fails the
+ * typecheck here until the contract covers it.
+ */
+
+import type {
+  Language,
+  Stability,
+  SymbolKind,
+  SymbolLanguage,
+  SymbolRecord,
+  Visibility,
+} from "@langchain/ir-schema";
+
+/**
+ * A way in which an extractor output breaks the contract.
+ */
+export interface IRContractIssue {
+  /** Symbol the issue applies to (absent for package-level issues) */
+  symbolId?: string;
+  message: string;
+}
+
+/**
+ * The parts of an extractor output the contract covers.
+ */
+export interface IRContractOutput {
+  package?: { packageId?: string; language?: string; ecosystem?: string; version?: string };
+  symbols?: SymbolRecord[];
+}
+
+/**
+ * Ecosystem segments of package IDs (`pkg_{ecosystem}_{name}`) by language.
+ */
+export const PACKAGE_ID_ECOSYSTEMS: Record<SymbolLanguage, string> = {
+  python: "py",
+  typescript: "js",
+  java: "java",
+  go: "go",
+};
+
+/**
+ * Package ecosystems (the languages of URLs and output paths) by language.
+ */
+const ECOSYSTEMS: Record<SymbolLanguage, Language> = {
+  python: "python",
+  typescript: "javascript",
+  java: "java",
+  go: "go",
+};
+
+const SYMBOL_KINDS: Record<SymbolKind, true> = {
+  module: true,
+  class: true,
+  function: true,
+  method: true,
+  property: true,
+  attribute: true,
+  interface: true,
+  typeAlias: true,
+  enum: true,
+  enumMember: true,
+  variable: true,
+  namespace: true,
+  constructor: true,
+  parameter: true,
+};
+
+/**
+ * Keys a type requires.
+ */
+type RequiredKeys<T> = {
+  [K in keyof T]-?: object extends Pick<T, K> ? never : K;
+}[keyof T];
+
+const REQUIRED_FIELDS: Record<RequiredKeys<SymbolRecord>, true> = {
+  id: true,
+  packageId: true,
+  language: true,
+  kind: true,
+  name: true,
+  qualifiedName: true,
+  display: true,
+  signature: true,
+  docs: true,
+  source: true,
+  urls: true,
+  tags: true,
+};
+
+const VISIBILITIES: Record<Visibility, true> = { public: true, protected: true, private: true };
+
+const STABILITIES: Record<Stability, true> = {
+  experimental: true,
+  beta: true,
+  stable: true,
+  deprecated: true,
+};
+
+/**
+ * Check an extractor output for a language against the fields and
+ * invariants the build pipeline reads.
+ */
+export function checkIRContract(
+  output: IRContractOutput,
+  language: SymbolLanguage,
+): IRContractIssue[] {
+  const issues: IRContractIssue[] = [];
+  const pkg = output.package;
+  const prefix = `pkg_${PACKAGE_ID_ECOSYSTEMS[language]}_`;
+
+  if (!pkg?.packageId?.startsWith(prefix)) {
+    issues.push({ message: `package.packageId must start with "${prefix}"` });
+  }
+  if (pkg?.language !== language) {
+    issues.push({ message: `package.language must be "${language}"` });
+  }
+  if (pkg?.ecosystem !== ECOSYSTEMS[language]) {
+    issues.push({ message: `package.ecosystem must be "${ECOSYSTEMS[language]}"` });
+  }
+  if (typeof pkg?.version !== "string" || !pkg.version) {
+    issues.push({ message: "package.version must be a non-empty string" });
+  }
+  if (!Array.isArray(output.symbols) || output.symbols.length === 0) {
+    issues.push({ message: "symbols must be a non-empty array" });
+    return issues;
+  }
+
+  const ids = new Set<string>();
+  for (const symbol of output.symbols) {
+    if (ids.has(symbol.id)) {
+      issues.push({ symbolId: symbol.id, message: "duplicate symbol id" });
+    }
+    ids.add(symbol.id);
+  }
+
+  for (const symbol of output.symbols) {
+    for (const message of checkSymbol(symbol, language, pkg?.packageId, ids)) {
+      issues.push({ symbolId: symbol.id, message });
+    }
+  }
+
+  return issues;
+}
+
+/**
+ * Check a single symbol record.
+ */
+function checkSymbol(
+  symbol: SymbolRecord,
+  language: SymbolLanguage,
+  packageId: string | undefined,
+  ids: Set<string>,
+): string[] {
+  const problems: string[] = [];
+
+  for (const field of Object.keys(REQUIRED_FIELDS) as Array<keyof SymbolRecord>) {
+    if (symbol[field] === undefined || symbol[field] === null) {
+      problems.push(`${field} is required`);
+    }
+  }
+  for (const field of ["id", "name", "qualifiedName"] as const) {
+    if (typeof symbol[field] !== "string" || !symbol[field]) {
+      problems.push(`${field} must be a non-empty string`);
+    }
+  }
+  if (packageId && !symbol.id?.startsWith(`${packageId}:`)) {
+    problems.push(`id must start with "${packageId}:"`);
+  }
+  if (symbol.packageId !== packageId) {
+    problems.push(`packageId "${symbol.packageId}" does not match the package header`);
+  }
+  if (symbol.language !== language) {
+    problems.push(`language must be "${language}"`);
+  }
+  if (!(symbol.kind in SYMBOL_KINDS)) {
+    problems.push(`unknown kind "${symbol.kind}"`);
+  }
+  if (typeof symbol.signature !== "string") {
+    problems.push("signature must be a string");
+  }
+  if (typeof symbol.display?.name !== "string" || typeof symbol.display?.qualified !== "string") {
+    problems.push("display.name and display.qualified must be strings");
+  }
+  if (typeof symbol.docs?.summary !== "string") {
+    problems.push("docs.summary must be a string");
+  }
+  if (!symbol.source?.path || !(symbol.source.line > 0)) {
+    problems.push("source.path and a positive source.line are required");
+  }
+  if (typeof symbol.urls?.canonical !== "string") {
+    problems.push("urls.canonical must be a string");
+  }
+  if (!(symbol.tags?.visibility in VISIBILITIES)) {
+    problems.push(`unknown visibility "${symbol.tags?.visibility}"`);
+  }
+  if (!(symbol.tags?.stability in STABILITIES)) {
+    problems.push(`unknown stability "${symbol.tags?.stability}"`);
+  }
+
+  for (const member of symbol.members ?? []) {
+    if (!member.name || !(member.kind in SYMBOL_KINDS)) {
+      problems.push(`member "${member.name}" needs a name and a known kind`);
+    }
+    // Method members link to their own symbols; other members render inline
+    if (member.kind === "method" && !ids.has(member.refId)) {
+      problems.push(`member "${member.name}" refers to missing symbol ${member.refId}`);
+    }
+  }
+  for (const ref of symbol.typeRefs ?? []) {
+    if (ref.refId && !ids.has(ref.refId)) {
+      problems.push(`type reference "${ref.name}" refers to missing symbol ${ref.refId}`);
+    }
+  }
+
+  return problems;
+}
@@ -0,0 +1,97 @@
+/**
+ * Build constraint tests
+ */
+
+import { mkdtemp, writeFile } from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect } from "vitest";
+
+import {
+  buildTarget,
+  matchBuildConstraint,
+  matchFileName,
+  type BuildTarget,
+} from "../build-constraints.js";
+import { GoExtractor } from "../extractor.js";
+import { createConfig } from "../config.js";
+
+const linux: BuildTarget = { goos: "linux", goarch: "amd64", tags: ["integration"] };
+
+describe("matchBuildConstraint", () => {
+  it("should evaluate tags with go/build's rules", () => {
+    expect(matchBuildConstraint("linux && amd64", linux)).toBe(true);
+    expect(matchBuildConstraint("unix && !windows", linux)).toBe(true);
+    expect(matchBuildConstraint("(darwin || linux) && go1.21", linux)).toBe(true);
+    expect(matchBuildConstraint("integration && gc", linux)).toBe(true);
+    expect(matchBuildConstraint("cgo", linux)).toBe(false);
+    expect(matchBuildConstraint("ignore", linux)).toBe(false);
+    expect(matchBuildConstraint("linux", { ...linux, goos: "android" })).toBe(true);
+    expect(matchBuildConstraint("unix", { ...linux, goos: "windows" })).toBe(false);
+  });
+
+  it("should throw on malformed expressions", () => {
+    expect(() => matchBuildConstraint("linux &&", linux)).toThrow("Invalid build constraint");
+    expect(() => matchBuildConstraint("(linux || windows", linux)).toThrow("Invalid");
+    expect(() => matchBuildConstraint("windows || linux)", linux)).toThrow("Invalid");
+    expect(() => matchBuildConstraint("linux, amd64", linux)).toThrow("Invalid");
+  });
+});
+
+describe("matchFileName", () => {
+  it("should match GOOS and GOARCH suffixes like go/build", () => {
+    expect(matchFileName("pkg/client.go", linux)).toBe(true);
+    expect(matchFileName("pkg/linux.go", linux)).toBe(true);
+    expect(matchFileName("pkg/windows.go", linux)).toBe(true);
+    expect(matchFileName("pkg/file_linux.go", linux)).toBe(true);
+    expect(matchFileName("pkg/file_windows.go", linux)).toBe(false);
+    expect(matchFileName("pkg/file_linux_arm64.go", linux)).toBe(false);
+    expect(matchFileName("pkg/file_linux_amd64_test.go", linux)).toBe(true);
+    expect(matchFileName("pkg/file_arm64.go", linux)).toBe(false);
+    expect(matchFileName("pkg/file_unix.go", linux)).toBe(true);
+  });
+});
+
+describe("buildTarget", () => {
+  it("should select nothing without tags or a platform, and default to the host", () => {
+    const env = { GOOS: "plan9", GOARCH: "386" };
+    expect(buildTarget({}, env)).toBeUndefined();
+    expect(buildTarget({ buildTags: ["integration"] }, env)).toEqual({
+      goos: "plan9",
+      goarch: "386",
+      tags: ["integration"],
+    });
+    expect(buildTarget({ goos: "windows" }, env)).toEqual({
+      goos: "windows",
+      goarch: "386",
+      tags: [],
+    });
+  });
+});
+
+describe("GoExtractor with a build target", () => {
+  it("should extract only the files that build for the target", async () => {
+    const root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-build-tags-"));
+    await writeFile(path.join(root, "go.mod"), "module example.com/m\n");
+    await writeFile(path.join(root, "m.go"), "package m\n\n// Run runs.\nfunc Run() {}\n");
+    await writeFile(path.join(root, "m_windows.go"), "package m\n\nfunc RunWindows() {}\n");
+    await writeFile(
+      path.join(root, "m_integration.go"),
+      "//go:build integration\n\npackage m\n\nfunc RunIntegration() {}\n",
+    );
+    const extract = async (selection: { buildTags?: string[]; goos?: string }) => {
+      const config = createConfig({ packageName: "m", packagePath: root, ...selection });
+      const result = await new GoExtractor(config).extract();
+      return result.functions.map((f) => f.name).sort();
+    };
+
+    expect(await extract({})).toEqual(["Run", "RunIntegration", "RunWindows"]);
+    expect(await extract({ goos: "linux" })).toEqual(["Run"]);
+    expect(await extract({ goos: "linux", buildTags: ["integration"] })).toEqual([
+      "Run",
+      "RunIntegration",
+    ]);
+    expect(await extract({ goos: "windows" })).toEqual(["Run", "RunWindows"]);
+  });
+});
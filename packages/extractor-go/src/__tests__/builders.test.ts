@@ -0,0 +1,120 @@
+/**
+ * Builder API tests
+ */
+
+import { mkdtemp, rm, writeFile } from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect, beforeAll, afterAll } from "vitest";
+
+import { extractCallChains, inferChainOrder, isChainable } from "../builders.js";
+import { GoExtractor, type GoMethod } from "../extractor.js";
+import { GoTransformer, type GoSymbolRecord } from "../transformer.js";
+import { createConfig } from "../config.js";
+
+function method(returns: string): GoMethod {
+  return { name: "M", signature: "", parameters: [], returns, sourceFile: "q.go", startLine: 1 };
+}
+
+describe("isChainable", () => {
+  it("should accept methods returning the receiver type", () => {
+    expect(isChainable(method("*Query"), "Query")).toBe(true);
+    expect(isChainable(method("Query"), "Query")).toBe(true);
+    expect(isChainable(method("*Query[T]"), "Query")).toBe(true);
+  });
+
+  it("should reject other results", () => {
+    expect(isChainable(method("(*Query, error)"), "Query")).toBe(false);
+    expect(isChainable(method("*Result"), "Query")).toBe(false);
+    expect(isChainable(method(""), "Query")).toBe(false);
+  });
+});
+
+describe("extractCallChains", () => {
+  it("should find chains across lines and nested arguments", () => {
+    const code = [
+      'q := db.Query().Select("a", f(x)).',
+      "\tWhere(cond).",
+      "\tLimit(10)",
+      "rows := q.Run()",
+    ].join("\n");
+
+    expect(extractCallChains(code)).toEqual([["Query", "Select", "Where", "Limit"]]);
+  });
+});
+
+describe("inferChainOrder", () => {
+  it("should order methods by their average position in chains", () => {
+    const chains = [
+      ["Select", "Where", "Limit"],
+      ["Select", "OrderBy", "Limit"],
+      ["Where", "Limit"],
+    ];
+    expect(inferChainOrder(chains, ["Limit", "OrderBy", "Select", "Where"])).toEqual([
+      "Select",
+      "Where",
+      "OrderBy",
+      "Limit",
+    ]);
+  });
+
+  it("should return undefined without matching chains", () => {
+    expect(inferChainOrder([["Other", "Call"]], ["Limit"])).toBeUndefined();
+  });
+});
+
+describe("GoTransformer builder annotations", () => {
+  let root: string;
+
+  beforeAll(async () => {
+    root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-builders-"));
+    await writeFile(
+      path.join(root, "query.go"),
+      [
+        "package sql",
+        "",
+        "// Query builds a SELECT statement:",
+        "//",
+        '//\trows, err := sql.From("users").Where("active").Limit(10).Run(ctx)',
+        "type Query struct{}",
+        "",
+        "// From starts a query.",
+        "func From(table string) *Query { return &Query{} }",
+        "",
+        "// Limit caps the number of rows.",
+        "func (q *Query) Limit(n int) *Query { return q }",
+        "",
+        "// Where filters rows.",
+        "func (q *Query) Where(cond string) *Query { return q }",
+        "",
+        "// Run executes the query.",
+        "func (q *Query) Run(ctx context.Context) (*Rows, error) { return nil, nil }",
+        "",
+      ].join("\n"),
+    );
+  });
+
+  afterAll(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  async function transform(inferChainOrder?: boolean): Promise<GoSymbolRecord[]> {
+    const config = createConfig({ packageName: "sql", packagePath: root, inferChainOrder });
+    return new GoTransformer(await new GoExtractor(config).extract(), config).transform();
+  }
+
+  it("should flag chainable methods", async () => {
+    const symbols = await transform();
+    const query = symbols.find((s) => s.name === "Query")!;
+
+    expect(query.builder).toEqual({ chainable: ["Limit", "Where"], order: undefined });
+    expect(query.members!.find((m) => m.name === "Run")!.chainable).toBeUndefined();
+    expect(symbols.find((s) => s.qualifiedName === "Query.Where")!.chainable).toBe(true);
+  });
+
+  it("should infer the chain order from doc examples when enabled", async () => {
+    const symbols = await transform(true);
+    expect(symbols.find((s) => s.name === "Query")!.builder!.order).toEqual(["Where", "Limit"]);
+  });
+});
@@ -0,0 +1,65 @@
+/**
+ * Canonical ordering tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput, serializeOutput } from "../output.js";
+import { compareCanonical, sortSymbols } from "../canonical.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("canonical ordering", () => {
+  async function extract(reverse: boolean): Promise<string> {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    const symbols = new GoTransformer(result, config).transform();
+    if (reverse) symbols.reverse();
+    return serializeOutput(buildOutput(result, config, symbols));
+  }
+
+  it("should write byte-identical output whatever the symbol order", async () => {
+    expect(await extract(true)).toBe(await extract(false));
+  });
+
+  it("should sort symbols by qualified name with methods after their type", async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    const names = sortSymbols(new GoTransformer(result, config).transform()).map(
+      (symbol) => symbol.qualifiedName,
+    );
+
+    expect(names).toEqual([...names].sort(compareCanonical));
+    expect(names.indexOf("Client.Close")).toBe(names.indexOf("Client") + 1);
+  });
+
+  it("should sort methods by name and keep fields in declaration order", async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    const client = sortSymbols(new GoTransformer(result, config).transform()).find(
+      (symbol) => symbol.qualifiedName === "Client",
+    )!;
+
+    expect(client.members!.map((member) => member.name)).toEqual([
+      "Close",
+      "Get",
+      "Post",
+      "SetTimeout",
+      "BaseURL",
+      "APIKey",
+      "Timeout",
+    ]);
+  });
+
+  it("should compare independent of the locale", () => {
+    expect(["b", "B", "a", "Z"].sort(compareCanonical)).toEqual(["B", "Z", "a", "b"]);
+  });
+});
@@ -0,0 +1,65 @@
+/**
+ * Capabilities manifest tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer, type GoSymbolRecord } from "../transformer.js";
+import { createConfig, type GoExtractorConfig } from "../config.js";
+import { buildOutput } from "../output.js";
+import { buildCapabilities, capabilityNames } from "../capabilities.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+async function extractWith(overrides: Partial<GoExtractorConfig> = {}) {
+  const config = createConfig({
+    packageName: "test-package",
+    packagePath: fixturesPath,
+    ...overrides,
+  });
+  const result = await new GoExtractor(config).extract();
+  return buildOutput(result, config, new GoTransformer(result, config).transform());
+}
+
+describe("buildCapabilities", () => {
+  it("should list every capability in the output", async () => {
+    const output = await extractWith();
+    expect(Object.keys(output.capabilities!)).toEqual(capabilityNames);
+  });
+
+  it("should report features that produced data as applied", async () => {
+    const { capabilities } = await extractWith();
+
+    expect(capabilities!.generics).toEqual({ enabled: true, applied: true });
+    expect(capabilities!.implementations).toEqual({ enabled: true, applied: true });
+    expect(capabilities!.iterators).toEqual({ enabled: true, applied: true });
+  });
+
+  it("should tell enabled features without data from disabled ones", async () => {
+    const { capabilities } = await extractWith({ pairContextVariants: false });
+
+    expect(capabilities!.docTruncation).toEqual({ enabled: true, applied: false });
+    expect(capabilities!.contextVariants).toEqual({ enabled: false, applied: false });
+    expect(capabilities!.testHelpers).toEqual({ enabled: false, applied: false });
+  });
+
+  it("should follow the configuration", async () => {
+    const { capabilities } = await extractWith({ testHelpers: true, repo: "langchain-ai/x" });
+
+    expect(capabilities!.testHelpers).toEqual({ enabled: true, applied: true });
+    expect(capabilities!.feedbackLinks).toEqual({ enabled: true, applied: true });
+  });
+
+  it("should report nothing applied without symbols", () => {
+    const config = createConfig({ packageName: "empty", packagePath: fixturesPath });
+    const capabilities = buildCapabilities(config, [] as GoSymbolRecord[]);
+
+    expect(Object.values(capabilities).some((status) => status.applied)).toBe(false);
+  });
+});
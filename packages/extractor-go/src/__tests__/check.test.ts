@@ -0,0 +1,89 @@
+/**
+ * Output check tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput, type ExtractorOutput } from "../output.js";
+import { diffOutputs, formatDiff, hasChanges } from "../check.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("diffOutputs", () => {
+  let baseline: ExtractorOutput;
+
+  beforeAll(async () => {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      repo: "langchain-ai/test-repo",
+      sha: "abc123",
+    });
+
+    const result = await new GoExtractor(config).extract();
+    const symbols = new GoTransformer(result, config).transform();
+    baseline = buildOutput(result, config, symbols);
+  });
+
+  function clone(output: ExtractorOutput): ExtractorOutput {
+    return JSON.parse(JSON.stringify(output)) as ExtractorOutput;
+  }
+
+  it("should report no changes for identical output", () => {
+    const diff = diffOutputs(baseline, clone(baseline));
+    expect(hasChanges(diff)).toBe(false);
+  });
+
+  it("should report added and removed symbols", () => {
+    const current = clone(baseline);
+    const removed = current.symbols.shift()!;
+    current.symbols.push({ ...removed, id: "pkg_go_test_package:Added" });
+
+    const diff = diffOutputs(baseline, current);
+    expect(hasChanges(diff)).toBe(true);
+    expect(diff.added).toEqual(["pkg_go_test_package:Added"]);
+    expect(diff.removed).toEqual([removed.id]);
+  });
+
+  it("should report changed fields and signatures", () => {
+    const current = clone(baseline);
+    const client = current.symbols.find((s) => s.id === "pkg_go_test_package:Client")!;
+    client.signature = "type Client interface";
+
+    const diff = diffOutputs(baseline, current);
+    expect(diff.changed).toHaveLength(1);
+    expect(diff.changed[0].fields).toEqual(["signature"]);
+    expect(diff.changed[0].signature).toEqual({
+      before: "type Client struct",
+      after: "type Client interface",
+    });
+  });
+
+  it("should report package header changes", () => {
+    const current = clone(baseline);
+    current.package.version = "1.2.3";
+
+    const diff = diffOutputs(baseline, current);
+    expect(diff.packageFields).toEqual(["version"]);
+  });
+
+  it("should format a readable report", () => {
+    const current = clone(baseline);
+    current.symbols.find((s) => s.id === "pkg_go_test_package:Client")!.signature =
+      "type Client interface";
+
+    const report = formatDiff(diffOutputs(baseline, current));
+    expect(report).toContain("0 added, 0 removed, 1 changed");
+    expect(report).toContain("~ pkg_go_test_package:Client (signature)");
+    expect(report).toContain("- type Client struct");
+    expect(report).toContain("+ type Client interface");
+  });
+});
@@ -0,0 +1,120 @@
+/**
+ * Documentation chunk tests
+ */
+
+import { describe, it, expect } from "vitest";
+import type { SymbolRecord } from "@langchain/ir-schema";
+
+import {
+  buildChunks,
+  estimateTokens,
+  ragChunks,
+  selectSymbols,
+  streamChunks,
+} from "../chunks.js";
+
+function symbol(qualifiedName: string, signature: string, description?: string): SymbolRecord {
+  return {
+    id: `pkg_go_example:${qualifiedName.replace(/\./g, "_")}`,
+    qualifiedName,
+    signature,
+    docs: { summary: description?.split("\n")[0] ?? "", description },
+  } as SymbolRecord;
+}
+
+const symbols = [
+  symbol(
+    "Client",
+    "type Client struct",
+    "Client talks to the API.\n\n```go\nc := NewClient()\n```\n\nIt is safe for concurrent use.",
+  ),
+  symbol("Client.Get", "func (c *Client) Get(path string) ([]byte, error)", "Get fetches path."),
+  symbol("Connect", "func Connect() (*Client, error)"),
+];
+
+describe("buildChunks", () => {
+  it("should group chunks by kind in priority order", () => {
+    const chunks = buildChunks(symbols);
+
+    expect(chunks.map((c) => `${c.kind}:${c.qualifiedName}`)).toEqual([
+      "signature:Client",
+      "signature:Client.Get",
+      "signature:Connect",
+      "doc:Client",
+      "doc:Client.Get",
+      "example:Client",
+    ]);
+    expect(chunks[3].text).toBe("Client talks to the API.\n\nIt is safe for concurrent use.");
+    expect(chunks[5].text).toBe("```go\nc := NewClient()\n```");
+  });
+
+  it("should only emit the requested kinds", () => {
+    const kinds = buildChunks(symbols, ["example", "doc"]).map((c) => c.kind);
+    expect(kinds).toEqual(["example", "doc", "doc"]);
+  });
+});
+
+describe("selectSymbols", () => {
+  it("should select a symbol with its methods", () => {
+    const names = selectSymbols(symbols, "Client").map((s) => s.qualifiedName);
+    expect(names).toEqual(["Client", "Client.Get"]);
+  });
+
+  it("should reject unknown symbols", () => {
+    expect(() => selectSymbols(symbols, "Missing")).toThrow("Unknown symbol: Missing");
+  });
+});
+
+describe("streamChunks", () => {
+  it("should stay within the budget, skipping chunks that don't fit", () => {
+    const signature = estimateTokens("```go\ntype Client struct\n```");
+    const chunks = Array.from(streamChunks(symbols, { symbol: "Client", budget: signature + 5 }));
+
+    expect(chunks.map((c) => c.kind)).toEqual(["signature", "doc"]);
+    expect(chunks[1].text).toBe("Get fetches path.");
+  });
+});
+
+describe("ragChunks", () => {
+  const published = (s: SymbolRecord, visibility = "public") =>
+    ({
+      ...s,
+      urls: { canonical: `/${s.qualifiedName}` },
+      tags: { stability: "stable", visibility },
+    }) as SymbolRecord;
+  const paragraphs = ["First paragraph.", "Second paragraph.", "x".repeat(30)].join("\n\n");
+  const records = [
+    published(symbol("Client", "type Client struct", paragraphs)),
+    published(symbol("client", "type client struct", "client is unexported."), "private"),
+  ];
+
+  it("should split docs at paragraphs to fit the chunk size", () => {
+    const docs = ragChunks(records, 10).filter((c) => c.kind === "doc");
+
+    expect(docs.map((c) => c.text)).toEqual([
+      "First paragraph.\n\nSecond paragraph.",
+      "x".repeat(30),
+    ]);
+    expect(docs.every((c) => c.tokens <= 10 && c.url === "/Client")).toBe(true);
+  });
+
+  it("should cut paragraphs too long on their own", () => {
+    const docs = ragChunks(records, 4).filter((c) => c.kind === "doc");
+    expect(docs.map((c) => c.text.length)).toEqual([16, 16, 1, 16, 14]);
+  });
+
+  it("should key chunks by their content", () => {
+    const first = ragChunks(records, 10);
+    const edited = published(symbol("Client", "type Client struct", `${paragraphs} More.`));
+
+    expect(first.map((c) => c.id)).toEqual(ragChunks([...records], 10).map((c) => c.id));
+    expect(first[0].id).toMatch(/^[0-9a-f]{16}$/);
+    const ids = ragChunks([edited], 10).map((c) => c.id);
+    expect(ids.slice(0, 2)).toEqual([first[0].id, first[1].id]);
+    expect(ids[2]).not.toBe(first[2].id);
+  });
+
+  it("should leave out unexported symbols", () => {
+    expect(ragChunks(records).map((c) => c.qualifiedName)).toEqual(["Client", "Client"]);
+  });
+});
@@ -0,0 +1,136 @@
+/**
+ * Code ownership tests
+ */
+
+import fs from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import {
+  OwnershipResolver,
+  loadOwnership,
+  parseCodeowners,
+  ownershipPatternToRegExp,
+} from "../codeowners.js";
+import { GoExtractor, type ExtractionResult } from "../extractor.js";
+import { GoTransformer, type GoSymbolRecord } from "../transformer.js";
+import { createConfig, type GoExtractorConfig } from "../config.js";
+import { buildOutput } from "../output.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("parseCodeowners", () => {
+  it("should parse patterns and owners, skipping comments", () => {
+    const rules = parseCodeowners(
+      ["# Owners", "*       @langchain-ai/docs", "", "/pkg/client/ @alice @bob # inline"].join(
+        "\n",
+      ),
+    );
+
+    expect(rules).toEqual([
+      { pattern: "*", owners: ["@langchain-ai/docs"] },
+      { pattern: "/pkg/client/", owners: ["@alice", "@bob"] },
+    ]);
+  });
+});
+
+describe("ownershipPatternToRegExp", () => {
+  it("should match unanchored file patterns at any depth", () => {
+    const regex = ownershipPatternToRegExp("*.go");
+    expect(regex.test("main.go")).toBe(true);
+    expect(regex.test("pkg/client/client.go")).toBe(true);
+    expect(regex.test("README.md")).toBe(false);
+  });
+
+  it("should anchor patterns starting with a slash", () => {
+    const regex = ownershipPatternToRegExp("/pkg/");
+    expect(regex.test("pkg/client.go")).toBe(true);
+    expect(regex.test("internal/pkg/client.go")).toBe(false);
+  });
+
+  it("should support double-star segments", () => {
+    const regex = ownershipPatternToRegExp("pkg/**/client.go");
+    expect(regex.test("pkg/a/b/client.go")).toBe(true);
+    expect(regex.test("pkg/client.go")).toBe(true);
+  });
+});
+
+describe("OwnershipResolver", () => {
+  it("should let the last matching rule win", () => {
+    const resolver = new OwnershipResolver([
+      { pattern: "*", owners: ["@everyone"] },
+      { pattern: "/pkg/client/", owners: ["@client-team"] },
+    ]);
+
+    expect(resolver.ownersOf("pkg/client/get.go")).toEqual(["@client-team"]);
+    expect(resolver.ownersOf("pkg/server/serve.go")).toEqual(["@everyone"]);
+  });
+
+  it("should return no owners when nothing matches", () => {
+    const resolver = new OwnershipResolver([{ pattern: "/docs/", owners: ["@docs"] }]);
+    expect(resolver.ownersOf("main.go")).toEqual([]);
+  });
+});
+
+describe("loadOwnership", () => {
+  it("should read an explicit owners file", async () => {
+    const dir = await fs.mkdtemp(path.join(os.tmpdir(), "codeowners-"));
+    await fs.writeFile(path.join(dir, "OWNERS"), "* @langchain-ai/go\n");
+
+    const ownership = await loadOwnership(dir, { ownersFile: path.join(dir, "OWNERS") });
+    expect(ownership!.resolver.ownersOf("client.go")).toEqual(["@langchain-ai/go"]);
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+
+  it("should fail when an explicit owners file can't be read", async () => {
+    const dir = await fs.mkdtemp(path.join(os.tmpdir(), "codeowners-"));
+
+    await expect(loadOwnership(dir, { ownersFile: path.join(dir, "OWNERS") })).rejects.toThrow(
+      "Can't read owners file",
+    );
+    expect(await loadOwnership(dir, {})).toBeUndefined();
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+});
+
+describe("ownership metadata", () => {
+  let result: ExtractionResult;
+  let symbols: GoSymbolRecord[];
+  let config: GoExtractorConfig;
+
+  beforeAll(async () => {
+    config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      owners: {
+        "*": ["@langchain-ai/go"],
+        "types.go": ["@langchain-ai/clients"],
+      },
+    });
+
+    result = await new GoExtractor(config).extract();
+    symbols = new GoTransformer(result, config).transform();
+  });
+
+  it("should attach owners of the declaring file to types", () => {
+    const client = symbols.find((s) => s.name === "Client");
+    expect(client!.owners).toEqual(["@langchain-ai/clients"]);
+  });
+
+  it("should attach owners to methods and functions", () => {
+    const get = symbols.find((s) => s.qualifiedName === "Client.Get");
+    const connect = symbols.find((s) => s.name === "Connect");
+    expect(get!.owners).toEqual(["@langchain-ai/clients"]);
+    expect(connect!.owners).toEqual(["@langchain-ai/go"]);
+  });
+
+  it("should attach package owners to the output header", () => {
+    const output = buildOutput(result, config, symbols);
+    expect(output.package.owners).toEqual(["@langchain-ai/go"]);
+  });
+});
@@ -0,0 +1,56 @@
+/**
+ * Compressed artifact tests
+ */
+
+import fs from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect } from "vitest";
+
+import {
+  compress,
+  compressFile,
+  compressedPath,
+  decompress,
+  detectCompression,
+  uncompressedPath,
+} from "../compress.js";
+
+describe("compressed artifacts", () => {
+  const json = JSON.stringify({ symbols: [{ name: "Client" }] });
+
+  it("should round-trip gzip and zstd", () => {
+    for (const compression of ["gzip", "zstd"] as const) {
+      const compressed = compress(json, compression);
+
+      expect(detectCompression(compressed)).toBe(compression);
+      expect(decompress(compressed).toString("utf-8")).toBe(json);
+    }
+  });
+
+  it("should pass uncompressed contents through", () => {
+    expect(detectCompression(Buffer.from(json))).toBeUndefined();
+    expect(decompress(Buffer.from(json)).toString("utf-8")).toBe(json);
+  });
+
+  it("should add and strip compression extensions", () => {
+    expect(compressedPath("symbols.json", "gzip")).toBe("symbols.json.gz");
+    expect(compressedPath("symbols.json.zst", "zstd")).toBe("symbols.json.zst");
+    expect(uncompressedPath("symbols.jsonl.zst")).toBe("symbols.jsonl");
+    expect(uncompressedPath("symbols.json")).toBe("symbols.json");
+  });
+
+  it("should compress a file and remove the original", async () => {
+    const dir = await fs.mkdtemp(path.join(os.tmpdir(), "extractor-go-compress-"));
+    const file = path.join(dir, "symbols.json.tmp");
+    await fs.writeFile(file, json, "utf-8");
+
+    await compressFile(file, path.join(dir, "symbols.json.zst"), "zstd");
+
+    expect(await fs.readdir(dir)).toEqual(["symbols.json.zst"]);
+    const content = await fs.readFile(path.join(dir, "symbols.json.zst"));
+    expect(decompress(content).toString("utf-8")).toBe(json);
+    await fs.rm(dir, { recursive: true });
+  });
+});
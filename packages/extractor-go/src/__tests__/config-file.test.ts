@@ -0,0 +1,90 @@
+/**
+ * Configuration file tests
+ */
+
+import fs from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect } from "vitest";
+
+import { loadConfigFile, parseConfigFile } from "../config-file.js";
+
+describe("parseConfigFile", () => {
+  it("should resolve paths against the file's directory", () => {
+    const config = parseConfigFile(
+      {
+        package: "langsmith",
+        path: "./src",
+        output: { path: "out/symbols.json", toc: "/tmp/toc.json", format: "jsonl" },
+        plugins: ["./tools/stability.go"],
+      },
+      "/repo",
+    );
+
+    expect(config.package).toBe("langsmith");
+    expect(config.path).toBe("/repo/src");
+    expect(config.plugins).toEqual(["/repo/tools/stability.go"]);
+    expect(config.output).toEqual({
+      path: "/repo/out/symbols.json",
+      format: "jsonl",
+      profile: undefined,
+      toc: "/tmp/toc.json",
+      searchIndex: undefined,
+      diagrams: undefined,
+    });
+  });
+
+  it("should accept a single pattern for a list", () => {
+    const config = parseConfigFile(
+      { packages: "./...", exclude: "*.pb.go", symbols: { exclude: ["Mock*"] } },
+      ".",
+    );
+
+    expect(config.packages).toEqual(["./..."]);
+    expect(config.exclude).toEqual(["*.pb.go"]);
+    expect(config.symbols?.exclude).toEqual(["Mock*"]);
+  });
+
+  it("should reject unknown keys and invalid values", () => {
+    expect(() => parseConfigFile({ pacakge: "x" }, ".")).toThrow(
+      "Invalid langchain-references.yaml: unknown key pacakge",
+    );
+    expect(() => parseConfigFile({ output: { toc: "a", tocs: "b" } }, ".")).toThrow(
+      "unknown key output.tocs",
+    );
+    expect(() => parseConfigFile({ output: { format: "xml" } }, ".")).toThrow(
+      "output.format must be one of",
+    );
+    expect(() => parseConfigFile({ tiers: { "Beta*": "secret" } }, ".")).toThrow(
+      "tiers.Beta* must be one of public, partner, internal",
+    );
+    expect(() => parseConfigFile({ include: [1] }, ".")).toThrow(
+      "include must be a list of strings",
+    );
+    expect(() => parseConfigFile(["package"], ".")).toThrow("the file must be a mapping");
+  });
+});
+
+describe("loadConfigFile", () => {
+  it("should read a YAML file", async () => {
+    const dir = await fs.mkdtemp(path.join(os.tmpdir(), "config-file-"));
+    const file = path.join(dir, "langchain-references.yaml");
+    const lines = ["package: langsmith", "symbols:", "  exclude: [Mock*]", "categories:"];
+    await fs.writeFile(file, [...lines, '  "Trace*": tracing'].join("\n"));
+
+    const config = await loadConfigFile(file);
+
+    expect(config.package).toBe("langsmith");
+    expect(config.symbols?.exclude).toEqual(["Mock*"]);
+    expect(config.categories).toEqual({ "Trace*": "tracing" });
+  });
+
+  it("should report syntax errors with the file name", async () => {
+    const dir = await fs.mkdtemp(path.join(os.tmpdir(), "config-file-"));
+    const file = path.join(dir, "langchain-references.yaml");
+    await fs.writeFile(file, "package: a\npackage: b\n");
+
+    await expect(loadConfigFile(file)).rejects.toThrow(`Invalid ${file}: line 2: duplicate key`);
+  });
+});
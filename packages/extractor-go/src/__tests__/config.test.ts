@@ -3,7 +3,14 @@
  */
 
 import { describe, it, expect } from "vitest";
-import { createConfig, validateConfig, defaultConfig, type GoExtractorConfig } from "../config.js";
+import {
+  createConfig,
+  validateConfig,
+  defaultConfig,
+  type ExampleVerification,
+  type GoExtractorConfig,
+  type VisibilityTier,
+} from "../config.js";
 
 describe("createConfig", () => {
   it("should create a config with required fields", () => {
@@ -96,6 +103,64 @@ describe("validateConfig", () => {
 
     expect(() => validateConfig(config)).toThrow("packagePath is required");
   });
+
+  it("should throw for unknown visibility tiers", () => {
+    const config = createConfig({
+      packageName: "langsmith",
+      packagePath: "/path/to/src",
+      includeTiers: ["public", "secret" as VisibilityTier],
+    });
+
+    expect(() => validateConfig(config)).toThrow("Unknown visibility tier: secret");
+  });
+
+  it("should throw for invalid note markers", () => {
+    const config = createConfig({
+      packageName: "langsmith",
+      packagePath: "/path/to/src",
+      noteMarkers: ["BUG", "Todo"],
+    });
+
+    expect(() => validateConfig(config)).toThrow("Invalid note marker: Todo");
+  });
+
+  it("should throw for unknown example verification modes", () => {
+    const config = createConfig({
+      packageName: "langsmith",
+      packagePath: "/path/to/src",
+      verifyExamples: "strict" as ExampleVerification,
+    });
+
+    expect(() => validateConfig(config)).toThrow("Unknown example verification: strict");
+  });
+
+  it("should throw for invalid doc tags", () => {
+    const config = createConfig({
+      packageName: "langsmith",
+      packagePath: "/path/to/src",
+      docTags: ["since", "@beta"],
+    });
+
+    expect(() => validateConfig(config)).toThrow("Invalid doc tag: @beta");
+  });
+
+  it("should throw for non-positive doc limits", () => {
+    const config = createConfig({
+      packageName: "langsmith",
+      packagePath: "/path/to/src",
+      docLimits: { default: { maxChars: 0, maxSections: 2 } },
+    });
+
+    expect(() => validateConfig(config)).toThrow("Doc limits need a positive maxChars");
+  });
+
+  it("should throw for unknown build targets and invalid build tags", () => {
+    const config = createConfig({ packageName: "langsmith", packagePath: "/path/to/src" });
+
+    expect(() => validateConfig({ ...config, goos: "macos" })).toThrow("Unknown GOOS: macos");
+    expect(() => validateConfig({ ...config, goarch: "x64" })).toThrow("Unknown GOARCH: x64");
+    expect(() => validateConfig({ ...config, buildTags: ["a b"] })).toThrow("Invalid build tag");
+  });
 });
 
 describe("defaultConfig", () => {
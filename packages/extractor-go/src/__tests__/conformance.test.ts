@@ -0,0 +1,91 @@
+/**
+ * Conformance tests
+ */
+
+import { mkdtemp, writeFile } from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import { checkConformance, runConformance, type ConformanceReport } from "../conformance.js";
+import type { ExtractorOutput } from "../output.js";
+
+describe("runConformance", () => {
+  let report: ConformanceReport;
+
+  beforeAll(async () => {
+    report = await runConformance();
+  });
+
+  it("should extract the bundled fixture package", () => {
+    expect(report.output.package.packageId).toBe("pkg_go_conformance");
+    expect(report.output.symbols.map((s) => s.name)).toContain("Client");
+  });
+
+  it("should produce output the IR consumers accept", () => {
+    expect(report.issues).toEqual([]);
+  });
+
+  it("should extract a module at a given path", async () => {
+    const root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-conformance-"));
+    await writeFile(path.join(root, "go.mod"), "module example.com/m\n");
+    await writeFile(path.join(root, "m.go"), "package m\n\n// Run runs.\nfunc Run() {}\n");
+
+    const { output, issues } = await runConformance(root);
+    expect(output.symbols.map((s) => s.name)).toEqual(["Run"]);
+    expect(issues).toEqual([]);
+    await expect(runConformance(path.join(root, "missing"))).rejects.toThrow("No such module");
+  });
+});
+
+describe("checkConformance", () => {
+  let output: ExtractorOutput;
+
+  beforeAll(async () => {
+    output = (await runConformance()).output;
+  });
+
+  function withSymbols(symbols: ExtractorOutput["symbols"]): ExtractorOutput {
+    return { ...output, symbols };
+  }
+
+  it("should flag duplicate symbol IDs", () => {
+    const [first] = output.symbols;
+    const issues = checkConformance(withSymbols([first, first]));
+    expect(issues).toContainEqual({ symbolId: first.id, message: "duplicate symbol id" });
+  });
+
+  it("should flag unknown kinds and visibilities", () => {
+    const [first] = output.symbols;
+    const broken = { ...first, kind: "struct", tags: { ...first.tags, visibility: "exported" } };
+    const messages = checkConformance(withSymbols([broken as never])).map((i) => i.message);
+    expect(messages).toContain(`unknown kind "struct"`);
+    expect(messages).toContain(`unknown visibility "exported"`);
+  });
+
+  it("should flag missing fields the IR schema requires", () => {
+    const first: Partial<ExtractorOutput["symbols"][number]> = { ...output.symbols[0] };
+    delete first.urls;
+    const messages = checkConformance(withSymbols([first as never])).map((i) => i.message);
+    expect(messages).toContain("urls is required");
+  });
+
+  it("should flag method members that point at missing symbols", () => {
+    const client = output.symbols.find((s) => s.name === "Client")!;
+    const issues = checkConformance(withSymbols([client]));
+    expect(issues.some((i) => i.message.includes("refers to missing symbol"))).toBe(true);
+  });
+
+  it("should flag a package header the build pipeline can't read", () => {
+    const messages = checkConformance({
+      package: { ...output.package, packageId: "go_conformance", version: "" },
+      symbols: [],
+    }).map((i) => i.message);
+    expect(messages).toEqual([
+      `package.packageId must start with "pkg_go_"`,
+      "package.version must be a non-empty string",
+      "symbols must be a non-empty array",
+    ]);
+  });
+});
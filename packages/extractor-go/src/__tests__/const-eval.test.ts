@@ -0,0 +1,131 @@
+/**
+ * Constant expression evaluation tests
+ */
+
+import { describe, it, expect } from "vitest";
+
+import {
+  convertConstValue,
+  evaluateConstExpr,
+  formatDuration,
+  type GoConstValue,
+} from "../const-eval.js";
+
+describe("evaluateConstExpr", () => {
+  it("should fold durations", () => {
+    expect(evaluateConstExpr("90 * time.Minute")).toEqual({
+      value: "5400000000000",
+      type: "time.Duration",
+      underlyingType: "int64",
+      display: "1h30m0s",
+    });
+  });
+
+  it("should keep typed results integral for fractional multipliers", () => {
+    expect(evaluateConstExpr("1.5 * time.Second")?.display).toBe("1.5s");
+  });
+
+  it("should follow Go operator precedence", () => {
+    expect(evaluateConstExpr("1 + 2 * 3 << 1")?.value).toBe("13");
+    expect(evaluateConstExpr("(1 + 2) * 3")?.value).toBe("9");
+  });
+
+  it("should use integer division for untyped integers", () => {
+    expect(evaluateConstExpr("10 / 4")).toEqual({ value: "2", type: "untyped int" });
+    expect(evaluateConstExpr("10 / 4.0")).toEqual({ value: "2.5", type: "untyped float" });
+  });
+
+  it("should parse Go integer literals", () => {
+    expect(evaluateConstExpr("0x1F")?.value).toBe("31");
+    expect(evaluateConstExpr("0755")?.value).toBe("493");
+    expect(evaluateConstExpr("1_000_000")?.value).toBe("1000000");
+    expect(evaluateConstExpr("0x1F &^ 0x3")?.value).toBe("28");
+  });
+
+  it("should evaluate conversions", () => {
+    expect(evaluateConstExpr("time.Duration(250) * time.Millisecond")?.display).toBe("250ms");
+    expect(evaluateConstExpr("uint8(255)")).toEqual({
+      value: "255",
+      type: "uint8",
+    });
+  });
+
+  it("should complement unsigned values within their type's bits", () => {
+    expect(evaluateConstExpr("^uint(0)")).toEqual({ value: "18446744073709551615", type: "uint" });
+    expect(evaluateConstExpr("^uint8(0)")).toEqual({ value: "255", type: "uint8" });
+    expect(evaluateConstExpr("^int8(0)")).toEqual({ value: "-1", type: "int8" });
+    expect(evaluateConstExpr("^0")).toEqual({ value: "-1", type: "untyped int" });
+  });
+
+  it("should keep integral float constants in scope floats", () => {
+    const scope = new Map<string, GoConstValue>([
+      ["X", evaluateConstExpr("2.0")!],
+      ["Half", convertConstValue(evaluateConstExpr("1")!, "float64")!],
+    ]);
+    expect(evaluateConstExpr("X / 4", scope)).toEqual({ value: "0.5", type: "untyped float" });
+    expect(evaluateConstExpr("Half / 2", scope)).toEqual({ value: "0.5", type: "float64" });
+  });
+
+  it("should reject typed constants that overflow their type", () => {
+    expect(evaluateConstExpr("int64(1) << 63")).toBeUndefined();
+    expect(evaluateConstExpr("uint8(256)")).toBeUndefined();
+    expect(evaluateConstExpr("-uint(1)")).toBeUndefined();
+    expect(evaluateConstExpr("1 << 63")?.value).toBe("9223372036854775808");
+  });
+
+  it("should reject negative and oversized shift counts", () => {
+    expect(evaluateConstExpr("1 << -1")).toBeUndefined();
+    expect(evaluateConstExpr("1 << 100000")).toBeUndefined();
+    expect(evaluateConstExpr("1 << 10")?.value).toBe("1024");
+  });
+
+  it("should resolve constants in scope", () => {
+    const scope = new Map<string, GoConstValue>([["Base", { value: "21", type: "Level" }]]);
+    expect(evaluateConstExpr("Base * 2", scope)).toEqual({ value: "42", type: "Level" });
+  });
+
+  it("should evaluate strings and booleans", () => {
+    expect(evaluateConstExpr('"lang" + `chain`')?.value).toBe('"langchain"');
+    expect(evaluateConstExpr('len("abc") > 2 && true')).toEqual({
+      value: "true",
+      type: "untyped bool",
+    });
+  });
+
+  it("should give up on non-constant expressions", () => {
+    expect(evaluateConstExpr("[]int{1, 2}")).toBeUndefined();
+    expect(evaluateConstExpr("unknownConst + 1")).toBeUndefined();
+    expect(evaluateConstExpr("1 +")).toBeUndefined();
+  });
+});
+
+describe("convertConstValue", () => {
+  it("should give untyped constants the declared type", () => {
+    expect(convertConstValue({ value: "5", type: "untyped int" }, "time.Duration")?.display).toBe(
+      "5ns",
+    );
+  });
+
+  it("should reject values that do not fit the type's kind", () => {
+    expect(convertConstValue({ value: "2.5", type: "untyped float" }, "int")).toBeUndefined();
+  });
+
+  it("should reject values that overflow the type", () => {
+    const shifted = evaluateConstExpr("1 << 63")!;
+    expect(convertConstValue(shifted, "int64")).toBeUndefined();
+    expect(convertConstValue(shifted, "uint64")).toEqual({
+      value: "9223372036854775808",
+      type: "uint64",
+    });
+  });
+});
+
+describe("formatDuration", () => {
+  it("should format like time.Duration.String", () => {
+    expect(formatDuration(0n)).toBe("0s");
+    expect(formatDuration(1500n)).toBe("1.5µs");
+    expect(formatDuration(2_000_000n)).toBe("2ms");
+    expect(formatDuration(3_723_000_000_000n)).toBe("1h2m3s");
+    expect(formatDuration(-1_500_000_000n)).toBe("-1.5s");
+  });
+});
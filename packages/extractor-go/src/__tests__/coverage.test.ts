@@ -0,0 +1,122 @@
+/**
+ * Documentation coverage tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer, type GoSymbolRecord } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput } from "../output.js";
+import { buildCoverage, formatCoverage } from "../coverage.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+function symbol(
+  qualifiedName: string,
+  kind: string,
+  file: string,
+  summary: string,
+  extra: Partial<GoSymbolRecord> = {},
+): GoSymbolRecord {
+  return {
+    qualifiedName,
+    kind,
+    docs: { summary },
+    source: { path: file, line: 1 },
+    tags: { stability: "stable", visibility: "public" },
+    ...extra,
+  } as GoSymbolRecord;
+}
+
+describe("buildCoverage", () => {
+  const symbols = [
+    symbol("Client", "class", "client.go", "Client talks to the API.", {
+      members: [
+        { name: "Timeout", kind: "property", visibility: "public", doc: "Timeout of calls." },
+        { name: "Retries", kind: "property", visibility: "public" },
+        { name: "conn", kind: "property", visibility: "private" },
+        { name: "Close", kind: "method", visibility: "public" },
+      ],
+    } as Partial<GoSymbolRecord>),
+    symbol("Client.Close", "method", "client.go", ""),
+    symbol("New", "function", "client.go", "New creates a Client."),
+    symbol("Sign", "function", "auth/sign.go", ""),
+    symbol("Version", "variable", "auth/sign.go", "Version of the signer."),
+    symbol("helper", "function", "client.go", "", {
+      tags: { stability: "stable", visibility: "private" },
+    }),
+    symbol("Fake", "function", "client_test.go", "", { testHelper: true }),
+  ];
+
+  it("should count documented exported API per package and kind", () => {
+    const coverage = buildCoverage(symbols);
+
+    expect(coverage.packages.map((pkg) => pkg.package)).toEqual([".", "auth"]);
+    expect(coverage.packages[0]).toMatchObject({
+      documented: 3,
+      total: 5,
+      percent: 60,
+      undocumented: ["Client.Close", "Client.Retries"],
+    });
+    expect(coverage.packages[0].kinds.fields).toEqual({ documented: 1, total: 2, percent: 50 });
+    expect(coverage.packages[1].kinds.values).toEqual({ documented: 1, total: 1, percent: 100 });
+  });
+
+  it("should total the packages", () => {
+    const coverage = buildCoverage(symbols);
+
+    expect(coverage).toMatchObject({ documented: 4, total: 7, percent: 57.1 });
+    expect(coverage.kinds.functions).toEqual({ documented: 1, total: 2, percent: 50 });
+    expect(coverage.kinds.types).toEqual({ documented: 1, total: 1, percent: 100 });
+  });
+
+  it("should report full coverage without any API", () => {
+    expect(buildCoverage([])).toEqual({
+      documented: 0,
+      total: 0,
+      percent: 100,
+      kinds: {
+        functions: { documented: 0, total: 0, percent: 100 },
+        types: { documented: 0, total: 0, percent: 100 },
+        methods: { documented: 0, total: 0, percent: 100 },
+        fields: { documented: 0, total: 0, percent: 100 },
+        values: { documented: 0, total: 0, percent: 100 },
+      },
+      packages: [],
+    });
+  });
+});
+
+describe("formatCoverage", () => {
+  it("should print a row per package and the total", () => {
+    const lines = formatCoverage(
+      buildCoverage([
+        symbol("New", "function", "client.go", "New creates a Client."),
+        symbol("Sign", "function", "auth/sign.go", ""),
+      ]),
+    ).split("\n");
+
+    expect(lines[0]).toMatch(/^Package\s+Functions\s+Types\s+Methods\s+Fields\s+Values\s+Total$/);
+    expect(lines[1]).toMatch(/^\.\s+1\/1 \(100%\)\s+-\s+-\s+-\s+-\s+1\/1 \(100%\)$/);
+    expect(lines[2]).toMatch(/^auth\s+0\/1 \(0%\)/);
+    expect(lines[3]).toMatch(/^Total\s+1\/2 \(50%\)/);
+  });
+});
+
+describe("coverage output", () => {
+  it("should count deprecated fields and symbols as documented", async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    const output = buildOutput(result, config, new GoTransformer(result, config).transform());
+
+    expect(output.coverage!.packages.map((pkg) => pkg.package)).toEqual(["."]);
+    expect(output.coverage!.packages[0].undocumented).not.toContain("RetryPolicy.Jitter");
+    expect(output.coverage!.percent).toBeGreaterThan(90);
+  });
+});
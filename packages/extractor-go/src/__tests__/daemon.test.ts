@@ -0,0 +1,207 @@
+/**
+ * Extraction daemon tests
+ */
+
+import { mkdtemp, rm, utimes, writeFile } from "node:fs/promises";
+import { createServer } from "node:net";
+import os from "node:os";
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll, afterAll } from "vitest";
+
+import { DaemonClient, ExtractionDaemon } from "../daemon.js";
+import { ParseCache } from "../parse-cache.js";
+import { GoExtractor, type ParsedFile } from "../extractor.js";
+import { createConfig } from "../config.js";
+import type { ExtractorOutput } from "../output.js";
+import type { DocChunk } from "../chunks.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("ParseCache", () => {
+  it("should return entries stored under the same key only", () => {
+    const cache = new ParseCache<string>();
+    cache.set("a.go", "v1", "parsed");
+
+    expect(cache.get("a.go", "v1")).toBe("parsed");
+    expect(cache.get("a.go", "v2")).toBeUndefined();
+    expect(cache.get("b.go", "v1")).toBeUndefined();
+    expect(cache.stats()).toEqual({ files: 1, hits: 1, misses: 2 });
+  });
+
+  it("should drop the entries under a directory", () => {
+    const cache = new ParseCache<string>();
+    cache.set(path.join("mod", "a.go"), "v1", "a");
+    cache.set(path.join("mod", "sub", "b.go"), "v1", "b");
+    cache.set(path.join("module", "c.go"), "v1", "c");
+    cache.deleteDir("mod");
+
+    expect(cache.get(path.join("mod", "a.go"), "v1")).toBeUndefined();
+    expect(cache.get(path.join("mod", "sub", "b.go"), "v1")).toBeUndefined();
+    expect(cache.get(path.join("module", "c.go"), "v1")).toBe("c");
+  });
+});
+
+describe("GoExtractor with a parse cache", () => {
+  let root: string;
+  let file: string;
+
+  beforeAll(async () => {
+    root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-cache-"));
+    file = path.join(root, "config.go");
+    await writeFile(
+      file,
+      `package config
+
+// Config holds settings.
+type Config struct {
+	// Port is the listen port.
+	Port int
+}
+
+// Default returns the default settings.
+func Default() *Config {
+	return &Config{Port: 8080}
+}
+`,
+    );
+  });
+
+  afterAll(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it("should reuse parsed files without accumulating resolution results", async () => {
+    const cache = new ParseCache<ParsedFile>();
+    const config = createConfig({ packageName: "config", packagePath: root });
+    await new GoExtractor(config, undefined, cache).extract();
+    const result = await new GoExtractor(config, undefined, cache).extract();
+
+    expect(cache.stats()).toEqual({ files: 1, hits: 1, misses: 1 });
+    expect(result.types[0].fields[0].defaults).toEqual([
+      { value: "8080", constructor: "Default" },
+    ]);
+  });
+
+  it("should re-parse files that changed", async () => {
+    const cache = new ParseCache<ParsedFile>();
+    const config = createConfig({ packageName: "config", packagePath: root });
+    await new GoExtractor(config, undefined, cache).extract();
+    await utimes(file, new Date(), new Date(Date.now() + 1000));
+    await new GoExtractor(config, undefined, cache).extract();
+
+    expect(cache.stats().hits).toBe(0);
+  });
+
+  it("should re-parse files when the note markers change", async () => {
+    const cache = new ParseCache<ParsedFile>();
+    const config = createConfig({ packageName: "config", packagePath: root });
+    await new GoExtractor(config, undefined, cache).extract();
+    await new GoExtractor({ ...config, noteMarkers: ["BUG", "TODO"] }, undefined, cache).extract();
+
+    expect(cache.stats().hits).toBe(0);
+  });
+});
+
+describe("ExtractionDaemon", () => {
+  let root: string;
+  let socket: string;
+  let daemon: ExtractionDaemon;
+  let client: DaemonClient;
+
+  beforeAll(async () => {
+    root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-daemon-"));
+    socket = path.join(root, "daemon.sock");
+    daemon = new ExtractionDaemon();
+    await daemon.listen(socket);
+    client = new DaemonClient(socket);
+  });
+
+  afterAll(async () => {
+    client.close();
+    await daemon.close();
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it("should serve extractions over the socket", async () => {
+    const output = (await client.extract({
+      config: { packageName: "example", packagePath: fixturesPath },
+    })) as ExtractorOutput;
+
+    expect(output.package.packageId).toBe("pkg_go_example");
+    expect(output.symbols.map((s) => s.name)).toContain("Client");
+  });
+
+  it("should keep parsed files warm across requests", async () => {
+    await client.extract({
+      config: { packageName: "example", packagePath: fixturesPath },
+      profile: "summary",
+    });
+    const stats = await client.stats();
+
+    expect(stats.requests).toBe(3);
+    expect(stats.cache.hits).toBe(stats.cache.files);
+  });
+
+  it("should report request errors without stopping", async () => {
+    await expect(
+      client.extract({ config: { packageName: "", packagePath: fixturesPath } }),
+    ).rejects.toThrow("packageName is required");
+    expect((await client.stats()).requests).toBe(5);
+  });
+
+  it("should stream chunks within the token budget", async () => {
+    const chunks: DocChunk[] = [];
+    const summary = await client.chunks(
+      {
+        config: { packageName: "example", packagePath: fixturesPath },
+        symbol: "Client",
+        budget: 60,
+      },
+      (chunk) => chunks.push(chunk),
+    );
+
+    expect(chunks.length).toBeGreaterThan(0);
+    expect(chunks[0]).toMatchObject({ qualifiedName: "Client", kind: "signature" });
+    expect(chunks.every((c) => c.qualifiedName.startsWith("Client"))).toBe(true);
+    expect(summary).toEqual({
+      chunks: chunks.length,
+      tokens: chunks.reduce((sum, c) => sum + c.tokens, 0),
+    });
+    expect(summary.tokens).toBeLessThanOrEqual(60);
+  });
+
+  it("should reject unknown chunk kinds", async () => {
+    await expect(
+      client.chunks(
+        {
+          config: { packageName: "example", packagePath: fixturesPath },
+          budget: 100,
+          priority: ["examples" as "example"],
+        },
+        () => {},
+      ),
+    ).rejects.toThrow("Unknown chunk kind: examples");
+  });
+});
+
+describe("DaemonClient", () => {
+  it("should fail requests in flight when the daemon dies", async () => {
+    const root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-daemon-client-"));
+    const socket = path.join(root, "daemon.sock");
+    // A daemon that dies on the first request it reads
+    const server = createServer((connection) => connection.on("data", () => connection.destroy()));
+    await new Promise<void>((resolve) => server.listen(socket, resolve));
+    const client = new DaemonClient(socket);
+
+    await expect(client.stats()).rejects.toThrow("The daemon closed the connection");
+    // The next request connects again
+    await expect(client.stats()).rejects.toThrow("The daemon closed the connection");
+    client.close();
+    await new Promise((resolve) => server.close(resolve));
+    await rm(root, { recursive: true, force: true });
+  });
+});
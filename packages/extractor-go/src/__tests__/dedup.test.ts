@@ -0,0 +1,136 @@
+/**
+ * Package deduplication tests
+ */
+
+import { mkdir, mkdtemp, rm, writeFile } from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect, beforeAll, afterAll } from "vitest";
+
+import { findDuplicatePackages, hashPackageFiles } from "../dedup.js";
+import { GoExtractor, type ExtractionResult } from "../extractor.js";
+import { GoTransformer, type GoSymbolRecord } from "../transformer.js";
+import { createConfig, type VendorPolicy } from "../config.js";
+import { buildOutput } from "../output.js";
+
+const CLIENT_SOURCE = `// Package client talks to the API.
+package client
+
+// Client is an API client.
+type Client struct {
+	// Addr is the server address.
+	Addr string
+}
+`;
+
+describe("hashPackageFiles", () => {
+  it("should not depend on file order", () => {
+    const a = { name: "a.go", content: "package x" };
+    const b = { name: "b.go", content: "package x\n\nconst B = 1" };
+    expect(hashPackageFiles([a, b])).toBe(hashPackageFiles([b, a]));
+  });
+
+  it("should change when a file name or content changes", () => {
+    const base = hashPackageFiles([{ name: "a.go", content: "package x" }]);
+    expect(hashPackageFiles([{ name: "b.go", content: "package x" }])).not.toBe(base);
+    expect(hashPackageFiles([{ name: "a.go", content: "package y" }])).not.toBe(base);
+  });
+});
+
+describe("findDuplicatePackages", () => {
+  it("should pick the shallowest directory as canonical", () => {
+    const duplicates = findDuplicatePackages([
+      { dir: "third_party/client", files: [], contentHash: "h1" },
+      { dir: "client", files: [], contentHash: "h1" },
+      { dir: "internal/fork/client", files: [], contentHash: "h1" },
+      { dir: "server", files: [], contentHash: "h2" },
+    ]);
+
+    expect(duplicates).toEqual([
+      {
+        canonical: "client",
+        aliases: ["third_party/client", "internal/fork/client"],
+        contentHash: "h1",
+      },
+    ]);
+  });
+
+  it("should return nothing when all packages differ", () => {
+    expect(
+      findDuplicatePackages([
+        { dir: "", files: [], contentHash: "h1" },
+        { dir: "a", files: [], contentHash: "h2" },
+      ]),
+    ).toEqual([]);
+  });
+});
+
+describe("GoExtractor package deduplication", () => {
+  let root: string;
+  let result: ExtractionResult;
+  let symbols: GoSymbolRecord[];
+
+  beforeAll(async () => {
+    root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-dedup-"));
+    for (const dir of ["client", "third_party/client"]) {
+      await mkdir(path.join(root, dir), { recursive: true });
+      await writeFile(path.join(root, dir, "client.go"), CLIENT_SOURCE);
+    }
+
+    const config = createConfig({ packageName: "dedup", packagePath: root, dedupePackages: true });
+    result = await new GoExtractor(config).extract();
+    symbols = new GoTransformer(result, config).transform();
+  });
+
+  afterAll(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it("should extract identical packages only once", () => {
+    expect(result.types.filter((t) => t.name === "Client")).toHaveLength(1);
+    expect(result.types[0].sourceFile).toBe("client/client.go");
+  });
+
+  it("should record the skipped copies", () => {
+    expect(result.duplicates).toEqual([
+      expect.objectContaining({ canonical: "client", aliases: ["third_party/client"] }),
+    ]);
+  });
+
+  it("should list the copies as aliases of the canonical symbols", () => {
+    const client = symbols.find((s) => s.name === "Client");
+    expect(client!.aliases).toEqual(["third_party/client/client.go"]);
+  });
+
+  it("should include the duplicates in the package header", () => {
+    const config = createConfig({ packageName: "dedup", packagePath: root, dedupePackages: true });
+    const output = buildOutput(result, config, symbols);
+    expect(output.package.duplicates).toHaveLength(1);
+  });
+
+  it("should keep every copy by default", async () => {
+    const config = createConfig({ packageName: "dedup", packagePath: root });
+    const all = await new GoExtractor(config).extract();
+    expect(all.types.filter((t) => t.name === "Client")).toHaveLength(2);
+    expect(all.duplicates).toBeUndefined();
+  });
+
+  it("should only see vendored copies when vendored packages are extracted", async () => {
+    const dir = await mkdtemp(path.join(os.tmpdir(), "extractor-go-dedup-vendor-"));
+    for (const sub of ["client", "vendor/example.com/client"]) {
+      await mkdir(path.join(dir, sub), { recursive: true });
+      await writeFile(path.join(dir, sub, "client.go"), CLIENT_SOURCE);
+    }
+    const extract = (vendoredPackages?: VendorPolicy) => {
+      const options = { packageName: "dedup", packagePath: dir, dedupePackages: true };
+      return new GoExtractor(createConfig({ ...options, vendoredPackages })).extract();
+    };
+
+    expect((await extract()).duplicates).toBeUndefined();
+    expect((await extract("dependencies")).duplicates).toEqual([
+      expect.objectContaining({ canonical: "client", aliases: ["vendor/example.com/client"] }),
+    ]);
+    await rm(dir, { recursive: true, force: true });
+  });
+});
@@ -0,0 +1,259 @@
+/**
+ * Doc comment parsing tests
+ */
+
+import { describe, it, expect } from "vitest";
+
+import {
+  docToMarkdown,
+  markCallouts,
+  parseDocComment,
+  parseDocText,
+  resolveDocLinks,
+  seeAlsoTargets,
+  splitDocTags,
+} from "../doc-comment.js";
+
+describe("parseDocComment", () => {
+  it("should split paragraphs and keep code blocks verbatim", () => {
+    const blocks = parseDocComment(
+      [
+        "Connect dials the server.",
+        "It retries on failure.",
+        "",
+        "\tc, err := Connect(addr)",
+        "",
+        "\tif err != nil {",
+        "\t    return err",
+        "\t}",
+      ].join("\n"),
+    );
+
+    expect(blocks).toEqual([
+      {
+        kind: "paragraph",
+        text: [{ kind: "plain", text: "Connect dials the server. It retries on failure." }],
+      },
+      {
+        kind: "code",
+        language: "go",
+        text: "c, err := Connect(addr)\n\nif err != nil {\n    return err\n}",
+      },
+    ]);
+  });
+
+  it("should parse # headings and old-style headings", () => {
+    const blocks = parseDocComment(
+      "Intro.\n\n# Usage\n\nCall it.\n\nConcurrent use\n\nIt is safe.",
+    );
+
+    expect(blocks.map((b) => b.kind)).toEqual([
+      "paragraph",
+      "heading",
+      "paragraph",
+      "heading",
+      "paragraph",
+    ]);
+    expect(blocks[1]).toEqual({ kind: "heading", text: [{ kind: "plain", text: "Usage" }] });
+  });
+
+  it("should not treat sentences as headings", () => {
+    const kinds = parseDocComment("Intro.\n\nExample:\n\nMore.").map((b) => b.kind);
+    expect(kinds).toEqual(["paragraph", "paragraph", "paragraph"]);
+  });
+
+  it("should parse bullet and numbered lists", () => {
+    const [bullets, numbered] = parseDocComment(
+      "  - first item\n    continued\n  - second\n\nThen:\n\n  1. one\n  2. two",
+    ).filter((b) => b.kind === "list");
+
+    expect(bullets).toEqual({
+      kind: "list",
+      ordered: false,
+      items: [
+        { text: [{ kind: "plain", text: "first item continued" }] },
+        { text: [{ kind: "plain", text: "second" }] },
+      ],
+    });
+    expect(numbered).toMatchObject({ ordered: true, items: [{ number: "1" }, { number: "2" }] });
+  });
+
+  it("should resolve link definitions and drop them from the blocks", () => {
+    const blocks = parseDocComment("See the [API docs].\n\n[API docs]: https://example.com/api");
+
+    expect(blocks).toEqual([
+      {
+        kind: "paragraph",
+        text: [
+          { kind: "plain", text: "See the " },
+          { kind: "link", text: "API docs", url: "https://example.com/api" },
+          { kind: "plain", text: "." },
+        ],
+      },
+    ]);
+  });
+});
+
+describe("verbatim blocks", () => {
+  it("should keep diagrams and aligned tables verbatim", () => {
+    const diagram = "idle --Start--> running\n^                  |\n+-------Stop-------+";
+    const table = "Option   Default  Meaning\nRetries  3        attempts\nTimeout  30s      a call";
+
+    expect(parseDocComment(`States:\n\n${diagram}\n\n${table}`)).toEqual([
+      { kind: "paragraph", text: [{ kind: "plain", text: "States:" }] },
+      { kind: "verbatim", text: diagram },
+      { kind: "verbatim", text: table },
+    ]);
+  });
+
+  it("should reflow prose with stray double spaces or arrows", () => {
+    const [block] = parseDocComment("Start ->  then run.  The\nend  of it.");
+    expect(block.kind).toBe("paragraph");
+  });
+
+  it("should fence diagrams and tables in Markdown", () => {
+    expect(docToMarkdown("Codes:\n\ncode  name\n200   OK")).toBe(
+      "Codes:\n\n```text\ncode  name\n200   OK\n```",
+    );
+  });
+});
+
+describe("parseDocText", () => {
+  it("should find doc links and URLs", () => {
+    expect(parseDocText("Use [Client] or [*io.Reader], see https://go.dev.")).toEqual([
+      { kind: "plain", text: "Use " },
+      { kind: "docLink", text: "Client", target: "Client" },
+      { kind: "plain", text: " or " },
+      { kind: "docLink", text: "*io.Reader", target: "io.Reader" },
+      { kind: "plain", text: ", see " },
+      { kind: "link", text: "https://go.dev", url: "https://go.dev" },
+      { kind: "plain", text: "." },
+    ]);
+  });
+
+  it("should leave other bracketed text alone", () => {
+    expect(parseDocText("a [1, 2] slice")).toEqual([{ kind: "plain", text: "a [1, 2] slice" }]);
+  });
+});
+
+describe("resolveDocLinks", () => {
+  it("should attach targets and turn unresolved links into plain text", () => {
+    const parsed = parseDocComment("Use [Client], not [Gone].\n\n  - [Client]");
+    const blocks = resolveDocLinks(parsed, (target) =>
+      target === "Client" ? { refId: "pkg:Client" } : undefined,
+    );
+
+    expect(blocks).toEqual([
+      {
+        kind: "paragraph",
+        text: [
+          { kind: "plain", text: "Use " },
+          { kind: "docLink", text: "Client", target: "Client", refId: "pkg:Client" },
+          { kind: "plain", text: ", not [Gone]." },
+        ],
+      },
+      {
+        kind: "list",
+        ordered: false,
+        items: [
+          { text: [{ kind: "docLink", text: "Client", target: "Client", refId: "pkg:Client" }] },
+        ],
+      },
+    ]);
+  });
+});
+
+describe("splitDocTags", () => {
+  it("should split tag lines into annotations", () => {
+    const doc = "Loader loads files.\n\n@since v1.2.0\n@beta\n\nMore prose.";
+
+    expect(splitDocTags(doc, ["since", "beta"])).toEqual({
+      prose: "Loader loads files.\n\nMore prose.",
+      annotations: { since: "v1.2.0", beta: true },
+    });
+  });
+
+  it("should ignore unknown tags, indented lines, and @ inside prose", () => {
+    const doc = "Use @since in prose.\n@param x\n\n\t@since v1";
+
+    expect(splitDocTags(doc, ["since"])).toEqual({ prose: doc });
+    expect(splitDocTags("@sinceforever", ["since"])).toEqual({ prose: "@sinceforever" });
+  });
+});
+
+describe("seeAlsoTargets", () => {
+  it("should collect the doc links of See also sentences", () => {
+    const blocks = parseDocComment(
+      "Get fetches a [Document]. See also [Client.Put],\n[io.Reader], and [Client.Put].\n\n" +
+        "- see also: [Options]\n\n" +
+        "Unrelated [Store]. See also the [guide].\n\n[guide]: https://example.com",
+    );
+
+    expect(seeAlsoTargets(blocks)).toEqual(["Client.Put", "io.Reader", "Options"]);
+  });
+
+  it("should end the sentence at its closing punctuation", () => {
+    const blocks = parseDocComment("See also [A]! Then [B]. Also see [C].");
+
+    expect(seeAlsoTargets(blocks)).toEqual(["A"]);
+  });
+});
+
+describe("markCallouts", () => {
+  it("should turn labeled paragraphs into callouts", () => {
+    const blocks = markCallouts(
+      parseDocComment(
+        "Open opens a store.\n\nSecurity: Keys are read from\n[Env].\n\nConcurrency:",
+      ),
+    );
+
+    expect(blocks).toEqual([
+      { kind: "paragraph", text: [{ kind: "plain", text: "Open opens a store." }] },
+      {
+        kind: "callout",
+        callout: "security",
+        text: [
+          { kind: "plain", text: "Keys are read from " },
+          { kind: "docLink", text: "Env", target: "Env" },
+          { kind: "plain", text: "." },
+        ],
+      },
+      { kind: "callout", callout: "concurrency", text: [] },
+    ]);
+  });
+
+  it("should move sentences on thread safety into a callout", () => {
+    const blocks = markCallouts(
+      parseDocComment(
+        "A Cache is not safe for concurrent use. It holds [Entry] values.\n" +
+          "Implementations must be thread-safe!",
+      ),
+    );
+
+    expect(blocks).toEqual([
+      {
+        kind: "paragraph",
+        text: [
+          { kind: "plain", text: "It holds " },
+          { kind: "docLink", text: "Entry", target: "Entry" },
+          { kind: "plain", text: " values." },
+        ],
+      },
+      {
+        kind: "callout",
+        callout: "concurrency",
+        text: [
+          {
+            kind: "plain",
+            text: "A Cache is not safe for concurrent use. Implementations must be thread-safe!",
+          },
+        ],
+      },
+    ]);
+  });
+
+  it("should leave other blocks alone", () => {
+    const blocks = parseDocComment("Safety:\n\n\tmu.Lock() // thread-safe\n\n - thread-safe");
+    expect(markCallouts(blocks)).toEqual(blocks);
+  });
+});
@@ -0,0 +1,86 @@
+/**
+ * Docusaurus integration tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput, type ExtractorOutput } from "../output.js";
+import { mdxPageSymbols, renderMdx } from "../mdx.js";
+import { buildDocusaurusSidebar, type DocusaurusSidebarItem } from "../docusaurus.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("Docusaurus integration", () => {
+  let output: ExtractorOutput;
+  let pages: Map<string, string>;
+
+  beforeAll(async () => {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      repo: "example/testpkg",
+      sha: "abc123",
+    });
+    const result = await new GoExtractor(config).extract();
+    output = buildOutput(result, config, new GoTransformer(result, config).transform());
+    pages = renderMdx(output, { docusaurus: "reference/go" });
+  });
+
+  it("should add Docusaurus front matter to the package page", () => {
+    const index = pages.get("index.mdx")!;
+
+    expect(index).toMatch(/^---\ntitle: "test-package"\nslug: "\/reference\/go"\n/);
+    expect(index).toContain('\nsidebar_label: "test-package"\n');
+    expect(index).not.toContain("custom_edit_url");
+  });
+
+  it("should link symbol pages to their Go source", () => {
+    const page = pages.get("Client.mdx")!;
+
+    expect(page).toContain('\nslug: "/reference/go/Client"\n');
+    expect(page).toContain('\nsidebar_label: "Client"\n');
+    expect(page).toContain(
+      '\ncustom_edit_url: "https://github.com/example/testpkg/blob/abc123/types.go#L11"\n',
+    );
+  });
+
+  it("should leave the front matter alone without a docs path", () => {
+    const page = renderMdx(output).get("Client.mdx")!;
+
+    expect(page).toContain('\nslug: "/Client"\n');
+    expect(page).not.toContain("sidebar_label");
+  });
+
+  it("should list the pages in a sidebar category per package", () => {
+    const [category] = buildDocusaurusSidebar(output, mdxPageSymbols(output), "reference/go");
+
+    expect(category).toMatchObject({
+      type: "category",
+      label: "test-package",
+      link: { type: "doc", id: "reference/go/index" },
+    });
+    const groups = (category as { items: Array<{ label: string; items: string[] }> }).items;
+    expect(groups.map((group) => group.label)).toEqual(["Types", "Functions"]);
+    expect(groups[0].items).toContain("reference/go/Client");
+    expect(groups[1].items).toContain("reference/go/NewClient");
+    expect(groups.flatMap((group) => group.items)).toHaveLength(pages.size - 1);
+  });
+
+  it("should use the package page's doc ID at the docs root", () => {
+    const sidebar: DocusaurusSidebarItem[] = buildDocusaurusSidebar(
+      output,
+      mdxPageSymbols(output),
+      "",
+    );
+
+    expect(sidebar[0]).toMatchObject({ link: { id: "index" } });
+  });
+});
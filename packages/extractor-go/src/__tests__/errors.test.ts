@@ -0,0 +1,84 @@
+/**
+ * Extraction error tests
+ */
+
+import fs from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { afterEach, describe, it, expect, vi } from "vitest";
+
+import { formatExtractionError, sortExtractionErrors, type ExtractionError } from "../errors.js";
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { buildOutput } from "../output.js";
+import { validateOutput } from "../schema.js";
+import { createConfig } from "../config.js";
+
+async function tree(files: Record<string, string>): Promise<string> {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), "errors-"));
+  for (const [file, content] of Object.entries(files)) {
+    await fs.mkdir(path.dirname(path.join(dir, file)), { recursive: true });
+    await fs.writeFile(path.join(dir, file), content);
+  }
+  return dir;
+}
+
+describe("extraction errors", () => {
+  afterEach(() => {
+    vi.restoreAllMocks();
+  });
+
+  it("should sort by package, then file", () => {
+    const errors: ExtractionError[] = [
+      { kind: "import", package: "b", file: "b/b.go", message: "x" },
+      { kind: "parse", package: "", file: "z.go", message: "x" },
+      { kind: "package", package: "b", file: "b/a.go", message: "x" },
+    ];
+    expect(sortExtractionErrors(errors).map((error) => error.file)).toEqual([
+      "z.go",
+      "b/a.go",
+      "b/b.go",
+    ]);
+  });
+
+  it("should format the package, kind, and file", () => {
+    expect(
+      formatExtractionError({ kind: "parse", package: "", file: "a.go", message: "bad" }),
+    ).toBe(". (parse): a.go: bad");
+  });
+
+  it("should report mixed package clauses and missing module imports", async () => {
+    const error = vi.spyOn(console, "error").mockImplementation(() => {});
+    const root = await tree({
+      "go.mod": "module example.com/app\n",
+      "app.go": 'package app\n\nimport "example.com/app/store"\n',
+      "store/store.go": "package store\n",
+      "store/other.go": "package other\n",
+      "store/gen.go": "//go:build ignore\n\npackage main\n",
+      "client/client.go": 'package client\n\nimport (\n\t"fmt"\n\t"example.com/app/gone"\n)\n',
+    });
+    const config = createConfig({ packageName: "app", packagePath: root });
+    const result = await new GoExtractor(config).extract();
+
+    expect(result.errors).toEqual([
+      {
+        kind: "import",
+        package: "client",
+        file: "client/client.go",
+        message: "imports example.com/app/gone, which doesn't exist",
+      },
+      {
+        kind: "package",
+        package: "store",
+        file: "store/store.go",
+        message: "declares package store, not other",
+      },
+    ]);
+    expect(error).toHaveBeenCalledTimes(2);
+
+    const output = buildOutput(result, config, new GoTransformer(result, config).transform());
+    expect(output.package.errors).toEqual(result.errors);
+    expect(validateOutput(output)).toEqual([]);
+  });
+});
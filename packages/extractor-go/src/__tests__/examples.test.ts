@@ -0,0 +1,109 @@
+/**
+ * Whole-file and package example tests
+ */
+
+import { mkdtemp, rm, writeFile } from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect, beforeAll, afterAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer, type GoSymbolRecord } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput, type ExtractorOutput } from "../output.js";
+import { summarizeOutput } from "../profile.js";
+
+const files: Record<string, string> = {
+  "sorting.go": `// Package sorting sorts things.
+package sorting
+
+// Sort sorts values in place.
+func Sort(values []int) {}
+`,
+  "sort_example_test.go": `package sorting_test
+
+import (
+	"fmt"
+
+	"github.com/example/sorting"
+)
+
+type byLength []string
+
+func (s byLength) Len() int { return len(s) }
+
+func ExampleSort() {
+	values := []int{3, 1, 2}
+	sorting.Sort(values)
+	fmt.Println(values, byLength{}.Len())
+	// Output: [1 2 3] 0
+}
+`,
+  "package_example_test.go": `package sorting_test
+
+import "fmt"
+
+// The package sorts in place.
+func Example() {
+	fmt.Println("sorted")
+	// Output: sorted
+}
+
+func Example_reverse() {}
+
+func TestNothing(t *testing.T) {}
+`,
+};
+
+describe("whole-file and package examples", () => {
+  let root: string;
+  let output: ExtractorOutput;
+
+  beforeAll(async () => {
+    root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-examples-"));
+    for (const [name, content] of Object.entries(files)) {
+      await writeFile(path.join(root, name), content);
+    }
+
+    const config = createConfig({ packageName: "sorting", packagePath: root });
+    const result = await new GoExtractor(config).extract();
+    output = buildOutput(result, config, new GoTransformer(result, config).transform());
+  });
+
+  afterAll(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it("should use the whole file for an example that needs its helpers", () => {
+    const sort = output.symbols.find((s) => s.name === "Sort") as GoSymbolRecord;
+    const [example] = sort.docs.examples!;
+
+    expect(example.wholeFile).toBe(true);
+    expect(example.output).toBe("[1 2 3] 0");
+    expect(example.code).toMatch(/^package sorting_test\n\nimport \(/);
+    expect(example.code).toContain("func (s byLength) Len() int { return len(s) }");
+    expect(example.code).toMatch(/fmt\.Println\(values, byLength\{\}\.Len\(\)\)\n\}$/);
+    expect(example.code).not.toContain("Output:");
+  });
+
+  it("should attach package examples to the package", () => {
+    expect(output.package.examples).toEqual([
+      expect.objectContaining({
+        name: "Example",
+        description: "The package sorts in place.",
+        code: 'fmt.Println("sorted")',
+        output: "sorted",
+      }),
+      expect.objectContaining({ name: "Example_reverse", title: "Reverse", code: "" }),
+    ]);
+  });
+
+  it("should not use the whole file when it has tests", () => {
+    expect(output.package.examples!.some((e) => e.wholeFile)).toBe(false);
+  });
+
+  it("should leave package examples out of the summary profile", () => {
+    expect(summarizeOutput(output).package.examples).toBeUndefined();
+  });
+});
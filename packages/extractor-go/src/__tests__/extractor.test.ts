@@ -2,10 +2,12 @@
  * GoExtractor tests
  */
 
+import { mkdir, mkdtemp, rm, writeFile } from "node:fs/promises";
+import os from "node:os";
 import path from "node:path";
 import url from "node:url";
 
-import { describe, it, expect, beforeAll } from "vitest";
+import { describe, it, expect, beforeAll, afterAll } from "vitest";
 
 import { GoExtractor, type ExtractionResult } from "../extractor.js";
 import { createConfig } from "../config.js";
@@ -54,6 +56,15 @@ describe("GoExtractor", () => {
     it("should detect version or return default", () => {
       expect(typeof result.version).toBe("string");
     });
+
+    it("should extract the package doc comment", () => {
+      expect(result.packageDoc).toMatch(/^Package example provides/);
+    });
+
+    it("should use the doc.go package comment as the overview", () => {
+      expect(result.packageDoc).toMatch(/^Package example provides example Go APIs/);
+      expect(result.packageDoc).toContain("doc\ncomment conventions");
+    });
   });
 
   describe("struct extraction", () => {
@@ -203,6 +214,239 @@ describe("GoExtractor", () => {
       expect(logger).toBeDefined();
       expect(logger!.kind).toBe("interface");
     });
+
+    it("should record interface method names", () => {
+      const message = result.types.find((t) => t.name === "Message");
+      expect(message!.interfaceMethods).toEqual(["Text", "isMessage"]);
+    });
+  });
+
+  describe("sealed interfaces", () => {
+    it("should mark interfaces with unexported methods as sealed", () => {
+      const message = result.types.find((t) => t.name === "Message");
+      expect(message!.sealed).toBe(true);
+    });
+
+    it("should list in-package implementations, exported or not", () => {
+      const message = result.types.find((t) => t.name === "Message");
+      expect(message!.implementations).toEqual(["AIMessage", "systemMessage"]);
+    });
+
+    it("should seal interfaces embedding a sealed interface", () => {
+      const toolMessage = result.types.find((t) => t.name === "ToolMessage");
+      expect(toolMessage!.sealed).toBe(true);
+      expect(toolMessage!.implementations).toEqual([]);
+    });
+
+    it("should not seal interfaces with only exported methods", () => {
+      const storage = result.types.find((t) => t.name === "Storage");
+      expect(storage!.sealed).toBeUndefined();
+    });
+
+    it("should match method sets and embeds within the interface's package", async () => {
+      const root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-sealed-"));
+      await mkdir(path.join(root, "events"));
+      await mkdir(path.join(root, "other"));
+      await writeFile(path.join(root, "go.mod"), "module example.com/m\n");
+      await writeFile(
+        path.join(root, "events", "events.go"),
+        [
+          "package events",
+          "",
+          "type Base interface{ Kind() string }",
+          "",
+          "type Event interface {",
+          "\tBase",
+          "\tevent()",
+          "}",
+          "",
+          "type Plugin interface{ Base }",
+          "",
+          "type Click struct{}",
+          "",
+          'func (Click) Kind() string { return "click" }',
+          "",
+          "func (Click) event() {}",
+          "",
+        ].join("\n"),
+      );
+      await writeFile(
+        path.join(root, "other", "other.go"),
+        [
+          "package other",
+          "",
+          "type Base interface{ seal() }",
+          "",
+          "type Click struct{}",
+          "",
+          "func (Click) seal() {}",
+          "",
+          "type Tap struct{}",
+          "",
+          'func (Tap) Kind() string { return "tap" }',
+          "",
+          "func (Tap) event() {}",
+          "",
+        ].join("\n"),
+      );
+      const extracted = await new GoExtractor(
+        createConfig({ packageName: "m", packagePath: root }),
+      ).extract();
+      await rm(root, { recursive: true, force: true });
+
+      const type = (dir: string, name: string) =>
+        extracted.types.find((t) => t.name === name && t.sourceFile.startsWith(dir))!;
+      expect(type("events", "Event").implementations).toEqual(["Click"]);
+      expect(type("events", "Plugin").sealed).toBeUndefined();
+      expect(type("other", "Base").sealed).toBe(true);
+      expect(type("other", "Base").implementations).toEqual(["Click"]);
+    });
+  });
+
+  describe("grouped declarations", () => {
+    const constant = (name: string) => result.constants.find((c) => c.name === name)!;
+
+    it("should extract the members of const and var blocks", () => {
+      expect(constant("DefaultPort")).toMatchObject({ kind: "const", value: "8080" });
+      expect(constant("DefaultHost")).toMatchObject({ kind: "const", value: '"localhost"' });
+      expect(constant("ErrClosed")).toMatchObject({ kind: "var", value: 'errors.New("closed")' });
+      expect(constant("Verbose")).toMatchObject({ kind: "var", type: "bool" });
+      expect(constant("Verbose").value).toBeUndefined();
+    });
+
+    it("should repeat the type and expression of the constant above with iota", () => {
+      expect(constant("LevelInfo")).toMatchObject({ type: "Level", value: "iota", iota: 1 });
+      expect(constant("LevelWarn").evaluated).toEqual({ value: "2", type: "Level" });
+    });
+
+    it("should document members with leading or trailing comments", () => {
+      expect(constant("LevelDebug").doc).toBe("LevelDebug logs everything.");
+      expect(constant("LevelInfo").doc).toBe("informational messages");
+      expect(constant("DefaultPort").doc).toBe("default server port");
+      expect(constant("Verbose").doc).toBe("enables debug output");
+    });
+
+    it("should not take directives for docs", () => {
+      expect(constant("LevelWarn").doc).toBeUndefined();
+    });
+  });
+
+  describe("trailing field comments", () => {
+    it("should document fields with their trailing comment", () => {
+      const options = result.types.find((t) => t.name === "ServerOptions")!;
+      const doc = (name: string) => options.fields.find((f) => f.name === name)!.doc;
+
+      expect(doc("Port")).toBe("server port");
+      expect(doc("TLS")).toBe("serve HTTPS");
+    });
+
+    it("should prefer the doc comment above the field", () => {
+      const options = result.types.find((t) => t.name === "ServerOptions")!;
+      expect(options.fields.find((f) => f.name === "Host")!.doc).toBe(
+        "Host is the interface to bind.",
+      );
+    });
+  });
+
+  describe("notes", () => {
+    it("should collect BUG notes with their continuation lines", () => {
+      expect(result.notes).toEqual([
+        {
+          marker: "BUG",
+          uid: "cbromann",
+          body: "Ping ignores the context deadline\nand waits for the full network timeout.",
+          sourceFile: "functions.go",
+          line: 46,
+        },
+      ]);
+    });
+
+    it("should remove notes from doc comments", () => {
+      const ping = result.functions.find((f) => f.name === "Ping")!;
+      expect(ping.doc).toBe("Ping checks if the service is available.");
+    });
+
+    it("should collect the configured markers from any comment", async () => {
+      const config = createConfig({
+        packageName: "test-package",
+        packagePath: fixturesPath,
+        noteMarkers: ["BUG", "TODO"],
+      });
+      const { notes } = await new GoExtractor(config).extract();
+
+      expect(notes!.map((n) => `${n.marker}(${n.uid})`)).toEqual(["TODO(alice)", "BUG(cbromann)"]);
+      expect(notes![0].body).toBe("Support streaming responses.");
+    });
+  });
+
+  describe("examples", () => {
+    const example = (name: string) => result.examples!.find((e) => e.name === name)!;
+
+    it("should extract Example functions from test files", () => {
+      expect(result.examples!.map((e) => e.name)).toEqual([
+        "ExampleConnect",
+        "ExampleConnect_retry",
+        "ExampleClient_Get",
+      ]);
+    });
+
+    it("should derive the documented symbol and suffix from the name", () => {
+      expect(example("ExampleConnect")).toMatchObject({ target: "Connect" });
+      expect(example("ExampleConnect_retry")).toMatchObject({ target: "Connect", suffix: "retry" });
+      expect(example("ExampleClient_Get")).toMatchObject({ target: "Client.Get" });
+      expect(example("ExampleClient_Get").suffix).toBeUndefined();
+    });
+
+    it("should split the expected output off the code", () => {
+      const connect = example("ExampleConnect");
+      expect(connect.output).toBe("true");
+      expect(connect.unordered).toBeUndefined();
+      expect(connect.code).toMatch(/^client, err := example\.Connect\(/);
+      expect(connect.code).toContain("if err != nil {\n\tpanic(err)\n}");
+      expect(connect.code).not.toContain("Output");
+
+      expect(example("ExampleClient_Get")).toMatchObject({ output: "/b\n/a", unordered: true });
+      expect(example("ExampleConnect_retry").output).toBeUndefined();
+    });
+
+    it("should keep test files out of the declarations", () => {
+      expect(result.functions.find((f) => f.name.startsWith("Example"))).toBeUndefined();
+      expect(result.functions.find((f) => f.name === "SetRetryDelay")).toBeUndefined();
+    });
+
+    it("should skip examples when disabled", async () => {
+      const config = createConfig({
+        packageName: "test-package",
+        packagePath: fixturesPath,
+        examples: false,
+      });
+      expect((await new GoExtractor(config).extract()).examples).toBeUndefined();
+    });
+  });
+
+  describe("imports", () => {
+    it("should record the imports of each file by package name", () => {
+      expect(result.imports!["assertions.go"]).toEqual({ io: "io" });
+      expect(result.imports!["functions.go"]).toHaveProperty("context", "context");
+    });
+  });
+
+  describe("interface assertions", () => {
+    it("should record the interfaces a type is asserted to implement", () => {
+      const buffer = result.types.find((t) => t.name === "Buffer");
+      expect(buffer!.implements).toEqual(["Closer", "io.Writer"]);
+    });
+
+    it("should list asserted implementations of open interfaces", () => {
+      const closer = result.types.find((t) => t.name === "Closer");
+      expect(closer!.sealed).toBeUndefined();
+      expect(closer!.implementations).toEqual(["Buffer"]);
+    });
+
+    it("should merge assertions with inferred implementations", () => {
+      const message = result.types.find((t) => t.name === "Message");
+      expect(message!.implementations).toEqual(["AIMessage", "systemMessage"]);
+    });
   });
 
   describe("type alias extraction", () => {
@@ -216,6 +460,150 @@ describe("GoExtractor", () => {
       const middleware = result.types.find((t) => t.name === "Middleware");
       expect(middleware!.signature).toContain("=");
     });
+
+    it("should extract aliases of multi-line type literals", () => {
+      const stringer = result.types.find((t) => t.name === "Stringer");
+      expect(stringer!.aliasOf).toBe("interface{ String() string }");
+    });
+
+    it("should extract type parameters of generic aliases", () => {
+      const index = result.types.find((t) => t.name === "PageIndex")!;
+      expect(index.kind).toBe("alias");
+      expect(index.signature).toBe("type PageIndex[K comparable] = map[K]Page[K, Result]");
+      expect(index.typeParams).toEqual([{ name: "K", constraint: "comparable" }]);
+    });
+  });
+
+  describe("generic extraction", () => {
+    it("should extract type parameters of generic structs", () => {
+      const page = result.types.find((t) => t.name === "Page");
+      expect(page!.signature).toBe("type Page[K comparable, V any] struct");
+      expect(page!.typeParams).toEqual([
+        { name: "K", constraint: "comparable" },
+        { name: "V", constraint: "any" },
+      ]);
+    });
+
+    it("should associate methods with generic receivers", () => {
+      const list = result.types.find((t) => t.name === "List");
+      const push = list!.methods.find((m) => m.name === "Push");
+      expect(push).toBeDefined();
+      expect(push!.signature).toBe("func (l *List[T]) Push(item T) *List[T]");
+      expect(push!.returns).toBe("*List[T]");
+    });
+
+    it("should extract generic functions with function-typed parameters", () => {
+      const mapFn = result.functions.find((f) => f.name === "Map");
+      expect(mapFn!.typeParams).toEqual([
+        { name: "T", constraint: "any" },
+        { name: "U", constraint: "any" },
+      ]);
+      expect(mapFn!.parameters[1]).toEqual({ name: "fn", type: "func(T) U" });
+      expect(mapFn!.returns).toBe("[]U");
+    });
+
+    it("should keep constraints with nested brackets whole", async () => {
+      const root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-generics-"));
+      await writeFile(path.join(root, "go.mod"), "module example.com/g\n");
+      await writeFile(
+        path.join(root, "g.go"),
+        [
+          "package g",
+          "",
+          "type Bytes[T interface{ ~[]byte }] struct{ v T }",
+          "",
+          "type Index[M ~map[K]V, K comparable, V any] = map[K]M",
+          "",
+          "func Keys[M ~map[K]V, K comparable, V any](m M) []K { return nil }",
+          "",
+        ].join("\n"),
+      );
+      const generic = await new GoExtractor(
+        createConfig({ packageName: "g", packagePath: root }),
+      ).extract();
+      await rm(root, { recursive: true, force: true });
+
+      const bytes = generic.types.find((t) => t.name === "Bytes")!;
+      expect(bytes.signature).toBe("type Bytes[T interface{ ~[]byte }] struct");
+      expect(bytes.typeParams).toEqual([{ name: "T", constraint: "interface{ ~[]byte }" }]);
+      const index = generic.types.find((t) => t.name === "Index")!;
+      expect(index.aliasOf).toBe("map[K]M");
+      expect(index.typeParams![0]).toEqual({ name: "M", constraint: "~map[K]V" });
+      const keys = generic.functions.find((f) => f.name === "Keys")!;
+      expect(keys.signature).toBe("func Keys[M ~map[K]V, K comparable, V any](m M) []K");
+      expect(keys.typeParams).toHaveLength(3);
+    });
+  });
+
+  describe("field doc extraction", () => {
+    it("should keep every comment line above a field", () => {
+      const retryPolicy = result.types.find((t) => t.name === "RetryPolicy");
+      const backoff = retryPolicy!.fields.find((f) => f.name === "Backoff");
+      expect(backoff!.doc).toBe(
+        [
+          "Backoff is the delay between attempts in milliseconds.",
+          "",
+          "Deprecated: Use BackoffDuration instead. It will be removed",
+          "in the next major release.",
+        ].join("\n"),
+      );
+    });
+  });
+
+  describe("anonymous type literals", () => {
+    it("should not leak fields of nested struct literals into the outer struct", () => {
+      const options = result.types.find((t) => t.name === "Options");
+      expect(options!.fields.map((f) => f.name)).toEqual(["Addr", "Limits", "Hooks", "Upstream"]);
+    });
+
+    it("should normalize multi-line literal field types to one line", () => {
+      const options = result.types.find((t) => t.name === "Options");
+      const limits = options!.fields.find((f) => f.name === "Limits");
+      expect(limits!.type).toBe('struct{ MaxConns int; Burst int `json:"burst"` }');
+    });
+
+    it("should keep nested field docs in the structured type", () => {
+      const options = result.types.find((t) => t.name === "Options");
+      const limits = options!.fields.find((f) => f.name === "Limits");
+      expect(limits!.typeExpr).toEqual({
+        kind: "struct",
+        fields: [
+          {
+            name: "MaxConns",
+            type: { kind: "named", name: "int", builtin: true },
+            doc: "MaxConns caps concurrent connections.",
+          },
+          {
+            name: "Burst",
+            type: { kind: "named", name: "int", builtin: true },
+            tag: 'json:"burst"',
+            doc: "Burst is the allowed request burst.",
+          },
+        ],
+      });
+    });
+
+    it("should keep trailing comments of literal fields out of their types", () => {
+      const options = result.types.find((t) => t.name === "Options");
+      const upstream = options!.fields.find((f) => f.name === "Upstream");
+      expect(upstream!.type).toBe(
+        'struct{ Host string; Port int; User string; URL string `default:"http://localhost"` }',
+      );
+      expect(upstream!.typeExpr).toMatchObject({
+        fields: [
+          { name: "Host", doc: "hostname" },
+          { name: "Port", doc: "port (default 80" },
+          { name: "User" },
+          { name: "URL", tag: 'default:"http://localhost"', doc: "base URL [optional]" },
+        ],
+      });
+    });
+
+    it("should keep the field line of multi-line literals", () => {
+      const options = result.types.find((t) => t.name === "Options");
+      const hooks = options!.fields.find((f) => f.name === "Hooks");
+      expect(hooks!.startLine).toBeGreaterThan(options!.fields[1].startLine + 5);
+    });
   });
 
   describe("function extraction", () => {
@@ -273,6 +661,24 @@ describe("GoExtractor", () => {
       expect(maxRetries!.type).toBe("int");
     });
 
+    it("should take the type of function literal variables from their signature", () => {
+      const shouldRetry = result.constants.find((c) => c.name === "ShouldRetry");
+      expect(shouldRetry!.type).toBe(
+        "func(ctx context.Context, resp *Response, err error) (bool, error)",
+      );
+      expect(shouldRetry!.value).toBeUndefined();
+      expect(shouldRetry!.funcLiteral!.parameters.map((p) => p.name)).toEqual([
+        "ctx",
+        "resp",
+        "err",
+      ]);
+    });
+
+    it("should extract composite variable types", () => {
+      const routes = result.constants.find((c) => c.name === "Routes");
+      expect(routes!.type).toBe("map[string]Handler");
+    });
+
     it("should extract variable declarations", () => {
       const constNames = result.constants.map((c) => c.name);
       expect(constNames).toContain("ErrNotFound");
@@ -288,6 +694,112 @@ describe("GoExtractor", () => {
       const constNames = result.constants.map((c) => c.name);
       expect(constNames).not.toContain("unexportedConst");
     });
+
+    it("should extract the value expression without trailing comments", () => {
+      const maxBatchSize = result.constants.find((c) => c.name === "MaxBatchSize");
+      expect(maxBatchSize!.value).toBe("defaultBatchSize * 4");
+    });
+
+    it("should extract variable initializers", () => {
+      const errNotFound = result.constants.find((c) => c.name === "ErrNotFound");
+      expect(errNotFound!.value).toBe('errors.New("not found")');
+      expect(errNotFound!.evaluated).toBeUndefined();
+    });
+  });
+
+  describe("constant evaluation", () => {
+    it("should fold expressions over standard library constants", () => {
+      const timeout = result.constants.find((c) => c.name === "RequestTimeout");
+      expect(timeout!.evaluated).toEqual({
+        value: "30000000000",
+        type: "time.Duration",
+        underlyingType: "int64",
+        display: "30s",
+      });
+    });
+
+    it("should resolve unexported constants declared later", () => {
+      const maxBatchSize = result.constants.find((c) => c.name === "MaxBatchSize");
+      expect(maxBatchSize!.evaluated).toEqual({ value: "1024", type: "untyped int" });
+    });
+
+    it("should apply the declared type", () => {
+      const maxRetries = result.constants.find((c) => c.name === "MaxRetries");
+      expect(maxRetries!.evaluated).toEqual({ value: "3", type: "int" });
+    });
+
+    it("should concatenate string constants", () => {
+      const userAgent = result.constants.find((c) => c.name === "UserAgent");
+      expect(userAgent!.evaluated!.value).toBe('"langchain-go/0.3.0"');
+    });
+
+    it("should resolve exported constants of the module's other packages", async () => {
+      const root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-consts-"));
+      await mkdir(path.join(root, "limits"));
+      await writeFile(path.join(root, "go.mod"), "module example.com/m\n");
+      await writeFile(
+        path.join(root, "limits", "limits.go"),
+        "package limits\n\ntype Size int\n\nconst MaxBatch Size = 100\n\nconst private = 1\n",
+      );
+      await writeFile(
+        path.join(root, "m.go"),
+        [
+          "package m",
+          "",
+          'import lim "example.com/m/limits"',
+          "",
+          "const BatchLimit = lim.MaxBatch * 2",
+          "",
+          "const Hidden = lim.private",
+          "",
+        ].join("\n"),
+      );
+      const extracted = await new GoExtractor(
+        createConfig({ packageName: "m", packagePath: root }),
+      ).extract();
+      await rm(root, { recursive: true, force: true });
+
+      const constant = (name: string) => extracted.constants.find((c) => c.name === name)!;
+      expect(constant("BatchLimit").evaluated).toMatchObject({ value: "200", type: "lim.Size" });
+      expect(constant("Hidden").evaluated).toBeUndefined();
+    });
+  });
+
+  describe("constructor defaults", () => {
+    it("should record constant field values from returned composite literals", () => {
+      const config = result.types.find((t) => t.name === "Config")!;
+      expect(config.fields.map((f) => [f.name, f.defaults])).toEqual([
+        ["Host", [{ value: '"localhost"', constructor: "WithDefaults" }]],
+        ["Port", [{ value: "8080", constructor: "WithDefaults" }]],
+        ["Debug", [{ value: "false", constructor: "WithDefaults" }]],
+      ]);
+    });
+
+    it("should skip values taken from parameters", () => {
+      const client = result.types.find((t) => t.name === "Client")!;
+      const defaults = Object.fromEntries(client.fields.map((f) => [f.name, f.defaults]));
+      expect(defaults.BaseURL).toBeUndefined();
+      expect(defaults.APIKey).toBeUndefined();
+      expect(defaults.Timeout).toEqual([{ value: "30", constructor: "NewClient" }]);
+    });
+
+    it("should keep the returned literal on the constructor", () => {
+      const withDefaults = result.functions.find((f) => f.name === "WithDefaults");
+      expect(withDefaults!.returnedLiteral).toEqual({
+        type: "Config",
+        fields: { Host: '"localhost"', Port: "8080", Debug: "false" },
+      });
+    });
+
+    it("should read the first of multiple results", () => {
+      const loadConfig = result.functions.find((f) => f.name === "LoadConfig");
+      expect(loadConfig!.returnedLiteral).toEqual({ type: "Config", fields: {} });
+    });
+
+    it("should ignore functions that don't return a literal", () => {
+      const ping = result.functions.find((f) => f.name === "Ping");
+      expect(ping!.returnedLiteral).toBeUndefined();
+    });
   });
 });
 
@@ -305,6 +817,50 @@ describe("GoExtractor with exportedOnly=false", () => {
     const constNames = result.constants.map((c) => c.name);
     expect(constNames).toContain("unexportedConst");
   });
+
+  it("should include unexported functions, types, methods, and fields", async () => {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      exportedOnly: false,
+    });
+
+    const result = await new GoExtractor(config).extract();
+    const functionNames = result.functions.map((f) => f.name);
+    const baseClient = result.types.find((t) => t.name === "baseClient");
+    const client = result.types.find((t) => t.name === "Client");
+
+    expect(functionNames).toContain("newRequestID");
+    expect(functionNames).not.toContain("init");
+    expect(baseClient!.methods.map((m) => m.name)).toContain("reset");
+    expect(client!.fields.map((f) => f.name)).toEqual(["BaseURL", "APIKey", "Timeout", "internal"]);
+  });
+});
+
+describe("GoExtractor assertion policies", () => {
+  const extract = (implementationAssertions: "ignore" | "only") =>
+    new GoExtractor(
+      createConfig({
+        packageName: "test-package",
+        packagePath: fixturesPath,
+        implementationAssertions,
+      }),
+    ).extract();
+
+  it("should list only asserted implementations with the only policy", async () => {
+    const result = await extract("only");
+
+    const message = result.types.find((t) => t.name === "Message");
+    expect(message!.sealed).toBe(true);
+    expect(message!.implementations).toEqual(["AIMessage"]);
+  });
+
+  it("should skip assertions with the ignore policy", async () => {
+    const result = await extract("ignore");
+
+    expect(result.types.find((t) => t.name === "Buffer")!.implements).toBeUndefined();
+    expect(result.types.find((t) => t.name === "Closer")!.implementations).toBeUndefined();
+  });
 });
 
 describe("GoExtractor file discovery", () => {
@@ -336,3 +892,189 @@ describe("GoExtractor file discovery", () => {
     expect(client).toBeUndefined();
   });
 });
+
+describe("GoExtractor package doc selection", () => {
+  let root: string;
+
+  beforeAll(async () => {
+    root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-doc-"));
+    await mkdir(path.join(root, "internal"));
+    await writeFile(path.join(root, "a.go"), "// Package client is a stub.\npackage client\n");
+    await writeFile(
+      path.join(root, "internal", "doc.go"),
+      "// Package internal helps.\npackage internal\n",
+    );
+  });
+
+  afterAll(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  async function extractPackageDoc(): Promise<string | undefined> {
+    const config = createConfig({ packageName: "client", packagePath: root });
+    return (await new GoExtractor(config).extract()).packageDoc;
+  }
+
+  it("should prefer the root package over a subpackage's doc.go", async () => {
+    expect(await extractPackageDoc()).toBe("Package client is a stub.");
+  });
+
+  it("should prefer the root doc.go over files sorted before it", async () => {
+    await writeFile(
+      path.join(root, "doc.go"),
+      "// Package client talks to the API.\npackage client\n",
+    );
+    expect(await extractPackageDoc()).toBe("Package client talks to the API.");
+  });
+});
+
+describe("GoExtractor test helpers", () => {
+  let root: string;
+
+  beforeAll(async () => {
+    root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-tests-"));
+    await writeFile(path.join(root, "client.go"), "package client\n\nfunc Dial() {}\n");
+    await writeFile(
+      path.join(root, "export_test.go"),
+      [
+        "// Package client is documented in client.go.",
+        "package client",
+        "",
+        "// SetClock swaps the clock in tests.",
+        "func SetClock() {}",
+        "",
+        "func TestDial(t *testing.T) {}",
+        "",
+        "func Testify() {}",
+        "",
+      ].join("\n"),
+    );
+    await writeFile(
+      path.join(root, "client_test.go"),
+      "package client_test\n\nfunc Helper() {}\n",
+    );
+  });
+
+  afterAll(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  async function extractFunctions(testHelpers?: boolean): Promise<ExtractionResult> {
+    const config = createConfig({ packageName: "client", packagePath: root, testHelpers });
+    return new GoExtractor(config).extract();
+  }
+
+  it("should skip _test.go files by default", async () => {
+    const result = await extractFunctions();
+    expect(result.functions.map((f) => f.name)).toEqual(["Dial"]);
+  });
+
+  it("should extract in-package test exports but not the tests themselves", async () => {
+    const result = await extractFunctions(true);
+    expect(result.functions.map((f) => f.name).sort()).toEqual(["Dial", "SetClock", "Testify"]);
+    expect(result.packageDoc).toBeUndefined();
+  });
+});
+
+describe("GoExtractor body-less declarations", () => {
+  let root: string;
+  let functions: ExtractionResult["functions"];
+
+  beforeAll(async () => {
+    root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-asm-"));
+    await writeFile(
+      path.join(root, "sqrt.go"),
+      [
+        "package fastmath",
+        "",
+        "// Sqrt returns the square root of x.",
+        "func Sqrt(x float64) float64",
+        "",
+        "// Nanotime reads the monotonic clock.",
+        "//",
+        "//go:linkname Nanotime runtime.nanotime",
+        "func Nanotime() int64 // provided by the runtime {see runtime}",
+        "",
+        "// Abs returns |x|.",
+        "func Abs(x float64) float64 {",
+        "\treturn x",
+        "}",
+      ].join("\n"),
+    );
+    const config = createConfig({ packageName: "fastmath", packagePath: root });
+    functions = (await new GoExtractor(config).extract()).functions;
+  });
+
+  afterAll(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it("should mark assembly-backed functions", () => {
+    const sqrt = functions.find((f) => f.name === "Sqrt")!;
+    expect(sqrt.signature).toBe("func Sqrt(x float64) float64");
+    expect(sqrt.externalImplementation).toEqual({ kind: "assembly" });
+  });
+
+  it("should mark linknamed functions with their target", () => {
+    const nanotime = functions.find((f) => f.name === "Nanotime")!;
+    expect(nanotime.signature).toBe("func Nanotime() int64");
+    expect(nanotime.externalImplementation).toEqual({
+      kind: "linkname",
+      target: "runtime.nanotime",
+    });
+  });
+
+  it("should leave functions with a body unmarked", () => {
+    expect(functions.find((f) => f.name === "Abs")!.externalImplementation).toBeUndefined();
+  });
+});
+
+describe("GoExtractor generated files", () => {
+  let root: string;
+
+  beforeAll(async () => {
+    root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-generated-"));
+    await writeFile(path.join(root, "color.go"), "package color\n\ntype Color int\n");
+    await writeFile(
+      path.join(root, "color_string.go"),
+      [
+        '// Code generated by "stringer -type=Color"; DO NOT EDIT.',
+        "",
+        "package color",
+        "",
+        "func (c Color) String() string { return \"\" }",
+        "",
+        "func Names() []string { return nil }",
+        "",
+      ].join("\n"),
+    );
+    await writeFile(
+      path.join(root, "notes.go"),
+      "package color\n\n// Code generated ... DO NOT EDIT. is how headers look.\nfunc Parse() {}\n",
+    );
+  });
+
+  afterAll(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it("should list generated files", async () => {
+    const config = createConfig({ packageName: "color", packagePath: root });
+    const result = await new GoExtractor(config).extract();
+
+    expect(result.generatedFiles).toEqual(["color_string.go"]);
+    expect(result.functions.map((f) => f.name).sort()).toEqual(["Names", "Parse"]);
+  });
+
+  it("should drop generated files when excluded", async () => {
+    const config = createConfig({
+      packageName: "color",
+      packagePath: root,
+      generatedFiles: "exclude",
+    });
+    const result = await new GoExtractor(config).extract();
+
+    expect(result.generatedFiles).toBeUndefined();
+    expect(result.functions.map((f) => f.name)).toEqual(["Parse"]);
+  });
+});
@@ -0,0 +1,34 @@
+// Package example provides example annotation tags for testing.
+package example
+
+// Reranker reorders retrieved documents by relevance.
+//
+// @experimental @category retrievers
+// @since v0.3.0
+type Reranker struct {
+	// TopN caps the number of documents returned.
+	// @since v0.4.0
+	TopN int
+}
+
+// NewReranker creates a Reranker that keeps the top n documents.
+//
+// Mail support@example.com for access; an @since inside prose is not a tag.
+//
+// @since v0.3.0
+func NewReranker(n int) *Reranker {
+	return &Reranker{TopN: n}
+}
+
+// Rerank reorders documents with a Reranker keeping the top ten.
+// See also [Reranker], [NewReranker], and [sort.Strings]; [Rerank] itself
+// and [Unknown] are left out. Other links like [Reranker.TopN] are not related.
+//
+// score  rank
+// 0.9    1
+// 0.5    2
+//
+// Category: retrievers
+func Rerank(docs []string) []string {
+	return docs
+}
@@ -0,0 +1,30 @@
+// Package example provides example interface assertions for testing.
+package example
+
+import "io"
+
+// Compile-time checks that the types implement their interfaces.
+var (
+	_ Message   = AIMessage{}
+	_ io.Writer = (*Buffer)(nil)
+	_ Closer    = &Buffer{}
+)
+
+// Buffer collects written bytes. It is an [io.Writer] and a [Closer]:
+// call [Buffer.Write] to append and [Buffer.Close] to reset, or encode
+// values into it with [encoding/json.NewEncoder]. See also [Flusher].
+type Buffer struct {
+	data []byte
+}
+
+// Write appends p to the buffer.
+func (b *Buffer) Write(p []byte) (int, error) {
+	b.data = append(b.data, p...)
+	return len(p), nil
+}
+
+// Close discards the buffered bytes.
+func (b *Buffer) Close() error {
+	b.data = nil
+	return nil
+}
@@ -0,0 +1,31 @@
+package example_test
+
+import (
+	"testing"
+
+	example "github.com/example/testpkg"
+)
+
+// BenchmarkConnect measures connection setup.
+func BenchmarkConnect(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = example.Connect("api.example.com", "key")
+	}
+}
+
+// Round-trips through a client.
+func BenchmarkRoundTrip(b *testing.B) {
+	client := example.NewClient("https://api.example.com", "key")
+	for i := 0; i < b.N; i++ {
+		_, _ = client.Get(nil, "/a") // example.Missing in a comment
+	}
+}
+
+func FuzzConnect_hosts(f *testing.F) {
+	f.Add("api.example.com")
+	f.Fuzz(func(t *testing.T, host string) {
+		_, _ = example.Connect(host, "key")
+	})
+}
+
+func TestConnect(t *testing.T) {}
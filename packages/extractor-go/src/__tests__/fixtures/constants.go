@@ -0,0 +1,24 @@
+// Package example provides example constant expressions for testing.
+package example
+
+import "time"
+
+// RequestTimeout is the default request timeout.
+const RequestTimeout = 30 * time.Second
+
+// PollInterval is how often jobs are polled.
+const PollInterval time.Duration = 500 * time.Millisecond
+
+// MaxBatchSize is the largest batch accepted.
+const MaxBatchSize = defaultBatchSize * 4 // four full pages
+
+const defaultBatchSize = 1 << 8
+
+// UserAgent identifies the client.
+const UserAgent = "langchain-go/" + Version
+
+// Version is the library version.
+const Version = "0.3.0"
+
+// Routes maps request paths to their handlers.
+var Routes map[string]Handler = map[string]Handler{}
@@ -0,0 +1,30 @@
+// Package example provides example context-aware APIs for testing.
+package example
+
+import "context"
+
+// Search looks up documents matching the query.
+func Search(query string) ([]string, error) {
+	return SearchContext(context.Background(), query)
+}
+
+// SearchContext looks up documents matching the query, honoring ctx.
+func SearchContext(ctx context.Context, query string) ([]string, error) {
+	return nil, nil
+}
+
+// Index is a searchable document index.
+type Index struct {
+	// Name identifies the index.
+	Name string
+}
+
+// Flush writes buffered documents.
+func (i *Index) Flush() error {
+	return i.FlushWithContext(context.Background())
+}
+
+// FlushWithContext writes buffered documents, honoring ctx.
+func (i *Index) FlushWithContext(ctx context.Context) error {
+	return nil
+}
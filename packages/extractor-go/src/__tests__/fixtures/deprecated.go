@@ -0,0 +1,24 @@
+// Package example provides example deprecation markers for testing.
+package example
+
+// RetryPolicy controls how failed requests are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts.
+	MaxAttempts int
+	// Backoff is the delay between attempts in milliseconds.
+	//
+	// Deprecated: Use BackoffDuration instead. It will be removed
+	// in the next major release.
+	Backoff int
+	// Deprecated: Jitter is always applied.
+	Jitter bool
+	// BackoffDuration is the delay between attempts.
+	BackoffDuration string
+}
+
+// Retry calls f until it succeeds or the policy gives up.
+//
+// Deprecated: Use RetryPolicy with Do instead.
+func Retry(f func() error) error {
+	return f()
+}
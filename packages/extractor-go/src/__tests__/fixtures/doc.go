@@ -0,0 +1,7 @@
+// Package example provides example Go APIs for testing the extractor.
+//
+// The package covers types, functions, constants, generics, and the doc
+// comment conventions the extractor understands.
+package example
+
+// TODO(alice): Support streaming responses.
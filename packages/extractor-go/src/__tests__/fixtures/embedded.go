@@ -0,0 +1,29 @@
+package example
+
+// baseClient holds the connection shared by the API clients.
+type baseClient struct{}
+
+// Ping checks that the server is reachable.
+func (b *baseClient) Ping() error {
+	return nil
+}
+
+// Close closes the connection.
+func (b *baseClient) Close() error {
+	return nil
+}
+
+func (b *baseClient) reset() {}
+
+// Store is a key-value store client.
+type Store struct {
+	*baseClient
+	Buffer
+	// Bucket is the bucket the store reads from.
+	Bucket string
+}
+
+// Close flushes pending writes and closes the connection.
+func (s *Store) Close() error {
+	return nil
+}
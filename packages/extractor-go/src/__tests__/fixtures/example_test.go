@@ -0,0 +1,38 @@
+package example_test
+
+import (
+	"fmt"
+
+	example "github.com/example/testpkg"
+)
+
+func ExampleConnect() {
+	client, err := example.Connect("api.example.com", "my-api-key")
+	if err != nil {
+		panic(err)
+	}
+	defer client.Close()
+
+	fmt.Println(client != nil)
+	// Output: true
+}
+
+// Retries are left to the caller.
+func ExampleConnect_retry() {
+	for attempt := 1; attempt <= 3; attempt++ {
+		if _, err := example.Connect("api.example.com", "my-api-key"); err == nil {
+			break
+		}
+	}
+}
+
+func ExampleClient_Get() {
+	client := example.NewClient("https://api.example.com", "my-api-key")
+	for _, path := range []string{"/a", "/b"} {
+		fmt.Println(path)
+	}
+	_, _ = client.Get(nil, "/a")
+	// Unordered output:
+	// /b
+	// /a
+}
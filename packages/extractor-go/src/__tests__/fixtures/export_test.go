@@ -0,0 +1,8 @@
+package example
+
+import "testing"
+
+// SetRetryDelay overrides the retry delay for the package's tests.
+func SetRetryDelay(seconds int) {}
+
+func TestConnect(t *testing.T) {}
@@ -19,6 +19,11 @@ var ErrNotFound = errors.New("not found")
 // ErrUnauthorized indicates an authentication failure.
 var ErrUnauthorized error = errors.New("unauthorized")
 
+// ShouldRetry decides whether a failed request is retried.
+var ShouldRetry = func(ctx context.Context, resp *Response, err error) (bool, error) {
+	return err != nil, nil
+}
+
 // unexportedConst should not be extracted
 const unexportedConst = "hidden"
 
@@ -37,6 +42,9 @@ func Connect(host, apiKey string) (*Client, error) {
 }
 
 // Ping checks if the service is available.
+//
+// BUG(cbromann): Ping ignores the context deadline
+// and waits for the full network timeout.
 func Ping(ctx context.Context, host string) error {
 	return nil
 }
@@ -102,3 +110,10 @@ type Result struct {
 	// Error contains any error that occurred.
 	Error error
 }
+
+// newRequestID builds a request ID with the given prefix.
+func newRequestID(prefix string) string {
+	return prefix + "-1"
+}
+
+func init() {}
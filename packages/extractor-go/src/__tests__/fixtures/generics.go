@@ -0,0 +1,47 @@
+// Package example provides example generic types for testing.
+package example
+
+import "iter"
+
+// List is an ordered collection of items.
+type List[T any] struct {
+	// Items holds the list elements.
+	Items []T
+}
+
+// Push appends an item and returns the list.
+func (l *List[T]) Push(item T) *List[T] {
+	l.Items = append(l.Items, item)
+	return l
+}
+
+// All iterates over the index and value of each item.
+func (l *List[T]) All() iter.Seq2[int, T] {
+	return nil
+}
+
+// Values iterates over the items of lists.
+func Values[T any](lists ...List[T]) iter.Seq[T] {
+	return nil
+}
+
+// Page is a single page of results.
+type Page[K comparable, V any] struct {
+	// Entries maps keys to values on this page.
+	Entries map[K]V
+	// Next is the cursor of the following page.
+	Next string
+}
+
+// PageIndex maps keys to their pages.
+type PageIndex[K comparable] = map[K]Page[K, Result]
+
+// Collect groups the given names into pages.
+func Collect(names List[string]) map[string]Page[string, Result] {
+	return nil
+}
+
+// Map applies fn to every element of xs.
+func Map[T, U any](xs []T, fn func(T) U) []U {
+	return nil
+}
@@ -0,0 +1,35 @@
+// Package example provides example grouped declarations for testing.
+package example
+
+import "errors"
+
+// Level is a log level.
+type Level int
+
+// Log levels.
+const (
+	// LevelDebug logs everything.
+	LevelDebug Level = iota
+	LevelInfo        // informational messages
+	LevelWarn        //nolint:revive
+	levelMax
+)
+
+const (
+	DefaultPort = 8080 // default server port
+	DefaultHost = "localhost"
+)
+
+var (
+	// ErrClosed is returned after Close.
+	ErrClosed = errors.New("closed")
+	Verbose   bool // enables debug output
+)
+
+// ServerOptions configures a server.
+type ServerOptions struct {
+	Port int // server port
+	// Host is the interface to bind.
+	Host string // ignored: the doc comment above wins
+	TLS  bool   `json:"tls"` // serve HTTPS
+}
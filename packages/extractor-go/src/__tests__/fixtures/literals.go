@@ -0,0 +1,33 @@
+// Package example provides example anonymous type literals for testing.
+package example
+
+// Options configures a server.
+type Options struct {
+	// Addr is the listen address.
+	Addr string
+	// Limits bounds resource usage.
+	Limits struct {
+		// MaxConns caps concurrent connections.
+		MaxConns int
+		// Burst is the allowed request burst.
+		Burst int `json:"burst"`
+	}
+	// Hooks receives lifecycle callbacks.
+	Hooks interface {
+		// OnStart is called once the server is listening.
+		OnStart(addr string)
+		Closer
+	}
+	// Upstream is the proxied backend.
+	Upstream struct {
+		Host string // hostname
+		Port int    // port (default 80
+		User string
+		URL  string `default:"http://localhost"` // base URL [optional]
+	}
+}
+
+// Serve starts a server for the named service and closes c on shutdown.
+func Serve(svc struct{ Name string }, c interface{ Close() error }) error {
+	return nil
+}
@@ -0,0 +1,17 @@
+//go:build linux && !android
+
+package example
+
+// SetAffinity pins the calling thread to a CPU.
+//
+//go:noinline
+//nolint:errcheck
+func SetAffinity(cpu int) error {
+	return nil
+}
+
+// PageSize is the memory page size.
+const PageSize = 4096 //nolint:gochecknoglobals
+
+//go:noinline
+func Spin() {}
@@ -0,0 +1,33 @@
+// Package example provides example sealed interfaces for testing.
+package example
+
+// Message is a chat message. Only this package provides implementations.
+type Message interface {
+	// Text returns the message content.
+	Text() string
+	isMessage()
+}
+
+// AIMessage is a message produced by a model.
+type AIMessage struct {
+	// Content is the message text.
+	Content string
+}
+
+// Text returns the message content.
+func (m AIMessage) Text() string { return m.Content }
+
+func (AIMessage) isMessage() {}
+
+type systemMessage struct{ content string }
+
+func (m *systemMessage) Text() string { return m.content }
+
+func (m *systemMessage) isMessage() {}
+
+// ToolMessage carries a tool result.
+type ToolMessage interface {
+	Message
+	// ToolCallID identifies the originating tool call.
+	ToolCallID() string
+}
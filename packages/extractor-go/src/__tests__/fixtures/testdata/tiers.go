@@ -0,0 +1,27 @@
+// Package example provides example visibility tiers for testing.
+package example
+
+// Gateway routes requests to partner backends.
+//
+// Visibility: partner
+type Gateway struct {
+	// Region is the gateway region.
+	Region string
+	// DebugToken grants access to diagnostics.
+	//
+	// Visibility: internal
+	DebugToken string
+}
+
+// Route forwards a request to the matching backend.
+func (g *Gateway) Route(path string) error {
+	return nil
+}
+
+// Drain stops accepting requests.
+//
+// Visibility: internal
+func Drain() {}
+
+// ExperimentalRouter is an unstable router.
+func ExperimentalRouter() {}
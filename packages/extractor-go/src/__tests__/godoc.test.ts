@@ -0,0 +1,87 @@
+/**
+ * godoc HTML tests
+ */
+
+import fs from "node:fs";
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput, type ExtractorOutput } from "../output.js";
+import { renderGodoc } from "../godoc.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("renderGodoc", () => {
+  let output: ExtractorOutput;
+  let sources: Record<string, string>;
+  let page: string;
+
+  beforeAll(async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    output = buildOutput(result, config, new GoTransformer(result, config).transform());
+    sources = Object.fromEntries(
+      fs
+        .readdirSync(fixturesPath)
+        .filter((file) => file.endsWith(".go"))
+        .map((file) => [file, fs.readFileSync(path.join(fixturesPath, file), "utf-8")]),
+    );
+    page = renderGodoc(output, sources).get("index.html")!;
+  });
+
+  it("should write the pkg.go.dev sections in order", () => {
+    const sections = [...page.matchAll(/<h3 id="(pkg-\w+)">/g)].map((match) => match[1]);
+    expect(sections).toEqual([
+      "pkg-overview",
+      "pkg-index",
+      "pkg-constants",
+      "pkg-variables",
+      "pkg-functions",
+      "pkg-types",
+    ]);
+  });
+
+  it("should list constructors and methods under their type", () => {
+    expect(page).toContain(
+      '<li><a href="#Client">type Client</a>\n<ul>\n' +
+        '<li><a href="#Connect">func Connect(host, apiKey string) (*Client, error)</a></li>\n',
+    );
+    expect(page).toContain('<h4 class="Documentation-typeFuncHeader" id="NewClient">');
+    expect(page).not.toContain('<h4 class="Documentation-functionHeader" id="NewClient">');
+    expect(page).toContain(
+      '<h4 class="Documentation-typeMethodHeader" id="Client.Get">' +
+        'func (c *Client) <a href="#Client.Get">Get</a></h4>',
+    );
+  });
+
+  it("should show type declarations without unexported fields", () => {
+    expect(page).toContain(
+      "<pre>type Client struct {\n\t// BaseURL is the base URL for API requests.\n\tBaseURL string\n",
+    );
+    expect(page).toContain(
+      "\tTimeout int\n\t// contains filtered or unexported fields\n}</pre>",
+    );
+    expect(page).toContain("<pre>type ReadWriteCloser interface {\n\tio.Reader\n");
+  });
+
+  it("should rebuild declarations from the IR without sources", () => {
+    const fallback = renderGodoc(output).get("index.html")!;
+    expect(fallback).toContain(
+      "<pre>type Client struct {\n\tBaseURL string\n\tAPIKey string\n\tTimeout int\n}</pre>",
+    );
+  });
+
+  it("should tag deprecated symbols and link doc links to anchors", () => {
+    expect(page).toMatch(
+      /id="Retry">func <a href="#Retry">Retry<\/a> <span class="Documentation-deprecatedTag">/,
+    );
+    expect(page).toContain('<a href="#Buffer.Write">Buffer.Write</a>');
+  });
+});
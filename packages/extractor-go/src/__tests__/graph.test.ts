@@ -0,0 +1,81 @@
+/**
+ * Symbol graph tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput } from "../output.js";
+import { buildSymbolGraph, type SymbolGraph } from "../graph.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("buildSymbolGraph", () => {
+  let graph: SymbolGraph;
+  const id = (name: string) => `pkg_go_test_package:${name}`;
+  const edgesFrom = (source: string, kind?: string) =>
+    graph.relationships.filter((e) => e.source === id(source) && (!kind || e.kind === kind));
+
+  beforeAll(async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    graph = buildSymbolGraph(
+      buildOutput(result, config, new GoTransformer(result, config).transform()),
+    );
+  });
+
+  it("should have a node per symbol and struct field", () => {
+    expect(graph.module).toEqual({
+      name: "test-package",
+      packageId: "pkg_go_test_package",
+      language: "go",
+    });
+    expect(graph.symbols.find((n) => n.id === id("Client_BaseURL"))).toEqual({
+      id: id("Client_BaseURL"),
+      kind: "property",
+      name: "BaseURL",
+      qualifiedName: "Client.BaseURL",
+      summary: "BaseURL is the base URL for API requests.",
+    });
+    expect(new Set(graph.symbols.map((n) => n.id)).size).toBe(graph.symbols.length);
+  });
+
+  it("should link methods and fields to their type", () => {
+    expect(edgesFrom("Client_Get", "memberOf")).toEqual([
+      { kind: "memberOf", source: id("Client_Get"), target: id("Client") },
+    ]);
+    expect(edgesFrom("Client_BaseURL")).toEqual([
+      { kind: "memberOf", source: id("Client_BaseURL"), target: id("Client") },
+    ]);
+  });
+
+  it("should link types to the interfaces they implement and embed", () => {
+    expect(edgesFrom("Buffer", "conformsTo")).toEqual([
+      { kind: "conformsTo", source: id("Buffer"), target: id("Closer") },
+      { kind: "conformsTo", source: id("Buffer"), target: "io.Writer", targetFallback: "io.Writer" },
+    ]);
+    expect(edgesFrom("ReadWriteCloser", "inheritsFrom").map((e) => e.target)).toEqual([
+      "io.Reader",
+      "io.Writer",
+      id("Closer"),
+    ]);
+  });
+
+  it("should link symbols to the types and symbols they reference", () => {
+    expect(edgesFrom("NewClient", "references")).toEqual([
+      { kind: "references", source: id("NewClient"), target: id("Client") },
+    ]);
+    expect(edgesFrom("Buffer", "references").map((e) => e.target)).toEqual([
+      id("Closer"),
+      id("Buffer_Write"),
+      id("Buffer_Close"),
+    ]);
+  });
+});
@@ -0,0 +1,185 @@
+/**
+ * gRPC service tests
+ */
+
+import http2 from "node:http2";
+import type { AddressInfo } from "node:net";
+
+import { describe, it, expect } from "vitest";
+import type { SymbolRecord } from "@langchain/ir-schema";
+
+import {
+  createGrpcServer,
+  frameGrpcMessage,
+  grpcServiceName,
+  grpcStatus,
+  readGrpcMessages,
+} from "../grpc.js";
+import {
+  ProtoWriter,
+  protoMessages,
+  protoString,
+  protoStrings,
+  protoVarint,
+  readProtoFields,
+  type ProtoField,
+} from "../protobuf.js";
+import { ExtractionCache, type ModuleExtractor } from "../serve.js";
+import type { ExtractorOutput } from "../output.js";
+
+function output(module: string, version: string, names: string[]): ExtractorOutput {
+  const symbols = names.map(
+    (name) =>
+      ({
+        id: `${module}:${name}`,
+        name,
+        qualifiedName: name,
+        kind: "function",
+        signature: `func ${name}() ${version}`,
+        docs: { summary: `${name} does things.` },
+        source: { repo: "", sha: "", path: "client.go", line: 3 },
+      }) as SymbolRecord,
+  );
+  return { package: { packageId: "pkg_go_m", synopsis: "Package m." }, symbols } as ExtractorOutput;
+}
+
+const extract: ModuleExtractor = async ({ module, version }) => {
+  const resolved = version === "latest" ? "v1.1.0" : version;
+  const names = resolved === "v1.0.0" ? ["New", "Old"] : ["New", "Run"];
+  return { module, version: resolved, output: output(module, resolved, names) };
+};
+
+interface Call {
+  status: number;
+  message: string;
+  responses: ProtoField[][];
+}
+
+async function listen(extractModule: ModuleExtractor) {
+  const server = createGrpcServer(new ExtractionCache(extractModule));
+  await new Promise<void>((resolve) => server.listen(0, resolve));
+  const client = http2.connect(`http://localhost:${(server.address() as AddressInfo).port}`);
+
+  const call = (method: string, request: ProtoWriter) =>
+    new Promise<Call>((resolve, reject) => {
+      const stream = client.request({
+        ":method": "POST",
+        ":path": `/${grpcServiceName}/${method}`,
+        "content-type": "application/grpc",
+      });
+      const chunks: Buffer[] = [];
+      stream.on("data", (chunk: Buffer) => chunks.push(chunk));
+      stream.on("trailers", (trailers) => {
+        stream.on("end", () =>
+          resolve({
+            status: Number(trailers["grpc-status"]),
+            message: decodeURIComponent(String(trailers["grpc-message"] ?? "")),
+            responses: readGrpcMessages(Buffer.concat(chunks)).map(readProtoFields),
+          }),
+        );
+      });
+      stream.on("error", reject);
+      stream.end(frameGrpcMessage(request.finish()));
+    });
+
+  const close = async () => {
+    client.close();
+    await new Promise((resolve) => server.close(resolve));
+  };
+  return { call, close };
+}
+
+describe("readGrpcMessages", () => {
+  it("should split length-prefixed messages", () => {
+    const body = Buffer.concat([
+      frameGrpcMessage(new ProtoWriter().string(1, "a").finish()),
+      frameGrpcMessage(new Uint8Array()),
+    ]);
+    const messages = readGrpcMessages(body);
+
+    expect(messages).toHaveLength(2);
+    expect(protoString(readProtoFields(messages[0]), 1)).toBe("a");
+    expect(() => readGrpcMessages(body.subarray(0, 6))).toThrow("Truncated gRPC message");
+  });
+});
+
+describe("createGrpcServer", () => {
+  it("should extract packages and stream their symbols", async () => {
+    const { call, close } = await listen(extract);
+    try {
+      const request = new ProtoWriter().string(1, "example.com/m");
+      const extracted = await call("ExtractPackage", request);
+      expect(extracted.status).toBe(grpcStatus.ok);
+      const [response] = extracted.responses;
+      const [pkg] = protoMessages(response, 1);
+      expect(protoString(pkg, 3)).toBe("v1.1.0");
+      expect(protoVarint(pkg, 5)).toBe(2);
+
+      const symbols = protoMessages(response, 2);
+      expect(symbols.map((symbol) => protoString(symbol, 2))).toEqual(["New", "Run"]);
+      expect(protoString(symbols[0], 6)).toBe("New does things.");
+      expect(protoVarint(symbols[0], 8)).toBe(3);
+      expect(JSON.parse(protoString(symbols[0], 9)).signature).toBe("func New() v1.1.0");
+
+      const streamed = await call("StreamSymbols", request);
+      expect(streamed.responses.map((symbol) => protoString(symbol, 1))).toEqual([
+        "example.com/m:New",
+        "example.com/m:Run",
+      ]);
+    } finally {
+      await close();
+    }
+  });
+
+  it("should get symbols and diff versions", async () => {
+    const { call, close } = await listen(extract);
+    try {
+      const symbol = await call(
+        "GetSymbol",
+        new ProtoWriter().string(1, "example.com/m").string(3, "example.com/m:Run"),
+      );
+      expect(protoString(symbol.responses[0], 2)).toBe("Run");
+
+      const missing = await call(
+        "GetSymbol",
+        new ProtoWriter().string(1, "example.com/m").string(3, "example.com/m:Gone"),
+      );
+      expect(missing.status).toBe(grpcStatus.notFound);
+
+      const diff = await call(
+        "DiffVersions",
+        new ProtoWriter().string(1, "example.com/m").string(2, "v1.0.0").string(3, "latest"),
+      );
+      const [response] = diff.responses;
+      expect(protoString(response, 2)).toBe("v1.1.0");
+      expect(protoStrings(response, 4)).toEqual(["example.com/m:Run"]);
+      expect(protoStrings(response, 5)).toEqual(["example.com/m:Old"]);
+      const [changed] = protoMessages(response, 6);
+      expect(protoString(changed, 1)).toBe("example.com/m:New");
+      expect(protoString(changed, 4)).toBe("func New() v1.1.0");
+    } finally {
+      await close();
+    }
+  });
+
+  it("should answer bad calls with a status", async () => {
+    const { call, close } = await listen(async () => {
+      throw new Error("not found on proxy");
+    });
+    try {
+      const invalid = await call("ExtractPackage", new ProtoWriter());
+      expect(invalid.status).toBe(grpcStatus.invalidArgument);
+      expect(invalid.message).toBe("module is required");
+
+      const failed = await call("ExtractPackage", new ProtoWriter().string(1, "example.com/gone"));
+      expect(failed.status).toBe(grpcStatus.internal);
+      expect(failed.message).toBe("not found on proxy");
+
+      const unknown = await call("Other", new ProtoWriter());
+      expect(unknown.status).toBe(grpcStatus.unimplemented);
+      expect(unknown.responses).toEqual([]);
+    } finally {
+      await close();
+    }
+  });
+});
@@ -0,0 +1,135 @@
+/**
+ * Symbol history tests
+ */
+
+import { mkdir, mkdtemp, rm, writeFile } from "node:fs/promises";
+import type { AddressInfo } from "node:net";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect, afterAll } from "vitest";
+import type { SymbolRecord } from "@langchain/ir-schema";
+
+import {
+  compareVersions,
+  createHistoryServer,
+  loadVersionStore,
+  matchHistoryRoute,
+  symbolHistory,
+  type VersionedOutput,
+} from "../history.js";
+import { compress } from "../compress.js";
+import type { ExtractorOutput } from "../output.js";
+
+function symbol(qualifiedName: string, signature: string, summary: string): SymbolRecord {
+  return {
+    id: `pkg_go_example:${qualifiedName.replace(/\./g, "_")}`,
+    qualifiedName,
+    signature,
+    docs: { summary },
+  } as SymbolRecord;
+}
+
+function version(name: string, symbols: SymbolRecord[]): VersionedOutput {
+  return { version: name, output: { symbols } as ExtractorOutput };
+}
+
+const versions = [
+  version("v0.1.0", [symbol("Client", "type Client struct", "Client talks to the API.")]),
+  version("v0.2.0", [
+    symbol("Client", "type Client struct", "Client talks to the API."),
+    symbol("Client.Get", "func (c *Client) Get(path string) []byte", "Get fetches path."),
+  ]),
+  version("v0.3.0", [
+    symbol("Client", "type Client struct", "Client talks to the API."),
+    symbol("Client.Get", "func (c *Client) Get(path string) ([]byte, error)", "Get fetches path."),
+  ]),
+  version("v0.10.0", [symbol("Client", "type Client struct", "Client is an API client.")]),
+];
+
+describe("compareVersions", () => {
+  it("should compare numeric parts numerically", () => {
+    const sorted = ["v0.10.0", "v0.2.0", "v1.0.0", "v1.0.0-rc.1"].sort(compareVersions);
+    expect(sorted).toEqual(["v0.2.0", "v0.10.0", "v1.0.0-rc.1", "v1.0.0"]);
+  });
+});
+
+describe("symbolHistory", () => {
+  it("should list the versions in which a symbol changed", () => {
+    const history = symbolHistory(versions, "pkg_go_example:Client_Get");
+
+    expect(history?.qualifiedName).toBe("Client.Get");
+    expect(history?.entries.map((e) => [e.version, e.changes])).toEqual([
+      ["v0.2.0", ["added"]],
+      ["v0.3.0", ["signature"]],
+      ["v0.10.0", ["removed"]],
+    ]);
+    expect(history?.entries[1].signature).toBe(
+      "func (c *Client) Get(path string) ([]byte, error)",
+    );
+  });
+
+  it("should record doc changes and accept qualified names", () => {
+    const history = symbolHistory(versions, "Client");
+
+    expect(history?.entries.map((e) => [e.version, e.changes])).toEqual([
+      ["v0.1.0", ["added"]],
+      ["v0.10.0", ["docs"]],
+    ]);
+    expect(history?.entries[1].summary).toBe("Client is an API client.");
+  });
+
+  it("should return undefined for unknown symbols", () => {
+    expect(symbolHistory(versions, "Missing")).toBeUndefined();
+  });
+});
+
+describe("matchHistoryRoute", () => {
+  it("should extract the symbol from history paths", () => {
+    expect(matchHistoryRoute("/symbols/pkg_go_example%3AClient/history")).toBe(
+      "pkg_go_example:Client",
+    );
+    expect(matchHistoryRoute("/symbols/Client")).toBeUndefined();
+  });
+});
+
+describe("createHistoryServer", () => {
+  let store: string;
+
+  afterAll(async () => {
+    await rm(store, { recursive: true, force: true });
+  });
+
+  it("should serve timelines from a version store", async () => {
+    store = await mkdtemp(path.join(os.tmpdir(), "extractor-go-history-"));
+    for (const { version: name, output } of versions) {
+      await mkdir(path.join(store, name));
+      // The newest output is compressed, as `--compress gzip` writes it
+      const [file, content] =
+        name === "v0.10.0"
+          ? ["symbols.json.gz", compress(JSON.stringify(output), "gzip")]
+          : ["symbols.json", JSON.stringify(output)];
+      await writeFile(path.join(store, name, file), content);
+    }
+    expect((await loadVersionStore(store)).map((v) => v.version)).toEqual([
+      "v0.1.0",
+      "v0.2.0",
+      "v0.3.0",
+      "v0.10.0",
+    ]);
+
+    const server = createHistoryServer(() => loadVersionStore(store));
+    await new Promise<void>((resolve) => server.listen(0, resolve));
+    const base = `http://localhost:${(server.address() as AddressInfo).port}`;
+    try {
+      const found = await fetch(`${base}/symbols/Client.Get/history`);
+      expect(found.status).toBe(200);
+      expect((await found.json()).entries).toHaveLength(3);
+
+      expect((await fetch(`${base}/symbols/Missing/history`)).status).toBe(404);
+      expect((await fetch(`${base}/other`)).status).toBe(404);
+    } finally {
+      await new Promise((resolve) => server.close(resolve));
+    }
+  });
+});
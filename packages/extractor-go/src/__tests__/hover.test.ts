@@ -0,0 +1,120 @@
+/**
+ * Hover docs tests
+ */
+
+import { mkdtemp, rm, writeFile } from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+import { PassThrough } from "node:stream";
+
+import { describe, it, expect, beforeAll, afterAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { buildOutput } from "../output.js";
+import { createConfig } from "../config.js";
+import { compress } from "../compress.js";
+import { HoverIndex, serveHover, watchOutputFile, type JsonRpcResponse } from "../hover.js";
+
+const source = `package hover
+
+// Client talks to the API.
+type Client struct{}
+
+// NewClient creates a Client.
+//
+// Deprecated: use Dial.
+func NewClient() *Client {
+	return &Client{}
+}
+
+// Close releases the client.
+func (c *Client) Close() error {
+	return nil
+}
+`;
+
+describe("HoverIndex", () => {
+  let root: string;
+  let output: ReturnType<typeof buildOutput>;
+  let index: HoverIndex;
+
+  beforeAll(async () => {
+    root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-hover-"));
+    await writeFile(path.join(root, "client.go"), source);
+
+    const config = createConfig({ packageName: "hover", packagePath: root });
+    const result = await new GoExtractor(config).extract();
+    const symbols = new GoTransformer(result, config).transform();
+    output = buildOutput(result, config, symbols);
+    index = new HoverIndex(output, root);
+  });
+
+  afterAll(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it("should resolve the identifier under the cursor", async () => {
+    // "return &Client{}" on line 9
+    const hover = await index.hover({ file: "client.go", line: 9, character: 11 });
+
+    expect(hover?.symbol.qualifiedName).toBe("Client");
+    expect(hover?.contents.value).toContain("Client talks to the API.");
+  });
+
+  it("should fall back to the enclosing declaration", async () => {
+    const hover = await index.hover({ file: "client.go", line: 14, character: 2 });
+
+    expect(hover?.symbol.qualifiedName).toBe("Client.Close");
+    expect(hover?.contents.value.startsWith("```go\n")).toBe(true);
+  });
+
+  it("should accept absolute paths", async () => {
+    const hover = await index.hover({
+      file: path.join(root, "client.go"),
+      line: 8,
+      character: 6,
+    });
+
+    expect(hover?.symbol.qualifiedName).toBe("NewClient");
+    expect(hover?.contents.value).toContain("NewClient creates a Client.");
+  });
+
+  it("should load compressed output files", async () => {
+    const file = path.join(root, "symbols.json.zst");
+    await writeFile(file, compress(JSON.stringify(output), "zstd"));
+    const load = watchOutputFile(file, root);
+
+    const hover = await (await load()).hover({ file: "client.go", line: 9, character: 11 });
+    expect(hover?.symbol.qualifiedName).toBe("Client");
+  });
+
+  it("should return null for unknown files", async () => {
+    expect(await index.hover({ file: "missing.go", line: 0, character: 0 })).toBeNull();
+  });
+
+  it("should answer JSON-RPC requests", async () => {
+    const input = new PassThrough();
+    const output = new PassThrough();
+    let written = "";
+    output.on("data", (chunk) => (written += chunk));
+
+    const served = serveHover(input, output, async () => index);
+    const params = { file: "client.go", line: 3, character: 6 };
+    input.write(`${JSON.stringify({ jsonrpc: "2.0", id: 1, method: "hover", params })}\n`);
+    input.write('{"jsonrpc":"2.0","id":2,"method":"definition","params":{}}\n');
+    input.write('{"jsonrpc":"2.0","id":3,"method":"hover","params":{"file":"client.go"}}\n');
+    input.write("not json\n");
+    input.end();
+    await served;
+
+    const responses = written
+      .trim()
+      .split("\n")
+      .map((line) => JSON.parse(line) as JsonRpcResponse);
+    const result = responses[0].result as { symbol: { qualifiedName: string } };
+    expect(responses.map((r) => r.id)).toEqual([1, 2, 3, null]);
+    expect(result.symbol.qualifiedName).toBe("Client");
+    expect(responses.slice(1).map((r) => r.error?.code)).toEqual([-32601, -32602, -32700]);
+  });
+});
@@ -0,0 +1,63 @@
+/**
+ * HTML rendering tests
+ */
+
+import { describe, it, expect } from "vitest";
+
+import { markCallouts, parseDocComment, resolveDocLinks } from "../doc-comment.js";
+import { escapeHtml, renderHtml } from "../html.js";
+
+describe("renderHtml", () => {
+  it("should render paragraphs, headings, lists, and code blocks", () => {
+    const blocks = parseDocComment(
+      "Client talks to the API.\n\n# Usage & Setup\n\n 3. Dial\n 4. Call\n\n" +
+        "For example:\n\n\tif a < b {\n\t\tc.Close()\n\t}",
+    );
+
+    expect(renderHtml(blocks)).toBe(
+      [
+        "<p>Client talks to the API.</p>",
+        '<h3 id="hdr-Usage___Setup">Usage &amp; Setup</h3>',
+        '<ol>\n<li value="3">Dial</li>\n<li value="4">Call</li>\n</ol>',
+        "<p>For example:</p>",
+        '<pre><code class="language-go">if a &lt; b {\n\tc.Close()\n}</code></pre>',
+      ].join("\n"),
+    );
+  });
+
+  it("should link URLs and doc links through the symbol URL mapping", () => {
+    const blocks = resolveDocLinks(
+      parseDocComment('See [Client], [io.Reader], and https://go.dev/?a=1&b=2.'),
+      (target) =>
+        target === "Client" ? { refId: "pkg:Client" } : { url: "https://pkg.go.dev/io#Reader" },
+    );
+
+    expect(renderHtml(blocks, { symbolUrl: (refId) => `/go/${refId}` })).toBe(
+      '<p>See <a href="/go/pkg:Client">Client</a>, ' +
+        '<a href="https://pkg.go.dev/io#Reader">io.Reader</a>, and ' +
+        '<a href="https://go.dev/?a=1&amp;b=2">https://go.dev/?a=1&amp;b=2</a>.</p>',
+    );
+    expect(renderHtml(blocks)).toMatch(/^<p>See Client, <a/);
+  });
+
+  it("should render verbatim blocks as preformatted text", () => {
+    const blocks = parseDocComment("a --> <b>\nc --> d");
+    expect(renderHtml(blocks)).toBe("<pre>a --&gt; &lt;b&gt;\nc --&gt; d</pre>");
+  });
+
+  it("should render callouts as asides", () => {
+    const blocks = markCallouts(parseDocComment("Pool reuses conns. It is not thread-safe."));
+    expect(renderHtml(blocks)).toBe(
+      "<p>Pool reuses conns.</p>\n" +
+        '<aside class="callout callout-concurrency"><p>It is not thread-safe.</p></aside>',
+    );
+  });
+});
+
+describe("escapeHtml", () => {
+  it("should escape markup and quotes", () => {
+    expect(escapeHtml(`<a href="x">'&'</a>`)).toBe(
+      "&lt;a href=&quot;x&quot;&gt;&#39;&amp;&#39;&lt;/a&gt;",
+    );
+  });
+});
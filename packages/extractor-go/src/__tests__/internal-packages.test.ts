@@ -0,0 +1,118 @@
+/**
+ * Internal package policy tests
+ */
+
+import { mkdir, mkdtemp, rm, writeFile } from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect, beforeAll, afterAll } from "vitest";
+
+import { isInternalPath } from "../internal-packages.js";
+import { GoExtractor, type ExtractionResult } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig, validateConfig, type InternalPackagePolicy } from "../config.js";
+
+const CLIENT_SOURCE = `package client
+
+import "example.com/client/internal/auth"
+
+// Client is an API client.
+type Client struct {
+	// Session is the current session.
+	Session *auth.Session
+}
+
+// AuthToken is an access token.
+type AuthToken = auth.Token
+`;
+
+const AUTH_SOURCE = `package auth
+
+// Token is an access token.
+type Token struct{}
+
+// Valid reports whether the token is still valid.
+func (t Token) Valid() bool { return true }
+
+// Session is an authenticated session.
+type Session struct{}
+
+// Secret is never exposed.
+type Secret struct{}
+
+// Sign signs a request.
+func Sign() {}
+`;
+
+describe("isInternalPath", () => {
+  it("should match files in internal directories at any depth", () => {
+    expect(isInternalPath("internal/auth/auth.go")).toBe(true);
+    expect(isInternalPath("client/internal/auth.go")).toBe(true);
+  });
+
+  it("should not match other paths", () => {
+    expect(isInternalPath("client.go")).toBe(false);
+    expect(isInternalPath("internalize/auth.go")).toBe(false);
+    expect(isInternalPath("internal.go")).toBe(false);
+  });
+});
+
+describe("GoTransformer internal package policy", () => {
+  let root: string;
+  let result: ExtractionResult;
+
+  beforeAll(async () => {
+    root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-internal-"));
+    await mkdir(path.join(root, "internal", "auth"), { recursive: true });
+    await writeFile(path.join(root, "client.go"), CLIENT_SOURCE);
+    await writeFile(path.join(root, "internal", "auth", "auth.go"), AUTH_SOURCE);
+
+    const config = createConfig({ packageName: "client", packagePath: root });
+    result = await new GoExtractor(config).extract();
+  });
+
+  afterAll(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  function transform(internalPackages?: InternalPackagePolicy) {
+    const config = createConfig({ packageName: "client", packagePath: root, internalPackages });
+    return new GoTransformer(result, config).transform();
+  }
+
+  it("should include internal symbols with a flag by default", () => {
+    const symbols = transform();
+    expect(symbols.filter((s) => s.internal).map((s) => s.qualifiedName)).toEqual([
+      "Token",
+      "Token.Valid",
+      "Session",
+      "Secret",
+      "Sign",
+    ]);
+    expect(symbols.find((s) => s.name === "Client")!.internal).toBeUndefined();
+  });
+
+  it("should drop internal symbols with exclude", () => {
+    expect(transform("exclude").map((s) => s.qualifiedName)).toEqual(["Client", "AuthToken"]);
+  });
+
+  it("should keep only re-exposed internal symbols and their methods with reexposed", () => {
+    expect(transform("reexposed").map((s) => s.qualifiedName)).toEqual([
+      "Client",
+      "AuthToken",
+      "Token",
+      "Token.Valid",
+      "Session",
+    ]);
+  });
+
+  it("should reject unknown policies", () => {
+    const config = createConfig({
+      packageName: "client",
+      packagePath: root,
+      internalPackages: "hide" as InternalPackagePolicy,
+    });
+    expect(() => validateConfig(config)).toThrow("Unknown internal package policy: hide");
+  });
+});
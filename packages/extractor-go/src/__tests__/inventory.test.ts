@@ -0,0 +1,73 @@
+/**
+ * API inventory tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput, type ExtractorOutput } from "../output.js";
+import { renderInventory } from "../inventory.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+const pkg = "github.com/example/testpkg";
+
+describe("renderInventory", () => {
+  let output: ExtractorOutput;
+  let rows: string[];
+
+  beforeAll(async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    output = buildOutput(result, config, new GoTransformer(result, config).transform());
+    rows = renderInventory(output).trimEnd().split("\n");
+  });
+
+  it("should start with the header row", () => {
+    expect(rows[0]).toBe("package,symbol,kind,signature,deprecated,since,file,line");
+  });
+
+  const row = (name: string) => rows.find((line) => line.split(",")[1] === name);
+
+  it("should write a row per public symbol in canonical order", () => {
+    expect(row("Client.Close")?.split(",")).toEqual([
+      pkg,
+      "Client.Close",
+      "method",
+      "func (c *Client) Close() error",
+      "false",
+      "",
+      "types.go",
+      "49",
+    ]);
+    const names = rows.slice(1).map((line) => line.split(",")[1]);
+    expect(names.indexOf("Client")).toBeLessThan(names.indexOf("Client.Close"));
+    expect(names).not.toContain("baseClient");
+  });
+
+  it("should record deprecation and since", () => {
+    expect(row("Retry")?.split(",").slice(4, 6)).toEqual(["true", ""]);
+    expect(row("Reranker")?.split(",").slice(4, 6)).toEqual(["false", "v0.3.0"]);
+  });
+
+  it("should quote CSV fields holding commas", () => {
+    expect(row("Buffer.Write")).toBe(
+      `${pkg},Buffer.Write,method,"func (b *Buffer) Write(p []byte) (int, error)",` +
+        "false,,assertions.go,21",
+    );
+  });
+
+  it("should write TSV without quoting", () => {
+    const tsv = renderInventory(output, "\t").split("\n");
+    expect(tsv[0]).toBe("package\tsymbol\tkind\tsignature\tdeprecated\tsince\tfile\tline");
+    expect(tsv.find((line) => line.includes("\tBuffer.Write\t"))?.split("\t")[3]).toBe(
+      "func (b *Buffer) Write(p []byte) (int, error)",
+    );
+  });
+});
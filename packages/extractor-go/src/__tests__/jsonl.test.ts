@@ -0,0 +1,60 @@
+/**
+ * JSONL output tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput, type ExtractorOutput } from "../output.js";
+import { summarizeOutput } from "../profile.js";
+import { jsonlRecords, parseJsonl } from "../jsonl.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("jsonlRecords", () => {
+  let output: ExtractorOutput;
+
+  beforeAll(async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    output = buildOutput(result, config, new GoTransformer(result, config).transform());
+  });
+
+  it("should write header records before a record per symbol", () => {
+    const records = [...jsonlRecords(output)].map((line) => JSON.parse(line));
+    const headers = records.slice(0, -output.symbols.length).map((r) => Object.keys(r)[0]);
+
+    expect(headers).toEqual(["package", "capabilities", "coverage"]);
+    expect(records.slice(-output.symbols.length)).toEqual(
+      output.symbols.map((symbol) => ({ symbol })),
+    );
+  });
+
+  it("should write one line per record", () => {
+    for (const record of jsonlRecords(output)) {
+      expect(record).not.toContain("\n");
+    }
+  });
+
+  it("should round-trip through parseJsonl", () => {
+    const text = [...jsonlRecords(output)].join("\n") + "\n";
+    expect(parseJsonl(text)).toEqual(JSON.parse(JSON.stringify(output)));
+
+    const summary = summarizeOutput(output);
+    expect(parseJsonl([...jsonlRecords(summary)].join("\n"))).toEqual(summary);
+  });
+});
+
+describe("parseJsonl", () => {
+  it("should reject records that aren't objects", () => {
+    expect(() => parseJsonl('{"package":{}}\n[1]\n')).toThrow("Invalid JSONL record on line 2");
+    expect(() => parseJsonl("{oops")).toThrow("Invalid JSONL record on line 1");
+  });
+});
@@ -0,0 +1,102 @@
+/**
+ * Localized label tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect } from "vitest";
+
+import { defaultLabels, formatLabel, parseLocaleBundle, resolveLabels } from "../labels.js";
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { buildOutput } from "../output.js";
+import { createConfig, validateConfig, type GoExtractorConfig } from "../config.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+const japanese = {
+  locale: "ja",
+  labels: {
+    constants: "定数",
+    sealedNote: "このインターフェースはシールされています。",
+    sealedImplementations: "実装: {list}。",
+  },
+};
+
+describe("parseLocaleBundle", () => {
+  it("should accept known labels", () => {
+    expect(parseLocaleBundle(japanese)).toEqual(japanese);
+  });
+
+  it("should reject bundles without a locale or with unknown labels", () => {
+    expect(() => parseLocaleBundle({ labels: {} })).toThrow("Locale bundle needs a locale");
+    expect(() => parseLocaleBundle({ locale: "ja", labels: { constant: "定数" } })).toThrow(
+      "Unknown label: constant",
+    );
+  });
+});
+
+describe("resolveLabels", () => {
+  it("should fall back to English for missing keys", () => {
+    const labels = resolveLabels(japanese);
+    expect(labels.constants).toBe("定数");
+    expect(labels.variables).toBe(defaultLabels.variables);
+  });
+});
+
+describe("formatLabel", () => {
+  it("should fill known placeholders and keep unknown ones", () => {
+    expect(formatLabel("{list} and {other}", { list: "`A`" })).toBe("`A` and {other}");
+  });
+});
+
+describe("localized output", () => {
+  async function extract(overrides: Partial<GoExtractorConfig>) {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      ...overrides,
+    });
+    validateConfig(config);
+    const result = await new GoExtractor(config).extract();
+    return buildOutput(result, config, new GoTransformer(result, config).transform());
+  }
+
+  it("should omit labels by default", async () => {
+    expect((await extract({})).labels).toBeUndefined();
+  });
+
+  it("should embed the resolved labels and translate notes", async () => {
+    const output = await extract({ localeBundle: japanese });
+
+    expect(output.labels!.locale).toBe("ja");
+    expect(output.labels!.labels!.constants).toBe("定数");
+    expect(output.labels!.labels!.functions).toBe("Functions");
+
+    const message = output.symbols.find((s) => s.name === "Message")!;
+    expect(message.docs.description).toContain("このインターフェースはシールされています。 実装:");
+  });
+
+  it("should reference the bundle URL instead of embedding it", async () => {
+    const output = await extract({
+      localeBundle: japanese,
+      localeBundleUrl: "https://docs.example.com/locales/ja.json",
+    });
+    expect(output.labels).toEqual({
+      locale: "ja",
+      bundleUrl: "https://docs.example.com/locales/ja.json",
+    });
+  });
+
+  it("should require a bundle for the bundle URL", () => {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      localeBundleUrl: "https://docs.example.com/locales/ja.json",
+    });
+    expect(() => validateConfig(config)).toThrow("localeBundleUrl requires a localeBundle");
+  });
+});
@@ -0,0 +1,69 @@
+/**
+ * Lifecycle grouping tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect } from "vitest";
+
+import { groupLifecycle, isConstructorOf, methodStage } from "../lifecycle.js";
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("methodStage", () => {
+  it("should classify methods by name", () => {
+    expect(methodStage("SetTimeout")).toBe("configure");
+    expect(methodStage("WithRetry")).toBe("configure");
+    expect(methodStage("Close")).toBe("close");
+    expect(methodStage("ShutdownNow")).toBe("close");
+    expect(methodStage("Get")).toBe("use");
+    // Prefixes must end at a word boundary
+    expect(methodStage("Settle")).toBe("use");
+    expect(methodStage("Stopwatch")).toBe("use");
+  });
+});
+
+describe("isConstructorOf", () => {
+  it("should match the first result type", () => {
+    expect(isConstructorOf("*Client", "Client")).toBe(true);
+    expect(isConstructorOf("(*Client, error)", "Client")).toBe(true);
+    expect(isConstructorOf("Cache[K, V]", "Cache")).toBe(true);
+    expect(isConstructorOf("(error, *Client)", "Client")).toBe(false);
+    expect(isConstructorOf("", "Client")).toBe(false);
+  });
+});
+
+describe("groupLifecycle", () => {
+  it("should skip types whose API is a single stage", () => {
+    expect(groupLifecycle([], [{ name: "Get" }, { name: "Post" }])).toBeUndefined();
+  });
+});
+
+describe("GoTransformer lifecycle", () => {
+  it("should group Client's constructor and methods", async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    const symbols = new GoTransformer(result, config).transform();
+    const client = symbols.find((s) => s.name === "Client")!;
+
+    const names = Object.fromEntries(
+      Object.entries(client.lifecycle!).map(([stage, refs]) => [stage, refs.map((r) => r.name)]),
+    );
+    expect(names).toEqual({
+      construct: ["Connect", "NewClient"],
+      configure: ["SetTimeout"],
+      use: ["Get", "Post"],
+      close: ["Close"],
+    });
+    expect(Object.keys(client.lifecycle!)).toEqual(["construct", "configure", "use", "close"]);
+    expect(client.lifecycle!.construct![1].refId).toBe(
+      symbols.find((s) => s.name === "NewClient")!.id,
+    );
+  });
+});
@@ -0,0 +1,126 @@
+/**
+ * Documentation lint tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer, type GoSymbolRecord } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { formatLintIssues, lintPackage, parseLintSeverities } from "../lint.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+function symbol(
+  name: string,
+  kind: string,
+  summary: string,
+  extra: Partial<GoSymbolRecord> = {},
+): GoSymbolRecord {
+  return {
+    id: `pkg:${name}`,
+    name,
+    qualifiedName: name,
+    kind,
+    docs: { summary },
+    source: { path: "client.go", line: 3 },
+    tags: { stability: "stable", visibility: "public" },
+    ...extra,
+  } as GoSymbolRecord;
+}
+
+describe("lintPackage", () => {
+  it("should flag missing docs, wrong prefixes, and a missing package comment", () => {
+    const issues = lintPackage(undefined, [
+      symbol("Client", "class", "A Client talks to the API."),
+      symbol("Dial", "function", "Connects to the server."),
+      symbol("DialContext", "function", "Dial with a context."),
+      symbol("Close", "method", ""),
+      symbol("Timeout", "variable", "default timeout."),
+    ]);
+
+    expect(issues).toEqual([
+      { rule: "package-doc", severity: "error", message: "package has no package comment" },
+      {
+        rule: "doc-prefix",
+        severity: "error",
+        message: 'doc comment of Dial should start with "Dial"',
+        symbolId: "pkg:Dial",
+        source: { path: "client.go", line: 3 },
+      },
+      expect.objectContaining({ rule: "doc-prefix", symbolId: "pkg:DialContext" }),
+      expect.objectContaining({
+        rule: "missing-doc",
+        message: "exported Close has no doc comment",
+      }),
+    ]);
+  });
+
+  it("should skip unexported, deprecated-only, test helper, and generated symbols", () => {
+    const issues = lintPackage("Package client talks to the API.", [
+      symbol("helper", "function", "", { tags: { stability: "stable", visibility: "private" } }),
+      symbol("Old", "function", "", { docs: { summary: "", deprecated: { isDeprecated: true } } }),
+      symbol("Fake", "function", "", { testHelper: true }),
+      symbol("Marshal", "function", "", { generated: true } as Partial<GoSymbolRecord>),
+    ]);
+
+    expect(issues).toEqual([]);
+  });
+
+  it("should apply the configured severities", () => {
+    const issues = lintPackage(
+      undefined,
+      [symbol("Dial", "function", "Connects."), symbol("Close", "method", "")],
+      { "doc-prefix": "warning", "package-doc": "off" },
+    );
+
+    expect(issues.map((issue) => [issue.rule, issue.severity])).toEqual([
+      ["doc-prefix", "warning"],
+      ["missing-doc", "error"],
+    ]);
+  });
+
+  it("should lint the fixture package", async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    const issues = lintPackage(result.packageDoc, new GoTransformer(result, config).transform());
+
+    expect(issues.map((issue) => issue.symbolId)).toEqual([
+      "pkg_go_test_package:Spin",
+      "pkg_go_test_package:LevelWarn",
+      "pkg_go_test_package:DefaultHost",
+    ]);
+  });
+});
+
+describe("parseLintSeverities", () => {
+  it("should parse rule=severity settings", () => {
+    expect(parseLintSeverities(["doc-prefix=warning", " package-doc = off "])).toEqual({
+      "doc-prefix": "warning",
+      "package-doc": "off",
+    });
+  });
+
+  it("should reject unknown rules and severities", () => {
+    expect(() => parseLintSeverities(["typo=off"])).toThrow("Unknown lint rule: typo");
+    expect(() => parseLintSeverities(["missing-doc=fatal"])).toThrow(
+      "Unknown lint severity for missing-doc: fatal",
+    );
+  });
+});
+
+describe("formatLintIssues", () => {
+  it("should print one issue per line with its location", () => {
+    const issues = lintPackage(undefined, [symbol("Close", "method", "")]);
+
+    expect(formatLintIssues(issues)).toBe(
+      "package: error: package has no package comment (package-doc)\n" +
+        "client.go:3: error: exported Close has no doc comment (missing-doc)",
+    );
+  });
+});
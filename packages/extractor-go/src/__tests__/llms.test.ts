@@ -0,0 +1,64 @@
+/**
+ * llms.txt corpus tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput, type ExtractorOutput } from "../output.js";
+import { renderLlmsText } from "../llms.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("renderLlmsText", () => {
+  let output: ExtractorOutput;
+
+  beforeAll(async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    output = buildOutput(result, config, new GoTransformer(result, config).transform());
+  });
+
+  it("should write a section per package with signatures and docs", () => {
+    const text = renderLlmsText(output);
+
+    expect(text).toMatch(/^# test-package\n\n> Package example provides [^\n]*\n\n## test-package\n/);
+    expect(text).toContain(
+      "### Client\n\n```go\ntype Client struct {\n\tBaseURL string\n\tAPIKey string\n" +
+        "\tTimeout int\n}\n```\n\nClient represents a client connection to a service. " +
+        "It handles authentication and request management.\n",
+    );
+    expect(text).toContain(
+      "### ParseConfig\n\n```go\nfunc ParseConfig(path string) (map[string]string, error)\n```" +
+        "\n\nDeprecated: Use LoadConfig instead.\n\n",
+    );
+  });
+
+  it("should leave out unexported symbols and test helpers", () => {
+    const headings = renderLlmsText(output).match(/^### .*$/gm)!;
+    expect(headings.every((heading) => /^### [A-Z]/.test(heading))).toBe(true);
+  });
+
+  it("should be deterministic", () => {
+    const reversed = { ...output, symbols: [...output.symbols].reverse() };
+    expect(renderLlmsText(reversed)).toBe(renderLlmsText(output));
+  });
+
+  it("should drop whole symbols past the size cap", () => {
+    const text = renderLlmsText(output, 600);
+    const omitted = Number(text.match(/\((\d+) more symbols omitted\)\n$/)![1]);
+
+    expect(text.length).toBeLessThan(650);
+    expect(text.match(/^### /gm)!.length + omitted).toBe(
+      renderLlmsText(output).match(/^### /gm)!.length,
+    );
+    expect(text).not.toMatch(/```go\n[^`]*$/);
+  });
+});
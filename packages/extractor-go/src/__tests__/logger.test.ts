@@ -0,0 +1,73 @@
+/**
+ * Logging tests
+ */
+
+import { afterEach, describe, it, expect, vi } from "vitest";
+
+import { Logger } from "../logger.js";
+
+describe("Logger", () => {
+  afterEach(() => {
+    vi.restoreAllMocks();
+  });
+
+  it("should write text messages at or above its level", () => {
+    const log = vi.spyOn(console, "log").mockImplementation(() => {});
+    const warn = vi.spyOn(console, "warn").mockImplementation(() => {});
+    const logger = new Logger();
+
+    logger.debug("hidden");
+    logger.info("shown");
+    logger.warn("careful");
+
+    expect(log.mock.calls).toEqual([["shown"]]);
+    expect(warn.mock.calls).toEqual([["Warning: careful"]]);
+  });
+
+  it("should count warnings and errors it doesn't write", () => {
+    vi.spyOn(console, "warn").mockImplementation(() => {});
+    const logger = new Logger();
+    logger.configure({ level: "silent" });
+
+    logger.warn("one");
+    logger.warn("two");
+    logger.error("three");
+
+    expect(logger.warnings).toBe(2);
+    expect(logger.errors).toBe(1);
+    expect(console.warn).not.toHaveBeenCalled();
+  });
+
+  it("should write JSON lines with fields to stderr", () => {
+    const write = vi.spyOn(process.stderr, "write").mockImplementation(() => true);
+    const logger = new Logger();
+    logger.configure({ format: "json" });
+
+    logger.info("✅ Wrote 3 files", { files: 3 });
+    logger.error("❌ Failed", { error: new Error("boom") });
+
+    const entries = write.mock.calls.map((call) => JSON.parse(String(call[0])));
+    expect(entries[0]).toMatchObject({ level: "info", msg: "Wrote 3 files", files: 3 });
+    expect(entries[0].time).toMatch(/^\d{4}-\d{2}-\d{2}T/);
+    expect(entries[1]).toMatchObject({ level: "error", msg: "Failed", error: { message: "boom" } });
+  });
+
+  it("should log reports as text, or as their fields in JSON", () => {
+    const log = vi.spyOn(console, "log").mockImplementation(() => {});
+    const write = vi.spyOn(process.stderr, "write").mockImplementation(() => true);
+    const logger = new Logger();
+
+    logger.report("Timings", "a | b");
+    expect(log.mock.calls).toEqual([[], ["a | b"]]);
+
+    logger.configure({ format: "json" });
+    logger.report("Timings", "a | b", { total: 2 });
+    expect(JSON.parse(String(write.mock.calls[0][0]))).toMatchObject({ msg: "Timings", total: 2 });
+  });
+
+  it("should reject unknown levels and formats", () => {
+    const logger = new Logger();
+    expect(() => logger.configure({ level: "trace" as never })).toThrow("Unknown log level");
+    expect(() => logger.configure({ format: "xml" as never })).toThrow("Unknown log format");
+  });
+});
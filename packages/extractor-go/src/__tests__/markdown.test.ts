@@ -0,0 +1,69 @@
+/**
+ * Markdown rendering tests
+ */
+
+import { describe, it, expect } from "vitest";
+
+import { markCallouts, parseDocComment, resolveDocLinks } from "../doc-comment.js";
+import { escapeMarkdown, renderMarkdown } from "../markdown.js";
+
+describe("renderMarkdown", () => {
+  it("should render headings, lists, and fenced code blocks", () => {
+    const blocks = parseDocComment(
+      "Client talks to the API.\n\n# Usage\n\nSteps:\n\n 1. Dial\n 2. Call\n\n" +
+        "For example:\n\n\tc := New()\n\tc.Close()",
+    );
+
+    expect(renderMarkdown(blocks)).toBe(
+      "Client talks to the API.\n\n### Usage\n\nSteps:\n\n1. Dial\n2. Call\n\nFor example:\n\n" +
+        "```go\nc := New()\nc.Close()\n```",
+    );
+    expect(renderMarkdown(blocks, { headingLevel: 2 })).toContain("\n\n## Usage\n\n");
+  });
+
+  it("should link URLs, links, and resolved doc links", () => {
+    const blocks = resolveDocLinks(
+      parseDocComment(
+        "See [Client], [io.Reader], [the spec], and https://go.dev.\n\n" +
+          "[the spec]: https://x.dev/a_(b)",
+      ),
+      (target) =>
+        target === "Client" ? { refId: "pkg:Client" } : { url: "https://pkg.go.dev/io#Reader" },
+    );
+
+    expect(renderMarkdown(blocks, { symbolUrl: (refId) => `/go/${refId}` })).toBe(
+      "See [Client](/go/pkg:Client), [io.Reader](https://pkg.go.dev/io#Reader), " +
+        "[the spec](https://x.dev/a_%28b%29), and <https://go.dev>.",
+    );
+    expect(renderMarkdown(blocks)).toMatch(/^See Client, \[io\.Reader\]/);
+  });
+
+  it("should use a fence longer than the backticks in the code", () => {
+    const [block] = parseDocComment("\ts := ```raw```");
+    expect(renderMarkdown([block])).toBe("````go\ns := ```raw```\n````");
+  });
+
+  it("should fence verbatim blocks as text", () => {
+    const blocks = parseDocComment("a  b\n1  2");
+    expect(renderMarkdown(blocks)).toBe("```text\na  b\n1  2\n```");
+  });
+
+  it("should render callouts as GitHub alerts", () => {
+    const blocks = markCallouts(parseDocComment("Security: Tokens are logged in *debug* mode."));
+    expect(renderMarkdown(blocks)).toBe("> [!CAUTION]\n> Tokens are logged in \\*debug\\* mode.");
+  });
+});
+
+describe("escapeMarkdown", () => {
+  it("should escape formatting characters", () => {
+    expect(escapeMarkdown("a_b *c* `d` [e] <f> \\")).toBe(
+      "a\\_b \\*c\\* \\`d\\` \\[e\\] \\<f> \\\\",
+    );
+  });
+
+  it("should escape line-start markers only at the start of a line", () => {
+    expect(escapeMarkdown("# not a heading")).toBe("\\# not a heading");
+    expect(escapeMarkdown("1. not a list")).toBe("1\\. not a list");
+    expect(escapeMarkdown("- x", false)).toBe("- x");
+  });
+});
@@ -0,0 +1,77 @@
+/**
+ * MDX page tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput } from "../output.js";
+import { escapeMdx, renderMdx } from "../mdx.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("renderMdx", () => {
+  let pages: Map<string, string>;
+
+  beforeAll(async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    pages = renderMdx(buildOutput(result, config, new GoTransformer(result, config).transform()));
+  });
+
+  it("should write a package page listing types and functions", () => {
+    const index = pages.get("index.mdx")!;
+
+    expect(index).toMatch(
+      /^---\ntitle: "test-package"\nslug: "\/"\nkind: "package"\ndescription: "Package example/,
+    );
+    expect(index).toContain("\n## Types\n\n- [AIMessage](./AIMessage) — AIMessage is a message");
+    expect(index).toContain("\n## Functions\n\n");
+    expect(index).toContain("\n## Constants\n\n");
+    expect(index).toContain("\n### ErrNotFound\n\n```go\nvar ErrNotFound");
+  });
+
+  it("should write a page per type and function with front matter", () => {
+    expect(pages.has("Client.mdx")).toBe(true);
+    expect(pages.has("NewClient.mdx")).toBe(true);
+    expect(pages.has("Client.Get.mdx")).toBe(false);
+
+    expect(pages.get("Reranker.mdx")).toMatch(
+      /^---\ntitle: "Reranker"\nslug: "\/Reranker"\nkind: "class"\nsince: "v0.3.0"\n/,
+    );
+    expect(pages.get("NewClient.mdx")).not.toContain("since:");
+  });
+
+  it("should document fields and methods on the type page", () => {
+    const client = pages.get("Client.mdx")!;
+
+    expect(client).toContain("## Fields\n\n### BaseURL\n\n```go\nBaseURL string\n```");
+    expect(client).toContain("## Methods\n\n### Close\n\n```go\nfunc (c *Client) Close(");
+  });
+
+  it("should link doc links to pages and method anchors", () => {
+    expect(pages.get("Buffer.mdx")).toContain(
+      "a [Closer](./Closer): call [Buffer.Write](./Buffer#write) to append",
+    );
+  });
+});
+
+describe("escapeMdx", () => {
+  it("should escape braces and angle brackets outside code", () => {
+    expect(escapeMdx("Use map[string]{} or <T> with `x{}` and \\<b>")).toBe(
+      "Use map[string]\\{\\} or \\<T> with `x{}` and \\<b>",
+    );
+    expect(escapeMdx("```go\nfunc() {}\n```\n{a}")).toBe("```go\nfunc() {}\n```\n\\{a\\}");
+  });
+
+  it("should turn autolinks into links", () => {
+    expect(escapeMdx("See <https://go.dev>.")).toBe("See [https://go.dev](https://go.dev).");
+  });
+});
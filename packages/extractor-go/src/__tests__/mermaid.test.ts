@@ -0,0 +1,61 @@
+/**
+ * Mermaid diagram tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput } from "../output.js";
+import { renderMermaid } from "../mermaid.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("renderMermaid", () => {
+  let diagrams: Map<string, string>;
+  let diagram: string;
+
+  beforeAll(async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    const output = buildOutput(result, config, new GoTransformer(result, config).transform());
+    diagrams = renderMermaid(output);
+    diagram = diagrams.get("github.com/example/testpkg")!;
+  });
+
+  it("should render a class diagram per package", () => {
+    expect([...diagrams.keys()]).toEqual(["github.com/example/testpkg"]);
+    expect(diagram.startsWith("classDiagram\n")).toBe(true);
+    expect(diagram).toContain("  class Client {\n    <<struct>>\n  }");
+    expect(diagram).toContain("  class Closer {\n    <<interface>>\n  }");
+  });
+
+  it("should draw embedding and interface satisfaction", () => {
+    expect(diagram).toContain("  Buffer <|-- Store : embeds");
+    expect(diagram).toContain("  Closer <|-- ReadWriteCloser : embeds");
+    expect(diagram).toContain("  Closer <|.. Buffer : implements");
+    expect(diagram).toContain("  io_Writer <|.. Buffer : implements");
+    expect(diagram).not.toContain("<|-- Options");
+  });
+
+  it("should label types of other packages with their qualified name", () => {
+    expect(diagram).toContain('  class io_Writer["io.Writer"]');
+  });
+
+  it("should draw constructors", () => {
+    expect(diagram).toContain("  class NewClient {\n    <<constructor>>\n  }");
+    expect(diagram).toContain("  NewClient ..> Client : creates");
+    expect(diagram).toContain("  Connect ..> Client : creates");
+  });
+
+  it("should leave out unexported types", () => {
+    expect(diagram).not.toMatch(/\bbaseClient\b/);
+    expect(diagram).not.toMatch(/\bsystemMessage\b/);
+  });
+});
@@ -0,0 +1,94 @@
+/**
+ * Package cache tests
+ */
+
+import { mkdir, mkdtemp, readdir, writeFile } from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect } from "vitest";
+
+import { PackageCache } from "../package-cache.js";
+import { GoExtractor, type ParsedFile } from "../extractor.js";
+import { createConfig } from "../config.js";
+
+describe("PackageCache", () => {
+  it("should key packages by their sources, options, and extractor version", () => {
+    const cache = new PackageCache(os.tmpdir(), "1.0.0");
+    const a = { file: "a.go", content: "package a" };
+    const b = { file: "b.go", content: "package a\n" };
+    const key = cache.key([a, b], [true]);
+
+    expect(cache.key([b, a], [true])).toBe(key);
+    expect(cache.key([a, { ...b, content: "package a\n\n" }], [true])).not.toBe(key);
+    expect(cache.key([a, { ...b, file: "c.go" }], [true])).not.toBe(key);
+    expect(cache.key([a, b], [false])).not.toBe(key);
+    expect(new PackageCache(os.tmpdir(), "1.0.1").key([a, b], [true])).not.toBe(key);
+  });
+
+  it("should store and read back parsed files", async () => {
+    const dir = await mkdtemp(path.join(os.tmpdir(), "package-cache-"));
+    const cache = new PackageCache(path.join(dir, "cache"), "1.0.0");
+    const parsed = { types: [], functions: [], constants: [], receiverMethods: [] } as ParsedFile;
+
+    expect(await cache.get("key")).toBeUndefined();
+    await cache.set("key", { "a.go": parsed });
+    expect(await cache.get("key")).toEqual({ "a.go": parsed });
+    expect(cache.stats()).toEqual({ hits: 1, misses: 1 });
+    expect(await readdir(path.join(dir, "cache"))).toEqual(["key.json"]);
+  });
+});
+
+describe("GoExtractor with a package cache", () => {
+  it("should reuse unchanged packages and parse changed ones", async () => {
+    const root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-package-cache-"));
+    await mkdir(path.join(root, "client"));
+    await writeFile(path.join(root, "go.mod"), "module example.com/m\n");
+    await writeFile(path.join(root, "m.go"), "package m\n\n// Run runs.\nfunc Run() {}\n");
+    await writeFile(
+      path.join(root, "client", "client.go"),
+      "package client\n\n// Client calls the API.\ntype Client struct{}\n",
+    );
+    const cacheDir = path.join(root, ".cache");
+    const config = createConfig({ packageName: "m", packagePath: root });
+    const extract = async () => {
+      const cache = new PackageCache(cacheDir, "1.0.0");
+      const extractor = new GoExtractor(config, undefined, undefined, undefined, cache);
+      return { result: await extractor.extract(), stats: cache.stats() };
+    };
+
+    const first = await extract();
+    expect(first.stats).toEqual({ hits: 0, misses: 2 });
+
+    const second = await extract();
+    expect(second.stats).toEqual({ hits: 2, misses: 0 });
+    expect(second.result.types).toEqual(first.result.types);
+    expect(second.result.functions).toEqual(first.result.functions);
+
+    await writeFile(path.join(root, "m.go"), "package m\n\n// Run runs twice.\nfunc Run() {}\n");
+    const third = await extract();
+    expect(third.stats).toEqual({ hits: 1, misses: 1 });
+    expect(third.result.functions.find((f) => f.name === "Run")?.doc).toBe("Run runs twice.");
+  });
+
+  it("should parse packages again when the note markers change", async () => {
+    const root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-package-cache-"));
+    await writeFile(path.join(root, "go.mod"), "module example.com/m\n");
+    await writeFile(
+      path.join(root, "m.go"),
+      "package m\n\n// TODO(ann): run faster.\nfunc Run() {}\n",
+    );
+    const cacheDir = path.join(root, ".cache");
+    const extract = async (noteMarkers?: string[]) => {
+      const config = createConfig({ packageName: "m", packagePath: root, noteMarkers });
+      const cache = new PackageCache(cacheDir, "1.0.0");
+      const extractor = new GoExtractor(config, undefined, undefined, undefined, cache);
+      return { result: await extractor.extract(), stats: cache.stats() };
+    };
+
+    expect((await extract()).result.notes).toBeUndefined();
+    const todo = await extract(["TODO"]);
+    expect(todo.stats).toEqual({ hits: 0, misses: 1 });
+    expect(todo.result.notes).toMatchObject([{ marker: "TODO", body: "run faster." }]);
+  });
+});
@@ -0,0 +1,97 @@
+/**
+ * Package pattern tests
+ */
+
+import fs from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import {
+  normalizeExcludeGlob,
+  packageIncludePatterns,
+  packagePatternMatcher,
+  resolvePackagePatterns,
+} from "../packages.js";
+
+describe("package patterns", () => {
+  let root: string;
+
+  beforeAll(async () => {
+    root = await fs.mkdtemp(path.join(os.tmpdir(), "packages-"));
+    const files = [
+      "go.mod",
+      "client.go",
+      "llms/llms.go",
+      "llms/openai/openai.go",
+      "llms/openai/openai_test.go",
+      "llms/docs/README.md",
+      "tools/tools_test.go",
+      "internal/gen/zz_generated.go",
+      "vendor/example.com/dep/dep.go",
+      "testdata/fixture.go",
+      "_examples/main.go",
+      "contrib/go.mod",
+      "contrib/contrib.go",
+    ];
+    for (const file of files) {
+      await fs.mkdir(path.dirname(path.join(root, file)), { recursive: true });
+      const content = file.endsWith("go.mod") ? "module example.com/app\n" : "package x\n";
+      await fs.writeFile(path.join(root, file), content);
+    }
+  });
+
+  it("should expand ./... to every package of the module", async () => {
+    expect(await resolvePackagePatterns(root, ["./..."])).toEqual([
+      ".",
+      "internal/gen",
+      "llms",
+      "llms/openai",
+      "tools",
+    ]);
+  });
+
+  it("should resolve subtrees and single packages by relative or import path", async () => {
+    expect(await resolvePackagePatterns(root, ["./llms/..."])).toEqual(["llms", "llms/openai"]);
+    expect(await resolvePackagePatterns(root, ["example.com/app/llms/..."])).toEqual([
+      "llms",
+      "llms/openai",
+    ]);
+    expect(await resolvePackagePatterns(root, ["example.com/app", "./tools"])).toEqual([
+      ".",
+      "tools",
+    ]);
+  });
+
+  it("should reject patterns outside the module or matching nothing", async () => {
+    await expect(resolvePackagePatterns(root, ["./missing/..."])).rejects.toThrow(
+      "Package pattern ./missing/... matched no packages",
+    );
+    await expect(resolvePackagePatterns(root, ["./contrib"])).rejects.toThrow(
+      "matched no packages",
+    );
+    await expect(resolvePackagePatterns(root, ["example.com/other"])).rejects.toThrow(
+      "is outside module example.com/app",
+    );
+    await expect(resolvePackagePatterns(root, ["../..."])).rejects.toThrow(
+      "is outside the module",
+    );
+  });
+
+  it("should match ... anywhere in a pattern", () => {
+    const match = packagePatternMatcher("./llms/.../v2", "example.com/app");
+
+    expect(match("llms/openai/v2")).toBe(true);
+    expect(match("llms/v2")).toBe(false);
+    expect(match("llms/openai")).toBe(false);
+  });
+
+  it("should build include and exclude globs", () => {
+    expect(packageIncludePatterns([".", "llms"])).toEqual(["*.go", "llms/*.go"]);
+    expect(normalizeExcludeGlob("*.pb.go")).toBe("**/*.pb.go");
+    expect(normalizeExcludeGlob("zz_generated*")).toBe("**/zz_generated*");
+    expect(normalizeExcludeGlob("internal/gen/")).toBe("internal/gen/**");
+    expect(normalizeExcludeGlob("api/*.go")).toBe("api/*.go");
+  });
+});
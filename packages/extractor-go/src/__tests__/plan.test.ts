@@ -0,0 +1,104 @@
+/**
+ * Dry run tests
+ */
+
+import fs from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect } from "vitest";
+
+import type { ExtractorOutput } from "../output.js";
+import { formatBytes, formatPlan, planDeletions, planFiles, plannedPackages } from "../plan.js";
+
+async function tree(files: Record<string, string>): Promise<string> {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), "plan-"));
+  for (const [file, content] of Object.entries(files)) {
+    await fs.mkdir(path.dirname(path.join(dir, file)), { recursive: true });
+    await fs.writeFile(path.join(dir, file), content);
+  }
+  return dir;
+}
+
+describe("planFiles", () => {
+  it("should compare staged files with their targets", async () => {
+    const staged = await tree({ "a.json": "same", "b.json": "new", "sub/c.json": "created" });
+    const target = await tree({ "a.json": "same", "b.json": "old", "stale.json": "kept" });
+
+    expect(await planFiles(staged, target)).toEqual([
+      { path: path.join(target, "a.json"), action: "unchanged", bytes: 4 },
+      { path: path.join(target, "b.json"), action: "update", bytes: 3 },
+      { path: path.join(target, "sub/c.json"), action: "create", bytes: 7 },
+    ]);
+  });
+
+  it("should plan single files and nothing for missing staging paths", async () => {
+    const staged = await tree({ "out.json": "{}" });
+    const target = await tree({});
+
+    expect(await planFiles(path.join(staged, "out.json"), path.join(target, "x.json"))).toEqual([
+      { path: path.join(target, "x.json"), action: "create", bytes: 2 },
+    ]);
+    expect(await planFiles(path.join(staged, "missing"), target)).toEqual([]);
+  });
+
+  it("should only plan deletions of existing files", async () => {
+    const dir = await tree({ "stale.json": "12345" });
+
+    expect(
+      await planDeletions([path.join(dir, "stale.json"), path.join(dir, "gone.json")]),
+    ).toEqual([{ path: path.join(dir, "stale.json"), action: "delete", bytes: 5 }]);
+  });
+});
+
+describe("formatPlan", () => {
+  it("should count symbols per package", () => {
+    const output = {
+      package: { modulePath: "example.com/app", publishedName: "app" },
+      symbols: [
+        { source: { path: "app.go" } },
+        { source: { path: "store/store.go" } },
+        { source: { path: "store/get.go" } },
+      ],
+    } as unknown as ExtractorOutput;
+
+    expect(plannedPackages(output)).toEqual([
+      { importPath: "example.com/app", symbols: 1 },
+      { importPath: "example.com/app/store", symbols: 2 },
+    ]);
+    expect(plannedPackages(output, ["store"])).toEqual([
+      { importPath: "example.com/app/store", symbols: 2 },
+    ]);
+  });
+
+  it("should format sizes with binary units", () => {
+    expect(formatBytes(512)).toBe("512 B");
+    expect(formatBytes(1536)).toBe("1.5 KiB");
+    expect(formatBytes(3 * 1024 * 1024)).toBe("3.0 MiB");
+  });
+
+  it("should list packages, files, and totals", () => {
+    const plan = formatPlan(
+      [{ importPath: "example.com/app", symbols: 3 }],
+      [
+        { path: "out/example.com/app.json", action: "update", bytes: 2048 },
+        { path: "out/manifest.json", action: "unchanged", bytes: 100 },
+        { path: "out/example.com/app/old.json", action: "delete", bytes: 10 },
+      ],
+    );
+
+    expect(plan).toBe(
+      [
+        "Packages to extract (1):",
+        "  example.com/app (3 symbols)",
+        "",
+        "Files (3):",
+        "  update      2.0 KiB  out/example.com/app.json",
+        "  unchanged     100 B  out/manifest.json",
+        "  delete         10 B  out/example.com/app/old.json",
+        "",
+        "0 to create, 1 to update, 1 unchanged, 1 to delete; 2.0 KiB to write",
+      ].join("\n"),
+    );
+  });
+});
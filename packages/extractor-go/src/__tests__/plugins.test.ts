@@ -0,0 +1,155 @@
+/**
+ * Transform plugin tests
+ */
+
+import fs from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { afterEach, describe, it, expect, vi } from "vitest";
+import type { SymbolRecord } from "@langchain/ir-schema";
+
+import { createConfig } from "../config.js";
+import { logger } from "../logger.js";
+import { applyPlugins, parsePluginResponse, pluginCommand } from "../plugins.js";
+import type { ExtractorOutput } from "../output.js";
+
+const config = createConfig({ packageName: "example", packagePath: "." });
+
+function output(names: string[]): ExtractorOutput {
+  const symbols = names.map(
+    (name) =>
+      ({
+        id: `pkg_go_example:${name}`,
+        name,
+        qualifiedName: name,
+        kind: "function",
+        source: { path: "example.go", line: 1 },
+        docs: { summary: "" },
+        tags: { stability: "stable", visibility: "public" },
+      }) as SymbolRecord,
+  );
+  return { package: { packageId: "pkg_go_example" }, symbols } as ExtractorOutput;
+}
+
+/**
+ * Write a Node.js script as an executable plugin.
+ */
+async function writePlugin(dir: string, name: string, body: string): Promise<string> {
+  const file = path.join(dir, name);
+  await fs.writeFile(
+    file,
+    `#!${process.execPath}\n` +
+      `let input = "";\n` +
+      `process.stdin.on("data", (chunk) => (input += chunk));\n` +
+      `process.stdin.on("end", () => {\n${body}\n});\n`,
+  );
+  await fs.chmod(file, 0o755);
+  return file;
+}
+
+describe("applyPlugins", () => {
+  afterEach(() => {
+    vi.restoreAllMocks();
+  });
+
+  it("should run plugins in order over the symbols", async () => {
+    const dir = await fs.mkdtemp(path.join(os.tmpdir(), "plugins-"));
+    const filter = await writePlugin(
+      dir,
+      "filter.js",
+      `const symbols = JSON.parse(input).symbols.filter((s) => s.name !== "Debug");
+       process.stdout.write(JSON.stringify({ symbols }));`,
+    );
+    const enrich = await writePlugin(
+      dir,
+      "enrich.js",
+      `const request = JSON.parse(input);
+       console.error("version " + request.version);
+       for (const s of request.symbols) if (s.name.startsWith("Beta")) s.tags.stability = "beta";
+       request.package.synopsis = "Enriched.";
+       process.stdout.write(JSON.stringify(request));`,
+    );
+
+    const warn = vi.spyOn(logger, "warn").mockImplementation(() => {});
+    const result = await applyPlugins(
+      output(["Run", "Debug", "BetaRun"]),
+      [filter, enrich],
+      config,
+    );
+
+    expect(result.symbols.map((s) => [s.name, s.tags.stability])).toEqual([
+      ["BetaRun", "beta"],
+      ["Run", "stable"],
+    ]);
+    expect(result.package.synopsis).toBe("Enriched.");
+    expect(warn.mock.calls).toEqual([[`Plugin ${enrich}: version 1`]]);
+  });
+
+  it("should rebuild categories and coverage from the symbols plugins return", async () => {
+    const dir = await fs.mkdtemp(path.join(os.tmpdir(), "plugins-"));
+    const categorize = await writePlugin(
+      dir,
+      "categorize.js",
+      `const request = JSON.parse(input);
+       const symbols = request.symbols.filter((s) => s.name !== "Debug");
+       for (const s of symbols) {
+         s.category = "Runners";
+         s.docs = { summary: s.name + " runs." };
+       }
+       process.stdout.write(JSON.stringify({ symbols }));`,
+    );
+
+    const result = await applyPlugins(output(["Run", "Debug", "BetaRun"]), [categorize], config);
+
+    expect(result.package.categories).toEqual([
+      { name: "Runners", symbolIds: ["pkg_go_example:BetaRun", "pkg_go_example:Run"] },
+    ]);
+    expect(result.coverage).toMatchObject({ documented: 2, total: 2 });
+  });
+
+  it("should fail with the plugin's stderr", async () => {
+    const dir = await fs.mkdtemp(path.join(os.tmpdir(), "plugins-"));
+    const failing = await writePlugin(
+      dir,
+      "failing.js",
+      `console.error("no stability.yaml"); process.exit(3);`,
+    );
+    const silent = await writePlugin(dir, "silent.js", `process.stdout.write("[]");`);
+
+    await expect(applyPlugins(output(["Run"]), [failing], config)).rejects.toThrow(
+      `Plugin ${failing} exited with code 3: no stability.yaml`,
+    );
+    await expect(applyPlugins(output(["Run"]), [silent], config)).rejects.toThrow(
+      "must write an object with a symbols array",
+    );
+    const missing = path.join(dir, "missing");
+    await expect(applyPlugins(output(["Run"]), [missing], config)).rejects.toThrow(
+      "failed to start",
+    );
+  });
+
+  it("should leave the output alone without plugins", async () => {
+    const input = output(["Run"]);
+    expect(await applyPlugins(input, [], config)).toBe(input);
+  });
+});
+
+describe("pluginCommand", () => {
+  it("should run Go files with go run", () => {
+    expect(pluginCommand("/tools/stability.go")).toEqual({
+      command: "go",
+      args: ["run", "/tools/stability.go"],
+    });
+    expect(pluginCommand("/tools/stability")).toEqual({ command: "/tools/stability", args: [] });
+  });
+});
+
+describe("parsePluginResponse", () => {
+  it("should reject invalid responses", () => {
+    expect(() => parsePluginResponse("{")).toThrow("wrote invalid JSON");
+    expect(() => parsePluginResponse('{"symbols":[{}]}')).toThrow("records with an id");
+    expect(() => parsePluginResponse('{"symbols":[{"id":"a"}]}')).toThrow("a source path");
+    expect(() => parsePluginResponse('{"symbols":[],"package":{}}')).toThrow("packageId");
+  });
+});
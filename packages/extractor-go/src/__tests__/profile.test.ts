@@ -0,0 +1,161 @@
+/**
+ * Extraction profile tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput, packageSynopsis, type ExtractorOutput } from "../output.js";
+import { applyProfile, signaturesOutput, summarizeOutput } from "../profile.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("packageSynopsis", () => {
+  it("should return the first sentence of the first paragraph", () => {
+    expect(packageSynopsis("Package client talks to the API.\nIt retries on failure.")).toBe(
+      "Package client talks to the API.",
+    );
+  });
+
+  it("should join wrapped lines", () => {
+    expect(packageSynopsis("Package client talks to\nthe LangSmith API. More.")).toBe(
+      "Package client talks to the LangSmith API.",
+    );
+  });
+
+  it("should return undefined without a doc", () => {
+    expect(packageSynopsis(undefined)).toBeUndefined();
+    expect(packageSynopsis("")).toBeUndefined();
+  });
+
+  it("should not end the sentence after an initial", () => {
+    expect(packageSynopsis("Package jwt implements RFC 7519 by J. Smith. It signs.")).toBe(
+      "Package jwt implements RFC 7519 by J. Smith.",
+    );
+  });
+
+  it("should stop at the end of the first paragraph", () => {
+    expect(packageSynopsis("Package client has no period\n\nMore text. Here.")).toBe(
+      "Package client has no period",
+    );
+  });
+
+  it("should render doc links as their text", () => {
+    expect(packageSynopsis("Package store wraps [sql.DB] for [Client] use.")).toBe(
+      "Package store wraps sql.DB for Client use.",
+    );
+  });
+
+  it("should skip license headers and comments without a leading paragraph", () => {
+    expect(packageSynopsis("Copyright 2026 The Authors. All rights reserved.")).toBeUndefined();
+    expect(packageSynopsis("# Overview\n\nPackage x does things.")).toBeUndefined();
+  });
+});
+
+describe("package overview", () => {
+  it("should emit the whole package comment as Markdown and blocks", async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    const output = buildOutput(result, config, new GoTransformer(result, config).transform());
+
+    expect(output.package.synopsis).toBe(
+      "Package example provides example Go APIs for testing the extractor.",
+    );
+    expect(output.package.overview).toMatch(
+      /^Package example provides .*\n\nThe package covers types, functions, constants/s,
+    );
+    expect(output.package.overviewBlocks!.map((b) => b.kind)).toEqual(["paragraph", "paragraph"]);
+  });
+});
+
+describe("package notes", () => {
+  it("should group notes by marker in the package header", async () => {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      noteMarkers: ["BUG", "TODO"],
+    });
+    const result = await new GoExtractor(config).extract();
+    const output = buildOutput(result, config, new GoTransformer(result, config).transform());
+
+    expect(Object.keys(output.package.notes!)).toEqual(["TODO", "BUG"]);
+    expect(output.package.notes!.BUG).toEqual([
+      {
+        uid: "cbromann",
+        body: "Ping ignores the context deadline\nand waits for the full network timeout.",
+        source: { repo: "", sha: "", path: "functions.go", line: 46 },
+      },
+    ]);
+  });
+});
+
+describe("extraction profiles", () => {
+  let full: ExtractorOutput;
+
+  beforeAll(async () => {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      exportedOnly: false,
+    });
+    const result = await new GoExtractor(config).extract();
+    full = buildOutput(result, config, new GoTransformer(result, config).transform());
+  });
+
+  it("should return the full output unchanged for the full profile", () => {
+    expect(applyProfile(full, "full")).toBe(full);
+  });
+
+  it("should keep names, kinds, signatures, and docs without examples", () => {
+    const summary = summarizeOutput(full);
+    const record = full.symbols.find((s) => s.id === "pkg_go_test_package:Client")!;
+    const client = summary.symbols.find((s) => s.id === "pkg_go_test_package:Client");
+    expect(client).toEqual({
+      id: "pkg_go_test_package:Client",
+      name: "Client",
+      qualifiedName: "Client",
+      kind: "class",
+      summary: "Client represents a client connection to a service.",
+      description: record.docs.description,
+      signature: record.signature,
+    });
+    expect(client?.signature).toMatch(/^type Client struct/);
+  });
+
+  it("should include methods by qualified name", () => {
+    const summary = summarizeOutput(full);
+    expect(summary.symbols.map((s) => s.qualifiedName)).toContain("Client.Get");
+  });
+
+  it("should drop unexported symbols", () => {
+    const summary = summarizeOutput(full);
+    expect(full.symbols.some((s) => s.name === "unexportedConst")).toBe(true);
+    expect(summary.symbols.some((s) => s.name === "unexportedConst")).toBe(false);
+  });
+
+  it("should keep the package header with its synopsis but not the overview", () => {
+    const summary = applyProfile(full, "summary");
+    expect(summary.package.packageId).toBe(full.package.packageId);
+    expect(summary.package.synopsis).toMatch(/^Package example provides/);
+    expect(full.package.overview).toBeDefined();
+    expect(summary.package.overview).toBeUndefined();
+    expect(summary.package.overviewBlocks).toBeUndefined();
+  });
+
+  it("should keep only names, kinds, and signatures for the signatures profile", () => {
+    const signatures = signaturesOutput(full);
+    const get = signatures.symbols.find((s) => s.qualifiedName === "Client.Get");
+    expect(get?.signature).toMatch(/Get\(ctx context\.Context, path string\) \(\[\]byte, error\)/);
+    expect(get?.summary).toBeUndefined();
+    expect(signatures.symbols.some((s) => s.name === "unexportedConst")).toBe(false);
+    expect(signatures.package.synopsis).toBeUndefined();
+    expect(applyProfile(full, "signatures")).toEqual(signatures);
+  });
+});
@@ -0,0 +1,124 @@
+/**
+ * Progress reporting tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { afterEach, describe, it, expect, vi } from "vitest";
+
+import {
+  ProgressReporter,
+  formatDuration,
+  formatProgress,
+  formatProgressBar,
+} from "../progress.js";
+import { logger } from "../logger.js";
+import { GoExtractor } from "../extractor.js";
+import { createConfig } from "../config.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+function stream(isTTY: boolean) {
+  const chunks: string[] = [];
+  return { chunks, isTTY, columns: 80, write: (chunk: string) => chunks.push(chunk) > 0 };
+}
+
+describe("formatProgress", () => {
+  it("should show the packages completed, the current package, and the ETA", () => {
+    const state = { completed: 12, total: 340, package: "internal/auth", etaMs: 80000 };
+    expect(formatProgress(state)).toBe("12/340 packages, internal/auth, ETA 1m 20s");
+    expect(formatProgress({ completed: 0, total: 3, package: "" })).toBe("0/3 packages, .");
+    expect(formatProgress({ completed: 3, total: 3, package: "x", etaMs: 0 })).toBe("3/3 packages");
+  });
+
+  it("should draw a bar that fits the terminal", () => {
+    const line = formatProgressBar({ completed: 1, total: 4, package: "a" });
+    expect(line).toBe("[######------------------] 25% 1/4 packages, a");
+
+    const long = formatProgressBar({ completed: 1, total: 4, package: "a/".repeat(50) }, 40);
+    expect(long).toHaveLength(39);
+    expect(long.endsWith("…")).toBe(true);
+  });
+
+  it("should round durations up to whole seconds", () => {
+    expect(formatDuration(1)).toBe("1s");
+    expect(formatDuration(59500)).toBe("1m 0s");
+    expect(formatDuration(3725000)).toBe("1h 2m");
+  });
+});
+
+describe("ProgressReporter", () => {
+  afterEach(() => {
+    vi.restoreAllMocks();
+  });
+
+  it("should redraw the bar in place and clear it when finished", () => {
+    let now = 0;
+    const tty = stream(true);
+    const progress = new ProgressReporter({ stream: tty, now: () => now });
+    expect(progress.mode).toBe("bar");
+
+    progress.start(2);
+    progress.begin("a");
+    now = 50;
+    progress.complete();
+    now = 200;
+    progress.begin("b");
+    progress.finish();
+
+    expect(tty.chunks).toEqual([
+      "\r[------------------------] 0% 0/2 packages, a\x1b[K",
+      "\r[############------------] 50% 1/2 packages, b, ETA 1s\x1b[K",
+      "\r\x1b[K",
+    ]);
+  });
+
+  it("should log events at an interval when not on a terminal", () => {
+    const info = vi.spyOn(logger, "info").mockImplementation(() => {});
+    let now = 0;
+    const progress = new ProgressReporter({
+      stream: stream(false),
+      interval: 1000,
+      now: () => now,
+    });
+    expect(progress.mode).toBe("events");
+
+    progress.start(4);
+    progress.begin("a");
+    progress.complete();
+    now = 1000;
+    progress.begin("b");
+    now = 1500;
+    progress.complete();
+    progress.finish();
+
+    expect(info.mock.calls).toEqual([
+      [
+        "⏳ 1/4 packages, b, ETA 3s",
+        { event: "progress", completed: 1, total: 4, package: "b", etaMs: 3000 },
+      ],
+      [
+        "⏳ 2/4 packages, b, ETA 2s",
+        { event: "progress", completed: 2, total: 4, package: "b", etaMs: 1500 },
+      ],
+    ]);
+  });
+
+  it("should count every extracted package", async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const progress = new ProgressReporter({ stream: stream(false) });
+    const complete = vi.spyOn(progress, "complete");
+    const finish = vi.spyOn(progress, "finish");
+
+    await new GoExtractor(config, undefined, undefined, progress).extract();
+
+    const { completed, total } = progress.state();
+    expect(total).toBeGreaterThan(0);
+    expect(completed).toBe(total);
+    expect(complete).toHaveBeenCalledTimes(total);
+    expect(finish).toHaveBeenCalledOnce();
+  });
+});
@@ -0,0 +1,47 @@
+/**
+ * Protobuf tests
+ */
+
+import { describe, it, expect } from "vitest";
+
+import {
+  ProtoWriter,
+  protoMessages,
+  protoString,
+  protoStrings,
+  protoVarint,
+  readProtoFields,
+} from "../protobuf.js";
+
+describe("readProtoFields", () => {
+  it("should read back what ProtoWriter writes", () => {
+    const bytes = new ProtoWriter()
+      .string(1, "first")
+      .string(1, "héllo")
+      .varint(2, 300)
+      .strings(3, ["a", ""])
+      .message(4, new ProtoWriter().string(1, "inner"))
+      .message(4, new ProtoWriter().varint(2, 7))
+      .finish();
+    const fields = readProtoFields(bytes);
+
+    expect(protoString(fields, 1)).toBe("héllo");
+    expect(protoVarint(fields, 2)).toBe(300);
+    expect(protoStrings(fields, 3)).toEqual(["a", ""]);
+    expect(protoMessages(fields, 4).map((m) => [protoString(m, 1), protoVarint(m, 2)])).toEqual([
+      ["inner", 0],
+      ["", 7],
+    ]);
+    expect(protoString(fields, 9)).toBe("");
+  });
+
+  it("should skip fixed-width fields and reject truncated messages", () => {
+    // Field 1 as fixed64, then field 2 as a varint
+    const bytes = Uint8Array.from([0x09, 1, 2, 3, 4, 5, 6, 7, 8, 0x10, 5]);
+    expect(readProtoFields(bytes)).toEqual([{ field: 2, value: 5 }]);
+
+    expect(() => readProtoFields(Uint8Array.from([0x0a, 5, 1]))).toThrow(
+      "Truncated protobuf field",
+    );
+  });
+});
@@ -0,0 +1,78 @@
+/**
+ * Provenance tests
+ */
+
+import fs from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput, type ExtractorOutput } from "../output.js";
+import { collectProvenance, provenanceTimestamp, withProvenance } from "../provenance.js";
+import { validateOutput } from "../schema.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("provenance", () => {
+  let output: ExtractorOutput;
+
+  beforeAll(async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    output = buildOutput(result, config, new GoTransformer(result, config).transform());
+  });
+
+  it("should record the extractor, schema, module, and platform", async () => {
+    const env = { SOURCE_DATE_EPOCH: "1700000000", GOOS: "darwin", GOARCH: "arm64" };
+    const provenance = await collectProvenance(output.package, fixturesPath, env);
+
+    expect(provenance).toMatchObject({
+      extractor: { name: "@langchain/extractor-go" },
+      schemaVersion: 1,
+      module: { path: "github.com/example/testpkg", version: "0.0.0" },
+      goos: "darwin",
+      goarch: "arm64",
+      timestamp: "2023-11-14T22:13:20.000Z",
+    });
+    expect(provenance.extractor.version).toMatch(/^\d+\.\d+\.\d+/);
+  });
+
+  it("should record the git commit of the source", async () => {
+    const provenance = await collectProvenance(output.package, fixturesPath, {});
+
+    expect(provenance.vcs?.system).toBe("git");
+    expect(provenance.vcs?.revision).toMatch(/^[0-9a-f]{40}$/);
+    expect(typeof provenance.vcs?.modified).toBe("boolean");
+  });
+
+  it("should leave out the commit outside a git working tree", async () => {
+    const dir = await fs.mkdtemp(path.join(os.tmpdir(), "extractor-go-provenance-"));
+    const provenance = await collectProvenance(output.package, dir, {});
+
+    expect(provenance.vcs).toBeUndefined();
+    await fs.rm(dir, { recursive: true });
+  });
+
+  it("should take the timestamp from SOURCE_DATE_EPOCH", () => {
+    expect(provenanceTimestamp({ SOURCE_DATE_EPOCH: "0" })).toBe("1970-01-01T00:00:00.000Z");
+    expect(() => provenanceTimestamp({ SOURCE_DATE_EPOCH: "yesterday" })).toThrow(
+      "Invalid SOURCE_DATE_EPOCH",
+    );
+    expect(provenanceTimestamp({})).toMatch(/^\d{4}-\d\d-\d\dT/);
+  });
+
+  it("should add provenance after the package header", async () => {
+    const provenance = await collectProvenance(output.package, fixturesPath);
+    const withHeader = withProvenance(output, provenance);
+
+    expect(Object.keys(withHeader).slice(0, 2)).toEqual(["package", "provenance"]);
+    expect(validateOutput(withHeader)).toEqual([]);
+  });
+});
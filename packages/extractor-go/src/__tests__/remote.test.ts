@@ -0,0 +1,195 @@
+/**
+ * Remote module tests
+ */
+
+import fs from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+import { crc32, deflateRawSync } from "node:zlib";
+
+import { describe, it, expect } from "vitest";
+
+import {
+  downloadModule,
+  escapeModulePath,
+  githubRepo,
+  goProxyUrl,
+  moduleName,
+  moduleRevision,
+  parseModuleSpec,
+  readZip,
+} from "../remote.js";
+
+/**
+ * Build a zip archive of deflated entries.
+ */
+function zip(files: Record<string, string>): Buffer {
+  const locals: Buffer[] = [];
+  const centrals: Buffer[] = [];
+  let offset = 0;
+  for (const [name, content] of Object.entries(files)) {
+    const data = Buffer.from(content);
+    const compressed = deflateRawSync(data);
+    const nameBytes = Buffer.from(name);
+    const local = Buffer.alloc(30);
+    local.writeUInt32LE(0x04034b50, 0);
+    local.writeUInt16LE(8, 8);
+    local.writeUInt32LE(crc32(data), 14);
+    local.writeUInt32LE(compressed.length, 18);
+    local.writeUInt32LE(data.length, 22);
+    local.writeUInt16LE(nameBytes.length, 26);
+    const central = Buffer.alloc(46);
+    central.writeUInt32LE(0x02014b50, 0);
+    central.writeUInt16LE(8, 10);
+    central.writeUInt32LE(crc32(data), 16);
+    central.writeUInt32LE(compressed.length, 20);
+    central.writeUInt32LE(data.length, 24);
+    central.writeUInt16LE(nameBytes.length, 28);
+    central.writeUInt32LE(offset, 42);
+    locals.push(local, nameBytes, compressed);
+    centrals.push(central, nameBytes);
+    offset += 30 + nameBytes.length + compressed.length;
+  }
+  const directory = Buffer.concat(centrals);
+  const end = Buffer.alloc(22);
+  end.writeUInt32LE(0x06054b50, 0);
+  end.writeUInt16LE(Object.keys(files).length, 8);
+  end.writeUInt16LE(Object.keys(files).length, 10);
+  end.writeUInt32LE(directory.length, 12);
+  end.writeUInt32LE(offset, 16);
+  return Buffer.concat([...locals, directory, end]);
+}
+
+/**
+ * A fetch serving proxy responses by URL, recording the requested URLs.
+ */
+function fakeProxy(responses: Record<string, string | Buffer>) {
+  const requested: string[] = [];
+  const fetch = async (url: string | URL | Request) => {
+    requested.push(String(url));
+    const body = responses[String(url)];
+    return body === undefined
+      ? new Response("not found", { status: 404 })
+      : new Response(body, { status: 200 });
+  };
+  return { fetch: fetch as typeof globalThis.fetch, requested };
+}
+
+describe("module specs", () => {
+  it("should parse import paths and versions", () => {
+    expect(parseModuleSpec("github.com/tmc/langchaingo@v0.1.13")).toEqual({
+      path: "github.com/tmc/langchaingo",
+      version: "v0.1.13",
+    });
+    expect(parseModuleSpec("example.com/mod").version).toBe("latest");
+    expect(() => parseModuleSpec("langchaingo@v1.0.0")).toThrow("Invalid module path");
+    expect(() => parseModuleSpec("example.com/../mod")).toThrow("Invalid module path");
+    expect(() => parseModuleSpec("example.com/.hidden")).toThrow("Invalid module path");
+    expect(() => parseModuleSpec("example.com/mod@1.0")).toThrow("Invalid module version");
+  });
+
+  it("should derive names, repositories, and revisions", () => {
+    expect(moduleName("github.com/tmc/langchaingo")).toBe("langchaingo");
+    expect(moduleName("github.com/redis/go-redis/v9")).toBe("go-redis");
+    expect(githubRepo("github.com/tmc/langchaingo/llms")).toBe("tmc/langchaingo");
+    expect(githubRepo("golang.org/x/net")).toBeUndefined();
+    expect(moduleRevision("github.com/tmc/langchaingo", "v0.1.13")).toBe("v0.1.13");
+    expect(moduleRevision("github.com/redis/go-redis/v9", "v9.5.1")).toBe("v9.5.1");
+    expect(moduleRevision("github.com/a/b/sdk/v2", "v2.0.1")).toBe("sdk/v2.0.1");
+    expect(moduleRevision("github.com/a/b", "v0.0.0-20240101120000-0123456789ab")).toBe(
+      "0123456789ab",
+    );
+    expect(moduleRevision("github.com/a/b", "v1.2.4-0.20240101120000-0123456789ab")).toBe(
+      "0123456789ab",
+    );
+  });
+
+  it("should escape uppercase letters", () => {
+    expect(escapeModulePath("github.com/Azure/azure-sdk-for-go")).toBe(
+      "github.com/!azure/azure-sdk-for-go",
+    );
+  });
+
+  it("should use the first GOPROXY entry", () => {
+    expect(goProxyUrl({})).toBe("https://proxy.golang.org");
+    expect(goProxyUrl({ GOPROXY: "https://goproxy.example/,direct" })).toBe(
+      "https://goproxy.example",
+    );
+    expect(() => goProxyUrl({ GOPROXY: "off" })).toThrow("GOPROXY=off");
+    expect(() => goProxyUrl({ GOPROXY: "direct" })).toThrow("GOPROXY=direct isn't supported");
+  });
+});
+
+describe("readZip", () => {
+  it("should read deflated entries", () => {
+    const entries = readZip(zip({ "a.go": "package a\n", "dir/b.go": "package b\n" }));
+
+    expect([...entries.keys()]).toEqual(["a.go", "dir/b.go"]);
+    expect(entries.get("dir/b.go")!.toString()).toBe("package b\n");
+  });
+
+  it("should reject corrupt archives", () => {
+    const archive = zip({ "a.go": "package a\n" });
+    const directory = archive.readUInt32LE(archive.length - 22 + 16);
+    archive.writeUInt32LE(0, directory + 16);
+
+    expect(() => readZip(archive)).toThrow("Invalid zip: checksum mismatch in a.go");
+    expect(() => readZip(Buffer.from("not a zip"))).toThrow("no end of central directory");
+  });
+});
+
+describe("downloadModule", () => {
+  const proxy = "https://proxy.example";
+  const env = { GOPROXY: proxy };
+  const files = {
+    "example.com/Mod@v1.2.0/go.mod": "module example.com/Mod\n",
+    "example.com/Mod@v1.2.0/client.go": "package mod\n\n// Client calls.\ntype Client struct{}\n",
+  };
+
+  it("should resolve the version and unpack the module into the cache", async () => {
+    const cacheDir = await fs.mkdtemp(path.join(os.tmpdir(), "modules-"));
+    const { fetch, requested } = fakeProxy({
+      [`${proxy}/example.com/!mod/@latest`]: JSON.stringify({ Version: "v1.2.0" }),
+      [`${proxy}/example.com/!mod/@v/v1.2.0.zip`]: zip(files),
+    });
+
+    const module = await downloadModule(parseModuleSpec("example.com/Mod"), {
+      cacheDir,
+      env,
+      fetch,
+    });
+
+    expect(module).toEqual({
+      path: "example.com/Mod",
+      version: "v1.2.0",
+      dir: path.join(cacheDir, "example.com/!mod@v1.2.0"),
+      cached: false,
+    });
+    expect(await fs.readFile(path.join(module.dir, "client.go"), "utf-8")).toContain("Client");
+    expect(requested).toHaveLength(2);
+
+    const again = await downloadModule(parseModuleSpec("example.com/Mod"), {
+      cacheDir,
+      env,
+      fetch,
+    });
+    expect(again.cached).toBe(true);
+    expect(requested).toHaveLength(3);
+  });
+
+  it("should report unknown modules and files outside the module", async () => {
+    const cacheDir = await fs.mkdtemp(path.join(os.tmpdir(), "modules-"));
+    const { fetch } = fakeProxy({
+      [`${proxy}/example.com/!mod/@v/v1.2.0.info`]: JSON.stringify({ Version: "v1.2.0" }),
+      [`${proxy}/example.com/!mod/@v/v1.2.0.zip`]: zip({ "../evil.go": "package evil\n" }),
+    });
+
+    await expect(
+      downloadModule(parseModuleSpec("example.com/Mod@v1.3.0"), { cacheDir, env, fetch }),
+    ).rejects.toThrow("example.com/Mod@v1.3.0 not found on https://proxy.example");
+    await expect(
+      downloadModule(parseModuleSpec("example.com/Mod@v1.2.0"), { cacheDir, env, fetch }),
+    ).rejects.toThrow("unexpected file in module zip: ../evil.go");
+    expect(await fs.readdir(cacheDir)).toEqual([]);
+  });
+});
@@ -0,0 +1,101 @@
+/**
+ * Output schema tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput, type ExtractorOutput } from "../output.js";
+import { signaturesOutput, summarizeOutput } from "../profile.js";
+import {
+  formatSchemaErrors,
+  outputSchema,
+  validateJson,
+  validateOutput,
+  type JsonSchema,
+} from "../schema.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("validateOutput", () => {
+  let output: ExtractorOutput;
+
+  beforeAll(async () => {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      repo: "langchain-ai/test-repo",
+      sha: "abc123",
+      testHelpers: true,
+      testFunctions: true,
+      renderMarkdown: true,
+      renderHtml: true,
+      sidecarDocs: "docs",
+    });
+    const result = await new GoExtractor(config).extract();
+    output = buildOutput(result, config, new GoTransformer(result, config).transform());
+  });
+
+  it("should accept the outputs of every profile of the fixtures", () => {
+    expect(validateOutput(output)).toEqual([]);
+    expect(validateOutput(JSON.parse(JSON.stringify(output)))).toEqual([]);
+    expect(validateOutput(summarizeOutput(output))).toEqual([]);
+    expect(validateOutput(signaturesOutput(output))).toEqual([]);
+  });
+
+  it("should report mismatches by JSON Pointer", () => {
+    const broken = structuredClone(output);
+    Object.assign(broken.symbols[0], { kind: "struct" });
+    delete (broken.symbols[1] as Partial<ExtractorOutput["symbols"][number]>).source;
+    Object.assign(broken.package, { langauge: "go" });
+
+    expect(formatSchemaErrors(validateOutput(broken))).toBe(
+      [
+        "/package/langauge: unexpected property",
+        '/symbols/0/kind: expected one of "module", "class", "function", "method", ' +
+          '"property", "attribute", "interface", "typeAlias", "enum", "enumMember", ' +
+          '"variable", "namespace", "constructor", "parameter"',
+        '/symbols/1: missing required property "source"',
+      ].join("\n"),
+    );
+  });
+
+  it("should version the schema", () => {
+    expect(outputSchema.$id).toBe("urn:langchain:extractor-go:output:1");
+    expect(validateOutput([])).toEqual([{ path: "/", message: "expected object, got array" }]);
+  });
+});
+
+describe("validateJson", () => {
+  const schema: JsonSchema = {
+    type: "object",
+    properties: {
+      count: { type: "integer", minimum: 0 },
+      tags: { type: "array", items: { anyOf: [{ type: "string" }, { const: true }] } },
+    },
+    additionalProperties: { $ref: "#/$defs/flag" },
+    $defs: { flag: { type: "boolean" } },
+  };
+
+  it("should check types, bounds, alternatives, and references", () => {
+    expect(validateJson({ count: 2, tags: ["a", true], on: false }, schema)).toEqual([]);
+    expect(validateJson({ count: -1.5, tags: [false], on: "yes" }, schema)).toEqual([
+      { path: "/count", message: "expected integer, got number" },
+      { path: "/tags/0", message: "matches none of the allowed schemas" },
+      { path: "/on", message: "expected boolean, got string" },
+    ]);
+  });
+
+  it("should fail on unknown references", () => {
+    expect(() => validateJson(1, { $ref: "#/$defs/missing" })).toThrow(
+      "Unknown schema reference: #/$defs/missing",
+    );
+  });
+});
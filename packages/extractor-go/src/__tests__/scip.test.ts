@@ -0,0 +1,93 @@
+/**
+ * SCIP index tests
+ */
+
+import fs from "node:fs";
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput, type ExtractorOutput } from "../output.js";
+import { buildScipIndex, encodeScipIndex, type ScipIndex } from "../scip.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("buildScipIndex", () => {
+  let output: ExtractorOutput;
+  let index: ScipIndex;
+  let prefix: string;
+
+  beforeAll(async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    output = buildOutput(result, config, new GoTransformer(result, config).transform());
+    const sources = Object.fromEntries(
+      fs
+        .readdirSync(fixturesPath)
+        .filter((file) => file.endsWith(".go"))
+        .map((file) => [file, fs.readFileSync(path.join(fixturesPath, file), "utf-8")]),
+    );
+    index = buildScipIndex(output, sources);
+    const version = output.package.version || ".";
+    prefix = `scip-go gomod github.com/example/testpkg ${version} \`github.com/example/testpkg\`/`;
+  });
+
+  const symbol = (name: string) =>
+    index.documents
+      .flatMap((document) => document.symbols)
+      .find((info) => info.symbol === `${prefix}${name}`);
+
+  it("should name symbols as scip-go does", () => {
+    expect(symbol("Client#")?.kind).toBe(49);
+    expect(symbol("Client#Get().")?.enclosingSymbol).toBe(`${prefix}Client#`);
+    expect(symbol("NewClient().")?.signature).toBe(
+      "func NewClient(baseURL, apiKey string) *Client",
+    );
+    expect(symbol("MaxRetries.")?.kind).toBe(8);
+    expect(symbol("unexportedConst.")).toBeUndefined();
+  });
+
+  it("should record definition ranges", () => {
+    const types = index.documents.find((document) => document.relativePath === "types.go")!;
+    const ranges = Object.fromEntries(
+      types.occurrences.map((occurrence) => [occurrence.symbol, occurrence.range]),
+    );
+
+    expect(ranges[`${prefix}Client#`]).toEqual([10, 5, 11]);
+    expect(ranges[`${prefix}Client#Get().`]).toEqual([33, 17, 20]);
+  });
+
+  it("should carry documentation and implemented interfaces", () => {
+    expect(symbol("Retry().")?.documentation[0]).toMatch(/^\*\*Deprecated:\*\* /);
+    expect(symbol("Buffer#")?.implements).toEqual([`${prefix}Closer#`]);
+  });
+});
+
+describe("encodeScipIndex", () => {
+  it("should encode protobuf messages", () => {
+    const bytes = encodeScipIndex({
+      metadata: { toolName: "t", toolVersion: "", projectRoot: "" },
+      documents: [
+        {
+          relativePath: "a.go",
+          occurrences: [{ range: [0, 5, 6], symbol: "s" }],
+          symbols: [],
+        },
+      ],
+    });
+
+    expect([...bytes]).toEqual([
+      // metadata: tool_info { name: "t" }, text_document_encoding: UTF8
+      0x0a, 0x07, 0x12, 0x03, 0x0a, 0x01, 0x74, 0x20, 0x01,
+      // documents: relative_path, occurrences { range, symbol, symbol_roles }, language, encoding
+      0x12, 0x18, 0x0a, 0x04, 0x61, 0x2e, 0x67, 0x6f, 0x12, 0x0a, 0x0a, 0x03, 0x00, 0x05, 0x06,
+      0x12, 0x01, 0x73, 0x18, 0x01, 0x22, 0x02, 0x67, 0x6f, 0x30, 0x02,
+    ]);
+  });
+});
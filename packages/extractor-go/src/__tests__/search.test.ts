@@ -0,0 +1,89 @@
+/**
+ * Search index record tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+import type { SearchRecord } from "@langchain/ir-schema";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput } from "../output.js";
+import { searchIndexAdapters, searchRecords } from "../search.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("searchRecords", () => {
+  let records: SearchRecord[];
+  const record = (title: string) => records.find((r) => r.title === title)!;
+
+  beforeAll(async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    records = searchRecords(
+      buildOutput(result, config, new GoTransformer(result, config).transform()),
+    );
+  });
+
+  it("should flatten symbols into search records", () => {
+    expect(record("Client.Get")).toEqual({
+      id: "pkg_go_test_package:Client_Get",
+      url: "/Client.Get",
+      title: "Client.Get",
+      breadcrumbs: ["test-package", "Client"],
+      excerpt: "Get performs an HTTP GET request to the specified path.",
+      keywords: ["Get", "test-package.Client.Get", "get"],
+      kind: "method",
+      language: "go",
+      packageId: "pkg_go_test_package",
+    });
+    expect(record("NewClient").breadcrumbs).toEqual(["test-package"]);
+  });
+
+  it("should boost by name words, category, and deprecation", () => {
+    expect(record("Rerank").keywords).toContain("retrievers");
+    expect(record("ParseConfig").keywords).toEqual([
+      "ParseConfig",
+      "test-package.ParseConfig",
+      "parse",
+      "config",
+      "deprecated",
+    ]);
+  });
+
+  it("should only index the public API", () => {
+    expect(records.every((r) => /^[A-Z]/.test(r.title.split(".").pop()!))).toBe(true);
+  });
+});
+
+describe("searchIndexAdapters", () => {
+  const records: SearchRecord[] = [
+    {
+      id: "pkg_go_x:A",
+      url: "/A",
+      title: "A",
+      breadcrumbs: ["x"],
+      excerpt: "",
+      keywords: ["A"],
+      kind: "function",
+      language: "go",
+      packageId: "pkg_go_x",
+    },
+  ];
+
+  it("should key Algolia objects by objectID", () => {
+    const [object] = JSON.parse(searchIndexAdapters.algolia.serialize(records));
+    expect(object).toEqual({ objectID: "pkg_go_x:A", ...records[0] });
+  });
+
+  it("should write a Typesense document per line", () => {
+    expect(searchIndexAdapters.typesense.serialize([...records, ...records])).toBe(
+      `${JSON.stringify(records[0])}\n${JSON.stringify(records[0])}\n`,
+    );
+  });
+});
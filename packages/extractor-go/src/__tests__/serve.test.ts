@@ -0,0 +1,205 @@
+/**
+ * Extraction server tests
+ */
+
+import type { AddressInfo } from "node:net";
+
+import { describe, it, expect } from "vitest";
+import type { SymbolRecord } from "@langchain/ir-schema";
+
+import {
+  ExtractionCache,
+  LruCache,
+  createExtractionServer,
+  parseExtractRequest,
+  type ModuleExtractor,
+} from "../serve.js";
+import type { ExtractorOutput } from "../output.js";
+
+function output(module: string, names: string[]): ExtractorOutput {
+  const symbols = names.map(
+    (name) => ({ id: `${module}:${name}`, name, qualifiedName: name }) as SymbolRecord,
+  );
+  return { package: { publishedName: module }, symbols } as ExtractorOutput;
+}
+
+async function listen(extract: ModuleExtractor) {
+  const server = createExtractionServer(new ExtractionCache(extract));
+  await new Promise<void>((resolve) => server.listen(0, resolve));
+  const base = `http://localhost:${(server.address() as AddressInfo).port}`;
+  const post = (body: unknown) =>
+    fetch(`${base}/extract`, { method: "POST", body: JSON.stringify(body) });
+  const close = () => new Promise((resolve) => server.close(resolve));
+  return { base, post, close };
+}
+
+describe("LruCache", () => {
+  it("should drop the least recently used entry", () => {
+    const cache = new LruCache<string, number>(2);
+    cache.set("a", 1);
+    cache.set("b", 2);
+    cache.get("a");
+    cache.set("c", 3);
+
+    expect(cache.get("b")).toBeUndefined();
+    expect(cache.values()).toEqual([3, 1]);
+    expect(() => new LruCache(0)).toThrow("Invalid cache size");
+  });
+
+  it("should report dropped entries", () => {
+    const evicted: string[] = [];
+    const cache = new LruCache<string, number>(1, (value, key) => evicted.push(`${key}=${value}`));
+    cache.set("a", 1);
+    cache.set("a", 2);
+    cache.set("b", 3);
+
+    expect(evicted).toEqual(["a=2"]);
+  });
+});
+
+describe("ExtractionCache", () => {
+  const extract: ModuleExtractor = async ({ module, version }) => ({
+    module,
+    version: version === "latest" ? "v1.1.0" : version,
+    output: output(module, ["Client"]),
+  });
+  const request = { module: "example.com/m", profile: "full" } as const;
+  function recording(versions: string[]): ModuleExtractor {
+    return async (extraction) => {
+      versions.push(extraction.version);
+      return extract(extraction);
+    };
+  }
+
+  it("should share the extraction of the version latest resolves to", async () => {
+    const versions: string[] = [];
+    let resolved = 0;
+    const cache = new ExtractionCache(recording(versions), 4, {
+      resolveLatest: async () => {
+        resolved++;
+        return "v1.1.0";
+      },
+    });
+    await cache.extract({ ...request, version: "v1.1.0" });
+    await cache.extract({ ...request, version: "latest" });
+    await cache.extract({ ...request, version: "latest" });
+
+    expect(versions).toEqual(["v1.1.0"]);
+    expect(resolved).toBe(1);
+  });
+
+  it("should resolve latest again once it's stale", async () => {
+    const releases = ["v1.0.0", "v1.1.0"];
+    const versions: string[] = [];
+    const cache = new ExtractionCache(recording(versions), 4, {
+      resolveLatest: async () => releases.shift()!,
+      latestTtlMs: 0,
+    });
+    await cache.extract({ ...request, version: "latest" });
+    const latest = await cache.extract({ ...request, version: "latest" });
+
+    expect(versions).toEqual(["v1.0.0", "v1.1.0"]);
+    expect(latest.version).toBe("v1.1.0");
+  });
+
+  it("should extract latest again once it's stale without a resolver", async () => {
+    const versions: string[] = [];
+    const cache = new ExtractionCache(recording(versions), 4, { latestTtlMs: 0 });
+    await cache.extract({ ...request, version: "latest" });
+    await cache.extract({ ...request, version: "latest" });
+
+    expect(versions).toEqual(["latest", "latest"]);
+  });
+
+  it("should report the extractions it drops", async () => {
+    const evicted: string[] = [];
+    const cache = new ExtractionCache(extract, 1, {
+      onEvict: ({ module, version }) => evicted.push(`${module}@${version}`),
+    });
+    await cache.extract({ ...request, version: "v1.0.0" });
+    await cache.extract({ ...request, version: "v1.1.0" });
+    await Promise.resolve();
+
+    expect(evicted).toEqual(["example.com/m@v1.0.0"]);
+  });
+});
+
+describe("parseExtractRequest", () => {
+  it("should default to the latest version and the full profile", () => {
+    expect(parseExtractRequest({ module: "example.com/m" })).toEqual({
+      module: "example.com/m",
+      version: "latest",
+      profile: "full",
+    });
+    expect(() => parseExtractRequest({})).toThrow("module is required");
+    expect(() => parseExtractRequest({ module: "example.com/m", profile: "tiny" })).toThrow(
+      "Unknown profile",
+    );
+  });
+
+  it("should reject module paths and versions a module source would", () => {
+    expect(() => parseExtractRequest({ module: "m" })).toThrow("Invalid module path");
+    expect(() => parseExtractRequest({ module: "example.com/../../etc" })).toThrow(
+      "Invalid module path",
+    );
+    expect(() => parseExtractRequest({ module: "example.com/m", version: "../v1" })).toThrow(
+      "Invalid module version",
+    );
+  });
+});
+
+describe("createExtractionServer", () => {
+  it("should extract once per version and serve symbols of extracted modules", async () => {
+    const calls: string[] = [];
+    const { base, post, close } = await listen(async ({ module, version }) => {
+      calls.push(`${module}@${version}`);
+      const resolved = version === "latest" ? "v1.1.0" : version;
+      return { module, version: resolved, output: output(module, [`Client${resolved}`]) };
+    });
+    try {
+      const [first, second] = await Promise.all([
+        post({ module: "example.com/m", version: "v1.0.0" }),
+        post({ module: "example.com/m", version: "v1.0.0" }),
+      ]);
+      expect(first.status).toBe(200);
+      expect((await first.json()).symbols).toHaveLength(1);
+      expect(second.status).toBe(200);
+      await post({ module: "example.com/m" });
+      expect(calls).toEqual(["example.com/m@v1.0.0", "example.com/m@latest"]);
+
+      const id = encodeURIComponent("example.com/m:Clientv1.0.0");
+      const symbol = await fetch(`${base}/symbol/${id}`);
+      expect(symbol.status).toBe(200);
+      expect((await symbol.json()).name).toBe("Clientv1.0.0");
+
+      expect((await fetch(`${base}/symbol/${id}?version=v1.1.0`)).status).toBe(404);
+    } finally {
+      await close();
+    }
+  });
+
+  it("should reject bad requests and not cache failures", async () => {
+    let attempts = 0;
+    const { base, post, close } = await listen(async () => {
+      attempts++;
+      throw new Error("not found on proxy");
+    });
+    try {
+      expect((await post({ version: "v1.0.0" })).status).toBe(400);
+      const failed = await post({ module: "example.com/gone" });
+      expect(failed.status).toBe(500);
+      expect((await failed.json()).error).toBe("not found on proxy");
+      await post({ module: "example.com/gone" });
+      expect(attempts).toBe(2);
+
+      expect((await post({ module: "example.com/m", version: "../../x" })).status).toBe(400);
+      expect((await fetch(`${base}/symbol/%E0`)).status).toBe(400);
+      expect(attempts).toBe(2);
+
+      expect((await fetch(`${base}/extract`)).status).toBe(405);
+      expect((await fetch(`${base}/other`)).status).toBe(404);
+    } finally {
+      await close();
+    }
+  });
+});
@@ -0,0 +1,218 @@
+/**
+ * Incremental update tests
+ */
+
+import { execFileSync } from "node:child_process";
+import fs from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig, type GoExtractorConfig } from "../config.js";
+import { buildOutput, type ExtractorOutput } from "../output.js";
+import { compress } from "../compress.js";
+import { packageDependencies, packageDependents, packageIncludePatterns } from "../packages.js";
+import { splitManifestFile, splitOutput } from "../split.js";
+import type { GoSymbolRecord } from "../transformer.js";
+import { changedGoFiles, loadSplitOutput, mergeSplitOutput } from "../since.js";
+
+/**
+ * A committed git repository with a module of two packages.
+ */
+async function repository(): Promise<string> {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), "since-"));
+  const files: Record<string, string> = {
+    "go.mod": "module example.com/app\n",
+    "app.go": "// Package app runs things.\npackage app\n\n// Run runs.\nfunc Run() {}\n",
+    "store/store.go": "package store\n\n// Get gets.\nfunc Get() {}\n",
+    "README.md": "# app\n",
+  };
+  for (const [file, content] of Object.entries(files)) {
+    await fs.mkdir(path.dirname(path.join(dir, file)), { recursive: true });
+    await fs.writeFile(path.join(dir, file), content);
+  }
+  git(dir, "init", "-q");
+  git(dir, "add", "-A");
+  git(dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-qm", "init");
+  return dir;
+}
+
+function git(dir: string, ...args: string[]): void {
+  execFileSync("git", args, { cwd: dir, stdio: "ignore" });
+}
+
+async function extract(config: GoExtractorConfig): Promise<ExtractorOutput> {
+  const result = await new GoExtractor(config).extract();
+  return buildOutput(result, config, new GoTransformer(result, config).transform());
+}
+
+describe("changedGoFiles", () => {
+  it("should list changed and untracked Go files", async () => {
+    const dir = await repository();
+    await fs.appendFile(path.join(dir, "store/store.go"), "\n// Put puts.\nfunc Put() {}\n");
+    await fs.mkdir(path.join(dir, "cache"));
+    await fs.writeFile(path.join(dir, "cache/cache.go"), "package cache\n");
+    await fs.appendFile(path.join(dir, "README.md"), "More.\n");
+
+    expect(await changedGoFiles(dir, "HEAD")).toEqual(["cache/cache.go", "store/store.go"]);
+  });
+
+  it("should list both packages of a file moved between them", async () => {
+    const dir = await repository();
+    await fs.mkdir(path.join(dir, "cache"));
+    git(dir, "mv", "store/store.go", "cache/store.go");
+
+    expect(await changedGoFiles(dir, "HEAD")).toEqual(["cache/store.go", "store/store.go"]);
+  });
+
+  it("should list files relative to a module in a subdirectory", async () => {
+    const dir = await repository();
+    await fs.appendFile(path.join(dir, "store/store.go"), "\n// Put puts.\nfunc Put() {}\n");
+
+    expect(await changedGoFiles(path.join(dir, "store"), "HEAD")).toEqual(["store.go"]);
+  });
+
+  it("should return undefined when go.mod changed", async () => {
+    const dir = await repository();
+    await fs.appendFile(path.join(dir, "go.mod"), "\ngo 1.22\n");
+
+    expect(await changedGoFiles(dir, "HEAD")).toBe(undefined);
+  });
+
+  it("should fail on unknown refs", async () => {
+    const dir = await repository();
+
+    await expect(changedGoFiles(dir, "no-such-ref")).rejects.toThrow(
+      "Can't list the files changed since no-such-ref",
+    );
+  });
+});
+
+describe("mergeSplitOutput", () => {
+  async function write(dir: string, output: ExtractorOutput, gzip = false): Promise<string> {
+    const outputDir = await fs.mkdtemp(path.join(dir, "out-"));
+    for (const [file, content] of splitOutput(output, gzip ? "gzip" : undefined)) {
+      await fs.mkdir(path.dirname(path.join(outputDir, file)), { recursive: true });
+      const data = gzip && file !== splitManifestFile ? compress(content, "gzip") : content;
+      await fs.writeFile(path.join(outputDir, file), data);
+    }
+    return outputDir;
+  }
+
+  it("should read split outputs back, compressed or not", async () => {
+    const dir = await repository();
+    const output = await extract(createConfig({ packageName: "app", packagePath: dir }));
+
+    for (const gzip of [false, true]) {
+      const files = splitOutput(output, gzip ? "gzip" : undefined);
+      const loaded = await loadSplitOutput(await write(dir, output, gzip));
+
+      expect(loaded.packages.map((pkg) => pkg.importPath)).toEqual([
+        "example.com/app",
+        "example.com/app/store",
+      ]);
+      expect(loaded.files).toEqual(files);
+    }
+  });
+
+  it("should fail without a split output", async () => {
+    const dir = await repository();
+
+    await expect(loadSplitOutput(path.join(dir, "missing"))).rejects.toThrow(
+      "No split output to update",
+    );
+  });
+
+  it("should match a full extraction after re-extracting changed packages", async () => {
+    const dir = await repository();
+    const config = createConfig({ packageName: "app", packagePath: dir });
+    const previous = await loadSplitOutput(await write(dir, await extract(config)));
+    await fs.appendFile(path.join(dir, "store/store.go"), "\n// Put puts.\nfunc Put() {}\n");
+
+    const dirs = ["store"];
+    const fresh = await extract({ ...config, includePatterns: packageIncludePatterns(dirs) });
+    const merged = mergeSplitOutput(previous, fresh, dirs, config);
+    const full = await extract(config);
+
+    expect(fresh.symbols.map((symbol) => symbol.name)).toEqual(["Get", "Put"]);
+    expect(merged).toEqual(full);
+    expect(splitOutput(merged).get("example.com/app.json")).toBe(
+      previous.files.get("example.com/app.json"),
+    );
+  });
+
+  describe("with constants from other packages", () => {
+    async function dependentRepository(): Promise<string> {
+      const dir = await repository();
+      await fs.writeFile(
+        path.join(dir, "app.go"),
+        '// Package app runs things.\npackage app\n\nimport "example.com/app/store"\n\n' +
+          "// Answer is the answer.\nconst Answer = store.Base + 2\n",
+      );
+      await fs.appendFile(
+        path.join(dir, "store/store.go"),
+        "\n// Base is the base.\nconst Base = 40\n",
+      );
+      return dir;
+    }
+
+    it("should re-extract the packages importing changed ones", async () => {
+      const dir = await dependentRepository();
+      const config = createConfig({ packageName: "app", packagePath: dir });
+      const previous = await loadSplitOutput(await write(dir, await extract(config)));
+      const storeFile = path.join(dir, "store/store.go");
+      const store = await fs.readFile(storeFile, "utf-8");
+      await fs.writeFile(storeFile, store.replace("Base = 40", "Base = 50"));
+
+      const dirs = await packageDependents(dir, ["store"]);
+      const parseDirs = await packageDependencies(dir, dirs);
+      const includePatterns = packageIncludePatterns(parseDirs);
+      const fresh = await extract({ ...config, includePatterns });
+      const merged = mergeSplitOutput(previous, fresh, dirs, config);
+      const answer = merged.symbols.find((symbol) => symbol.name === "Answer") as GoSymbolRecord;
+
+      expect(dirs).toEqual([".", "store"]);
+      expect(answer.value?.evaluated).toEqual({ value: "52", type: "untyped int" });
+      expect(merged).toEqual(await extract(config));
+    });
+
+    it("should parse the packages changed ones import, without re-extracting them", async () => {
+      const dir = await dependentRepository();
+      const config = createConfig({ packageName: "app", packagePath: dir });
+      const previous = await loadSplitOutput(await write(dir, await extract(config)));
+      await fs.appendFile(path.join(dir, "app.go"), "\n// Run runs.\nfunc Run() {}\n");
+
+      const dirs = await packageDependents(dir, ["."]);
+      const parseDirs = await packageDependencies(dir, dirs);
+      const includePatterns = packageIncludePatterns(parseDirs);
+      const fresh = await extract({ ...config, includePatterns });
+      const merged = mergeSplitOutput(previous, fresh, dirs, config);
+      const answer = merged.symbols.find((symbol) => symbol.name === "Answer") as GoSymbolRecord;
+
+      expect([dirs, parseDirs]).toEqual([["."], [".", "store"]]);
+      expect(answer.value?.evaluated).toEqual({ value: "42", type: "untyped int" });
+      expect(merged).toEqual(await extract(config));
+      expect(splitOutput(merged).get("example.com/app/store.json")).toBe(
+        previous.files.get("example.com/app/store.json"),
+      );
+    });
+  });
+
+  it("should drop the symbols of deleted packages", async () => {
+    const dir = await repository();
+    const config = createConfig({ packageName: "app", packagePath: dir });
+    const previous = await loadSplitOutput(await write(dir, await extract(config)));
+    await fs.rm(path.join(dir, "store"), { recursive: true });
+
+    const dirs = ["store"];
+    const fresh = await extract({ ...config, includePatterns: packageIncludePatterns(dirs) });
+    const merged = mergeSplitOutput(previous, fresh, dirs, config);
+
+    expect(merged.symbols.map((symbol) => symbol.name)).toEqual(["Run"]);
+    expect(merged.package.overview).toBe(previous.manifest.package.overview);
+    expect([...splitOutput(merged).keys()]).toEqual(["example.com/app.json", splitManifestFile]);
+  });
+});
@@ -0,0 +1,70 @@
+/**
+ * Single file tests
+ */
+
+import fs from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+import { Readable } from "node:stream";
+
+import { describe, it, expect } from "vitest";
+
+import {
+  isGoFileSource,
+  packageClause,
+  readStream,
+  stageGoFile,
+  stageGoFileFrom,
+} from "../single-file.js";
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+
+const source = `// Package scratch is a standalone file.
+package scratch
+
+import "example.com/missing"
+
+// Greet says hello.
+func Greet(name string) string { return missing.Hello(name) }
+`;
+
+describe("single files", () => {
+  it("should recognize a single .go file source", () => {
+    expect(isGoFileSource(["main.go"])).toBe(true);
+    expect(isGoFileSource(["module", "example.com/x@v1.0.0"])).toBe(false);
+    expect(isGoFileSource([])).toBe(false);
+  });
+
+  it("should read the package clause", () => {
+    expect(packageClause(source)).toBe("scratch");
+    expect(packageClause("func F() {}")).toBeUndefined();
+  });
+
+  it("should read a stream to its end", async () => {
+    expect(await readStream(Readable.from([Buffer.from("package "), "x\n"]))).toBe("package x\n");
+  });
+
+  it("should stage a file on disk under its own name", async () => {
+    const dir = await fs.mkdtemp(path.join(os.tmpdir(), "single-"));
+    await fs.writeFile(path.join(dir, "greet.go"), source);
+
+    const staged = await stageGoFileFrom(path.join(dir, "greet.go"));
+
+    expect(staged.file).toBe("greet.go");
+    expect(staged.packageName).toBe("scratch");
+    expect(await fs.readdir(staged.dir)).toEqual(["greet.go"]);
+  });
+
+  it("should extract staged source without a module", async () => {
+    const staged = await stageGoFile(source);
+    const config = createConfig({ packageName: "scratch", packagePath: staged.dir });
+    const result = await new GoExtractor(config).extract();
+    const symbols = new GoTransformer(result, config).transform();
+
+    expect(staged.file).toBe("stdin.go");
+    expect(result.errors).toBeUndefined();
+    expect(symbols.map((symbol) => symbol.name)).toContain("Greet");
+    expect(symbols.find((symbol) => symbol.name === "Greet")?.source.path).toBe("stdin.go");
+  });
+});
@@ -0,0 +1,102 @@
+/**
+ * Slug collision tests
+ */
+
+import { mkdtemp, rm, writeFile } from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect, beforeAll, afterAll, vi } from "vitest";
+import type { SymbolRecord } from "@langchain/ir-schema";
+
+import { normalizeSlug, resolveSlugCollisions } from "../slugs.js";
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer, type GoSymbolRecord } from "../transformer.js";
+import { createConfig } from "../config.js";
+
+function symbol(id: string, canonical: string): SymbolRecord {
+  return { id, urls: { canonical } } as SymbolRecord;
+}
+
+describe("normalizeSlug", () => {
+  it("should fold case and Unicode compatibility forms", () => {
+    expect(normalizeSlug("/Client")).toBe("/client");
+    expect(normalizeSlug("/\u212Aelvin")).toBe("/kelvin");
+    expect(normalizeSlug("/Cafe\u0301")).toBe(normalizeSlug("/Caf\u00e9"));
+  });
+});
+
+describe("resolveSlugCollisions", () => {
+  it("should leave distinct slugs alone", () => {
+    const symbols = [symbol("a", "/Client"), symbol("b", "/Server")];
+    expect(resolveSlugCollisions(symbols)).toEqual([]);
+    expect(symbols.map((s) => s.urls.canonical)).toEqual(["/Client", "/Server"]);
+  });
+
+  it("should suffix every colliding URL but the first by code point", () => {
+    const symbols = [symbol("c", "/client"), symbol("a", "/CLIENT"), symbol("b", "/Client")];
+    const collisions = resolveSlugCollisions(symbols);
+
+    expect(collisions).toEqual([
+      { slug: "/client", symbolIds: ["a", "b", "c"], urls: ["/CLIENT", "/Client-2", "/client-3"] },
+    ]);
+  });
+
+  it("should not depend on input order", () => {
+    const forward = [symbol("a", "/Client"), symbol("b", "/client")];
+    const backward = [symbol("b", "/client"), symbol("a", "/Client")];
+    resolveSlugCollisions(forward);
+    resolveSlugCollisions(backward);
+
+    expect(forward.map((s) => s.urls.canonical)).toEqual(["/Client", "/client-2"]);
+    expect(backward.map((s) => s.urls.canonical)).toEqual(["/client-2", "/Client"]);
+  });
+
+  it("should skip suffixes that are already taken", () => {
+    const symbols = [symbol("a", "/Client"), symbol("b", "/client"), symbol("c", "/Client-2")];
+    resolveSlugCollisions(symbols);
+    expect(symbols.map((s) => s.urls.canonical)).toEqual(["/Client", "/client-3", "/Client-2"]);
+  });
+});
+
+describe("GoTransformer slug collisions", () => {
+  let root: string;
+  let symbols: GoSymbolRecord[];
+  let warnings: unknown[][];
+
+  beforeAll(async () => {
+    root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-slugs-"));
+    await writeFile(
+      path.join(root, "client.go"),
+      `package client
+
+// Client is an API client.
+type Client struct{}
+
+// client is the shared default client.
+var client = &Client{}
+`,
+    );
+
+    const config = createConfig({ packageName: "slugs", packagePath: root, exportedOnly: false });
+    const result = await new GoExtractor(config).extract();
+    const warn = vi.spyOn(console, "warn");
+    symbols = new GoTransformer(result, config).transform();
+    warnings = warn.mock.calls;
+    warn.mockRestore();
+  });
+
+  afterAll(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it("should give case-colliding symbols distinct URLs", () => {
+    expect(symbols.find((s) => s.name === "Client")!.urls.canonical).toBe("/Client");
+    expect(symbols.find((s) => s.name === "client")!.urls.canonical).toBe("/client-2");
+  });
+
+  it("should warn about the collision", () => {
+    expect(warnings).toHaveLength(1);
+    expect(String(warnings[0][0])).toContain(`share the slug "/client"`);
+  });
+});
@@ -0,0 +1,93 @@
+/**
+ * Split output tests
+ */
+
+import crypto from "node:crypto";
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput, type ExtractorOutput } from "../output.js";
+import { splitManifestFile, splitOutput, type SplitManifest } from "../split.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("splitOutput", () => {
+  let output: ExtractorOutput;
+
+  beforeAll(async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    output = buildOutput(result, config, new GoTransformer(result, config).transform());
+    // Move the functions into a subpackage
+    output.symbols = output.symbols.map((symbol) =>
+      symbol.kind === "function"
+        ? { ...symbol, source: { ...symbol.source, path: `util/${symbol.source.path}` } }
+        : symbol,
+    );
+  });
+
+  it("should write a file per package by import path", () => {
+    const files = splitOutput(output);
+
+    expect([...files.keys()]).toEqual([
+      "github.com/example/testpkg.json",
+      "github.com/example/testpkg/util.json",
+      splitManifestFile,
+    ]);
+    const util = JSON.parse(files.get("github.com/example/testpkg/util.json")!);
+    expect(util.importPath).toBe("github.com/example/testpkg/util");
+    expect(util.symbols.every((symbol: { kind: string }) => symbol.kind === "function")).toBe(true);
+  });
+
+  it("should list package files with counts and checksums in the manifest", () => {
+    const files = splitOutput(output);
+    const manifest = JSON.parse(files.get(splitManifestFile)!) as SplitManifest;
+
+    expect(manifest.package.packageId).toBe("pkg_go_test_package");
+    expect(manifest).not.toHaveProperty("symbols");
+    expect(manifest.packages.reduce((n, entry) => n + entry.symbols, 0)).toBe(
+      output.symbols.length,
+    );
+    for (const entry of manifest.packages) {
+      const hash = crypto.createHash("sha256").update(files.get(entry.file)!).digest("hex");
+      expect(entry.sha256).toBe(hash);
+    }
+  });
+
+  it("should keep checksums of unchanged packages", () => {
+    const before = JSON.parse(splitOutput(output).get(splitManifestFile)!) as SplitManifest;
+    const changed = {
+      ...output,
+      symbols: output.symbols.map((symbol) =>
+        symbol.qualifiedName === "NewClient"
+          ? { ...symbol, docs: { ...symbol.docs, summary: "Changed." } }
+          : symbol,
+      ),
+    };
+    const after = JSON.parse(splitOutput(changed).get(splitManifestFile)!) as SplitManifest;
+
+    expect(after.packages[0].sha256).toBe(before.packages[0].sha256);
+    expect(after.packages[1].sha256).not.toBe(before.packages[1].sha256);
+  });
+
+  it("should record the compression of package files in the manifest", () => {
+    const files = splitOutput(output, "gzip");
+    const manifest = JSON.parse(files.get(splitManifestFile)!) as SplitManifest;
+
+    expect(manifest.encoding).toBe("gzip");
+    expect(manifest.packages.map((entry) => entry.file)).toEqual([
+      "github.com/example/testpkg.json.gz",
+      "github.com/example/testpkg/util.json.gz",
+    ]);
+    expect(manifest.packages[0].sha256).toBe(
+      (JSON.parse(splitOutput(output).get(splitManifestFile)!) as SplitManifest).packages[0].sha256,
+    );
+  });
+});
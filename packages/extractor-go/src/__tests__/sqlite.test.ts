@@ -0,0 +1,118 @@
+/**
+ * SQLite index tests
+ */
+
+import { mkdtemp, rm } from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput, type ExtractorOutput } from "../output.js";
+import { sqliteTables, writeSqlite, type SqliteTables } from "../sqlite.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+const hasSqlite = await import("node:sqlite").then(
+  () => true,
+  () => false,
+);
+
+describe("sqliteTables", () => {
+  let output: ExtractorOutput;
+  let tables: SqliteTables;
+
+  beforeAll(async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    output = buildOutput(result, config, new GoTransformer(result, config).transform());
+    tables = sqliteTables(output);
+  });
+
+  it("should write a row per symbol to the symbol tables", () => {
+    expect(tables.packages).toHaveLength(1);
+    expect(tables.symbols).toHaveLength(output.symbols.length);
+    expect(tables.signatures).toHaveLength(output.symbols.length);
+    expect(tables.docs).toHaveLength(output.symbols.length);
+
+    expect(tables.symbols.find((row) => row.qualified_name === "Client.Get")).toMatchObject({
+      id: "pkg_go_test_package:Client_Get",
+      kind: "method",
+      url: "/Client.Get",
+      source_path: "types.go",
+    });
+    const rowOf = (table: keyof SqliteTables, id: string) =>
+      tables[table].find((row) => row.symbol_id === `pkg_go_test_package:${id}`);
+    expect(rowOf("signatures", "Client_Get")).toMatchObject({
+      params: '[{"name":"ctx","type":"context.Context"},{"name":"path","type":"string"}]',
+      returns: "([]byte, error)",
+    });
+    expect(rowOf("docs", "Retry")).toMatchObject({
+      deprecated: 1,
+      deprecation_message: "Use RetryPolicy with Do instead.",
+    });
+  });
+
+  it("should index cross-references by kind", () => {
+    const refsOf = (id: string) =>
+      tables.refs.filter((row) => row.from_id === `pkg_go_test_package:${id}`);
+
+    expect(refsOf("NewClient")).toEqual([
+      {
+        from_id: "pkg_go_test_package:NewClient",
+        to_id: "pkg_go_test_package:Client",
+        to_name: "Client",
+        kind: "type",
+      },
+    ]);
+    expect(refsOf("Buffer")).toContainEqual({
+      from_id: "pkg_go_test_package:Buffer",
+      to_id: "pkg_go_test_package:Closer",
+      to_name: "Closer",
+      kind: "implements",
+    });
+    expect(refsOf("Buffer")).toContainEqual({
+      from_id: "pkg_go_test_package:Buffer",
+      to_id: null,
+      to_name: "io.Writer",
+      kind: "docLink",
+    });
+  });
+});
+
+describe.skipIf(!hasSqlite)("writeSqlite", () => {
+  it("should write a queryable database", async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    const output = buildOutput(result, config, new GoTransformer(result, config).transform());
+    const dir = await mkdtemp(path.join(os.tmpdir(), "extract-go-sqlite-"));
+    const file = path.join(dir, "symbols.db");
+
+    try {
+      await writeSqlite(output, file);
+      await writeSqlite(output, file);
+
+      const { DatabaseSync } = await import("node:sqlite");
+      const db = new DatabaseSync(file);
+      const implementations = db
+        .prepare(
+          "SELECT s.qualified_name AS name FROM refs r JOIN symbols s ON s.id = r.from_id " +
+            "WHERE r.kind = 'implements' AND r.to_name = ? ORDER BY name",
+        )
+        .all("Closer");
+      const count = db.prepare("SELECT COUNT(*) AS count FROM symbols").get();
+      db.close();
+
+      expect(implementations.map((row) => row.name)).toContain("Buffer");
+      expect(count).toEqual({ count: output.symbols.length });
+    } finally {
+      await rm(dir, { recursive: true, force: true });
+    }
+  });
+});
@@ -0,0 +1,225 @@
+/**
+ * Go stub tests
+ */
+
+import { execFileSync } from "node:child_process";
+import { mkdir, mkdtemp, rm, writeFile } from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect, beforeAll, afterAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { createConfig } from "../config.js";
+import { renderStubs } from "../stubs.js";
+
+const source = `// Copyright 2026 Example Authors.
+
+// Package store is a key-value store client.
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultTTL is how long entries live.
+const DefaultTTL = 10 * time.Minute
+
+// Store reads and writes entries.
+type Store struct {
+	// Bucket is the bucket to use.
+	Bucket string \`json:"bucket"\`
+	conn   fmt.Stringer
+}
+
+// Get returns the value of key.
+func (s *Store) Get(ctx context.Context, key string) (string, error) {
+	return s.conn.String(), nil
+}
+
+// Reset drops all entries.
+func (s *Store) Reset() {
+	s.conn = nil
+}
+
+// Getter reads entries.
+type Getter interface {
+	// Get returns the value of key.
+	Get(ctx context.Context, key string) (string, error)
+}
+`;
+
+const internals = `package client
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrClosed is returned after Close.
+var ErrClosed = errors.New("closed")
+
+// Default is the default client.
+var Default = newClient(time.Second)
+
+// Options configures a client.
+var Options = &options{retries: 3}
+
+// OnClose is called after Close.
+var OnClose = func(c *Client, err error) {}
+
+// Debug logs everything.
+const Debug level = 1
+
+// Client talks to the server.
+type Client struct {
+	// Timeout bounds each call.
+	Timeout time.Duration
+	opts    *options
+}
+
+// Closer closes things.
+type Closer interface {
+	sealed
+	// Close closes.
+	Close() error
+	// flush writes what's buffered.
+	flush(
+		force bool,
+	) error
+}
+
+// New returns a client.
+func New(opts *options) *Client { return newClient(time.Second) }
+
+// Items lists the items.
+func (c *Client) Items() list[string] { return nil }
+
+func newClient(timeout time.Duration) *Client { return &Client{Timeout: timeout} }
+`;
+
+const internalTypes = `package client
+
+type level int
+
+type (
+	options struct {
+		retries int
+	}
+	list[T any] []T
+)
+
+type sealed interface{ seal() }
+`;
+
+const hasGo = (() => {
+  try {
+    execFileSync("go", ["version"], { stdio: "ignore" });
+    return true;
+  } catch {
+    return false;
+  }
+})();
+
+describe("renderStubs", () => {
+  let root: string;
+  let stub: string;
+
+  beforeAll(async () => {
+    root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-stubs-"));
+    await writeFile(path.join(root, "store.go"), source);
+    await writeFile(path.join(root, "store_test.go"), "package store\n\nfunc Helper() {}\n");
+
+    const config = createConfig({ packageName: "store", packagePath: root });
+    const stubs = await renderStubs(await new GoExtractor(config).extract(), root);
+    expect([...stubs.keys()]).toEqual(["store.go"]);
+    stub = stubs.get("store.go")!;
+  });
+
+  afterAll(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it("should keep the file header, package clause, and used imports only", () => {
+    expect(stub).toMatch(/^\/\/ Copyright 2026 Example Authors\.\n\n\/\/ Package store/);
+    expect(stub).toContain('import (\n\t"context"\n\t"time"\n)');
+    expect(stub).not.toContain('"fmt"');
+  });
+
+  it("should replace function bodies", () => {
+    expect(stub).toContain(
+      "// Get returns the value of key.\n" +
+        "func (s *Store) Get(ctx context.Context, key string) (string, error) {\n" +
+        '\tpanic("stub")\n}',
+    );
+    expect(stub).toContain("func (s *Store) Reset() {}");
+    expect(stub).not.toContain("s.conn");
+  });
+
+  it("should keep exported fields and interface methods with their docs", () => {
+    expect(stub).toContain(
+      '\t// Bucket is the bucket to use.\n\tBucket string `json:"bucket"`\n}',
+    );
+    expect(stub).toContain(
+      "type Getter interface {\n\t// Get returns the value of key.\n" +
+        "\tGet(ctx context.Context, key string) (string, error)\n}",
+    );
+  });
+
+  it("should fold constants to their values", () => {
+    expect(stub).toContain("const DefaultTTL time.Duration = 600000000000");
+  });
+});
+
+describe.skipIf(!hasGo)("renderStubs with unexported names", () => {
+  let root: string;
+  let output: string;
+  let stubs: Map<string, string>;
+
+  beforeAll(async () => {
+    root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-stubs-"));
+    output = await mkdtemp(path.join(os.tmpdir(), "extractor-go-stubs-out-"));
+    await writeFile(path.join(root, "client.go"), internals);
+    await writeFile(path.join(root, "types.go"), internalTypes);
+
+    const config = createConfig({ packageName: "client", packagePath: root });
+    stubs = await renderStubs(await new GoExtractor(config).extract(), root);
+    await mkdir(output, { recursive: true });
+    await writeFile(path.join(output, "go.mod"), "module example.com/client\n\ngo 1.22\n");
+    for (const [file, stub] of stubs) {
+      await writeFile(path.join(output, file), stub);
+    }
+  });
+
+  afterAll(async () => {
+    await rm(root, { recursive: true, force: true });
+    await rm(output, { recursive: true, force: true });
+  });
+
+  it("should compile", () => {
+    expect(() =>
+      execFileSync("go", ["build", "./..."], { cwd: output, stdio: "pipe" }),
+    ).not.toThrow();
+  });
+
+  it("should declare the unexported types the API refers to opaquely", () => {
+    const types = stubs.get("types.go")!;
+    expect(types).toContain("type level int");
+    expect(types).toContain("type options struct{}");
+    expect(types).toContain("type list[T any] struct{}");
+    expect(types).toContain("type sealed interface{}");
+    expect(types).not.toContain("retries");
+  });
+
+  it("should leave out unexported initializers and interface methods", () => {
+    const client = stubs.get("client.go")!;
+    expect(client).toContain('var ErrClosed = errors.New("closed")');
+    expect(client).not.toContain("Default");
+    expect(client).toContain("var Options = &options{}");
+    expect(client).toContain("var OnClose func(c *Client, err error)");
+    expect(client).toContain("\tsealed\n\t// Close closes.\n\tClose() error\n}");
+    expect(client).not.toContain("flush");
+  });
+});
@@ -0,0 +1,81 @@
+/**
+ * Extraction timing tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect } from "vitest";
+
+import { TimingRecorder, formatTimings } from "../timings.js";
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("TimingRecorder", () => {
+  it("should aggregate file timings by package, slowest first", () => {
+    const timings = new TimingRecorder();
+    timings.recordFile("client.go", "parse", 2);
+    timings.recordFile("internal/auth/auth.go", "parse", 5);
+    timings.recordFile("internal/auth/token.go", "parse", 1);
+    timings.recordFile("internal/auth/auth.go", "analyze", 4);
+    timings.recordRun("typecheck", 3);
+
+    expect(timings.report()).toEqual({
+      packages: [
+        { package: "internal/auth", files: 2, phases: { parse: 6, analyze: 4 }, total: 10 },
+        { package: "", files: 1, phases: { parse: 2 }, total: 2 },
+      ],
+      run: { typecheck: 3 },
+      total: 15,
+    });
+  });
+
+  it("should charge timed work and pass its result through", () => {
+    const timings = new TimingRecorder();
+    expect(timings.timeRun("render", () => "done")).toBe("done");
+    expect(timings.report().run.render).toBeGreaterThanOrEqual(0);
+  });
+});
+
+describe("formatTimings", () => {
+  it("should print one row per package plus cross-package and total rows", () => {
+    const timings = new TimingRecorder();
+    timings.recordFile("client.go", "parse", 2);
+    timings.recordRun("render", 1);
+
+    expect(formatTimings(timings.report()).split("\n")).toEqual([
+      "Package          Files  parse  typecheck  analyze  render  Total",
+      ".                    1  2.0ms          -        -       -  2.0ms",
+      "(cross-package)             -          -        -   1.0ms  1.0ms",
+      "Total                1  2.0ms          -        -   1.0ms  3.0ms",
+    ]);
+  });
+
+  it("should collapse packages past the limit", () => {
+    const timings = new TimingRecorder();
+    for (const dir of ["a", "b", "c"]) {
+      timings.recordFile(`${dir}/x.go`, "parse", 1);
+    }
+    expect(formatTimings(timings.report(), 1)).toContain("... 2 more package(s)");
+  });
+});
+
+describe("GoExtractor timings", () => {
+  it("should record parse and analyze time for every fixture file", async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const timings = new TimingRecorder();
+    const result = await new GoExtractor(config, timings).extract();
+    new GoTransformer(result, config, timings).transform();
+
+    const { packages, run } = timings.report();
+    expect(packages.map((p) => p.package)).toEqual([""]);
+    expect(packages[0].phases.parse).toBeGreaterThan(0);
+    expect(packages[0].phases.analyze).toBeGreaterThan(0);
+    expect(run.typecheck).toBeGreaterThanOrEqual(0);
+  });
+});
@@ -0,0 +1,73 @@
+/**
+ * Navigation tree tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput, type ExtractorOutput } from "../output.js";
+import { buildToc, type TocNode } from "../toc.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("buildToc", () => {
+  let output: ExtractorOutput;
+  let toc: TocNode;
+
+  const find = (node: TocNode, title: string): TocNode | undefined =>
+    node.title === title
+      ? node
+      : node.children?.map((child) => find(child, title)).find(Boolean);
+
+  beforeAll(async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const result = await new GoExtractor(config).extract();
+    output = buildOutput(result, config, new GoTransformer(result, config).transform());
+    toc = buildToc(output);
+  });
+
+  it("should nest packages and kind groups under the module", () => {
+    expect(toc).toMatchObject({ kind: "module", title: "github.com/example/testpkg", slug: "/" });
+    expect(toc.children!.map((node) => [node.kind, node.title, node.slug])).toEqual([
+      ["package", "test-package", "/"],
+    ]);
+    expect(toc.children![0].children!.map((node) => node.title)).toEqual([
+      "Types",
+      "Functions",
+      "Constants",
+      "Variables",
+    ]);
+  });
+
+  it("should list symbols with slugs and methods under their type", () => {
+    const client = find(toc, "Client")!;
+
+    expect(client).toMatchObject({
+      kind: "class",
+      slug: "/Client",
+      id: "pkg_go_test_package:Client",
+    });
+    expect(client.children!.map((node) => node.title)).toEqual([
+      "Close",
+      "Get",
+      "Post",
+      "SetTimeout",
+    ]);
+  });
+
+  it("should flag deprecated symbols", () => {
+    expect(find(toc, "Retry")?.deprecated).toBe(true);
+    expect(find(toc, "NewClient")?.deprecated).toBeUndefined();
+  });
+
+  it("should leave out unexported symbols", () => {
+    expect(find(toc, "unexportedConst")).toBeUndefined();
+  });
+});
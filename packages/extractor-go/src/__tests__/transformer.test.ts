@@ -2,15 +2,18 @@
  * GoTransformer tests
  */
 
+import { mkdtemp, rm, writeFile } from "node:fs/promises";
+import os from "node:os";
 import path from "node:path";
 import url from "node:url";
 
-import { describe, it, expect, beforeAll } from "vitest";
+import { describe, it, expect, beforeAll, vi } from "vitest";
 import type { SymbolRecord, MemberReference } from "@langchain/ir-schema";
 
 import { GoExtractor, type ExtractionResult } from "../extractor.js";
-import { GoTransformer } from "../transformer.js";
+import { GoTransformer, type GoSymbolRecord } from "../transformer.js";
 import { createConfig, type GoExtractorConfig } from "../config.js";
+import { groupCategories } from "../output.js";
 
 const __filename = url.fileURLToPath(import.meta.url);
 const __dirname = path.dirname(__filename);
@@ -185,6 +188,291 @@ describe("GoTransformer", () => {
     });
   });
 
+  describe("context detection", () => {
+    const find = (qualifiedName: string) =>
+      symbols.find((s) => s.qualifiedName === qualifiedName) as GoSymbolRecord;
+
+    it("should flag functions taking a context first", () => {
+      expect(find("Ping").context).toEqual({ acceptsContext: true });
+    });
+
+    it("should leave functions without a context unmarked", () => {
+      expect(find("Connect").context).toBeUndefined();
+    });
+
+    it("should pair Foo and FooContext functions", () => {
+      expect(find("SearchContext").context).toEqual({
+        acceptsContext: true,
+        contextFreeVariant: "pkg_go_test_package:Search",
+      });
+      expect(find("Search").context).toEqual({
+        acceptsContext: false,
+        contextVariant: "pkg_go_test_package:SearchContext",
+      });
+    });
+
+    it("should pair methods of the same type", () => {
+      expect(find("Index.FlushWithContext").context!.contextFreeVariant).toBe(
+        "pkg_go_test_package:Index_Flush",
+      );
+      expect(find("Index.Flush").context!.contextVariant).toBe(
+        "pkg_go_test_package:Index_FlushWithContext",
+      );
+    });
+  });
+
+  describe("sealed interfaces", () => {
+    it("should flag sealed interfaces and link exported implementations", () => {
+      const message = symbols.find((s) => s.name === "Message") as GoSymbolRecord;
+      expect(message.sealed).toBe(true);
+      expect(message.implementations).toEqual([
+        { name: "AIMessage", refId: "pkg_go_test_package:AIMessage" },
+        { name: "systemMessage", refId: undefined },
+      ]);
+    });
+
+    it("should explain the restriction in the docs", () => {
+      const message = symbols.find((s) => s.name === "Message")!;
+      expect(message.docs.description).toContain("This interface is sealed");
+      expect(message.docs.description).toContain("Implementations: `AIMessage`, `systemMessage`.");
+    });
+
+    it("should leave open interfaces unmarked", () => {
+      const storage = symbols.find((s) => s.name === "Storage") as GoSymbolRecord;
+      expect(storage.sealed).toBeUndefined();
+    });
+
+    it("should emit asserted interfaces as implements relations", () => {
+      const buffer = symbols.find((s) => s.name === "Buffer")!;
+      expect(buffer.relations?.implements).toEqual(["Closer", "io.Writer"]);
+    });
+
+    it("should emit embedded interfaces as extends relations", () => {
+      const rwc = symbols.find((s) => s.name === "ReadWriteCloser")!;
+      expect(rwc.relations).toEqual({ extends: ["io.Reader", "io.Writer", "Closer"] });
+    });
+
+    it("should emit exported types embedded in structs as extends relations", () => {
+      expect(symbols.find((s) => s.name === "Store")!.relations).toEqual({ extends: ["Buffer"] });
+      expect(symbols.find((s) => s.name === "Options")!.relations).toBeUndefined();
+    });
+  });
+
+  describe("directives", () => {
+    it("should keep directive comments out of docs", () => {
+      const setAffinity = symbols.find((s) => s.name === "SetAffinity")!;
+      expect(setAffinity.docs.summary).toBe("SetAffinity pins the calling thread to a CPU.");
+      expect(setAffinity.docs.description).toBeUndefined();
+      expect(symbols.find((s) => s.name === "Spin")!.docs.summary).toBe("");
+    });
+
+    it("should record the build constraint of the declaring file", () => {
+      const setAffinity = symbols.find((s) => s.name === "SetAffinity") as GoSymbolRecord;
+      expect(setAffinity.buildConstraint).toBe("linux && !android");
+      expect(
+        (symbols.find((s) => s.name === "Connect") as GoSymbolRecord).buildConstraint,
+      ).toBeUndefined();
+    });
+  });
+
+  describe("examples", () => {
+    it("should attach examples to the symbols they document", () => {
+      const connect = symbols.find((s) => s.name === "Connect") as GoSymbolRecord;
+
+      expect(connect.docs.examples!.map((e) => e.name)).toEqual([
+        "ExampleConnect",
+        "ExampleConnect_retry",
+      ]);
+      expect(connect.docs.examples![0]).toMatchObject({
+        language: "go",
+        output: "true",
+        source: { path: "example_test.go", line: 9 },
+      });
+      expect(connect.docs.examples![1]).toMatchObject({
+        title: "Retry",
+        description: "Retries are left to the caller.",
+      });
+    });
+
+    it("should attach method examples to the method symbol", () => {
+      const get = symbols.find((s) => s.qualifiedName === "Client.Get") as GoSymbolRecord;
+      expect(get.docs.examples!.map((e) => e.name)).toEqual(["ExampleClient_Get"]);
+    });
+
+    it("should drop examples of unknown symbols", async () => {
+      // Written outside the fixtures, where go vet rejects examples of unknown symbols
+      const root = await mkdtemp(path.join(os.tmpdir(), "transformer-go-examples-"));
+      await writeFile(path.join(root, "client.go"), "package client\n\nfunc Dial() {}\n");
+      await writeFile(
+        path.join(root, "client_test.go"),
+        "package client_test\n\nfunc ExampleDial() {}\n\nfunc ExampleMissing() {}\n",
+      );
+      const config = createConfig({ packageName: "client", packagePath: root });
+      const result = await new GoExtractor(config).extract();
+      await rm(root, { recursive: true, force: true });
+      const transformed = new GoTransformer(result, config).transform() as GoSymbolRecord[];
+
+      expect(result.examples!.map((e) => e.name)).toContain("ExampleMissing");
+      const names = transformed.flatMap((s) => s.docs.examples ?? []).map((e) => e.name);
+      expect(names).toEqual(["ExampleDial"]);
+    });
+  });
+
+  describe("doc links", () => {
+    it("should resolve doc links to symbol IDs and pkg.go.dev URLs", () => {
+      const buffer = symbols.find((s) => s.name === "Buffer") as GoSymbolRecord;
+      const [paragraph] = buffer.docs.blocks!;
+      const links = paragraph.kind === "paragraph" ? paragraph.text : [];
+
+      expect(links.filter((span) => span.kind === "docLink")).toEqual([
+        {
+          kind: "docLink",
+          text: "io.Writer",
+          target: "io.Writer",
+          url: "https://pkg.go.dev/io#Writer",
+        },
+        { kind: "docLink", text: "Closer", target: "Closer", refId: "pkg_go_test_package:Closer" },
+        {
+          kind: "docLink",
+          text: "Buffer.Write",
+          target: "Buffer.Write",
+          refId: "pkg_go_test_package:Buffer_Write",
+        },
+        {
+          kind: "docLink",
+          text: "Buffer.Close",
+          target: "Buffer.Close",
+          refId: "pkg_go_test_package:Buffer_Close",
+        },
+        {
+          kind: "docLink",
+          text: "encoding/json.NewEncoder",
+          target: "encoding/json.NewEncoder",
+          url: "https://pkg.go.dev/encoding/json#NewEncoder",
+        },
+      ]);
+    });
+
+    it("should leave unresolved doc links as plain text", () => {
+      const buffer = symbols.find((s) => s.name === "Buffer") as GoSymbolRecord;
+      const paragraph = buffer.docs.blocks![0];
+
+      expect(paragraph.kind === "paragraph" && paragraph.text.at(-1)).toEqual({
+        kind: "plain",
+        text: ". See also [Flusher].",
+      });
+    });
+  });
+
+  describe("field deprecation", () => {
+    let retryPolicy: GoSymbolRecord;
+
+    beforeAll(() => {
+      retryPolicy = symbols.find((s) => s.name === "RetryPolicy") as GoSymbolRecord;
+    });
+
+    it("should mark fields with a Deprecated paragraph", () => {
+      const backoff = retryPolicy.members!.find((m) => m.name === "Backoff");
+      expect(backoff!.deprecated).toEqual({
+        isDeprecated: true,
+        message: "Use BackoffDuration instead. It will be removed in the next major release.",
+        replacement: "BackoffDuration",
+        replacedBy: {
+          name: "BackoffDuration",
+          refId: "pkg_go_test_package:RetryPolicy_BackoffDuration",
+        },
+      });
+    });
+
+    it("should mark fields whose comment begins with Deprecated", () => {
+      const jitter = retryPolicy.members!.find((m) => m.name === "Jitter");
+      expect(jitter!.deprecated).toEqual({
+        isDeprecated: true,
+        message: "Jitter is always applied.",
+      });
+    });
+
+    it("should leave other fields undeprecated", () => {
+      const maxAttempts = retryPolicy.members!.find((m) => m.name === "MaxAttempts");
+      expect(maxAttempts!.deprecated).toBeUndefined();
+    });
+
+    it("should not deprecate the containing type", () => {
+      expect(retryPolicy.docs.deprecated).toBeUndefined();
+    });
+  });
+
+  describe("symbol deprecation", () => {
+    it("should move a Deprecated paragraph into deprecation metadata", () => {
+      const retry = symbols.find((s) => s.name === "Retry")!;
+
+      expect(retry.docs.deprecated).toMatchObject({
+        isDeprecated: true,
+        message: "Use RetryPolicy with Do instead.",
+      });
+      expect(retry.tags.stability).toBe("deprecated");
+      expect(retry.docs.summary).toBe("Retry calls f until it succeeds or the policy gives up.");
+      expect(retry.docs.description).toBeUndefined();
+    });
+
+    it("should recognize the legacy DEPRECATED form inside a paragraph", () => {
+      const parseConfig = symbols.find((s) => s.name === "ParseConfig") as GoSymbolRecord;
+
+      expect(parseConfig.docs.deprecated).toMatchObject({
+        isDeprecated: true,
+        message: "Use LoadConfig instead.",
+      });
+      expect(parseConfig.tags.stability).toBe("deprecated");
+      expect(JSON.stringify(parseConfig.docs.blocks)).not.toContain("DEPRECATED");
+    });
+
+    it("should link the replacement a deprecation message names", () => {
+      const parseConfig = symbols.find((s) => s.name === "ParseConfig") as GoSymbolRecord;
+      const retry = symbols.find((s) => s.name === "Retry") as GoSymbolRecord;
+
+      expect(parseConfig.docs.deprecated!.replacedBy).toEqual({
+        name: "LoadConfig",
+        refId: "pkg_go_test_package:LoadConfig",
+      });
+      expect(parseConfig.docs.deprecated!.replacement).toBe("LoadConfig");
+      expect(retry.docs.deprecated!.replacedBy!.refId).toBe("pkg_go_test_package:RetryPolicy");
+    });
+
+    it("should not link replacements without a Use suggestion", () => {
+      const retryPolicy = symbols.find((s) => s.name === "RetryPolicy") as GoSymbolRecord;
+      const jitter = retryPolicy.members!.find((m) => m.name === "Jitter");
+      expect(jitter!.deprecated!.replacedBy).toBeUndefined();
+    });
+
+    it("should leave other symbols stable", () => {
+      expect(symbols.find((s) => s.name === "Connect")!.tags.stability).toBe("stable");
+    });
+  });
+
+  describe("field defaults", () => {
+    it("should attach constructor defaults to field members", () => {
+      const config = symbols.find((s) => s.name === "Config") as GoSymbolRecord;
+      const port = config.members!.find((m) => m.name === "Port");
+      expect(port!.defaults).toEqual([{ value: "8080", constructor: "WithDefaults" }]);
+    });
+  });
+
+  describe("field docs", () => {
+    it("should include field docs in the member references", () => {
+      const options = symbols.find((s) => s.name === "ServerOptions") as GoSymbolRecord;
+      const port = options.members!.find((m) => m.name === "Port");
+      expect(port!.doc).toBe("server port");
+    });
+
+    it("should leave the deprecation notice out of field docs", () => {
+      const retryPolicy = symbols.find((s) => s.name === "RetryPolicy") as GoSymbolRecord;
+      const doc = (name: string) => retryPolicy.members!.find((m) => m.name === name)!.doc;
+
+      expect(doc("Backoff")).toBe("Backoff is the delay between attempts in milliseconds.");
+      expect(doc("Jitter")).toBeUndefined();
+    });
+  });
+
   describe("field with tag transformation", () => {
     let apiKeyField: MemberReference | undefined;
 
@@ -236,6 +524,30 @@ describe("GoTransformer", () => {
     it("should include alias in signature", () => {
       expect(middlewareSymbol!.signature).toContain("=");
     });
+
+    it("should keep type parameters and aliased type refs of generic aliases", () => {
+      const index = symbols.find((s) => s.name === "PageIndex")!;
+      expect(index.kind).toBe("typeAlias");
+      expect(index.typeParams).toEqual([{ name: "K", constraint: "comparable" }]);
+      expect(index.typeRefs!.map((ref) => ref.name).sort()).toEqual(["Page", "Result"]);
+    });
+
+    it("should decompose the aliased type into linkable components", () => {
+      const index = symbols.find((s) => s.name === "PageIndex") as GoSymbolRecord;
+      expect(index.typeExpr).toMatchObject({
+        kind: "map",
+        key: { kind: "named", name: "K" },
+        value: {
+          kind: "named",
+          name: "Page",
+          refId: "pkg_go_test_package:Page",
+          typeArgs: [
+            { kind: "named", name: "K" },
+            { kind: "named", name: "Result", refId: "pkg_go_test_package:Result" },
+          ],
+        },
+      });
+    });
   });
 
   describe("function transformation", () => {
@@ -262,6 +574,25 @@ describe("GoTransformer", () => {
       expect(connectSymbol!.docs.summary).toContain("establishes a connection");
     });
 
+    it("should parse the doc comment into blocks", () => {
+      const blocks = (connectSymbol as GoSymbolRecord).docs.blocks!;
+      expect(blocks.map((b) => b.kind)).toEqual(["paragraph", "paragraph", "code"]);
+    });
+
+    it("should keep the example code block verbatim", () => {
+      const code = (connectSymbol as GoSymbolRecord).docs.blocks!.at(-1);
+      const example = [
+        'client, err := Connect("api.example.com", "my-api-key")',
+        "if err != nil {",
+        "    log.Fatal(err)",
+        "}",
+        "defer client.Close()",
+      ].join("\n");
+
+      expect(code).toEqual({ kind: "code", language: "go", text: example });
+      expect(connectSymbol!.docs.description).toContain("Example:\n\n```go\n" + example + "\n```");
+    });
+
     it("should include parameters", () => {
       expect(connectSymbol!.params).toBeDefined();
       expect(connectSymbol!.params!.length).toBe(2);
@@ -272,6 +603,95 @@ describe("GoTransformer", () => {
     });
   });
 
+  describe("generic type references", () => {
+    it("should keep type arguments as structured references", () => {
+      const collect = symbols.find((s) => s.name === "Collect") as GoSymbolRecord;
+      expect(collect.returns!.typeExpr).toMatchObject({
+        kind: "map",
+        value: {
+          kind: "named",
+          name: "Page",
+          refId: "pkg_go_test_package:Page",
+          typeArgs: [
+            { kind: "named", name: "string", builtin: true },
+            { kind: "named", name: "Result", refId: "pkg_go_test_package:Result" },
+          ],
+        },
+      });
+    });
+
+    it("should resolve instantiated parameter types", () => {
+      const collect = symbols.find((s) => s.name === "Collect") as GoSymbolRecord;
+      expect(collect.params![0].typeExpr).toMatchObject({
+        kind: "named",
+        name: "List",
+        refId: "pkg_go_test_package:List",
+      });
+    });
+
+    it("should list every linkable type argument in typeRefs", () => {
+      const collect = symbols.find((s) => s.name === "Collect")!;
+      const refIds = collect.typeRefs!.map((r) => r.refId);
+      expect(refIds).toContain("pkg_go_test_package:List");
+      expect(refIds).toContain("pkg_go_test_package:Page");
+      expect(refIds).toContain("pkg_go_test_package:Result");
+    });
+
+    it("should mark package-qualified types as external", () => {
+      const get = symbols.find((s) => s.qualifiedName === "Client.Get")!;
+      expect(get.typeRefs).toContainEqual({
+        name: "Context",
+        qualifiedName: "context.Context",
+        external: true,
+      });
+    });
+
+    it("should not link type parameters", () => {
+      const mapFn = symbols.find((s) => s.name === "Map")!;
+      expect(mapFn.typeParams).toEqual([
+        { name: "T", constraint: "any" },
+        { name: "U", constraint: "any" },
+      ]);
+      expect(mapFn.typeRefs).toBeUndefined();
+    });
+  });
+
+  describe("anonymous type literals", () => {
+    it("should expand inline struct parameters", () => {
+      const serve = symbols.find((s) => s.name === "Serve") as GoSymbolRecord;
+      expect(serve.params![0].typeExpr).toEqual({
+        kind: "struct",
+        fields: [{ name: "Name", type: { kind: "named", name: "string", builtin: true } }],
+      });
+    });
+
+    it("should expand inline interface parameters", () => {
+      const serve = symbols.find((s) => s.name === "Serve") as GoSymbolRecord;
+      expect(serve.params![1].typeExpr).toEqual({
+        kind: "interface",
+        embeds: [],
+        methods: [
+          {
+            name: "Close",
+            params: [],
+            results: [{ kind: "named", name: "error", builtin: true }],
+          },
+        ],
+      });
+    });
+
+    it("should resolve types referenced inside field literals", () => {
+      const options = symbols.find((s) => s.name === "Options") as GoSymbolRecord;
+      const hooks = options.members!.find((m) => m.name === "Hooks")!;
+      expect(hooks.typeExpr).toMatchObject({
+        kind: "interface",
+        embeds: [{ kind: "named", name: "Closer", refId: "pkg_go_test_package:Closer" }],
+        methods: [{ name: "OnStart", doc: "OnStart is called once the server is listening." }],
+      });
+      expect(options.typeRefs!.map((r) => r.name)).toContain("Closer");
+    });
+  });
+
   describe("constant transformation", () => {
     let timeoutSymbol: SymbolRecord | undefined;
 
@@ -291,6 +711,19 @@ describe("GoTransformer", () => {
       expect(timeoutSymbol!.signature).toContain("const");
       expect(timeoutSymbol!.signature).toContain("DefaultTimeout");
     });
+
+    it("should include the expression and its evaluated value", () => {
+      const pollInterval = symbols.find((s) => s.name === "PollInterval") as GoSymbolRecord;
+      expect(pollInterval.value).toEqual({
+        expression: "500 * time.Millisecond",
+        evaluated: {
+          value: "500000000",
+          type: "time.Duration",
+          underlyingType: "int64",
+          display: "500ms",
+        },
+      });
+    });
   });
 
   describe("variable transformation", () => {
@@ -311,6 +744,33 @@ describe("GoTransformer", () => {
     it("should include var in signature", () => {
       expect(errSymbol!.signature).toContain("var");
     });
+
+    it("should document function literal variables with their full signature", () => {
+      const shouldRetry = symbols.find((s) => s.name === "ShouldRetry") as GoSymbolRecord;
+      expect(shouldRetry.signature).toBe(
+        "var ShouldRetry func(ctx context.Context, resp *Response, err error) (bool, error)",
+      );
+      expect(shouldRetry.params!.map((p) => `${p.name} ${p.type}`)).toEqual([
+        "ctx context.Context",
+        "resp *Response",
+        "err error",
+      ]);
+      expect(shouldRetry.returns!.type).toBe("(bool, error)");
+      expect(shouldRetry.typeRefs!.map((ref) => ref.name)).toEqual(["Context", "Response"]);
+    });
+
+    it("should decompose composite types into key and element references", () => {
+      const routes = symbols.find((s) => s.name === "Routes") as GoSymbolRecord;
+      expect(routes.signature).toBe("var Routes map[string]Handler");
+      expect(routes.typeExpr).toEqual({
+        kind: "map",
+        key: { kind: "named", name: "string", builtin: true },
+        value: { kind: "named", name: "Handler", refId: "pkg_go_test_package:Handler" },
+      });
+      expect(routes.typeRefs).toEqual([
+        { name: "Handler", qualifiedName: "Handler", refId: "pkg_go_test_package:Handler" },
+      ]);
+    });
   });
 
   describe("Go doc to Markdown conversion", () => {
@@ -319,11 +779,9 @@ describe("GoTransformer", () => {
       expect(clientSymbol!.docs.description).toBeDefined();
     });
 
-    it("should convert DEPRECATED to markdown", () => {
+    it("should strip deprecation notices from the description", () => {
       const parseConfig = symbols.find((s) => s.name === "ParseConfig");
-      if (parseConfig?.docs?.description) {
-        expect(parseConfig.docs.description).toContain("**Deprecated:**");
-      }
+      expect(parseConfig!.docs.description ?? "").not.toContain("DEPRECATED");
     });
   });
 });
@@ -353,6 +811,489 @@ describe("GoTransformer without repo/sha", () => {
   });
 });
 
+describe("GoTransformer feedback URLs", () => {
+  async function transformWith(overrides: Partial<GoExtractorConfig>): Promise<GoSymbolRecord[]> {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      sha: "abc123def456",
+      ...overrides,
+    });
+    const result = await new GoExtractor(config).extract();
+    return new GoTransformer(result, config).transform();
+  }
+
+  it("should emit a pre-filled GitHub issue URL per symbol", async () => {
+    const symbols = await transformWith({ repo: "langchain-ai/test-repo" });
+    const get = symbols.find((s) => s.qualifiedName === "Client.Get")!;
+    const url = new URL(get.urls.feedback!);
+
+    expect(url.origin + url.pathname).toBe(
+      "https://github.com/langchain-ai/test-repo/issues/new",
+    );
+    expect(url.searchParams.get("title")).toBe("Docs: Client.Get");
+    expect(url.searchParams.get("body")).toContain(
+      "https://github.com/langchain-ai/test-repo/blob/abc123def456/types.go#L",
+    );
+    expect(url.searchParams.get("labels")).toBe("documentation");
+  });
+
+  it("should mention owners in the issue body", async () => {
+    const symbols = await transformWith({
+      repo: "langchain-ai/test-repo",
+      owners: { "*": ["@langchain-ai/go"] },
+    });
+    const client = symbols.find((s) => s.name === "Client")!;
+    const url = new URL(client.urls.feedback!);
+    expect(url.searchParams.get("body")).toContain("Owners: @langchain-ai/go");
+  });
+
+  it("should apply a custom template", async () => {
+    const symbols = await transformWith({
+      repo: "langchain-ai/test-repo",
+      feedbackUrlTemplate: "https://docs.example.com/feedback?symbol={symbol}&at={file}:{line}",
+    });
+    const connect = symbols.find((s) => s.name === "Connect")!;
+    expect(connect.urls.feedback).toMatch(
+      /^https:\/\/docs\.example\.com\/feedback\?symbol=Connect&at=functions\.go:\d+$/,
+    );
+  });
+
+  it("should omit the link when the template needs a repository", async () => {
+    const symbols = await transformWith({});
+    expect(symbols.every((s) => s.urls.feedback === undefined)).toBe(true);
+  });
+});
+
+describe("GoTransformer context variant pairing", () => {
+  it("should only flag context use when pairing is disabled", async () => {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      pairContextVariants: false,
+    });
+    const result = await new GoExtractor(config).extract();
+    const symbols = new GoTransformer(result, config).transform();
+
+    expect(symbols.find((s) => s.name === "SearchContext")!.context).toEqual({
+      acceptsContext: true,
+    });
+    expect(symbols.find((s) => s.name === "Search")!.context).toBeUndefined();
+  });
+});
+
+describe("GoTransformer test helpers", () => {
+  it("should flag symbols exported from test files", async () => {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      testHelpers: true,
+    });
+    const result = await new GoExtractor(config).extract();
+    const symbols = new GoTransformer(result, config).transform();
+
+    expect(symbols.find((s) => s.name === "SetRetryDelay")!.testHelper).toBe(true);
+    expect(symbols.find((s) => s.name === "TestConnect")).toBeUndefined();
+    expect(symbols.find((s) => s.name === "Connect")!.testHelper).toBeUndefined();
+  });
+});
+
+describe("GoTransformer promoted methods", () => {
+  let symbols: GoSymbolRecord[];
+
+  beforeAll(async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    symbols = new GoTransformer(await new GoExtractor(config).extract(), config).transform();
+  });
+
+  it("should promote exported methods of unexported embedded types", () => {
+    const store = symbols.find((s) => s.name === "Store")!;
+    const ping = store.members!.find((m) => m.name === "Ping")!;
+    expect(ping).toMatchObject({ kind: "method", promotedFrom: "baseClient" });
+
+    const symbol = symbols.find((s) => s.qualifiedName === "Store.Ping")!;
+    expect(symbol.promotedFrom).toBe("baseClient");
+    expect(symbol.docs.description).toContain(
+      "Promoted from the embedded type `baseClient`, an unexported implementation detail.",
+    );
+  });
+
+  it("should prefer the struct's own methods", () => {
+    const close = symbols.find((s) => s.qualifiedName === "Store.Close")!;
+    expect(close.promotedFrom).toBeUndefined();
+    expect(close.docs.summary).toBe("Close flushes pending writes and closes the connection.");
+    expect(symbols.filter((s) => s.qualifiedName === "Store.Close")).toHaveLength(1);
+  });
+
+  it("should not emit the unexported type or its unexported methods", () => {
+    expect(symbols.find((s) => s.name === "baseClient")).toBeUndefined();
+    expect(symbols.find((s) => s.qualifiedName === "Store.reset")).toBeUndefined();
+  });
+});
+
+describe("GoTransformer generated files", () => {
+  it("should mark symbols from generated files", async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    const symbols = new GoTransformer(await new GoExtractor(config).extract(), config).transform();
+
+    expect(symbols.find((s) => s.name === "MockStorage")!.generated).toBe(true);
+    expect(symbols.find((s) => s.name === "Client")!.generated).toBeUndefined();
+  });
+});
+
+describe("GoTransformer iterators", () => {
+  let symbols: GoSymbolRecord[];
+
+  beforeAll(async () => {
+    const config = createConfig({ packageName: "test-package", packagePath: fixturesPath });
+    symbols = new GoTransformer(await new GoExtractor(config).extract(), config).transform();
+  });
+
+  it("should tag functions returning iter.Seq", () => {
+    expect(symbols.find((s) => s.name === "Values")!.iterator).toEqual({
+      kind: "seq",
+      yields: ["T"],
+      usage: "for v := range Values(...) { ... }",
+    });
+  });
+
+  it("should tag methods returning iter.Seq2", () => {
+    const all = symbols.find((s) => s.qualifiedName === "List.All")!;
+    expect(all.iterator).toEqual({
+      kind: "seq2",
+      yields: ["int", "T"],
+      usage: "for k, v := range l.All() { ... }",
+    });
+
+    const list = symbols.find((s) => s.name === "List")!;
+    expect(list.members!.find((m) => m.name === "All")!.iterator).toBe(true);
+    expect(list.members!.find((m) => m.name === "Push")!.iterator).toBeUndefined();
+  });
+
+  it("should leave other functions untagged", () => {
+    expect(symbols.find((s) => s.name === "Map")!.iterator).toBeUndefined();
+  });
+});
+
+describe("GoTransformer visibility tiers", () => {
+  // Kept under testdata so tiered symbols don't leak into the shared fixtures
+  const tiersPath = path.join(fixturesPath, "testdata");
+
+  async function transformWith(overrides: Partial<GoExtractorConfig>): Promise<GoSymbolRecord[]> {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: tiersPath,
+      ...overrides,
+    });
+    const result = await new GoExtractor(config).extract();
+    return new GoTransformer(result, config).transform();
+  }
+
+  it("should include only public symbols by default", async () => {
+    const symbols = await transformWith({});
+    const names = symbols.map((s) => s.qualifiedName);
+    expect(names).toEqual(["ExperimentalRouter"]);
+    expect(names).not.toContain("Gateway");
+    expect(names).not.toContain("Gateway.Route");
+    expect(names).not.toContain("Drain");
+  });
+
+  it("should include partner symbols and their members with a tier", async () => {
+    const symbols = await transformWith({ includeTiers: ["public", "partner"] });
+    const gateway = symbols.find((s) => s.name === "Gateway")!;
+    expect(gateway.tier).toBe("partner");
+    expect(gateway.members!.map((m) => m.name)).toEqual(["Route", "Region"]);
+    expect(symbols.find((s) => s.qualifiedName === "Gateway.Route")!.tier).toBe("partner");
+    expect(symbols.find((s) => s.name === "ExperimentalRouter")!.tier).toBeUndefined();
+  });
+
+  it("should let members override the tier of their type", async () => {
+    const symbols = await transformWith({ includeTiers: ["public", "partner", "internal"] });
+    const gateway = symbols.find((s) => s.name === "Gateway")!;
+    const debugToken = gateway.members!.find((m) => m.name === "DebugToken")!;
+    expect(debugToken.tier).toBe("internal");
+    expect(symbols.find((s) => s.name === "Drain")!.tier).toBe("internal");
+  });
+
+  it("should assign tiers from configured name patterns", async () => {
+    const symbols = await transformWith({
+      tiers: { "Experimental*": "partner" },
+      includeTiers: ["public", "partner"],
+    });
+    expect(symbols.find((s) => s.name === "ExperimentalRouter")!.tier).toBe("partner");
+  });
+
+  it("should strip the annotation from rendered docs", async () => {
+    const symbols = await transformWith({ includeTiers: ["partner"] });
+    const gateway = symbols.find((s) => s.name === "Gateway")!;
+    expect(gateway.docs.summary).toBe("Gateway routes requests to partner backends.");
+    expect(gateway.docs.description ?? "").not.toContain("Visibility:");
+  });
+});
+
+describe("GoTransformer Markdown rendering", () => {
+  async function transformWith(overrides: Partial<GoExtractorConfig> = {}) {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      ...overrides,
+    });
+    const result = await new GoExtractor(config).extract();
+    return new GoTransformer(result, config).transform();
+  }
+
+  it("should not render Markdown by default", async () => {
+    const symbols = await transformWith();
+    expect(symbols.some((s) => s.docs.markdown)).toBe(false);
+  });
+
+  it("should render docs with doc links to canonical URLs", async () => {
+    const symbols = await transformWith({ renderMarkdown: true });
+    const buffer = symbols.find((s) => s.name === "Buffer")!;
+
+    expect(buffer.docs.markdown).toBe(
+      "Buffer collects written bytes. It is an [io.Writer](https://pkg.go.dev/io#Writer) and a " +
+        "[Closer](/Closer): call [Buffer.Write](/Buffer.Write) to append and " +
+        "[Buffer.Close](/Buffer.Close) to reset, or encode values into it with " +
+        "[encoding/json.NewEncoder](https://pkg.go.dev/encoding/json#NewEncoder). " +
+        "See also \\[Flusher\\].",
+    );
+  });
+
+  it("should render the sealed note with links to exported implementations", async () => {
+    const symbols = await transformWith({ renderMarkdown: true });
+    const message = symbols.find((s) => s.name === "Message")!;
+
+    expect(message.docs.markdown).toContain("\n\nThis interface is sealed");
+    expect(message.docs.markdown).toContain(
+      "Implementations: [AIMessage](/AIMessage), systemMessage.",
+    );
+  });
+
+  it("should render HTML with doc links through the symbol URL template", async () => {
+    const symbols = await transformWith({
+      renderHtml: true,
+      symbolUrlTemplate: "/go/{package}/{symbol}",
+    });
+    const buffer = symbols.find((s) => s.name === "Buffer")!;
+
+    expect(buffer.docs.markdown).toBeUndefined();
+    expect(buffer.docs.html).toContain('<a href="/go/test-package/Closer">Closer</a>');
+    expect(buffer.docs.html).toContain('<a href="/go/test-package/Buffer.Write">Buffer.Write</a>');
+    expect(buffer.docs.html).toContain('<a href="https://pkg.go.dev/io#Writer">io.Writer</a>');
+  });
+});
+
+describe("GoTransformer test functions", () => {
+  async function transformWith(overrides: Partial<GoExtractorConfig> = {}) {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      ...overrides,
+    });
+    const result = await new GoExtractor(config).extract();
+    return new GoTransformer(result, config).transform();
+  }
+
+  it("should not collect benchmarks and fuzz targets by default", async () => {
+    const symbols = await transformWith();
+    expect(symbols.some((s) => s.tests)).toBe(false);
+  });
+
+  it("should link tests to the symbol their name points to", async () => {
+    const symbols = await transformWith({ testFunctions: true });
+    const connect = symbols.find((s) => s.name === "Connect")!;
+
+    expect(connect.tests).toEqual([
+      {
+        kind: "benchmark",
+        name: "BenchmarkConnect",
+        doc: "BenchmarkConnect measures connection setup.",
+        source: { repo: "", sha: "", path: "bench_test.go", line: 10 },
+      },
+      expect.objectContaining({ kind: "fuzz", name: "FuzzConnect_hosts", doc: undefined }),
+    ]);
+  });
+
+  it("should link other tests to the package symbols their body uses", async () => {
+    const symbols = await transformWith({ testFunctions: true });
+    const newClient = symbols.find((s) => s.name === "NewClient")!;
+
+    expect(newClient.tests!.map((t) => t.name)).toEqual(["BenchmarkRoundTrip"]);
+    expect(symbols.find((s) => s.qualifiedName === "Client.Get")!.tests).toBeUndefined();
+    expect(symbols.flatMap((s) => s.tests ?? []).map((t) => t.name)).not.toContain(
+      "TestConnect",
+    );
+  });
+});
+
+describe("GoTransformer sidecar docs", () => {
+  it("should merge Markdown files into the docs of the symbols they name", async () => {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      sidecarDocs: "docs",
+    });
+    const result = await new GoExtractor(config).extract();
+    const warn = vi.spyOn(console, "warn");
+    const symbols = new GoTransformer(result, config).transform();
+    const warnings = warn.mock.calls.map((call) => String(call[0]));
+    warn.mockRestore();
+
+    const client = symbols.find((s) => s.name === "Client")!;
+    expect(client.docs.sidecar).toEqual({
+      path: "docs/Client.md",
+      markdown:
+        "## Connection pooling\n\nA Client keeps idle connections open between calls. Reuse " +
+        "one Client per\nprocess rather than creating one per request.",
+    });
+    expect(symbols.find((s) => s.qualifiedName === "Client.Get")!.docs.sidecar?.path).toBe(
+      "docs/guides/Client.Get.md",
+    );
+    expect(warnings).toContain("Warning: docs/Missing.md documents no symbol");
+  });
+
+  it("should fail on a missing sidecar directory", async () => {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      sidecarDocs: "guides",
+    });
+    await expect(new GoExtractor(config).extract()).rejects.toThrow(
+      "Sidecar docs directory not found",
+    );
+  });
+});
+
+describe("GoTransformer doc tags", () => {
+  async function transformWith(overrides: Partial<GoExtractorConfig> = {}) {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      ...overrides,
+    });
+    const result = await new GoExtractor(config).extract();
+    return new GoTransformer(result, config).transform();
+  }
+
+  it("should split tags into annotations and out of the prose", async () => {
+    const symbols = await transformWith();
+    const reranker = symbols.find((s) => s.name === "Reranker")!;
+
+    expect(reranker.docs.annotations).toEqual({
+      experimental: true,
+      category: "retrievers",
+      since: "v0.3.0",
+    });
+    expect(reranker.docs.summary).toBe("Reranker reorders retrieved documents by relevance.");
+    expect(reranker.docs.description).toBeUndefined();
+    expect(JSON.stringify(reranker.docs.blocks)).not.toContain("@");
+  });
+
+  it("should map @experimental to the stability and @since to the version info", async () => {
+    const symbols = await transformWith();
+    const reranker = symbols.find((s) => s.name === "Reranker")!;
+    const constructor = symbols.find((s) => s.name === "NewReranker")!;
+
+    expect(reranker.tags.stability).toBe("experimental");
+    expect(reranker.versionInfo).toEqual({ since: "v0.3.0" });
+    expect(constructor.tags.stability).toBe("stable");
+  });
+
+  it("should leave @ in prose alone", async () => {
+    const symbols = await transformWith();
+    const constructor = symbols.find((s) => s.name === "NewReranker")!;
+
+    expect(constructor.docs.annotations).toEqual({ since: "v0.3.0" });
+    expect(constructor.docs.description).toBe(
+      "NewReranker creates a Reranker that keeps the top n documents.\n\n" +
+        "Mail support@example.com for access; an @since inside prose is not a tag.",
+    );
+  });
+
+  it("should split tags off field docs", async () => {
+    const symbols = await transformWith();
+    const topN = symbols.find((s) => s.name === "Reranker")!.members![0];
+
+    expect(topN.doc).toBe("TopN caps the number of documents returned.");
+    expect(topN.annotations).toEqual({ since: "v0.4.0" });
+  });
+
+  it("should only recognize the configured tags", async () => {
+    const symbols = await transformWith({ docTags: ["category"] });
+    const reranker = symbols.find((s) => s.name === "Reranker")!;
+
+    expect(reranker.docs.annotations).toBeUndefined();
+    expect(reranker.tags.stability).toBe("stable");
+    expect(reranker.docs.description).toContain("@experimental @category retrievers");
+  });
+
+  it("should assign categories from @category tags and Category: lines", async () => {
+    const symbols = await transformWith();
+    const rerank = symbols.find((s) => s.name === "Rerank")!;
+
+    expect(symbols.find((s) => s.name === "Reranker")!.category).toBe("retrievers");
+    expect(rerank.category).toBe("retrievers");
+    expect(rerank.docs.annotations).toEqual({ category: "retrievers" });
+    expect(rerank.docs.summary).toBe(
+      "Rerank reorders documents with a Reranker keeping the top ten.",
+    );
+    expect(rerank.docs.description).not.toContain("Category");
+    expect(symbols.find((s) => s.name === "NewReranker")!.category).toBeUndefined();
+  });
+
+  it("should group symbols by category", async () => {
+    const symbols = await transformWith();
+
+    expect(groupCategories(symbols)).toEqual([
+      {
+        name: "retrievers",
+        symbolIds: ["pkg_go_test_package:Reranker", "pkg_go_test_package:Rerank"],
+      },
+    ]);
+    expect(groupCategories(symbols.filter((s) => !s.category))).toBeUndefined();
+  });
+
+  it("should list the symbols of See also sentences as related", async () => {
+    const symbols = await transformWith();
+
+    expect(symbols.find((s) => s.name === "Rerank")!.related).toEqual([
+      { name: "Reranker", refId: "pkg_go_test_package:Reranker" },
+      { name: "NewReranker", refId: "pkg_go_test_package:NewReranker" },
+      { name: "sort.Strings", url: "https://pkg.go.dev/sort#Strings" },
+    ]);
+    expect(symbols.find((s) => s.name === "Reranker")!.related).toBeUndefined();
+  });
+
+  it("should keep tables in doc comments verbatim", async () => {
+    const symbols = await transformWith();
+    const rerank = symbols.find((s) => s.name === "Rerank") as GoSymbolRecord;
+
+    expect(rerank.docs.blocks).toContainEqual({
+      kind: "verbatim",
+      text: "score  rank\n0.9    1\n0.5    2",
+    });
+    expect(rerank.docs.description).toContain("```text\nscore  rank\n0.9    1\n0.5    2\n```");
+  });
+
+  it("should mark concurrency notes as callouts", async () => {
+    const symbols = await transformWith();
+    const storage = symbols.find((s) => s.name === "Storage") as GoSymbolRecord;
+
+    expect(storage.docs.blocks).toEqual([
+      {
+        kind: "paragraph",
+        text: [{ kind: "plain", text: "Storage defines the interface for data storage." }],
+      },
+      {
+        kind: "callout",
+        callout: "concurrency",
+        text: [{ kind: "plain", text: "Implementations must be thread-safe." }],
+      },
+    ]);
+  });
+});
+
 describe("GoTransformer kind mapping", () => {
   let symbols: SymbolRecord[];
 
@@ -483,3 +1424,74 @@ describe("GoTransformer - method symbol emission", () => {
     expect(methodSymbols.length).toBe(expectedMethodCount);
   });
 });
+
+describe("GoTransformer symbol filters", () => {
+  async function transformWith(overrides: Partial<GoExtractorConfig> = {}) {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      ...overrides,
+    });
+    const result = await new GoExtractor(config).extract();
+    return new GoTransformer(result, config).transform();
+  }
+
+  it("should leave out excluded symbols and members", async () => {
+    const symbols = await transformWith({ excludeSymbols: ["Retry*", "Client.Close"] });
+    const names = symbols.map((s) => s.qualifiedName);
+    const client = symbols.find((s) => s.name === "Client")!;
+
+    expect(names.some((name) => name.startsWith("Retry"))).toBe(false);
+    expect(names).not.toContain("Client.Close");
+    expect(names).toContain("Client.Get");
+    expect(client.members!.map((m) => m.name)).not.toContain("Close");
+  });
+
+  it("should leave out the methods of excluded types", async () => {
+    const symbols = await transformWith({ excludeSymbols: ["Client"] });
+
+    expect(symbols.find((s) => s.name === "Client")).toBeUndefined();
+    expect(symbols.find((s) => s.qualifiedName === "Client.Get")).toBeUndefined();
+  });
+
+  it("should only extract included symbols with their members", async () => {
+    const symbols = await transformWith({ includeSymbols: ["Client", "NewClient"] });
+
+    expect(new Set(symbols.map((s) => s.qualifiedName.split(".")[0]))).toEqual(
+      new Set(["Client", "NewClient"]),
+    );
+    expect(symbols.find((s) => s.qualifiedName === "Client.Get")).toBeDefined();
+  });
+
+  it("should categorize symbols by name pattern, after @category tags", async () => {
+    const symbols = await transformWith({
+      categories: { "Client*": "clients", "Rerank*": "ranking" },
+    });
+
+    expect(symbols.find((s) => s.name === "Client")!.category).toBe("clients");
+    expect(symbols.find((s) => s.name === "Reranker")!.category).toBe("retrievers");
+    expect(symbols.find((s) => s.name === "Buffer")!.category).toBeUndefined();
+  });
+});
+
+describe("GoTransformer unexported symbols", () => {
+  it("should mark unexported symbols and members as private", async () => {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      exportedOnly: false,
+    });
+    const result = await new GoExtractor(config).extract();
+    const symbols = new GoTransformer(result, config).transform();
+    const client = symbols.find((s) => s.name === "Client")!;
+
+    expect(symbols.find((s) => s.name === "newRequestID")!.tags.visibility).toBe("private");
+    expect(symbols.find((s) => s.name === "baseClient")!.tags.visibility).toBe("private");
+    expect(symbols.find((s) => s.qualifiedName === "Store.reset")!.tags.visibility).toBe(
+      "private",
+    );
+    expect(client.tags.visibility).toBe("public");
+    expect(client.members!.find((m) => m.name === "internal")!.visibility).toBe("private");
+    expect(client.members!.find((m) => m.name === "BaseURL")!.visibility).toBe("public");
+  });
+});
@@ -0,0 +1,117 @@
+/**
+ * Doc truncation tests
+ */
+
+import { mkdtemp, rm, writeFile } from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect, beforeAll, afterAll } from "vitest";
+
+import { defaultDocLimits, docLimitFor, truncateDoc } from "../truncate.js";
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer, type GoSymbolRecord } from "../transformer.js";
+import { createConfig } from "../config.js";
+
+const DOC = [
+  "Frame is a wire frame.",
+  "It is sent as is.",
+  "# Layout",
+  "Frames start with a header.",
+  "# Flags",
+  "Flags follow the header.",
+  "# Errors",
+  "Errors close the stream.",
+].join("\n\n");
+
+describe("truncateDoc", () => {
+  it("should leave docs within the limit alone", () => {
+    expect(truncateDoc(DOC, { maxChars: DOC.length, maxSections: 0 })).toBeUndefined();
+  });
+
+  it("should keep the introduction and the first sections", () => {
+    expect(truncateDoc(DOC, { maxChars: DOC.length - 1, maxSections: 1 })).toBe(
+      "Frame is a wire frame.\n\nIt is sent as is.\n\n# Layout\n\nFrames start with a header.",
+    );
+  });
+
+  it("should drop trailing paragraphs until the text fits", () => {
+    expect(truncateDoc(DOC, { maxChars: 45, maxSections: 3 })).toBe(
+      "Frame is a wire frame.\n\nIt is sent as is.",
+    );
+  });
+
+  it("should always keep the synopsis paragraph", () => {
+    expect(truncateDoc(DOC, { maxChars: 5, maxSections: 3 })).toBe("Frame is a wire frame.");
+  });
+});
+
+describe("docLimitFor", () => {
+  it("should prefer the limit for the kind", () => {
+    expect(docLimitFor(defaultDocLimits, "class")).toBe(defaultDocLimits.kinds!.class);
+    expect(docLimitFor(defaultDocLimits, "function")).toBe(defaultDocLimits.default);
+  });
+});
+
+describe("GoTransformer doc truncation", () => {
+  let root: string;
+  let symbols: GoSymbolRecord[];
+
+  beforeAll(async () => {
+    root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-truncate-"));
+    const spec = Array.from({ length: 40 }, (_, i) => `// Section ${i} of the embedded spec.`);
+    await writeFile(
+      path.join(root, "frame.go"),
+      `package frame
+
+// Encode writes a frame.
+//
+${spec.join("\n//\n")}
+func Encode() {}
+
+// Frame is a wire frame.
+//
+${spec.join("\n//\n")}
+type Frame struct{}
+`,
+    );
+
+    const config = createConfig({
+      packageName: "frame",
+      packagePath: root,
+      repo: "langchain-ai/frame",
+      sha: "abc123",
+      docLimits: {
+        default: { maxChars: 200, maxSections: 2 },
+        kinds: { class: { maxChars: 10_000, maxSections: 2 } },
+      },
+    });
+    const result = await new GoExtractor(config).extract();
+    symbols = new GoTransformer(result, config).transform();
+  });
+
+  afterAll(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it("should truncate docs over the limit for their kind", () => {
+    const encode = symbols.find((s) => s.name === "Encode")!;
+    expect(encode.docs.truncated).toBe(true);
+    expect(encode.docs.description!.startsWith("Encode writes a frame.")).toBe(true);
+    expect(encode.docs.description).not.toContain("Section 39");
+  });
+
+  it("should link to the full doc comment", () => {
+    const encode = symbols.find((s) => s.name === "Encode")!;
+    expect(encode.docs.fullDocsUrl).toBe(
+      "https://github.com/langchain-ai/frame/blob/abc123/frame.go#L84",
+    );
+    expect(encode.docs.description).toContain(`[full doc comment](${encode.docs.fullDocsUrl})`);
+  });
+
+  it("should leave docs within the limit for their kind alone", () => {
+    const frame = symbols.find((s) => s.name === "Frame")!;
+    expect(frame.docs.truncated).toBeUndefined();
+    expect(frame.docs.description).toContain("Section 39");
+  });
+});
@@ -0,0 +1,170 @@
+/**
+ * Type expression parser tests
+ */
+
+import { describe, it, expect } from "vitest";
+
+import {
+  collectNamedTypes,
+  formatTypeExpr,
+  parseResultTypes,
+  parseTypeExpr,
+} from "../type-expr.js";
+
+describe("parseTypeExpr", () => {
+  it("should parse builtin and qualified names", () => {
+    expect(parseTypeExpr("string")).toEqual({ kind: "named", name: "string", builtin: true });
+    expect(parseTypeExpr("context.Context")).toEqual({
+      kind: "named",
+      name: "Context",
+      package: "context",
+    });
+  });
+
+  it("should keep type arguments of generic instantiations", () => {
+    expect(parseTypeExpr("map[string]Result[int]")).toEqual({
+      kind: "map",
+      key: { kind: "named", name: "string", builtin: true },
+      value: {
+        kind: "named",
+        name: "Result",
+        typeArgs: [{ kind: "named", name: "int", builtin: true }],
+      },
+    });
+  });
+
+  it("should parse nested instantiations with several arguments", () => {
+    const expr = parseTypeExpr("Page[string, List[io.Reader]]");
+    const names = collectNamedTypes(expr).map((n) => n.name);
+    expect(names).toEqual(["Page", "string", "List", "Reader"]);
+  });
+
+  it("should parse pointers, slices, arrays, and channels", () => {
+    expect(parseTypeExpr("*Client").kind).toBe("pointer");
+    expect(parseTypeExpr("[]byte").kind).toBe("slice");
+    expect(parseTypeExpr("[4]int")).toMatchObject({ kind: "array", length: "4" });
+    expect(parseTypeExpr("<-chan Event")).toMatchObject({ kind: "chan", dir: "recv" });
+    expect(parseTypeExpr("chan<- Event")).toMatchObject({ kind: "chan", dir: "send" });
+  });
+
+  it("should parse function types", () => {
+    const expr = parseTypeExpr("func(ctx context.Context, n int) (*Response, error)");
+    expect(expr.kind).toBe("func");
+    expect(collectNamedTypes(expr).map((n) => n.name)).toEqual([
+      "Context",
+      "int",
+      "Response",
+      "error",
+    ]);
+  });
+
+  it("should parse variadic parameters", () => {
+    expect(parseTypeExpr("...interface{}")).toMatchObject({
+      kind: "variadic",
+      elem: { kind: "interface" },
+    });
+  });
+});
+
+describe("anonymous type literals", () => {
+  it("should parse struct fields with tags and embeds", () => {
+    expect(parseTypeExpr('struct{ io.Reader; Name, Alias string `json:"name"` }')).toEqual({
+      kind: "struct",
+      fields: [
+        {
+          name: "Reader",
+          type: { kind: "named", name: "Reader", package: "io" },
+          embedded: true,
+        },
+        {
+          name: "Name",
+          type: { kind: "named", name: "string", builtin: true },
+          tag: 'json:"name"',
+        },
+        {
+          name: "Alias",
+          type: { kind: "named", name: "string", builtin: true },
+          tag: 'json:"name"',
+        },
+      ],
+    });
+  });
+
+  it("should parse interface methods and embeds", () => {
+    const expr = parseTypeExpr("interface{ fmt.Stringer; Read(p []byte) (int, error) }");
+    expect(expr).toMatchObject({
+      kind: "interface",
+      embeds: [{ kind: "named", name: "Stringer", package: "fmt" }],
+      methods: [{ name: "Read", params: [{ kind: "slice" }], results: [{}, {}] }],
+    });
+  });
+
+  it("should keep trailing comments as field docs", () => {
+    const expr = parseTypeExpr(
+      "struct {\n\tHost string // hostname\n\tPort int // port (default 80\n\tUser string\n}",
+    );
+    expect(expr).toEqual({
+      kind: "struct",
+      fields: [
+        { name: "Host", type: { kind: "named", name: "string", builtin: true }, doc: "hostname" },
+        {
+          name: "Port",
+          type: { kind: "named", name: "int", builtin: true },
+          doc: "port (default 80",
+        },
+        { name: "User", type: { kind: "named", name: "string", builtin: true } },
+      ],
+    });
+    expect(formatTypeExpr(expr)).toBe("struct{ Host string; Port int; User string }");
+  });
+
+  it("should ignore comment markers and brackets inside tags", () => {
+    const expr = parseTypeExpr(
+      'struct {\n\tURL string `default:"http://x/("` // base URL\n\tUser string\n}',
+    );
+    expect(expr).toMatchObject({
+      fields: [
+        { name: "URL", tag: 'default:"http://x/("', doc: "base URL" },
+        { name: "User" },
+      ],
+    });
+  });
+
+  it("should collect named types inside literals", () => {
+    const expr = parseTypeExpr("struct{ Client *Client; Hook interface{ Run(Event) } }");
+    expect(collectNamedTypes(expr).map((t) => t.name)).toEqual(["Client", "Event"]);
+  });
+});
+
+describe("formatTypeExpr", () => {
+  it("should render multi-line literals on one line", () => {
+    const expr = parseTypeExpr("struct {\n\t// Max is the limit.\n\tMax int\n\tMin int\n}");
+    expect(formatTypeExpr(expr)).toBe("struct{ Max int; Min int }");
+  });
+
+  it("should render composite types", () => {
+    const source = "map[string][]*Result[int]";
+    expect(formatTypeExpr(parseTypeExpr(source))).toBe(source);
+  });
+
+  it("should render empty literals compactly", () => {
+    expect(formatTypeExpr(parseTypeExpr("interface{}"))).toBe("interface{}");
+  });
+});
+
+describe("parseResultTypes", () => {
+  it("should split result tuples", () => {
+    const results = parseResultTypes("([]byte, error)");
+    expect(results).toHaveLength(2);
+    expect(results[0].kind).toBe("slice");
+  });
+
+  it("should drop names of named results", () => {
+    const results = parseResultTypes("(n int, err error)");
+    expect(results.map((r) => (r.kind === "named" ? r.name : r.kind))).toEqual(["int", "error"]);
+  });
+
+  it("should return nothing for empty results", () => {
+    expect(parseResultTypes("")).toEqual([]);
+  });
+});
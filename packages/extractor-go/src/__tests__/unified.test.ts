@@ -0,0 +1,87 @@
+/**
+ * Unified reference output tests
+ */
+
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect, beforeAll } from "vitest";
+
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { buildOutput } from "../output.js";
+import { unifyOutput, type UnifiedOutput } from "../unified.js";
+
+const __filename = url.fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+const fixturesPath = path.join(__dirname, "fixtures");
+
+describe("unifyOutput", () => {
+  let output: UnifiedOutput;
+  const symbol = (qualifiedName: string) =>
+    output.symbols.find((s) => s.qualifiedName === qualifiedName)!;
+
+  beforeAll(async () => {
+    const config = createConfig({
+      packageName: "test-package",
+      packagePath: fixturesPath,
+      sidecarDocs: "docs",
+    });
+    const result = await new GoExtractor(config).extract();
+    const symbols = new GoTransformer(result, config).transform();
+    output = unifyOutput(buildOutput(result, config, symbols));
+  });
+
+  it("should keep only the shared package header", () => {
+    expect(Object.keys(output.package).sort()).toEqual([
+      "displayName",
+      "ecosystem",
+      "language",
+      "packageId",
+      "publishedName",
+      "repo",
+      "version",
+    ]);
+  });
+
+  it("should leave out Go-specific fields", () => {
+    const get = symbol("Client.Get");
+
+    expect(get.kind).toBe("method");
+    expect(get.params).toEqual([
+      { name: "ctx", type: "context.Context", required: true },
+      { name: "path", type: "string", required: true },
+    ]);
+    expect(get.returns).toEqual({ type: "([]byte, error)" });
+    expect(get).not.toHaveProperty("context");
+    expect(get.docs).not.toHaveProperty("blocks");
+    expect(symbol("Client").members?.find((m) => m.name === "Get")).toEqual({
+      name: "Get",
+      refId: get.id,
+      kind: "method",
+      visibility: "public",
+    });
+  });
+
+  it("should name the successor of a deprecated symbol", () => {
+    expect(symbol("Retry").docs.deprecated).toEqual({
+      isDeprecated: true,
+      message: "Use RetryPolicy with Do instead.",
+      replacement: "RetryPolicy",
+    });
+  });
+
+  it("should resolve doc links and append sidecar docs to the description", () => {
+    expect(symbol("Buffer").docs.description).toContain(
+      "a [Closer](/Closer): call [Buffer.Write](/Buffer.Write) to append",
+    );
+    expect(symbol("Client").docs.description).toContain("\n\n## Connection pooling\n");
+  });
+
+  it("should keep type references for cross-linking", () => {
+    expect(symbol("NewClient").typeRefs).toEqual([
+      { name: "Client", qualifiedName: "Client", refId: "pkg_go_test_package:Client" },
+    ]);
+  });
+});
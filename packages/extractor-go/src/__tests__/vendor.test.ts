@@ -0,0 +1,189 @@
+/**
+ * Vendored dependency tests
+ */
+
+import { mkdir, mkdtemp, rm, writeFile } from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect, beforeAll, afterAll } from "vitest";
+
+import { parseModulesTxt, vendorImportPath } from "../vendor.js";
+import { GoExtractor } from "../extractor.js";
+import { GoTransformer, type GoSymbolRecord } from "../transformer.js";
+import { createConfig, validateConfig, type VendorPolicy } from "../config.js";
+import { buildOutput } from "../output.js";
+import { renderMdx } from "../mdx.js";
+
+const CLIENT_SOURCE = `package client
+
+// Client is an API client.
+type Client struct{}
+
+// Wrap wraps an error.
+func Wrap(err error) *Error { return nil }
+`;
+
+const ERRORS_SOURCE = `package errors
+
+// Error is an error with a stack.
+type Error struct{}
+
+// Cause returns the underlying error.
+func (e *Error) Cause() error { return nil }
+
+// Client is a vendored type with the same name as one in the module.
+type Client struct{}
+
+// New returns an Error.
+func New(message string) *Error { return nil }
+`;
+
+const MODULES_TXT = `# github.com/pkg/errors v0.9.1
+## explicit
+github.com/pkg/errors
+# golang.org/x/net v0.20.0 => ../net
+golang.org/x/net/http2
+`;
+
+describe("vendorImportPath", () => {
+  it("should return the import path below the vendor directory", () => {
+    expect(vendorImportPath("vendor/github.com/pkg/errors/errors.go")).toBe(
+      "github.com/pkg/errors",
+    );
+    expect(vendorImportPath("tools/vendor/golang.org/x/net/http2/frame.go")).toBe(
+      "golang.org/x/net/http2",
+    );
+  });
+
+  it("should ignore files outside vendor packages", () => {
+    expect(vendorImportPath("client.go")).toBeUndefined();
+    expect(vendorImportPath("vendor/modules.go")).toBeUndefined();
+    expect(vendorImportPath("vendoring/a/a.go")).toBeUndefined();
+  });
+});
+
+describe("parseModulesTxt", () => {
+  it("should read module paths and versions", () => {
+    expect(parseModulesTxt(MODULES_TXT)).toEqual([
+      { path: "github.com/pkg/errors", version: "v0.9.1" },
+      { path: "golang.org/x/net", version: "v0.20.0" },
+    ]);
+  });
+});
+
+describe("GoTransformer vendored packages", () => {
+  let root: string;
+
+  beforeAll(async () => {
+    root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-vendor-"));
+    await mkdir(path.join(root, "vendor", "github.com", "pkg", "errors"), { recursive: true });
+    await writeFile(path.join(root, "client.go"), CLIENT_SOURCE);
+    await writeFile(
+      path.join(root, "vendor", "github.com", "pkg", "errors", "errors.go"),
+      ERRORS_SOURCE,
+    );
+    await writeFile(path.join(root, "vendor", "modules.txt"), MODULES_TXT);
+  });
+
+  afterAll(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  async function transform(
+    vendoredPackages?: VendorPolicy,
+    excludePatterns?: string[],
+  ): Promise<GoSymbolRecord[]> {
+    const config = createConfig({
+      packageName: "client",
+      packagePath: root,
+      vendoredPackages,
+      ...(excludePatterns && { excludePatterns }),
+    });
+    const result = await new GoExtractor(config).extract();
+    return new GoTransformer(result, config).transform();
+  }
+
+  it("should skip vendored packages by default", async () => {
+    expect((await transform()).map((s) => s.qualifiedName)).toEqual(["Client", "Wrap"]);
+  });
+
+  it("should skip vendored packages even when the exclude patterns don't", async () => {
+    const symbols = await transform(undefined, ["**/*_test.go"]);
+    expect(symbols.map((s) => s.qualifiedName)).toEqual(["Client", "Wrap"]);
+  });
+
+  describe("in the dependencies namespace", () => {
+    let symbols: GoSymbolRecord[];
+
+    beforeAll(async () => {
+      symbols = await transform("dependencies");
+    });
+
+    it("should prefix vendored symbols with their import path", () => {
+      const error = symbols.find((s) => s.qualifiedName === "github.com/pkg/errors.Error")!;
+      expect(error.id).toBe("pkg_go_client:dependencies/github.com/pkg/errors/Error");
+      expect(error.urls.canonical).toBe("/dependencies/github.com/pkg/errors.Error");
+      expect(error.members!.map((m) => m.refId)).toEqual([
+        "pkg_go_client:dependencies/github.com/pkg/errors/Error_Cause",
+      ]);
+      expect(symbols.map((s) => s.id)).toContain(error.members![0].refId);
+    });
+
+    it("should put the MDX pages of vendored symbols under their import path", async () => {
+      const config = createConfig({
+        packageName: "client",
+        packagePath: root,
+        vendoredPackages: "dependencies",
+      });
+      const result = await new GoExtractor(config).extract();
+      const output = buildOutput(result, config, new GoTransformer(result, config).transform());
+      expect([...renderMdx(output).keys()]).toContain(
+        "dependencies/github.com/pkg/errors.Error.mdx",
+      );
+    });
+
+    it("should tag vendored symbols with their module and version", () => {
+      const error = symbols.find((s) => s.qualifiedName === "github.com/pkg/errors.Error")!;
+      expect(error.dependency).toEqual({
+        package: "github.com/pkg/errors",
+        module: "github.com/pkg/errors",
+        version: "v0.9.1",
+      });
+    });
+
+    it("should keep module symbols that share a name with a vendored one", () => {
+      const clients = symbols.filter((s) => s.name === "Client");
+      expect(clients.map((s) => s.id)).toEqual([
+        "pkg_go_client:Client",
+        "pkg_go_client:dependencies/github.com/pkg/errors/Client",
+      ]);
+      expect(clients[0].dependency).toBeUndefined();
+    });
+
+    it("should link vendored symbols to types in their own package", () => {
+      const newFn = symbols.find((s) => s.qualifiedName === "github.com/pkg/errors.New")!;
+      expect(newFn.typeRefs).toContainEqual(
+        expect.objectContaining({
+          name: "Error",
+          refId: "pkg_go_client:dependencies/github.com/pkg/errors/Error",
+        }),
+      );
+    });
+
+    it("should not link module symbols to vendored types by bare name", () => {
+      const wrap = symbols.find((s) => s.name === "Wrap")!;
+      const error = wrap.typeRefs!.find((r) => r.name === "Error");
+      expect(error?.refId).toBeUndefined();
+    });
+  });
+
+  it("should reject unknown policies", () => {
+    const config = createConfig({
+      packageName: "client",
+      packagePath: root,
+      vendoredPackages: "flatten" as VendorPolicy,
+    });
+    expect(() => validateConfig(config)).toThrow("Unknown vendor policy: flatten");
+  });
+});
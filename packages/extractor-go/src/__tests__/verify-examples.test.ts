@@ -0,0 +1,119 @@
+/**
+ * Example verification tests
+ */
+
+import { mkdtemp, rm, writeFile } from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect, beforeAll, afterAll, vi } from "vitest";
+
+import { GoExtractor, type ExtractionResult } from "../extractor.js";
+import { GoTransformer } from "../transformer.js";
+import { createConfig } from "../config.js";
+import { formatExampleProblems, verifyExamples } from "../verify-examples.js";
+
+const source = `// Package store is a key-value store client.
+package store
+
+// Store reads and writes entries.
+//
+// Open a store and read from it:
+//
+//	// verify
+//	s := store.Open("db")
+//	s.Get("key")
+//	store.Remove(s)
+//
+// Unverified code may call anything:
+//
+//	store.Gone()
+type Store struct{}
+
+// Open opens the store at path.
+func Open(path string) *Store { return &Store{} }
+
+// Get returns the value of key.
+func (s *Store) Get(key string) string { return "" }
+`;
+
+const examples = `package store_test
+
+import (
+	"fmt"
+
+	kv "github.com/example/store"
+)
+
+func ExampleOpen() {
+	s := kv.Open("db")
+	fmt.Println(s.Get("key"), "kv.Missing") // kv.Commented
+	get := kv.Store.Fetch
+	_ = get
+}
+
+func ExampleStore_Delete() {}
+`;
+
+describe("verifyExamples", () => {
+  let root: string;
+  let result: ExtractionResult;
+
+  beforeAll(async () => {
+    root = await mkdtemp(path.join(os.tmpdir(), "extractor-go-verify-"));
+    await writeFile(path.join(root, "go.mod"), "module github.com/example/store\n");
+    await writeFile(path.join(root, "store.go"), source);
+    await writeFile(path.join(root, "example_test.go"), examples);
+
+    const config = createConfig({ packageName: "store", packagePath: root });
+    result = await new GoExtractor(config).extract();
+  });
+
+  afterAll(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it("should report references to undeclared APIs through the import name", () => {
+    expect(verifyExamples(result)).toEqual([
+      { example: "ExampleOpen", reference: "Store.Fetch", sourceFile: "example_test.go", line: 12 },
+      {
+        example: "ExampleStore_Delete",
+        reference: "Store.Delete",
+        sourceFile: "example_test.go",
+        line: 16,
+      },
+      { example: "Store", reference: "Remove", sourceFile: "store.go", line: 16 },
+    ]);
+  });
+
+  it("should format one problem per line", () => {
+    expect(formatExampleProblems(verifyExamples(result)).split("\n")[0]).toBe(
+      "example_test.go:12: ExampleOpen refers to Store.Fetch, which the package doesn't declare",
+    );
+  });
+
+  it("should fail the transform in error mode and only warn in warn mode", () => {
+    const strict = createConfig({
+      packageName: "store",
+      packagePath: root,
+      verifyExamples: "error",
+    });
+    expect(() => new GoTransformer(result, strict).transform()).toThrow(
+      "3 example(s) refer to APIs the package doesn't declare",
+    );
+
+    const lenient = createConfig({
+      packageName: "store",
+      packagePath: root,
+      verifyExamples: "warn",
+    });
+    const warn = vi.spyOn(console, "warn");
+    expect(new GoTransformer(result, lenient).transform().length).toBeGreaterThan(0);
+    const warnings = warn.mock.calls.map((call) => String(call[0]));
+    warn.mockRestore();
+    expect(warnings).toHaveLength(3);
+    expect(warnings[2]).toBe(
+      "Warning: store.go:16: Store refers to Remove, which the package doesn't declare",
+    );
+  });
+});
@@ -0,0 +1,72 @@
+/**
+ * Watch mode tests
+ */
+
+import fs from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect } from "vitest";
+
+import { affectedPackages, isWatchedFile, splitChanges, watchSources } from "../watch.js";
+
+describe("watch mode", () => {
+  it("should only watch Go sources and go.mod", () => {
+    expect(isWatchedFile("client.go")).toBe(true);
+    expect(isWatchedFile("sub/go.mod")).toBe(true);
+    expect(isWatchedFile("README.md")).toBe(false);
+    expect(isWatchedFile("out/symbols.json")).toBe(false);
+  });
+
+  it("should map changed files to their packages", () => {
+    expect(affectedPackages(["sub/b.go", "a.go", "sub/a.go", "other/go.mod"])).toEqual([
+      ".",
+      "other",
+      "sub",
+    ]);
+  });
+
+  it("should only rewrite changed split files and remove stale ones", () => {
+    const previous = new Map([
+      ["a.json", "1"],
+      ["b.json", "2"],
+      ["manifest.json", "m1"],
+    ]);
+    const next = new Map([
+      ["a.json", "1"],
+      ["c.json", "3"],
+      ["manifest.json", "m2"],
+    ]);
+
+    expect(splitChanges(previous, next)).toEqual({
+      written: ["c.json", "manifest.json"],
+      removed: ["b.json"],
+    });
+    expect(splitChanges(new Map(), next).written).toEqual([...next.keys()]);
+  });
+
+  it("should report batches of changed sources", async () => {
+    const dir = await fs.mkdtemp(path.join(os.tmpdir(), "watch-"));
+    await fs.mkdir(path.join(dir, "sub"));
+    const batches: string[][] = [];
+    const changed = new Promise<void>((resolve) => {
+      const stop = watchSources(
+        dir,
+        (files) => {
+          batches.push(files);
+          stop();
+          resolve();
+        },
+        { debounceMs: 50 },
+      );
+    });
+
+    await new Promise((resolve) => setTimeout(resolve, 50));
+    await fs.writeFile(path.join(dir, "notes.txt"), "ignored");
+    await fs.writeFile(path.join(dir, "sub", "a.go"), "package sub\n");
+    await fs.writeFile(path.join(dir, "b.go"), "package main\n");
+    await changed;
+
+    expect(batches).toEqual([["b.go", "sub/a.go"]]);
+  });
+});
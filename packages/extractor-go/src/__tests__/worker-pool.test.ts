@@ -0,0 +1,211 @@
+/**
+ * Worker pool tests
+ */
+
+import { EventEmitter } from "node:events";
+import path from "node:path";
+import url from "node:url";
+
+import { describe, it, expect } from "vitest";
+
+import { createConfig } from "../config.js";
+import { GoExtractor } from "../extractor.js";
+import {
+  WorkerLimitError,
+  WorkerPool,
+  defaultWorkerCount,
+  forEachLimit,
+  parseWorkerCount,
+  workerMemoryLimit,
+  type PoolWorker,
+} from "../worker-pool.js";
+
+/**
+ * A worker on this thread that doubles numbers after `delay(n)` ms, and
+ * fails on negative ones.
+ */
+class FakeWorker extends EventEmitter {
+  terminated = false;
+  /** Whether the worker keeps the process alive */
+  refed = true;
+
+  constructor(private delay: (n: number) => number) {
+    super();
+  }
+
+  postMessage(n: number): void {
+    setTimeout(() => {
+      if (n === -1) {
+        this.emit("error", new Error("crashed"));
+      } else {
+        this.emit("message", n < 0 ? { error: `negative: ${n}` } : { result: n * 2 });
+      }
+    }, this.delay(n));
+  }
+
+  ref(): void {
+    this.refed = true;
+  }
+
+  unref(): void {
+    this.refed = false;
+  }
+
+  async terminate(): Promise<number> {
+    this.terminated = true;
+    return 0;
+  }
+}
+
+function pool(size: number, delay: (n: number) => number = () => 0) {
+  const workers: FakeWorker[] = [];
+  const spawn = () => {
+    const worker = new FakeWorker(delay);
+    workers.push(worker);
+    return worker as unknown as PoolWorker;
+  };
+  return { pool: new WorkerPool<number, number>(size, spawn), workers };
+}
+
+describe("WorkerPool", () => {
+  it("should run tasks on up to size workers and resolve each with its result", async () => {
+    // Later tasks finish first
+    const { pool: workerPool, workers } = pool(3, (n) => 30 - n * 5);
+    const results = await Promise.all([1, 2, 3, 4, 5].map((n) => workerPool.run(n)));
+
+    expect(results).toEqual([2, 4, 6, 8, 10]);
+    expect(workers).toHaveLength(3);
+    await workerPool.close();
+    expect(workers.every((worker) => worker.terminated)).toBe(true);
+  });
+
+  it("should keep the process alive only while a worker runs a task", async () => {
+    const { pool: workerPool, workers } = pool(1, () => 10);
+
+    const running = workerPool.run(1);
+    expect(workers[0].refed).toBe(true);
+    expect(await running).toBe(2);
+    expect(workers[0].refed).toBe(false);
+    await workerPool.close();
+  });
+
+  it("should fail tasks that fail and replace crashed workers", async () => {
+    const { pool: workerPool, workers } = pool(1);
+
+    await expect(workerPool.run(-2)).rejects.toThrow("negative: -2");
+    await expect(workerPool.run(-1)).rejects.toThrow("crashed");
+    expect(await workerPool.run(4)).toBe(8);
+    expect(workers).toHaveLength(2);
+    await workerPool.close();
+  });
+
+  it("should terminate workers whose task times out", async () => {
+    const { pool: workerPool, workers } = pool(1, (n) => n);
+
+    const failed = workerPool.run(1000, 10);
+    await expect(failed).rejects.toThrow("timed out after 10 ms");
+    await expect(failed).rejects.toMatchObject({ limit: "timeout" });
+    expect(workers[0].terminated).toBe(true);
+    expect(await workerPool.run(1, 100)).toBe(2);
+    expect(workers).toHaveLength(2);
+    await workerPool.close();
+  });
+
+  it("should share a time budget between tasks and fail those left without time", async () => {
+    const { pool: workerPool, workers } = pool(1, (n) => n);
+    let budget = 100;
+    const timeout = () => budget;
+
+    expect(await workerPool.run(1, timeout)).toBe(2);
+    budget = 0;
+    const failed = workerPool.run(1, timeout);
+    await expect(failed).rejects.toThrow("timed out before it started");
+    await expect(failed).rejects.toMatchObject({ limit: "timeout" });
+    expect(workers).toHaveLength(1);
+    expect(workers[0].terminated).toBe(false);
+    await workerPool.close();
+  });
+
+  it("should fail tasks of workers that run out of memory with a limit error", async () => {
+    const { pool: workerPool, workers } = pool(1, () => 50);
+
+    const failed = workerPool.run(1);
+    workers[0].emit(
+      "error",
+      Object.assign(new Error("Worker terminated due to reaching memory limit"), {
+        code: "ERR_WORKER_OUT_OF_MEMORY",
+      }),
+    );
+    const error = await failed.catch((e: unknown) => e);
+    expect(error).toBeInstanceOf(WorkerLimitError);
+    expect((error as WorkerLimitError).limit).toBe("memory");
+    await workerPool.close();
+  });
+});
+
+describe("forEachLimit", () => {
+  it("should keep at most limit calls pending", async () => {
+    let pending = 0;
+    let most = 0;
+    const done: number[] = [];
+    await forEachLimit([1, 2, 3, 4, 5, 6], 2, async (n) => {
+      pending++;
+      most = Math.max(most, pending);
+      await new Promise((resolve) => setTimeout(resolve, 5));
+      done.push(n);
+      pending--;
+    });
+
+    expect(most).toBe(2);
+    expect(done.sort()).toEqual([1, 2, 3, 4, 5, 6]);
+    await expect(
+      forEachLimit([1, 2], 2, async (n) => {
+        if (n === 2) throw new Error("disk full");
+      }),
+    ).rejects.toThrow("disk full");
+  });
+});
+
+describe("worker counts", () => {
+  it("should default to GOMAXPROCS and reject invalid counts", () => {
+    expect(defaultWorkerCount({ GOMAXPROCS: "3" })).toBe(3);
+    expect(defaultWorkerCount({ GOMAXPROCS: "zero" })).toBeGreaterThan(0);
+    expect(parseWorkerCount("8")).toBe(8);
+    expect(() => parseWorkerCount("0")).toThrow("Invalid --workers: 0");
+    expect(() => parseWorkerCount("1.5")).toThrow("Invalid --workers");
+  });
+
+  it("should split a memory budget between workers", () => {
+    expect(workerMemoryLimit(1024, 4)).toBe(256);
+    expect(workerMemoryLimit(100, 3)).toBe(33);
+    expect(() => workerMemoryLimit(100, 4)).toThrow("leaves 25 MB per worker");
+  });
+});
+
+describe("GoExtractor with workers", () => {
+  const fixturesPath = path.join(path.dirname(url.fileURLToPath(import.meta.url)), "fixtures");
+
+  it("should parse on worker threads and merge the same result as a single thread", async () => {
+    const config = createConfig({ packageName: "fixtures", packagePath: fixturesPath });
+    const single = await new GoExtractor(config).extract();
+    const parallel = await new GoExtractor({ ...config, workers: 2 }).extract();
+
+    expect(parallel.types.map((type) => type.name)).toEqual(single.types.map((type) => type.name));
+    expect(parallel).toEqual(single);
+  });
+
+  it("should skip and report the packages that overrun their time budget", async () => {
+    const config = createConfig({ packageName: "fixtures", packagePath: fixturesPath });
+    const result = await new GoExtractor({ ...config, packageTimeoutMs: 1 }).extract();
+
+    expect(result.types).toEqual([]);
+    expect(result.errors?.length).toBeGreaterThan(0);
+    for (const error of result.errors!) {
+      expect(error).toMatchObject({
+        kind: "timeout",
+        message: "took longer than 1 ms to parse; skipped the package",
+      });
+    }
+    expect(new Set(result.errors!.map((error) => error.package)).size).toBe(result.errors!.length);
+  });
+});
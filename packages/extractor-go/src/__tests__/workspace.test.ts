@@ -0,0 +1,177 @@
+/**
+ * Go workspace tests
+ */
+
+import { execFileSync } from "node:child_process";
+import fs from "node:fs/promises";
+import os from "node:os";
+import path from "node:path";
+
+import { describe, it, expect } from "vitest";
+
+import {
+  buildWorkspaceManifest,
+  loadWorkspace,
+  nestedModuleExcludes,
+  parseGoWork,
+  pseudoVersion,
+  type GoWorkspace,
+} from "../workspace.js";
+
+describe("parseGoWork", () => {
+  it("should read the go version and use directives", () => {
+    const work = parseGoWork(
+      [
+        "go 1.22",
+        "toolchain go1.22.3",
+        "",
+        "use ./cmd // the CLI",
+        "use (",
+        "\t.",
+        '\t"./sdk"',
+        ")",
+        "replace (",
+        "\texample.com/old => ./old",
+        ")",
+      ].join("\n"),
+    );
+
+    expect(work).toEqual({ go: "1.22", use: ["./cmd", ".", "./sdk"] });
+  });
+
+  it("should reject unterminated blocks", () => {
+    expect(() => parseGoWork("use (\n  ./a\n")).toThrow("unterminated use block");
+  });
+});
+
+describe("loadWorkspace", () => {
+  async function workspace(files: Record<string, string>): Promise<string> {
+    const dir = await fs.mkdtemp(path.join(os.tmpdir(), "workspace-"));
+    for (const [file, content] of Object.entries(files)) {
+      await fs.mkdir(path.dirname(path.join(dir, file)), { recursive: true });
+      await fs.writeFile(path.join(dir, file), content);
+    }
+    return dir;
+  }
+
+  it("should return undefined without a go.work", async () => {
+    expect(await loadWorkspace(await workspace({ "go.mod": "module example.com/a\n" }))).toBe(
+      undefined,
+    );
+  });
+
+  it("should resolve module paths and versions from tags", async () => {
+    const dir = await workspace({
+      "go.work": "go 1.22\n\nuse (\n\t.\n\t./sdk\n)\n",
+      "go.mod": "module example.com/app\n",
+      "sdk/go.mod": "module example.com/app/sdk\n",
+    });
+    const git = (...args: string[]) => execFileSync("git", args, { cwd: dir, stdio: "ignore" });
+    git("init", "-q");
+    git("add", "-A");
+    git("-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-qm", "init");
+    for (const tag of ["v1.2.0", "v1.10.0", "sdk/v0.3.0", "sdk/v0.3.1-rc.1", "sdk/latest"]) {
+      git("tag", tag);
+    }
+
+    const loaded = await loadWorkspace(dir);
+
+    expect(loaded!.go).toBe("1.22");
+    expect(loaded!.modules).toEqual([
+      { dir: ".", modulePath: "example.com/app", version: "v1.10.0" },
+      { dir: "sdk", modulePath: "example.com/app/sdk", version: "v0.3.1-rc.1" },
+    ]);
+  });
+
+  it("should give untagged commits pseudo-versions", async () => {
+    const dir = await workspace({
+      "go.work": "go 1.22\n\nuse (\n\t.\n\t./sdk\n)\n",
+      "go.mod": "module example.com/app\n",
+      "sdk/go.mod": "module example.com/app/sdk\n",
+    });
+    const git = (...args: string[]) =>
+      execFileSync("git", ["-c", "user.name=test", "-c", "user.email=test@example.com", ...args], {
+        cwd: dir,
+        encoding: "utf-8",
+        env: { ...process.env, GIT_COMMITTER_DATE: "2026-10-16T12:00:00Z" },
+      }).trim();
+    git("init", "-q");
+    git("add", "-A");
+    git("commit", "-qm", "init");
+    git("tag", "v1.2.0");
+    git("commit", "-q", "--allow-empty", "-m", "next");
+    const revision = git("rev-parse", "HEAD").slice(0, 12);
+
+    const loaded = await loadWorkspace(dir);
+
+    expect(loaded!.modules.map((m) => m.version)).toEqual([
+      `v1.2.1-0.20261016120000-${revision}`,
+      `v0.0.0-20261016120000-${revision}`,
+    ]);
+  });
+
+  it("should fail on members without a go.mod", async () => {
+    const dir = await workspace({ "go.work": "use ./missing\n" });
+
+    await expect(loadWorkspace(dir)).rejects.toThrow("./missing has no go.mod");
+  });
+});
+
+describe("pseudoVersion", () => {
+  const time = new Date("2026-10-16T12:00:00Z");
+  const revision = "0123456789abcdef0123";
+
+  it("should follow the latest tag", () => {
+    expect(pseudoVersion(undefined, time, revision)).toBe("v0.0.0-20261016120000-0123456789ab");
+    expect(pseudoVersion("v1.2.3", time, revision)).toBe("v1.2.4-0.20261016120000-0123456789ab");
+    expect(pseudoVersion("v1.3.0-rc.1", time, revision)).toBe(
+      "v1.3.0-rc.1.0.20261016120000-0123456789ab",
+    );
+  });
+});
+
+describe("workspace manifests", () => {
+  const workspace: GoWorkspace = {
+    root: "/repo",
+    go: "1.22",
+    modules: [
+      { dir: ".", modulePath: "example.com/app", version: "v1.0.0" },
+      { dir: "sdk", modulePath: "example.com/app/sdk", version: "0.0.0" },
+      { dir: "sdk/contrib", modulePath: "example.com/app/sdk/contrib", version: "0.0.0" },
+      { dir: "tools", modulePath: "example.com/tools", version: "0.0.0" },
+    ],
+  };
+
+  it("should exclude the files of nested members", () => {
+    const [app, sdk, contrib] = workspace.modules;
+
+    expect(nestedModuleExcludes(app, workspace)).toEqual([
+      "sdk/**",
+      "sdk/contrib/**",
+      "tools/**",
+    ]);
+    expect(nestedModuleExcludes(sdk, workspace)).toEqual(["contrib/**"]);
+    expect(nestedModuleExcludes(contrib, workspace)).toEqual([]);
+  });
+
+  it("should list module outputs with checksums", () => {
+    const [app] = workspace.modules;
+    const manifest = buildWorkspaceManifest("app", workspace, [
+      { module: app, symbols: 2, content: "{}" },
+    ]);
+
+    expect(manifest).toEqual({
+      workspace: { name: "app", go: "1.22" },
+      modules: [
+        {
+          modulePath: "example.com/app",
+          version: "v1.0.0",
+          dir: ".",
+          file: "example.com/app.json",
+          symbols: 2,
+          sha256: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+        },
+      ],
+    });
+  });
+});
@@ -0,0 +1,73 @@
+/**
+ * YAML subset tests
+ */
+
+import { describe, it, expect } from "vitest";
+
+import { parseYaml, YamlError } from "../yaml.js";
+
+describe("parseYaml", () => {
+  it("should parse nested block mappings and sequences", () => {
+    const text = [
+      "package: langsmith # the published name",
+      "output:",
+      "  path: ./out/symbols.json",
+      "  toc: ./out/toc.json",
+      "exclude:",
+      '  - "**/*.pb.go"',
+      "  - testdata/**",
+      "sources:",
+      "- name: core",
+      "  depth: 2",
+      "-",
+      "  name: extra",
+    ].join("\n");
+
+    expect(parseYaml(text)).toEqual({
+      package: "langsmith",
+      output: { path: "./out/symbols.json", toc: "./out/toc.json" },
+      exclude: ["**/*.pb.go", "testdata/**"],
+      sources: [{ name: "core", depth: 2 }, { name: "extra" }],
+    });
+  });
+
+  it("should parse flow collections and typed scalars", () => {
+    const text = [
+      "list: [a, 'b, c', \"d\"]",
+      "map: {Trace*: tracing, 'Mock*': internal}",
+      "flags: [true, false, null, ~, 1.5, -2, 0.1.2]",
+      "empty:",
+      "quoted: 'it''s # not a comment'",
+      'escaped: "tab\\there"',
+    ].join("\n");
+
+    expect(parseYaml(text)).toEqual({
+      list: ["a", "b, c", "d"],
+      map: { "Trace*": "tracing", "Mock*": "internal" },
+      flags: [true, false, null, null, 1.5, -2, "0.1.2"],
+      empty: null,
+      quoted: "it's # not a comment",
+      escaped: "tab\there",
+    });
+  });
+
+  it("should parse an empty document as null", () => {
+    expect(parseYaml("# nothing here\n\n")).toBeNull();
+  });
+
+  it("should report errors with their line", () => {
+    const cases: [string, string][] = [
+      ["a: 1\na: 2", 'line 2: duplicate key "a"'],
+      ["a: 1\n\tb: 2", "line 2: tabs can't be used for indentation"],
+      ["a:\n  b: 1\n    c: 2", "line 3: unexpected indentation"],
+      ["a: 1\n---\nb: 2", "line 2: multiple documents aren't supported"],
+      ["a: &anchor 1", "line 1: unsupported YAML syntax: &anchor 1 (quote the value)"],
+      ["a: [1, [2]]", "line 1: nested flow collections aren't supported"],
+      ["just text", 'line 1: expected a "key: value" entry'],
+    ];
+    for (const [text, message] of cases) {
+      expect(() => parseYaml(text)).toThrow(message);
+    }
+    expect(() => parseYaml("a: 'open")).toThrow(YamlError);
+  });
+});
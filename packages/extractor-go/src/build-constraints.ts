@@ -0,0 +1,221 @@
+/**
+ * Build Constraints
+ *
+ * Selects the files `go build` would compile for a target: a GOOS, a
+ * GOARCH, and a set of extra build tags. A file is selected when its
+ * `//go:build` expression holds and its name has no `_GOOS`, `_GOARCH`, or
+ * `_GOOS_GOARCH` suffix for another target, with go/build's rules: `unix`
+ * holds on Unix systems, `android` files also build for `linux`, `ios` for
+ * `darwin`, and `illumos` for `solaris`, and every `go1.N` release tag
+ * holds, as with the latest toolchain. Other tags, like `cgo`, hold only
+ * when given.
+ */
+
+import { basename } from "path";
+
+/**
+ * The target files are selected for.
+ */
+export interface BuildTarget {
+  goos: string;
+  goarch: string;
+  /** Extra build tags, as passed to `go build -tags` */
+  tags: string[];
+}
+
+/**
+ * GOOS values go/build knows of.
+ */
+export const knownGoos = [
+  "aix",
+  "android",
+  "darwin",
+  "dragonfly",
+  "freebsd",
+  "hurd",
+  "illumos",
+  "ios",
+  "js",
+  "linux",
+  "nacl",
+  "netbsd",
+  "openbsd",
+  "plan9",
+  "solaris",
+  "wasip1",
+  "windows",
+  "zos",
+];
+
+/**
+ * GOARCH values go/build knows of.
+ */
+export const knownGoarch = [
+  "386",
+  "amd64",
+  "amd64p32",
+  "arm",
+  "armbe",
+  "arm64",
+  "arm64be",
+  "loong64",
+  "mips",
+  "mipsle",
+  "mips64",
+  "mips64le",
+  "mips64p32",
+  "mips64p32le",
+  "ppc",
+  "ppc64",
+  "ppc64le",
+  "riscv",
+  "riscv64",
+  "s390",
+  "s390x",
+  "sparc",
+  "sparc64",
+  "wasm",
+];
+
+/**
+ * GOOS values the `unix` tag holds for.
+ */
+const unixGoos = new Set([
+  "aix",
+  "android",
+  "darwin",
+  "dragonfly",
+  "freebsd",
+  "hurd",
+  "illumos",
+  "ios",
+  "linux",
+  "netbsd",
+  "openbsd",
+  "solaris",
+]);
+
+/**
+ * GOOS values that also build the files of another.
+ */
+const goosImplies: Record<string, string> = {
+  android: "linux",
+  illumos: "solaris",
+  ios: "darwin",
+};
+
+/**
+ * GOOS values of Node.js platforms that differ.
+ */
+const GOOS: Record<string, string> = { win32: "windows", sunos: "solaris" };
+
+/**
+ * GOARCH values of Node.js architectures that differ.
+ */
+const GOARCH: Record<string, string> = { x64: "amd64", ia32: "386", ppc64: "ppc64le" };
+
+/**
+ * The GOOS of the host: `GOOS` if set, as `go` tooling does, or the
+ * platform's.
+ */
+export function hostGoos(env: NodeJS.ProcessEnv = process.env): string {
+  return env.GOOS || GOOS[process.platform] || process.platform;
+}
+
+/**
+ * The GOARCH of the host: `GOARCH` if set, or the architecture's.
+ */
+export function hostGoarch(env: NodeJS.ProcessEnv = process.env): string {
+  return env.GOARCH || GOARCH[process.arch] || process.arch;
+}
+
+/**
+ * The target of an extraction selecting files by build constraints, with
+ * the host's GOOS and GOARCH unless given, or undefined if it selects none
+ * of these, and extracts every file.
+ */
+export function buildTarget(
+  selection: { buildTags?: string[]; goos?: string; goarch?: string },
+  env: NodeJS.ProcessEnv = process.env,
+): BuildTarget | undefined {
+  const { buildTags, goos, goarch } = selection;
+  if (!buildTags && !goos && !goarch) return undefined;
+  return { goos: goos || hostGoos(env), goarch: goarch || hostGoarch(env), tags: buildTags ?? [] };
+}
+
+/**
+ * Whether a build tag holds for a target.
+ */
+export function matchBuildTag(tag: string, target: BuildTarget): boolean {
+  if (tag === target.goos || tag === target.goarch || tag === "gc") return true;
+  if (tag === goosImplies[target.goos]) return true;
+  if (tag === "unix" && unixGoos.has(target.goos)) return true;
+  return /^go1\.\d+$/.test(tag) || target.tags.includes(tag);
+}
+
+/**
+ * Whether a `//go:build` expression holds for a target. Throws on a
+ * malformed expression.
+ */
+export function matchBuildConstraint(expression: string, target: BuildTarget): boolean {
+  const tokens = expression.match(/&&|\|\||[!()]|[\w.]+|\S/g) ?? [];
+  let position = 0;
+  const fail = (): never => {
+    throw new Error(`Invalid build constraint: ${expression}`);
+  };
+  // Every operand is parsed before it's combined, so a malformed one fails
+  // even where the operator would short-circuit
+  const or = (): boolean => {
+    let value = and();
+    while (tokens[position] === "||") {
+      position++;
+      value = and() || value;
+    }
+    return value;
+  };
+  const and = (): boolean => {
+    let value = not();
+    while (tokens[position] === "&&") {
+      position++;
+      value = not() && value;
+    }
+    return value;
+  };
+  const not = (): boolean => {
+    const token = tokens[position++];
+    if (token === "!") return !not();
+    if (token === "(") {
+      const value = or();
+      if (tokens[position++] !== ")") fail();
+      return value;
+    }
+    if (!token || !/^[\w.]+$/.test(token)) fail();
+    return matchBuildTag(token, target);
+  };
+  const value = or();
+  if (position !== tokens.length) fail();
+  return value;
+}
+
+/**
+ * Whether a file's name suits a target: it has no `_GOOS`, `_GOARCH`, or
+ * `_GOOS_GOARCH` suffix, before any `_test`, for another one.
+ */
+export function matchFileName(file: string, target: BuildTarget): boolean {
+  const name = basename(file).split(".")[0];
+  const underscore = name.indexOf("_");
+  if (underscore < 0) return true;
+  // Everything before the first underscore is ignored, so linux.go builds
+  // everywhere
+  const parts = name.slice(underscore).split("_");
+  if (parts[parts.length - 1] === "test") parts.pop();
+  const [os, arch] = parts.slice(-2);
+  const last = parts[parts.length - 1];
+  if (parts.length >= 2 && knownGoos.includes(os) && knownGoarch.includes(arch)) {
+    return matchBuildTag(os, target) && matchBuildTag(arch, target);
+  }
+  if (knownGoos.includes(last) || knownGoarch.includes(last)) {
+    return matchBuildTag(last, target);
+  }
+  return true;
+}
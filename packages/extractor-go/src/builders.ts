@@ -0,0 +1,99 @@
+/**
+ * Builder APIs
+ *
+ * Detects fluent/builder methods, which return their receiver type so calls
+ * can be chained, and infers the order they are typically chained in from the
+ * code samples in doc comments.
+ */
+
+import type { GoMethod } from "./extractor.js";
+
+/**
+ * Chaining information of a builder type.
+ */
+export interface GoBuilderInfo {
+  /** Methods returning the receiver type, in declaration order */
+  chainable: string[];
+  /** Typical chain order of the chainable methods seen in examples */
+  order?: string[];
+}
+
+/**
+ * Whether a method returns its own receiver type, e.g. `func (b *Builder)
+ * Limit(n int) *Builder`, so calls to it can be chained.
+ */
+export function isChainable(method: GoMethod, typeName: string): boolean {
+  const result = method.returns
+    .replace(/^\((.*)\)$/s, "$1")
+    .trim()
+    .replace(/^\*/, "")
+    .replace(/\[.*\]$/s, "");
+  return result === typeName;
+}
+
+/**
+ * Find method call chains such as `b.Select("a").Where(x).Limit(10)` in code,
+ * as the list of called method names.
+ */
+export function extractCallChains(code: string): string[][] {
+  const chains: string[][] = [];
+  const callPattern = /\.\s*(\w+)\s*\(/y;
+  let consumed = 0;
+
+  for (const match of code.matchAll(/\.\s*\w+\s*\(/g)) {
+    if (match.index < consumed) continue;
+
+    const chain: string[] = [];
+    let pos = match.index;
+    for (;;) {
+      callPattern.lastIndex = pos;
+      const call = callPattern.exec(code);
+      if (!call) break;
+      chain.push(call[1]);
+      pos = skipWhitespace(code, closingParen(code, callPattern.lastIndex - 1) + 1);
+    }
+    consumed = pos;
+    if (chain.length > 1) chains.push(chain);
+  }
+  return chains;
+}
+
+/**
+ * Order chainable methods by their average relative position in the example
+ * chains that call at least two of them. Returns undefined when no example
+ * chains them.
+ */
+export function inferChainOrder(chains: string[][], methods: string[]): string[] | undefined {
+  const known = new Set(methods);
+  const positions = new Map<string, number[]>();
+
+  for (const chain of chains) {
+    const calls = chain.filter((name) => known.has(name));
+    if (calls.length < 2) continue;
+    calls.forEach((name, i) => {
+      positions.set(name, [...(positions.get(name) ?? []), i / (calls.length - 1)]);
+    });
+  }
+  if (positions.size === 0) return undefined;
+
+  const average = (name: string) => {
+    const seen = positions.get(name)!;
+    return seen.reduce((sum, p) => sum + p, 0) / seen.length;
+  };
+  // Map iteration order breaks ties by first appearance
+  return Array.from(positions.keys()).sort((a, b) => average(a) - average(b));
+}
+
+function closingParen(code: string, openIndex: number): number {
+  let depth = 0;
+  for (let i = openIndex; i < code.length; i++) {
+    if (code[i] === "(") depth++;
+    else if (code[i] === ")" && --depth === 0) return i;
+  }
+  return code.length;
+}
+
+function skipWhitespace(code: string, index: number): number {
+  while (index < code.length && /\s/.test(code[index])) index++;
+  return index;
+}
@@ -0,0 +1,52 @@
+/**
+ * Canonical Ordering
+ *
+ * Orders the output so repeated runs on unchanged source write
+ * byte-identical files for diffing and caching, whatever order the file
+ * system lists the source files in:
+ *
+ * - source files by path, so notes, examples, and other per-file lists
+ *   follow it
+ * - symbols by package directory, then qualified name, so a type's methods
+ *   follow the type
+ * - a type's methods by name, then its fields in declaration order (the
+ *   struct's layout)
+ * - categories and coverage packages by name
+ *
+ * Strings compare by UTF-16 code unit, independent of the locale. Object
+ * keys are written in their declared order, and maps keyed by data in source
+ * order.
+ *
+ * The provenance header is the exception: its timestamp is the time of the
+ * run unless SOURCE_DATE_EPOCH is set, and `vcs.modified` follows the state
+ * of the working tree, which an output written inside it changes.
+ */
+
+import { posix } from "path";
+import type { GoSymbolRecord } from "./transformer.js";
+
+/**
+ * Compare strings by code unit, independent of the locale.
+ */
+export function compareCanonical(a: string, b: string): number {
+  return a < b ? -1 : a > b ? 1 : 0;
+}
+
+/**
+ * Sort symbols and their members in canonical order, in place.
+ */
+export function sortSymbols<T extends GoSymbolRecord>(symbols: T[]): T[] {
+  for (const symbol of symbols) {
+    if (!symbol.members) continue;
+    const methods = symbol.members.filter((member) => member.kind === "method");
+    const others = symbol.members.filter((member) => member.kind !== "method");
+    methods.sort((a, b) => compareCanonical(a.name, b.name));
+    symbol.members = [...methods, ...others];
+  }
+  return symbols.sort(
+    (a, b) =>
+      compareCanonical(posix.dirname(a.source.path), posix.dirname(b.source.path)) ||
+      compareCanonical(a.qualifiedName, b.qualifiedName) ||
+      compareCanonical(a.id, b.id),
+  );
+}
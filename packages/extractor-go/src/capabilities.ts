@@ -0,0 +1,193 @@
+/**
+ * Capabilities Manifest
+ *
+ * Records which extraction features were enabled for a run and which of them
+ * actually produced data, so consumers can tell a field that is absent because
+ * the feature was off from one that is absent because nothing matched.
+ */
+
+import type { GoExtractorConfig } from "./config.js";
+import { defaultFeedbackUrlTemplate } from "./config.js";
+import type { GoSymbolRecord } from "./transformer.js";
+
+/**
+ * An extraction feature.
+ *
+ * - `generics`: type parameters of types, functions, and aliases
+ * - `typeExpressions`: structured, linkable types of parameters, results, and fields
+ * - `implementations`: implementations of interfaces and `relations.implements`
+ * - `constantValues`: folded values of constant expressions
+ * - `fieldDefaults`: struct field values assigned by constructors
+ * - `contextVariants`: links between Foo/FooContext function pairs
+ * - `ownership`: CODEOWNERS owners of symbols
+ * - `feedbackLinks`: per-symbol "report a doc issue" URLs
+ * - `builderChainOrder`: typical call order of builder methods
+ * - `lifecycle`: lifecycle grouping of type APIs
+ * - `iterators`: range-over-func iterator usage
+ * - `examples`: Example functions from `_test.go` files
+ * - `testHelpers`: symbols exported from `_test.go` files
+ * - `testFunctions`: benchmarks and fuzz targets linked to the symbols they exercise
+ * - `dependencies`: vendored packages under the dependencies namespace
+ * - `deprecations`: deprecation notices of symbols and fields
+ * - `docTags`: annotation tags such as `@since` split off doc comments
+ * - `categories`: symbols grouped by `@category` tags and `Category:` lines
+ * - `relatedSymbols`: symbols linked from "See also" sentences
+ * - `callouts`: security and concurrency notes marked as callouts
+ * - `sidecarDocs`: supplementary Markdown merged from the sidecar docs directory
+ * - `docStructure`: headings, lists, code blocks, and links parsed from doc comments
+ * - `docTruncation`: descriptions cut down to the doc limits
+ * - `localizedLabels`: translated structural labels
+ */
+export type Capability =
+  | "generics"
+  | "typeExpressions"
+  | "implementations"
+  | "constantValues"
+  | "fieldDefaults"
+  | "contextVariants"
+  | "ownership"
+  | "feedbackLinks"
+  | "builderChainOrder"
+  | "lifecycle"
+  | "iterators"
+  | "examples"
+  | "testHelpers"
+  | "testFunctions"
+  | "dependencies"
+  | "deprecations"
+  | "docTags"
+  | "categories"
+  | "relatedSymbols"
+  | "callouts"
+  | "sidecarDocs"
+  | "docStructure"
+  | "docTruncation"
+  | "localizedLabels";
+
+/**
+ * Whether a feature ran and whether it produced data.
+ */
+export interface CapabilityStatus {
+  /** The feature was turned on for the run */
+  enabled: boolean;
+  /** At least one symbol (or the output) carries data from the feature */
+  applied: boolean;
+}
+
+/**
+ * Status of every extraction feature.
+ */
+export type Capabilities = Record<Capability, CapabilityStatus>;
+
+interface CapabilityDetector {
+  enabled: (config: GoExtractorConfig) => boolean;
+  applied: (symbol: GoSymbolRecord, config: GoExtractorConfig) => boolean;
+}
+
+const always = () => true;
+
+const detectors: Record<Capability, CapabilityDetector> = {
+  generics: { enabled: always, applied: (s) => Boolean(s.typeParams?.length) },
+  typeExpressions: {
+    enabled: always,
+    applied: (s) =>
+      Boolean(s.typeExpr || s.params?.length || s.returns || s.members?.some((m) => m.typeExpr)),
+  },
+  implementations: {
+    enabled: always,
+    applied: (s) => Boolean(s.implementations?.length || s.relations?.implements?.length),
+  },
+  constantValues: { enabled: always, applied: (s) => Boolean(s.value?.evaluated) },
+  fieldDefaults: {
+    enabled: always,
+    applied: (s) => Boolean(s.members?.some((m) => m.defaults?.length)),
+  },
+  contextVariants: {
+    enabled: (config) => config.pairContextVariants !== false,
+    applied: (s) => Boolean(s.context?.contextVariant || s.context?.contextFreeVariant),
+  },
+  ownership: { enabled: always, applied: (s) => Boolean(s.owners?.length) },
+  feedbackLinks: {
+    enabled: (config) =>
+      Boolean(config.repo) ||
+      !(config.feedbackUrlTemplate ?? defaultFeedbackUrlTemplate).includes("{repo}"),
+    applied: (s) => Boolean(s.urls.feedback),
+  },
+  builderChainOrder: {
+    enabled: (config) => config.inferChainOrder === true,
+    applied: (s) => Boolean(s.builder?.order?.length),
+  },
+  lifecycle: { enabled: always, applied: (s) => Boolean(s.lifecycle) },
+  iterators: { enabled: always, applied: (s) => Boolean(s.iterator) },
+  examples: {
+    enabled: (config) => config.examples !== false,
+    applied: (s) => Boolean(s.docs.examples?.length),
+  },
+  testHelpers: {
+    enabled: (config) => config.testHelpers === true,
+    applied: (s) => Boolean(s.testHelper),
+  },
+  testFunctions: {
+    enabled: (config) => config.testFunctions === true,
+    applied: (s) => Boolean(s.tests?.length),
+  },
+  dependencies: {
+    enabled: (config) => config.vendoredPackages === "dependencies",
+    applied: (s) => Boolean(s.dependency),
+  },
+  deprecations: {
+    enabled: always,
+    applied: (s) => Boolean(s.docs.deprecated || s.members?.some((m) => m.deprecated)),
+  },
+  docTags: {
+    enabled: (config) => config.docTags?.length !== 0,
+    applied: (s) => Boolean(s.docs.annotations || s.members?.some((m) => m.annotations)),
+  },
+  categories: { enabled: always, applied: (s) => Boolean(s.category) },
+  relatedSymbols: { enabled: always, applied: (s) => Boolean(s.related?.length) },
+  callouts: {
+    enabled: always,
+    applied: (s) => Boolean(s.docs.blocks?.some((block) => block.kind === "callout")),
+  },
+  sidecarDocs: {
+    enabled: (config) => Boolean(config.sidecarDocs),
+    applied: (s) => Boolean(s.docs.sidecar),
+  },
+  docStructure: {
+    enabled: always,
+    applied: (s) =>
+      Boolean(
+        s.docs.blocks?.some(
+          (block) =>
+            block.kind !== "paragraph" || block.text.some((span) => span.kind !== "plain"),
+        ),
+      ),
+  },
+  docTruncation: { enabled: always, applied: (s) => Boolean(s.docs.truncated) },
+  localizedLabels: {
+    enabled: (config) => Boolean(config.localeBundle),
+    applied: (_, config) => Boolean(config.localeBundle),
+  },
+};
+
+/**
+ * All capabilities, in manifest order.
+ */
+export const capabilityNames = Object.keys(detectors) as Capability[];
+
+/**
+ * Build the capabilities manifest of a run from its configuration and symbols.
+ */
+export function buildCapabilities(
+  config: GoExtractorConfig,
+  symbols: GoSymbolRecord[],
+): Capabilities {
+  return Object.fromEntries(
+    capabilityNames.map((name) => {
+      const detector = detectors[name];
+      const enabled = detector.enabled(config);
+      const applied = enabled && symbols.some((symbol) => detector.applied(symbol, config));
+      return [name, { enabled, applied }];
+    }),
+  ) as Capabilities;
+}
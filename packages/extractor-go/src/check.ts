@@ -0,0 +1,129 @@
+/**
+ * Output Check
+ *
+ * Compares a fresh extraction against a committed baseline so CI can
+ * fail when the published reference would change.
+ */
+
+import type { ProfiledOutput } from "./profile.js";
+
+/**
+ * A symbol present in both outputs whose record differs.
+ */
+export interface SymbolChange {
+  id: string;
+  /** Top-level record fields that differ */
+  fields: string[];
+  /** Previous and current signature, when the signature changed */
+  signature?: { before: string; after: string };
+}
+
+/**
+ * Differences between a baseline output and a fresh extraction.
+ */
+export interface OutputDiff {
+  /** Package header fields that differ */
+  packageFields: string[];
+  added: string[];
+  removed: string[];
+  changed: SymbolChange[];
+}
+
+/**
+ * Compare two extractor outputs symbol by symbol.
+ */
+export function diffOutputs(baseline: ProfiledOutput, current: ProfiledOutput): OutputDiff {
+  const packageFields = diffFields(
+    baseline.package as unknown as Record<string, unknown>,
+    current.package as unknown as Record<string, unknown>,
+  );
+
+  const before = new Map<string, { signature?: string }>(
+    baseline.symbols.map((s) => [s.id, s]),
+  );
+  const after = new Map<string, { signature?: string }>(current.symbols.map((s) => [s.id, s]));
+
+  const added = [...after.keys()].filter((id) => !before.has(id)).sort();
+  const removed = [...before.keys()].filter((id) => !after.has(id)).sort();
+
+  const changed: SymbolChange[] = [];
+  for (const [id, symbol] of after) {
+    const previous = before.get(id);
+    if (!previous) continue;
+
+    const fields = diffFields(
+      previous as unknown as Record<string, unknown>,
+      symbol as unknown as Record<string, unknown>,
+    );
+    if (fields.length === 0) continue;
+
+    const change: SymbolChange = { id, fields };
+    if (fields.includes("signature")) {
+      change.signature = { before: previous.signature ?? "", after: symbol.signature ?? "" };
+    }
+    changed.push(change);
+  }
+  changed.sort((a, b) => a.id.localeCompare(b.id));
+
+  return { packageFields, added, removed, changed };
+}
+
+/**
+ * Whether a diff contains any change at all.
+ */
+export function hasChanges(diff: OutputDiff): boolean {
+  return (
+    diff.packageFields.length > 0 ||
+    diff.added.length > 0 ||
+    diff.removed.length > 0 ||
+    diff.changed.length > 0
+  );
+}
+
+/**
+ * Render a diff as a human-readable report.
+ */
+export function formatDiff(diff: OutputDiff): string {
+  const lines: string[] = [];
+
+  lines.push(
+    `Generated reference is out of date ` +
+      `(${diff.added.length} added, ${diff.removed.length} removed, ${diff.changed.length} changed)`,
+  );
+
+  if (diff.packageFields.length > 0) {
+    lines.push(`  ~ package (${diff.packageFields.join(", ")})`);
+  }
+  for (const id of diff.added) {
+    lines.push(`  + ${id}`);
+  }
+  for (const id of diff.removed) {
+    lines.push(`  - ${id}`);
+  }
+  for (const change of diff.changed) {
+    lines.push(`  ~ ${change.id} (${change.fields.join(", ")})`);
+    if (change.signature) {
+      lines.push(`      - ${change.signature.before}`);
+      lines.push(`      + ${change.signature.after}`);
+    }
+  }
+
+  return lines.join("\n");
+}
+
+/**
+ * List the keys whose serialized values differ between two records.
+ */
+function diffFields(before: Record<string, unknown>, after: Record<string, unknown>): string[] {
+  const keys = new Set([...Object.keys(before), ...Object.keys(after)]);
+  const fields: string[] = [];
+
+  for (const key of keys) {
+    if (JSON.stringify(before[key]) !== JSON.stringify(after[key])) {
+      fields.push(key);
+    }
+  }
+
+  return fields.sort();
+}
+
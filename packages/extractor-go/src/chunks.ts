@@ -0,0 +1,221 @@
+/**
+ * Documentation Chunks
+ *
+ * Splits an extraction into small, self-contained chunks (signatures, docs,
+ * examples) and selects them in priority order within a token budget, for
+ * agents assembling context for an LLM. For retrieval pipelines, chunks are
+ * also cut to embedding size and given stable IDs.
+ */
+
+import { createHash } from "crypto";
+import type { SymbolRecord } from "@langchain/ir-schema";
+
+/**
+ * What a chunk holds.
+ *
+ * - `signature`: the Go declaration
+ * - `doc`: the doc comment prose, without its code samples
+ * - `example`: one code sample
+ */
+export type ChunkKind = "signature" | "doc" | "example";
+
+/**
+ * All chunk kinds, in the default priority order.
+ */
+export const chunkKinds: ChunkKind[] = ["signature", "doc", "example"];
+
+/**
+ * A piece of documentation of one symbol.
+ */
+export interface DocChunk {
+  symbolId: string;
+  qualifiedName: string;
+  kind: ChunkKind;
+  text: string;
+  /** Estimated token count of the text */
+  tokens: number;
+}
+
+/**
+ * Options for selecting chunks.
+ */
+export interface ChunkOptions {
+  /** Symbol ID or qualified name; its methods are included (default: whole package) */
+  symbol?: string;
+  /** Maximum total tokens of the selected chunks */
+  budget: number;
+  /** Kinds to emit, highest priority first (default: `chunkKinds`) */
+  priority?: ChunkKind[];
+}
+
+/**
+ * A chunk for embedding, with a stable ID and the symbol it documents.
+ */
+export interface RagChunk extends DocChunk {
+  /** Hash of the symbol ID, kind, and text, stable while the content is unchanged */
+  id: string;
+  /** Canonical URL of the symbol */
+  url: string;
+}
+
+/**
+ * Default maximum size of an embedding chunk in tokens.
+ */
+export const defaultChunkTokens = 512;
+
+const FENCED_CODE = /```\w*\n([\s\S]*?)\n```/g;
+
+/**
+ * Estimate the token count of a text, at about four characters per token.
+ */
+export function estimateTokens(text: string): number {
+  return Math.ceil(text.length / 4);
+}
+
+/**
+ * Split symbols into chunks, grouped by kind in priority order and by symbol
+ * order within a kind.
+ */
+export function buildChunks(
+  symbols: SymbolRecord[],
+  priority: ChunkKind[] = chunkKinds,
+): DocChunk[] {
+  const byKind = new Map<ChunkKind, DocChunk[]>(priority.map((kind) => [kind, []]));
+  const add = (symbol: SymbolRecord, kind: ChunkKind, text: string) => {
+    byKind.get(kind)?.push({
+      symbolId: symbol.id,
+      qualifiedName: symbol.qualifiedName,
+      kind,
+      text,
+      tokens: estimateTokens(text),
+    });
+  };
+
+  for (const symbol of symbols) {
+    add(symbol, "signature", `\`\`\`go\n${symbol.signature}\n\`\`\``);
+
+    const description = symbol.docs.description ?? symbol.docs.summary;
+    const prose = description.replace(FENCED_CODE, "").replace(/\n{3,}/g, "\n\n").trim();
+    if (prose) {
+      add(symbol, "doc", prose);
+    }
+
+    for (const example of symbol.docs.examples ?? []) {
+      add(symbol, "example", `\`\`\`${example.language ?? "go"}\n${example.code}\n\`\`\``);
+    }
+    for (const match of description.matchAll(FENCED_CODE)) {
+      add(symbol, "example", match[0]);
+    }
+  }
+
+  return priority.flatMap((kind) => byKind.get(kind)!);
+}
+
+/**
+ * Pick the symbols a chunk request is about: one symbol and its methods, or
+ * all of them.
+ */
+export function selectSymbols(symbols: SymbolRecord[], symbol?: string): SymbolRecord[] {
+  if (!symbol) return symbols;
+
+  const target = symbols.find((s) => s.id === symbol || s.qualifiedName === symbol);
+  if (!target) {
+    throw new Error(`Unknown symbol: ${symbol}`);
+  }
+  return symbols.filter(
+    (s) => s === target || s.qualifiedName.startsWith(`${target.qualifiedName}.`),
+  );
+}
+
+/**
+ * Yield chunks in priority order while they fit the budget. Chunks too large
+ * for the remaining budget are skipped, so smaller ones after them can still
+ * fill it.
+ */
+export function* streamChunks(
+  symbols: SymbolRecord[],
+  options: ChunkOptions,
+): Generator<DocChunk> {
+  let remaining = options.budget;
+  for (const chunk of buildChunks(selectSymbols(symbols, options.symbol), options.priority)) {
+    if (chunk.tokens > remaining) continue;
+    remaining -= chunk.tokens;
+    yield chunk;
+  }
+}
+
+/**
+ * Split the public API into embedding-sized chunks of at most `maxTokens`:
+ * docs are cut at paragraph boundaries (then lines, then characters) and
+ * every chunk is keyed by a hash of its content, so re-extracting unchanged
+ * docs yields the same IDs.
+ */
+export function ragChunks(
+  symbols: SymbolRecord[],
+  maxTokens: number = defaultChunkTokens,
+): RagChunk[] {
+  const urls = new Map(symbols.map((symbol) => [symbol.id, symbol.urls.canonical]));
+  const seen = new Map<string, number>();
+  const chunks: RagChunk[] = [];
+  for (const chunk of buildChunks(symbols.filter((s) => s.tags.visibility === "public"))) {
+    for (const text of splitText(chunk.text, maxTokens)) {
+      const hash = createHash("sha256")
+        .update(`${chunk.symbolId}\0${chunk.kind}\0${text}`)
+        .digest("hex")
+        .slice(0, 16);
+      // The same text twice in one symbol, like a repeated snippet, gets a numbered ID
+      const count = (seen.get(hash) ?? 0) + 1;
+      seen.set(hash, count);
+      chunks.push({
+        id: count > 1 ? `${hash}-${count}` : hash,
+        url: urls.get(chunk.symbolId)!,
+        ...chunk,
+        text,
+        tokens: estimateTokens(text),
+      });
+    }
+  }
+  return chunks;
+}
+
+/**
+ * Split a text into pieces of at most `maxTokens`, packing whole paragraphs,
+ * then whole lines of paragraphs too long on their own.
+ */
+function splitText(text: string, maxTokens: number): string[] {
+  return pack(text, maxTokens * 4, ["\n\n", "\n"]);
+}
+
+/**
+ * Pack the parts of a text split on the first separator into pieces of at
+ * most `maxChars`. Parts too long on their own are split on the next
+ * separator, or cut when none is left.
+ */
+function pack(text: string, maxChars: number, separators: string[]): string[] {
+  if (text.length <= maxChars) return [text];
+
+  const pieces: string[] = [];
+  const [separator, ...rest] = separators;
+  if (separator === undefined) {
+    for (let i = 0; i < text.length; i += maxChars) pieces.push(text.slice(i, i + maxChars));
+    return pieces;
+  }
+
+  let current = "";
+  for (const part of text.split(separator)) {
+    const next = current ? `${current}${separator}${part}` : part;
+    if (next.length <= maxChars) {
+      current = next;
+      continue;
+    }
+    if (current) pieces.push(current);
+    if (part.length <= maxChars) {
+      current = part;
+    } else {
+      current = "";
+      pieces.push(...pack(part, maxChars, rest));
+    }
+  }
+  if (current) pieces.push(current);
+  return pieces;
+}
@@ -5,38 +5,531 @@
  * Command-line interface for the Go API extractor.
  */
 
-import { program } from "commander";
-import { writeFile, mkdir } from "fs/promises";
-import { dirname } from "path";
+import { program, type Command } from "commander";
+import { createWriteStream } from "fs";
+import { writeFile, mkdir, mkdtemp, readFile, rm } from "fs/promises";
+import { once } from "events";
+import { finished } from "stream/promises";
+import { basename, dirname, join, resolve } from "path";
 import { execSync } from "child_process";
-import { createConfig } from "./config.js";
-import { GoExtractor } from "./extractor.js";
+import { tmpdir } from "os";
+import { performance } from "perf_hooks";
+import {
+  createConfig,
+  defaultConfig,
+  validateConfig,
+  type AssertionPolicy,
+  type ExampleVerification,
+  type GeneratedPolicy,
+  type GoExtractorConfig,
+  type InternalPackagePolicy,
+  type VendorPolicy,
+  type VisibilityTier,
+} from "./config.js";
+import { GoExtractor, type ParsedFile } from "./extractor.js";
 import { GoTransformer } from "./transformer.js";
+import {
+  buildOutput,
+  outputFormats,
+  serializeOutput,
+  type ExtractorOutput,
+  type OutputFormat,
+} from "./output.js";
+import {
+  applyProfile,
+  extractionProfiles,
+  type ExtractionProfile,
+  type ProfiledOutput,
+} from "./profile.js";
+import { diffOutputs, formatDiff, hasChanges } from "./check.js";
+import { runConformance } from "./conformance.js";
+import { defaultDocLimits } from "./truncate.js";
+import { TimingRecorder, formatTimings } from "./timings.js";
+import { logger, logLevels, type LogFormat, type LogLevel } from "./logger.js";
+import { ProgressReporter } from "./progress.js";
+import { exitCodes, formatExtractionError } from "./errors.js";
+import {
+  isGoFileSource,
+  readStream,
+  stageGoFile,
+  stageGoFileFrom,
+  type StagedFile,
+} from "./single-file.js";
+import { formatCoverage } from "./coverage.js";
+import { formatLintIssues, lintPackage, parseLintSeverities } from "./lint.js";
+import { formatSchemaErrors, outputSchema, validateOutput } from "./schema.js";
+import { mdxPageSymbols, renderMdx } from "./mdx.js";
+import { buildDocusaurusSidebar, docusaurusSidebarFile } from "./docusaurus.js";
+import { unifyOutput } from "./unified.js";
+import { jsonlRecords, parseJsonl } from "./jsonl.js";
+import { writeSqlite } from "./sqlite.js";
+import { searchIndexAdapters, searchRecords } from "./search.js";
+import { defaultLlmsMaxChars, renderLlmsText } from "./llms.js";
+import { defaultChunkTokens, ragChunks } from "./chunks.js";
+import { buildSymbolGraph } from "./graph.js";
+import { writeScip } from "./scip.js";
+import { splitManifestFile, splitOutput } from "./split.js";
+import {
+  compress,
+  compressFile,
+  compressedPath,
+  compressions,
+  decompress,
+  uncompressedPath,
+  type Compression,
+} from "./compress.js";
+import { buildToc } from "./toc.js";
+import { writeGodoc } from "./godoc.js";
+import { renderMermaid } from "./mermaid.js";
+import { renderInventory } from "./inventory.js";
+import { collectProvenance, extractorManifest, withProvenance } from "./provenance.js";
+import {
+  configFileName,
+  findConfigFile,
+  loadConfigFile,
+  type ConfigFile,
+} from "./config-file.js";
+import { DaemonClient, ExtractionDaemon, defaultDaemonSocket } from "./daemon.js";
+import { ParseCache } from "./parse-cache.js";
+import { affectedPackages, splitChanges, watchSources } from "./watch.js";
+import {
+  changedGoFiles,
+  loadSplitOutput,
+  mergeSplitOutput,
+  type SinceChanges,
+} from "./since.js";
+import { formatPlan, planDeletions, planFiles, plannedPackages, type PlannedFile } from "./plan.js";
+import {
+  normalizeExcludeGlob,
+  packageDependencies,
+  packageDependents,
+  packageIncludePatterns,
+  resolvePackagePatterns,
+} from "./packages.js";
+import {
+  downloadModule,
+  githubRepo,
+  moduleName,
+  moduleRevision,
+  parseModuleSpec,
+  resolveModuleVersion,
+  type DownloadedModule,
+} from "./remote.js";
+import {
+  buildWorkspaceManifest,
+  goWorkFileName,
+  loadWorkspace,
+  nestedModuleExcludes,
+  workspaceManifestFile,
+  workspaceModuleFile,
+  type GoWorkspace,
+} from "./workspace.js";
+import { serveHover, watchOutputFile } from "./hover.js";
+import { createHistoryServer, loadVersionStore } from "./history.js";
+import { ExtractionCache, createExtractionServer, defaultServeCacheSize } from "./serve.js";
+import { createGrpcServer } from "./grpc.js";
+import { applyPlugins } from "./plugins.js";
+import { PackageCache } from "./package-cache.js";
+import {
+  defaultWorkerCount,
+  forEachLimit,
+  parseWorkerCount,
+  workerMemoryLimit,
+} from "./worker-pool.js";
+import { loadLocaleBundle } from "./labels.js";
+import { renderStubs } from "./stubs.js";
+import { defaultDocTags } from "./doc-comment.js";
+
+/**
+ * Formats rendered from the full profile, which `--check` can't compare.
+ */
+const FULL_PROFILE_FORMATS: OutputFormat[] = [
+  "mdx",
+  "unified",
+  "sqlite",
+  "llms",
+  "chunks",
+  "graph",
+  "scip",
+  "godoc",
+  "csv",
+  "tsv",
+];
 
 interface CliOptions {
   package: string;
   path: string;
   output: string;
+  config?: string;
   repo: string;
   sha: string;
   includeUnexported: boolean;
+  ownersFile?: string;
+  feedbackUrlTemplate?: string;
+  dedupe: boolean;
+  contextPairs: boolean;
+  examples: boolean;
+  notes: string;
+  verifyExamples: ExampleVerification;
+  docTags: string;
+  maxDocChars?: string;
+  internalPackages: InternalPackagePolicy;
+  vendor: VendorPolicy;
+  generated: GeneratedPolicy;
+  assertions: AssertionPolicy;
+  testHelpers: boolean;
+  testFunctions: boolean;
+  chainOrder: boolean;
+  markdown: boolean;
+  html: boolean;
+  linkTemplate?: string;
+  sidecarDocs?: string;
+  locale?: string;
+  localeUrl?: string;
+  visibility: string;
+  format: OutputFormat;
+  split: boolean;
+  watch: boolean;
+  since?: string;
+  dryRun: boolean;
+  workspace: boolean;
+  stdin: boolean;
+  packages?: string[];
+  exclude?: string[];
+  tags?: string;
+  goos?: string;
+  goarch?: string;
+  docusaurus?: string;
+  compress?: Compression;
+  profile: ExtractionProfile;
+  check: boolean;
+  timings: boolean;
+  coverage: boolean;
+  searchIndex?: string;
+  searchFormat: string;
+  toc?: string;
+  diagrams?: string;
+  llmsMaxChars: string;
+  chunkTokens: string;
+  daemon?: string | true;
+  moduleCache?: string;
   verbose: boolean;
+  logLevel?: string;
+  logFormat: string;
+  progress: boolean;
+  plugin?: string[];
+  cacheDir?: string;
+  workers: string;
+  packageTimeout?: string;
+  maxMemory?: string;
 }
 
+program.name("extract-go").description("Extract Go API documentation to IR format");
+
 program
-  .name("extract-go")
-  .description("Extract Go API documentation to IR format")
-  .requiredOption("--package <name>", "Package name (e.g., langsmith)")
-  .requiredOption("--path <path>", "Path to the Go source directory")
-  .requiredOption("--output <file>", "Output JSON file path")
+  .command("extract", { isDefault: true })
+  .description("Extract a Go package to IR format")
+  .argument(
+    "[source...]",
+    "`module <import path>@<version>` to download the module from the Go proxy instead of " +
+      "--path, or a single .go file to extract without its module",
+  )
+  .option("--package <name>", "Package name (e.g., langsmith)")
+  .option("--path <path>", "Path to the Go source directory")
+  .option(
+    "--output <file>",
+    "Output file path (a directory for --format stubs, mdx, and godoc, and --split)",
+  )
+  .option(
+    "--config <file>",
+    "Configuration file (default: langchain-references.yaml in the working directory, if any)",
+  )
   .option("--repo <repo>", "Repository (e.g., langchain-ai/langsmith-go)", "")
   .option("--sha <sha>", "Git commit SHA", "")
-  .option("--include-unexported", "Include unexported symbols", false)
-  .option("-v, --verbose", "Enable verbose output", false);
+  .option(
+    "--include-unexported",
+    "Also extract unexported functions, types, and fields, with private visibility",
+    false,
+  )
+  .option("--owners-file <file>", "CODEOWNERS file (defaults to the repository's CODEOWNERS)")
+  .option(
+    "--feedback-url-template <template>",
+    "URL template for per-symbol doc feedback links ({repo}, {file}, {line}, {symbol}, ...)",
+  )
+  .option(
+    "--visibility <tiers>",
+    "Comma-separated visibility tiers to include (public, partner, internal)",
+    "public",
+  )
+  .option(
+    "--format <format>",
+    "Output format: json (IR document), stubs (Go files with only exported declarations), " +
+      "mdx (a page per package, type, and function), unified (cross-language schema), " +
+      "jsonl (one record per line), sqlite (a queryable index database), " +
+      "llms (llms.txt plain text), chunks (embedding-sized doc chunks as JSONL), " +
+      "graph (symbol graph), scip (SCIP code-intelligence index), " +
+      "godoc (pkg.go.dev-style HTML per package), " +
+      "or csv/tsv (an inventory of the public API)",
+    "json",
+  )
+  .option(
+    "--split",
+    "Write a file per package and a manifest with checksums to the --output directory",
+    false,
+  )
+  .option(
+    "--packages <patterns...>",
+    "Go package patterns to extract, relative to --path or import paths (e.g. ./... or ./llms/...)",
+  )
+  .option(
+    "--exclude <globs...>",
+    "Source files to leave out, like *.pb.go or zz_generated* (matched in any directory)",
+  )
+  .option(
+    "--tags <tags>",
+    "Comma-separated build tags to satisfy, as with go build -tags. With any of --tags, --goos, " +
+      "and --goarch, only the files whose build constraints hold are extracted",
+  )
+  .option("--goos <os>", "GOOS to select files for (default: GOOS, or the host's)")
+  .option("--goarch <arch>", "GOARCH to select files for (default: GOARCH, or the host's)")
+  .option(
+    "--no-workspace",
+    "Extract only the module at --path, even if it has a go.work listing more modules",
+  )
+  .option(
+    "--stdin",
+    "Extract a single Go file read from stdin, printing the output without --output",
+    false,
+  )
+  .option(
+    "--watch",
+    "Keep running and rewrite the --split package files that change when the source changes",
+    false,
+  )
+  .option(
+    "--since <ref>",
+    "Re-extract only the packages with Go files changed since a git ref and the ones " +
+      "importing them, and update the --split output in place",
+  )
+  .option(
+    "--dry-run",
+    "Report the packages that would be extracted and the files that would be written or " +
+      "deleted, with their sizes, without touching the outputs",
+    false,
+  )
+  .option(
+    "--compress <compression>",
+    "Compress the output file, or --split package files, with gzip or zstd",
+  )
+  .option(
+    "--docusaurus <path>",
+    "For --format mdx: add Docusaurus front matter and a sidebars.json for pages at this path " +
+      "in the docs folder",
+  )
+  .option(
+    "--llms-max-chars <chars>",
+    "Size cap of the llms.txt corpus in characters",
+    String(defaultLlmsMaxChars),
+  )
+  .option(
+    "--chunk-tokens <tokens>",
+    "Maximum size of a doc chunk in tokens, for --format chunks",
+    String(defaultChunkTokens),
+  )
+  .option(
+    "--workers <n>",
+    "Worker threads to parse files and write --split package files on; the output is the " +
+      "same for any count (default: GOMAXPROCS, or the number of cores)",
+    String(defaultWorkerCount()),
+  )
+  .option(
+    "--package-timeout <ms>",
+    "Skip packages whose files take longer than this to parse in total, reported as timed out",
+  )
+  .option(
+    "--max-memory <mb>",
+    "Heap budget of parsing in megabytes, shared by the --workers; packages with a file that " +
+      "exceeds it are skipped and reported",
+  )
+  .option(
+    "--cache-dir <dir>",
+    "Directory to keep per-package parse results in between runs, keyed by a hash of the " +
+      "package's source files, so unchanged packages aren't parsed again",
+  )
+  .option(
+    "--plugin <paths...>",
+    "Transform plugins to run over the symbols before output: executables, or Go files run " +
+      "with go run, reading and writing JSON on stdio",
+  )
+  .option(
+    "--profile <profile>",
+    "How much to emit: full, summary (package synopsis and symbol names, kinds, one-line docs), " +
+      "or signatures (symbol names, kinds, signatures)",
+    "full",
+  )
+  .option(
+    "--internal-packages <policy>",
+    "Symbols from internal/... packages: exclude, include (flagged), or reexposed by public APIs",
+    "include",
+  )
+  .option(
+    "--vendor <policy>",
+    "Packages in vendor/ trees: skip, or extract them under the dependencies namespace",
+    "skip",
+  )
+  .option(
+    "--generated <policy>",
+    "Files marked `Code generated ... DO NOT EDIT.`: mark their symbols, or exclude them",
+    "mark",
+  )
+  .option(
+    "--assertions <policy>",
+    "Interface assertions like `var _ I = (*T)(nil)`: ignore, merge with inferred ones, or only",
+    "merge",
+  )
+  .option(
+    "--test-helpers",
+    "Document symbols exported from in-package _test.go files as testing helpers",
+    false,
+  )
+  .option(
+    "--test-functions",
+    "Link Benchmark and Fuzz functions of _test.go files to the symbols they exercise",
+    false,
+  )
+  .option(
+    "--max-doc-chars <chars>",
+    "Truncate descriptions longer than this to their synopsis and first sections",
+  )
+  .option(
+    "--chain-order",
+    "Infer the typical call order of builder methods from doc comment examples",
+    false,
+  )
+  .option("--markdown", "Render doc comments as CommonMark into docs.markdown", false)
+  .option("--html", "Render doc comments as HTML into docs.html", false)
+  .option(
+    "--link-template <template>",
+    "URL template of symbol links in rendered docs ({package}, {symbol}, {id})",
+  )
+  .option(
+    "--sidecar-docs <dir>",
+    "Directory of Markdown files (Client.md, Client.Get.md) merged into symbol docs",
+  )
+  .option("--locale <file>", "Locale bundle (JSON) translating section names and notes")
+  .option("--locale-url <url>", "Reference the locale bundle at this URL instead of embedding it")
+  .option("--no-context-pairs", "Don't cross-link Foo/FooContext function variants")
+  .option(
+    "--notes <markers>",
+    "Comma-separated go/doc note markers to collect, like BUG,TODO",
+    "BUG",
+  )
+  .option(
+    "--doc-tags <names>",
+    "Comma-separated annotation tags to split off doc comments, like since,experimental",
+    defaultDocTags.join(","),
+  )
+  .option(
+    "--verify-examples <mode>",
+    "Examples referring to APIs the package doesn't declare: off, warn, or error",
+    "off",
+  )
+  .option("--no-examples", "Don't attach Example functions from _test.go files to symbols")
+  .option(
+    "--dedupe",
+    "Extract byte-identical packages (forked, or vendored with --vendor dependencies) only " +
+      "once, with aliases",
+    false,
+  )
+  .option(
+    "--check",
+    "Compare against the existing output file and exit non-zero if it would change",
+    false,
+  )
+  .option("--timings", "Print a per-package timing breakdown of the extraction phases", false)
+  .option("--coverage", "Print the documentation coverage per package and kind", false)
+  .option("--search-index <file>", "Also write search records of the public API to this file")
+  .option(
+    "--search-format <adapter>",
+    "Search index format: records (IR search records), algolia, or typesense (JSONL)",
+    "records",
+  )
+  .option("--toc <file>", "Also write the navigation tree of the public API to this file")
+  .option("--diagrams <dir>", "Also write a Mermaid class diagram per package to this directory")
+  .option("--daemon [socket]", "Run the extraction on a running `extract-go daemon`")
+  .option(
+    "--module-cache <dir>",
+    "Directory modules downloaded from the Go proxy are cached in (default: in the temp dir)",
+  )
+  .option("-v, --verbose", "Enable verbose output, like --log-level debug", false)
+  .option("--log-level <level>", `Log level: ${logLevels.join(", ")} (default: info)`)
+  .option("--log-format <format>", "Log format: text, or json lines on stderr", "text")
+  .option("--no-progress", "Don't report extraction progress")
+  .action(extract);
 
-program.parse();
+program
+  .command("daemon")
+  .description("Serve extraction requests over a local socket, keeping parsed files warm")
+  .option("--socket <path>", "Socket path", defaultDaemonSocket)
+  .action(daemon);
+
+program
+  .command("hover")
+  .description("Serve JSON-RPC hover requests on stdio from the latest extraction output")
+  .requiredOption("--input <file>", "Extraction output (symbols.json) to answer from")
+  .option("--path <path>", "Source root (default: the output's repo path)")
+  .action(hover);
 
-const options = program.opts<CliOptions>();
+program
+  .command("history")
+  .description("Serve GET /symbols/{id}/history from a versioned extraction store")
+  .requiredOption("--store <dir>", "Directory with one <version>/symbols.json per version")
+  .option("--port <port>", "Port to listen on", "4180")
+  .action(history);
+
+program
+  .command("serve")
+  .description("Serve POST /extract and GET /symbol/{id}, extracting modules from the Go proxy")
+  .option("--port <port>", "Port to listen on", "4181")
+  .option("--cache-size <n>", "Module versions to keep extracted", String(defaultServeCacheSize))
+  .option(
+    "--module-cache <dir>",
+    "Directory modules downloaded from the Go proxy are cached in (default: in the temp dir)",
+  )
+  .option("--grpc-port <port>", "Also serve the gRPC service of extractor.proto on this port")
+  .action(serve);
+
+program
+  .command("lint")
+  .description("Check exported symbols and the package comment for missing or malformed docs")
+  .requiredOption("--path <path>", "Path to the Go source directory")
+  .option("--package <name>", "Package name (default: the directory name)")
+  .option(
+    "--rule <rule=severity>",
+    "Severity of a rule (missing-doc, doc-prefix, package-doc): error, warning, or off",
+    (spec: string, specs: string[]) => [...specs, spec],
+    [] as string[],
+  )
+  .action(lint);
+
+program
+  .command("validate <files...>")
+  .description("Check extraction output files against the output JSON Schema")
+  .action(validate);
+
+program
+  .command("schema")
+  .description("Print the output JSON Schema")
+  .action(() => console.log(JSON.stringify(outputSchema, null, 2)));
+
+program
+  .command("conformance [path]")
+  .description(
+    "Extract a Go module (default: the bundled fixture package) and validate it against IR " +
+      "consumers",
+  )
+  .option("--output <file>", "Also write the fixture extraction to this file")
+  .action(conformance);
 
 /**
  * Check if Go is installed.
@@ -50,13 +543,459 @@ function checkGoInstalled(): boolean {
   }
 }
 
-async function main(): Promise<void> {
+/**
+ * Compare freshly extracted output with the committed baseline.
+ * Returns the process exit code: 0 when up to date, 1 when it would change.
+ */
+async function checkOutput(baselinePath: string, current: ProfiledOutput): Promise<number> {
+  let baseline: ProfiledOutput;
   try {
+    baseline = await readOutputFile(baselinePath);
+  } catch {
+    logger.error(`❌ No baseline output found at ${baselinePath}`);
+    return 1;
+  }
+
+  const diff = diffOutputs(baseline, current);
+  if (!hasChanges(diff)) {
+    logger.info(`✅ ${baselinePath} is up to date`);
+    return 0;
+  }
+
+  logger.error(`❌ ${formatDiff(diff)}`);
+  logger.error(`\nRe-run extract-go without --check to update ${baselinePath}.`);
+  return 1;
+}
+
+/**
+ * Extract the package and write Go stubs of its exported API, mirroring the
+ * source layout under the output directory.
+ */
+async function writeStubs(config: GoExtractorConfig, outputDir: string): Promise<void> {
+  const result = await new GoExtractor(config).extract();
+  const stubs = await renderStubs(result, config.packagePath);
+  for (const [file, content] of stubs) {
+    await mkdir(dirname(join(outputDir, file)), { recursive: true });
+    await writeFile(join(outputDir, file), content, "utf-8");
+  }
+  logger.info(`✅ Wrote ${stubs.size} stub files to ${outputDir}`);
+}
+
+/**
+ * Read an output file written as JSON, or as JSONL when it has a `.jsonl`
+ * extension, decompressing it when it was compressed.
+ */
+async function readOutputFile(file: string): Promise<ProfiledOutput> {
+  const text = decompress(await readFile(file)).toString("utf-8");
+  return uncompressedPath(file).endsWith(".jsonl")
+    ? parseJsonl(text)
+    : (JSON.parse(text) as ProfiledOutput);
+}
+
+/**
+ * Stream an output to a file as JSONL, one record at a time.
+ */
+async function writeJsonl(output: ProfiledOutput, file: string): Promise<void> {
+  const stream = createWriteStream(file, "utf-8");
+  for (const record of jsonlRecords(output)) {
+    if (!stream.write(`${record}\n`)) await once(stream, "drain");
+  }
+  stream.end();
+  await finished(stream);
+}
+
+/**
+ * Write the MDX pages of an output to a directory, with a Docusaurus sidebar
+ * fragment when the pages' path in the docs folder is given.
+ */
+async function writeMdx(
+  output: ExtractorOutput,
+  outputDir: string,
+  docusaurus?: string,
+): Promise<void> {
+  const pages = renderMdx(output, { docusaurus });
+  await mkdir(outputDir, { recursive: true });
+  for (const [file, content] of pages) {
+    await mkdir(dirname(join(outputDir, file)), { recursive: true });
+    await writeFile(join(outputDir, file), content, "utf-8");
+  }
+  logger.info(`✅ Wrote ${pages.size} MDX pages to ${outputDir}`);
+  if (docusaurus !== undefined) {
+    const sidebar = buildDocusaurusSidebar(output, mdxPageSymbols(output), docusaurus);
+    const content = JSON.stringify(sidebar, null, 2);
+    await writeFile(join(outputDir, docusaurusSidebarFile), content, "utf-8");
+    logger.info(`✅ Wrote the Docusaurus sidebar to ${join(outputDir, docusaurusSidebarFile)}`);
+  }
+}
+
+/**
+ * Write an output as a file per package and a manifest, compressing the
+ * package files with the given compression. Up to `workers` package files
+ * are written at a time, and the manifest last. Given the files of a
+ * previous write, only changed files are rewritten and stale ones removed.
+ * Returns the files written, uncompressed.
+ */
+async function writeSplit(
+  output: ExtractorOutput,
+  outputDir: string,
+  workers: number,
+  compression?: Compression,
+  previous?: Map<string, string>,
+): Promise<Map<string, string>> {
+  const files = splitOutput(output, compression);
+  const { written, removed } = splitChanges(previous ?? new Map(), files);
+  const write = async (file: string) => {
+    const content = files.get(file)!;
+    await mkdir(dirname(join(outputDir, file)), { recursive: true });
+    const compressed = compression && file !== splitManifestFile;
+    await writeFile(join(outputDir, file), compressed ? compress(content, compression) : content);
+  };
+  const packageFiles = written.filter((file) => file !== splitManifestFile);
+  await forEachLimit(packageFiles, workers, write);
+  if (written.includes(splitManifestFile)) {
+    await write(splitManifestFile);
+  }
+  for (const file of removed) {
+    await rm(join(outputDir, file), { force: true });
+  }
+  if (previous) {
+    const packages = written.filter((file) => file !== splitManifestFile).length;
+    const gone = removed.length > 0 ? `, removed ${removed.length}` : "";
+    logger.info(
+      `✅ Rewrote ${packages} of ${files.size - 1} package files${gone} in ${outputDir}`,
+    );
+  } else {
+    const packages = files.size - 1;
+    logger.info(`✅ Wrote ${packages} package files and ${splitManifestFile} to ${outputDir}`);
+  }
+  return files;
+}
+
+/**
+ * Re-extract whenever the source changes, with a warm parse cache, and
+ * rewrite the package files of the split output that changed. Runs until
+ * the process is interrupted; failed re-extractions are reported and the
+ * previous output kept.
+ */
+function watchSplit(
+  config: GoExtractorConfig,
+  options: CliOptions,
+  cache: ParseCache<ParsedFile>,
+  files: Map<string, string>,
+): void {
+  let previous = files;
+  let running = Promise.resolve();
+  watchSources(config.packagePath, (changed) => {
+    running = running.then(async () => {
+      try {
+        logger.info(`🔄 Changed: ${affectedPackages(changed).join(", ")}`);
+        const extracted = await extractLocally(config, options, undefined, cache);
+        const provenance = await collectProvenance(
+          extracted.package,
+          config.packagePath,
+          process.env,
+          config,
+        );
+        const output = withProvenance(extracted, provenance) as ExtractorOutput;
+        const schemaErrors = validateOutput(output);
+        if (schemaErrors.length > 0) {
+          throw new Error(`Output doesn't match the schema:\n${formatSchemaErrors(schemaErrors)}`);
+        }
+        previous = await writeSplit(
+          output,
+          options.output,
+          config.workers ?? 1,
+          options.compress,
+          previous,
+        );
+      } catch (error) {
+        logger.error("❌ Re-extraction failed", { error });
+      }
+    });
+  });
+  logger.info(`👀 Watching ${config.packagePath} for changes (Ctrl+C to stop)`);
+}
+
+/**
+ * Find the package directories with Go files changed since a git ref, with
+ * the packages importing them, and read the split output they are to be
+ * merged into. Without directories, a go.mod changed and every package is
+ * extracted again.
+ */
+async function changesSince(
+  ref: string,
+  root: string,
+  outputDir: string,
+): Promise<SinceChanges> {
+  const previous = await loadSplitOutput(outputDir);
+  const files = await changedGoFiles(root, ref);
+  if (!files) {
+    logger.info(`🔄 go.mod changed since ${ref}; extracting every package`);
+    return { previous };
+  }
+  const changed = affectedPackages(files);
+  if (changed.length === 0) return { previous, dirs: [] };
+  const dirs = await packageDependents(root, changed);
+  logger.info(`🔄 Changed since ${ref}: ${changed.join(", ")}`);
+  if (dirs.length > changed.length) {
+    logger.info(`🔄 Importing them: ${dirs.filter((dir) => !changed.includes(dir)).join(", ")}`);
+  }
+  return { previous, dirs, parseDirs: await packageDependencies(root, dirs) };
+}
+
+/**
+ * Run the extraction pipeline in this process.
+ */
+async function extractLocally(
+  config: GoExtractorConfig,
+  options: CliOptions,
+  timings?: TimingRecorder,
+  cache?: ParseCache<ParsedFile>,
+  progress?: ProgressReporter,
+): Promise<ProfiledOutput> {
+  // Run extraction
+  const packageCache = options.cacheDir
+    ? new PackageCache(options.cacheDir, (await extractorManifest()).version)
+    : undefined;
+  const extractor = new GoExtractor(config, timings, cache, progress, packageCache);
+  const result = await extractor.extract();
+
+  logger.debug(`Module: ${result.moduleName}`);
+  logger.debug(`Version: ${result.version}`);
+  logger.debug(`Found ${result.types.length} types`);
+  logger.debug(`Found ${result.functions.length} functions`);
+  logger.debug(`Found ${result.constants.length} constants`);
+  for (const duplicate of result.duplicates ?? []) {
+    const canonical = duplicate.canonical || ".";
+    logger.debug(`Skipped ${duplicate.aliases.join(", ")} (identical to ${canonical})`);
+  }
+
+  // Transform to IR format
+  const transformer = new GoTransformer(result, config, timings);
+  const symbols = transformer.transform();
+
+  logger.debug(`Transformed to ${symbols.length} IR symbols`);
+
+  const build = () => buildOutput(result, config, symbols);
+  const output = await applyPlugins(
+    timings ? timings.timeRun("render", build) : build(),
+    options.plugin ?? [],
+    config,
+  );
+  const render = () => applyProfile(output, options.profile);
+  return timings ? timings.timeRun("render", render) : render();
+}
+
+/**
+ * A reporter of extraction progress, unless it's turned off or info
+ * messages aren't logged.
+ */
+function progressReporter(options: CliOptions): ProgressReporter | undefined {
+  return options.progress && logger.enabled("info") ? new ProgressReporter() : undefined;
+}
+
+/**
+ * Download the module a `module <import path>@<version>` source names, and
+ * point the options at it: the package name defaults to the module's name,
+ * and, for modules on GitHub, the repository and SHA to the module's repo
+ * and version tag.
+ */
+async function downloadSource(
+  source: string[],
+  options: CliOptions,
+  command: Command,
+): Promise<DownloadedModule> {
+  if (source[0] !== "module" || source.length !== 2) {
+    throw new Error(
+      `Unknown source: ${source.join(" ")} (expected module <import path>@<version>)`,
+    );
+  }
+  if (command.getOptionValueSource("path") === "cli") {
+    throw new Error("--path can't be combined with a module source");
+  }
+  const spec = parseModuleSpec(source[1]);
+  const module = await downloadModule(spec, { cacheDir: options.moduleCache });
+  const origin = module.cached ? "cached" : "downloaded";
+  logger.debug(`Remote module: ${module.path}@${module.version} (${origin} in ${module.dir})`);
+
+  options.path = module.dir;
+  options.package ??= moduleName(module.path);
+  const repo = githubRepo(module.path);
+  if (repo && !options.repo) {
+    options.repo = repo;
+    options.sha ||= moduleRevision(module.path, module.version);
+  }
+  return module;
+}
+
+/**
+ * Extract each member module of a workspace to `<module path>.json` in the
+ * output directory, and write the combined manifest.
+ */
+async function extractWorkspace(
+  workspace: GoWorkspace,
+  config: GoExtractorConfig,
+  options: CliOptions,
+): Promise<void> {
+  const unsupported = [
+    options.format !== "json" && `--format ${options.format}`,
+    options.split && "--split",
+    options.packages && "--packages",
+    options.check && "--check",
+    options.daemon && "--daemon",
+    options.compress && "--compress",
+    options.toc && "--toc",
+    options.searchIndex && "--search-index",
+    options.diagrams && "--diagrams",
+  ].filter(Boolean);
+  if (unsupported.length > 0) {
+    throw new Error(
+      `${goWorkFileName} workspaces can't be extracted with ${unsupported.join(", ")} ` +
+        "(use --no-workspace to extract a single module)",
+    );
+  }
+  const members = workspace.modules.map((module) => `${module.modulePath}@${module.version}`);
+  logger.debug(`Workspace modules: ${members.join(", ")}`);
+
+  const outputs: Parameters<typeof buildWorkspaceManifest>[2] = [];
+  for (const module of workspace.modules) {
+    const moduleConfig = createConfig({
+      ...config,
+      packageName: module.modulePath,
+      packagePath: join(config.packagePath, module.dir),
+      moduleVersion: module.version,
+      excludePatterns: [...config.excludePatterns, ...nestedModuleExcludes(module, workspace)],
+    });
+    const extracted = await extractLocally(
+      moduleConfig,
+      options,
+      undefined,
+      undefined,
+      progressReporter(options),
+    );
+    const provenance = await collectProvenance(
+      extracted.package,
+      moduleConfig.packagePath,
+      process.env,
+      moduleConfig,
+    );
+    const output = withProvenance(extracted, provenance);
+    const schemaErrors = validateOutput(output);
+    if (schemaErrors.length > 0) {
+      throw new Error(
+        `Output of ${module.modulePath} doesn't match the schema:\n` +
+          formatSchemaErrors(schemaErrors),
+      );
+    }
+    const content = serializeOutput(output);
+    const file = join(options.output, workspaceModuleFile(module));
+    await mkdir(dirname(file), { recursive: true });
+    await writeFile(file, content, "utf-8");
+    outputs.push({ module, symbols: output.symbols.length, content });
+  }
+
+  const manifest = buildWorkspaceManifest(config.packageName, workspace, outputs);
+  const manifestFile = join(options.output, workspaceManifestFile);
+  await mkdir(options.output, { recursive: true });
+  await writeFile(manifestFile, JSON.stringify(manifest, null, 2), "utf-8");
+  logger.info(
+    `✅ Extracted ${workspace.modules.length} workspace modules to ${options.output} ` +
+      `(${workspaceManifestFile} lists them)`,
+  );
+}
+
+/**
+ * Run the extraction on a running daemon, which reuses its parsed files.
+ */
+async function extractOnDaemon(
+  socket: string | true,
+  config: GoExtractorConfig,
+  profile: ExtractionProfile,
+): Promise<ProfiledOutput> {
+  const client = new DaemonClient(socket === true ? undefined : socket);
+  try {
+    // The daemon runs in its own working directory
+    const output = await client.extract({
+      config: {
+        ...config,
+        packagePath: resolve(config.packagePath),
+        ownersFile: config.ownersFile && resolve(config.ownersFile),
+      },
+      profile,
+    });
+    output.package.repo.path = config.packagePath;
+    // Report the errors the daemon ran into, as a local run does
+    for (const error of output.package.errors ?? []) {
+      logger.error(formatExtractionError(error), { ...error });
+    }
+    return output;
+  } finally {
+    client.close();
+  }
+}
+
+/**
+ * Fill in the options not given on the command line from a configuration
+ * file.
+ */
+function applyConfigFile(options: CliOptions, command: Command, file: ConfigFile): void {
+  const values: Partial<CliOptions> = {
+    package: file.package,
+    path: file.path,
+    output: file.output?.path,
+    repo: file.repo,
+    sha: file.sha,
+    packages: file.packages,
+    exclude: file.exclude,
+    format: file.output?.format,
+    profile: file.output?.profile,
+    toc: file.output?.toc,
+    searchIndex: file.output?.searchIndex,
+    diagrams: file.output?.diagrams,
+    feedbackUrlTemplate: file.urls?.feedback,
+    linkTemplate: file.urls?.symbol,
+    visibility: file.visibility?.join(","),
+    plugin: file.plugins,
+  };
+  for (const [key, value] of Object.entries(values)) {
+    if (value !== undefined && command.getOptionValueSource(key) !== "cli") {
+      Object.assign(options, { [key]: value });
+    }
+  }
+}
+
+async function extract(source: string[], options: CliOptions, command: Command): Promise<void> {
+  let single: StagedFile | undefined;
+  let exitCode: number | undefined;
+  try {
+    const configFile = findConfigFile(options.config);
+    const fileConfig = configFile ? await loadConfigFile(configFile) : {};
+    applyConfigFile(options, command, fileConfig);
+    logger.configure({
+      level: (options.logLevel ?? (options.verbose ? "debug" : "info")) as LogLevel,
+      format: options.logFormat as LogFormat,
+    });
+    const start = performance.now();
+    single =
+      options.stdin || isGoFileSource(source)
+        ? await stageSingleFile(source, options, command)
+        : undefined;
+    const remote =
+      !single && source.length > 0 ? await downloadSource(source, options, command) : undefined;
+    for (const [key, name] of [
+      ["package", "--package <name>"],
+      ["path", "--path <path>"],
+      ["output", "--output <file>"],
+    ] as const) {
+      // A single file is printed without an output path
+      if (!options[key] && !(single && key === "output")) {
+        throw new Error(`${name} is required, on the command line or in ${configFileName}`);
+      }
+    }
+
     // Check for Go (optional, for future enhancements)
     const goInstalled = checkGoInstalled();
-    if (options.verbose) {
-      console.log("Go installed:", goInstalled);
-    }
+    logger.debug(`Go installed: ${goInstalled}`);
 
     // Create configuration
     const config = createConfig({
@@ -64,66 +1003,631 @@ async function main(): Promise<void> {
       packagePath: options.path,
       repo: options.repo,
       sha: options.sha,
+      moduleVersion: remote?.version,
       exportedOnly: !options.includeUnexported,
+      includePatterns: options.packages
+        ? packageIncludePatterns(await resolvePackagePatterns(options.path, options.packages))
+        : (fileConfig.include ?? defaultConfig.includePatterns),
+      excludePatterns: [
+        ...defaultConfig.excludePatterns!,
+        ...(options.exclude ?? []).map(normalizeExcludeGlob),
+      ],
+      buildTags: options.tags
+        ?.split(",")
+        .map((tag) => tag.trim())
+        .filter(Boolean),
+      goos: options.goos,
+      goarch: options.goarch,
+      includeSymbols: fileConfig.symbols?.include,
+      excludeSymbols: fileConfig.symbols?.exclude,
+      categories: fileConfig.categories,
+      tiers: fileConfig.tiers,
+      ownersFile: options.ownersFile,
+      feedbackUrlTemplate: options.feedbackUrlTemplate,
+      dedupePackages: options.dedupe,
+      pairContextVariants: options.contextPairs,
+      includeTiers: options.visibility.split(",").map((tier) => tier.trim() as VisibilityTier),
+      internalPackages: options.internalPackages,
+      vendoredPackages: options.vendor,
+      generatedFiles: options.generated,
+      implementationAssertions: options.assertions,
+      testHelpers: options.testHelpers,
+      testFunctions: options.testFunctions,
+      // Only the full profile emits examples
+      examples: options.examples && options.profile === "full",
+      noteMarkers: options.notes.split(",").map((marker) => marker.trim()),
+      verifyExamples: options.verifyExamples,
+      docTags: options.docTags
+        .split(",")
+        .map((tag) => tag.trim().replace(/^@/, ""))
+        .filter(Boolean),
+      inferChainOrder: options.chainOrder,
+      renderMarkdown: options.markdown,
+      renderHtml: options.html,
+      symbolUrlTemplate: options.linkTemplate,
+      sidecarDocs: options.sidecarDocs,
+      localeBundle: options.locale ? await loadLocaleBundle(options.locale) : undefined,
+      localeBundleUrl: options.localeUrl,
+      workers: parseWorkerCount(options.workers),
+      packageTimeoutMs: options.packageTimeout ? Number(options.packageTimeout) : undefined,
+      maxMemoryMb: options.maxMemory ? Number(options.maxMemory) : undefined,
+      docLimits: options.maxDocChars
+        ? {
+            default: {
+              maxChars: Number(options.maxDocChars),
+              maxSections: defaultDocLimits.default.maxSections,
+            },
+          }
+        : undefined,
     });
+    validateConfig(config);
+    if (!extractionProfiles.includes(options.profile)) {
+      throw new Error(`Unknown profile: ${options.profile}`);
+    }
+    if (!outputFormats.includes(options.format)) {
+      throw new Error(`Unknown output format: ${options.format}`);
+    }
+    if (!searchIndexAdapters[options.searchFormat]) {
+      throw new Error(`Unknown search index format: ${options.searchFormat}`);
+    }
+    if (!(Number(options.llmsMaxChars) > 0)) {
+      throw new Error(`Invalid --llms-max-chars: ${options.llmsMaxChars}`);
+    }
+    if (!(Number(options.chunkTokens) > 0)) {
+      throw new Error(`Invalid --chunk-tokens: ${options.chunkTokens}`);
+    }
+    if (options.packageTimeout !== undefined && !(Number(options.packageTimeout) > 0)) {
+      throw new Error(`Invalid --package-timeout: ${options.packageTimeout}`);
+    }
+    if (options.maxMemory !== undefined) {
+      if (!Number.isInteger(Number(options.maxMemory)) || !(Number(options.maxMemory) > 0)) {
+        throw new Error(`Invalid --max-memory: ${options.maxMemory}`);
+      }
+      workerMemoryLimit(Number(options.maxMemory), config.workers ?? 1);
+    }
+    if (options.searchIndex && options.profile !== "full") {
+      throw new Error("--search-index needs the full profile");
+    }
+    if (options.toc && options.profile !== "full") {
+      throw new Error("--toc needs the full profile");
+    }
+    if (options.diagrams && options.profile !== "full") {
+      throw new Error("--diagrams needs the full profile");
+    }
 
-    if (options.verbose) {
-      console.log("Extracting:", config.packageName);
-      console.log("Source path:", config.packagePath);
-      console.log("Repository:", config.repo);
-      console.log("SHA:", config.sha);
-      console.log();
-    }
-
-    // Run extraction
-    const extractor = new GoExtractor(config);
-    const result = await extractor.extract();
-
-    if (options.verbose) {
-      console.log("Module:", result.moduleName);
-      console.log("Version:", result.version);
-      console.log(`Found ${result.types.length} types`);
-      console.log(`Found ${result.functions.length} functions`);
-      console.log(`Found ${result.constants.length} constants`);
-    }
-
-    // Transform to IR format
-    const transformer = new GoTransformer(result, config);
-    const symbols = transformer.transform();
-
-    if (options.verbose) {
-      console.log(`Transformed to ${symbols.length} IR symbols`);
-    }
-
-    const outputData = {
-      package: {
-        packageId: `pkg_go_${config.packageName.replace(/[^a-zA-Z0-9]/g, "_")}`,
-        displayName: config.packageName,
-        publishedName: config.packageName,
-        language: "go",
-        ecosystem: "go",
-        version: result.version,
-        repo: {
-          owner: config.repo.split("/")[0] || "",
-          name: config.repo.split("/")[1] || "",
-          sha: config.sha,
-          path: config.packagePath,
-        },
-      },
-      symbols,
-    };
+    if (configFile) logger.debug(`Config file: ${configFile}`);
+    logger.debug(`Extracting: ${config.packageName}`);
+    logger.debug(`Source path: ${config.packagePath}`);
+    logger.debug(`Repository: ${config.repo}`);
+    logger.debug(`SHA: ${config.sha}`);
+
+    if (
+      FULL_PROFILE_FORMATS.includes(options.format) &&
+      (options.check || options.profile !== "full")
+    ) {
+      throw new Error(
+        `--format ${options.format} needs the full profile and can't be combined with --check`,
+      );
+    }
+    const splittable = options.format === "json" && options.profile === "full" && !options.check;
+    if (options.split && !splittable) {
+      throw new Error("--split needs --format json and the full profile, without --check");
+    }
+    if (options.watch && (!options.split || options.daemon)) {
+      throw new Error("--watch needs --split and can't be combined with --daemon");
+    }
+    if (options.since && (!options.split || options.watch || options.daemon || options.packages)) {
+      throw new Error(
+        "--since needs --split and can't be combined with --watch, --daemon, or --packages",
+      );
+    }
+    if (options.plugin && options.daemon) {
+      throw new Error("--plugin can't be combined with --daemon");
+    }
+    if (options.cacheDir && options.daemon) {
+      throw new Error("--cache-dir can't be combined with --daemon, which keeps its own cache");
+    }
+    if (options.dryRun && (options.watch || options.check)) {
+      throw new Error("--dry-run can't be combined with --watch or --check");
+    }
+    if (options.compress && !compressions.includes(options.compress)) {
+      throw new Error(
+        `Invalid --compress: ${options.compress} (expected ${compressions.join(" or ")})`,
+      );
+    }
+    if (options.compress && ["stubs", "mdx", "godoc"].includes(options.format)) {
+      throw new Error(`--compress can't be combined with --format ${options.format}`);
+    }
+    if (options.docusaurus !== undefined && options.format !== "mdx") {
+      throw new Error("--docusaurus needs --format mdx");
+    }
+    const since = options.since
+      ? await changesSince(options.since, config.packagePath, options.output)
+      : undefined;
+    if (single && !options.output) {
+      await printSingleFile(config, options);
+    } else if (options.dryRun) {
+      await planRun(config, options, since);
+    } else {
+      await writeOutputs(config, options, since);
+    }
+    if (!options.watch) {
+      const durationMs = Math.round(performance.now() - start);
+      const { warnings, errors } = logger;
+      logger.info(
+        `⏱️ Finished in ${durationMs} ms with ${warnings} warning(s) and ${errors} error(s)`,
+        { durationMs, warnings, errors },
+      );
+      if (errors > 0) exitCode = exitCodes.partial;
+    }
+  } catch (error) {
+    logger.error("❌ Extraction failed", { error });
+    exitCode = exitCodes.failure;
+  } finally {
+    // Exiting skips finally blocks, so the staged file goes before that
+    if (single) await rm(single.dir, { recursive: true, force: true });
+  }
+  if (exitCode !== undefined) process.exit(exitCode);
+}
+
+/**
+ * Copy the single Go file a source names, or the one read from stdin, to a
+ * directory of its own, and extract that directory. Without --output, only
+ * warnings are logged, leaving stdout to the output.
+ */
+async function stageSingleFile(
+  source: string[],
+  options: CliOptions,
+  command: Command,
+): Promise<StagedFile> {
+  if (options.stdin && source.length > 0) {
+    throw new Error(`--stdin can't be combined with a source: ${source.join(" ")}`);
+  }
+  if (command.getOptionValueSource("path") === "cli") {
+    throw new Error("--path can't be combined with a single file");
+  }
+  const staged = options.stdin
+    ? await stageGoFile(await readStream(process.stdin))
+    : await stageGoFileFrom(source[0]);
+  logger.debug(`Single file: ${staged.file} (staged in ${staged.dir})`);
+
+  options.path = staged.dir;
+  options.package ??= staged.packageName ?? basename(staged.file, ".go");
+  options.workspace = false;
+  if (!options.output && !options.logLevel && !options.verbose) {
+    logger.configure({ level: "warn" });
+  }
+  return staged;
+}
+
+/**
+ * Extract a single file and print its output to stdout, as JSON.
+ */
+async function printSingleFile(config: GoExtractorConfig, options: CliOptions): Promise<void> {
+  if (options.format !== "json" || options.split || options.watch || options.check) {
+    throw new Error("A single file is printed as JSON; use --output for other formats");
+  }
+  const output = await extractLocally(config, options);
+  process.stdout.write(`${serializeOutput(output)}\n`);
+}
+
+/**
+ * Plan a run: write its outputs to a staging directory instead, and report
+ * the packages it extracts and how the files at the real output paths would
+ * change. The staging directory is removed afterwards.
+ */
+async function planRun(
+  config: GoExtractorConfig,
+  options: CliOptions,
+  since?: SinceChanges,
+): Promise<void> {
+  const staging = await mkdtemp(join(tmpdir(), "extract-go-plan-"));
+  logger.info(`🧪 Dry run, staging outputs in ${staging}`);
+  try {
+    const targets = [options.output, options.searchIndex, options.toc, options.diagrams];
+    const staged = targets.map((target, i) => target && join(staging, `${i}`, basename(target)));
+    const [output, searchIndex, toc, diagrams] = staged;
+    const stagedOptions = { ...options, output: output!, searchIndex, toc, diagrams };
+    const extracted = await writeOutputs(config, stagedOptions, since);
+
+    const files: PlannedFile[] = [];
+    for (const [i, target] of targets.entries()) {
+      // Compressed outputs get an extension, so compare the directories
+      if (target) files.push(...(await planFiles(join(staging, `${i}`), dirname(target))));
+    }
+    if (since?.dirs) {
+      const written = new Set(files.map((file) => file.path));
+      const stale = [...since.previous.files.keys()]
+        .map((file) => join(options.output, file))
+        .filter((file) => !written.has(file));
+      files.push(...(await planDeletions(stale)));
+    }
+    const packages = extracted ? plannedPackages(extracted, since?.dirs) : [];
+    logger.report("Dry run plan", formatPlan(packages, files), { packages, files });
+  } finally {
+    await rm(staging, { recursive: true, force: true });
+  }
+}
 
-    // Ensure output directory exists
-    await mkdir(dirname(options.output), { recursive: true });
+/**
+ * Extract and write the outputs the options ask for. Returns the extracted
+ * output, before it is merged into a --since output, unless the run wrote
+ * stubs or a workspace, or found nothing to update.
+ */
+async function writeOutputs(
+  config: GoExtractorConfig,
+  options: CliOptions,
+  since?: SinceChanges,
+): Promise<ProfiledOutput | undefined> {
+  const workspace = options.workspace ? await loadWorkspace(config.packagePath) : undefined;
+  if (workspace) {
+    await extractWorkspace(workspace, config, options);
+    return undefined;
+  }
+  if (options.format === "stubs") {
+    if (options.daemon || options.check) {
+      throw new Error("--format stubs can't be combined with --daemon or --check");
+    }
+    await writeStubs(config, options.output);
+    return undefined;
+  }
+
+  if (since?.dirs?.length === 0) {
+    logger.info(`✅ No Go files changed since ${options.since}, nothing to update`);
+    return undefined;
+  }
+  const extractConfig = since?.dirs
+    ? { ...config, includePatterns: packageIncludePatterns(since.parseDirs ?? since.dirs) }
+    : config;
+
+  const timings = options.timings || logger.format === "json" ? new TimingRecorder() : undefined;
+  const cache = options.watch ? new ParseCache<ParsedFile>() : undefined;
+  const extracted = options.daemon
+    ? await extractOnDaemon(options.daemon, config, options.profile)
+    : await extractLocally(extractConfig, options, timings, cache, progressReporter(options));
+  const provenance = await collectProvenance(
+    extracted.package,
+    config.packagePath,
+    process.env,
+    config,
+  );
+  let outputData = withProvenance(extracted, provenance);
+  if (since?.dirs) {
+    const fresh = outputData as ExtractorOutput;
+    outputData = mergeSplitOutput(since.previous, fresh, since.dirs, config);
+  }
+
+  const outputFile = options.compress
+    ? compressedPath(options.output, options.compress)
+    : options.output;
+  if (options.check) {
+    const exitCode = await checkOutput(outputFile, outputData);
+    process.exit(exitCode);
+  }
+
+  const schemaErrors = validateOutput(outputData);
+  if (schemaErrors.length > 0) {
+    throw new Error(`Output doesn't match the schema:\n${formatSchemaErrors(schemaErrors)}`);
+  }
 
-    // Write output
-    await writeFile(options.output, JSON.stringify(outputData, null, 2), "utf-8");
+  if (options.searchIndex) {
+    const records = searchRecords(outputData as ExtractorOutput);
+    await mkdir(dirname(options.searchIndex), { recursive: true });
+    const adapter = searchIndexAdapters[options.searchFormat];
+    await writeFile(options.searchIndex, adapter.serialize(records), "utf-8");
+    logger.info(`✅ Wrote ${records.length} search records to ${options.searchIndex}`);
+  }
+  if (options.toc) {
+    const toc = buildToc(outputData as ExtractorOutput);
+    await mkdir(dirname(options.toc), { recursive: true });
+    await writeFile(options.toc, serializeOutput(toc), "utf-8");
+    logger.info(`✅ Wrote the navigation tree to ${options.toc}`);
+  }
+  if (options.diagrams) {
+    const diagrams = renderMermaid(outputData as ExtractorOutput);
+    for (const [importPath, diagram] of diagrams) {
+      const file = join(options.diagrams, `${importPath}.mmd`);
+      await mkdir(dirname(file), { recursive: true });
+      await writeFile(file, diagram, "utf-8");
+    }
+    logger.info(`✅ Wrote ${diagrams.size} Mermaid diagrams to ${options.diagrams}`);
+  }
+
+  if (options.format === "mdx") {
+    await writeMdx(outputData as ExtractorOutput, options.output, options.docusaurus);
+    logReports(options, outputData, timings);
+    return extracted;
+  }
+  if (options.format === "godoc") {
+    const count = await writeGodoc(outputData as ExtractorOutput, options.output);
+    logger.info(`✅ Wrote ${count} godoc pages to ${options.output}`);
+    logReports(options, outputData, timings);
+    return extracted;
+  }
+  if (options.split) {
+    const files = await writeSplit(
+      outputData as ExtractorOutput,
+      options.output,
+      config.workers ?? 1,
+      options.compress,
+      options.dryRun ? undefined : since?.previous.files,
+    );
+    logReports(options, outputData, timings);
+    if (options.watch) {
+      watchSplit(config, options, cache!, files);
+    }
+    return extracted;
+  }
+
+  // Ensure output directory exists
+  await mkdir(dirname(outputFile), { recursive: true });
+
+  // Write output, uncompressed to a temporary file first when compressing
+  const writtenFile = options.compress ? `${outputFile}.tmp` : outputFile;
+  if (options.format === "sqlite") {
+    await writeSqlite(outputData as ExtractorOutput, writtenFile);
+  } else if (options.format === "scip") {
+    await writeScip(outputData as ExtractorOutput, writtenFile);
+  } else if (options.format === "llms") {
+    const text = renderLlmsText(outputData as ExtractorOutput, Number(options.llmsMaxChars));
+    await writeFile(writtenFile, text, "utf-8");
+  } else if (options.format === "chunks") {
+    const { symbols } = outputData as ExtractorOutput;
+    const lines = ragChunks(symbols, Number(options.chunkTokens)).map(
+      (chunk) => `${JSON.stringify(chunk)}\n`,
+    );
+    await writeFile(writtenFile, lines.join(""), "utf-8");
+  } else if (options.format === "csv" || options.format === "tsv") {
+    const delimiter = options.format === "csv" ? "," : "\t";
+    const inventory = renderInventory(outputData as ExtractorOutput, delimiter);
+    await writeFile(writtenFile, inventory, "utf-8");
+  } else if (options.format === "jsonl") {
+    await writeJsonl(outputData, writtenFile);
+  } else {
+    const written =
+      options.format === "unified"
+        ? unifyOutput(outputData as ExtractorOutput)
+        : options.format === "graph"
+          ? buildSymbolGraph(outputData as ExtractorOutput)
+          : outputData;
+    const serialized = timings
+      ? timings.timeRun("render", () => serializeOutput(written))
+      : serializeOutput(written);
+    await writeFile(writtenFile, serialized, "utf-8");
+  }
+
+  if (options.compress) {
+    await compressFile(writtenFile, outputFile, options.compress);
+  }
+
+  logger.info(`✅ Extracted ${outputData.symbols.length} symbols to ${outputFile}`, {
+    symbols: outputData.symbols.length,
+    output: outputFile,
+  });
+  logReports(options, outputData, timings);
+  return extracted;
+}
 
-    console.log(`✅ Extracted ${symbols.length} symbols to ${options.output}`);
+/**
+ * Log the timings and doc coverage of a run, if asked for. As JSON, every
+ * run logs its per-package timings.
+ */
+function logReports(
+  options: CliOptions,
+  output: ProfiledOutput,
+  timings: TimingRecorder | undefined,
+): void {
+  if (timings) {
+    const report = timings.report();
+    if (logger.format === "json") {
+      for (const pkg of report.packages) {
+        logger.info(`Timed package ${pkg.package || "."}`, {
+          package: pkg.package || ".",
+          files: pkg.files,
+          phases: pkg.phases,
+          totalMs: pkg.total,
+        });
+      }
+    } else {
+      logger.report("Timings", formatTimings(report));
+    }
+  }
+  if (options.coverage && "coverage" in output && output.coverage) {
+    logger.report("Doc coverage", formatCoverage(output.coverage), { coverage: output.coverage });
+  }
+}
+
+/**
+ * Run the conformance check and exit non-zero if the fixture output breaks
+ * any consumer expectation.
+ */
+async function conformance(
+  packagePath: string | undefined,
+  options: { output?: string },
+): Promise<void> {
+  try {
+    const { output, issues } = await runConformance(packagePath);
+
+    if (options.output) {
+      await mkdir(dirname(options.output), { recursive: true });
+      await writeFile(options.output, serializeOutput(output), "utf-8");
+    }
+
+    if (issues.length > 0) {
+      for (const issue of issues) {
+        logger.error(`  ${issue.symbolId ?? "package"}: ${issue.message}`);
+      }
+      logger.error(
+        `❌ ${issues.length} conformance issue(s) in ${output.symbols.length} symbols`,
+      );
+      process.exit(1);
+    }
+
+    logger.info(`✅ ${output.symbols.length} symbols conform to the IR consumers`);
   } catch (error) {
-    console.error("❌ Extraction failed:", error);
+    logger.error("❌ Conformance check failed", { error });
     process.exit(1);
   }
 }
 
-main();
+/**
+ * Validate output files against the output schema and exit non-zero if any
+ * doesn't match.
+ */
+async function validate(files: string[]): Promise<void> {
+  let invalid = 0;
+  for (const file of files) {
+    try {
+      const errors = validateOutput(await readOutputFile(file));
+      if (errors.length > 0) {
+        invalid++;
+        logger.error(`❌ ${file}:\n${formatSchemaErrors(errors)}`);
+      } else {
+        logger.info(`✅ ${file} matches the output schema`);
+      }
+    } catch (error) {
+      invalid++;
+      logger.error(`❌ ${file}: ${error}`);
+    }
+  }
+  if (invalid > 0) process.exit(1);
+}
+
+/**
+ * Lint the docs of a package and exit non-zero if any rule reports an error.
+ */
+async function lint(options: { path: string; package?: string; rule: string[] }): Promise<void> {
+  try {
+    const severities = parseLintSeverities(options.rule);
+    const config = createConfig({
+      packageName: options.package ?? basename(resolve(options.path)),
+      packagePath: options.path,
+    });
+    const result = await new GoExtractor(config).extract();
+    const symbols = new GoTransformer(result, config).transform();
+    const issues = lintPackage(result.packageDoc, symbols, severities);
+
+    if (issues.length > 0) {
+      logger.error(formatLintIssues(issues));
+    }
+    const errors = issues.filter((issue) => issue.severity === "error").length;
+    if (errors > 0) {
+      logger.error(`❌ ${errors} error(s), ${issues.length - errors} warning(s)`);
+      process.exit(1);
+    }
+    logger.info(`✅ Linted ${symbols.length} symbols, ${issues.length} warning(s)`);
+  } catch (error) {
+    logger.error("❌ Lint failed", { error });
+    process.exit(1);
+  }
+}
+
+/**
+ * Run the extraction daemon until it is asked to shut down or interrupted.
+ */
+async function daemon(options: { socket: string }): Promise<void> {
+  const server = new ExtractionDaemon();
+  try {
+    await server.listen(options.socket);
+  } catch (error) {
+    logger.error("❌ Failed to start the daemon", { error });
+    process.exit(1);
+  }
+
+  for (const signal of ["SIGINT", "SIGTERM"] as const) {
+    process.once(signal, () => server.close().then(() => process.exit(0)));
+  }
+  logger.info(`✅ Listening on ${options.socket}`);
+}
+
+/**
+ * Answer hover requests on stdin until it closes. The output file is re-read
+ * whenever it changes, so answers follow the latest extraction.
+ */
+async function hover(options: { input: string; path?: string }): Promise<void> {
+  await serveHover(process.stdin, process.stdout, watchOutputFile(options.input, options.path));
+}
+
+/**
+ * Serve symbol timelines over HTTP until interrupted. The store is re-read per
+ * request, so newly extracted versions show up without a restart.
+ */
+function history(options: { store: string; port: string }): void {
+  const server = createHistoryServer(() => loadVersionStore(options.store));
+  server.on("error", (error) => {
+    logger.error("❌ Failed to start the history server", { error });
+    process.exit(1);
+  });
+  server.listen(parseInt(options.port, 10), () => {
+    logger.info(`✅ Serving symbol history on http://localhost:${options.port}`);
+  });
+}
+
+/**
+ * Serve extractions of modules over HTTP, and over gRPC with --grpc-port,
+ * until interrupted. Modules are downloaded through the Go proxy and share
+ * one warm parse cache.
+ */
+function serve(options: {
+  port: string;
+  cacheSize: string;
+  moduleCache?: string;
+  grpcPort?: string;
+}): void {
+  // Parsed files are dropped with the extractions of their module version
+  const parseCache = new ParseCache<ParsedFile>();
+  const engine = new ExtractionDaemon(parseCache);
+  const moduleDirs = new Map<string, string>();
+  const cache = new ExtractionCache(
+    async ({ module: path, version, profile }) => {
+      const module = await downloadModule({ path, version }, { cacheDir: options.moduleCache });
+      moduleDirs.set(`${module.path}@${module.version}`, module.dir);
+      const repo = githubRepo(module.path);
+      const output = await engine.extract({
+        config: {
+          packageName: moduleName(module.path),
+          packagePath: module.dir,
+          repo: repo ?? "",
+          sha: repo ? moduleRevision(module.path, module.version) : "",
+          moduleVersion: module.version,
+        },
+        profile,
+      });
+      logger.info(`✅ Extracted ${module.path}@${module.version}`, {
+        module: module.path,
+        version: module.version,
+        symbols: output.symbols.length,
+      });
+      return { module: module.path, version: module.version, output };
+    },
+    parseInt(options.cacheSize, 10),
+    {
+      resolveLatest: (path) => resolveModuleVersion({ path, version: "latest" }),
+      onEvict: ({ module, version }) => {
+        const dir = moduleDirs.get(`${module}@${version}`);
+        if (dir) parseCache.deleteDir(dir);
+      },
+    },
+  );
+  const server = createExtractionServer(cache);
+  server.on("error", (error) => {
+    logger.error("❌ Failed to start the extraction server", { error });
+    process.exit(1);
+  });
+  server.listen(parseInt(options.port, 10), () => {
+    logger.info(`✅ Serving extractions on http://localhost:${options.port}`);
+  });
+
+  if (options.grpcPort) {
+    const grpc = createGrpcServer(cache);
+    grpc.on("error", (error) => {
+      logger.error("❌ Failed to start the gRPC server", { error });
+      process.exit(1);
+    });
+    grpc.listen(parseInt(options.grpcPort, 10), () => {
+      logger.info(`✅ Serving gRPC on localhost:${options.grpcPort}`);
+    });
+  }
+}
+
+program.parseAsync();
@@ -0,0 +1,165 @@
+/**
+ * Code Ownership
+ *
+ * Resolves the owners of source files from a CODEOWNERS file or from an
+ * explicit path-to-owners mapping in the configuration.
+ */
+
+import { readFile, stat } from "fs/promises";
+import { dirname, join, relative, resolve } from "path";
+
+/**
+ * A single ownership rule.
+ */
+export interface OwnershipRule {
+  pattern: string;
+  owners: string[];
+}
+
+/**
+ * Locations searched for a CODEOWNERS file, relative to the repository root.
+ */
+const CODEOWNERS_LOCATIONS = [".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"];
+
+/**
+ * Parse the contents of a CODEOWNERS file.
+ */
+export function parseCodeowners(content: string): OwnershipRule[] {
+  const rules: OwnershipRule[] = [];
+
+  for (const rawLine of content.split("\n")) {
+    const line = rawLine.replace(/\s#.*$/, "").trim();
+    if (!line || line.startsWith("#")) continue;
+
+    const [pattern, ...owners] = line.split(/\s+/);
+    rules.push({ pattern, owners });
+  }
+
+  return rules;
+}
+
+/**
+ * Convert a CODEOWNERS (gitignore-style) pattern to a regular expression
+ * matched against repository-relative paths.
+ */
+export function ownershipPatternToRegExp(pattern: string): RegExp {
+  const anchored = pattern.startsWith("/") || pattern.replace(/\/$/, "").includes("/");
+  const directory = pattern.endsWith("/");
+  const body = pattern.replace(/^\//, "").replace(/\/$/, "");
+
+  let regex = "";
+  for (let i = 0; i < body.length; i++) {
+    const char = body[i];
+    if (char === "*" && body[i + 1] === "*") {
+      regex += ".*";
+      i++;
+      if (body[i + 1] === "/") i++;
+    } else if (char === "*") {
+      regex += "[^/]*";
+    } else if (char === "?") {
+      regex += "[^/]";
+    } else {
+      regex += char.replace(/[.+^$()|[\]\\{}]/g, "\\$&");
+    }
+  }
+
+  const prefix = anchored ? "^" : "^(?:.*/)?";
+  // A pattern matches the path itself or, as a directory, anything below it
+  const suffix = directory ? "/.*$" : "(?:/.*)?$";
+  return new RegExp(prefix + regex + suffix);
+}
+
+/**
+ * Resolves owners for repository-relative paths. The last matching rule wins.
+ */
+export class OwnershipResolver {
+  private rules: Array<OwnershipRule & { regex: RegExp }>;
+
+  constructor(rules: OwnershipRule[]) {
+    this.rules = rules.map((rule) => ({ ...rule, regex: ownershipPatternToRegExp(rule.pattern) }));
+  }
+
+  /**
+   * Get the owners of a repository-relative path.
+   */
+  ownersOf(path: string): string[] {
+    const normalized = path.replace(/\\/g, "/").replace(/^\.\//, "");
+    for (let i = this.rules.length - 1; i >= 0; i--) {
+      if (this.rules[i].regex.test(normalized)) {
+        return this.rules[i].owners;
+      }
+    }
+    return [];
+  }
+}
+
+/**
+ * Find the repository root containing a directory by looking for `.git`.
+ * Falls back to the directory itself.
+ */
+export async function findRepoRoot(dir: string): Promise<string> {
+  let current = resolve(dir);
+  for (;;) {
+    if (await exists(join(current, ".git"))) {
+      return current;
+    }
+    const parent = dirname(current);
+    if (parent === current) {
+      return resolve(dir);
+    }
+    current = parent;
+  }
+}
+
+/**
+ * Load ownership rules for a package.
+ *
+ * Explicit `owners` mappings take precedence; their patterns are relative to
+ * the package path. Otherwise the owners file is used, which must be
+ * readable, or the repository's CODEOWNERS file if there is one. Returns the
+ * resolver and the prefix that turns package-relative paths into paths
+ * understood by the rules.
+ */
+export async function loadOwnership(
+  packagePath: string,
+  options: { owners?: Record<string, string[]>; ownersFile?: string },
+): Promise<{ resolver: OwnershipResolver; pathPrefix: string } | undefined> {
+  if (options.owners && Object.keys(options.owners).length > 0) {
+    const rules = Object.entries(options.owners).map(([pattern, owners]) => ({ pattern, owners }));
+    return { resolver: new OwnershipResolver(rules), pathPrefix: "" };
+  }
+
+  const repoRoot = await findRepoRoot(packagePath);
+  const pathPrefix = relative(repoRoot, resolve(packagePath)).replace(/\\/g, "/");
+  if (options.ownersFile) {
+    let content: string;
+    try {
+      content = await readFile(resolve(options.ownersFile), "utf-8");
+    } catch (error) {
+      throw new Error(`Can't read owners file ${options.ownersFile}: ${(error as Error).message}`, {
+        cause: error,
+      });
+    }
+    return { resolver: new OwnershipResolver(parseCodeowners(content)), pathPrefix };
+  }
+
+  for (const location of CODEOWNERS_LOCATIONS) {
+    try {
+      const content = await readFile(join(repoRoot, location), "utf-8");
+      return { resolver: new OwnershipResolver(parseCodeowners(content)), pathPrefix };
+    } catch {
+      // Try the next location
+    }
+  }
+
+  return undefined;
+}
+
+async function exists(path: string): Promise<boolean> {
+  try {
+    await stat(path);
+    return true;
+  } catch {
+    return false;
+  }
+}
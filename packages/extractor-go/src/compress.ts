@@ -0,0 +1,113 @@
+/**
+ * Compressed Artifacts
+ *
+ * Writes output artifacts gzip- or zstd-compressed, since extractions of
+ * large modules run to hundreds of MB of JSON, and reads them back
+ * transparently: compressed files are recognized by their magic bytes, so
+ * readers don't depend on the `.gz` or `.zst` extension.
+ */
+
+import { createReadStream, createWriteStream } from "fs";
+import { unlink } from "fs/promises";
+import { pipeline } from "stream/promises";
+import {
+  createGzip,
+  createZstdCompress,
+  gunzipSync,
+  gzipSync,
+  zstdCompressSync,
+  zstdDecompressSync,
+} from "zlib";
+
+/**
+ * A compression of output artifacts.
+ */
+export type Compression = "gzip" | "zstd";
+
+/**
+ * All compressions.
+ */
+export const compressions: Compression[] = ["gzip", "zstd"];
+
+/**
+ * File extension of each compression.
+ */
+export const compressionExtensions: Record<Compression, string> = {
+  gzip: ".gz",
+  zstd: ".zst",
+};
+
+/**
+ * Magic bytes a file of each compression starts with.
+ */
+const MAGIC: Record<Compression, number[]> = {
+  gzip: [0x1f, 0x8b],
+  zstd: [0x28, 0xb5, 0x2f, 0xfd],
+};
+
+/**
+ * Path of a compressed artifact: the path with the compression's extension,
+ * unless it already has it.
+ */
+export function compressedPath(file: string, compression: Compression): string {
+  const extension = compressionExtensions[compression];
+  return file.endsWith(extension) ? file : `${file}${extension}`;
+}
+
+/**
+ * Path of an artifact without its compression extension.
+ */
+export function uncompressedPath(file: string): string {
+  for (const extension of Object.values(compressionExtensions)) {
+    if (file.endsWith(extension)) return file.slice(0, -extension.length);
+  }
+  return file;
+}
+
+/**
+ * Compress an artifact's contents.
+ */
+export function compress(content: string | Buffer, compression: Compression): Buffer {
+  return compression === "gzip" ? gzipSync(content) : zstdCompressSync(content);
+}
+
+/**
+ * The compression of an artifact's contents, from its magic bytes.
+ */
+export function detectCompression(content: Buffer): Compression | undefined {
+  return compressions.find((compression) =>
+    MAGIC[compression].every((byte, i) => content[i] === byte),
+  );
+}
+
+/**
+ * Decompress an artifact's contents; uncompressed contents are returned as
+ * they are.
+ */
+export function decompress(content: Buffer): Buffer {
+  switch (detectCompression(content)) {
+    case "gzip":
+      return gunzipSync(content);
+    case "zstd":
+      return zstdDecompressSync(content);
+    default:
+      return content;
+  }
+}
+
+/**
+ * Compress a file written uncompressed to `target`, streaming it, and
+ * remove the original.
+ */
+export async function compressFile(
+  file: string,
+  target: string,
+  compression: Compression,
+): Promise<void> {
+  await pipeline(
+    createReadStream(file),
+    compression === "gzip" ? createGzip() : createZstdCompress(),
+    createWriteStream(target),
+  );
+  await unlink(file);
+}
@@ -0,0 +1,229 @@
+/**
+ * Configuration File
+ *
+ * Reads `langchain-references.yaml`, so large repositories keep their
+ * extraction settings in one reviewed file instead of ever-growing flag
+ * lists: the package and its source, file and symbol filters, the output
+ * targets, URL templates, and category and visibility mappings. Flags given
+ * on the command line take precedence over the file. Paths in the file are
+ * relative to it.
+ *
+ * ```yaml
+ * package: langsmith
+ * path: ./src
+ * repo: langchain-ai/langsmith-go
+ * packages: ["./..."]
+ * exclude: ["*.pb.go", "zz_generated*"]
+ * symbols:
+ *   exclude: ["Mock*", "Client.Debug"]
+ * output:
+ *   path: ./out/symbols.json
+ *   toc: ./out/toc.json
+ * urls:
+ *   symbol: /go/langsmith/{symbol}
+ * categories:
+ *   "Trace*": tracing
+ * plugins: ["./tools/stability.go"]
+ * ```
+ */
+
+import { existsSync } from "fs";
+import { readFile } from "fs/promises";
+import { dirname, isAbsolute, join } from "path";
+import { parseYaml } from "./yaml.js";
+import { outputFormats, type OutputFormat } from "./output.js";
+import { extractionProfiles, type ExtractionProfile } from "./profile.js";
+import { visibilityTiers, type VisibilityTier } from "./config.js";
+
+/**
+ * Name of the configuration file looked up in the working directory.
+ */
+export const configFileName = "langchain-references.yaml";
+
+/**
+ * Settings of a configuration file, with paths resolved against its
+ * directory.
+ */
+export interface ConfigFile {
+  package?: string;
+  /** Path to the Go source directory */
+  path?: string;
+  repo?: string;
+  sha?: string;
+  /** Go package patterns, like `./...`, replacing `include` */
+  packages?: string[];
+  /** Source file patterns to include, replacing the default `**\/*.go` */
+  include?: string[];
+  /**
+   * Source file globs to exclude, besides the default exclusions; globs
+   * without a slash, like `*.pb.go`, match file names in any directory
+   */
+  exclude?: string[];
+  symbols?: {
+    /** Name patterns of the top-level symbols to extract */
+    include?: string[];
+    /** Name patterns of the symbols and members to leave out */
+    exclude?: string[];
+  };
+  output?: {
+    path?: string;
+    format?: OutputFormat;
+    profile?: ExtractionProfile;
+    /** Navigation tree file */
+    toc?: string;
+    /** Search records file */
+    searchIndex?: string;
+    /** Directory of Mermaid diagrams */
+    diagrams?: string;
+  };
+  urls?: {
+    /** URL template of "report a doc issue" links */
+    feedback?: string;
+    /** URL template of links to symbols in rendered docs */
+    symbol?: string;
+  };
+  /** Categories keyed by symbol name patterns */
+  categories?: Record<string, string>;
+  /** Visibility tiers keyed by symbol name patterns */
+  tiers?: Record<string, VisibilityTier>;
+  /** Visibility tiers to include in the output */
+  visibility?: VisibilityTier[];
+  /** Transform plugins to run over the symbols before output */
+  plugins?: string[];
+}
+
+/**
+ * The configuration file to use: the given one, or `langchain-references.yaml`
+ * in the working directory if there is one.
+ */
+export function findConfigFile(file?: string): string | undefined {
+  if (file) return file;
+  return existsSync(configFileName) ? configFileName : undefined;
+}
+
+/**
+ * Read and validate a configuration file.
+ */
+export async function loadConfigFile(file: string): Promise<ConfigFile> {
+  let document: unknown;
+  try {
+    document = parseYaml(await readFile(file, "utf-8"));
+  } catch (error) {
+    throw new Error(`Invalid ${file}: ${(error as Error).message}`, { cause: error });
+  }
+  return parseConfigFile(document ?? {}, dirname(file), file);
+}
+
+/**
+ * Validate a parsed configuration file, resolving its paths against `dir`.
+ */
+export function parseConfigFile(
+  document: unknown,
+  dir: string,
+  file = configFileName,
+): ConfigFile {
+  const fail = (key: string, expected: string): never => {
+    throw new Error(`Invalid ${file}: ${key} must be ${expected}`);
+  };
+  const object = (value: unknown, key: string, keys: string[]) => {
+    if (value === undefined || value === null) return undefined;
+    if (typeof value !== "object" || value === null || Array.isArray(value)) {
+      return fail(key || "the file", "a mapping");
+    }
+    const unknown = Object.keys(value).find((name) => !keys.includes(name));
+    if (unknown) {
+      throw new Error(`Invalid ${file}: unknown key ${key ? `${key}.` : ""}${unknown}`);
+    }
+    return value as Record<string, unknown>;
+  };
+  const string = (value: unknown, key: string) => {
+    if (value === undefined || value === null) return undefined;
+    return typeof value === "string" ? value : fail(key, "a string");
+  };
+  const strings = (value: unknown, key: string) => {
+    if (value === undefined || value === null) return undefined;
+    const list = typeof value === "string" ? [value] : value;
+    return Array.isArray(list) && list.every((item) => typeof item === "string")
+      ? (list as string[])
+      : fail(key, "a list of strings");
+  };
+  const oneOf = <T extends string>(value: unknown, key: string, values: readonly T[]) => {
+    if (value === undefined || value === null) return undefined;
+    return values.includes(value as T) ? (value as T) : fail(key, `one of ${values.join(", ")}`);
+  };
+  const stringMap = (value: unknown, key: string) => {
+    const map = object(value, key, Object.keys(value ?? {}));
+    if (!map) return undefined;
+    for (const [name, entry] of Object.entries(map)) string(entry, `${key}.${name}`);
+    return map as Record<string, string>;
+  };
+  const path = (value: unknown, key: string) => {
+    const relative = string(value, key);
+    return relative === undefined || isAbsolute(relative) ? relative : join(dir, relative);
+  };
+
+  const root = object(document, "", [
+    "package",
+    "path",
+    "repo",
+    "sha",
+    "packages",
+    "include",
+    "exclude",
+    "symbols",
+    "output",
+    "urls",
+    "categories",
+    "tiers",
+    "visibility",
+    "plugins",
+  ])!;
+  const symbols = object(root.symbols, "symbols", ["include", "exclude"]);
+  const output = object(root.output, "output", [
+    "path",
+    "format",
+    "profile",
+    "toc",
+    "searchIndex",
+    "diagrams",
+  ]);
+  const urls = object(root.urls, "urls", ["feedback", "symbol"]);
+  const tiers = stringMap(root.tiers, "tiers");
+  for (const [pattern, tier] of Object.entries(tiers ?? {})) {
+    oneOf(tier, `tiers.${pattern}`, visibilityTiers);
+  }
+
+  return {
+    package: string(root.package, "package"),
+    path: path(root.path, "path"),
+    repo: string(root.repo, "repo"),
+    sha: string(root.sha, "sha"),
+    packages: strings(root.packages, "packages"),
+    include: strings(root.include, "include"),
+    exclude: strings(root.exclude, "exclude"),
+    symbols: symbols && {
+      include: strings(symbols.include, "symbols.include"),
+      exclude: strings(symbols.exclude, "symbols.exclude"),
+    },
+    output: output && {
+      path: path(output.path, "output.path"),
+      format: oneOf(output.format, "output.format", outputFormats),
+      profile: oneOf(output.profile, "output.profile", extractionProfiles),
+      toc: path(output.toc, "output.toc"),
+      searchIndex: path(output.searchIndex, "output.searchIndex"),
+      diagrams: path(output.diagrams, "output.diagrams"),
+    },
+    urls: urls && {
+      feedback: string(urls.feedback, "urls.feedback"),
+      symbol: string(urls.symbol, "urls.symbol"),
+    },
+    categories: stringMap(root.categories, "categories"),
+    tiers: tiers as Record<string, VisibilityTier> | undefined,
+    visibility: strings(root.visibility, "visibility")?.map((tier) =>
+      oneOf(tier, "visibility", visibilityTiers)!,
+    ),
+    plugins: strings(root.plugins, "plugins")?.map((plugin) =>
+      isAbsolute(plugin) ? plugin : join(dir, plugin),
+    ),
+  };
+}
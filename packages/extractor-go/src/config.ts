@@ -4,6 +4,72 @@
  * Defines the configuration options for Go API extraction.
  */
 
+import type { DocLimits } from "./truncate.js";
+import type { LocaleBundle } from "./labels.js";
+import { knownGoarch, knownGoos } from "./build-constraints.js";
+
+/**
+ * Documentation visibility tier of a symbol.
+ */
+export type VisibilityTier = "public" | "partner" | "internal";
+
+/**
+ * All visibility tiers, from most to least widely published.
+ */
+export const visibilityTiers: VisibilityTier[] = ["public", "partner", "internal"];
+
+/**
+ * How to treat third-party packages in `vendor/` trees.
+ */
+export type VendorPolicy = "skip" | "dependencies";
+
+/**
+ * All vendor policies.
+ */
+export const vendorPolicies: VendorPolicy[] = ["skip", "dependencies"];
+
+/**
+ * How to treat files marked `// Code generated ... DO NOT EDIT.`
+ */
+export type GeneratedPolicy = "mark" | "exclude";
+
+/**
+ * All generated file policies.
+ */
+export const generatedPolicies: GeneratedPolicy[] = ["mark", "exclude"];
+
+/**
+ * How to use interface-conformance assertions such as
+ * `var _ Handler = (*handler)(nil)`.
+ */
+export type AssertionPolicy = "ignore" | "merge" | "only";
+
+/**
+ * All assertion policies.
+ */
+export const assertionPolicies: AssertionPolicy[] = ["ignore", "merge", "only"];
+
+/**
+ * How to treat symbols from `internal/...` packages.
+ */
+export type InternalPackagePolicy = "exclude" | "include" | "reexposed";
+
+/**
+ * All internal package policies.
+ */
+export const internalPackagePolicies: InternalPackagePolicy[] = ["exclude", "include", "reexposed"];
+
+/**
+ * What to do with examples that refer to APIs the package doesn't declare:
+ * don't verify examples, print warnings, or fail the extraction.
+ */
+export type ExampleVerification = "off" | "warn" | "error";
+
+/**
+ * All example verification modes.
+ */
+export const exampleVerifications: ExampleVerification[] = ["off", "warn", "error"];
+
 /**
  * Configuration for Go extraction.
  */
@@ -31,8 +97,204 @@ export interface GoExtractorConfig {
 
   /** Source file patterns to exclude */
   excludePatterns: string[];
+
+  /** Path to a CODEOWNERS file (defaults to the repository's CODEOWNERS) */
+  ownersFile?: string;
+
+  /** Owners keyed by CODEOWNERS-style patterns relative to the package path */
+  owners?: Record<string, string[]>;
+
+  /**
+   * URL template for "report a doc issue" links. Supports the placeholders
+   * {repo}, {sha}, {file}, {line}, {symbol}, {owners}, {title}, and {body}.
+   */
+  feedbackUrlTemplate?: string;
+
+  /**
+   * Extract byte-identical packages (e.g. forks) only once. Vendored copies
+   * are only seen with `vendoredPackages: "dependencies"` (default: false)
+   */
+  dedupePackages?: boolean;
+
+  /**
+   * Visibility tiers keyed by symbol name patterns (e.g. "Client.Debug" or
+   * "Experimental*"). A `Visibility:` line in a doc comment takes precedence.
+   */
+  tiers?: Record<string, VisibilityTier>;
+
+  /** Visibility tiers to include in the output (default: public only) */
+  includeTiers?: VisibilityTier[];
+
+  /**
+   * Name patterns (e.g. "Client" or "Chat*") of the top-level symbols to
+   * extract; a type's methods and fields go with it (default: all)
+   */
+  includeSymbols?: string[];
+
+  /**
+   * Name patterns of the symbols and members to leave out, e.g. "Mock*" or
+   * "Client.Debug". Leaving out a type leaves out its methods.
+   */
+  excludeSymbols?: string[];
+
+  /**
+   * Categories keyed by symbol name patterns (e.g. "Retriever*"). A
+   * `@category` tag or `Category:` line takes precedence.
+   */
+  categories?: Record<string, string>;
+
+  /** Version of the module, e.g. from its git tag (default: 0.0.0) */
+  moduleVersion?: string;
+
+  /** Link Foo/FooContext function pairs to each other (default: true) */
+  pairContextVariants?: boolean;
+
+  /** Size limits for doc comments, by symbol kind (default: `defaultDocLimits`) */
+  docLimits?: DocLimits;
+
+  /**
+   * How to treat symbols from `internal/...` packages: drop them, include them
+   * flagged as internal, or include only those re-exposed by public APIs
+   * (default: "include")
+   */
+  internalPackages?: InternalPackagePolicy;
+
+  /**
+   * How to treat packages in `vendor/` trees: skip them even when the exclude
+   * patterns don't, or extract them into the `dependencies` namespace, which
+   * also lifts the default `**\/vendor/**` exclusion (default: "skip")
+   */
+  vendoredPackages?: VendorPolicy;
+
+  /**
+   * How to treat generated files (protobuf, stringer, mocks): mark their
+   * symbols `generated: true`, or leave them out (default: "mark")
+   */
+  generatedFiles?: GeneratedPolicy;
+
+  /**
+   * How to use compile-time interface assertions: ignore them, merge the
+   * implementations they declare with those inferred from method sets, or
+   * list only asserted implementations (default: "merge")
+   */
+  implementationAssertions?: AssertionPolicy;
+
+  /**
+   * Document symbols exported only from in-package `_test.go` files (the
+   * export_test.go pattern) as testing helpers, which also lifts the default
+   * `**\/*_test.go` exclusion (default: false)
+   */
+  testHelpers?: boolean;
+
+  /**
+   * Attach the `ExampleXxx` functions of `_test.go` files to the symbols they
+   * document, reading test files despite the default `**\/*_test.go`
+   * exclusion (default: true)
+   */
+  examples?: boolean;
+
+  /**
+   * Collect the `BenchmarkXxx` and `FuzzXxx` functions of `_test.go` files
+   * and link them to the symbols they exercise (default: false)
+   */
+  testFunctions?: boolean;
+
+  /**
+   * Markers of the go/doc notes (`// BUG(uid): ...`) to collect into the
+   * package notes and remove from doc comments (default: ["BUG"])
+   */
+  noteMarkers?: string[];
+
+  /**
+   * Check Example functions and doc comment code blocks starting with
+   * `// verify` for references to APIs the package doesn't declare
+   * (default: "off")
+   */
+  verifyExamples?: ExampleVerification;
+
+  /**
+   * Names of the annotation tags (`@since v0.3.0`, `@experimental`) to split
+   * off doc comments into structured metadata (default: ["since",
+   * "experimental", "category"])
+   */
+  docTags?: string[];
+
+  /**
+   * Infer the typical call order of builder methods from the method chains in
+   * doc comment code samples (default: false)
+   */
+  inferChainOrder?: boolean;
+
+  /**
+   * Render each symbol's parsed doc comment as CommonMark into
+   * `docs.markdown`, for consumers that don't read the structured blocks
+   * (default: false)
+   */
+  renderMarkdown?: boolean;
+
+  /** Render each symbol's parsed doc comment as HTML into `docs.html` (default: false) */
+  renderHtml?: boolean;
+
+  /**
+   * URL template of doc links to symbols of the package in rendered Markdown
+   * and HTML, e.g. "/go/{package}/{symbol}". Supports the placeholders
+   * {package}, {symbol} (the qualified name), and {id} (default: the
+   * symbol's canonical URL).
+   */
+  symbolUrlTemplate?: string;
+
+  /**
+   * Directory of Markdown files with supplementary symbol docs, relative to
+   * the package path, e.g. "docs". A file is merged into the docs of the
+   * symbol its name points to: `Client.md`, `Client.Get.md`, or the symbol ID
+   */
+  sidecarDocs?: string;
+
+  /** Translation of the structural labels and notes (default: English) */
+  localeBundle?: LocaleBundle;
+
+  /** Reference the locale bundle at this URL in the output instead of embedding it */
+  localeBundleUrl?: string;
+
+  /**
+   * Parse files on this many worker threads; results are merged in file
+   * order, so the output doesn't depend on it (default: 1, parsing in this
+   * thread)
+   */
+  workers?: number;
+
+  /**
+   * Skip packages whose files take longer than this to parse in total, in
+   * milliseconds, reporting them as timed out; parses on a worker
+   */
+  packageTimeoutMs?: number;
+
+  /**
+   * Heap budget of parsing in megabytes, shared by the workers; packages
+   * with a file that exceeds it are skipped and reported
+   */
+  maxMemoryMb?: number;
+
+  /**
+   * Extra build tags, as with `go build -tags`. With this, `goos`, or
+   * `goarch`, only the files whose build constraints hold are extracted
+   * (default: every file)
+   */
+  buildTags?: string[];
+
+  /** GOOS to select files for (default: the host's, when selecting) */
+  goos?: string;
+
+  /** GOARCH to select files for (default: the host's, when selecting) */
+  goarch?: string;
 }
 
+/**
+ * Default feedback URL template, opening a pre-filled GitHub issue.
+ */
+export const defaultFeedbackUrlTemplate =
+  "https://github.com/{repo}/issues/new?title={title}&body={body}&labels=documentation";
+
 /**
  * Default configuration values.
  */
@@ -69,4 +331,54 @@ export function validateConfig(config: GoExtractorConfig): void {
   if (!config.packagePath) {
     throw new Error("packagePath is required");
   }
+  const tiers = [...(config.includeTiers ?? []), ...Object.values(config.tiers ?? {})];
+  const unknown = tiers.find((tier) => !visibilityTiers.includes(tier));
+  if (unknown) {
+    throw new Error(`Unknown visibility tier: ${unknown}`);
+  }
+  if (config.internalPackages && !internalPackagePolicies.includes(config.internalPackages)) {
+    throw new Error(`Unknown internal package policy: ${config.internalPackages}`);
+  }
+  if (config.vendoredPackages && !vendorPolicies.includes(config.vendoredPackages)) {
+    throw new Error(`Unknown vendor policy: ${config.vendoredPackages}`);
+  }
+  if (config.generatedFiles && !generatedPolicies.includes(config.generatedFiles)) {
+    throw new Error(`Unknown generated file policy: ${config.generatedFiles}`);
+  }
+  if (
+    config.implementationAssertions &&
+    !assertionPolicies.includes(config.implementationAssertions)
+  ) {
+    throw new Error(`Unknown assertion policy: ${config.implementationAssertions}`);
+  }
+  if (config.verifyExamples && !exampleVerifications.includes(config.verifyExamples)) {
+    throw new Error(`Unknown example verification: ${config.verifyExamples}`);
+  }
+  const marker = config.noteMarkers?.find((m) => !/^[A-Z][A-Z]+$/.test(m));
+  if (marker !== undefined) {
+    throw new Error(`Invalid note marker: ${marker}`);
+  }
+  const tag = config.docTags?.find((t) => !/^[a-z][\w-]*$/.test(t));
+  if (tag !== undefined) {
+    throw new Error(`Invalid doc tag: ${tag}`);
+  }
+  const limits = config.docLimits
+    ? [config.docLimits.default, ...Object.values(config.docLimits.kinds ?? {})]
+    : [];
+  if (limits.some((limit) => !(limit.maxChars > 0) || !(limit.maxSections >= 0))) {
+    throw new Error("Doc limits need a positive maxChars and a non-negative maxSections");
+  }
+  if (config.goos && !knownGoos.includes(config.goos)) {
+    throw new Error(`Unknown GOOS: ${config.goos}`);
+  }
+  if (config.goarch && !knownGoarch.includes(config.goarch)) {
+    throw new Error(`Unknown GOARCH: ${config.goarch}`);
+  }
+  const buildTag = config.buildTags?.find((t) => !/^[\w.]+$/.test(t));
+  if (buildTag !== undefined) {
+    throw new Error(`Invalid build tag: ${buildTag}`);
+  }
+  if (config.localeBundleUrl && !config.localeBundle) {
+    throw new Error("localeBundleUrl requires a localeBundle");
+  }
 }
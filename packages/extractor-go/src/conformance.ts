@@ -0,0 +1,77 @@
+/**
+ * Conformance
+ *
+ * Extracts a Go module, the bundled `example` fixture package by default,
+ * and validates the output against the IR contract of the build pipeline,
+ * the consumer of `symbols.json`, so interop breaks surface in this package.
+ * The contract lives with the pipeline, which names package IDs by it, so
+ * the expectations can't drift from what it reads.
+ */
+
+import { stat } from "fs/promises";
+import path from "path";
+import url from "url";
+import { checkIRContract, type IRContractIssue } from "@langchain/build-pipeline/ir-contract";
+import { createConfig } from "./config.js";
+import { GoExtractor } from "./extractor.js";
+import { GoTransformer } from "./transformer.js";
+import { buildOutput, type ExtractorOutput } from "./output.js";
+
+/**
+ * A way in which an output breaks consumer expectations.
+ */
+export type ConformanceIssue = IRContractIssue;
+
+/**
+ * Result of a conformance run.
+ */
+export interface ConformanceReport {
+  output: ExtractorOutput;
+  issues: ConformanceIssue[];
+}
+
+/**
+ * Directory holding the canonical fixture package. It ships with the
+ * sources; installs without it need a module path.
+ */
+export const CONFORMANCE_FIXTURES_PATH = path.join(
+  path.dirname(url.fileURLToPath(import.meta.url)),
+  "__tests__",
+  "fixtures",
+);
+
+/**
+ * Extract a module, the canonical fixture package by default, with a fixed
+ * configuration and check the result.
+ */
+export async function runConformance(
+  packagePath = CONFORMANCE_FIXTURES_PATH,
+): Promise<ConformanceReport> {
+  try {
+    await stat(packagePath);
+  } catch {
+    throw new Error(
+      packagePath === CONFORMANCE_FIXTURES_PATH
+        ? `The bundled fixture package isn't installed (${packagePath}); pass a module path`
+        : `No such module path: ${packagePath}`,
+    );
+  }
+  const config = createConfig({
+    packageName: "conformance",
+    packagePath,
+    repo: "langchain-ai/conformance",
+    sha: "0000000",
+  });
+  const result = await new GoExtractor(config).extract();
+  const symbols = new GoTransformer(result, config).transform();
+  const output = buildOutput(result, config, symbols);
+
+  return { output, issues: checkConformance(output) };
+}
+
+/**
+ * Check an extractor output against the build pipeline's IR contract for Go.
+ */
+export function checkConformance(output: ExtractorOutput): ConformanceIssue[] {
+  return checkIRContract(output, "go");
+}
@@ -0,0 +1,550 @@
+/**
+ * Constant Expression Evaluation
+ *
+ * Folds Go constant expressions such as `30 * time.Second` or `1 << 10` to
+ * their values and types, following Go's rules for untyped constants.
+ */
+
+/**
+ * The evaluated value of a constant expression.
+ */
+export interface GoConstValue {
+  /** Exact value as Go would print it with %v (strings are quoted) */
+  value: string;
+  /** Constant type, e.g. "time.Duration" or "untyped int" */
+  type: string;
+  /** Underlying type, when the type is a known defined type */
+  underlyingType?: string;
+  /** Human-friendly rendering, e.g. "30s" for durations */
+  display?: string;
+}
+
+/**
+ * An evaluated constant during folding.
+ */
+type Constant =
+  | { kind: "int"; value: bigint; type: string }
+  | { kind: "float"; value: number; type: string }
+  | { kind: "string"; value: string; type: string }
+  | { kind: "bool"; value: boolean; type: string };
+
+/**
+ * Bit sizes of the integer types. int, uint and uintptr are 64-bit, as on
+ * the platforms go/types sizes default to.
+ */
+const INTEGER_BITS = new Map([
+  ["int", 64],
+  ["int8", 8],
+  ["int16", 16],
+  ["int32", 32],
+  ["int64", 64],
+  ["uint", 64],
+  ["uint8", 8],
+  ["uint16", 16],
+  ["uint32", 32],
+  ["uint64", 64],
+  ["uintptr", 64],
+  ["byte", 8],
+  ["rune", 32],
+]);
+
+const FLOAT_TYPES = new Set(["float32", "float64"]);
+
+/**
+ * Builtin functions, which look like conversions but are not.
+ */
+const BUILTIN_FUNCTIONS = new Set(["len", "cap", "min", "max", "real", "imag", "complex"]);
+
+/**
+ * Underlying types of well-known defined types.
+ */
+const UNDERLYING_TYPES: Record<string, string> = {
+  "time.Duration": "int64",
+  "os.FileMode": "uint32",
+  "fs.FileMode": "uint32",
+  byte: "uint8",
+  rune: "int32",
+};
+
+const DURATION = "time.Duration";
+
+/**
+ * Largest constant shift count go/types accepts.
+ */
+const MAX_SHIFT = 1074n;
+
+/**
+ * Constants exported by standard library packages.
+ */
+const KNOWN_CONSTANTS: Record<string, Constant> = {
+  "time.Nanosecond": { kind: "int", value: 1n, type: DURATION },
+  "time.Microsecond": { kind: "int", value: 1_000n, type: DURATION },
+  "time.Millisecond": { kind: "int", value: 1_000_000n, type: DURATION },
+  "time.Second": { kind: "int", value: 1_000_000_000n, type: DURATION },
+  "time.Minute": { kind: "int", value: 60_000_000_000n, type: DURATION },
+  "time.Hour": { kind: "int", value: 3_600_000_000_000n, type: DURATION },
+  "math.MaxInt8": { kind: "int", value: 127n, type: "untyped int" },
+  "math.MinInt8": { kind: "int", value: -128n, type: "untyped int" },
+  "math.MaxInt16": { kind: "int", value: 32767n, type: "untyped int" },
+  "math.MinInt16": { kind: "int", value: -32768n, type: "untyped int" },
+  "math.MaxInt32": { kind: "int", value: 2147483647n, type: "untyped int" },
+  "math.MinInt32": { kind: "int", value: -2147483648n, type: "untyped int" },
+  "math.MaxInt64": { kind: "int", value: (1n << 63n) - 1n, type: "untyped int" },
+  "math.MinInt64": { kind: "int", value: -(1n << 63n), type: "untyped int" },
+  "math.MaxInt": { kind: "int", value: (1n << 63n) - 1n, type: "untyped int" },
+  "math.MinInt": { kind: "int", value: -(1n << 63n), type: "untyped int" },
+  "math.MaxUint8": { kind: "int", value: 255n, type: "untyped int" },
+  "math.MaxUint16": { kind: "int", value: 65535n, type: "untyped int" },
+  "math.MaxUint32": { kind: "int", value: (1n << 32n) - 1n, type: "untyped int" },
+  "math.MaxUint64": { kind: "int", value: (1n << 64n) - 1n, type: "untyped int" },
+  "math.MaxUint": { kind: "int", value: (1n << 64n) - 1n, type: "untyped int" },
+  "math.Pi": { kind: "float", value: Math.PI, type: "untyped float" },
+  "math.E": { kind: "float", value: Math.E, type: "untyped float" },
+  "math.Sqrt2": { kind: "float", value: Math.SQRT2, type: "untyped float" },
+  "math.Ln2": { kind: "float", value: Math.LN2, type: "untyped float" },
+  "io.SeekStart": { kind: "int", value: 0n, type: "untyped int" },
+  "io.SeekCurrent": { kind: "int", value: 1n, type: "untyped int" },
+  "io.SeekEnd": { kind: "int", value: 2n, type: "untyped int" },
+};
+
+/**
+ * Binary operator precedence, as in the Go spec.
+ */
+const PRECEDENCE: Record<string, number> = {
+  "||": 1,
+  "&&": 2,
+  "==": 3,
+  "!=": 3,
+  "<": 3,
+  "<=": 3,
+  ">": 3,
+  ">=": 3,
+  "+": 4,
+  "-": 4,
+  "|": 4,
+  "^": 4,
+  "*": 5,
+  "/": 5,
+  "%": 5,
+  "<<": 5,
+  ">>": 5,
+  "&": 5,
+  "&^": 5,
+};
+
+const TOKEN_PATTERN =
+  /\s*(?:(0[xX][\da-fA-F_]+|0[bB][01_]+|0[oO][0-7_]+|(?:\d[\d_]*\.?[\d_]*|\.\d[\d_]*)(?:[eE][+-]?\d+)?)|("(?:[^"\\]|\\.)*"|`[^`]*`|'(?:[^'\\]|\\.)+')|([A-Za-z_]\w*(?:\.[A-Za-z_]\w*)?)|(&\^|<<|>>|<=|>=|==|!=|&&|\|\||[-+*/%&|^!<>()]))/y;
+
+/**
+ * Previously evaluated constants an expression may refer to, by name, or by
+ * `pkg.Name` for constants of other packages.
+ */
+export type ConstScope = Pick<ReadonlyMap<string, GoConstValue>, "get">;
+
+/**
+ * Evaluate a constant expression.
+ *
+ * `scope` resolves the constants the expression refers to, besides the
+ * standard library's well-known ones. Returns undefined when the expression
+ * is not a constant this evaluator understands.
+ */
+export function evaluateConstExpr(
+  expr: string,
+  scope: ConstScope = new Map(),
+): GoConstValue | undefined {
+  try {
+    const parser = new ConstParser(tokenize(expr), scope);
+    const result = parser.parseExpression(0);
+    if (!parser.done()) return undefined;
+    return describe(result);
+  } catch {
+    return undefined;
+  }
+}
+
+/**
+ * Convert an evaluated constant to its type, as in `const X T = expr`.
+ */
+export function convertConstValue(value: GoConstValue, type: string): GoConstValue | undefined {
+  try {
+    return describe(convert(fromValue(value), type));
+  } catch {
+    return undefined;
+  }
+}
+
+/**
+ * Format a duration in nanoseconds the way Go's time.Duration.String does.
+ */
+export function formatDuration(nanoseconds: bigint): string {
+  if (nanoseconds === 0n) return "0s";
+
+  const sign = nanoseconds < 0n ? "-" : "";
+  const ns = nanoseconds < 0n ? -nanoseconds : nanoseconds;
+
+  if (ns < 1_000n) return `${sign}${ns}ns`;
+  if (ns < 1_000_000n) return `${sign}${fraction(ns, 1_000n)}µs`;
+  if (ns < 1_000_000_000n) return `${sign}${fraction(ns, 1_000_000n)}ms`;
+
+  const hours = ns / 3_600_000_000_000n;
+  const minutes = (ns / 60_000_000_000n) % 60n;
+  const seconds = fraction(ns % 60_000_000_000n, 1_000_000_000n);
+
+  if (hours > 0n) return `${sign}${hours}h${minutes}m${seconds}s`;
+  if (minutes > 0n) return `${sign}${minutes}m${seconds}s`;
+  return `${sign}${seconds}s`;
+}
+
+function fraction(value: bigint, unit: bigint): string {
+  const whole = value / unit;
+  const rest = value % unit;
+  if (rest === 0n) return whole.toString();
+
+  const digits = unit.toString().length - 1;
+  const decimals = rest.toString().padStart(digits, "0").replace(/0+$/, "");
+  return `${whole}.${decimals}`;
+}
+
+function tokenize(expr: string): string[] {
+  const tokens: string[] = [];
+  TOKEN_PATTERN.lastIndex = 0;
+  while (TOKEN_PATTERN.lastIndex < expr.length) {
+    if (/^\s*$/.test(expr.slice(TOKEN_PATTERN.lastIndex))) break;
+    const match = TOKEN_PATTERN.exec(expr);
+    if (!match) throw new Error(`Unexpected input in ${expr}`);
+    tokens.push(match[0].trim());
+  }
+  return tokens;
+}
+
+/**
+ * Precedence-climbing parser that folds as it parses.
+ */
+class ConstParser {
+  private tokens: string[];
+  private scope: ConstScope;
+  private position = 0;
+
+  constructor(tokens: string[], scope: ConstScope) {
+    this.tokens = tokens;
+    this.scope = scope;
+  }
+
+  done(): boolean {
+    return this.position === this.tokens.length;
+  }
+
+  parseExpression(minPrecedence: number): Constant {
+    let left = this.parseUnary();
+    for (;;) {
+      const op = this.tokens[this.position];
+      const precedence = PRECEDENCE[op];
+      if (precedence === undefined || precedence <= minPrecedence) return left;
+      this.position++;
+      const right = this.parseExpression(precedence);
+      left = binary(op, left, right);
+    }
+  }
+
+  private parseUnary(): Constant {
+    const token = this.tokens[this.position];
+    if (token === "-" || token === "+" || token === "^" || token === "!") {
+      this.position++;
+      return unary(token, this.parseUnary());
+    }
+    return this.parsePrimary();
+  }
+
+  private parsePrimary(): Constant {
+    const token = this.tokens[this.position++];
+    if (token === undefined) throw new Error("Unexpected end of expression");
+
+    if (token === "(") {
+      const inner = this.parseExpression(0);
+      this.expect(")");
+      return inner;
+    }
+
+    if (/^[\d.]/.test(token)) return parseNumber(token);
+    if (token.startsWith('"') || token.startsWith("`")) {
+      const value = token.startsWith("`") ? token.slice(1, -1) : (JSON.parse(token) as string);
+      return { kind: "string", value, type: "untyped string" };
+    }
+    if (token.startsWith("'")) {
+      const char = JSON.parse(`"${token.slice(1, -1).replace(/"/g, '\\"')}"`) as string;
+      return { kind: "int", value: BigInt(char.codePointAt(0)!), type: "untyped rune" };
+    }
+
+    // Conversion, e.g. time.Duration(5) or int64(x)
+    if (this.tokens[this.position] === "(") {
+      this.position++;
+      const operand = this.parseExpression(0);
+      this.expect(")");
+      return convert(operand, token);
+    }
+
+    if (token === "true" || token === "false") {
+      return { kind: "bool", value: token === "true", type: "untyped bool" };
+    }
+    if (token in KNOWN_CONSTANTS) return KNOWN_CONSTANTS[token];
+
+    const local = this.scope.get(token);
+    if (local) return fromValue(local);
+
+    throw new Error(`Unknown identifier ${token}`);
+  }
+
+  private expect(token: string): void {
+    if (this.tokens[this.position++] !== token) {
+      throw new Error(`Expected ${token}`);
+    }
+  }
+}
+
+function parseNumber(token: string): Constant {
+  const text = token.replace(/_/g, "");
+  if (/^0[xXbBoO]/.test(text) || /^\d+$/.test(text)) {
+    // Go treats a leading 0 as octal
+    const normalized = /^0\d/.test(text) ? `0o${text.slice(1)}` : text;
+    return { kind: "int", value: BigInt(normalized), type: "untyped int" };
+  }
+  return { kind: "float", value: Number(text), type: "untyped float" };
+}
+
+function fromValue(value: GoConstValue): Constant {
+  if (value.value.startsWith('"')) {
+    return { kind: "string", value: JSON.parse(value.value) as string, type: value.type };
+  }
+  if (value.value === "true" || value.value === "false") {
+    return { kind: "bool", value: value.value === "true", type: value.type };
+  }
+  // Integral floats print without a fraction, so the type decides
+  if (/^-?\d+$/.test(value.value) && !isFloatType(value.type)) {
+    return { kind: "int", value: BigInt(value.value), type: value.type };
+  }
+  return { kind: "float", value: Number(value.value), type: value.type };
+}
+
+function isUntyped(constant: Constant): boolean {
+  return constant.type.startsWith("untyped ");
+}
+
+function isFloatType(type: string): boolean {
+  return type === "untyped float" || FLOAT_TYPES.has(UNDERLYING_TYPES[type] ?? type);
+}
+
+function isIntegerType(type: string): boolean {
+  return INTEGER_BITS.has(UNDERLYING_TYPES[type] ?? type);
+}
+
+/**
+ * Size and signedness of a typed integer constant's type, or undefined for
+ * untyped constants and defined types whose underlying type isn't known.
+ */
+function integerSize(type: string): { bits: bigint; signed: boolean } | undefined {
+  const underlying = UNDERLYING_TYPES[type] ?? type;
+  const bits = INTEGER_BITS.get(underlying);
+  if (bits === undefined) return undefined;
+  return { bits: BigInt(bits), signed: !underlying.startsWith("u") };
+}
+
+/**
+ * Reject typed integer constants that overflow their type, as go/types does.
+ */
+function representable(constant: Constant): Constant {
+  if (constant.kind !== "int") return constant;
+  const size = integerSize(constant.type);
+  if (!size) return constant;
+
+  const min = size.signed ? -(1n << (size.bits - 1n)) : 0n;
+  const max = size.signed ? (1n << (size.bits - 1n)) - 1n : (1n << size.bits) - 1n;
+  if (constant.value < min || constant.value > max) {
+    throw new Error(`Constant ${constant.value} overflows ${constant.type}`);
+  }
+  return constant;
+}
+
+function convert(constant: Constant, type: string): Constant {
+  if (type === "string") {
+    if (constant.kind === "string") return { ...constant, type };
+    if (constant.kind === "int") {
+      return { kind: "string", value: String.fromCodePoint(Number(constant.value)), type };
+    }
+    throw new Error(`Cannot convert to ${type}`);
+  }
+  if (isIntegerType(type)) {
+    if (constant.kind === "int") return representable({ ...constant, type });
+    if (constant.kind === "float" && Number.isInteger(constant.value)) {
+      return representable({ kind: "int", value: BigInt(constant.value), type });
+    }
+    throw new Error(`Cannot convert to ${type}`);
+  }
+  if (FLOAT_TYPES.has(type)) {
+    if (constant.kind === "int") return { kind: "float", value: Number(constant.value), type };
+    if (constant.kind === "float") return { ...constant, type };
+    throw new Error(`Cannot convert to ${type}`);
+  }
+  if (type === "bool" && constant.kind === "bool") {
+    return { ...constant, type };
+  }
+  if (BUILTIN_FUNCTIONS.has(type)) {
+    if (type === "len" && constant.kind === "string") {
+      return { kind: "int", value: BigInt(Buffer.byteLength(constant.value)), type: "int" };
+    }
+    throw new Error(`Cannot evaluate ${type}`);
+  }
+  // A defined type whose underlying type is unknown keeps the value as is
+  return { ...constant, type };
+}
+
+/**
+ * Type of a binary operation's result, following the untyped constant rules.
+ */
+function resultType(left: Constant, right: Constant, kind: Constant["kind"]): string {
+  if (!isUntyped(left)) return left.type;
+  if (!isUntyped(right)) return right.type;
+  if (kind === "float") return "untyped float";
+  if (left.type === "untyped rune" || right.type === "untyped rune") return "untyped rune";
+  return left.type;
+}
+
+function binary(op: string, left: Constant, right: Constant): Constant {
+  if (op === "&&" || op === "||") {
+    if (left.kind !== "bool" || right.kind !== "bool") throw new Error(`Invalid ${op}`);
+    const value = op === "&&" ? left.value && right.value : left.value || right.value;
+    return { kind: "bool", value, type: "untyped bool" };
+  }
+
+  if (PRECEDENCE[op] === 3) {
+    return { kind: "bool", value: compare(op, left, right), type: "untyped bool" };
+  }
+
+  if (op === "<<" || op === ">>") {
+    if (left.kind !== "int" || right.kind !== "int") throw new Error(`Invalid ${op}`);
+    if (right.value < 0n || right.value > MAX_SHIFT) {
+      throw new Error(`Invalid shift count ${right.value}`);
+    }
+    const value = op === "<<" ? left.value << right.value : left.value >> right.value;
+    return representable({ kind: "int", value, type: left.type });
+  }
+
+  if (left.kind === "string" && right.kind === "string" && op === "+") {
+    const type = resultType(left, right, "string");
+    return { kind: "string", value: left.value + right.value, type };
+  }
+
+  if (left.kind === "int" && right.kind === "int") {
+    const type = resultType(left, right, "int");
+    return representable({ kind: "int", value: integerOp(op, left.value, right.value), type });
+  }
+
+  const numeric = (c: Constant) => c.kind === "int" || c.kind === "float";
+  if (numeric(left) && numeric(right)) {
+    const value = floatOp(op, Number(left.value), Number(right.value));
+    const type = resultType(left, right, "float");
+    // Typed integer results, e.g. 1.5 * time.Second, stay integers
+    if (isIntegerType(type) && Number.isInteger(value)) {
+      return representable({ kind: "int", value: BigInt(value), type });
+    }
+    return { kind: "float", value, type };
+  }
+
+  throw new Error(`Invalid operands for ${op}`);
+}
+
+function integerOp(op: string, a: bigint, b: bigint): bigint {
+  switch (op) {
+    case "+":
+      return a + b;
+    case "-":
+      return a - b;
+    case "*":
+      return a * b;
+    case "/":
+      return a / b;
+    case "%":
+      return a % b;
+    case "&":
+      return a & b;
+    case "|":
+      return a | b;
+    case "^":
+      return a ^ b;
+    case "&^":
+      return a & ~b;
+    default:
+      throw new Error(`Unsupported operator ${op}`);
+  }
+}
+
+function floatOp(op: string, a: number, b: number): number {
+  switch (op) {
+    case "+":
+      return a + b;
+    case "-":
+      return a - b;
+    case "*":
+      return a * b;
+    case "/":
+      return a / b;
+    default:
+      throw new Error(`Unsupported operator ${op}`);
+  }
+}
+
+function compare(op: string, left: Constant, right: Constant): boolean {
+  const a = left.value;
+  const b = right.value;
+  switch (op) {
+    case "==":
+      return a == b;
+    case "!=":
+      return a != b;
+    case "<":
+      return a < b;
+    case "<=":
+      return a <= b;
+    case ">":
+      return a > b;
+    default:
+      return a >= b;
+  }
+}
+
+function unary(op: string, operand: Constant): Constant {
+  if (op === "!") {
+    if (operand.kind !== "bool") throw new Error("Invalid !");
+    return { ...operand, value: !operand.value };
+  }
+  if (operand.kind === "int") {
+    if (op === "-") return representable({ ...operand, value: -operand.value });
+    if (op === "^") {
+      // The complement of an unsigned value flips the type's bits; of a
+      // signed or untyped one, it is -x - 1
+      const size = integerSize(operand.type);
+      const value =
+        size && !size.signed ? operand.value ^ ((1n << size.bits) - 1n) : ~operand.value;
+      return { ...operand, value };
+    }
+    return operand;
+  }
+  if (operand.kind === "float" && op !== "^") {
+    return op === "-" ? { ...operand, value: -operand.value } : operand;
+  }
+  throw new Error(`Invalid ${op}`);
+}
+
+function describe(constant: Constant): GoConstValue {
+  const value =
+    constant.kind === "string" ? JSON.stringify(constant.value) : String(constant.value);
+  const result: GoConstValue = { value, type: constant.type };
+
+  if (constant.type in UNDERLYING_TYPES) {
+    result.underlyingType = UNDERLYING_TYPES[constant.type];
+  }
+  if (constant.type === DURATION && constant.kind === "int") {
+    result.display = formatDuration(constant.value);
+  }
+
+  return result;
+}
@@ -0,0 +1,175 @@
+/**
+ * Documentation Coverage
+ *
+ * Counts how many exported symbols and fields have doc comments, per Go
+ * package and per kind, so doc gaps can be tracked and reported in CI.
+ */
+
+import { posix } from "path";
+
+import type { GoSymbolRecord } from "./transformer.js";
+import { compareCanonical } from "./canonical.js";
+
+/**
+ * A kind of exported API counted for coverage.
+ *
+ * - `functions`: package-level functions
+ * - `types`: structs, interfaces, and type aliases
+ * - `methods`: methods of types
+ * - `fields`: exported fields of structs
+ * - `values`: constants and variables
+ */
+export type CoverageKind = "functions" | "types" | "methods" | "fields" | "values";
+
+export const coverageKinds: CoverageKind[] = ["functions", "types", "methods", "fields", "values"];
+
+/**
+ * Documented and total counts of exported API.
+ */
+export interface CoverageCount {
+  documented: number;
+  total: number;
+  /** Share of documented API in percent, with one decimal; 100 without any API */
+  percent: number;
+}
+
+/**
+ * Coverage of one Go package, by its directory relative to the package path.
+ */
+export interface PackageCoverage extends CoverageCount {
+  package: string;
+  kinds: Record<CoverageKind, CoverageCount>;
+  /** Qualified names of the undocumented symbols and fields */
+  undocumented: string[];
+}
+
+/**
+ * Coverage of a run: totals across packages, by kind, and per package.
+ */
+export interface DocCoverage extends CoverageCount {
+  kinds: Record<CoverageKind, CoverageCount>;
+  packages: PackageCoverage[];
+}
+
+/**
+ * Compute the doc coverage of the exported symbols of a run. Test helpers and
+ * vendored dependencies are not part of the package's API and don't count.
+ */
+export function buildCoverage(symbols: GoSymbolRecord[]): DocCoverage {
+  type Names = { documented: string[]; undocumented: string[] };
+  const packages = new Map<string, Record<CoverageKind, Names>>();
+  const tally = (pkg: string, kind: CoverageKind, name: string, documented: boolean) => {
+    if (!packages.has(pkg)) {
+      const kinds = coverageKinds.map((k) => [k, { documented: [], undocumented: [] }]);
+      packages.set(pkg, Object.fromEntries(kinds));
+    }
+    packages.get(pkg)![kind][documented ? "documented" : "undocumented"].push(name);
+  };
+
+  for (const symbol of symbols) {
+    if (symbol.tags.visibility !== "public" || symbol.testHelper || symbol.dependency) continue;
+    const kind = coverageKindOf(symbol);
+    if (!kind) continue;
+
+    const pkg = posix.dirname(symbol.source?.path ?? ".");
+    const { summary, description, deprecated } = symbol.docs;
+    const documented = Boolean(summary || description || deprecated);
+    tally(pkg, kind, symbol.qualifiedName, documented);
+    if (kind !== "types") continue;
+    for (const member of symbol.members ?? []) {
+      if (member.kind !== "property" || member.visibility !== "public") continue;
+      const name = `${symbol.qualifiedName}.${member.name}`;
+      tally(pkg, "fields", name, Boolean(member.doc || member.deprecated));
+    }
+  }
+
+  const perPackage = [...packages.entries()]
+    .sort(([a], [b]) => compareCanonical(a, b))
+    .map(([pkg, kinds]): PackageCoverage => {
+      const counts = Object.fromEntries(
+        coverageKinds.map((kind) => [
+          kind,
+          count(kinds[kind].documented.length, kinds[kind].undocumented.length),
+        ]),
+      ) as Record<CoverageKind, CoverageCount>;
+      return {
+        package: pkg,
+        ...sum(Object.values(counts)),
+        kinds: counts,
+        undocumented: coverageKinds.flatMap((kind) => kinds[kind].undocumented).sort(),
+      };
+    });
+
+  return {
+    ...sum(perPackage),
+    kinds: Object.fromEntries(
+      coverageKinds.map((kind) => [kind, sum(perPackage.map((pkg) => pkg.kinds[kind]))]),
+    ) as Record<CoverageKind, CoverageCount>,
+    packages: perPackage,
+  };
+}
+
+/**
+ * Format coverage as a table of packages by kind, for terminal output.
+ */
+export function formatCoverage(coverage: DocCoverage): string {
+  const header = ["Package", ...coverageKinds.map(capitalize), "Total"];
+  const row = (name: string, entry: PackageCoverage | DocCoverage) => [
+    name,
+    ...coverageKinds.map((kind) => formatCount(entry.kinds[kind])),
+    formatCount(entry),
+  ];
+
+  const rows = [
+    header,
+    ...coverage.packages.map((pkg) => row(pkg.package, pkg)),
+    row("Total", coverage),
+  ];
+
+  const widths = header.map((_, i) => Math.max(...rows.map((r) => r[i].length)));
+  return rows
+    .map((r) =>
+      r.map((cell, i) => (i === 0 ? cell.padEnd(widths[i]) : cell.padStart(widths[i]))).join("  "),
+    )
+    .join("\n");
+}
+
+/**
+ * The coverage kind of a symbol, if it counts for coverage.
+ */
+function coverageKindOf(symbol: GoSymbolRecord): CoverageKind | undefined {
+  switch (symbol.kind) {
+    case "function":
+      return "functions";
+    case "method":
+      return "methods";
+    case "variable":
+      return "values";
+    case "class":
+    case "interface":
+    case "typeAlias":
+      return "types";
+    default:
+      return undefined;
+  }
+}
+
+function count(documented: number, undocumented: number): CoverageCount {
+  const total = documented + undocumented;
+  const percent = total === 0 ? 100 : Math.round((documented / total) * 1000) / 10;
+  return { documented, total, percent };
+}
+
+function sum(counts: CoverageCount[]): CoverageCount {
+  const documented = counts.reduce((total, c) => total + c.documented, 0);
+  const total = counts.reduce((all, c) => all + c.total, 0);
+  return count(documented, total - documented);
+}
+
+function formatCount({ documented, total, percent }: CoverageCount): string {
+  return total === 0 ? "-" : `${documented}/${total} (${percent}%)`;
+}
+
+function capitalize(text: string): string {
+  return text[0].toUpperCase() + text.slice(1);
+}
@@ -0,0 +1,389 @@
+/**
+ * Extraction Daemon
+ *
+ * A long-running process that serves extraction requests over a local socket
+ * and keeps parsed files warm between them, so interactive tooling such as the
+ * preview server and editor integrations only pay for the files that changed.
+ *
+ * The protocol is newline-delimited JSON: each request line is
+ * `{ "id", "method", "params" }` and gets one `{ "id", "result" }` or
+ * `{ "id", "error" }` line back. Streaming methods send `{ "id", "chunk" }`
+ * lines before the result.
+ */
+
+import { createConnection, createServer, type Server, type Socket } from "net";
+import { rm } from "fs/promises";
+import { tmpdir } from "os";
+import { join } from "path";
+import { createConfig, validateConfig, type GoExtractorConfig } from "./config.js";
+import { GoExtractor, type ParsedFile } from "./extractor.js";
+import { GoTransformer } from "./transformer.js";
+import { buildOutput, type ExtractorOutput } from "./output.js";
+import {
+  applyProfile,
+  extractionProfiles,
+  type ExtractionProfile,
+  type ProfiledOutput,
+} from "./profile.js";
+import { ParseCache, type ParseCacheStats } from "./parse-cache.js";
+import { chunkKinds, streamChunks, type ChunkOptions, type DocChunk } from "./chunks.js";
+
+/**
+ * Default socket path of the daemon.
+ */
+export const defaultDaemonSocket = join(tmpdir(), "extract-go.sock");
+
+/**
+ * Parameters of an `extract` request.
+ */
+export interface DaemonExtractParams {
+  config: Partial<GoExtractorConfig> & Pick<GoExtractorConfig, "packageName" | "packagePath">;
+  profile?: ExtractionProfile;
+}
+
+/**
+ * Parameters of a `chunks` request: the package to extract and which chunks
+ * to stream within what token budget.
+ */
+export interface DaemonChunkParams extends ChunkOptions {
+  config: DaemonExtractParams["config"];
+}
+
+/**
+ * Final result of a `chunks` request.
+ */
+export interface ChunkStreamSummary {
+  chunks: number;
+  tokens: number;
+}
+
+/**
+ * A request to the daemon.
+ *
+ * - `extract`: run an extraction and return the output document
+ * - `chunks`: run an extraction and stream documentation chunks within a
+ *   token budget, then return a `ChunkStreamSummary`
+ * - `stats`: return request and cache counters
+ * - `shutdown`: stop the daemon after replying
+ */
+export type DaemonRequest =
+  | { id: number; method: "extract"; params: DaemonExtractParams }
+  | { id: number; method: "chunks"; params: DaemonChunkParams }
+  | { id: number; method: "stats" | "shutdown" };
+
+/**
+ * A reply from the daemon, or one chunk of a streamed reply.
+ */
+export interface DaemonResponse {
+  id: number;
+  result?: unknown;
+  error?: string;
+  chunk?: DocChunk;
+}
+
+/**
+ * Counters reported by the `stats` method.
+ */
+export interface DaemonStats {
+  requests: number;
+  cache: ParseCacheStats;
+}
+
+/**
+ * Serves extraction requests, sharing one parse cache across them.
+ */
+export class ExtractionDaemon {
+  private cache: ParseCache<ParsedFile>;
+  private server?: Server;
+  private sockets = new Set<Socket>();
+  private requests = 0;
+
+  constructor(cache: ParseCache<ParsedFile> = new ParseCache()) {
+    this.cache = cache;
+  }
+
+  /**
+   * Handle a single request. Streamed chunks are passed to `send` before the
+   * final response is returned.
+   */
+  async handle(
+    request: DaemonRequest,
+    send: (response: DaemonResponse) => void = () => {},
+  ): Promise<DaemonResponse> {
+    this.requests++;
+    try {
+      switch (request.method) {
+        case "extract":
+          return { id: request.id, result: await this.extract(request.params) };
+        case "chunks":
+          return {
+            id: request.id,
+            result: await this.chunks(request.params, (chunk) => send({ id: request.id, chunk })),
+          };
+        case "stats":
+          return { id: request.id, result: this.stats() };
+        case "shutdown":
+          setImmediate(() => this.close());
+          return { id: request.id, result: null };
+        default:
+          throw new Error(`Unknown method: ${(request as { method: string }).method}`);
+      }
+    } catch (error) {
+      return { id: request.id, error: error instanceof Error ? error.message : String(error) };
+    }
+  }
+
+  /**
+   * Run an extraction with the warm parse cache.
+   */
+  async extract(params: DaemonExtractParams): Promise<ProfiledOutput> {
+    const config = createConfig(params.config);
+    validateConfig(config);
+    const profile = params.profile ?? "full";
+    if (!extractionProfiles.includes(profile)) {
+      throw new Error(`Unknown profile: ${profile}`);
+    }
+
+    const result = await new GoExtractor(config, undefined, this.cache).extract();
+    const symbols = new GoTransformer(result, config).transform();
+    return applyProfile(buildOutput(result, config, symbols), profile);
+  }
+
+  /**
+   * Run an extraction and pass its chunks to `onChunk` in priority order,
+   * within the token budget.
+   */
+  async chunks(
+    params: DaemonChunkParams,
+    onChunk: (chunk: DocChunk) => void,
+  ): Promise<ChunkStreamSummary> {
+    if (!(params.budget > 0)) {
+      throw new Error("budget must be a positive number of tokens");
+    }
+    const unknown = params.priority?.find((kind) => !chunkKinds.includes(kind));
+    if (unknown) {
+      throw new Error(`Unknown chunk kind: ${unknown}`);
+    }
+
+    const output = (await this.extract({ config: params.config })) as ExtractorOutput;
+    const summary: ChunkStreamSummary = { chunks: 0, tokens: 0 };
+    for (const chunk of streamChunks(output.symbols, params)) {
+      onChunk(chunk);
+      summary.chunks++;
+      summary.tokens += chunk.tokens;
+    }
+    return summary;
+  }
+
+  /**
+   * Count the requests served and the cache hits and misses.
+   */
+  stats(): DaemonStats {
+    return { requests: this.requests, cache: this.cache.stats() };
+  }
+
+  /**
+   * Listen on a Unix socket (or Windows named pipe). A socket file left behind
+   * by a daemon that is no longer running is replaced.
+   */
+  async listen(socketPath: string = defaultDaemonSocket): Promise<void> {
+    try {
+      await this.bind(socketPath);
+    } catch (error) {
+      const inUse = (error as NodeJS.ErrnoException).code === "EADDRINUSE";
+      if (!inUse || (await isListening(socketPath))) {
+        throw error;
+      }
+      await rm(socketPath, { force: true });
+      await this.bind(socketPath);
+    }
+  }
+
+  /**
+   * Stop accepting connections and close open ones.
+   */
+  close(): Promise<void> {
+    for (const socket of this.sockets) {
+      socket.end();
+    }
+    return new Promise((resolve) => {
+      if (!this.server) return resolve();
+      this.server.close(() => resolve());
+      this.server = undefined;
+    });
+  }
+
+  private bind(socketPath: string): Promise<void> {
+    const server = createServer((socket) => this.serve(socket));
+    return new Promise((resolve, reject) => {
+      server.once("error", reject);
+      server.listen(socketPath, () => {
+        this.server = server;
+        resolve();
+      });
+    });
+  }
+
+  /**
+   * Answer the request lines of one connection, in order.
+   */
+  private serve(socket: Socket): void {
+    let buffer = "";
+    let queue = Promise.resolve();
+    this.sockets.add(socket);
+    socket.on("close", () => this.sockets.delete(socket));
+    socket.setEncoding("utf-8");
+    socket.on("data", (chunk: string) => {
+      buffer += chunk;
+      let newline;
+      while ((newline = buffer.indexOf("\n")) !== -1) {
+        const line = buffer.slice(0, newline).trim();
+        buffer = buffer.slice(newline + 1);
+        if (!line) continue;
+        queue = queue.then(async () => {
+          let response: DaemonResponse;
+          try {
+            response = await this.handle(JSON.parse(line) as DaemonRequest, (chunk) =>
+              socket.write(`${JSON.stringify(chunk)}\n`),
+            );
+          } catch {
+            response = { id: -1, error: "Malformed request" };
+          }
+          socket.write(`${JSON.stringify(response)}\n`);
+        });
+      }
+    });
+    socket.on("error", () => socket.destroy());
+  }
+}
+
+/**
+ * Client for a running daemon, for the CLI and Node callers.
+ */
+export class DaemonClient {
+  private socketPath: string;
+  private socket?: Socket;
+  private buffer = "";
+  private nextId = 1;
+  private pending = new Map<
+    number,
+    { resolve: (response: DaemonResponse) => void; reject: (error: Error) => void }
+  >();
+  private streams = new Map<number, (chunk: DocChunk) => void>();
+
+  constructor(socketPath: string = defaultDaemonSocket) {
+    this.socketPath = socketPath;
+  }
+
+  /**
+   * Run an extraction on the daemon.
+   */
+  async extract(params: DaemonExtractParams): Promise<ProfiledOutput> {
+    return (await this.request("extract", params)) as ProfiledOutput;
+  }
+
+  /**
+   * Stream documentation chunks of an extraction within a token budget.
+   */
+  async chunks(
+    params: DaemonChunkParams,
+    onChunk: (chunk: DocChunk) => void,
+  ): Promise<ChunkStreamSummary> {
+    return (await this.request("chunks", params, onChunk)) as ChunkStreamSummary;
+  }
+
+  /**
+   * Get the daemon's request and cache counters.
+   */
+  async stats(): Promise<DaemonStats> {
+    return (await this.request("stats")) as DaemonStats;
+  }
+
+  /**
+   * Ask the daemon to stop.
+   */
+  async shutdown(): Promise<void> {
+    await this.request("shutdown");
+  }
+
+  /**
+   * Close the connection.
+   */
+  close(): void {
+    this.socket?.end();
+    this.socket = undefined;
+  }
+
+  private async request(
+    method: DaemonRequest["method"],
+    params?: unknown,
+    onChunk?: (chunk: DocChunk) => void,
+  ): Promise<unknown> {
+    const socket = await this.connect();
+    const id = this.nextId++;
+    if (onChunk) {
+      this.streams.set(id, onChunk);
+    }
+    const response = await new Promise<DaemonResponse>((resolve, reject) => {
+      this.pending.set(id, { resolve, reject });
+      socket.write(`${JSON.stringify({ id, method, params })}\n`);
+    }).finally(() => this.streams.delete(id));
+    if (response.error) {
+      throw new Error(response.error);
+    }
+    return response.result;
+  }
+
+  private connect(): Promise<Socket> {
+    if (this.socket) return Promise.resolve(this.socket);
+
+    return new Promise((resolve, reject) => {
+      const socket = createConnection(this.socketPath);
+      socket.setEncoding("utf-8");
+      socket.once("error", reject);
+      socket.once("connect", () => {
+        this.socket = socket;
+        resolve(socket);
+      });
+      socket.on("data", (chunk: string) => {
+        this.buffer += chunk;
+        let newline;
+        while ((newline = this.buffer.indexOf("\n")) !== -1) {
+          const response = JSON.parse(this.buffer.slice(0, newline)) as DaemonResponse;
+          this.buffer = this.buffer.slice(newline + 1);
+          if (response.chunk) {
+            this.streams.get(response.id)?.(response.chunk);
+            continue;
+          }
+          this.pending.get(response.id)?.resolve(response);
+          this.pending.delete(response.id);
+        }
+      });
+      // A daemon that stops mid-request fails the requests in flight, and the
+      // next request connects again
+      socket.on("error", () => socket.destroy());
+      socket.on("close", () => {
+        if (this.socket === socket) this.socket = undefined;
+        this.buffer = "";
+        for (const { reject } of this.pending.values()) {
+          reject(new Error("The daemon closed the connection"));
+        }
+        this.pending.clear();
+      });
+    });
+  }
+}
+
+/**
+ * Whether a daemon is accepting connections on a socket.
+ */
+function isListening(socketPath: string): Promise<boolean> {
+  return new Promise((resolve) => {
+    const socket = createConnection(socketPath);
+    socket.once("connect", () => {
+      socket.end();
+      resolve(true);
+    });
+    socket.once("error", () => resolve(false));
+  });
+}
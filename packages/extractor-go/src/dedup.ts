@@ -0,0 +1,75 @@
+/**
+ * Package Deduplication
+ *
+ * Detects Go packages that are byte-identical copies of each other, such as
+ * vendored or forked packages, so they are extracted only once.
+ */
+
+import { createHash } from "crypto";
+
+/**
+ * A Go package directory and the hash of its source files.
+ */
+export interface PackageDirectory {
+  /** Directory relative to the package path ("" for the root) */
+  dir: string;
+  /** Source files in the directory, relative to the package path */
+  files: string[];
+  contentHash: string;
+}
+
+/**
+ * A canonical package and the directories holding identical copies of it.
+ */
+export interface PackageDuplicate {
+  canonical: string;
+  aliases: string[];
+  contentHash: string;
+}
+
+/**
+ * Hash the source files of a package. Files are hashed by base name and
+ * content, in name order, so identical copies hash the same wherever they live.
+ */
+export function hashPackageFiles(files: Array<{ name: string; content: string }>): string {
+  const hash = createHash("sha256");
+  for (const file of [...files].sort((a, b) => a.name.localeCompare(b.name))) {
+    hash.update(file.name);
+    hash.update("\0");
+    hash.update(file.content);
+    hash.update("\0");
+  }
+  return hash.digest("hex");
+}
+
+/**
+ * Group package directories by content hash and return every group with more
+ * than one member. The shallowest directory (then the first by name) is
+ * canonical; the others are its aliases.
+ */
+export function findDuplicatePackages(packages: PackageDirectory[]): PackageDuplicate[] {
+  const byHash = new Map<string, string[]>();
+  for (const pkg of packages) {
+    const dirs = byHash.get(pkg.contentHash) ?? [];
+    dirs.push(pkg.dir);
+    byHash.set(pkg.contentHash, dirs);
+  }
+
+  const duplicates: PackageDuplicate[] = [];
+  for (const [contentHash, dirs] of byHash) {
+    if (dirs.length < 2) continue;
+
+    const [canonical, ...aliases] = dirs.sort(compareDirs);
+    duplicates.push({ canonical, aliases, contentHash });
+  }
+
+  return duplicates.sort((a, b) => compareDirs(a.canonical, b.canonical));
+}
+
+function compareDirs(a: string, b: string): number {
+  return depth(a) - depth(b) || a.localeCompare(b);
+}
+
+function depth(dir: string): number {
+  return dir ? dir.split("/").length : 0;
+}
@@ -0,0 +1,490 @@
+/**
+ * Go Doc Comments
+ *
+ * Parses doc comments with the Go 1.19 syntax of go/doc/comment into
+ * structured blocks (paragraphs, headings, lists, code blocks, and
+ * verbatim diagrams and tables) and inline text (plain text, URLs, links,
+ * and doc links), so renderers don't have to guess at the structure of
+ * plain text. Also splits annotation tags such as `@since v0.3.0` off doc
+ * comments, finds the links of "See also" sentences, and marks security and
+ * concurrency notes as callouts.
+ */
+
+/**
+ * A block of a doc comment.
+ */
+export type DocBlock =
+  | { kind: "paragraph"; text: DocText[] }
+  | { kind: "heading"; text: DocText[] }
+  | { kind: "list"; ordered: boolean; items: DocListItem[] }
+  | { kind: "code"; language: "go"; text: string }
+  | { kind: "verbatim"; text: string }
+  | { kind: "callout"; callout: CalloutKind; text: DocText[] };
+
+/**
+ * A note renderers display as a warning box.
+ *
+ * - `security`: a `Security:` paragraph
+ * - `concurrency`: a `Concurrency:` paragraph, or a sentence on thread or
+ *   goroutine safety, like "Not safe for concurrent use."
+ */
+export type CalloutKind = "security" | "concurrency";
+
+/**
+ * An item of a list. Numbered items keep their number.
+ */
+export interface DocListItem {
+  number?: string;
+  text: DocText[];
+}
+
+/**
+ * A span of inline text.
+ *
+ * - `plain`: text as written
+ * - `link`: a URL, or a `[Text]` with a `[Text]: URL` definition
+ * - `docLink`: a `[Name]` or `[pkg.Name]` reference to a Go declaration,
+ *   with the symbol ID or URL it resolves to
+ */
+export type DocText =
+  | { kind: "plain"; text: string }
+  | { kind: "link"; text: string; url: string }
+  | ({ kind: "docLink"; text: string; target: string } & DocLinkTarget);
+
+/**
+ * A doc link span.
+ */
+export type DocLinkSpan = Extract<DocText, { kind: "docLink" }>;
+
+/**
+ * Where a doc link points: a symbol of the extracted package, or the
+ * documentation of another package.
+ */
+export interface DocLinkTarget {
+  refId?: string;
+  url?: string;
+}
+
+const LIST_MARKER = /^(?:([-*+•])|(\d+)[.)])[ \t]+/;
+const LINK_DEF = /^\[([^\]]+)\]:\s+(\S+)$/;
+const URL = /https?:\/\/[^\s<>"]+/;
+const DOC_LINK = /^\*?(?:[a-z][\w/.]*\.)?[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)?$/;
+const SEE_ALSO = /\bsee also\b/i;
+const DIAGRAM_LINE = /[\u2500-\u257f]|[-=]{2,}>|<[-=]{2,}|\+[-=]{2,}|\|.*\|/;
+const CALLOUT_LABEL = /^(Security|Concurrency):\s*/;
+const CONCURRENCY_NOTE =
+  /\b(?:(?:thread|goroutine|concurrency)[- ]safe|safe for (?:concurrent|parallel) use)\b/i;
+
+/**
+ * Parse a doc comment, as extracted with its `//` markers removed and its
+ * indentation kept, into blocks.
+ */
+export function parseDocComment(doc: string): DocBlock[] {
+  const lines = doc.replace(/\s+$/, "").split("\n");
+  const links = new Map<string, string>();
+  const spans: Array<{ indented: boolean; lines: string[] }> = [];
+
+  // Split into unindented paragraphs and indented spans, which may contain
+  // blank lines
+  for (let i = 0; i < lines.length; ) {
+    if (!lines[i].trim()) {
+      i++;
+      continue;
+    }
+    const indented = /^[ \t]/.test(lines[i]);
+    const span: string[] = [];
+    while (i < lines.length) {
+      const line = lines[i];
+      if (!line.trim()) {
+        if (!indented) break;
+        const next = lines.slice(i).find((l) => l.trim());
+        if (!next || !/^[ \t]/.test(next)) break;
+      } else if (/^[ \t]/.test(line) !== indented) {
+        break;
+      }
+      span.push(line);
+      i++;
+    }
+    spans.push({ indented, lines: span });
+  }
+
+  // Link definitions are paragraphs made up of `[Text]: URL` lines only
+  const content = spans.filter((span) => {
+    const defs = span.lines.map((line) => line.trim().match(LINK_DEF));
+    if (span.indented || defs.some((def) => !def)) return true;
+    for (const def of defs) links.set(def![1], def![2]);
+    return false;
+  });
+
+  return content.map((span, index) => {
+    if (span.indented) {
+      return indentedBlock(span.lines, links);
+    }
+    if (isVerbatim(span.lines)) {
+      return { kind: "verbatim", text: span.lines.map((line) => line.trimEnd()).join("\n") };
+    }
+    const text = span.lines.map((line) => line.trim()).join(" ");
+    const heading = text.match(/^#\s+(.+)$/);
+    if (span.lines.length === 1 && heading) {
+      return { kind: "heading", text: parseDocText(heading[1], links) };
+    }
+    if (span.lines.length === 1 && isImplicitHeading(text, content, index)) {
+      return { kind: "heading", text: parseDocText(text, links) };
+    }
+    return { kind: "paragraph", text: parseDocText(text, links) };
+  });
+}
+
+/**
+ * Parse an indented span: a list when its first line starts with a list
+ * marker, a code block otherwise.
+ */
+function indentedBlock(lines: string[], links: Map<string, string>): DocBlock {
+  const first = lines[0].trim().match(LIST_MARKER);
+  if (!first) {
+    return { kind: "code", language: "go", text: dedent(lines) };
+  }
+
+  // Continuation lines extend the item above them
+  const items: Array<{ number?: string; text: string }> = [];
+  for (const line of lines) {
+    const trimmed = line.trim();
+    const marker = trimmed.match(LIST_MARKER);
+    if (marker) {
+      items.push({ number: marker[2], text: trimmed.slice(marker[0].length) });
+    } else if (trimmed && items.length > 0) {
+      items[items.length - 1].text += ` ${trimmed}`;
+    }
+  }
+  return {
+    kind: "list",
+    ordered: first[2] !== undefined,
+    items: items.map(({ number, text }) => ({
+      ...(number ? { number } : {}),
+      text: parseDocText(text, links),
+    })),
+  };
+}
+
+/**
+ * Whether a single-line paragraph is an old-style heading: it starts with an
+ * uppercase letter, has no punctuation besides parentheses and commas, and sits
+ * between two paragraphs.
+ */
+function isImplicitHeading(
+  text: string,
+  spans: Array<{ indented: boolean; lines: string[] }>,
+  index: number,
+): boolean {
+  const before = spans[index - 1];
+  const after = spans[index + 1];
+  if (!before || before.indented || !after || after.indented) return false;
+  return /^[A-Z][\p{L}\p{N} (),']*$/u.test(text) && !/'(?!s\b)/.test(text);
+}
+
+/**
+ * Whether unindented lines are preformatted text that reflowing would
+ * garble: a diagram, with box-drawing characters, arrows, or `+--` and
+ * `|...|` borders on most lines, or a table, with columns separated by two
+ * or more spaces starting at the same position on every line.
+ */
+function isVerbatim(lines: string[]): boolean {
+  if (lines.length < 2) return false;
+  if (lines.filter((line) => DIAGRAM_LINE.test(line)).length * 2 >= lines.length) return true;
+
+  const columns = lines.map(
+    (line) => new Set([...line.matchAll(/\S {2,}(?=\S)/g)].map((m) => m.index + m[0].length)),
+  );
+  return [...columns[0]].some((column) => columns.every((starts) => starts.has(column)));
+}
+
+/**
+ * Remove the indentation common to all lines, keeping inner blank lines.
+ */
+export function dedent(lines: string[]): string {
+  const indents = lines.filter((l) => l.trim()).map((l) => l.match(/^[ \t]*/)![0]);
+  let prefix = indents[0] ?? "";
+  for (const indent of indents) {
+    while (!indent.startsWith(prefix)) prefix = prefix.slice(0, -1);
+  }
+  return lines
+    .map((line) => line.slice(prefix.length))
+    .join("\n")
+    .replace(/\s+$/, "");
+}
+
+/**
+ * Convert a doc comment to Markdown.
+ */
+export function docToMarkdown(doc?: string): string | undefined {
+  if (!doc) return undefined;
+
+  return (
+    doc
+      // Fence diagrams and tables, which would be reflowed
+      .replace(/^\S.*(?:\n\S.*)+/gm, (span) =>
+        isVerbatim(span.split("\n")) ? "```text\n" + span + "\n```" : span,
+      )
+      // Fence indented spans as code blocks, keeping their inner indentation
+      // and blank lines; indented lists stay lists
+      .replace(/^[ \t]+\S.*(?:\n(?:[ \t]*\n)*[ \t]+\S.*)*/gm, (span) =>
+        LIST_MARKER.test(span.trim()) ? span : "```go\n" + dedent(span.split("\n")) + "\n```",
+      )
+      // Convert BUG(name): to warning
+      .replace(/^BUG\((\w+)\):\s*/gm, "**Bug ($1):** ")
+      .trim()
+  );
+}
+
+/**
+ * Annotation tags of a doc comment by name: the tag's text, or `true` for a
+ * tag without one, like `@experimental`.
+ */
+export type DocAnnotations = Record<string, string | true>;
+
+/**
+ * Tags recognized in doc comments by default.
+ */
+export const defaultDocTags = ["since", "experimental", "category"];
+
+/**
+ * Split annotation tags off a doc comment. A tag starts an unindented line
+ * with `@name`, and its text runs to the end of the line or the next tag, so
+ * `@experimental @category retrievers` holds two tags. Only the given tag
+ * names are recognized; tag lines are removed from the returned prose.
+ */
+export function splitDocTags(
+  doc: string,
+  names: string[],
+): { prose: string; annotations?: DocAnnotations } {
+  if (names.length === 0) return { prose: doc };
+
+  const alternatives = names.join("|");
+  const tagLine = new RegExp(`^@(?:${alternatives})(?![\\w-])`);
+  const tag = new RegExp(`(?:^|\\s)@(${alternatives})(?![\\w-])`, "g");
+  const annotations: DocAnnotations = {};
+  const prose: string[] = [];
+
+  for (const line of doc.split("\n")) {
+    if (!tagLine.test(line)) {
+      prose.push(line);
+      continue;
+    }
+    const matches = [...line.matchAll(tag)];
+    for (const [index, match] of matches.entries()) {
+      const end = matches[index + 1]?.index ?? line.length;
+      const text = line.slice(match.index + match[0].length, end).trim();
+      annotations[match[1]] = text || true;
+    }
+  }
+
+  if (Object.keys(annotations).length === 0) return { prose: doc };
+  // Collapse the blank lines left around removed tag lines
+  return { prose: prose.join("\n").replace(/\n(?:[ \t]*\n){2,}/g, "\n\n"), annotations };
+}
+
+/**
+ * Split paragraph text into plain text, URLs, links, and doc links.
+ */
+export function parseDocText(text: string, links: Map<string, string> = new Map()): DocText[] {
+  const spans: DocText[] = [];
+  const plain = (value: string) => {
+    if (!value) return;
+    const last = spans[spans.length - 1];
+    if (last?.kind === "plain") last.text += value;
+    else spans.push({ kind: "plain", text: value });
+  };
+
+  const pattern = new RegExp(`\\[([^\\]\\n]+)\\]|${URL.source}`, "g");
+  let index = 0;
+  for (const match of text.matchAll(pattern)) {
+    plain(text.slice(index, match.index));
+    index = match.index + match[0].length;
+
+    if (match[1] === undefined) {
+      // Leave closing punctuation out of bare URLs
+      const url = match[0].replace(/[.,:;!?)]+$/, "");
+      spans.push({ kind: "link", text: url, url });
+      index = match.index + url.length;
+    } else if (links.has(match[1])) {
+      spans.push({ kind: "link", text: match[1], url: links.get(match[1])! });
+    } else if (DOC_LINK.test(match[1])) {
+      spans.push({ kind: "docLink", text: match[1], target: match[1].replace(/^\*/, "") });
+    } else {
+      plain(match[0]);
+    }
+  }
+  plain(text.slice(index));
+  return spans;
+}
+
+/**
+ * Resolve the doc links of parsed blocks. Links that don't resolve become
+ * plain text, as go/doc renders them.
+ */
+export function resolveDocLinks(
+  blocks: DocBlock[],
+  resolve: (target: string) => DocLinkTarget | undefined,
+): DocBlock[] {
+  const resolveText = (text: DocText[]): DocText[] => {
+    const spans: DocText[] = [];
+    for (const span of text) {
+      const resolved = span.kind === "docLink" ? resolve(span.target) : undefined;
+      const next: DocText =
+        span.kind !== "docLink"
+          ? span
+          : resolved
+            ? { ...span, ...resolved }
+            : { kind: "plain", text: `[${span.text}]` };
+      const last = spans[spans.length - 1];
+      if (next.kind === "plain" && last?.kind === "plain") {
+        spans[spans.length - 1] = { kind: "plain", text: last.text + next.text };
+      } else {
+        spans.push(next);
+      }
+    }
+    return spans;
+  };
+
+  return blocks.map((block) => {
+    switch (block.kind) {
+      case "paragraph":
+      case "heading":
+      case "callout":
+        return { ...block, text: resolveText(block.text) };
+      case "list":
+        return {
+          ...block,
+          items: block.items.map((item) => ({ ...item, text: resolveText(item.text) })),
+        };
+      default:
+        return block;
+    }
+  });
+}
+
+/**
+ * Collect the doc link spans of parsed blocks, in order of appearance.
+ */
+export function docLinkSpans(blocks: DocBlock[]): DocLinkSpan[] {
+  const spans = blocks.flatMap((block): DocText[] => {
+    switch (block.kind) {
+      case "paragraph":
+      case "heading":
+      case "callout":
+        return block.text;
+      case "list":
+        return block.items.flatMap((item) => item.text);
+      default:
+        return [];
+    }
+  });
+  return spans.filter((span): span is DocLinkSpan => span.kind === "docLink");
+}
+
+/**
+ * Targets of the doc links in "See also" sentences, like
+ * `See also [Client.Get] and [net/http.Client].`, in order of appearance.
+ * A sentence runs from "See also" to the next `.`, `!`, or `?` followed by
+ * a space, or to the end of its paragraph or list item.
+ */
+export function seeAlsoTargets(blocks: DocBlock[]): string[] {
+  const targets: string[] = [];
+  const scan = (text: DocText[]) => {
+    let inSentence = false;
+    for (const span of text) {
+      if (span.kind === "docLink") {
+        if (inSentence && !targets.includes(span.target)) targets.push(span.target);
+        continue;
+      }
+      // Follow the sentence through the plain text, which may start or end it
+      let rest = span.text;
+      while (rest) {
+        const boundary = inSentence ? rest.search(/[.!?](?:\s|$)/) : rest.search(SEE_ALSO);
+        if (boundary === -1) break;
+        rest = rest.slice(boundary + 1);
+        inSentence = !inSentence;
+      }
+    }
+  };
+
+  for (const block of blocks) {
+    if (block.kind === "paragraph") scan(block.text);
+    if (block.kind === "list") block.items.forEach((item) => scan(item.text));
+  }
+  return targets;
+}
+
+/**
+ * Mark the security and concurrency notes of parsed blocks as callouts.
+ * `Security:` and `Concurrency:` paragraphs become callouts without their
+ * label; sentences on thread safety are moved out of their paragraph into a
+ * callout after it, so the rest of the paragraph reads on as before.
+ */
+export function markCallouts(blocks: DocBlock[]): DocBlock[] {
+  return blocks.flatMap((block): DocBlock[] => {
+    if (block.kind !== "paragraph") return [block];
+
+    const [first, ...rest] = block.text;
+    const label = first?.kind === "plain" ? first.text.match(CALLOUT_LABEL) : null;
+    if (label) {
+      const text = first.text.slice(label[0].length);
+      return [
+        {
+          kind: "callout",
+          callout: label[1].toLowerCase() as CalloutKind,
+          text: text ? [{ ...first, text }, ...rest] : rest,
+        },
+      ];
+    }
+
+    const sentences = splitSentences(block.text);
+    const isNote = (sentence: DocText[]) =>
+      CONCURRENCY_NOTE.test(sentence.map((span) => span.text).join(""));
+    const notes = sentences.filter(isNote);
+    if (notes.length === 0) return [block];
+
+    const prose = sentences.filter((sentence) => !isNote(sentence));
+    return [
+      ...(prose.length ? [{ kind: "paragraph" as const, text: joinSentences(prose) }] : []),
+      { kind: "callout", callout: "concurrency", text: joinSentences(notes) },
+    ];
+  });
+}
+
+/**
+ * Split inline text into sentences, at `.`, `!`, or `?` followed by a space.
+ */
+function splitSentences(text: DocText[]): DocText[][] {
+  const sentences: DocText[][] = [[]];
+  for (const span of text) {
+    if (span.kind !== "plain") {
+      sentences[sentences.length - 1].push(span);
+      continue;
+    }
+    for (const [index, part] of span.text.split(/(?<=[.!?])\s+/).entries()) {
+      if (index > 0) sentences.push([]);
+      if (part) sentences[sentences.length - 1].push({ kind: "plain", text: part });
+    }
+  }
+  return sentences.filter((sentence) => sentence.length > 0);
+}
+
+/**
+ * Join sentences with a space, merging adjacent plain text.
+ */
+function joinSentences(sentences: DocText[][]): DocText[] {
+  const spans: DocText[] = [];
+  const separated = sentences.flatMap((sentence, index): DocText[] =>
+    index > 0 ? [{ kind: "plain", text: " " }, ...sentence] : sentence,
+  );
+  for (const span of separated) {
+    const last = spans[spans.length - 1];
+    if (span.kind === "plain" && last?.kind === "plain") {
+      spans[spans.length - 1] = { kind: "plain", text: last.text + span.text };
+    } else {
+      spans.push(span);
+    }
+  }
+  return spans;
+}
@@ -0,0 +1,100 @@
+/**
+ * Docusaurus Integration
+ *
+ * Wires the MDX pages into a Docusaurus site: front matter Docusaurus reads
+ * (`sidebar_label`, `slug`, and `custom_edit_url` pointing at the Go source)
+ * and a `sidebars.json` fragment listing the pages under a category per
+ * package, grouped like the package page. Doc IDs and slugs are prefixed
+ * with the pages' path in the site's docs folder, so the fragment can be
+ * spread into an existing sidebar as is.
+ */
+
+import { posix } from "path";
+import type { ExtractorOutput } from "./output.js";
+import type { GoSymbolRecord } from "./transformer.js";
+import { defaultLabels } from "./labels.js";
+
+/**
+ * Name of the sidebar fragment written next to the pages.
+ */
+export const docusaurusSidebarFile = "sidebars.json";
+
+/**
+ * An item of a Docusaurus sidebar: a doc ID or a category.
+ */
+export type DocusaurusSidebarItem =
+  | string
+  | {
+      type: "category";
+      label: string;
+      link?: { type: "doc"; id: string };
+      items: DocusaurusSidebarItem[];
+    };
+
+/**
+ * Docusaurus front matter of a page.
+ */
+export interface DocusaurusFrontMatter {
+  sidebar_label: string;
+  slug: string;
+  custom_edit_url?: string;
+}
+
+/**
+ * Front matter of the package page (without a symbol) or a symbol's page,
+ * for pages at `docPath` in the docs folder.
+ */
+export function docusaurusFrontMatter(
+  output: ExtractorOutput,
+  docPath: string,
+  symbol?: GoSymbolRecord,
+): DocusaurusFrontMatter {
+  if (!symbol) {
+    return { sidebar_label: output.package.displayName, slug: docSlug(docPath, "/") };
+  }
+  const { repo, sha, path, line } = symbol.source;
+  return {
+    sidebar_label: symbol.name,
+    slug: docSlug(docPath, symbol.urls.canonical),
+    custom_edit_url:
+      repo && sha ? `https://github.com/${repo}/blob/${sha}/${path}#L${line}` : undefined,
+  };
+}
+
+/**
+ * Build the sidebar fragment of the pages of `symbols` (the symbols with a
+ * page of their own), for pages at `docPath` in the docs folder.
+ */
+export function buildDocusaurusSidebar(
+  output: ExtractorOutput,
+  symbols: GoSymbolRecord[],
+  docPath: string,
+): DocusaurusSidebarItem[] {
+  const labels = output.labels?.labels ?? defaultLabels;
+  const docId = (slug: string) => posix.join(docPath, slug);
+  const groups: Array<[string, GoSymbolRecord[]]> = [
+    [labels.types, symbols.filter((symbol) => symbol.kind !== "function")],
+    [labels.functions, symbols.filter((symbol) => symbol.kind === "function")],
+  ];
+  return [
+    {
+      type: "category",
+      label: output.package.displayName,
+      link: { type: "doc", id: docId("index") },
+      items: groups
+        .filter(([, group]) => group.length > 0)
+        .map(([label, group]) => ({
+          type: "category" as const,
+          label,
+          items: group.map((symbol) => docId(symbol.urls.canonical.slice(1))),
+        })),
+    },
+  ];
+}
+
+/**
+ * Slug of a page at `slug` below `docPath`.
+ */
+function docSlug(docPath: string, slug: string): string {
+  return posix.join("/", docPath, slug).replace(/(.)\/$/, "$1");
+}
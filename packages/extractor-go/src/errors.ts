@@ -0,0 +1,78 @@
+/**
+ * Extraction Errors
+ *
+ * Problems that leave a package's extraction incomplete, which the run
+ * continues after. They are listed in the output's `package.errors` (and so
+ * in the split manifest) for CI to tell which packages are affected, and
+ * make the CLI exit with `exitCodes.partial`.
+ */
+
+import { compareCanonical } from "./canonical.js";
+
+/**
+ * What went wrong.
+ *
+ * - `parse`: a source file couldn't be read or parsed; its declarations are
+ *   missing
+ * - `package`: files of one directory declare different packages, which Go
+ *   rejects
+ * - `import`: a file imports a package of the module that doesn't exist
+ * - `timeout`: a file took longer than the package timeout to parse; the
+ *   package is skipped
+ * - `memory`: parsing a file ran out of the memory budget; the package is
+ *   skipped
+ */
+export type ExtractionErrorKind = "parse" | "package" | "import" | "timeout" | "memory";
+
+/**
+ * All extraction error kinds.
+ */
+export const extractionErrorKinds: ExtractionErrorKind[] = [
+  "parse",
+  "package",
+  "import",
+  "timeout",
+  "memory",
+];
+
+/**
+ * A problem with one package.
+ */
+export interface ExtractionError {
+  kind: ExtractionErrorKind;
+  /** Directory relative to the package path ("" for the root) */
+  package: string;
+  /** Source file relative to the package path */
+  file?: string;
+  message: string;
+}
+
+/**
+ * Exit codes of an extraction run.
+ *
+ * - `success`: everything was extracted
+ * - `failure`: the run failed and wrote nothing (or `--check` found changes)
+ * - `partial`: the outputs were written, but some packages had errors
+ */
+export const exitCodes = { success: 0, failure: 1, partial: 2 } as const;
+
+/**
+ * Put errors in canonical order: by package, file, kind, and message.
+ */
+export function sortExtractionErrors(errors: ExtractionError[]): ExtractionError[] {
+  return errors.sort(
+    (a, b) =>
+      compareCanonical(a.package, b.package) ||
+      compareCanonical(a.file ?? "", b.file ?? "") ||
+      extractionErrorKinds.indexOf(a.kind) - extractionErrorKinds.indexOf(b.kind) ||
+      compareCanonical(a.message, b.message),
+  );
+}
+
+/**
+ * Format an error as a line, like "internal/auth (parse): token.go: ...".
+ */
+export function formatExtractionError(error: ExtractionError): string {
+  const location = error.file ? `${error.file}: ` : "";
+  return `${error.package || "."} (${error.kind}): ${location}${error.message}`;
+}
@@ -0,0 +1,64 @@
+/**
+ * Go Examples
+ *
+ * Turns the `ExampleXxx` functions of test files into IR examples: those
+ * naming a symbol are attached to it, while bare `Example()` functions
+ * document the package as a whole.
+ */
+
+import type { SymbolExample, SymbolSource } from "@langchain/ir-schema";
+import type { GoExtractorConfig } from "./config.js";
+import type { GoExample } from "./extractor.js";
+
+/**
+ * An `ExampleXxx` function from a test file.
+ */
+export interface GoSymbolExample extends SymbolExample {
+  /** Function name, e.g. "ExampleConnect_retry" */
+  name: string;
+  /** Doc comment of the example function */
+  description?: string;
+  /** Expected output from the `// Output:` comment */
+  output?: string;
+  /** The output may be printed in any order */
+  unordered?: boolean;
+  /** The code is the whole test file, which the example needs to run */
+  wholeFile?: boolean;
+  source: SymbolSource;
+}
+
+/**
+ * Convert an extracted example to an IR example. The name suffix becomes the
+ * title, capitalized as godoc shows it.
+ */
+export function buildExample(example: GoExample, config: GoExtractorConfig): GoSymbolExample {
+  return {
+    name: example.name,
+    title: example.suffix ? example.suffix[0].toUpperCase() + example.suffix.slice(1) : undefined,
+    description: example.doc,
+    code: example.code,
+    language: "go",
+    output: example.output,
+    unordered: example.unordered,
+    wholeFile: example.wholeFile,
+    source: {
+      repo: config.repo,
+      sha: config.sha,
+      path: example.sourceFile,
+      line: example.startLine,
+    },
+  };
+}
+
+/**
+ * The package-level examples: `Example()` and `Example_suffix()`.
+ */
+export function packageExamples(
+  examples: GoExample[] | undefined,
+  config: GoExtractorConfig,
+): GoSymbolExample[] | undefined {
+  const packageLevel = (examples ?? []).filter((example) => !example.target);
+  return packageLevel.length > 0
+    ? packageLevel.map((example) => buildExample(example, config))
+    : undefined;
+}
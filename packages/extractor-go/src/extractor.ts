@@ -4,10 +4,48 @@
  * Parses Go source files and extracts API documentation.
  */
 
-import { readFile } from "fs/promises";
-import { join, relative } from "path";
+import { readdir, readFile, stat } from "fs/promises";
+import { performance } from "perf_hooks";
+import { Worker } from "worker_threads";
+import { basename, dirname, join, relative } from "path";
 import { glob } from "tinyglobby";
 import type { GoExtractorConfig } from "./config.js";
+import { loadOwnership } from "./codeowners.js";
+import { loadSidecarDocs, type SidecarDoc } from "./sidecar.js";
+import {
+  convertConstValue,
+  evaluateConstExpr,
+  type ConstScope,
+  type GoConstValue,
+} from "./const-eval.js";
+import {
+  findDuplicatePackages,
+  hashPackageFiles,
+  type PackageDirectory,
+  type PackageDuplicate,
+} from "./dedup.js";
+import { parseImports } from "./imports.js";
+import { dedent } from "./doc-comment.js";
+import { formatTypeExpr, parseTypeExpr, splitTopLevel, type GoTypeExpr } from "./type-expr.js";
+import type { TimingRecorder } from "./timings.js";
+import type { ParseCache } from "./parse-cache.js";
+import type { CachedPackage, PackageCache } from "./package-cache.js";
+import type { ProgressReporter } from "./progress.js";
+import {
+  parseWorkerUrl,
+  workerMemoryLimit,
+  WorkerLimitError,
+  WorkerPool,
+} from "./worker-pool.js";
+import { parseModulesTxt, vendorImportPath, type VendorModule } from "./vendor.js";
+import { compareCanonical } from "./canonical.js";
+import { buildTarget, matchBuildConstraint, matchFileName } from "./build-constraints.js";
+import { logger } from "./logger.js";
+import {
+  formatExtractionError,
+  sortExtractionErrors,
+  type ExtractionError,
+} from "./errors.js";
 
 /**
  * Represents a parsed Go type (struct, interface, etc.).
@@ -18,8 +56,27 @@ export interface GoType {
   packageName: string;
   doc?: string;
   signature: string;
+  typeParams?: GoTypeParam[];
   methods: GoMethod[];
   fields: GoField[];
+  /** Interface method names, including unexported ones */
+  interfaceMethods?: string[];
+  /** Source text between the braces of an interface, with its doc comments */
+  interfaceBody?: string;
+  /** Embedded interfaces of an interface */
+  embeds?: string[];
+  /** Interface with unexported methods, implementable only inside the package */
+  sealed?: boolean;
+  /** In-package types implementing the interface: inferred if sealed, or asserted */
+  implementations?: string[];
+  /** Interfaces the type is asserted to implement, e.g. "io.Writer" */
+  implements?: string[];
+  /** Aliased type expression, for aliases */
+  aliasOf?: string;
+  /** Unexported in-package types embedded in a struct, whose exported methods it promotes */
+  embedsUnexported?: string[];
+  /** Exported or package-qualified types embedded in a struct, e.g. "Buffer", "sync.Mutex" */
+  structEmbeds?: string[];
   sourceFile: string;
   startLine: number;
 }
@@ -33,11 +90,48 @@ export interface GoMethod {
   signature: string;
   receiver?: string;
   receiverType?: string;
+  typeParams?: GoTypeParam[];
   parameters: GoParameter[];
   returns: string;
+  /** Composite literal of the result type returned by the body, e.g. `return &Config{...}` */
+  returnedLiteral?: GoCompositeLiteral;
+  /** Set for declarations without a body, implemented elsewhere */
+  externalImplementation?: GoExternalImplementation;
+  /** Unexported embedded type the method is promoted from */
+  promotedFrom?: string;
+  sourceFile: string;
   startLine: number;
 }
 
+/**
+ * Where a function declared without a body is implemented.
+ */
+export interface GoExternalImplementation {
+  /** `linkname` when a //go:linkname directive names it, `assembly` (a `.s` file) otherwise */
+  kind: "assembly" | "linkname";
+  /** Symbol the declaration is linked to, e.g. "runtime.nanotime" */
+  target?: string;
+}
+
+/**
+ * A keyed composite literal, e.g. `Config{Host: "localhost", Port: 8080}`.
+ */
+export interface GoCompositeLiteral {
+  type: string;
+  /** Field values keyed by field name, as source expressions */
+  fields: Record<string, string>;
+}
+
+/**
+ * A default value a constructor assigns to a struct field.
+ */
+export interface GoFieldDefault {
+  /** Source expression, e.g. `"localhost"` */
+  value: string;
+  /** Function returning the literal, e.g. `WithDefaults` */
+  constructor: string;
+}
+
 /**
  * Represents a struct field.
  */
@@ -45,7 +139,11 @@ export interface GoField {
   name: string;
   doc?: string;
   type: string;
+  /** Structured type, for anonymous struct and interface literals */
+  typeExpr?: GoTypeExpr;
   tag?: string;
+  /** Constant values assigned by constructors that return a literal of the struct */
+  defaults?: GoFieldDefault[];
   startLine: number;
 }
 
@@ -57,6 +155,23 @@ export interface GoParameter {
   type: string;
 }
 
+/**
+ * Represents a type parameter of a generic type or function.
+ */
+export interface GoTypeParam {
+  name: string;
+  constraint: string;
+}
+
+/**
+ * Parameters and results of a function literal, e.g. the value of
+ * `var DefaultHandler = func(ctx context.Context) error { ... }`.
+ */
+export interface GoFuncLiteral {
+  parameters: GoParameter[];
+  returns: string;
+}
+
 /**
  * Represents a constant or variable.
  */
@@ -65,66 +180,637 @@ export interface GoConst {
   kind: "const" | "var";
   doc?: string;
   type?: string;
+  /** Source expression of the value */
   value?: string;
+  /** Folded value of a constant expression */
+  evaluated?: GoConstValue;
+  /** Signature of the function literal a variable is initialized with */
+  funcLiteral?: GoFuncLiteral;
+  /** Index of the constant's line in a `const (...)` block, the value of `iota` */
+  iota?: number;
+  sourceFile: string;
+  startLine: number;
+}
+
+/**
+ * An `ExampleXxx` function of a test file.
+ */
+export interface GoExample {
+  /** Function name, e.g. "ExampleClient_Get_retry" */
+  name: string;
+  /** Documented symbol, e.g. "Client.Get"; unset for package examples */
+  target?: string;
+  /** Lowercase name suffix telling several examples of a symbol apart */
+  suffix?: string;
+  doc?: string;
+  /** Function body, dedented, without the output comment */
+  code: string;
+  /** Expected output from the `// Output:` comment */
+  output?: string;
+  /** Set for `// Unordered output:` comments */
+  unordered?: boolean;
+  /** The code is the whole file: the example needs its imports and helper declarations */
+  wholeFile?: boolean;
+  sourceFile: string;
+  startLine: number;
+}
+
+/**
+ * A `BenchmarkXxx` or `FuzzXxx` function of a test file.
+ */
+export interface GoTestFunction {
+  kind: "benchmark" | "fuzz";
+  /** Function name, e.g. "BenchmarkClient_Get_parallel" */
+  name: string;
+  /** Symbol the name points to, e.g. "Client.Get"; unset for names like "BenchmarkDecode" */
+  target?: string;
+  doc?: string;
+  /** Names the body refers to: `pkg.Name` selectors and bare `Name(` or `Name{` uses */
+  references: string[];
   sourceFile: string;
   startLine: number;
 }
 
+/**
+ * A go/doc note: a `MARKER(uid): body` comment such as `// BUG(rsc): ...`.
+ */
+export interface GoNote {
+  marker: string;
+  /** Who the note is about or by, e.g. a username */
+  uid: string;
+  body: string;
+  sourceFile: string;
+  line: number;
+}
+
+/**
+ * Owners of the package and of each source file.
+ */
+export interface GoOwnership {
+  packageOwners: string[];
+  /** Owners keyed by source file path relative to the package path */
+  fileOwners: Record<string, string[]>;
+}
+
 /**
  * Result of extraction from all Go files.
  */
 export interface ExtractionResult {
   packageName: string;
   moduleName: string;
+  /** Package doc comment */
+  packageDoc?: string;
   types: GoType[];
   functions: GoMethod[];
   constants: GoConst[];
   version: string;
+  ownership?: GoOwnership;
+  /** Packages skipped because they are identical to a canonical package */
+  duplicates?: PackageDuplicate[];
+  /** Modules listed in vendor/modules.txt, when vendored packages are extracted */
+  vendorModules?: VendorModule[];
+  /** Generated source files, relative to the package path, when their symbols are kept */
+  generatedFiles?: string[];
+  /** Import paths by package name, per source file relative to the package path */
+  imports?: Record<string, Record<string, string>>;
+  /** Example functions of test files */
+  examples?: GoExample[];
+  /** Benchmark and fuzz functions of test files, when collected */
+  testFunctions?: GoTestFunction[];
+  /** Notes with the configured markers, in file order */
+  notes?: GoNote[];
+  /** `//go:build` constraints, per source file relative to the package path */
+  buildConstraints?: Record<string, string>;
+  /** Go package names (of the package clauses), per directory relative to the package path */
+  packageNames?: Record<string, string>;
+  /** Markdown files of the sidecar docs directory, by file name without `.md` */
+  sidecarDocs?: Record<string, SidecarDoc>;
+  /** Problems that left packages incomplete, in canonical order */
+  errors?: ExtractionError[];
+}
+
+/**
+ * Declarations parsed from a single source file.
+ */
+export interface ParsedFile {
+  /** Name in the package clause */
+  packageName?: string;
+  packageDoc?: string;
+  types: GoType[];
+  functions: GoMethod[];
+  constants: GoConst[];
+  /** Receiver type and method name of every method, exported or not */
+  receiverMethods: Array<[string, string]>;
+  /** Whether the file has a `// Code generated ... DO NOT EDIT.` line */
+  generated?: boolean;
+  /** Exported methods of unexported types, which embedding structs promote */
+  unexportedReceiverMethods?: GoMethod[];
+  /** Interface and type of each `var _ I = (*T)(nil)` assertion */
+  assertions?: Array<[string, string]>;
+  /** Import paths by the name the file refers to them with */
+  imports?: Record<string, string>;
+  examples?: GoExample[];
+  testFunctions?: GoTestFunction[];
+  notes?: GoNote[];
+  /** Expression of the file's `//go:build` line */
+  buildConstraint?: string;
+}
+
+/**
+ * A package looked up in the package cache: its key, and its cached files,
+ * or the files parsed in this run to store under the key.
+ */
+interface PackageCacheLookup {
+  key: string;
+  files?: CachedPackage;
+  parsed: CachedPackage;
+}
+
+/**
+ * The canonical header of generated Go files, which must come before the
+ * first non-comment, non-blank text.
+ */
+const GENERATED_HEADER = /^\/\/ Code generated .* DO NOT EDIT\.$/m;
+
+/**
+ * Names `go test` runs rather than exports: TestXxx, BenchmarkXxx, and so on.
+ */
+const TEST_FUNCTION = /^(Test|Benchmark|Example|Fuzz)($|[^a-z])/;
+
+/**
+ * Example functions: `Example`, `ExampleF`, `ExampleT`, `ExampleT_M`, each
+ * optionally followed by a lowercase `_suffix`.
+ */
+const EXAMPLE_FUNCTION = /^func\s+(Example(?:[A-Z_]\w*)?)\s*\(\s*\)\s*\{/gm;
+
+/**
+ * The comment of an example's expected output.
+ */
+const EXAMPLE_OUTPUT = /^[ \t]*\/\/[ \t]*(Output|Unordered output):(.*)$/gim;
+
+/**
+ * Benchmark and fuzz functions: `BenchmarkXxx(b *testing.B)` and
+ * `FuzzXxx(f *testing.F)`.
+ */
+const TEST_REFERENCE_FUNCTION =
+  /^func\s+((Benchmark|Fuzz)(?:[A-Z_]\w*)?)\s*\(\s*\w+\s+\*testing\.[BF]\s*\)\s*\{/gm;
+
+/**
+ * Directive comments, which are not part of the doc text (as in go/ast):
+ * `//go:noinline`, `//nolint:errcheck`, `//export Name`, `//line file:1`.
+ */
+const DIRECTIVE = /^\/\/(?:line |extern |export |[a-z0-9]+:[a-z0-9]|nolint\b)/;
+
+/**
+ * The first line of a note: `MARKER(uid): body`, the colon being optional.
+ */
+const NOTE = /^([A-Z][A-Z]+)\(([^)]+)\):?[ \t]*(.*)$/;
+
+/**
+ * Interface-conformance assertions, standalone or in a `var (...)` block:
+ * `var _ I = (*T)(nil)`, `var _ I = &T{}`, and `var _ I = T{}`.
+ */
+const ASSERTION =
+  /^[ \t]*(?:var\s+)?_\s+([\w.]+)(?:\[[^\]\n]*\])?\s*=\s*(?:\(\s*\*\s*(\w+)(?:\[[^\]\n]*\])?\s*\)\s*\(\s*nil\s*\)|&?(\w+)(?:\[[^\]\n]*\])?\s*\{\s*\})/gm;
+
+/**
+ * Key of a type in maps that span the module's packages, e.g. method sets:
+ * its package directory and name.
+ */
+function typeKey(dir: string, name: string): string {
+  return `${dir}:${name}`;
 }
 
+/**
+ * The config fields parsing a file reads, besides the package path, which
+ * key its cached results. Keep in step with `extractFile` and the methods it
+ * calls.
+ */
+const parseConfigFields = [
+  "exportedOnly",
+  "testHelpers",
+  "examples",
+  "testFunctions",
+  "noteMarkers",
+] as const satisfies ReadonlyArray<keyof GoExtractorConfig>;
+
 /**
  * Go source file extractor.
  */
 export class GoExtractor {
   private config: GoExtractorConfig;
-
-  constructor(config: GoExtractorConfig) {
+  private timings?: TimingRecorder;
+  private cache?: ParseCache<ParsedFile>;
+  private progress?: ProgressReporter;
+  private packageCache?: PackageCache;
+  private pool?: WorkerPool<string, ParsedFile>;
+
+  constructor(
+    config: GoExtractorConfig,
+    timings?: TimingRecorder,
+    cache?: ParseCache<ParsedFile>,
+    progress?: ProgressReporter,
+    packageCache?: PackageCache,
+  ) {
     this.config = config;
+    this.timings = timings;
+    this.cache = cache;
+    this.progress = progress;
+    this.packageCache = packageCache;
   }
 
   /**
    * Extract all Go symbols from the source directory.
    */
   async extract(): Promise<ExtractionResult> {
-    const files = await this.findGoFiles();
+    const { files: found, duplicates } = await this.dedupePackages(await this.findGoFiles());
+    // With a build target, files named for another target aren't parsed, and
+    // files whose //go:build constraint doesn't hold are left out once parsed
+    const target = buildTarget(this.config);
+    const files = target ? found.filter((file) => matchFileName(file, target)) : found;
     const types: GoType[] = [];
     const functions: GoMethod[] = [];
     const constants: GoConst[] = [];
+    // Method sets by package directory and receiver type
+    const receiverMethods = new Map<string, Set<string>>();
+    const unexportedReceiverMethods: GoMethod[] = [];
+    const assertions: Array<[string, string]> = [];
+    const packageDocs: Array<{ file: string; doc: string }> = [];
+    const generatedFiles: string[] = [];
+    const imports: Record<string, Record<string, string>> = {};
+    const examples: GoExample[] = [];
+    const testFunctions: GoTestFunction[] = [];
+    const notes: GoNote[] = [];
+    const buildConstraints: Record<string, string> = {};
+    const packageNames: Record<string, string> = {};
+    const errors: ExtractionError[] = [];
     let moduleName = "";
 
     // Try to get module name from go.mod
     moduleName = await this.detectModuleName();
 
+    // A package completes once all of its files are parsed
+    const remaining = new Map<string, number>();
     for (const file of files) {
-      try {
-        const fileResult = await this.extractFile(file);
+      const dir = this.packageDirOf(file);
+      remaining.set(dir, (remaining.get(dir) ?? 0) + 1);
+    }
+    this.progress?.start(remaining.size);
+    const cachedPackages = await this.readPackageCache(files);
+
+    // With workers, files are parsed up to a window ahead on worker threads,
+    // and merged in file order below, whichever worker finishes first. Time
+    // and memory limits need workers, which can be stopped.
+    const workers = this.config.workers ?? 1;
+    const limited = this.config.packageTimeoutMs !== undefined || this.config.maxMemoryMb;
+    this.pool = workers > 1 || limited ? this.startPool(workers) : undefined;
+    const window = workers > 1 ? workers * 4 : 1;
+    // A package's files share its time budget: each may take what the ones
+    // before it left, and one whose files took longer in total is skipped
+    const budget = this.config.packageTimeoutMs;
+    const spent = new Map<string, number>();
+    const timedOut = `took longer than ${budget} ms to parse; skipped the package`;
+    const parse = (file: string) => {
+      if (budget === undefined) return this.parseFile(file);
+      const dir = this.packageDirOf(file);
+      let start: number | undefined;
+      // Called as the file starts parsing on a worker
+      const timeout = () => {
+        start = performance.now();
+        return budget - (spent.get(dir) ?? 0);
+      };
+      return this.parseFile(file, timeout).finally(() => {
+        if (start === undefined) return;
+        spent.set(dir, (spent.get(dir) ?? 0) + performance.now() - start);
+      });
+    };
+    const parsing = new Map<string, Promise<ParsedFile>>();
+    let next = 0;
+    const parseAhead = () => {
+      while (next < files.length && parsing.size < window) {
+        const ahead = files[next++];
+        const cached = cachedPackages.get(this.packageDirOf(ahead))?.files;
+        if (cached?.[relative(this.config.packagePath, ahead)]) continue;
+        const parsed = parse(ahead);
+        // Failures are reported when the file's turn comes
+        parsed.catch(() => {});
+        parsing.set(ahead, parsed);
+      }
+    };
+
+    const merge = (file: string, fileResult: ParsedFile) => {
+      const dir = this.packageDirOf(file);
+      const relativePath = relative(this.config.packagePath, file);
+      if (target && fileResult.buildConstraint) {
+        try {
+          if (!matchBuildConstraint(fileResult.buildConstraint, target)) return;
+        } catch (error) {
+          const location = { package: dir, file: relativePath.replace(/\\/g, "/") };
+          errors.push({ kind: "parse", ...location, message: String(error) });
+          return;
+        }
+      }
+
+      // Generated files still implement interfaces, so keep their method sets
+      const excluded = fileResult.generated && this.config.generatedFiles === "exclude";
+      if (!excluded) {
         types.push(...fileResult.types);
         functions.push(...fileResult.functions);
         constants.push(...fileResult.constants);
-      } catch (error) {
-        console.warn(`Warning: Failed to parse ${file}: ${error}`);
+        if (fileResult.packageDoc) {
+          packageDocs.push({ file, doc: fileResult.packageDoc });
+        }
+        if (fileResult.generated) {
+          generatedFiles.push(relativePath);
+        }
+        if (fileResult.imports) {
+          imports[relativePath] = fileResult.imports;
+        }
+        if (fileResult.buildConstraint) {
+          buildConstraints[relativePath] = fileResult.buildConstraint;
+        }
+        if (fileResult.packageName && !relativePath.endsWith("_test.go")) {
+          const declared = packageNames[dir || "."];
+          const ignored = /\bignore\b/.test(fileResult.buildConstraint ?? "");
+          if (declared && declared !== fileResult.packageName && !ignored) {
+            errors.push({
+              kind: "package",
+              package: dir,
+              file: relativePath,
+              message: `declares package ${fileResult.packageName}, not ${declared}`,
+            });
+          } else if (!ignored) {
+            packageNames[dir || "."] = fileResult.packageName;
+          }
+        }
+        unexportedReceiverMethods.push(...(fileResult.unexportedReceiverMethods ?? []));
+      }
+      for (const [receiver, method] of fileResult.receiverMethods) {
+        const key = typeKey(dir, receiver);
+        receiverMethods.set(key, (receiverMethods.get(key) ?? new Set()).add(method));
+      }
+      assertions.push(...(fileResult.assertions ?? []));
+      examples.push(...(fileResult.examples ?? []));
+      testFunctions.push(...(fileResult.testFunctions ?? []));
+      if (!excluded) {
+        notes.push(...(fileResult.notes ?? []));
+      }
+    };
+
+    // Packages that hit a limit are skipped entirely, so parsed files are held
+    // until their package is done, and merged in file order from there
+    const parsedFiles = new Map<string, ParsedFile>();
+    const skipped = new Map<string, ExtractionError>();
+    let merged = 0;
+    for (const file of files) {
+      const dir = this.packageDirOf(file);
+      this.progress?.begin(dir);
+      const cachedPackage = cachedPackages.get(dir);
+      const relativePath = relative(this.config.packagePath, file);
+      let fileResult = cachedPackage?.files?.[relativePath];
+      if (!fileResult) {
+        parseAhead();
+        const parsed = parsing.get(file) ?? parse(file);
+        parsing.delete(file);
+        try {
+          // With workers, the time this file held up the merge
+          const start = performance.now();
+          fileResult = await parsed;
+          this.timings?.recordFile(relativePath, "parse", performance.now() - start);
+          if (cachedPackage && !cachedPackage.files) {
+            cachedPackage.parsed[relativePath] = fileResult;
+          }
+        } catch (error) {
+          const location = { package: dir, file: relativePath.replace(/\\/g, "/") };
+          if (error instanceof WorkerLimitError) {
+            const message =
+              error.limit === "timeout" ? timedOut : `${error.message}; skipped the package`;
+            skipped.set(dir, { kind: error.limit, ...location, message });
+          } else {
+            errors.push({ kind: "parse", ...location, message: String(error) });
+          }
+          // Packages with files that failed to parse aren't stored
+          cachedPackages.delete(dir);
+        }
       }
+      if (fileResult) {
+        parsedFiles.set(file, fileResult);
+      }
+      remaining.set(dir, remaining.get(dir)! - 1);
+      if (remaining.get(dir) === 0) {
+        // Files parsing at the same time can overrun the budget together
+        if (budget !== undefined && (spent.get(dir) ?? 0) > budget && !skipped.has(dir)) {
+          skipped.set(dir, { kind: "timeout", package: dir, message: timedOut });
+          cachedPackages.delete(dir);
+        }
+        // Stored before cross-file resolution changes the parsed declarations
+        if (cachedPackage && !cachedPackage.files && cachedPackages.has(dir)) {
+          await this.packageCache!.set(cachedPackage.key, cachedPackage.parsed);
+        }
+        this.progress?.complete();
+      }
+      for (; merged < files.length; merged++) {
+        const done = files[merged];
+        const doneDir = this.packageDirOf(done);
+        if (remaining.get(doneDir)! > 0) break;
+        const doneResult = parsedFiles.get(done);
+        parsedFiles.delete(done);
+        if (doneResult && !skipped.has(doneDir)) merge(done, doneResult);
+      }
+    }
+    errors.push(...skipped.values());
+    this.progress?.finish();
+    await this.pool?.close();
+    this.pool = undefined;
+    if (this.packageCache) {
+      const { hits, misses } = this.packageCache.stats();
+      logger.debug(`Package cache: ${hits} package(s) reused, ${misses} parsed`);
     }
 
+    const packageDoc = this.selectPackageDoc(packageDocs);
+    const typecheckStart = performance.now();
+    this.promoteEmbeddedMethods(types, unexportedReceiverMethods, receiverMethods);
+    this.resolveSealedInterfaces(types, receiverMethods);
+    this.applyAssertions(types, assertions);
+
+    // Evaluate with unexported constants in scope, then drop them if configured
+    this.evaluateConstants(constants, moduleName, imports);
+    this.resolveFieldDefaults(types, functions, constants);
+    this.timings?.recordRun("typecheck", performance.now() - typecheckStart);
+    const visibleConstants = this.config.exportedOnly
+      ? constants.filter((c) => this.isExported(c.name))
+      : constants;
+
     const version = await this.detectVersion();
+    const ownership = await this.resolveOwnership(files);
+    const vendorModules = await this.readVendorModules();
+    const sidecarDocs = this.config.sidecarDocs
+      ? await loadSidecarDocs(this.config.packagePath, this.config.sidecarDocs)
+      : undefined;
+    errors.push(...(await this.findUnresolvedImports(moduleName, imports)));
+    for (const error of sortExtractionErrors(errors)) {
+      logger.error(formatExtractionError(error), { ...error });
+    }
 
     return {
       packageName: this.config.packageName,
       moduleName,
+      packageDoc,
       types,
       functions,
-      constants,
+      constants: visibleConstants,
       version,
+      ownership,
+      duplicates: duplicates.length > 0 ? duplicates : undefined,
+      vendorModules,
+      generatedFiles: generatedFiles.length > 0 ? generatedFiles : undefined,
+      imports: Object.keys(imports).length > 0 ? imports : undefined,
+      examples: examples.length > 0 ? examples : undefined,
+      testFunctions: testFunctions.length > 0 ? testFunctions : undefined,
+      notes: notes.length > 0 ? notes : undefined,
+      buildConstraints: Object.keys(buildConstraints).length > 0 ? buildConstraints : undefined,
+      packageNames: Object.keys(packageNames).length > 0 ? packageNames : undefined,
+      sidecarDocs,
+      errors: errors.length > 0 ? errors : undefined,
+    };
+  }
+
+  /**
+   * Find imports of the module's own packages that don't exist, that is,
+   * with no directory of Go files at their path. Without a go.mod, the
+   * module path is unknown and nothing is checked.
+   */
+  private async findUnresolvedImports(
+    moduleName: string,
+    imports: Record<string, Record<string, string>>,
+  ): Promise<ExtractionError[]> {
+    try {
+      await stat(join(this.config.packagePath, "go.mod"));
+    } catch {
+      return [];
+    }
+
+    const resolved = new Map<string, Promise<boolean>>();
+    const errors: ExtractionError[] = [];
+    for (const [file, fileImports] of Object.entries(imports)) {
+      for (const importPath of new Set(Object.values(fileImports))) {
+        if (!importPath.startsWith(`${moduleName}/`)) continue;
+        const dir = importPath.slice(moduleName.length + 1);
+        if (!resolved.has(dir)) resolved.set(dir, this.hasGoFiles(dir));
+        if (await resolved.get(dir)) continue;
+        errors.push({
+          kind: "import",
+          package: this.packageDirOf(join(this.config.packagePath, file)),
+          file: file.replace(/\\/g, "/"),
+          message: `imports ${importPath}, which doesn't exist`,
+        });
+      }
+    }
+    return errors;
+  }
+
+  /**
+   * Whether a directory relative to the package path has Go files.
+   */
+  private async hasGoFiles(dir: string): Promise<boolean> {
+    try {
+      const entries = await readdir(join(this.config.packagePath, dir));
+      return entries.some((entry) => entry.endsWith(".go"));
+    } catch {
+      return false;
+    }
+  }
+
+  /**
+   * Pick the package overview among the package comments found. The root
+   * package's `doc.go` wins, as that is where Go packages keep their overview;
+   * otherwise the first root file's comment, then the first comment anywhere.
+   */
+  private selectPackageDoc(docs: Array<{ file: string; doc: string }>): string | undefined {
+    const root = docs.filter(({ file }) => this.packageDirOf(file) === "");
+    const docFile = root.find(({ file }) => basename(file) === "doc.go");
+    return (docFile ?? root[0] ?? docs[0])?.doc;
+  }
+
+  /**
+   * With `dedupePackages`, drop the files of packages that are byte-identical
+   * to another package, keeping only the canonical copy. Files are only read
+   * for it when it's on.
+   */
+  private async dedupePackages(
+    files: string[],
+  ): Promise<{ files: string[]; duplicates: PackageDuplicate[] }> {
+    if (!this.config.dedupePackages) {
+      return { files, duplicates: [] };
+    }
+
+    const filesByDir = new Map<string, string[]>();
+    for (const file of files) {
+      const dir = this.packageDirOf(file);
+      filesByDir.set(dir, [...(filesByDir.get(dir) ?? []), file]);
+    }
+
+    const packages: PackageDirectory[] = [];
+    for (const [dir, dirFiles] of filesByDir) {
+      const contents = await Promise.all(
+        dirFiles.map(async (file) => ({
+          name: basename(file),
+          content: await readFile(file, "utf-8"),
+        })),
+      );
+      packages.push({
+        dir,
+        files: dirFiles.map((file) => relative(this.config.packagePath, file)),
+        contentHash: hashPackageFiles(contents),
+      });
+    }
+
+    const duplicates = findDuplicatePackages(packages);
+    const aliasDirs = new Set(duplicates.flatMap((d) => d.aliases));
+
+    return {
+      files: files.filter((file) => !aliasDirs.has(this.packageDirOf(file))),
+      duplicates,
+    };
+  }
+
+  /**
+   * Get the directory of a file relative to the package path ("" for the root).
+   */
+  private packageDirOf(file: string): string {
+    const dir = dirname(relative(this.config.packagePath, file)).replace(/\\/g, "/");
+    return dir === "." ? "" : dir;
+  }
+
+  /**
+   * Get the package directory of a declaration, from its source file.
+   */
+  private declDirOf(decl: { sourceFile: string }): string {
+    return this.packageDirOf(join(this.config.packagePath, decl.sourceFile));
+  }
+
+  /**
+   * Resolve package and per-file owners from the configured mapping or CODEOWNERS.
+   */
+  private async resolveOwnership(files: string[]): Promise<GoOwnership | undefined> {
+    const ownership = await loadOwnership(this.config.packagePath, {
+      owners: this.config.owners,
+      ownersFile: this.config.ownersFile,
+    });
+    if (!ownership) return undefined;
+
+    const { resolver, pathPrefix } = ownership;
+    const withPrefix = (path: string) => (pathPrefix ? `${pathPrefix}/${path}` : path);
+
+    const fileOwners: Record<string, string[]> = {};
+    for (const file of files) {
+      const relativePath = relative(this.config.packagePath, file);
+      fileOwners[relativePath] = resolver.ownersOf(withPrefix(relativePath));
+    }
+
+    return {
+      packageOwners: resolver.ownersOf(pathPrefix ? `${pathPrefix}/` : ""),
+      fileOwners,
     };
   }
 
@@ -132,39 +818,318 @@ export class GoExtractor {
    * Find all Go files matching the patterns.
    */
   private async findGoFiles(): Promise<string[]> {
+    const vendored = this.config.vendoredPackages === "dependencies";
+    const tests =
+      this.config.testHelpers || this.config.examples !== false || this.config.testFunctions;
+    const lifted = [vendored && "**/vendor/**", tests && "**/*_test.go"];
     const files = await glob(this.config.includePatterns, {
       cwd: this.config.packagePath,
-      ignore: this.config.excludePatterns,
+      ignore: this.config.excludePatterns.filter((pattern) => !lifted.includes(pattern)),
       absolute: true,
     });
 
-    return files.filter((f) => f.endsWith(".go"));
+    return files
+      .filter(
+        (f) =>
+          f.endsWith(".go") &&
+          (vendored || !vendorImportPath(relative(this.config.packagePath, f).replace(/\\/g, "/"))),
+      )
+      .sort(compareCanonical);
+  }
+
+  /**
+   * Read the modules listed in `vendor/modules.txt`, when vendored packages
+   * are extracted.
+   */
+  private async readVendorModules(): Promise<VendorModule[] | undefined> {
+    if (this.config.vendoredPackages !== "dependencies") return undefined;
+    try {
+      const modulesTxt = join(this.config.packagePath, "vendor", "modules.txt");
+      return parseModulesTxt(await readFile(modulesTxt, "utf-8"));
+    } catch {
+      return undefined;
+    }
+  }
+
+  /**
+   * Look up every package of the files in the package cache. Packages that
+   * hit come with their parsed files; the others with their key and an
+   * empty record of parsed files to store under it.
+   */
+  private async readPackageCache(files: string[]): Promise<Map<string, PackageCacheLookup>> {
+    const packages = new Map<string, PackageCacheLookup>();
+    if (!this.packageCache) return packages;
+
+    const sources = new Map<string, Array<{ file: string; content: string }>>();
+    for (const file of files) {
+      const dir = this.packageDirOf(file);
+      const content = await readFile(file, "utf-8");
+      const entry = { file: relative(this.config.packagePath, file), content };
+      sources.set(dir, [...(sources.get(dir) ?? []), entry]);
+    }
+    for (const [dir, packageSources] of sources) {
+      const key = this.packageCache.key(packageSources, this.parseOptions());
+      const cached = await this.packageCache.get(key);
+      packages.set(dir, { key, files: cached, parsed: {} });
+    }
+    return packages;
+  }
+
+  /**
+   * The options that change what parsing a file yields.
+   */
+  private parseOptions(): unknown[] {
+    return parseConfigFields.map((field) => this.config[field]);
+  }
+
+  /**
+   * Start a pool of parse workers, sharing the memory budget.
+   */
+  private startPool(size: number): WorkerPool<string, ParsedFile> {
+    const { maxMemoryMb } = this.config;
+    const resourceLimits = maxMemoryMb
+      ? { maxOldGenerationSizeMb: workerMemoryLimit(maxMemoryMb, size) }
+      : undefined;
+    return new WorkerPool(
+      size,
+      () => new Worker(parseWorkerUrl, { workerData: this.config, resourceLimits }),
+    );
+  }
+
+  /**
+   * Parse a single file, without the caches or workers. Parse workers call
+   * this.
+   */
+  async parseSourceFile(filePath: string): Promise<ParsedFile> {
+    return this.extractFile(filePath);
+  }
+
+  /**
+   * Parse a file, reusing the cached result while the file and the options
+   * that affect parsing are unchanged. Callers get their own copy, since
+   * cross-file resolution mutates the parsed declarations.
+   */
+  private async parseFile(filePath: string, timeoutMs?: () => number): Promise<ParsedFile> {
+    const parse = () =>
+      this.pool ? this.pool.run(filePath, timeoutMs) : this.extractFile(filePath);
+    if (!this.cache) {
+      return parse();
+    }
+
+    const { mtimeMs, size } = await stat(filePath);
+    const options = JSON.stringify(this.parseOptions());
+    const key = [mtimeMs, size, this.config.packagePath, options].join("\0");
+    const cached = this.cache.get(filePath, key);
+    if (cached) {
+      return structuredClone(cached);
+    }
+
+    const parsed = await parse();
+    this.cache.set(filePath, key, structuredClone(parsed));
+    return parsed;
   }
 
   /**
    * Extract symbols from a single Go file.
    */
-  private async extractFile(
-    filePath: string,
-  ): Promise<{ types: GoType[]; functions: GoMethod[]; constants: GoConst[] }> {
+  private async extractFile(filePath: string): Promise<ParsedFile> {
     const content = await readFile(filePath, "utf-8");
     const relativePath = relative(this.config.packagePath, filePath);
 
     // Extract package name
     const packageMatch = content.match(/^package\s+(\w+)/m);
     const packageName = packageMatch ? packageMatch[1] : "";
+    const testFile = filePath.endsWith("_test.go");
+    const examples =
+      testFile && this.config.examples !== false ? this.extractExamples(content, relativePath) : [];
+    const testFunctions =
+      testFile && this.config.testFunctions ? this.extractTestFunctions(content, relativePath) : [];
+    // External test packages (package foo_test) can't export anything to foo,
+    // and other test files only export testing helpers. Examples keep their
+    // imports, which tell how they refer to the package.
+    if (testFile && (packageName.endsWith("_test") || !this.config.testHelpers)) {
+      const imports =
+        examples.length > 0 || testFunctions.length > 0
+          ? parseImports(content)
+          : new Map<string, string>();
+      return {
+        packageName,
+        types: [],
+        functions: [],
+        constants: [],
+        receiverMethods: [],
+        imports: imports.size > 0 ? Object.fromEntries(imports) : undefined,
+        examples: examples.length > 0 ? examples : undefined,
+        testFunctions: testFunctions.length > 0 ? testFunctions : undefined,
+      };
+    }
+    const packageDoc =
+      packageMatch && !testFile ? this.extractDocBefore(content, packageMatch.index!) : undefined;
 
     const types = this.extractTypes(content, packageName, relativePath);
     const functions = this.extractFunctions(content, relativePath);
     const constants = this.extractConstants(content, relativePath);
+    const receiverMethods = this.extractReceiverMethods(content);
 
     // Associate methods with types
     this.associateMethodsWithTypes(types, functions);
 
-    // Filter to only top-level functions (not methods)
-    const topLevelFunctions = functions.filter((f) => !f.receiver);
+    // Filter to only top-level functions (not methods), and drop the tests
+    // themselves from test files
+    const topLevelFunctions = functions.filter(
+      (f) => !f.receiver && !(testFile && TEST_FUNCTION.test(f.name)),
+    );
+
+    // The header must precede the package clause
+    const header = content.slice(0, packageMatch?.index ?? 0);
+    const generated = GENERATED_HEADER.test(header);
+    const buildConstraint = header.match(/^\/\/go:build[ \t]+(.+)$/m)?.[1].trim();
+    const unexportedReceiverMethods = functions.filter(
+      (f) => f.receiverType && !this.isExported(f.receiverType),
+    );
+    const assertions = Array.from(
+      content.matchAll(ASSERTION),
+      (m) => [m[1], m[2] ?? m[3]] as [string, string],
+    );
+    const imports = parseImports(content);
+    const notes = testFile ? [] : this.extractNotes(content, relativePath);
+
+    return {
+      packageName,
+      packageDoc,
+      types,
+      functions: topLevelFunctions,
+      constants,
+      receiverMethods,
+      generated,
+      unexportedReceiverMethods:
+        unexportedReceiverMethods.length > 0 ? unexportedReceiverMethods : undefined,
+      assertions: assertions.length > 0 ? assertions : undefined,
+      imports: imports.size > 0 ? Object.fromEntries(imports) : undefined,
+      examples: examples.length > 0 ? examples : undefined,
+      testFunctions: testFunctions.length > 0 ? testFunctions : undefined,
+      notes: notes.length > 0 ? notes : undefined,
+      buildConstraint,
+    };
+  }
+
+  /**
+   * Add the exported methods of embedded unexported types to the structs
+   * embedding them, unless the struct declares a method of the same name or
+   * several embedded types provide it (which makes the selector ambiguous).
+   * Method sets are extended too, so promoted methods count toward
+   * implementing sealed interfaces. Embedded types are looked up in the
+   * struct's own package.
+   */
+  private promoteEmbeddedMethods(
+    types: GoType[],
+    unexportedReceiverMethods: GoMethod[],
+    receiverMethods: Map<string, Set<string>>,
+  ): void {
+    for (const type of types) {
+      if (!type.embedsUnexported) continue;
+
+      const dir = this.declDirOf(type);
+      const declared = new Set(type.methods.map((m) => m.name));
+      const candidates = type.embedsUnexported.flatMap((embed) =>
+        unexportedReceiverMethods.filter(
+          (m) => m.receiverType === embed && this.declDirOf(m) === dir,
+        ),
+      );
+      const providers = new Map<string, number>();
+      for (const method of candidates) {
+        providers.set(method.name, (providers.get(method.name) ?? 0) + 1);
+      }
+
+      for (const method of candidates) {
+        if (declared.has(method.name) || providers.get(method.name)! > 1) continue;
+        type.methods.push({ ...method, promotedFrom: method.receiverType });
+      }
+
+      const key = typeKey(dir, type.name);
+      const methodSet = receiverMethods.get(key) ?? new Set<string>();
+      for (const embed of type.embedsUnexported) {
+        for (const name of receiverMethods.get(typeKey(dir, embed)) ?? []) methodSet.add(name);
+      }
+      receiverMethods.set(key, methodSet);
+    }
+  }
+
+  /**
+   * List the method names declared on each receiver type, exported or not.
+   */
+  private extractReceiverMethods(content: string): Array<[string, string]> {
+    const methodPattern = /\bfunc\s+\(\s*(?:\w+\s+)?\*?(\w+)(?:\[[^\]]*\])?\s*\)\s*(\w+)\s*[[(]/g;
+    return Array.from(content.matchAll(methodPattern), (m) => [m[1], m[2]] as [string, string]);
+  }
+
+  /**
+   * Mark interfaces that have unexported methods, directly or through an
+   * embedded in-package interface, as sealed and list the in-package types
+   * that implement them. `receiverMethods` is keyed by `typeKey`, so types
+   * and embeds of other packages with the same names don't match.
+   */
+  private resolveSealedInterfaces(
+    types: GoType[],
+    receiverMethods: Map<string, Set<string>>,
+  ): void {
+    const interfaces = new Map(
+      types
+        .filter((t) => t.interfaceMethods)
+        .map((t) => [typeKey(this.declDirOf(t), t.name), t] as [string, GoType]),
+    );
+
+    // Method set of an interface, following embedded in-package interfaces
+    const methodSet = (type: GoType, dir: string, seen = new Set<string>()): string[] => {
+      seen.add(type.name);
+      const embedded = (type.embeds ?? [])
+        .map((name) => interfaces.get(typeKey(dir, name)))
+        .filter((t): t is GoType => t !== undefined && !seen.has(t.name));
+      return [...type.interfaceMethods!, ...embedded.flatMap((t) => methodSet(t, dir, seen))];
+    };
+
+    for (const type of interfaces.values()) {
+      const dir = this.declDirOf(type);
+      const methods = methodSet(type, dir);
+      if (!methods.some((name) => !this.isExported(name))) continue;
+
+      const prefix = typeKey(dir, "");
+      type.sealed = true;
+      type.implementations = Array.from(receiverMethods)
+        .filter(([key]) => key.startsWith(prefix))
+        .filter(([, declared]) => methods.every((name) => declared.has(name)))
+        .map(([key]) => key.slice(prefix.length))
+        .sort();
+    }
+  }
 
-    return { types, functions: topLevelFunctions, constants };
+  /**
+   * Record compile-time interface assertions: each asserted type lists the
+   * interface in `implements`, and in-package interfaces list the type among
+   * their implementations. With the "only" policy, assertions replace the
+   * implementations inferred from method sets.
+   */
+  private applyAssertions(types: GoType[], assertions: Array<[string, string]>): void {
+    const policy = this.config.implementationAssertions ?? "merge";
+    if (policy === "ignore") return;
+
+    const byName = new Map(types.map((t) => [t.name, t] as [string, GoType]));
+    if (policy === "only") {
+      for (const type of types) {
+        if (type.implementations) type.implementations = [];
+      }
+    }
+
+    for (const [iface, name] of assertions) {
+      const type = byName.get(name);
+      if (type && !type.implements?.includes(iface)) {
+        type.implements = [...(type.implements ?? []), iface].sort();
+      }
+      const target = byName.get(iface);
+      if (target?.interfaceMethods && !target.implementations?.includes(name)) {
+        target.implementations = [...(target.implementations ?? []), name].sort();
+      }
+    }
   }
 
   /**
@@ -177,15 +1142,24 @@ export class GoExtractor {
     // type Name struct { ... }
     // type Name interface { ... }
     // type Name = OtherType
-    const typePattern = /\btype\s+([A-Z]\w*)\s+(struct|interface)\s*\{/g;
+    // type Name[T any] struct { ... }
+    const typePattern = /\btype\s+([A-Za-z_]\w*)/g;
+    const kindPattern = /\s+(struct|interface)\s*\{/y;
 
     let match;
     while ((match = typePattern.exec(content)) !== null) {
       const name = match[1];
-      const kind = match[2] as "struct" | "interface";
+      const { params: typeParamsStr, end } = this.scanTypeParams(
+        content,
+        match.index + match[0].length,
+      );
+      kindPattern.lastIndex = end;
+      const kindMatch = kindPattern.exec(content);
+      if (!kindMatch) continue;
+      const kind = kindMatch[1] as "struct" | "interface";
 
       // Skip unexported types if configured
-      if (this.config.exportedOnly && !this.isExported(name)) {
+      if (!this.isExtracted(name)) {
         continue;
       }
 
@@ -196,15 +1170,28 @@ export class GoExtractor {
       const doc = this.extractDocBefore(content, match.index);
 
       // Find the closing brace
-      const bodyStart = match.index + match[0].length - 1;
+      const bodyStart = kindPattern.lastIndex - 1;
       const bodyEnd = this.findClosingBrace(content, bodyStart);
       const body = content.substring(bodyStart + 1, bodyEnd);
 
       // Extract fields for structs
       const fields = kind === "struct" ? this.extractFields(body, lineNumber) : [];
+      const embedsUnexported = kind === "struct" ? this.extractUnexportedEmbeds(body) : [];
+      const structEmbeds = kind === "struct" ? this.extractExportedEmbeds(body) : [];
+
+      // Record the methods and local embeds of interfaces to detect sealed ones
+      const literal = kind === "interface" ? parseTypeExpr(`interface {${body}}`) : undefined;
+      const interfaceMethods =
+        literal?.kind === "interface" ? literal.methods.map((m) => m.name) : undefined;
+      const embeds =
+        literal?.kind === "interface"
+          ? literal.embeds.flatMap((e) => (e.kind === "named" && !e.package ? [e.name] : []))
+          : undefined;
 
       // Build signature
-      const signature = `type ${name} ${kind}`;
+      const signature = typeParamsStr
+        ? `type ${name}[${typeParamsStr}] ${kind}`
+        : `type ${name} ${kind}`;
 
       types.push({
         name,
@@ -212,21 +1199,44 @@ export class GoExtractor {
         packageName,
         doc,
         signature,
+        typeParams: typeParamsStr ? this.parseTypeParams(typeParamsStr) : undefined,
         methods: [],
         fields,
+        interfaceMethods,
+        interfaceBody: kind === "interface" ? body : undefined,
+        embeds,
+        embedsUnexported: embedsUnexported.length > 0 ? embedsUnexported : undefined,
+        structEmbeds: structEmbeds.length > 0 ? structEmbeds : undefined,
         sourceFile,
         startLine: lineNumber,
       });
     }
 
-    // Match type aliases - don't consume doc comments in pattern
-    const aliasPattern = /\btype\s+([A-Z]\w*)\s+=\s+(.+)/g;
+    // Match type aliases, including generic ones (Go 1.24):
+    // type Name = OtherType
+    // type Set[T comparable] = map[T]struct{}
+    const aliasPattern = /\btype\s+([A-Za-z_]\w*)/g;
+    const aliasedPattern = /\s*=\s*(.+)/y;
 
     while ((match = aliasPattern.exec(content)) !== null) {
       const name = match[1];
-      const aliasedType = match[2].trim();
+      const { params: typeParamsStr, end } = this.scanTypeParams(
+        content,
+        match.index + match[0].length,
+      );
+      aliasedPattern.lastIndex = end;
+      const aliased = aliasedPattern.exec(content);
+      if (!aliased) continue;
+      let aliasedType = this.stripLineComment(aliased[1]).trim();
+
+      // Struct and interface literals may span several lines
+      if (this.braceDepth(aliasedType) > 0) {
+        const start = aliasedPattern.lastIndex - aliased[1].length;
+        const end = this.findClosingBrace(content, content.indexOf("{", start));
+        aliasedType = formatTypeExpr(parseTypeExpr(content.slice(start, end + 1)));
+      }
 
-      if (this.config.exportedOnly && !this.isExported(name)) {
+      if (!this.isExtracted(name)) {
         continue;
       }
 
@@ -239,7 +1249,11 @@ export class GoExtractor {
         kind: "alias",
         packageName,
         doc,
-        signature: `type ${name} = ${aliasedType}`,
+        signature: typeParamsStr
+          ? `type ${name}[${typeParamsStr}] = ${aliasedType}`
+          : `type ${name} = ${aliasedType}`,
+        typeParams: typeParamsStr ? this.parseTypeParams(typeParamsStr) : undefined,
+        aliasOf: aliasedType,
         methods: [],
         fields: [],
         sourceFile,
@@ -253,23 +1267,37 @@ export class GoExtractor {
   /**
    * Extract function and method declarations.
    */
-  private extractFunctions(content: string, _sourceFile: string): GoMethod[] {
+  private extractFunctions(content: string, sourceFile: string): GoMethod[] {
     const functions: GoMethod[] = [];
 
     // Match function declarations - don't consume doc comments in pattern
     // func Name(params) returns
     // func (r *Receiver) Name(params) returns
-    const funcPattern = /\bfunc\s+(?:\((\w+)\s+(\*?\w+)\)\s+)?([A-Z]\w*)\s*\(([^)]*)\)\s*([^{]*)/g;
+    // func Name[T any](params) returns
+    const funcPattern = /\bfunc\s+(?:\((\w+)\s+(\*?\w+(?:\[[^\]]*\])?)\)\s+)?([A-Za-z_]\w*)/g;
+    const paramsPattern = /\s*\(/y;
+    const linknamePattern = /^\/\/go:linkname\s+(\w+)(?:[ \t]+(\S+))?/gm;
+    const linknames = new Map(Array.from(content.matchAll(linknamePattern), (m) => [m[1], m[2]]));
 
     let match;
     while ((match = funcPattern.exec(content)) !== null) {
       const receiverName = match[1];
       const receiverType = match[2];
       const name = match[3];
-      const paramsStr = match[4];
-      const returnsStr = match[5].trim();
+      const { params: typeParamsStr, end: nameEnd } = this.scanTypeParams(
+        content,
+        match.index + match[0].length,
+      );
+      paramsPattern.lastIndex = nameEnd;
+      if (!paramsPattern.test(content)) continue;
+      const {
+        params: paramsStr,
+        returns: returnsStr,
+        end,
+      } = this.scanSignature(content, paramsPattern.lastIndex - 1);
 
-      if (this.config.exportedOnly && !this.isExported(name)) {
+      // init functions run on import and can't be referred to
+      if (!this.isExtracted(name) || (!receiverName && name === "init")) {
         continue;
       }
 
@@ -285,7 +1313,9 @@ export class GoExtractor {
       if (receiverName && receiverType) {
         signature += `(${receiverName} ${receiverType}) `;
       }
-      signature += `${name}(${paramsStr})`;
+      signature += typeParamsStr
+        ? `${name}[${typeParamsStr}](${paramsStr})`
+        : `${name}(${paramsStr})`;
       if (returnsStr) {
         signature += ` ${returnsStr}`;
       }
@@ -295,9 +1325,21 @@ export class GoExtractor {
         doc,
         signature: signature.trim(),
         receiver: receiverName,
-        receiverType: receiverType?.replace(/^\*/, ""),
+        receiverType: receiverType?.replace(/^\*/, "").replace(/\[.*$/, ""),
+        typeParams: typeParamsStr ? this.parseTypeParams(typeParamsStr) : undefined,
         parameters,
         returns: returnsStr,
+        returnedLiteral:
+          !receiverName && content[end] === "{"
+            ? this.extractReturnedLiteral(content, end, returnsStr)
+            : undefined,
+        externalImplementation:
+          content[end] === "{"
+            ? undefined
+            : linknames.has(name)
+              ? { kind: "linkname", target: linknames.get(name) }
+              : { kind: "assembly" },
+        sourceFile,
         startLine: lineNumber,
       });
     }
@@ -305,42 +1347,369 @@ export class GoExtractor {
     return functions;
   }
 
+  /**
+   * Extract the example functions of a test file, with the symbol each
+   * documents (`ExampleClient_Get` documents `Client.Get`) and the expected
+   * output from its last `// Output:` comment. Like godoc, the only example
+   * of a file without tests that declares anything else is a whole-file
+   * example: its code is the file, with the imports and helpers it needs.
+   */
+  private extractExamples(content: string, sourceFile: string): GoExample[] {
+    const examples: GoExample[] = [];
+    const matches = Array.from(content.matchAll(EXAMPLE_FUNCTION));
+    const declarations = content.match(/^(?:func|type|var|const)\b/gm)?.length ?? 0;
+    const wholeFile =
+      matches.length === 1 &&
+      declarations > 1 &&
+      !/^func\s+(?:Test|Benchmark|Fuzz)(?:$|[^a-z])/m.test(content);
+
+    for (const match of matches) {
+      const open = match.index + match[0].length - 1;
+      const close = this.findClosingBrace(content, open);
+      let body = content.substring(open + 1, close);
+
+      let output: string | undefined;
+      let unordered: boolean | undefined;
+      const outputMatch = Array.from(body.matchAll(EXAMPLE_OUTPUT)).at(-1);
+      if (outputMatch) {
+        const rest = body.slice(outputMatch.index + outputMatch[0].length);
+        output = [outputMatch[2], ...rest.split("\n")]
+          .map((line) => line.trim().replace(/^\/\/ ?/, ""))
+          .join("\n")
+          .trim();
+        unordered = outputMatch[1].toLowerCase() === "unordered output" || undefined;
+        body = body.substring(0, outputMatch.index);
+      }
+
+      const parts = match[1].slice("Example".length).split("_");
+      const suffix = parts.length > 1 && /^[a-z]/.test(parts.at(-1)!) ? parts.pop() : undefined;
+      const target = parts.filter(Boolean).join(".");
+
+      examples.push({
+        name: match[1],
+        target: target || undefined,
+        suffix,
+        doc: this.extractDocBefore(content, match.index),
+        code: wholeFile
+          ? `${content.substring(0, open + 1)}${body.trimEnd()}\n${content.substring(close)}`.trim()
+          : dedent(body.replace(/^\s*\n/, "").split("\n")),
+        output: output || undefined,
+        unordered,
+        wholeFile: wholeFile || undefined,
+        sourceFile,
+        startLine: content.substring(0, match.index).split("\n").length,
+      });
+    }
+
+    return examples;
+  }
+
+  /**
+   * Collect the benchmark and fuzz functions of a test file with the names
+   * their bodies refer to, which tell the symbols they exercise.
+   */
+  private extractTestFunctions(content: string, sourceFile: string): GoTestFunction[] {
+    const testFunctions: GoTestFunction[] = [];
+
+    for (const match of content.matchAll(TEST_REFERENCE_FUNCTION)) {
+      const open = match.index + match[0].length - 1;
+      const body = content
+        .substring(open + 1, this.findClosingBrace(content, open))
+        .replace(/"(?:[^"\\\n]|\\.)*"|`[^`]*`/g, '""')
+        .replace(/\/\/.*$/gm, "");
+      const references = new Set<string>();
+      for (const [, selector, bare] of body.matchAll(
+        /\b([A-Za-z_]\w*\.[A-Z]\w*)|(?<![.\w])([A-Z]\w*)\s*[({]/g,
+      )) {
+        references.add(selector ?? bare);
+      }
+
+      const parts = match[1].slice(match[2].length).split("_");
+      if (parts.length > 1 && /^[a-z]/.test(parts.at(-1)!)) parts.pop();
+      const target = parts.filter(Boolean).join(".");
+
+      testFunctions.push({
+        kind: match[2] === "Benchmark" ? "benchmark" : "fuzz",
+        name: match[1],
+        target: target || undefined,
+        doc: this.extractDocBefore(content, match.index),
+        references: [...references],
+        sourceFile,
+        startLine: content.substring(0, match.index).split("\n").length,
+      });
+    }
+
+    return testFunctions;
+  }
+
+  /**
+   * Collect the notes with the configured markers from every comment of a
+   * file. As in go/doc, a note runs to the next note or the end of its
+   * comment group.
+   */
+  private extractNotes(content: string, sourceFile: string): GoNote[] {
+    const markers = new Set(this.config.noteMarkers ?? ["BUG"]);
+    const notes: GoNote[] = [];
+    let current: GoNote | undefined;
+
+    for (const [index, line] of content.split("\n").entries()) {
+      if (DIRECTIVE.test(line.trim())) continue;
+      const comment = line.match(/^[ \t]*\/\/ ?(.*)$/);
+      const note = comment?.[1].match(NOTE);
+      if (note && markers.has(note[1])) {
+        current = { marker: note[1], uid: note[2], body: note[3], sourceFile, line: index + 1 };
+        notes.push(current);
+      } else if (comment && current) {
+        current.body += `\n${comment[1]}`;
+      } else {
+        current = undefined;
+      }
+    }
+
+    for (const note of notes) {
+      note.body = note.body.trim();
+    }
+    return notes;
+  }
+
+  /**
+   * Find a keyed composite literal of the function's (first) result type
+   * returned from the body opening at `bodyIndex`, e.g. `return &Config{...}`.
+   */
+  private extractReturnedLiteral(
+    content: string,
+    bodyIndex: number,
+    returns: string,
+  ): GoCompositeLiteral | undefined {
+    const resultType = splitTopLevel(returns.replace(/^\((.*)\)$/s, "$1"))[0]
+      ?.replace(/^\*/, "")
+      .trim();
+    if (!resultType || !/^\w+$/.test(resultType)) return undefined;
+
+    const body = content.substring(bodyIndex, this.findClosingBrace(content, bodyIndex));
+    const literal = new RegExp(`\\breturn\\s+&?${resultType}\\s*\\{`).exec(body);
+    if (!literal) return undefined;
+
+    const open = bodyIndex + literal.index + literal[0].length - 1;
+    const elements = content
+      .substring(open + 1, this.findClosingBrace(content, open))
+      .split("\n")
+      .map((line) => this.stripLineComment(line))
+      .join("\n");
+
+    const fields: Record<string, string> = {};
+    for (const element of splitTopLevel(elements)) {
+      const keyed = element.match(/^(\w+)\s*:\s*([\s\S]+)$/);
+      if (keyed) {
+        fields[keyed[1]] = keyed[2].trim();
+      }
+    }
+    return { type: resultType, fields };
+  }
+
+  /**
+   * Record the constant field values of literals returned by constructors as
+   * defaults of the struct's fields. Values that depend on parameters or
+   * other runtime state are skipped.
+   */
+  private resolveFieldDefaults(types: GoType[], functions: GoMethod[], constants: GoConst[]): void {
+    const scope = new Map<string, GoConstValue>();
+    for (const constant of constants) {
+      if (constant.evaluated) scope.set(constant.name, constant.evaluated);
+    }
+
+    for (const func of functions) {
+      const literal = func.returnedLiteral;
+      const type = literal && types.find((t) => t.name === literal.type && t.kind === "struct");
+      if (!literal || !type) continue;
+
+      for (const field of type.fields) {
+        const value = literal.fields[field.name];
+        if (value === undefined || !evaluateConstExpr(value, scope)) continue;
+        field.defaults = [...(field.defaults ?? []), { value, constructor: func.name }];
+      }
+    }
+  }
+
   /**
    * Extract constants and variables.
    */
   private extractConstants(content: string, sourceFile: string): GoConst[] {
     const constants: GoConst[] = [];
 
-    // Match const declarations - use \w+ to match both exported and unexported
-    const constPattern = /\b(const|var)\s+(\w+)\s*(?:(\w+)\s*)?=/g;
+    // Match const declarations - use \w+ to match both exported and unexported.
+    // Unexported ones are kept so exported constants can be evaluated in terms
+    // of them; extract() filters them out afterwards.
+    // The type may be composite, e.g. `var Routes map[string]Handler = ...`
+    const constPattern = /\b(const|var)\s+(\w+)(?:[ \t]+([^=\n]*?))?[ \t]*=(.*)/g;
 
     let match;
     while ((match = constPattern.exec(content)) !== null) {
       const kind = match[1] as "const" | "var";
       const name = match[2];
-      const type = match[3];
-
-      if (this.config.exportedOnly && !this.isExported(name)) {
-        continue;
+      let type = match[3]?.trim() || undefined;
+
+      // Only keep values that are complete on the declaration line
+      const value = this.stripLineComment(match[4]).trim();
+      const complete = value !== "" && this.bracketDepth(value) === 0;
+
+      // A function literal's signature stands in for the missing type
+      let funcLiteral: GoFuncLiteral | undefined;
+      if (kind === "var" && !type && /^func\s*\(/.test(value)) {
+        const valueIndex = match.index + match[0].length - match[4].length;
+        const open = content.indexOf("(", valueIndex);
+        const { params, returns } = this.scanSignature(content, open);
+        funcLiteral = { parameters: this.parseParameters(params), returns };
+        type = returns ? `func(${params}) ${returns}` : `func(${params})`;
       }
 
       const beforeMatch = content.substring(0, match.index);
       const lineNumber = beforeMatch.split("\n").length;
-      const doc = this.extractDocBefore(content, match.index);
+      const doc = this.extractDocBefore(content, match.index) ?? this.lineComment(match[4]);
 
       constants.push({
         name,
         kind,
         doc,
         type,
+        value: complete && !funcLiteral ? value : undefined,
+        funcLiteral,
         sourceFile,
         startLine: lineNumber,
       });
     }
 
+    return [...constants, ...this.extractGroupedConstants(content, sourceFile)];
+  }
+
+  /**
+   * Extract the members of `const (...)` and `var (...)` blocks. A constant
+   * without a value repeats the type and expression of the one above it,
+   * with `iota` counting the lines of the block. Members are documented by
+   * the comment above them or, failing that, their trailing line comment.
+   */
+  private extractGroupedConstants(content: string, sourceFile: string): GoConst[] {
+    const constants: GoConst[] = [];
+
+    for (const block of content.matchAll(/^(const|var)[ \t]*\(/gm)) {
+      const kind = block[1] as "const" | "var";
+      const bodyStart = block.index + block[0].length;
+      const bodyEnd = content.indexOf("\n)", bodyStart);
+      if (bodyEnd === -1) continue;
+
+      const lines = content.substring(bodyStart, bodyEnd).split("\n");
+      const firstLine = content.substring(0, bodyStart).split("\n").length;
+      let previous: { type?: string; value?: string } = {};
+      let iota = 0;
+      let depth = 0;
+
+      for (const [index, raw] of lines.entries()) {
+        const line = this.stripLineComment(raw).trim();
+        const inValue = depth > 0;
+        depth += this.bracketDepth(line);
+        if (inValue || !line || line.startsWith("//")) continue;
+
+        const member = line.match(/^(\w+)(?:[ \t]+([^=]*?))?[ \t]*(?:=[ \t]*(.*))?$/);
+        if (!member) {
+          iota++;
+          continue;
+        }
+
+        const name = member[1];
+        const value = member[3];
+        const repeated = kind === "const" && value === undefined;
+        const type = repeated ? previous.type : member[2]?.trim() || undefined;
+        const expression = repeated ? previous.value : value;
+        const complete = expression !== undefined && this.bracketDepth(expression) === 0;
+        if (!repeated) {
+          previous = { type, value: complete ? expression : undefined };
+        }
+
+        const above: string[] = [];
+        for (let j = index - 1; j >= 0 && lines[j].trim().startsWith("//"); j--) {
+          if (DIRECTIVE.test(lines[j].trim())) continue;
+          above.unshift(lines[j].trim().replace(/^\/\/ ?/, ""));
+        }
+
+        if (name !== "_") {
+          constants.push({
+            name,
+            kind,
+            doc: above.length > 0 ? above.join("\n") : this.lineComment(raw),
+            type,
+            value: complete ? expression : undefined,
+            iota: kind === "const" ? iota : undefined,
+            sourceFile,
+            startLine: firstLine + index,
+          });
+        }
+        iota++;
+      }
+    }
+
     return constants;
   }
 
+  /**
+   * Fold constant expressions, resolving references between constants
+   * regardless of declaration order: by name within a package, and by
+   * `pkg.Name` to the exported constants of the module's other packages
+   * the file imports.
+   */
+  private evaluateConstants(
+    constants: GoConst[],
+    moduleName: string,
+    imports: Record<string, Record<string, string>>,
+  ): void {
+    // Folded constants by package directory, then name
+    const folded = new Map<string, Map<string, GoConstValue>>();
+    const importedDir = (importPath: string | undefined) => {
+      if (!moduleName || importPath === undefined) return undefined;
+      if (importPath === moduleName) return "";
+      return importPath.startsWith(`${moduleName}/`)
+        ? importPath.slice(moduleName.length + 1)
+        : undefined;
+    };
+    const scopeOf = (constant: GoConst, dir: string): ConstScope => ({
+      get: (name) => {
+        if (name === "iota" && constant.iota !== undefined) {
+          return { value: String(constant.iota), type: "untyped int" };
+        }
+        const [qualifier, member] = name.includes(".") ? name.split(".") : [undefined, name];
+        if (qualifier === undefined) return folded.get(dir)?.get(name);
+        const imported = importedDir(imports[constant.sourceFile]?.[qualifier]);
+        const value =
+          imported !== undefined && this.isExported(member)
+            ? folded.get(imported)?.get(member)
+            : undefined;
+        // Exported types of the package are qualified as the file refers to them
+        return value && /^[A-Z]/.test(value.type)
+          ? { ...value, type: `${qualifier}.${value.type}` }
+          : value;
+      },
+    });
+    let pending = constants.filter((c) => c.kind === "const" && c.value);
+
+    while (pending.length > 0) {
+      const remaining: GoConst[] = [];
+      for (const constant of pending) {
+        const dir = this.packageDirOf(join(this.config.packagePath, constant.sourceFile));
+        const value = evaluateConstExpr(constant.value!, scopeOf(constant, dir));
+        const evaluated = value && constant.type ? convertConstValue(value, constant.type) : value;
+        if (evaluated) {
+          constant.evaluated = evaluated;
+          if (!folded.has(dir)) folded.set(dir, new Map());
+          folded.get(dir)!.set(constant.name, evaluated);
+        } else {
+          remaining.push(constant);
+        }
+      }
+      // Stop once a pass makes no progress
+      if (remaining.length === pending.length) break;
+      pending = remaining;
+    }
+  }
+
   /**
    * Extract struct fields.
    */
@@ -356,35 +1725,149 @@ export class GoExtractor {
         continue;
       }
 
-      // Match field: Name Type `tag`
-      const fieldMatch = line.match(/^([A-Z]\w*)\s+(\S+)(?:\s+`([^`]+)`)?/);
-      if (fieldMatch) {
-        const name = fieldMatch[1];
-        const type = fieldMatch[2];
-        const tag = fieldMatch[3];
+      // Match field: Name Type `tag` (not an embedded type with a comment or tag)
+      const fieldMatch = line.match(/^([A-Za-z_]\w*)\s+([^\s/`].*)$/);
+      if (!fieldMatch) {
+        // Skip over the body of anything spanning several lines
+        i = this.skipBlock(lines, i);
+        continue;
+      }
 
-        // Look for doc comment above
-        let doc: string | undefined;
-        if (i > 0) {
-          const prevLine = lines[i - 1].trim();
-          if (prevLine.startsWith("//")) {
-            doc = prevLine.replace(/^\/\/\s*/, "");
-          }
-        }
+      const name = fieldMatch[1];
+      const startIndex = i;
 
-        fields.push({
-          name,
-          doc,
-          type,
-          tag,
-          startLine: typeStartLine + i,
-        });
+      // Anonymous struct/interface types may span several lines
+      let rest = fieldMatch[2];
+      while (this.braceDepth(rest) > 0 && i + 1 < lines.length) {
+        i++;
+        rest += "\n" + lines[i];
+      }
+
+      // Drop a trailing line comment, then split off the tag
+      const lastLineStart = rest.lastIndexOf("\n") + 1;
+      const lastLine = this.stripLineComment(rest.slice(lastLineStart));
+      rest = rest.slice(0, lastLineStart) + lastLine;
+      const tagMatch = rest.match(/\s*`([^`]+)`\s*$/);
+      const tag = tagMatch?.[1];
+      const typeText = (tagMatch ? rest.slice(0, tagMatch.index) : rest).trim();
+
+      // Literal types are normalized to one line; their structure is kept separately
+      const isLiteral = /^(struct|interface)\s*\{/.test(typeText);
+      const typeExpr = isLiteral ? parseTypeExpr(typeText) : undefined;
+      const type = typeExpr ? formatTypeExpr(typeExpr) : typeText;
+
+      // Look for doc comment lines directly above
+      const docLines: string[] = [];
+      for (let j = startIndex - 1; j >= 0 && lines[j].trim().startsWith("//"); j--) {
+        if (DIRECTIVE.test(lines[j].trim())) continue;
+        docLines.unshift(lines[j].trim().replace(/^\/\/\s*/, ""));
       }
+      const doc = docLines.length > 0 ? docLines.join("\n") : this.lineComment(lines[i]);
+      if (!this.isExtracted(name)) continue;
+
+      fields.push({
+        name,
+        doc,
+        type,
+        typeExpr,
+        tag,
+        startLine: typeStartLine + startIndex,
+      });
     }
 
     return fields;
   }
 
+  /**
+   * The text of a line's trailing `//` comment, unless it is a directive.
+   */
+  private lineComment(line: string): string | undefined {
+    const code = this.stripLineComment(line);
+    if (code.length === line.length) return undefined;
+
+    const comment = line.slice(code.length).trim();
+    if (DIRECTIVE.test(comment)) return undefined;
+    return comment.replace(/^\/\/ ?/, "").trim() || undefined;
+  }
+
+  /**
+   * Remove a trailing `//` comment from a line, ignoring `//` inside strings and tags.
+   */
+  private stripLineComment(line: string): string {
+    let quote: string | undefined;
+    for (let i = 0; i < line.length; i++) {
+      const char = line[i];
+      if (quote) {
+        if (char === quote) quote = undefined;
+      } else if (char === "`" || char === '"') {
+        quote = char;
+      } else if (char === "/" && line[i + 1] === "/") {
+        return line.slice(0, i).trimEnd();
+      }
+    }
+    return line;
+  }
+
+  /**
+   * Net count of opening brackets of any kind, ignoring string contents.
+   */
+  private bracketDepth(text: string): number {
+    const code = text.replace(/"(?:[^"\\]|\\.)*"|`[^`]*`|'(?:[^'\\]|\\.)*'/g, "");
+    return (code.match(/[({[]/g) ?? []).length - (code.match(/[)}\]]/g) ?? []).length;
+  }
+
+  /**
+   * Net count of opening braces in a piece of source.
+   */
+  private braceDepth(text: string): number {
+    let depth = 0;
+    for (const char of text) {
+      if (char === "{") depth++;
+      else if (char === "}") depth--;
+    }
+    return depth;
+  }
+
+  /**
+   * Find the unexported in-package types a struct embeds, e.g. `*base`.
+   */
+  private extractUnexportedEmbeds(body: string): string[] {
+    const embedPattern = /^\*?([a-z_]\w*)\s*(?:`[^`]*`)?\s*(?:\/\/.*)?$/;
+    return body.split("\n").flatMap((line) => {
+      const match = line.trim().match(embedPattern);
+      return match ? [match[1]] : [];
+    });
+  }
+
+  /**
+   * Find the exported or package-qualified types a struct embeds, e.g.
+   * `Buffer` or `*sync.Mutex`, skipping the bodies of anonymous struct and
+   * interface fields.
+   */
+  private extractExportedEmbeds(body: string): string[] {
+    const embedPattern = /^\*?((?:\w+\.)?[A-Z]\w*)(?:\[.*\])?\s*(?:`[^`]*`)?\s*(?:\/\/.*)?$/;
+    let depth = 0;
+    return body.split("\n").flatMap((line) => {
+      const match = depth === 0 ? line.trim().match(embedPattern) : null;
+      depth += this.braceDepth(this.stripLineComment(line));
+      return match ? [match[1]] : [];
+    });
+  }
+
+  /**
+   * Return the index of the last line of a block opened on line `index`
+   * (or `index` itself if the line does not open a block).
+   */
+  private skipBlock(lines: string[], index: number): number {
+    let depth = this.braceDepth(lines[index]);
+    let i = index;
+    while (depth > 0 && i + 1 < lines.length) {
+      i++;
+      depth += this.braceDepth(lines[i]);
+    }
+    return i;
+  }
+
   /**
    * Associate methods with their receiver types.
    */
@@ -399,6 +1882,69 @@ export class GoExtractor {
     }
   }
 
+  /**
+   * Read the parameter list starting at `openIndex` and the result list
+   * that follows it, up to the opening brace of the body or the end of the line.
+   * Type literals such as `interface{}` in the results are kept intact.
+   * `end` is the index where scanning stopped.
+   */
+  private scanSignature(
+    content: string,
+    openIndex: number,
+  ): { params: string; returns: string; end: number } {
+    let depth = 0;
+    let closeIndex = content.length;
+    for (let i = openIndex; i < content.length; i++) {
+      if (content[i] === "(") depth++;
+      else if (content[i] === ")") {
+        depth--;
+        if (depth === 0) {
+          closeIndex = i;
+          break;
+        }
+      }
+    }
+
+    let end = closeIndex + 1;
+    depth = 0;
+    while (end < content.length) {
+      const char = content[end];
+      if (char === "(" || char === "[") depth++;
+      else if (char === ")" || char === "]") depth--;
+      else if (char === "\n" && depth === 0) break;
+      // A trailing comment, e.g. after a body-less declaration
+      else if (content.startsWith("//", end) && depth === 0) break;
+      else if (char === "{") {
+        if (!/\b(interface|struct)\s*$/.test(content.substring(closeIndex + 1, end))) break;
+        end = this.findClosingBrace(content, end);
+      }
+      end++;
+    }
+
+    return {
+      params: content.substring(openIndex + 1, closeIndex),
+      returns: content.substring(closeIndex + 1, end).trim(),
+      end,
+    };
+  }
+
+  /**
+   * Read the type parameter list of a declaration, if one starts at
+   * `index`, counting brackets so constraints like `~[]byte` or `map[K]V`
+   * stay whole. `end` is the index after the list, or `index` without one.
+   */
+  private scanTypeParams(content: string, index: number): { params?: string; end: number } {
+    if (content[index] !== "[") return { end: index };
+    let depth = 0;
+    for (let i = index; i < content.length; i++) {
+      if (content[i] === "[") depth++;
+      else if (content[i] === "]" && --depth === 0) {
+        return { params: content.substring(index + 1, i), end: i + 1 };
+      }
+    }
+    return { params: content.substring(index + 1), end: content.length };
+  }
+
   /**
    * Find the closing brace matching an opening brace.
    */
@@ -427,8 +1973,12 @@ export class GoExtractor {
     for (let i = lines.length - 1; i >= 0; i--) {
       const line = lines[i].trim();
 
-      if (line.startsWith("//")) {
-        docLines.unshift(line.replace(/^\/\/\s*/, ""));
+      if (DIRECTIVE.test(line)) {
+        // Directives are for tools, not readers
+        continue;
+      } else if (line.startsWith("//")) {
+        // Keep indentation past the space after the marker: it marks code and lists
+        docLines.unshift(line.replace(/^\/\/ ?/, ""));
       } else if (line === "" && docLines.length > 0) {
         // Stop at empty line after finding doc
         break;
@@ -438,6 +1988,14 @@ export class GoExtractor {
       }
     }
 
+    // Notes are collected separately, and run to the end of the comment
+    const markers = new Set(this.config.noteMarkers ?? ["BUG"]);
+    const noteStart = docLines.findIndex((line) => markers.has(line.match(NOTE)?.[1] ?? ""));
+    if (noteStart !== -1) {
+      docLines.splice(noteStart);
+      return docLines.join("\n").trim() || undefined;
+    }
+
     if (docLines.length > 0) {
       return docLines.join("\n");
     }
@@ -503,6 +2061,17 @@ export class GoExtractor {
     return params;
   }
 
+  /**
+   * Parse a type parameter list such as `K comparable, V any`.
+   * Shares the grouped-name handling of regular parameters.
+   */
+  private parseTypeParams(typeParamsStr: string): GoTypeParam[] {
+    return this.parseParameters(typeParamsStr).map((p) => ({
+      name: p.name,
+      constraint: p.type,
+    }));
+  }
+
   /**
    * Split parameters by comma, respecting nested types like func(int) error.
    */
@@ -540,6 +2109,14 @@ export class GoExtractor {
     return /^[A-Z]/.test(name);
   }
 
+  /**
+   * Check if a declared name is extracted: exported names, and unexported
+   * ones too unless exportedOnly is set. Blank identifiers never are.
+   */
+  private isExtracted(name: string): boolean {
+    return name !== "_" && (!this.config.exportedOnly || this.isExported(name));
+  }
+
   /**
    * Detect module name from go.mod.
    */
@@ -559,11 +2136,11 @@ export class GoExtractor {
 
   /**
    * Detect version from go.mod or git tags.
-   * Note: Go modules don't have version in go.mod itself, so we return default.
+   * Note: Go modules don't have version in go.mod itself, so we return the
+   * configured version (as resolved from tags for workspaces) or a default.
    */
   private async detectVersion(): Promise<string> {
     // Go modules get versions from git tags, not from go.mod
-    // For now, return a default version
-    return "0.0.0";
+    return this.config.moduleVersion ?? "0.0.0";
   }
 }
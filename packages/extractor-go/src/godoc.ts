@@ -0,0 +1,326 @@
+/**
+ * godoc HTML
+ *
+ * Renders an extraction as HTML fragments laid out like pkg.go.dev, for
+ * teams hosting drop-in godoc pages: a fragment per package with the
+ * Overview, Index, Constants, Variables, Functions, and Types sections. The
+ * anchors match pkg.go.dev's (`#pkg-index`, `#NewClient`, `#Client.Get`), as
+ * do the class names, so its stylesheets and deep links carry over.
+ *
+ * As on pkg.go.dev, functions returning a type of the package are listed
+ * under the type as its constructors, and type declarations are shown as
+ * written, with unexported fields left out. Declarations come from the source
+ * files; without them, they are rebuilt from the IR.
+ */
+
+import { mkdir, readFile, writeFile } from "fs/promises";
+import { dirname, join, posix } from "path";
+import type { ExtractorOutput } from "./output.js";
+import type { DocBlock } from "./doc-comment.js";
+import type { GoSymbolRecord } from "./transformer.js";
+import { escapeHtml, renderHtml } from "./html.js";
+import { defaultLabels, type Labels } from "./labels.js";
+import { isConstructorOf } from "./lifecycle.js";
+import { compareCanonical } from "./canonical.js";
+
+/**
+ * Render the godoc fragments of an output, keyed by file name: `index.html`
+ * in the directory of each package. `sources` holds the contents of the
+ * source files by path, for type declarations.
+ */
+export function renderGodoc(
+  output: ExtractorOutput,
+  sources: Record<string, string> = {},
+): Map<string, string> {
+  const labels = output.labels?.labels ?? defaultLabels;
+  const symbols = (output.symbols as GoSymbolRecord[]).filter(
+    (symbol) => symbol.tags.visibility === "public" && !symbol.testHelper,
+  );
+  const byId = new Map(symbols.map((symbol) => [symbol.id, symbol]));
+  const dirOf = (symbol: GoSymbolRecord) => posix.dirname(symbol.source.path);
+
+  const byPackage = new Map<string, GoSymbolRecord[]>();
+  for (const symbol of symbols) {
+    byPackage.set(dirOf(symbol), [...(byPackage.get(dirOf(symbol)) ?? []), symbol]);
+  }
+
+  const pages = new Map<string, string>();
+  for (const dir of [...byPackage.keys()].sort(compareCanonical)) {
+    const symbolUrl = (refId: string) => {
+      const target = byId.get(refId);
+      if (!target) return undefined;
+      const path = posix.relative(dir, dirOf(target));
+      return `${path ? `${path}/` : ""}#${target.qualifiedName}`;
+    };
+    const page = new GodocPage(labels, symbolUrl, sources);
+    const overview = dir === "." ? output.package.overviewBlocks : undefined;
+    const file = dir === "." ? "index.html" : `${dir}/index.html`;
+    pages.set(file, page.render(byPackage.get(dir)!, byId, overview));
+  }
+  return pages;
+}
+
+/**
+ * Write the godoc fragments of an output to a directory, reading type
+ * declarations from the source files. Returns the number of fragments.
+ */
+export async function writeGodoc(output: ExtractorOutput, outputDir: string): Promise<number> {
+  const sources: Record<string, string> = {};
+  for (const path of new Set(output.symbols.map((symbol) => symbol.source.path))) {
+    sources[path] = await readFile(join(output.package.repo.path, path), "utf-8").catch(() => "");
+  }
+  const pages = renderGodoc(output, sources);
+  for (const [file, content] of pages) {
+    await mkdir(dirname(join(outputDir, file)), { recursive: true });
+    await writeFile(join(outputDir, file), content, "utf-8");
+  }
+  return pages.size;
+}
+
+/**
+ * Renders the fragment of one package.
+ */
+class GodocPage {
+  private labels: Labels;
+  private symbolUrl: (refId: string) => string | undefined;
+  private sources: Record<string, string>;
+
+  constructor(
+    labels: Labels,
+    symbolUrl: (refId: string) => string | undefined,
+    sources: Record<string, string>,
+  ) {
+    this.labels = labels;
+    this.symbolUrl = symbolUrl;
+    this.sources = sources;
+  }
+
+  /** Render the sections of a package. */
+  render(
+    symbols: GoSymbolRecord[],
+    byId: Map<string, GoSymbolRecord>,
+    overview?: DocBlock[],
+  ): string {
+    const types = symbols.filter((s) => ["class", "interface", "typeAlias"].includes(s.kind));
+    const typeNames = new Set(types.map((type) => type.name));
+    const constructorOf = (func: GoSymbolRecord) =>
+      [...typeNames].find((name) => isConstructorOf(func.returns?.type ?? "", name));
+    const functions = symbols.filter((s) => s.kind === "function" && !constructorOf(s));
+    const values = symbols.filter((s) => s.kind === "variable");
+    const constants = values.filter((s) => s.signature.startsWith("const "));
+    const variables = values.filter((s) => !s.signature.startsWith("const "));
+    const typeFuncs = (type: GoSymbolRecord) =>
+      symbols.filter((s) => s.kind === "function" && constructorOf(s) === type.name);
+    const methods = (type: GoSymbolRecord) =>
+      (type.members ?? []).flatMap((member) => {
+        const method = member.kind === "method" ? byId.get(member.refId) : undefined;
+        return method ? [method] : [];
+      });
+
+    const index: string[] = [];
+    if (constants.length) index.push(this.indexItem("pkg-constants", this.labels.constants));
+    if (variables.length) index.push(this.indexItem("pkg-variables", this.labels.variables));
+    for (const func of functions) index.push(this.indexItem(func.qualifiedName, func.signature));
+    for (const type of types) {
+      const nested = [...typeFuncs(type), ...methods(type)].map((func) =>
+        this.indexItem(func.qualifiedName, func.signature),
+      );
+      const list = nested.length ? `\n<ul>\n${nested.join("\n")}\n</ul>\n` : "";
+      index.push(this.indexItem(type.qualifiedName, `type ${type.name}`, list));
+    }
+
+    const sections = [];
+    if (overview?.length) {
+      sections.push(this.section("overview", this.labels.overview, this.docHtml(overview)));
+    }
+    const indexList = `<ul class="Documentation-indexList">\n${index.join("\n")}\n</ul>`;
+    sections.push(this.section("index", this.labels.index, indexList));
+    if (constants.length) {
+      sections.push(this.section("constants", this.labels.constants, this.values(constants)));
+    }
+    if (variables.length) {
+      sections.push(this.section("variables", this.labels.variables, this.values(variables)));
+    }
+    if (functions.length) {
+      const body = functions.map((func) => this.func(func, "Documentation-function"));
+      sections.push(this.section("functions", this.labels.functions, body.join("\n")));
+    }
+    if (types.length) {
+      const body = types.map((type) => {
+        const parts = [
+          this.header(type, "Documentation-typeHeader", `type ${this.anchorLink(type)}`),
+          `<pre>${escapeHtml(this.typeDeclaration(type))}</pre>`,
+          this.docs(type),
+          ...typeFuncs(type).map((func) => this.func(func, "Documentation-typeFunc")),
+          ...methods(type).map((method) => this.func(method, "Documentation-typeMethod")),
+        ];
+        return `<div class="Documentation-type">\n${parts.filter(Boolean).join("\n")}\n</div>`;
+      });
+      sections.push(this.section("types", this.labels.types, body.join("\n")));
+    }
+    return `<div class="Documentation">\n${sections.join("\n")}\n</div>\n`;
+  }
+
+  /** Render a section with its pkg.go.dev anchor. */
+  private section(id: string, title: string, body: string): string {
+    return (
+      `<section class="Documentation-${id}">\n` +
+      `<h3 id="pkg-${id}">${escapeHtml(title)}</h3>\n${body}\n</section>`
+    );
+  }
+
+  /** Render an entry of the index. */
+  private indexItem(anchor: string, text: string, nested = ""): string {
+    return `<li><a href="#${escapeHtml(anchor)}">${escapeHtml(text)}</a>${nested}</li>`;
+  }
+
+  /** Render the constants or variables of a package. */
+  private values(symbols: GoSymbolRecord[]): string {
+    return symbols
+      .map((symbol) => {
+        const value = symbol.value ? ` = ${symbol.value.expression}` : "";
+        const declaration = `<pre id="${escapeHtml(symbol.qualifiedName)}">`;
+        return `${declaration}${escapeHtml(symbol.signature + value)}</pre>\n${this.docs(symbol)}`;
+      })
+      .join("\n");
+  }
+
+  /** Render a function or method. */
+  private func(func: GoSymbolRecord, className: string): string {
+    const receiver = func.kind === "method" ? /^func (\([^)]*\) )/.exec(func.signature)?.[1] : "";
+    const title = `func ${escapeHtml(receiver ?? "")}${this.anchorLink(func)}`;
+    const parts = [
+      this.header(func, `${className}Header`, title),
+      `<pre>${escapeHtml(func.signature)}</pre>`,
+      this.docs(func),
+    ];
+    return `<div class="${className}">\n${parts.filter(Boolean).join("\n")}\n</div>`;
+  }
+
+  /** Render the heading of a declaration, tagged when deprecated. */
+  private header(symbol: GoSymbolRecord, className: string, title: string): string {
+    const tag = symbol.docs.deprecated
+      ? ` <span class="Documentation-deprecatedTag">${escapeHtml(this.labels.deprecated)}</span>`
+      : "";
+    const id = escapeHtml(symbol.qualifiedName);
+    return `<h4 class="${className}" id="${id}">${title}${tag}</h4>`;
+  }
+
+  /** Link a declaration's name to its own anchor. */
+  private anchorLink(symbol: GoSymbolRecord): string {
+    return `<a href="#${escapeHtml(symbol.qualifiedName)}">${escapeHtml(symbol.name)}</a>`;
+  }
+
+  /** Render the docs and examples of a symbol. */
+  private docs(symbol: GoSymbolRecord): string {
+    const { deprecated, blocks, description, summary, examples } = symbol.docs;
+    const parts: string[] = [];
+    if (deprecated) {
+      const message = deprecated.message ? ` ${escapeHtml(deprecated.message)}` : "";
+      const label = escapeHtml(this.labels.deprecated);
+      parts.push(`<p class="Documentation-deprecated">${label}:${message}</p>`);
+    }
+    if (blocks) {
+      parts.push(this.docHtml(blocks));
+    } else if (description ?? summary) {
+      parts.push(`<p>${escapeHtml(description ?? summary)}</p>`);
+    }
+    for (const example of examples ?? []) {
+      const { example: label } = this.labels;
+      const title = example.title ? `${label}: ${example.title}` : label;
+      const output = example.output ? `\n<pre>Output:\n\n${escapeHtml(example.output)}</pre>` : "";
+      parts.push(
+        `<details class="Documentation-exampleDetails">\n` +
+          `<summary>${escapeHtml(title)}</summary>\n` +
+          `<pre>${escapeHtml(example.code)}</pre>${output}\n</details>`,
+      );
+    }
+    return parts.join("\n");
+  }
+
+  /** Render doc comment blocks, with headings below the declaration's. */
+  private docHtml(blocks: DocBlock[]): string {
+    return renderHtml(blocks, { headingLevel: 4, symbolUrl: this.symbolUrl });
+  }
+
+  /**
+   * The declaration of a type as written in its source file, without
+   * unexported fields, or rebuilt from the IR.
+   */
+  private typeDeclaration(type: GoSymbolRecord): string {
+    const source = this.sources[type.source.path];
+    const written = source ? readDeclaration(source, type) : undefined;
+    if (written) return written;
+
+    const fields = (type.members ?? []).filter(
+      (m) => m.kind === "property" && m.visibility === "public",
+    );
+    if (type.kind !== "class" || fields.length === 0) return type.signature;
+    return `${type.signature} {\n${fields.map((f) => `\t${f.name} ${f.type ?? ""}`).join("\n")}\n}`;
+  }
+}
+
+/**
+ * Read a type declaration from its source: from the type's name on its
+ * declaration line to the end of its body. Unexported fields are replaced by
+ * a note, as pkg.go.dev does.
+ */
+function readDeclaration(source: string, type: GoSymbolRecord): string | undefined {
+  const sourceLines = source.split("\n");
+  const first = sourceLines[type.source.line - 1];
+  const column = first?.search(new RegExp(`\\b${type.name}\\b`)) ?? -1;
+  if (column < 0) return undefined;
+
+  const indent = /^\s*/.exec(first)![0];
+  const text = sourceLines.slice(type.source.line - 1).join("\n").slice(column);
+  const declaration = `type ${text.slice(0, declarationEnd(text)).trimEnd()}`;
+
+  const lines = declaration.split("\n");
+  const kept: string[] = [];
+  let comments: string[] = [];
+  let hidden = false;
+  for (const [i, line] of lines.entries()) {
+    const inBody = type.kind === "class" && i > 0 && i < lines.length - 1;
+    if (inBody && /^\s*\/\//.test(line)) {
+      comments.push(line);
+    } else if (inBody && /^\s*[a-z_]\w*[\s,]/.test(line)) {
+      hidden = true;
+      comments = [];
+    } else {
+      kept.push(...comments, line);
+      comments = [];
+    }
+  }
+  if (hidden) kept.splice(kept.length - 1, 0, "\t// contains filtered or unexported fields");
+  const dedent = (line: string) => (line.startsWith(indent) ? line.slice(indent.length) : line);
+  return kept.map(dedent).join("\n");
+}
+
+/**
+ * Find where a declaration ends: the first newline outside braces,
+ * parentheses, brackets, strings, and comments.
+ */
+function declarationEnd(text: string): number {
+  let depth = 0;
+  for (let i = 0; i < text.length; i++) {
+    const c = text[i];
+    if (c === "/" && text[i + 1] === "/") {
+      i = text.indexOf("\n", i) - 1;
+      if (i < 0) return text.length;
+    } else if (c === "/" && text[i + 1] === "*") {
+      i = text.indexOf("*/", i + 2) + 1;
+      if (i <= 0) return text.length;
+    } else if (c === '"' || c === "'" || c === "`") {
+      for (i++; i < text.length && text[i] !== c; i++) {
+        if (text[i] === "\\" && c !== "`") i++;
+      }
+    } else if ("{([".includes(c)) {
+      depth++;
+    } else if ("})]".includes(c)) {
+      depth--;
+    } else if (c === "\n" && depth <= 0) {
+      return i;
+    }
+  }
+  return text.length;
+}
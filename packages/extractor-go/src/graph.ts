@@ -0,0 +1,139 @@
+/**
+ * Symbol Graph
+ *
+ * Exports an extraction as a DocC-style symbol graph for graph-based
+ * navigation and "implemented by" pages: a node for every symbol and struct
+ * field, and relationship edges between them.
+ *
+ * - `memberOf`: a method or field to its type
+ * - `conformsTo`: a type to an interface it implements
+ * - `inheritsFrom`: a struct or interface to a type it embeds
+ * - `references`: a symbol to a type in its signature, or a symbol its docs
+ *   link to
+ *
+ * Edges to declarations outside the package target their qualified name and
+ * carry it as `targetFallback`, as DocC does for symbols not in the graph.
+ */
+
+import type { ExtractorOutput } from "./output.js";
+import type { Provenance } from "./provenance.js";
+import type { GoSymbolRecord } from "./transformer.js";
+import { docLinkSpans } from "./doc-comment.js";
+
+/**
+ * Version of the symbol graph format.
+ */
+export const symbolGraphFormatVersion = 1;
+
+/**
+ * Kind of a relationship edge.
+ */
+export type SymbolGraphEdgeKind = "memberOf" | "conformsTo" | "inheritsFrom" | "references";
+
+/**
+ * A symbol of the graph.
+ */
+export interface SymbolGraphNode {
+  id: string;
+  kind: string;
+  name: string;
+  qualifiedName: string;
+  /** Canonical URL; fields have none of their own */
+  url?: string;
+  summary?: string;
+}
+
+/**
+ * A relationship from one symbol to another.
+ */
+export interface SymbolGraphEdge {
+  kind: SymbolGraphEdgeKind;
+  source: string;
+  target: string;
+  /** Name of a target outside the graph */
+  targetFallback?: string;
+}
+
+/**
+ * A package as a symbol graph.
+ */
+export interface SymbolGraph {
+  metadata: { formatVersion: number; generator: string; provenance?: Provenance };
+  module: { name: string; packageId: string; language: "go" };
+  symbols: SymbolGraphNode[];
+  relationships: SymbolGraphEdge[];
+}
+
+/**
+ * Build the symbol graph of an output. Edges are listed once each, in
+ * symbol order; edges from a symbol to itself are left out.
+ */
+export function buildSymbolGraph(output: ExtractorOutput): SymbolGraph {
+  const symbols = output.symbols as GoSymbolRecord[];
+  const idsByName = new Map(symbols.map((symbol) => [symbol.qualifiedName, symbol.id]));
+  const nodes: SymbolGraphNode[] = [];
+  const edges = new Map<string, SymbolGraphEdge>();
+  const edge = (kind: SymbolGraphEdgeKind, source: string, target: string, refId?: string) => {
+    const id = refId ?? idsByName.get(target);
+    if (id === source) return;
+    const next: SymbolGraphEdge = id
+      ? { kind, source, target: id }
+      : { kind, source, target, targetFallback: target };
+    edges.set(`${kind}\0${source}\0${next.target}`, next);
+  };
+
+  for (const symbol of symbols) {
+    nodes.push({
+      id: symbol.id,
+      kind: symbol.kind,
+      name: symbol.name,
+      qualifiedName: symbol.qualifiedName,
+      url: symbol.urls.canonical,
+      summary: symbol.docs.summary || undefined,
+    });
+    for (const member of symbol.members ?? []) {
+      if (member.kind === "property") {
+        nodes.push({
+          id: member.refId,
+          kind: member.kind,
+          name: member.name,
+          qualifiedName: `${symbol.qualifiedName}.${member.name}`,
+          summary: member.doc,
+        });
+      }
+      edge("memberOf", member.refId, symbol.qualifiedName, symbol.id);
+    }
+    for (const name of symbol.relations?.implements ?? []) {
+      edge("conformsTo", symbol.id, name);
+    }
+    for (const implementation of symbol.implementations ?? []) {
+      if (implementation.refId) {
+        edge("conformsTo", implementation.refId, symbol.qualifiedName, symbol.id);
+      }
+    }
+    for (const name of symbol.relations?.extends ?? []) {
+      edge("inheritsFrom", symbol.id, name);
+    }
+    for (const ref of symbol.typeRefs ?? []) {
+      edge("references", symbol.id, ref.qualifiedName ?? ref.name, ref.refId);
+    }
+    for (const link of docLinkSpans(symbol.docs.blocks ?? [])) {
+      if (link.refId) edge("references", symbol.id, link.target, link.refId);
+    }
+  }
+
+  return {
+    metadata: {
+      formatVersion: symbolGraphFormatVersion,
+      generator: "extract-go",
+      provenance: output.provenance,
+    },
+    module: {
+      name: output.package.displayName,
+      packageId: output.package.packageId,
+      language: "go",
+    },
+    symbols: nodes,
+    relationships: [...edges.values()],
+  };
+}
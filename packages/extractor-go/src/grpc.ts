@@ -0,0 +1,271 @@
+/**
+ * gRPC Service
+ *
+ * Serves the `langchain.extractor.go.v1.Extractor` service of
+ * `extractor.proto` from the extraction server's cache, so other services in
+ * the docs pipeline can consume extractions with typed clients:
+ * `ExtractPackage`, `StreamSymbols` (server streaming), `GetSymbol`, and
+ * `DiffVersions`.
+ *
+ * gRPC is spoken over plaintext HTTP/2 without a gRPC dependency: each
+ * message is a protobuf, prefixed with an uncompressed flag and its length,
+ * and the status goes in the `grpc-status` and `grpc-message` trailers.
+ */
+
+import { createServer, type Http2Server, type ServerHttp2Stream } from "http2";
+import { diffOutputs, type OutputDiff } from "./check.js";
+import type { SummarySymbol } from "./profile.js";
+import { ProtoWriter, protoString, readProtoFields, type ProtoField } from "./protobuf.js";
+import {
+  parseExtractRequest,
+  type ExtractionCache,
+  type ServedExtraction,
+  type ServedSymbol,
+  type ServeExtractRequest,
+} from "./serve.js";
+
+/**
+ * Full name of the service.
+ */
+export const grpcServiceName = "langchain.extractor.go.v1.Extractor";
+
+/**
+ * gRPC status codes the service answers with.
+ */
+export const grpcStatus = {
+  ok: 0,
+  invalidArgument: 3,
+  notFound: 5,
+  unimplemented: 12,
+  internal: 13,
+} as const;
+
+/**
+ * A failed call and its gRPC status code.
+ */
+export class GrpcError extends Error {
+  readonly code: number;
+
+  constructor(code: number, message: string) {
+    super(message);
+    this.code = code;
+  }
+}
+
+/**
+ * Sends one response message of a call.
+ */
+type Send = (message: ProtoWriter) => void;
+
+/**
+ * A method: takes the request message's fields and sends its responses.
+ */
+type GrpcMethod = (request: ProtoField[], send: Send) => Promise<void>;
+
+/**
+ * Prefix a message with the gRPC frame header: not compressed, and its
+ * length.
+ */
+export function frameGrpcMessage(message: Uint8Array): Buffer {
+  const frame = Buffer.alloc(5 + message.length);
+  frame.writeUInt8(0, 0);
+  frame.writeUInt32BE(message.length, 1);
+  frame.set(message, 5);
+  return frame;
+}
+
+/**
+ * Split a gRPC body into its messages. Compressed messages aren't supported.
+ */
+export function readGrpcMessages(body: Buffer): Uint8Array[] {
+  const messages: Uint8Array[] = [];
+  let offset = 0;
+  while (offset < body.length) {
+    if (offset + 5 > body.length) throw new Error("Truncated gRPC frame");
+    if (body.readUInt8(offset) !== 0) throw new Error("Compressed gRPC messages aren't supported");
+    const length = body.readUInt32BE(offset + 1);
+    if (offset + 5 + length > body.length) throw new Error("Truncated gRPC message");
+    messages.push(body.subarray(offset + 5, offset + 5 + length));
+    offset += 5 + length;
+  }
+  return messages;
+}
+
+/**
+ * Encode a symbol as a `Symbol` message.
+ */
+export function encodeSymbol(symbol: ServedSymbol): ProtoWriter {
+  // Profiled symbols have no docs object, and no source location
+  const record = "docs" in symbol ? symbol : undefined;
+  const summary = record ? record.docs.summary : (symbol as SummarySymbol).summary;
+  const signature = record ? record.signature : (symbol as SummarySymbol).signature;
+  return new ProtoWriter()
+    .string(1, symbol.id)
+    .string(2, symbol.name)
+    .string(3, symbol.qualifiedName)
+    .string(4, symbol.kind)
+    .string(5, signature ?? "")
+    .string(6, summary ?? "")
+    .string(7, record?.source.path ?? "")
+    .varint(8, record?.source.line ?? 0)
+    .string(9, JSON.stringify(symbol));
+}
+
+/**
+ * Encode an extraction's package header as a `Package` message.
+ */
+export function encodePackage(extraction: ServedExtraction): ProtoWriter {
+  const pkg = extraction.output.package;
+  return new ProtoWriter()
+    .string(1, pkg.packageId)
+    .string(2, extraction.module)
+    .string(3, extraction.version)
+    .string(4, pkg.synopsis ?? "")
+    .varint(5, extraction.output.symbols.length)
+    .string(6, JSON.stringify(pkg));
+}
+
+/**
+ * Encode a diff of two versions as a `DiffVersionsResponse` message.
+ */
+export function encodeDiff(from: string, to: string, diff: OutputDiff): ProtoWriter {
+  const writer = new ProtoWriter()
+    .string(1, from)
+    .string(2, to)
+    .strings(3, diff.packageFields)
+    .strings(4, diff.added)
+    .strings(5, diff.removed);
+  for (const change of diff.changed) {
+    writer.message(
+      6,
+      new ProtoWriter()
+        .string(1, change.id)
+        .strings(2, change.fields)
+        .string(3, change.signature?.before ?? "")
+        .string(4, change.signature?.after ?? ""),
+    );
+  }
+  return writer;
+}
+
+/**
+ * Read an `ExtractPackageRequest`, filling in its defaults.
+ */
+function extractRequest(request: ProtoField[]): Required<ServeExtractRequest> {
+  return validRequest({
+    module: protoString(request, 1),
+    version: protoString(request, 2),
+    profile: protoString(request, 3),
+  });
+}
+
+/**
+ * Fill in the defaults of a request, answering invalid ones with
+ * INVALID_ARGUMENT.
+ */
+function validRequest(request: ServeExtractRequest): Required<ServeExtractRequest> {
+  try {
+    return parseExtractRequest(request);
+  } catch (error) {
+    throw new GrpcError(grpcStatus.invalidArgument, (error as Error).message);
+  }
+}
+
+/**
+ * The methods of the service, backed by an extraction cache.
+ */
+function grpcMethods(cache: ExtractionCache): Record<string, GrpcMethod> {
+  return {
+    async ExtractPackage(request, send) {
+      const extraction = await cache.extract(extractRequest(request));
+      const response = new ProtoWriter().message(1, encodePackage(extraction));
+      for (const symbol of extraction.output.symbols) {
+        response.message(2, encodeSymbol(symbol));
+      }
+      send(response);
+    },
+
+    async StreamSymbols(request, send) {
+      const extraction = await cache.extract(extractRequest(request));
+      for (const symbol of extraction.output.symbols) {
+        send(encodeSymbol(symbol));
+      }
+    },
+
+    async GetSymbol(request, send) {
+      const id = protoString(request, 3);
+      if (!id) throw new GrpcError(grpcStatus.invalidArgument, "id is required");
+      // Field 3 is the id here, not a profile
+      const module = protoString(request, 1);
+      const version = protoString(request, 2);
+      const symbol = await cache.symbol(validRequest({ module, version }), id);
+      if (!symbol) throw new GrpcError(grpcStatus.notFound, `Unknown symbol: ${id}`);
+      send(encodeSymbol(symbol));
+    },
+
+    async DiffVersions(request, send) {
+      const module = protoString(request, 1);
+      const versions = [protoString(request, 2), protoString(request, 3)];
+      if (versions.some((version) => !version)) {
+        throw new GrpcError(grpcStatus.invalidArgument, "from_version and to_version are required");
+      }
+      const [from, to] = await Promise.all(
+        versions.map((version) => cache.extract(validRequest({ module, version }))),
+      );
+      send(encodeDiff(from.version, to.version, diffOutputs(from.output, to.output)));
+    },
+  };
+}
+
+/**
+ * Create an HTTP/2 server answering the service's calls from an extraction
+ * cache.
+ */
+export function createGrpcServer(cache: ExtractionCache): Http2Server {
+  const methods = grpcMethods(cache);
+  const server = createServer();
+  server.on("stream", (stream, headers) => {
+    const chunks: Buffer[] = [];
+    stream.on("data", (chunk: Buffer) => chunks.push(chunk));
+    stream.on("end", () => {
+      void call(stream, String(headers[":path"]), Buffer.concat(chunks), methods);
+    });
+  });
+  return server;
+}
+
+/**
+ * Answer a call: the response messages, then the status trailers.
+ */
+async function call(
+  stream: ServerHttp2Stream,
+  path: string,
+  body: Buffer,
+  methods: Record<string, GrpcMethod>,
+): Promise<void> {
+  let status: number = grpcStatus.ok;
+  let message = "";
+  stream.respond({ ":status": 200, "content-type": "application/grpc" }, { waitForTrailers: true });
+  stream.on("wantTrailers", () => {
+    stream.sendTrailers({
+      "grpc-status": String(status),
+      ...(message ? { "grpc-message": encodeURIComponent(message) } : {}),
+    });
+  });
+
+  try {
+    const prefix = `/${grpcServiceName}/`;
+    const name = path.slice(prefix.length);
+    if (!path.startsWith(prefix) || !Object.hasOwn(methods, name)) {
+      throw new GrpcError(grpcStatus.unimplemented, `Unknown method: ${path}`);
+    }
+    const [request] = readGrpcMessages(body);
+    await methods[name](readProtoFields(request ?? new Uint8Array()), (response) => {
+      if (!stream.destroyed) stream.write(frameGrpcMessage(response.finish()));
+    });
+  } catch (error) {
+    status = error instanceof GrpcError ? error.code : grpcStatus.internal;
+    message = error instanceof Error ? error.message : String(error);
+  }
+  if (!stream.destroyed) stream.end();
+}
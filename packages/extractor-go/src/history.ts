@@ -0,0 +1,202 @@
+/**
+ * Symbol History
+ *
+ * Serves how a symbol's signature and docs evolved across the versions kept
+ * in a versioned extraction store, for "what changed in this API" views.
+ *
+ * The store is a directory with one `<version>/symbols.json` extraction
+ * output per version, which may be gzip- or zstd-compressed
+ * (`symbols.json.gz`, `symbols.json.zst`). `GET /symbols/{id}/history` returns the timeline of the
+ * symbol, oldest version first.
+ */
+
+import { createServer, type Server } from "http";
+import { readdir, readFile } from "fs/promises";
+import { join } from "path";
+import type { SymbolRecord } from "@langchain/ir-schema";
+import type { ExtractorOutput } from "./output.js";
+import { compressionExtensions, decompress } from "./compress.js";
+
+/**
+ * One version of the extraction store.
+ */
+export interface VersionedOutput {
+  version: string;
+  output: ExtractorOutput;
+}
+
+/**
+ * What changed in a symbol since the previous version.
+ *
+ * - `added`: the symbol first appears in this version
+ * - `signature`: the Go declaration changed
+ * - `docs`: the summary or description changed
+ * - `removed`: the symbol no longer exists in this version
+ */
+export type SymbolChange = "added" | "signature" | "docs" | "removed";
+
+/**
+ * The state of a symbol in one version. Removed entries carry no signature or
+ * docs.
+ */
+export interface SymbolHistoryEntry {
+  version: string;
+  changes: SymbolChange[];
+  signature?: string;
+  summary?: string;
+  description?: string;
+}
+
+/**
+ * The timeline of a symbol across versions, oldest first. Versions in which
+ * nothing changed are omitted.
+ */
+export interface SymbolHistory {
+  symbolId: string;
+  qualifiedName: string;
+  entries: SymbolHistoryEntry[];
+}
+
+const HISTORY_ROUTE = /^\/symbols\/([^/]+)\/history\/?$/;
+
+/**
+ * Order version strings by their numeric parts, e.g. "v0.9.0" before
+ * "v0.10.0". Pre-releases sort before their release.
+ */
+export function compareVersions(a: string, b: string): number {
+  const parts = (version: string) => version.replace(/^v/, "").split(/[.+-]/);
+  const left = parts(a);
+  const right = parts(b);
+  for (let i = 0; i < Math.max(left.length, right.length); i++) {
+    if (left[i] === undefined) return 1;
+    if (right[i] === undefined) return -1;
+    const diff = Number(left[i]) - Number(right[i]);
+    if (!Number.isNaN(diff) && diff !== 0) return diff;
+    if (Number.isNaN(diff) && left[i] !== right[i]) {
+      return left[i] < right[i] ? -1 : 1;
+    }
+  }
+  return 0;
+}
+
+/**
+ * Names a version's output may have: uncompressed first, then compressed.
+ */
+const OUTPUT_FILES = [
+  "symbols.json",
+  ...Object.values(compressionExtensions).map((extension) => `symbols.json${extension}`),
+];
+
+/**
+ * Read every `<version>/symbols.json` of a store directory, ordered by
+ * version. Version directories without an output are skipped.
+ */
+export async function loadVersionStore(dir: string): Promise<VersionedOutput[]> {
+  const entries = await readdir(dir, { withFileTypes: true });
+  const versions: VersionedOutput[] = [];
+  for (const entry of entries) {
+    if (!entry.isDirectory()) continue;
+    for (const file of OUTPUT_FILES) {
+      try {
+        const content = decompress(await readFile(join(dir, entry.name, file))).toString("utf-8");
+        versions.push({ version: entry.name, output: JSON.parse(content) as ExtractorOutput });
+        break;
+      } catch (error) {
+        if ((error as NodeJS.ErrnoException).code !== "ENOENT") throw error;
+      }
+    }
+  }
+  return versions.sort((a, b) => compareVersions(a.version, b.version));
+}
+
+/**
+ * Build the timeline of a symbol, by ID or qualified name. Returns undefined
+ * if no version has the symbol.
+ */
+export function symbolHistory(
+  versions: VersionedOutput[],
+  symbol: string,
+): SymbolHistory | undefined {
+  let found: SymbolRecord | undefined;
+  let previous: SymbolRecord | undefined;
+  const entries: SymbolHistoryEntry[] = [];
+
+  for (const { version, output } of versions) {
+    const current = output.symbols.find((s) => s.id === symbol || s.qualifiedName === symbol);
+    if (!current) {
+      if (previous) {
+        entries.push({ version, changes: ["removed"] });
+      }
+      previous = undefined;
+      continue;
+    }
+
+    const changes: SymbolChange[] = [];
+    if (!previous) {
+      changes.push("added");
+    } else {
+      if (current.signature !== previous.signature) changes.push("signature");
+      if (
+        current.docs.summary !== previous.docs.summary ||
+        current.docs.description !== previous.docs.description
+      ) {
+        changes.push("docs");
+      }
+    }
+    if (changes.length > 0) {
+      entries.push({
+        version,
+        changes,
+        signature: current.signature,
+        summary: current.docs.summary,
+        description: current.docs.description,
+      });
+    }
+    found = current;
+    previous = current;
+  }
+
+  if (!found) return undefined;
+  return { symbolId: found.id, qualifiedName: found.qualifiedName, entries };
+}
+
+/**
+ * Get the symbol a request path asks the history of, or undefined if the path
+ * is not a history route.
+ */
+export function matchHistoryRoute(pathname: string): string | undefined {
+  const match = HISTORY_ROUTE.exec(pathname);
+  return match ? decodeURIComponent(match[1]) : undefined;
+}
+
+/**
+ * Create an HTTP server answering `GET /symbols/{id}/history` from the store.
+ * `loadVersions` is called per request, so versions added to the store are
+ * picked up without a restart.
+ */
+export function createHistoryServer(loadVersions: () => Promise<VersionedOutput[]>): Server {
+  return createServer(async (request, response) => {
+    const send = (status: number, body: unknown) => {
+      response.writeHead(status, { "Content-Type": "application/json" });
+      response.end(JSON.stringify(body));
+    };
+
+    const symbol = matchHistoryRoute(new URL(request.url ?? "/", "http://localhost").pathname);
+    if (symbol === undefined) {
+      return send(404, { error: "Not found" });
+    }
+    if (request.method !== "GET") {
+      return send(405, { error: `Method not allowed: ${request.method}` });
+    }
+
+    try {
+      const history = symbolHistory(await loadVersions(), symbol);
+      if (!history) {
+        return send(404, { error: `Unknown symbol: ${symbol}` });
+      }
+      send(200, history);
+    } catch (error) {
+      send(500, { error: error instanceof Error ? error.message : String(error) });
+    }
+  });
+}
@@ -0,0 +1,224 @@
+/**
+ * Hover Docs
+ *
+ * Answers "what is the doc for the symbol at this position?" from the latest
+ * extraction output, so editor plugins and the docs preview show exactly the
+ * doc a reader of the reference will get.
+ *
+ * The endpoint speaks JSON-RPC 2.0, one message per line, with a single
+ * `hover` method taking an LSP-style position:
+ * `{ "file": "client.go", "line": 11, "character": 7 }` (0-based).
+ */
+
+import { readFile, stat } from "fs/promises";
+import { createInterface } from "readline";
+import { isAbsolute, relative, resolve } from "path";
+import type { Readable, Writable } from "stream";
+import type { SymbolRecord } from "@langchain/ir-schema";
+import type { ExtractorOutput } from "./output.js";
+import { decompress } from "./compress.js";
+
+/**
+ * Parameters of a `hover` request.
+ */
+export interface HoverParams {
+  /** Source file, absolute or relative to the package path */
+  file: string;
+  /** 0-based line */
+  line: number;
+  /** 0-based character offset in the line */
+  character: number;
+}
+
+/**
+ * Result of a `hover` request.
+ */
+export interface HoverResult {
+  symbol: Pick<SymbolRecord, "id" | "qualifiedName" | "kind" | "signature">;
+  contents: { kind: "markdown"; value: string };
+}
+
+/**
+ * A JSON-RPC 2.0 request.
+ */
+export interface JsonRpcRequest {
+  jsonrpc: "2.0";
+  id?: number | string;
+  method: string;
+  params?: unknown;
+}
+
+/**
+ * A JSON-RPC 2.0 response.
+ */
+export interface JsonRpcResponse {
+  jsonrpc: "2.0";
+  id: number | string | null;
+  result?: unknown;
+  error?: { code: number; message: string };
+}
+
+const PARSE_ERROR = -32700;
+const METHOD_NOT_FOUND = -32601;
+const INVALID_PARAMS = -32602;
+const INTERNAL_ERROR = -32603;
+
+/**
+ * Looks up symbols by source position.
+ */
+export class HoverIndex {
+  private symbols: SymbolRecord[];
+  private sourceRoot: string;
+
+  constructor(output: ExtractorOutput, sourceRoot: string) {
+    // Summary-profile outputs carry no source locations
+    this.symbols = output.symbols.filter((symbol) => symbol.source);
+    this.sourceRoot = sourceRoot;
+  }
+
+  /**
+   * Find the symbol for a position: the identifier under the cursor when it
+   * names an extracted symbol (preferring declarations in the same file),
+   * otherwise the declaration enclosing the position.
+   */
+  async hover(params: HoverParams): Promise<HoverResult | null> {
+    const file = isAbsolute(params.file)
+      ? relative(this.sourceRoot, params.file).replace(/\\/g, "/")
+      : params.file;
+
+    let text: string;
+    try {
+      text = await readFile(resolve(this.sourceRoot, file), "utf-8");
+    } catch {
+      return null;
+    }
+
+    const lineText = text.split("\n")[params.line] ?? "";
+    const word = wordAt(lineText, params.character);
+    const symbol =
+      (word && this.byName(word, file, params.line)) || this.enclosing(file, params.line);
+    return symbol ? toHover(symbol) : null;
+  }
+
+  private byName(word: string, file: string, line: number): SymbolRecord | undefined {
+    const candidates = this.symbols.filter((s) => s.name === word);
+    const sameFile = candidates.filter((s) => s.source.path === file);
+    // The closest declaration in the file wins, then the first anywhere
+    const distance = (s: SymbolRecord) => Math.abs(s.source.line - 1 - line);
+    return sameFile.sort((a, b) => distance(a) - distance(b))[0] ?? candidates[0];
+  }
+
+  private enclosing(file: string, line: number): SymbolRecord | undefined {
+    return this.symbols
+      .filter((s) => s.source.path === file && s.source.line - 1 <= line)
+      .sort((a, b) => b.source.line - a.source.line)[0];
+  }
+}
+
+/**
+ * Render a symbol's hover doc: its signature followed by its description.
+ */
+export function formatHover(symbol: SymbolRecord): string {
+  const parts = [`\`\`\`go\n${symbol.signature}\n\`\`\``];
+  if (symbol.docs.deprecated?.isDeprecated) {
+    const message = symbol.docs.deprecated.message;
+    parts.push(`**Deprecated**${message ? `: ${message}` : ""}`);
+  }
+  const doc = symbol.docs.description || symbol.docs.summary;
+  if (doc) {
+    parts.push(doc);
+  }
+  return parts.join("\n\n");
+}
+
+/**
+ * Serve JSON-RPC hover requests from `input` to `output`, one message per line.
+ * `loadIndex` is called for every request so answers follow the latest output.
+ */
+export async function serveHover(
+  input: Readable,
+  output: Writable,
+  loadIndex: () => Promise<HoverIndex>,
+): Promise<void> {
+  const send = (response: JsonRpcResponse) => output.write(`${JSON.stringify(response)}\n`);
+
+  for await (const line of createInterface({ input, crlfDelay: Infinity })) {
+    if (!line.trim()) continue;
+
+    let request: JsonRpcRequest;
+    try {
+      request = JSON.parse(line) as JsonRpcRequest;
+    } catch {
+      send({ jsonrpc: "2.0", id: null, error: { code: PARSE_ERROR, message: "Parse error" } });
+      continue;
+    }
+    // Notifications get no reply
+    if (request.id === undefined) continue;
+
+    const reply = (body: Pick<JsonRpcResponse, "result" | "error">) =>
+      send({ jsonrpc: "2.0", id: request.id!, ...body });
+
+    if (request.method !== "hover") {
+      reply({ error: { code: METHOD_NOT_FOUND, message: `Unknown method: ${request.method}` } });
+      continue;
+    }
+    const params = request.params as HoverParams | undefined;
+    if (
+      typeof params?.file !== "string" ||
+      !Number.isInteger(params.line) ||
+      !Number.isInteger(params.character)
+    ) {
+      reply({ error: { code: INVALID_PARAMS, message: "Expected { file, line, character }" } });
+      continue;
+    }
+
+    try {
+      reply({ result: await (await loadIndex()).hover(params) });
+    } catch (error) {
+      const message = error instanceof Error ? error.message : String(error);
+      reply({ error: { code: INTERNAL_ERROR, message } });
+    }
+  }
+}
+
+/**
+ * Load a hover index from an output file, which may be compressed,
+ * re-reading it only when it changes.
+ */
+export function watchOutputFile(
+  outputPath: string,
+  sourceRoot?: string,
+): () => Promise<HoverIndex> {
+  let cached: { mtimeMs: number; index: HoverIndex } | undefined;
+  return async () => {
+    const { mtimeMs } = await stat(outputPath);
+    if (cached?.mtimeMs !== mtimeMs) {
+      const content = decompress(await readFile(outputPath)).toString("utf-8");
+      const output = JSON.parse(content) as ExtractorOutput;
+      const root = resolve(sourceRoot ?? output.package.repo.path);
+      cached = { mtimeMs, index: new HoverIndex(output, root) };
+    }
+    return cached.index;
+  };
+}
+
+function wordAt(line: string, character: number): string | undefined {
+  for (const match of line.matchAll(/\w+/g)) {
+    if (match.index <= character && character <= match.index + match[0].length) {
+      return match[0];
+    }
+  }
+  return undefined;
+}
+
+function toHover(symbol: SymbolRecord): HoverResult {
+  return {
+    symbol: {
+      id: symbol.id,
+      qualifiedName: symbol.qualifiedName,
+      kind: symbol.kind,
+      signature: symbol.signature,
+    },
+    contents: { kind: "markdown", value: formatHover(symbol) },
+  };
+}
@@ -0,0 +1,96 @@
+/**
+ * HTML Rendering
+ *
+ * Renders parsed doc comments as HTML fragments, after the HTML printer of
+ * go/doc/comment, for embedding straight into the docs site. Doc links to
+ * symbols of the package go through the caller's URL mapping, so links can
+ * follow the site's routes instead of the extractor's canonical paths.
+ */
+
+import type { DocBlock, DocText } from "./doc-comment.js";
+
+/**
+ * Options of the HTML renderer.
+ */
+export interface HtmlOptions {
+  /** Level of doc comment headings (default: 3, as in go/doc/comment) */
+  headingLevel?: number;
+  /** URL of a symbol of the package, for doc links resolved to a symbol ID */
+  symbolUrl?: (refId: string) => string | undefined;
+}
+
+/**
+ * Render parsed doc comment blocks as an HTML fragment.
+ */
+export function renderHtml(blocks: DocBlock[], options: HtmlOptions = {}): string {
+  const level = options.headingLevel ?? 3;
+
+  return blocks
+    .map((block) => {
+      switch (block.kind) {
+        case "paragraph":
+          return `<p>${renderText(block.text, options)}</p>`;
+        case "heading": {
+          const id = headingId(block.text);
+          return `<h${level} id="${id}">${renderText(block.text, options)}</h${level}>`;
+        }
+        case "list": {
+          const tag = block.ordered ? "ol" : "ul";
+          const items = block.items.map((item) => {
+            const value = block.ordered && item.number ? ` value="${item.number}"` : "";
+            return `<li${value}>${renderText(item.text, options)}</li>`;
+          });
+          return `<${tag}>\n${items.join("\n")}\n</${tag}>`;
+        }
+        case "code": {
+          const code = escapeHtml(block.text);
+          return `<pre><code class="language-${block.language}">${code}</code></pre>`;
+        }
+        case "verbatim":
+          return `<pre>${escapeHtml(block.text)}</pre>`;
+        case "callout":
+          return (
+            `<aside class="callout callout-${block.callout}">` +
+            `<p>${renderText(block.text, options)}</p></aside>`
+          );
+      }
+    })
+    .join("\n");
+}
+
+/**
+ * Render inline text, linking URLs and resolved doc links.
+ */
+function renderText(text: DocText[], options: HtmlOptions): string {
+  return text
+    .map((span) => {
+      const escaped = escapeHtml(span.text);
+      if (span.kind === "plain") return escaped;
+
+      const url =
+        span.kind === "link"
+          ? span.url
+          : (span.url ?? (span.refId ? options.symbolUrl?.(span.refId) : undefined));
+      return url ? `<a href="${escapeHtml(url)}">${escaped}</a>` : escaped;
+    })
+    .join("");
+}
+
+/**
+ * Anchor of a heading, as go/doc/comment builds it: `hdr-` followed by the
+ * heading text with everything but letters and digits replaced by `_`.
+ */
+export function headingId(text: DocText[]): string {
+  const plain = text.map((span) => span.text).join("");
+  return `hdr-${plain.replace(/[^\p{L}\p{N}]/gu, "_")}`;
+}
+
+/**
+ * Escape text for HTML element content and attribute values.
+ */
+export function escapeHtml(text: string): string {
+  return text.replace(
+    /[&<>"']/g,
+    (c) => ({ "&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;", "'": "&#39;" })[c]!,
+  );
+}
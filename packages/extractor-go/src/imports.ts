@@ -0,0 +1,64 @@
+/**
+ * Go Imports
+ *
+ * Reads the import declarations of Go files, mapping the name a package is
+ * referred to by in a file to its import path.
+ */
+
+/**
+ * Import paths by the name they are referred to in a file.
+ */
+export type FileImports = Map<string, string>;
+
+/**
+ * Parse the imports of a Go file. Blank and dot imports are left out, as no
+ * name refers to them.
+ */
+export function parseImports(content: string): FileImports {
+  const imports: FileImports = new Map();
+  for (const [name, path] of importSpecs(content)) {
+    if (name === "_" || name === ".") continue;
+    imports.set(name ?? importName(path), path);
+  }
+  return imports;
+}
+
+/**
+ * The import paths of a Go file, blank and dot imports included.
+ */
+export function importPaths(content: string): string[] {
+  return importSpecs(content).map(([, path]) => path);
+}
+
+/**
+ * The import specs of a Go file, as their name, if any, and import path.
+ */
+function importSpecs(content: string): [string | undefined, string][] {
+  const specs: string[] = [];
+  for (const match of content.matchAll(/^import\s*\(([\s\S]*?)\)/gm)) {
+    specs.push(...match[1].split("\n"));
+  }
+  for (const match of content.matchAll(/^import\s+([^(\n].*)$/gm)) {
+    specs.push(match[1]);
+  }
+
+  const parsed: [string | undefined, string][] = [];
+  for (const spec of specs) {
+    const match = spec.trim().match(/^(?:([\w.]+)\s+)?"([^"]+)"/);
+    if (match) parsed.push([match[1], match[2]]);
+  }
+  return parsed;
+}
+
+/**
+ * The default name of an imported package: its last path element, without a
+ * major version suffix (`/v2`) or gopkg.in version (`.v1`).
+ */
+export function importName(path: string): string {
+  const elements = path.split("/");
+  let name = elements.pop()!;
+  if (/^v\d+$/.test(name) && elements.length > 0) {
+    name = elements.pop()!;
+  }
+  return name.replace(/\.v\d+$/, "").replace(/^go-/, "").replace(/[^\w]/g, "_");
+}
@@ -5,14 +5,441 @@
  * for the LangChain reference docs platform.
  */
 
-export { type GoExtractorConfig, defaultConfig, createConfig, validateConfig } from "./config.js";
+export {
+  type GoExtractorConfig,
+  type VisibilityTier,
+  visibilityTiers,
+  type InternalPackagePolicy,
+  internalPackagePolicies,
+  type VendorPolicy,
+  vendorPolicies,
+  type GeneratedPolicy,
+  generatedPolicies,
+  type AssertionPolicy,
+  assertionPolicies,
+  type ExampleVerification,
+  exampleVerifications,
+  defaultConfig,
+  defaultFeedbackUrlTemplate,
+  createConfig,
+  validateConfig,
+} from "./config.js";
 export {
   GoExtractor,
   type GoType,
   type GoMethod,
   type GoField,
   type GoConst,
+  type GoFuncLiteral,
   type GoParameter,
+  type GoTypeParam,
+  type GoOwnership,
+  type GoCompositeLiteral,
+  type GoFieldDefault,
+  type GoExternalImplementation,
+  type GoExample,
+  type GoTestFunction,
+  type GoNote,
   type ExtractionResult,
+  type ParsedFile,
 } from "./extractor.js";
-export { GoTransformer } from "./transformer.js";
+export {
+  GoTransformer,
+  type GoSymbolRecord,
+  type GoTestReference,
+  type GoSymbolParam,
+  type GoSymbolReturns,
+  type GoMemberReference,
+  type GoSymbolUrls,
+  type GoSymbolDocs,
+  type GoSymbolValue,
+  type GoContextInfo,
+  type GoDeprecationInfo,
+  type GoReplacement,
+  type GoRelatedSymbol,
+} from "./transformer.js";
+export {
+  evaluateConstExpr,
+  convertConstValue,
+  formatDuration,
+  type ConstScope,
+  type GoConstValue,
+} from "./const-eval.js";
+export {
+  parseTypeExpr,
+  parseResultTypes,
+  collectNamedTypes,
+  isBuiltinType,
+  formatTypeExpr,
+  type GoTypeExpr,
+  type GoNamedTypeExpr,
+  type GoStructFieldExpr,
+  type GoInterfaceMethodExpr,
+} from "./type-expr.js";
+export {
+  findDuplicatePackages,
+  hashPackageFiles,
+  type PackageDirectory,
+  type PackageDuplicate,
+} from "./dedup.js";
+export {
+  applyProfile,
+  summarizeOutput,
+  signaturesOutput,
+  extractionProfiles,
+  type ExtractionProfile,
+  type ProfiledOutput,
+  type SummaryOutput,
+  type SummarySymbol,
+} from "./profile.js";
+export {
+  buildOutput,
+  buildOutputLabels,
+  buildPackageId,
+  groupCategories,
+  groupNotes,
+  outputFormats,
+  packageSynopsis,
+  serializeOutput,
+  type ExtractorOutput,
+  type OutputCategory,
+  type OutputFormat,
+  type OutputNote,
+  type OutputPackage,
+} from "./output.js";
+export { renderStubs } from "./stubs.js";
+export { escapeMdx, mdxPageSymbols, renderMdx, type MdxOptions } from "./mdx.js";
+export {
+  buildDocusaurusSidebar,
+  docusaurusFrontMatter,
+  docusaurusSidebarFile,
+  type DocusaurusFrontMatter,
+  type DocusaurusSidebarItem,
+} from "./docusaurus.js";
+export { renderGodoc, writeGodoc } from "./godoc.js";
+export { renderMermaid } from "./mermaid.js";
+export {
+  compress,
+  compressFile,
+  compressedPath,
+  compressionExtensions,
+  compressions,
+  decompress,
+  detectCompression,
+  uncompressedPath,
+  type Compression,
+} from "./compress.js";
+export {
+  buildTarget,
+  hostGoarch,
+  hostGoos,
+  knownGoarch,
+  knownGoos,
+  matchBuildConstraint,
+  matchBuildTag,
+  matchFileName,
+  type BuildTarget,
+} from "./build-constraints.js";
+export {
+  collectProvenance,
+  provenanceTimestamp,
+  withProvenance,
+  type Provenance,
+} from "./provenance.js";
+export {
+  configFileName,
+  findConfigFile,
+  loadConfigFile,
+  parseConfigFile,
+  type ConfigFile,
+} from "./config-file.js";
+export { parseYaml, YamlError } from "./yaml.js";
+export {
+  normalizeExcludeGlob,
+  packageIncludePatterns,
+  packagePatternMatcher,
+  resolvePackagePatterns,
+} from "./packages.js";
+export {
+  defaultGoProxy,
+  defaultModuleCache,
+  downloadModule,
+  escapeModulePath,
+  githubRepo,
+  goProxyUrl,
+  moduleName,
+  moduleRevision,
+  parseModuleSpec,
+  readZip,
+  resolveModuleVersion,
+  type DownloadOptions,
+  type DownloadedModule,
+  type ModuleSpec,
+} from "./remote.js";
+export {
+  buildWorkspaceManifest,
+  goWorkFileName,
+  loadWorkspace,
+  moduleVersion,
+  nestedModuleExcludes,
+  parseGoWork,
+  pseudoVersion,
+  workspaceManifestFile,
+  workspaceModuleFile,
+  type GoWork,
+  type GoWorkspace,
+  type WorkspaceManifest,
+  type WorkspaceManifestEntry,
+  type WorkspaceModule,
+} from "./workspace.js";
+export { inventoryColumns, renderInventory, type InventoryDelimiter } from "./inventory.js";
+export { jsonlRecords, parseJsonl } from "./jsonl.js";
+export { defaultLlmsMaxChars, renderLlmsText } from "./llms.js";
+export {
+  buildSymbolGraph,
+  symbolGraphFormatVersion,
+  type SymbolGraph,
+  type SymbolGraphEdge,
+  type SymbolGraphEdgeKind,
+  type SymbolGraphNode,
+} from "./graph.js";
+export {
+  buildScipIndex,
+  encodeScipIndex,
+  writeScip,
+  type ScipDocument,
+  type ScipIndex,
+  type ScipOccurrence,
+  type ScipSymbol,
+} from "./scip.js";
+export { searchIndexAdapters, searchRecords, type SearchIndexAdapter } from "./search.js";
+export {
+  sqliteSchema,
+  sqliteTables,
+  writeSqlite,
+  type SqliteRefKind,
+  type SqliteTables,
+  type SqliteValue,
+} from "./sqlite.js";
+export {
+  unifyOutput,
+  unifySymbol,
+  type UnifiedOutput,
+  type UnifiedPackage,
+} from "./unified.js";
+export {
+  VERIFY_MARKER,
+  formatExampleProblems,
+  verifyExamples,
+  type ExampleProblem,
+} from "./verify-examples.js";
+export {
+  defaultLintSeverities,
+  formatLintIssues,
+  lintPackage,
+  lintRules,
+  lintSeverities,
+  parseLintSeverities,
+  type LintIssue,
+  type LintRule,
+  type LintSeverity,
+} from "./lint.js";
+export {
+  buildCoverage,
+  coverageKinds,
+  formatCoverage,
+  type CoverageCount,
+  type CoverageKind,
+  type DocCoverage,
+  type PackageCoverage,
+} from "./coverage.js";
+export { compareCanonical, sortSymbols } from "./canonical.js";
+export { buildToc, type TocNode, type TocNodeKind } from "./toc.js";
+export {
+  splitManifestFile,
+  splitOutput,
+  type SplitManifest,
+  type SplitManifestEntry,
+  type SplitPackage,
+} from "./split.js";
+export { escapeMarkdown, renderMarkdown, type MarkdownOptions } from "./markdown.js";
+export { escapeHtml, headingId, renderHtml, type HtmlOptions } from "./html.js";
+export { buildExample, packageExamples, type GoSymbolExample } from "./examples.js";
+export { loadSidecarDocs, type SidecarDoc } from "./sidecar.js";
+export {
+  formatSchemaErrors,
+  outputSchema,
+  outputSchemaVersion,
+  validateJson,
+  validateOutput,
+  type JsonSchema,
+  type SchemaError,
+} from "./schema.js";
+export {
+  defaultDocTags,
+  docLinkSpans,
+  docToMarkdown,
+  markCallouts,
+  parseDocComment,
+  parseDocText,
+  resolveDocLinks,
+  seeAlsoTargets,
+  splitDocTags,
+  type CalloutKind,
+  type DocAnnotations,
+  type DocBlock,
+  type DocLinkSpan,
+  type DocLinkTarget,
+  type DocListItem,
+  type DocText,
+} from "./doc-comment.js";
+export {
+  buildCapabilities,
+  capabilityNames,
+  type Capabilities,
+  type Capability,
+  type CapabilityStatus,
+} from "./capabilities.js";
+export {
+  diffOutputs,
+  formatDiff,
+  hasChanges,
+  type OutputDiff,
+  type SymbolChange,
+} from "./check.js";
+export {
+  OwnershipResolver,
+  parseCodeowners,
+  loadOwnership,
+  type OwnershipRule,
+} from "./codeowners.js";
+export {
+  checkConformance,
+  runConformance,
+  CONFORMANCE_FIXTURES_PATH,
+  type ConformanceIssue,
+  type ConformanceReport,
+} from "./conformance.js";
+export { normalizeSlug, resolveSlugCollisions, type SlugCollision } from "./slugs.js";
+export {
+  truncateDoc,
+  docLimitFor,
+  defaultDocLimits,
+  type DocLimit,
+  type DocLimits,
+} from "./truncate.js";
+export { applyInternalPolicy, isInternalPath } from "./internal-packages.js";
+export {
+  TimingRecorder,
+  formatTimings,
+  timingPhases,
+  type TimingPhase,
+  type PhaseTimings,
+  type PackageTiming,
+  type TimingReport,
+} from "./timings.js";
+export { ParseCache, type ParseCacheStats } from "./parse-cache.js";
+export {
+  PackageCache,
+  type PackageCacheStats,
+  type PackageSource,
+  type CachedPackage,
+} from "./package-cache.js";
+export {
+  WorkerPool,
+  WorkerLimitError,
+  defaultWorkerCount,
+  parseWorkerCount,
+  forEachLimit,
+  workerMemoryLimit,
+  minWorkerMemoryMb,
+  type PoolWorker,
+  type WorkerReply,
+} from "./worker-pool.js";
+export {
+  affectedPackages,
+  defaultWatchDebounceMs,
+  isWatchedFile,
+  splitChanges,
+  watchSources,
+  type SplitChanges,
+  type WatchOptions,
+} from "./watch.js";
+export {
+  buildChunks,
+  chunkKinds,
+  defaultChunkTokens,
+  estimateTokens,
+  ragChunks,
+  selectSymbols,
+  streamChunks,
+  type ChunkKind,
+  type ChunkOptions,
+  type DocChunk,
+  type RagChunk,
+} from "./chunks.js";
+export {
+  ExtractionDaemon,
+  DaemonClient,
+  defaultDaemonSocket,
+  type ChunkStreamSummary,
+  type DaemonChunkParams,
+  type DaemonExtractParams,
+  type DaemonRequest,
+  type DaemonResponse,
+  type DaemonStats,
+} from "./daemon.js";
+export {
+  extractCallChains,
+  inferChainOrder,
+  isChainable,
+  type GoBuilderInfo,
+} from "./builders.js";
+export {
+  groupLifecycle,
+  isConstructorOf,
+  lifecycleStages,
+  methodStage,
+  type GoLifecycle,
+  type LifecycleStage,
+} from "./lifecycle.js";
+export {
+  defaultLabels,
+  formatLabel,
+  loadLocaleBundle,
+  parseLocaleBundle,
+  resolveLabels,
+  type LabelKey,
+  type Labels,
+  type LocaleBundle,
+  type OutputLabels,
+} from "./labels.js";
+export { iteratorOf, type GoIteratorInfo } from "./iterators.js";
+export {
+  HoverIndex,
+  formatHover,
+  serveHover,
+  watchOutputFile,
+  type HoverParams,
+  type HoverResult,
+  type JsonRpcRequest,
+  type JsonRpcResponse,
+} from "./hover.js";
+export {
+  compareVersions,
+  createHistoryServer,
+  loadVersionStore,
+  matchHistoryRoute,
+  symbolHistory,
+  type SymbolChange,
+  type SymbolHistory,
+  type SymbolHistoryEntry,
+  type VersionedOutput,
+} from "./history.js";
+export {
+  namespaceDependencies,
+  parseModulesTxt,
+  vendorImportPath,
+  type DependencyInfo,
+  type VendorModule,
+} from "./vendor.js";
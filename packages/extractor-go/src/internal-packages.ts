@@ -0,0 +1,70 @@
+/**
+ * Internal Packages
+ *
+ * Go only lets code inside the parent of an `internal` directory import the
+ * packages below it, so their exported symbols aren't part of the public API
+ * unless a public package re-exposes them, e.g. through a type alias or a
+ * signature that mentions them.
+ */
+
+import type { InternalPackagePolicy } from "./config.js";
+import type { GoSymbolRecord } from "./transformer.js";
+
+/**
+ * Whether a source path lies in an `internal` package.
+ */
+export function isInternalPath(path: string): boolean {
+  return path.split("/").slice(0, -1).includes("internal");
+}
+
+/**
+ * Apply an internal package policy to a set of symbols.
+ *
+ * - `exclude`: drop symbols from internal packages
+ * - `include`: keep them, flagged with `internal: true`
+ * - `reexposed`: keep only those whose package-qualified name (`auth.Token`)
+ *   appears in the signature, members, or value of a public symbol, flagged
+ *   with `internal: true`; methods follow their type
+ */
+export function applyInternalPolicy(
+  symbols: GoSymbolRecord[],
+  policy: InternalPackagePolicy,
+): GoSymbolRecord[] {
+  const internal = new Set(symbols.filter((symbol) => isInternalPath(symbol.source.path)));
+  if (internal.size === 0) {
+    return symbols;
+  }
+
+  let kept = internal;
+  if (policy === "exclude") {
+    kept = new Set();
+  } else if (policy === "reexposed") {
+    const publicText = symbols
+      .filter((symbol) => !internal.has(symbol))
+      .flatMap((symbol) => [
+        symbol.signature,
+        symbol.value?.expression ?? "",
+        ...(symbol.members ?? []).map((member) => member.type ?? ""),
+      ])
+      .join("\n");
+    kept = new Set([...internal].filter((symbol) => isReexposed(symbol, publicText)));
+  }
+
+  return symbols.filter((symbol) => {
+    if (!internal.has(symbol)) return true;
+    if (!kept.has(symbol)) return false;
+    symbol.internal = true;
+    return true;
+  });
+}
+
+/**
+ * Whether a public API mentions the internal symbol (or, for a method, its
+ * type) by its package-qualified name.
+ */
+function isReexposed(symbol: GoSymbolRecord, publicText: string): boolean {
+  const dir = symbol.source.path.split("/").slice(0, -1);
+  const pkg = dir[dir.length - 1];
+  const name = symbol.qualifiedName.split(".")[0];
+  return new RegExp(`\\b${pkg}\\.${name}\\b`).test(publicText);
+}
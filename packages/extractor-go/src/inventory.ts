@@ -0,0 +1,73 @@
+/**
+ * API Inventory
+ *
+ * Renders the public API surface as a flat table, a row per symbol, for
+ * auditing and for comparing releases in a spreadsheet. Rows follow the
+ * canonical order of the output, so inventories of two releases diff line
+ * by line. CSV fields are quoted as in RFC 4180; TSV fields can't hold tabs
+ * or line breaks, which become spaces.
+ */
+
+import { posix } from "path";
+import type { ExtractorOutput } from "./output.js";
+import type { GoSymbolRecord } from "./transformer.js";
+
+/**
+ * Columns of the inventory, in order.
+ */
+export const inventoryColumns = [
+  "package",
+  "symbol",
+  "kind",
+  "signature",
+  "deprecated",
+  "since",
+  "file",
+  "line",
+] as const;
+
+/**
+ * Field delimiter of an inventory: `,` for CSV, tab for TSV.
+ */
+export type InventoryDelimiter = "," | "\t";
+
+/**
+ * Render the inventory of an output's public API, with a header row.
+ */
+export function renderInventory(
+  output: ExtractorOutput,
+  delimiter: InventoryDelimiter = ",",
+): string {
+  const module = output.package.modulePath || output.package.publishedName;
+  const rows: string[][] = [[...inventoryColumns]];
+  for (const symbol of output.symbols as GoSymbolRecord[]) {
+    if (symbol.tags.visibility !== "public" || symbol.testHelper) continue;
+    const dir = posix.dirname(symbol.source.path);
+    rows.push([
+      dir === "." ? module : `${module}/${dir}`,
+      symbol.qualifiedName,
+      symbol.kind,
+      symbol.signature,
+      symbol.docs.deprecated ? "true" : "false",
+      symbol.versionInfo?.since ?? "",
+      symbol.source.path,
+      symbol.source.line ? String(symbol.source.line) : "",
+    ]);
+  }
+  const field = delimiter === "\t" ? tsvField : csvField;
+  return rows.map((row) => `${row.map(field).join(delimiter)}\n`).join("");
+}
+
+/**
+ * A CSV field, quoted when it holds a comma, quote, or line break.
+ */
+function csvField(value: string): string {
+  return /[",\r\n]/.test(value) ? `"${value.replace(/"/g, '""')}"` : value;
+}
+
+/**
+ * A TSV field, with tabs and line breaks replaced by spaces.
+ */
+function tsvField(value: string): string {
+  return value.replace(/[\t\r\n]+/g, " ");
+}
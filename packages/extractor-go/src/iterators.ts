@@ -0,0 +1,44 @@
+/**
+ * Iterator APIs
+ *
+ * Detects range-over-func iterators, functions and methods returning
+ * `iter.Seq[V]` or `iter.Seq2[K, V]`, so docs can show how to use them with
+ * `for range` and group them together.
+ */
+
+import { formatTypeExpr, type GoTypeExpr } from "./type-expr.js";
+
+/**
+ * A range-over-func iterator returned by a function or method.
+ */
+export interface GoIteratorInfo {
+  /** `seq` yields one value per iteration, `seq2` a key and a value */
+  kind: "seq" | "seq2";
+  /** Types of the values yielded per iteration */
+  yields: string[];
+  /** How to consume the iterator, e.g. "for k, v := range c.All() { ... }" */
+  usage: string;
+}
+
+/**
+ * Describe the iterator a function returns, when its single result is an
+ * `iter.Seq` or `iter.Seq2`.
+ */
+export function iteratorOf(
+  result: GoTypeExpr | undefined,
+  name: string,
+  options: { receiver?: string; hasParams?: boolean } = {},
+): GoIteratorInfo | undefined {
+  if (result?.kind !== "named" || result.package !== "iter") return undefined;
+  if (result.name !== "Seq" && result.name !== "Seq2") return undefined;
+
+  const kind = result.name === "Seq" ? "seq" : "seq2";
+  const vars = kind === "seq" ? "v" : "k, v";
+  const callee = options.receiver ? `${options.receiver}.${name}` : name;
+  const call = `${callee}(${options.hasParams ? "..." : ""})`;
+  return {
+    kind,
+    yields: (result.typeArgs ?? []).map(formatTypeExpr),
+    usage: `for ${vars} := range ${call} { ... }`,
+  };
+}
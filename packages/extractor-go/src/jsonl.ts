@@ -0,0 +1,50 @@
+/**
+ * JSONL Output
+ *
+ * Writes an output as JSON Lines, one record per line, so consumers of very
+ * large modules can stream symbols without loading the whole document. The
+ * header records come first, one per top-level field (`{"package": ...}`,
+ * `{"labels": ...}`, ...), followed by one `{"symbol": ...}` record per
+ * symbol.
+ */
+
+import type { ProfiledOutput } from "./profile.js";
+
+/**
+ * Yield the JSONL records of an output, without line terminators.
+ */
+export function* jsonlRecords(output: ProfiledOutput): Generator<string> {
+  for (const [key, value] of Object.entries(output)) {
+    if (key === "symbols" || value === undefined) continue;
+    yield JSON.stringify({ [key]: value });
+  }
+  for (const symbol of output.symbols) {
+    yield JSON.stringify({ symbol });
+  }
+}
+
+/**
+ * Rebuild an output from its JSONL records.
+ */
+export function parseJsonl(text: string): ProfiledOutput {
+  const output: Record<string, unknown> = {};
+  const symbols: unknown[] = [];
+  text.split("\n").forEach((line, index) => {
+    if (!line.trim()) return;
+    let record: unknown;
+    try {
+      record = JSON.parse(line);
+    } catch {
+      throw new Error(`Invalid JSONL record on line ${index + 1}`);
+    }
+    if (typeof record !== "object" || record === null || Array.isArray(record)) {
+      throw new Error(`Invalid JSONL record on line ${index + 1}`);
+    }
+    if ("symbol" in record) {
+      symbols.push(record.symbol);
+    } else {
+      Object.assign(output, record);
+    }
+  });
+  return { ...output, symbols } as unknown as ProfiledOutput;
+}
@@ -0,0 +1,135 @@
+/**
+ * Localized Labels
+ *
+ * Structural strings of the rendered docs, such as section names and the
+ * notes the extractor adds to doc comments, with English defaults. A locale
+ * bundle overrides them; the output embeds the resolved labels or references
+ * the bundle by URL so renderers of non-English sites need no post-processing.
+ */
+
+import { readFile } from "fs/promises";
+
+/**
+ * A structural string. Notes may contain `{placeholders}`.
+ */
+export type LabelKey =
+  | "overview"
+  | "index"
+  | "constants"
+  | "variables"
+  | "functions"
+  | "types"
+  | "methods"
+  | "fields"
+  | "deprecated"
+  | "example"
+  | "examples"
+  | "iterators"
+  | "testingHelpers"
+  | "dependencies"
+  | "lifecycleConstruct"
+  | "lifecycleConfigure"
+  | "lifecycleUse"
+  | "lifecycleClose"
+  | "sealedNote"
+  | "sealedImplementations"
+  | "truncatedNote"
+  | "truncatedNoteNoUrl"
+  | "promotedNote";
+
+/**
+ * Structural strings by key.
+ */
+export type Labels = Record<LabelKey, string>;
+
+/**
+ * English labels.
+ */
+export const defaultLabels: Labels = {
+  overview: "Overview",
+  index: "Index",
+  constants: "Constants",
+  variables: "Variables",
+  functions: "Functions",
+  types: "Types",
+  methods: "Methods",
+  fields: "Fields",
+  deprecated: "Deprecated",
+  example: "Example",
+  examples: "Examples",
+  iterators: "Iterators",
+  testingHelpers: "Testing helpers",
+  dependencies: "Dependencies",
+  lifecycleConstruct: "Construct",
+  lifecycleConfigure: "Configure",
+  lifecycleUse: "Use",
+  lifecycleClose: "Close",
+  sealedNote:
+    "This interface is sealed: it has unexported methods, so only types in this package " +
+    "can implement it.",
+  sealedImplementations: "Implementations: {list}.",
+  truncatedNote: "_Documentation truncated; see the [full doc comment]({url})._",
+  truncatedNoteNoUrl: "_Documentation truncated; see the full doc comment in `{path}`._",
+  promotedNote: "Promoted from the embedded type `{type}`, an unexported implementation detail.",
+};
+
+/**
+ * A translation of the labels. Missing keys fall back to English.
+ */
+export interface LocaleBundle {
+  /** BCP 47 tag, e.g. "ja" or "pt-BR" */
+  locale: string;
+  labels: Partial<Labels>;
+}
+
+/**
+ * Labels in the output: embedded, or referenced by bundle URL.
+ */
+export interface OutputLabels {
+  locale: string;
+  /** Resolved labels, when embedded */
+  labels?: Labels;
+  /** Where renderers fetch the bundle, when referenced */
+  bundleUrl?: string;
+}
+
+/**
+ * Validate a parsed locale bundle.
+ */
+export function parseLocaleBundle(data: unknown): LocaleBundle {
+  const bundle = data as Partial<LocaleBundle> | null;
+  if (typeof bundle?.locale !== "string" || !bundle.locale) {
+    throw new Error("Locale bundle needs a locale");
+  }
+  const labels = bundle.labels ?? {};
+  for (const [key, value] of Object.entries(labels)) {
+    if (!(key in defaultLabels)) {
+      throw new Error(`Unknown label: ${key}`);
+    }
+    if (typeof value !== "string") {
+      throw new Error(`Label ${key} must be a string`);
+    }
+  }
+  return { locale: bundle.locale, labels };
+}
+
+/**
+ * Read and validate a locale bundle JSON file.
+ */
+export async function loadLocaleBundle(path: string): Promise<LocaleBundle> {
+  return parseLocaleBundle(JSON.parse(await readFile(path, "utf-8")));
+}
+
+/**
+ * Merge a bundle over the English labels.
+ */
+export function resolveLabels(bundle?: LocaleBundle): Labels {
+  return { ...defaultLabels, ...bundle?.labels };
+}
+
+/**
+ * Fill the `{placeholders}` of a label.
+ */
+export function formatLabel(label: string, values: Record<string, string>): string {
+  return label.replace(/\{(\w+)\}/g, (placeholder, name: string) => values[name] ?? placeholder);
+}
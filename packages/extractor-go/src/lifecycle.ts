@@ -0,0 +1,81 @@
+/**
+ * Lifecycle Grouping
+ *
+ * Sorts the API of a type into the stages of its lifecycle, from constructors
+ * through configuration and use to shutdown, so long types such as `Client`
+ * read as a guided flow instead of an alphabetical list.
+ */
+
+import type { TypeReference } from "@langchain/ir-schema";
+import { splitTopLevel } from "./type-expr.js";
+
+/**
+ * A lifecycle stage.
+ *
+ * - `construct`: functions returning the type, e.g. `NewClient`
+ * - `configure`: setters and options, e.g. `SetTimeout`, `WithRetry`
+ * - `use`: everything else
+ * - `close`: releasing the value, e.g. `Close`, `Shutdown`
+ */
+export type LifecycleStage = "construct" | "configure" | "use" | "close";
+
+/**
+ * All lifecycle stages, in order.
+ */
+export const lifecycleStages: LifecycleStage[] = ["construct", "configure", "use", "close"];
+
+/**
+ * The constructors and methods of a type, by lifecycle stage.
+ */
+export type GoLifecycle = Partial<Record<LifecycleStage, TypeReference[]>>;
+
+const CONFIGURE_PREFIX = /^(Set|With|Enable|Disable|Configure)([A-Z]|$)/;
+const CLOSE_PREFIX =
+  /^(Close|Shutdown|Stop|Dispose|Release|Disconnect|Terminate|Destroy)([A-Z]|$)/;
+
+/**
+ * Get the lifecycle stage of a method from its name.
+ */
+export function methodStage(name: string): Exclude<LifecycleStage, "construct"> {
+  if (CLOSE_PREFIX.test(name)) return "close";
+  if (CONFIGURE_PREFIX.test(name)) return "configure";
+  return "use";
+}
+
+/**
+ * Whether a function constructs a type: its first result is the type or a
+ * pointer to it, e.g. `func NewClient(url string) (*Client, error)`.
+ */
+export function isConstructorOf(returns: string, typeName: string): boolean {
+  const first = splitTopLevel(returns.replace(/^\((.*)\)$/s, "$1"))[0] ?? "";
+  return (
+    first
+      .trim()
+      .replace(/^\*/, "")
+      .replace(/\[.*\]$/s, "") === typeName
+  );
+}
+
+/**
+ * Group a type's constructors and methods by lifecycle stage. Returns
+ * undefined unless they span at least two stages, since a single group adds
+ * nothing over the plain member list.
+ */
+export function groupLifecycle(
+  constructors: TypeReference[],
+  methods: TypeReference[],
+): GoLifecycle | undefined {
+  const lifecycle: GoLifecycle = {};
+  if (constructors.length > 0) {
+    lifecycle.construct = constructors;
+  }
+  for (const method of methods) {
+    const stage = methodStage(method.name);
+    (lifecycle[stage] ??= []).push(method);
+  }
+
+  // Rebuild in stage order so serialized output reads top to bottom
+  const stages = lifecycleStages.filter((stage) => lifecycle[stage]);
+  if (stages.length < 2) return undefined;
+  return Object.fromEntries(stages.map((stage) => [stage, lifecycle[stage]!]));
+}
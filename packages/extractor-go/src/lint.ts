@@ -0,0 +1,141 @@
+/**
+ * Documentation Lint
+ *
+ * Checks the exported API of a package against the Go doc comment
+ * conventions: every exported symbol has a doc comment, doc comments start
+ * with the name they document, and the package has an overview. Each rule
+ * has a configurable severity so teams can adopt the rules one at a time.
+ */
+
+import type { GoSymbolRecord } from "./transformer.js";
+
+/**
+ * A lint rule.
+ *
+ * - `missing-doc`: an exported symbol has no doc comment
+ * - `doc-prefix`: the doc comment of a type, function, or method doesn't
+ *   start with its name (optionally after "A", "An", or "The")
+ * - `package-doc`: the package has no package comment
+ */
+export type LintRule = "missing-doc" | "doc-prefix" | "package-doc";
+
+export const lintRules: LintRule[] = ["missing-doc", "doc-prefix", "package-doc"];
+
+/**
+ * How a rule's violations are reported. Errors make the lint fail.
+ */
+export type LintSeverity = "error" | "warning" | "off";
+
+export const lintSeverities: LintSeverity[] = ["error", "warning", "off"];
+
+/**
+ * Severities of the rules when not configured: all are errors.
+ */
+export const defaultLintSeverities: Record<LintRule, LintSeverity> = {
+  "missing-doc": "error",
+  "doc-prefix": "error",
+  "package-doc": "error",
+};
+
+/**
+ * A violation of a lint rule.
+ */
+export interface LintIssue {
+  rule: LintRule;
+  severity: Exclude<LintSeverity, "off">;
+  message: string;
+  /** Symbol the issue applies to (absent for package-level issues) */
+  symbolId?: string;
+  /** Source location of the symbol */
+  source?: { path: string; line: number };
+}
+
+/**
+ * Lint the package comment and the exported symbols of a package. Test
+ * helpers, vendored dependencies, and generated code are skipped.
+ */
+export function lintPackage(
+  packageDoc: string | undefined,
+  symbols: GoSymbolRecord[],
+  severities: Partial<Record<LintRule, LintSeverity>> = {},
+): LintIssue[] {
+  const issues: LintIssue[] = [];
+  const report = (rule: LintRule, message: string, symbol?: GoSymbolRecord) => {
+    const severity = severities[rule] ?? defaultLintSeverities[rule];
+    if (severity === "off") return;
+    issues.push({
+      rule,
+      severity,
+      message,
+      ...(symbol
+        ? { symbolId: symbol.id, source: { path: symbol.source.path, line: symbol.source.line } }
+        : {}),
+    });
+  };
+
+  if (!packageDoc?.trim()) {
+    report("package-doc", "package has no package comment");
+  }
+
+  for (const symbol of symbols) {
+    if (symbol.tags.visibility !== "public") continue;
+    if (symbol.testHelper || symbol.dependency || symbol.generated) continue;
+
+    const { summary, description, deprecated } = symbol.docs;
+    const doc = summary || description;
+    if (!doc) {
+      if (!deprecated) {
+        report("missing-doc", `exported ${symbol.qualifiedName} has no doc comment`, symbol);
+      }
+      continue;
+    }
+    // Trailing comments document constants and variables without naming them
+    if (symbol.kind !== "variable" && !startsWithName(doc, symbol.name)) {
+      report(
+        "doc-prefix",
+        `doc comment of ${symbol.qualifiedName} should start with "${symbol.name}"`,
+        symbol,
+      );
+    }
+  }
+
+  return issues;
+}
+
+/**
+ * Whether a doc comment starts with the name it documents.
+ */
+function startsWithName(doc: string, name: string): boolean {
+  const text = doc.replace(/^(?:A|An|The)\s+/, "");
+  return text.startsWith(name) && !/^[\p{L}\p{N}_]/u.test(text.slice(name.length));
+}
+
+/**
+ * Parse `rule=severity` settings, as given on the command line.
+ */
+export function parseLintSeverities(specs: string[]): Partial<Record<LintRule, LintSeverity>> {
+  const severities: Partial<Record<LintRule, LintSeverity>> = {};
+  for (const spec of specs) {
+    const [rule, severity] = spec.split("=").map((part) => part.trim());
+    if (!lintRules.includes(rule as LintRule)) {
+      throw new Error(`Unknown lint rule: ${rule}`);
+    }
+    if (!lintSeverities.includes(severity as LintSeverity)) {
+      throw new Error(`Unknown lint severity for ${rule}: ${severity}`);
+    }
+    severities[rule as LintRule] = severity as LintSeverity;
+  }
+  return severities;
+}
+
+/**
+ * Format lint issues one per line, as `file:line: severity: message (rule)`.
+ */
+export function formatLintIssues(issues: LintIssue[]): string {
+  return issues
+    .map((issue) => {
+      const location = issue.source ? `${issue.source.path}:${issue.source.line}` : "package";
+      return `${location}: ${issue.severity}: ${issue.message} (${issue.rule})`;
+    })
+    .join("\n");
+}
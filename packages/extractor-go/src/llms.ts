@@ -0,0 +1,104 @@
+/**
+ * llms.txt Corpus
+ *
+ * Renders the public API as LLM-friendly plain text in the llms.txt style,
+ * for feeding the reference into retrieval pipelines: a section per package
+ * directory with each symbol's signature and condensed docs. The output is
+ * deterministic (sorted by package and qualified name, independent of the
+ * locale) and capped in size, dropping whole symbols from the end.
+ */
+
+import { posix } from "path";
+import { compareCanonical } from "./canonical.js";
+import type { ExtractorOutput } from "./output.js";
+import type { GoSymbolRecord } from "./transformer.js";
+
+/**
+ * Default size cap of the corpus in characters.
+ */
+export const defaultLlmsMaxChars = 100_000;
+
+/**
+ * Maximum length of a symbol's condensed docs.
+ */
+const MAX_DOC_CHARS = 600;
+
+/**
+ * Render the llms.txt corpus of an output, at most `maxChars` long.
+ */
+export function renderLlmsText(
+  output: ExtractorOutput,
+  maxChars: number = defaultLlmsMaxChars,
+): string {
+  const pkg = output.package;
+  const header = [`# ${pkg.displayName}`];
+  if (pkg.synopsis) header.push(`> ${pkg.synopsis}`);
+
+  const byPackage = new Map<string, GoSymbolRecord[]>();
+  for (const symbol of output.symbols as GoSymbolRecord[]) {
+    if (symbol.tags.visibility !== "public" || symbol.testHelper) continue;
+    const dir = posix.dirname(symbol.source.path);
+    byPackage.set(dir, [...(byPackage.get(dir) ?? []), symbol]);
+  }
+
+  let text = header.join("\n\n");
+  const total = [...byPackage.values()].reduce((n, symbols) => n + symbols.length, 0);
+  let written = 0;
+  for (const dir of [...byPackage.keys()].sort(compareCanonical)) {
+    const heading = `\n\n## ${posix.join(pkg.displayName, dir)}`;
+    let section = "";
+    const symbols = byPackage.get(dir)!.sort((a, b) =>
+      compareCanonical(a.qualifiedName, b.qualifiedName),
+    );
+    for (const symbol of symbols) {
+      const entry = `\n\n${renderSymbol(symbol)}`;
+      if (text.length + heading.length + section.length + entry.length > maxChars) {
+        if (section) text += heading + section;
+        return `${text}\n\n(${total - written} more symbols omitted)\n`;
+      }
+      section += entry;
+      written++;
+    }
+    text += heading + section;
+  }
+  return `${text}\n`;
+}
+
+/**
+ * Render a symbol as a heading, its signature (with a struct's exported
+ * fields), and its condensed docs.
+ */
+function renderSymbol(symbol: GoSymbolRecord): string {
+  const fields = (symbol.members ?? []).filter(
+    (m) => m.kind === "property" && m.visibility === "public",
+  );
+  const signature =
+    symbol.kind === "class" && fields.length > 0
+      ? `${symbol.signature} {\n${fields.map((f) => `\t${f.name} ${f.type ?? ""}`).join("\n")}\n}`
+      : symbol.signature;
+  const parts = [`### ${symbol.qualifiedName}`, `\`\`\`go\n${signature}\n\`\`\``];
+  if (symbol.docs.deprecated) {
+    parts.push(`Deprecated: ${symbol.docs.deprecated.message ?? ""}`.trimEnd());
+  }
+  const doc = condense(symbol.docs.description ?? symbol.docs.summary);
+  if (doc) parts.push(doc);
+  return parts.join("\n\n");
+}
+
+/**
+ * Condense a description to its prose: code blocks and headings are left
+ * out, each paragraph is joined into one line, and the text is cut at a
+ * sentence boundary to fit the doc limit.
+ */
+function condense(description: string): string {
+  const prose = description
+    .split(/\n\s*\n/)
+    .filter((paragraph) => !/^(\s|```|#)/.test(paragraph))
+    .map((paragraph) => paragraph.replace(/\s*\n\s*/g, " ").trim())
+    .filter(Boolean)
+    .join("\n\n");
+  if (prose.length <= MAX_DOC_CHARS) return prose;
+  const cut = prose.slice(0, MAX_DOC_CHARS);
+  const end = cut.lastIndexOf(". ");
+  return end > 0 ? cut.slice(0, end + 1) : `${cut.slice(0, cut.lastIndexOf(" "))} …`;
+}
@@ -0,0 +1,184 @@
+/**
+ * Logging
+ *
+ * A leveled logger for the extractor's diagnostics, in one of two formats:
+ *
+ * - `text`: the messages as written, for people; warnings are prefixed with
+ *   "Warning:", and debug and info messages go to stdout, warnings and
+ *   errors to stderr
+ * - `json`: one JSON object per line with the time, level, message, and
+ *   structured fields, all on stderr, so CI pipelines can parse them without
+ *   mixing them up with output written to stdout
+ *
+ * Warnings and errors are counted, for run summaries. Modules log through
+ * the shared `logger`, which the CLI configures from `--log-level` and
+ * `--log-format`.
+ */
+
+/**
+ * Severity of a log message. `silent` logs nothing.
+ */
+export type LogLevel = "debug" | "info" | "warn" | "error" | "silent";
+
+/**
+ * All log levels, from the most to the least verbose.
+ */
+export const logLevels: LogLevel[] = ["debug", "info", "warn", "error", "silent"];
+
+/**
+ * Format of log messages.
+ */
+export type LogFormat = "text" | "json";
+
+/**
+ * All log formats.
+ */
+export const logFormats: LogFormat[] = ["text", "json"];
+
+/**
+ * Structured fields of a message. An `error` field is serialized with its
+ * message and stack.
+ */
+export type LogFields = Record<string, unknown>;
+
+/**
+ * Writes leveled messages as text or JSON lines, and counts warnings and
+ * errors.
+ */
+export class Logger {
+  level: LogLevel = "info";
+  format: LogFormat = "text";
+  private counts = { warn: 0, error: 0 };
+
+  /**
+   * Change the level or format.
+   */
+  configure(options: { level?: LogLevel; format?: LogFormat }): void {
+    if (options.level !== undefined) {
+      if (!logLevels.includes(options.level)) {
+        throw new Error(`Unknown log level: ${options.level} (expected ${logLevels.join(", ")})`);
+      }
+      this.level = options.level;
+    }
+    if (options.format !== undefined) {
+      if (!logFormats.includes(options.format)) {
+        throw new Error(`Unknown log format: ${options.format} (expected text or json)`);
+      }
+      this.format = options.format;
+    }
+  }
+
+  /**
+   * Whether messages of a level are written.
+   */
+  enabled(level: Exclude<LogLevel, "silent">): boolean {
+    return logLevels.indexOf(level) >= logLevels.indexOf(this.level);
+  }
+
+  /**
+   * Number of warnings logged, whether or not they were written.
+   */
+  get warnings(): number {
+    return this.counts.warn;
+  }
+
+  /**
+   * Number of errors logged, whether or not they were written.
+   */
+  get errors(): number {
+    return this.counts.error;
+  }
+
+  /**
+   * Log details, like what a run found.
+   */
+  debug(message: string, fields?: LogFields): void {
+    this.log("debug", message, fields);
+  }
+
+  /**
+   * Log progress, like the files a run wrote.
+   */
+  info(message: string, fields?: LogFields): void {
+    this.log("info", message, fields);
+  }
+
+  /**
+   * Log a problem the run continues after.
+   */
+  warn(message: string, fields?: LogFields): void {
+    this.counts.warn++;
+    this.log("warn", message, fields);
+  }
+
+  /**
+   * Log a failure.
+   */
+  error(message: string, fields?: LogFields): void {
+    this.counts.error++;
+    this.log("error", message, fields);
+  }
+
+  /**
+   * Log a multi-line report, like a table: as text, the report after a
+   * blank line; as JSON, the title and the report's fields.
+   */
+  report(title: string, text: string, fields?: LogFields): void {
+    if (this.format === "json") {
+      this.log("info", title, fields);
+    } else if (this.enabled("info")) {
+      console.log();
+      console.log(text);
+    }
+  }
+
+  /**
+   * Write a message, if its level is enabled.
+   */
+  private log(level: Exclude<LogLevel, "silent">, message: string, fields?: LogFields): void {
+    if (!this.enabled(level)) return;
+    if (this.format === "json") {
+      const entry = {
+        time: new Date().toISOString(),
+        level,
+        msg: message.replace(/^\p{Extended_Pictographic}\uFE0F?\s*/u, ""),
+        ...fields,
+        ...(fields?.error !== undefined && { error: serializeError(fields.error) }),
+      };
+      process.stderr.write(`${JSON.stringify(entry)}\n`);
+      return;
+    }
+    const error = fields?.error;
+    switch (level) {
+      case "debug":
+      case "info":
+        console.log(message);
+        break;
+      case "warn":
+        console.warn(`Warning: ${message}`);
+        break;
+      case "error":
+        if (error !== undefined) {
+          console.error(`${message}:`, error);
+        } else {
+          console.error(message);
+        }
+        break;
+    }
+  }
+}
+
+/**
+ * The logger modules share.
+ */
+export const logger = new Logger();
+
+/**
+ * An error as JSON: its message and stack, or the value as a string.
+ */
+function serializeError(error: unknown): unknown {
+  if (error instanceof Error) {
+    return { message: error.message, stack: error.stack };
+  }
+  return String(error);
+}
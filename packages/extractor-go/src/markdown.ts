@@ -0,0 +1,101 @@
+/**
+ * Markdown Rendering
+ *
+ * Renders parsed doc comments as CommonMark, after the Markdown printer of
+ * go/doc/comment: headings at a fixed level, fenced Go code blocks, lists,
+ * and links, with the text escaped so it reads back as written. Callouts
+ * become GitHub alerts.
+ */
+
+import type { CalloutKind, DocBlock, DocText } from "./doc-comment.js";
+
+/**
+ * Options of the Markdown renderer.
+ */
+export interface MarkdownOptions {
+  /** Level of doc comment headings (default: 3, as in go/doc/comment) */
+  headingLevel?: number;
+  /** URL of a symbol of the package, for doc links resolved to a symbol ID */
+  symbolUrl?: (refId: string) => string | undefined;
+}
+
+/**
+ * GitHub alert types of callouts.
+ */
+const CALLOUT_ALERTS: Record<CalloutKind, string> = {
+  security: "CAUTION",
+  concurrency: "WARNING",
+};
+
+/**
+ * Render parsed doc comment blocks as CommonMark.
+ */
+export function renderMarkdown(blocks: DocBlock[], options: MarkdownOptions = {}): string {
+  const level = options.headingLevel ?? 3;
+
+  return blocks
+    .map((block) => {
+      switch (block.kind) {
+        case "paragraph":
+          return renderText(block.text, options);
+        case "heading":
+          return `${"#".repeat(level)} ${renderText(block.text, options)}`;
+        case "list":
+          return block.items
+            .map((item, index) => {
+              const marker = block.ordered ? `${item.number ?? index + 1}.` : "-";
+              return `${marker} ${renderText(item.text, options)}`;
+            })
+            .join("\n");
+        case "code":
+        case "verbatim": {
+          // Use a longer fence than any backtick run in the code
+          const runs = block.text.match(/`{3,}/g) ?? [];
+          const fence = "`".repeat(Math.max(3, ...runs.map((run) => run.length + 1)));
+          const language = block.kind === "code" ? block.language : "text";
+          return `${fence}${language}\n${block.text}\n${fence}`;
+        }
+        case "callout":
+          // A GitHub alert, which other renderers show as a blockquote
+          return `> [!${CALLOUT_ALERTS[block.callout]}]\n> ${renderText(block.text, options)}`;
+      }
+    })
+    .join("\n\n");
+}
+
+/**
+ * Render inline text, linking URLs and resolved doc links.
+ */
+function renderText(text: DocText[], options: MarkdownOptions): string {
+  return text
+    .map((span, index) => {
+      const escaped = escapeMarkdown(span.text, index === 0);
+      if (span.kind === "plain") return escaped;
+      if (span.kind === "link" && span.text === span.url) return `<${span.url}>`;
+
+      const url =
+        span.kind === "link"
+          ? span.url
+          : (span.url ?? (span.refId ? options.symbolUrl?.(span.refId) : undefined));
+      return url ? `[${escaped}](${escapeUrl(url)})` : escaped;
+    })
+    .join("");
+}
+
+/**
+ * Escape the characters Markdown would read as formatting, as
+ * go/doc/comment does: emphasis, code, link, and HTML characters anywhere,
+ * and heading, list, and quote markers at the start of a line.
+ */
+export function escapeMarkdown(text: string, lineStart = true): string {
+  const escaped = text.replace(/[\\`*_[\]<]/g, "\\$&");
+  if (!lineStart) return escaped;
+  return escaped.replace(/^([#+\->])/, "\\$1").replace(/^(\d+)([.)])/, "$1\\$2");
+}
+
+/**
+ * Keep a URL from ending the link destination early.
+ */
+function escapeUrl(url: string): string {
+  return url.replace(/[ ()]/g, (c) => `%${c.charCodeAt(0).toString(16).toUpperCase()}`);
+}
@@ -0,0 +1,214 @@
+/**
+ * MDX Pages
+ *
+ * Renders an extraction as MDX pages the docs site consumes directly: one
+ * page for the package, listing its API and documenting its constants and
+ * variables, and one page per type and function, documenting a type's
+ * fields and methods along with it. Pages start with front matter (title,
+ * slug, kind, and since), and prose is escaped so MDX reads it as text.
+ * For Docusaurus sites, pages also get Docusaurus front matter and slugs
+ * below their path in the docs folder.
+ */
+
+import type { ExtractorOutput } from "./output.js";
+import type { GoSymbolRecord } from "./transformer.js";
+import { renderMarkdown } from "./markdown.js";
+import { defaultLabels, type Labels } from "./labels.js";
+import { docusaurusFrontMatter } from "./docusaurus.js";
+
+/**
+ * Symbol kinds that get a page of their own.
+ */
+const PAGE_KINDS = new Set(["class", "interface", "typeAlias", "function"]);
+
+/**
+ * Options for rendering MDX pages.
+ */
+export interface MdxOptions {
+  /**
+   * Path of the pages in a Docusaurus site's docs folder, like
+   * `reference/go`: adds Docusaurus front matter to the pages
+   */
+  docusaurus?: string;
+}
+
+/**
+ * The symbols that get a page of their own, besides the package.
+ */
+export function mdxPageSymbols(output: ExtractorOutput): GoSymbolRecord[] {
+  return (output.symbols as GoSymbolRecord[]).filter(
+    (s) => s.tags.visibility === "public" && PAGE_KINDS.has(s.kind),
+  );
+}
+
+/**
+ * Render the MDX pages of an output, keyed by file path: `index.mdx` for the
+ * package and `<slug>.mdx` for each type and function, after its canonical
+ * URL, so the pages of vendored dependencies go in subdirectories.
+ */
+export function renderMdx(output: ExtractorOutput, options: MdxOptions = {}): Map<string, string> {
+  const symbols = output.symbols as GoSymbolRecord[];
+  const labels = output.labels?.labels ?? defaultLabels;
+  const byId = new Map(symbols.map((symbol) => [symbol.id, symbol]));
+  const pageOf = (symbol: GoSymbolRecord): GoSymbolRecord | undefined =>
+    PAGE_KINDS.has(symbol.kind)
+      ? symbol
+      : symbol.kind === "method"
+        ? byId.get(symbol.id.slice(0, symbol.id.lastIndexOf("_")))
+        : undefined;
+  const symbolUrl = (refId: string) => {
+    const symbol = byId.get(refId);
+    if (!symbol) return undefined;
+    const page = pageOf(symbol);
+    if (page === symbol) return `.${page.urls.canonical}`;
+    return `.${page?.urls.canonical ?? "/"}#${anchor(symbol.name)}`;
+  };
+  const render = (symbol: GoSymbolRecord, headingLevel: number) =>
+    renderDocs(symbol, labels, { headingLevel, symbolUrl });
+  const docusaurus = (symbol?: GoSymbolRecord) =>
+    options.docusaurus === undefined
+      ? {}
+      : docusaurusFrontMatter(output, options.docusaurus, symbol);
+
+  const pages = new Map<string, string>();
+  const pkg = output.package;
+  const topLevel = symbols.filter((s) => s.tags.visibility === "public" && s.kind !== "method");
+  const sections: string[] = [];
+  if (pkg.overview) sections.push(escapeMdx(pkg.overview));
+
+  const listed: Array<[keyof Labels, GoSymbolRecord[]]> = [
+    ["types", topLevel.filter((s) => PAGE_KINDS.has(s.kind) && s.kind !== "function")],
+    ["functions", topLevel.filter((s) => s.kind === "function")],
+  ];
+  for (const [label, group] of listed) {
+    if (group.length === 0) continue;
+    const items = group.map((symbol) => {
+      const summary = symbol.docs.summary ? ` — ${escapeMdx(symbol.docs.summary)}` : "";
+      return `- [${symbol.name}](.${symbol.urls.canonical})${summary}`;
+    });
+    sections.push(`## ${labels[label]}\n\n${items.join("\n")}`);
+  }
+  const values = topLevel.filter((s) => s.kind === "variable");
+  const declared: Array<[keyof Labels, GoSymbolRecord[]]> = [
+    ["constants", values.filter((s) => s.signature.startsWith("const "))],
+    ["variables", values.filter((s) => !s.signature.startsWith("const "))],
+  ];
+  for (const [label, group] of declared) {
+    if (group.length === 0) continue;
+    const docs = group.map((symbol) => `### ${symbol.name}\n\n${render(symbol, 4)}`);
+    sections.push(`## ${labels[label]}\n\n${docs.join("\n\n")}`);
+  }
+  pages.set(
+    "index.mdx",
+    page(
+      { title: pkg.displayName, slug: "/", kind: "package", ...docusaurus() },
+      sections,
+      pkg.synopsis,
+    ),
+  );
+
+  for (const symbol of mdxPageSymbols(output)) {
+    const body = [render(symbol, 2)];
+    const fields = (symbol.members ?? []).filter(
+      (m) => m.kind === "property" && m.visibility === "public",
+    );
+    if (fields.length > 0) {
+      const docs = fields.map((field) => {
+        const doc = field.doc ? `\n\n${escapeMdx(field.doc)}` : "";
+        return `### ${field.name}\n\n\`\`\`go\n${field.name} ${field.type ?? ""}\n\`\`\`${doc}`;
+      });
+      body.push(`## ${labels.fields}\n\n${docs.join("\n\n")}`);
+    }
+    const methods = (symbol.members ?? [])
+      .filter((m) => m.kind === "method" && m.visibility === "public")
+      .map((m) => byId.get(m.refId))
+      .filter((method): method is GoSymbolRecord => Boolean(method));
+    if (methods.length > 0) {
+      const docs = methods.map((method) => `### ${method.name}\n\n${render(method, 4)}`);
+      body.push(`## ${labels.methods}\n\n${docs.join("\n\n")}`);
+    }
+
+    const matter = {
+      title: symbol.name,
+      slug: symbol.urls.canonical,
+      kind: symbol.kind,
+      since: symbol.versionInfo?.since,
+      ...docusaurus(symbol),
+    };
+    pages.set(`${symbol.urls.canonical.slice(1)}.mdx`, page(matter, body, symbol.docs.summary));
+  }
+  return pages;
+}
+
+/**
+ * Render a symbol's signature, deprecation notice, docs, and examples.
+ */
+function renderDocs(
+  symbol: GoSymbolRecord,
+  labels: Labels,
+  options: { headingLevel: number; symbolUrl: (refId: string) => string | undefined },
+): string {
+  const parts = [`\`\`\`go\n${symbol.signature}\n\`\`\``];
+  const { deprecated, blocks, description, summary, examples } = symbol.docs;
+  if (deprecated) {
+    const message = deprecated.message ? ` ${escapeMdx(deprecated.message)}` : "";
+    parts.push(`> **${labels.deprecated}:**${message}`);
+  }
+  const doc = blocks ? renderMarkdown(blocks, options) : (description ?? summary);
+  if (doc) parts.push(escapeMdx(doc));
+
+  for (const example of examples ?? []) {
+    const title = example.title ? `${labels.example}: ${example.title}` : labels.example;
+    const heading = "#".repeat(Math.min(options.headingLevel + 1, 6));
+    let text = `${heading} ${escapeMdx(title)}\n\n\`\`\`go\n${example.code}\n\`\`\``;
+    if (example.output) text += `\n\nOutput:\n\n\`\`\`text\n${example.output}\n\`\`\``;
+    parts.push(text);
+  }
+  return parts.join("\n\n");
+}
+
+/**
+ * Assemble a page from its front matter, description, and body sections.
+ */
+function page(
+  matter: Record<string, string | undefined>,
+  sections: string[],
+  description?: string,
+): string {
+  const fields = { ...matter, description: description || undefined };
+  const lines = Object.entries(fields)
+    .filter(([, value]) => value !== undefined)
+    .map(([key, value]) => `${key}: ${JSON.stringify(value)}`);
+  return `---\n${lines.join("\n")}\n---\n\n${sections.join("\n\n")}\n`;
+}
+
+/**
+ * Escape Markdown for MDX, which reads `{` as an expression and `<` as JSX:
+ * braces and angle brackets outside code are escaped, and autolinks become
+ * links, which MDX doesn't support. Escaped characters stay as they are.
+ */
+export function escapeMdx(markdown: string): string {
+  let inFence = false;
+  return markdown
+    .split("\n")
+    .map((line) => {
+      if (/^\s*(`{3,}|~{3,})/.test(line)) {
+        inFence = !inFence;
+        return line;
+      }
+      if (inFence) return line;
+      return line.replace(
+        /\\[\s\S]|(`+).*?\1|<(https?:\/\/[^\s>]+)>|[{}<]/g,
+        (token, _code, url) =>
+          url ? `[${url}](${url})` : token.length > 1 ? token : `\\${token}`,
+      );
+    })
+    .join("\n");
+}
+
+/**
+ * Anchor of a heading, as MDX sites generate it.
+ */
+function anchor(name: string): string {
+  return name.toLowerCase();
+}
@@ -0,0 +1,101 @@
+/**
+ * Mermaid Diagrams
+ *
+ * Renders the type relationships of each package as a Mermaid class
+ * diagram, for visual overviews on docs pages:
+ *
+ * - struct and interface embedding: `Buffer <|-- Store : embeds`
+ * - interface satisfaction: `Closer <|.. Buffer : implements`
+ * - constructors: `NewClient ..> Client : creates`
+ *
+ * Types are classes with their kind as stereotype; constructors are
+ * `<<constructor>>` classes. Types of other packages, such as `io.Writer`,
+ * are named by their qualified name.
+ */
+
+import { posix } from "path";
+import type { ExtractorOutput } from "./output.js";
+import type { GoSymbolRecord } from "./transformer.js";
+import { isConstructorOf } from "./lifecycle.js";
+import { compareCanonical } from "./canonical.js";
+
+/**
+ * Stereotypes of type kinds.
+ */
+const STEREOTYPES: Record<string, string> = {
+  class: "struct",
+  interface: "interface",
+  typeAlias: "alias",
+};
+
+/**
+ * Render a class diagram per package of an output, keyed by import path.
+ */
+export function renderMermaid(output: ExtractorOutput): Map<string, string> {
+  const module = output.package.modulePath || output.package.publishedName;
+  const byPackage = new Map<string, GoSymbolRecord[]>();
+  for (const symbol of output.symbols as GoSymbolRecord[]) {
+    if (symbol.tags.visibility !== "public" || symbol.testHelper) continue;
+    const dir = posix.dirname(symbol.source.path);
+    const importPath = dir === "." ? module : `${module}/${dir}`;
+    byPackage.set(importPath, [...(byPackage.get(importPath) ?? []), symbol]);
+  }
+
+  const diagrams = new Map<string, string>();
+  for (const importPath of [...byPackage.keys()].sort(compareCanonical)) {
+    diagrams.set(importPath, classDiagram(byPackage.get(importPath)!));
+  }
+  return diagrams;
+}
+
+/**
+ * Render the class diagram of a package's symbols.
+ */
+function classDiagram(symbols: GoSymbolRecord[]): string {
+  const types = symbols.filter((symbol) => STEREOTYPES[symbol.kind]);
+  const classes = new Map<string, string>();
+  const edges = new Set<string>();
+  const node = (name: string, stereotype?: string) => {
+    const id = nodeId(name);
+    if (!classes.has(id)) {
+      const label = id === name ? "" : `["${name}"]`;
+      const body = stereotype ? ` {\n    <<${stereotype}>>\n  }` : "";
+      classes.set(id, `class ${id}${label}${body}`);
+    }
+    return id;
+  };
+
+  for (const type of types) node(type.name, STEREOTYPES[type.kind]);
+  for (const type of types) {
+    const self = nodeId(type.name);
+    for (const name of type.relations?.extends ?? []) {
+      edges.add(`${node(name)} <|-- ${self} : embeds`);
+    }
+    for (const name of type.relations?.implements ?? []) {
+      edges.add(`${node(name)} <|.. ${self} : implements`);
+    }
+    for (const implementation of type.implementations ?? []) {
+      if (!types.some((t) => t.name === implementation.name)) continue;
+      edges.add(`${self} <|.. ${nodeId(implementation.name)} : implements`);
+    }
+  }
+  for (const func of symbols.filter((symbol) => symbol.kind === "function")) {
+    const type = types.find((t) => isConstructorOf(func.returns?.type ?? "", t.name));
+    if (type) {
+      edges.add(`${node(func.name, "constructor")} ..> ${nodeId(type.name)} : creates`);
+    }
+  }
+
+  const lines = ["classDiagram", ...classes.values(), ...edges].map((line, i) =>
+    i === 0 ? line : `  ${line}`,
+  );
+  return `${lines.join("\n")}\n`;
+}
+
+/**
+ * A class name Mermaid accepts: qualified names such as `io.Writer` become
+ * `io_Writer`, labelled with the original.
+ */
+function nodeId(name: string): string {
+  return name.replace(/\W/g, "_");
+}
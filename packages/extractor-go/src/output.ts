@@ -0,0 +1,298 @@
+/**
+ * Extractor Output
+ *
+ * Builds the document written to `symbols.json`.
+ */
+
+import type { SymbolRecord, SymbolSource } from "@langchain/ir-schema";
+import type { GoExtractorConfig } from "./config.js";
+import type { ExtractionResult, GoNote } from "./extractor.js";
+import type { PackageDuplicate } from "./dedup.js";
+import type { ExtractionError } from "./errors.js";
+import type { ProfiledOutput } from "./profile.js";
+import type { UnifiedOutput } from "./unified.js";
+import type { SymbolGraph } from "./graph.js";
+import type { TocNode } from "./toc.js";
+import type { Provenance } from "./provenance.js";
+import { resolveLabels, type OutputLabels } from "./labels.js";
+import { buildCapabilities, type Capabilities } from "./capabilities.js";
+import { buildCoverage, type DocCoverage } from "./coverage.js";
+import type { GoSymbolRecord } from "./transformer.js";
+import { packageExamples, type GoSymbolExample } from "./examples.js";
+import { docToMarkdown, parseDocComment, type DocBlock } from "./doc-comment.js";
+import { compareCanonical, sortSymbols } from "./canonical.js";
+
+/**
+ * What the extractor writes.
+ *
+ * - `json`: the IR document (`symbols.json`)
+ * - `stubs`: compilable Go files with only the exported declarations, written
+ *   to the output directory
+ * - `mdx`: MDX pages for the package and each type and function, written to
+ *   the output directory
+ * - `unified`: the cross-language reference schema shared with the
+ *   TypeScript and Python extractors
+ * - `jsonl`: the IR document as JSON Lines, a record per header field and
+ *   per symbol, for streaming
+ * - `sqlite`: a SQLite database with tables for packages, symbols,
+ *   signatures, docs, and cross-references
+ * - `llms`: an llms.txt plain-text corpus of signatures and condensed docs
+ * - `chunks`: embedding-sized doc chunks with stable IDs, as JSONL
+ * - `graph`: a DocC-style symbol graph of symbols and relationship edges
+ * - `scip`: a SCIP index of definitions and documentation for
+ *   code-intelligence tools
+ * - `godoc`: HTML fragments laid out like pkg.go.dev, one per package,
+ *   written to the output directory
+ * - `csv`, `tsv`: an inventory of the public API, a row per symbol
+ */
+export type OutputFormat =
+  | "json"
+  | "stubs"
+  | "mdx"
+  | "unified"
+  | "jsonl"
+  | "sqlite"
+  | "llms"
+  | "chunks"
+  | "graph"
+  | "scip"
+  | "godoc"
+  | "csv"
+  | "tsv";
+
+/**
+ * All output formats.
+ */
+export const outputFormats: OutputFormat[] = [
+  "json",
+  "stubs",
+  "mdx",
+  "unified",
+  "jsonl",
+  "sqlite",
+  "llms",
+  "chunks",
+  "graph",
+  "scip",
+  "godoc",
+  "csv",
+  "tsv",
+];
+
+/**
+ * Package header of the extractor output.
+ */
+export interface OutputPackage {
+  packageId: string;
+  displayName: string;
+  publishedName: string;
+  language: "go";
+  ecosystem: "go";
+  version: string;
+  /** Module path from go.mod, e.g. "github.com/langchain-ai/langsmith-go" */
+  modulePath?: string;
+  repo: {
+    owner: string;
+    name: string;
+    sha: string;
+    path: string;
+  };
+  /** First sentence of the package doc comment, by the go/doc.Synopsis rules */
+  synopsis?: string;
+  /** The whole package doc comment as Markdown */
+  overview?: string;
+  /** The whole package doc comment as structured blocks */
+  overviewBlocks?: DocBlock[];
+  /** Teams or users maintaining the package */
+  owners?: string[];
+  /** Identical copies of packages that were extracted only once */
+  duplicates?: PackageDuplicate[];
+  /** Package examples, from `Example()` and `Example_suffix()` functions */
+  examples?: GoSymbolExample[];
+  /** go/doc notes by marker, e.g. the `BUG(uid): ...` comments under "BUG" */
+  notes?: Record<string, OutputNote[]>;
+  /** Symbols grouped by their category, for navigation by functional area */
+  categories?: OutputCategory[];
+  /** Problems that left packages incomplete */
+  errors?: ExtractionError[];
+}
+
+/**
+ * A category of the package and the symbols assigned to it.
+ */
+export interface OutputCategory {
+  name: string;
+  symbolIds: string[];
+}
+
+/**
+ * A go/doc note of the package.
+ */
+export interface OutputNote {
+  uid: string;
+  body: string;
+  source: SymbolSource;
+}
+
+/**
+ * The complete document produced by a single extraction.
+ */
+export interface ExtractorOutput {
+  package: OutputPackage;
+  /** Where the output came from, when the CLI wrote it */
+  provenance?: Provenance;
+  /** Localized structural labels, when a locale bundle is configured */
+  labels?: OutputLabels;
+  /** Which extraction features were enabled and produced data */
+  capabilities?: Capabilities;
+  /** Share of exported symbols and fields with doc comments */
+  coverage?: DocCoverage;
+  symbols: SymbolRecord[];
+}
+
+/**
+ * Build the package ID for a package name.
+ */
+export function buildPackageId(packageName: string): string {
+  return `pkg_go_${packageName.replace(/[^a-zA-Z0-9]/g, "_")}`;
+}
+
+/**
+ * Comments starting like this are not package documentation.
+ */
+const ILLEGAL_SYNOPSIS_PREFIXES = ["copyright", "all rights", "author"];
+
+/**
+ * Get the synopsis of a package doc comment, as go/doc.Synopsis does: its
+ * first sentence, cut at the end of the first paragraph, as plain text. The
+ * synopsis is empty for license headers and comments that don't start with a
+ * paragraph.
+ */
+export function packageSynopsis(doc?: string): string | undefined {
+  const sentence = firstSentence(doc?.trim() ?? "");
+  const lower = sentence.toLowerCase();
+  if (ILLEGAL_SYNOPSIS_PREFIXES.some((prefix) => lower.startsWith(prefix))) {
+    return undefined;
+  }
+
+  const [block] = parseDocComment(sentence);
+  if (block?.kind !== "paragraph") return undefined;
+  return block.text.map((span) => span.text).join("").trim() || undefined;
+}
+
+/**
+ * The text up to the first period followed by a space, unless the period
+ * ends a single capital letter (as in "J. Smith"), or up to a full-width
+ * period.
+ */
+function firstSentence(text: string): string {
+  let [ppp, pp, p] = ["", "", ""];
+  for (let i = 0; i < text.length; i++) {
+    const q = /[\n\r\t]/.test(text[i]) ? " " : text[i];
+    const upper = (c: string) => c !== c.toLowerCase() && c === c.toUpperCase();
+    if (q === " " && p === "." && (!upper(pp) || upper(ppp))) {
+      return text.slice(0, i);
+    }
+    if (p === "。" || p === "．") {
+      return text.slice(0, i);
+    }
+    [ppp, pp, p] = [pp, p, q];
+  }
+  return text;
+}
+
+/**
+ * Assemble the output document from an extraction result and its IR symbols,
+ * which are put in canonical order.
+ */
+export function buildOutput(
+  result: ExtractionResult,
+  config: GoExtractorConfig,
+  symbols: SymbolRecord[],
+): ExtractorOutput {
+  sortSymbols(symbols as GoSymbolRecord[]);
+  return {
+    package: {
+      packageId: buildPackageId(config.packageName),
+      displayName: config.packageName,
+      publishedName: config.packageName,
+      language: "go",
+      ecosystem: "go",
+      version: result.version,
+      modulePath: result.moduleName || undefined,
+      synopsis: packageSynopsis(result.packageDoc),
+      overview: docToMarkdown(result.packageDoc),
+      overviewBlocks: result.packageDoc ? parseDocComment(result.packageDoc) : undefined,
+      repo: {
+        owner: config.repo.split("/")[0] || "",
+        name: config.repo.split("/")[1] || "",
+        sha: config.sha,
+        path: config.packagePath,
+      },
+      owners: result.ownership?.packageOwners.length ? result.ownership.packageOwners : undefined,
+      duplicates: result.duplicates,
+      examples: packageExamples(result.examples, config),
+      notes: groupNotes(result.notes, config),
+      categories: groupCategories(symbols as GoSymbolRecord[]),
+      errors: result.errors,
+    },
+    labels: buildOutputLabels(config),
+    capabilities: buildCapabilities(config, symbols as GoSymbolRecord[]),
+    coverage: buildCoverage(symbols as GoSymbolRecord[]),
+    symbols,
+  };
+}
+
+/**
+ * Group notes by marker, in the order the markers first appear.
+ */
+export function groupNotes(
+  notes: GoNote[] | undefined,
+  config: GoExtractorConfig,
+): Record<string, OutputNote[]> | undefined {
+  if (!notes?.length) return undefined;
+
+  const grouped: Record<string, OutputNote[]> = {};
+  for (const { marker, uid, body, sourceFile, line } of notes) {
+    const source = { repo: config.repo, sha: config.sha, path: sourceFile, line };
+    (grouped[marker] ??= []).push({ uid, body, source });
+  }
+  return grouped;
+}
+
+/**
+ * Group symbols by category, sorted by category name. Symbols without a
+ * category aren't listed; they keep their place in the package's flat list.
+ */
+export function groupCategories(symbols: GoSymbolRecord[]): OutputCategory[] | undefined {
+  const grouped = new Map<string, string[]>();
+  for (const symbol of symbols) {
+    if (!symbol.category) continue;
+    grouped.set(symbol.category, [...(grouped.get(symbol.category) ?? []), symbol.id]);
+  }
+  if (grouped.size === 0) return undefined;
+
+  return [...grouped.entries()]
+    .sort(([a], [b]) => compareCanonical(a, b))
+    .map(([name, symbolIds]) => ({ name, symbolIds }));
+}
+
+/**
+ * Embed the configured locale's labels, or reference its bundle URL.
+ */
+export function buildOutputLabels(config: GoExtractorConfig): OutputLabels | undefined {
+  const bundle = config.localeBundle;
+  if (!bundle) return undefined;
+  return config.localeBundleUrl
+    ? { locale: bundle.locale, bundleUrl: config.localeBundleUrl }
+    : { locale: bundle.locale, labels: resolveLabels(bundle) };
+}
+
+/**
+ * Serialize an output document the way it is written to disk.
+ */
+export function serializeOutput(
+  output: ProfiledOutput | UnifiedOutput | SymbolGraph | TocNode,
+): string {
+  return JSON.stringify(output, null, 2);
+}
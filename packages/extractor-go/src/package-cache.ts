@@ -0,0 +1,112 @@
+/**
+ * Package Cache
+ *
+ * Keeps the parse results of each package on disk between runs, keyed by a
+ * hash of the package's source files, the options that affect parsing, and
+ * the extractor version, so CI and local rebuilds only parse the packages
+ * that changed. Unlike the in-memory parse cache, the key is the files'
+ * content rather than their modification times, which checkouts reset.
+ * Cross-package resolution and the transform still run on every extraction,
+ * since they depend on the other packages.
+ */
+
+import { createHash } from "crypto";
+import { mkdir, readFile, rename, writeFile } from "fs/promises";
+import { join } from "path";
+import { compareCanonical } from "./canonical.js";
+import type { ParsedFile } from "./extractor.js";
+
+/**
+ * Hit and miss counts of a package cache.
+ */
+export interface PackageCacheStats {
+  hits: number;
+  misses: number;
+}
+
+/**
+ * A source file of a package: its path relative to the package path, and
+ * its content.
+ */
+export interface PackageSource {
+  file: string;
+  content: string;
+}
+
+/**
+ * Parsed files of a package, by path relative to the package path.
+ */
+export type CachedPackage = Record<string, ParsedFile>;
+
+/**
+ * Parse results of packages, stored as one JSON file per key in a
+ * directory.
+ */
+export class PackageCache {
+  private dir: string;
+  private version: string;
+  private hits = 0;
+  private misses = 0;
+
+  /**
+   * A cache in `dir` for results of the given extractor version.
+   */
+  constructor(dir: string, version: string) {
+    this.dir = dir;
+    this.version = version;
+  }
+
+  /**
+   * The key of a package: a hash of its sources, in path order, and of the
+   * options it's parsed with.
+   */
+  key(sources: PackageSource[], options: unknown[]): string {
+    const hash = createHash("sha256");
+    hash.update(JSON.stringify([this.version, options]));
+    const sorted = [...sources].sort((a, b) => compareCanonical(a.file, b.file));
+    for (const { file, content } of sorted) {
+      hash.update(`\0${file}\0${content.length}\0`);
+      hash.update(content);
+    }
+    return hash.digest("hex");
+  }
+
+  /**
+   * Get a package's parsed files, if they were stored under the key.
+   */
+  async get(key: string): Promise<CachedPackage | undefined> {
+    try {
+      const cached = JSON.parse(await readFile(this.file(key), "utf-8")) as CachedPackage;
+      this.hits++;
+      return cached;
+    } catch {
+      // Missing, or unreadable and overwritten on the next store
+      this.misses++;
+      return undefined;
+    }
+  }
+
+  /**
+   * Store a package's parsed files. They are serialized right away, so
+   * callers may change them once this is called.
+   */
+  async set(key: string, files: CachedPackage): Promise<void> {
+    const content = JSON.stringify(files);
+    await mkdir(this.dir, { recursive: true });
+    // Written aside and renamed, so concurrent runs never read half a file
+    const temp = `${this.file(key)}.${process.pid}.tmp`;
+    await writeFile(temp, content, "utf-8");
+    await rename(temp, this.file(key));
+  }
+
+  /**
+   * Count the lookups so far.
+   */
+  stats(): PackageCacheStats {
+    return { hits: this.hits, misses: this.misses };
+  }
+
+  private file(key: string): string {
+    return join(this.dir, `${key}.json`);
+  }
+}
@@ -0,0 +1,201 @@
+/**
+ * Package Patterns
+ *
+ * Resolves Go package patterns to the package directories of a module, by
+ * the rules of the go command and go/packages: `./...` for every package,
+ * `./sub/...` and `github.com/org/repo/sub/...` for a subtree (including
+ * `sub` itself), and plain relative or import paths for single packages.
+ * As in `go list`, `...` matches any string, and packages in `vendor`,
+ * `testdata`, directories starting with `.` or `_`, and nested modules
+ * aren't matched.
+ */
+
+import { existsSync } from "fs";
+import { readdir, readFile } from "fs/promises";
+import { join, posix } from "path";
+import { importPaths } from "./imports.js";
+
+/**
+ * Directories whose packages patterns don't match.
+ */
+const SKIPPED_DIRS = new Set(["vendor", "testdata"]);
+
+/**
+ * Resolve package patterns to the matching package directories, relative to
+ * the module root with forward slashes ("." for the root), sorted. Fails on
+ * patterns outside the module and patterns that match no package.
+ */
+export async function resolvePackagePatterns(
+  root: string,
+  patterns: string[],
+  modulePath?: string,
+): Promise<string[]> {
+  const module = modulePath ?? (await readModulePath(root));
+  const packages = await findPackageDirs(root);
+  const matched = new Set<string>();
+  for (const pattern of patterns) {
+    const match = packagePatternMatcher(pattern, module);
+    const dirs = packages.filter(match);
+    if (dirs.length === 0) {
+      throw new Error(`Package pattern ${pattern} matched no packages`);
+    }
+    for (const dir of dirs) matched.add(dir);
+  }
+  return [...matched].sort();
+}
+
+/**
+ * A predicate on package directories (relative to the module root) for a
+ * package pattern of the module `modulePath`.
+ */
+export function packagePatternMatcher(
+  pattern: string,
+  modulePath: string | undefined,
+): (dir: string) => boolean {
+  const relative = /^\.\.?(\/|$)/.test(pattern) || !modulePath;
+  let path: string;
+  if (relative) {
+    path = posix.normalize(pattern.replace(/\\/g, "/"));
+    if (path === ".." || path.startsWith("../") || posix.isAbsolute(path)) {
+      throw new Error(`Package pattern ${pattern} is outside the module`);
+    }
+  } else if (pattern === modulePath || pattern.startsWith(`${modulePath}/`)) {
+    path = pattern === modulePath ? "." : pattern.slice(modulePath.length + 1);
+  } else {
+    throw new Error(`Package pattern ${pattern} is outside module ${modulePath}`);
+  }
+
+  if (!path.includes("...")) return (dir) => dir === path;
+  // "sub/..." matches "sub" too, and "./..." every package
+  const source = path
+    .split("...")
+    .map((part) => part.replace(/[.*+?^${}()|[\]\\]/g, "\\$&"))
+    .join(".*")
+    .replace(/\/\.\*$/, "(/.*)?");
+  const regex = new RegExp(`^${source}$`);
+  return (dir) => regex.test(dir);
+}
+
+/**
+ * Turn a file exclusion glob into one for the extractor: globs without a
+ * slash, like `*.pb.go` or `zz_generated*`, match file names in any
+ * directory, and globs ending in a slash match whole trees.
+ */
+export function normalizeExcludeGlob(glob: string): string {
+  if (glob.endsWith("/")) return `${glob}**`;
+  return glob.includes("/") ? glob : `**/${glob}`;
+}
+
+/**
+ * Include patterns for the Go files of package directories.
+ */
+export function packageIncludePatterns(dirs: string[]): string[] {
+  return dirs.map((dir) => (dir === "." ? "*.go" : `${dir}/*.go`));
+}
+
+/**
+ * The package directories of the module at `root` that import one of `dirs`,
+ * directly or through other packages of the module, and `dirs` themselves,
+ * sorted. Constants of those packages can be evaluated in terms of `dirs`.
+ */
+export async function packageDependents(root: string, dirs: string[]): Promise<string[]> {
+  const importedBy = new Map<string, string[]>();
+  for (const [dir, imported] of await packageImports(root)) {
+    for (const target of imported) {
+      importedBy.set(target, [...(importedBy.get(target) ?? []), dir]);
+    }
+  }
+  return reachablePackages(dirs, (dir) => importedBy.get(dir) ?? []);
+}
+
+/**
+ * The package directories of the module at `root` that `dirs` import,
+ * directly or through other packages of the module, and `dirs` themselves,
+ * sorted. Those are the packages to parse to evaluate the constants of `dirs`.
+ */
+export async function packageDependencies(root: string, dirs: string[]): Promise<string[]> {
+  const imports = await packageImports(root);
+  return reachablePackages(dirs, (dir) => imports.get(dir) ?? []);
+}
+
+/**
+ * The directories reachable from `dirs` over `next`, and `dirs`, sorted.
+ */
+function reachablePackages(dirs: string[], next: (dir: string) => string[]): string[] {
+  const reached = new Set(dirs);
+  const queue = [...dirs];
+  while (queue.length > 0) {
+    for (const dir of next(queue.shift()!)) {
+      if (reached.has(dir)) continue;
+      reached.add(dir);
+      queue.push(dir);
+    }
+  }
+  return [...reached].sort();
+}
+
+/**
+ * The packages of the module at `root` each package directory imports, as
+ * directories. Test files count, since their examples are extracted too.
+ */
+async function packageImports(root: string): Promise<Map<string, Set<string>>> {
+  const module = await readModulePath(root);
+  const imports = new Map<string, Set<string>>();
+  if (!module) return imports;
+  for (const dir of await findPackageDirs(root)) {
+    const imported = new Set<string>();
+    for (const entry of await readdir(join(root, dir), { withFileTypes: true })) {
+      if (!entry.isFile() || !isGoFile(entry.name)) continue;
+      for (const path of importPaths(await readFile(join(root, dir, entry.name), "utf-8"))) {
+        if (path === module) imported.add(".");
+        else if (path.startsWith(`${module}/`)) imported.add(path.slice(module.length + 1));
+      }
+    }
+    imported.delete(dir);
+    imports.set(dir, imported);
+  }
+  return imports;
+}
+
+/**
+ * Package directories of the module at `root`: directories with Go files,
+ * outside of skipped directories and nested modules.
+ */
+async function findPackageDirs(root: string): Promise<string[]> {
+  const packages: string[] = [];
+  const walk = async (dir: string): Promise<void> => {
+    const entries = await readdir(join(root, dir), { withFileTypes: true });
+    if (entries.some((entry) => entry.isFile() && isGoFile(entry.name))) {
+      packages.push(dir);
+    }
+    for (const entry of entries) {
+      if (!entry.isDirectory() || SKIPPED_DIRS.has(entry.name) || /^[._]/.test(entry.name)) {
+        continue;
+      }
+      const child = dir === "." ? entry.name : `${dir}/${entry.name}`;
+      if (existsSync(join(root, child, "go.mod"))) continue;
+      await walk(child);
+    }
+  };
+  await walk(".");
+  return packages;
+}
+
+/**
+ * Whether a file name is a Go source file the go command reads.
+ */
+function isGoFile(name: string): boolean {
+  return name.endsWith(".go") && !/^[._]/.test(name);
+}
+
+/**
+ * The module path in a module root's go.mod, if there is one.
+ */
+async function readModulePath(root: string): Promise<string | undefined> {
+  try {
+    const goMod = await readFile(join(root, "go.mod"), "utf-8");
+    return goMod.match(/^module\s+"?([^"\s]+)"?/m)?.[1];
+  } catch {
+    return undefined;
+  }
+}
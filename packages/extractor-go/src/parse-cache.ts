@@ -0,0 +1,72 @@
+/**
+ * Parse Cache
+ *
+ * Keeps per-file parse results across extractions so a long-running process
+ * only re-parses the files that changed.
+ */
+
+import { resolve, sep } from "path";
+
+/**
+ * Hit and miss counts of a cache.
+ */
+export interface ParseCacheStats {
+  files: number;
+  hits: number;
+  misses: number;
+}
+
+/**
+ * Parse results keyed by file path and a validity key (such as the file's
+ * modification time and size plus the options it was parsed with).
+ */
+export class ParseCache<T> {
+  private entries = new Map<string, { key: string; value: T }>();
+  private hits = 0;
+  private misses = 0;
+
+  /**
+   * Get the cached result for a file, if it was stored under the same key.
+   */
+  get(file: string, key: string): T | undefined {
+    const entry = this.entries.get(file);
+    if (entry?.key === key) {
+      this.hits++;
+      return entry.value;
+    }
+    this.misses++;
+    return undefined;
+  }
+
+  /**
+   * Store the result for a file, replacing any older entry.
+   */
+  set(file: string, key: string, value: T): void {
+    this.entries.set(file, { key, value });
+  }
+
+  /**
+   * Drop the entries of the files below a directory, e.g. of a module that's
+   * no longer served.
+   */
+  deleteDir(dir: string): void {
+    const prefix = `${resolve(dir)}${sep}`;
+    for (const file of this.entries.keys()) {
+      if (resolve(file).startsWith(prefix)) this.entries.delete(file);
+    }
+  }
+
+  /**
+   * Drop every entry.
+   */
+  clear(): void {
+    this.entries.clear();
+  }
+
+  /**
+   * Count the cached files and the lookups so far.
+   */
+  stats(): ParseCacheStats {
+    return { files: this.entries.size, hits: this.hits, misses: this.misses };
+  }
+}
@@ -0,0 +1,25 @@
+/**
+ * Parse Worker
+ *
+ * Entry point of the worker threads parsing Go files for an extraction with
+ * `workers` above 1. Each worker is started with the extraction's
+ * configuration and replies to every file path it's sent with the file's
+ * parse result.
+ */
+
+import { parentPort, workerData } from "worker_threads";
+import type { GoExtractorConfig } from "./config.js";
+import { GoExtractor, type ParsedFile } from "./extractor.js";
+import type { WorkerReply } from "./worker-pool.js";
+
+const extractor = new GoExtractor(workerData as GoExtractorConfig);
+
+parentPort!.on("message", async (file: string) => {
+  let reply: WorkerReply<ParsedFile>;
+  try {
+    reply = { result: await extractor.parseSourceFile(file) };
+  } catch (error) {
+    reply = { error: error instanceof Error ? error.message : String(error) };
+  }
+  parentPort!.postMessage(reply);
+});
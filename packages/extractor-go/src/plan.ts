@@ -0,0 +1,141 @@
+/**
+ * Dry Runs
+ *
+ * Plans a run without touching its outputs: the CLI writes everything to a
+ * staging directory instead, and the staged files are compared with the
+ * files at the real output paths, to report which would be created,
+ * updated, left unchanged, or deleted, and how large they would be. Useful
+ * before large publishing runs.
+ */
+
+import { existsSync } from "fs";
+import { readdir, readFile, stat } from "fs/promises";
+import { join, posix } from "path";
+import { compareCanonical } from "./canonical.js";
+import type { ProfiledOutput } from "./profile.js";
+
+/**
+ * What a run would do to an output file.
+ */
+export type PlannedAction = "create" | "update" | "unchanged" | "delete";
+
+/**
+ * An output file of a planned run.
+ */
+export interface PlannedFile {
+  /** Path the file would be written to, or deleted from */
+  path: string;
+  action: PlannedAction;
+  /** Size of the file as it would be written, or as it is for deletions */
+  bytes: number;
+}
+
+/**
+ * A package a planned run would extract.
+ */
+export interface PlannedPackage {
+  importPath: string;
+  symbols: number;
+}
+
+/**
+ * The packages of an extracted output, by import path, with their symbol
+ * counts, limited to the package directories `dirs` if given. Symbols of
+ * profiles without source locations count for the module.
+ */
+export function plannedPackages(output: ProfiledOutput, dirs?: string[]): PlannedPackage[] {
+  const module = output.package.modulePath || output.package.publishedName;
+  const counts = new Map<string, number>();
+  for (const symbol of output.symbols) {
+    const path = "source" in symbol ? symbol.source?.path : undefined;
+    const dir = path ? posix.dirname(path) : ".";
+    if (dirs && !dirs.includes(dir)) continue;
+    const importPath = dir === "." ? module : `${module}/${dir}`;
+    counts.set(importPath, (counts.get(importPath) ?? 0) + 1);
+  }
+  return [...counts.keys()]
+    .sort(compareCanonical)
+    .map((importPath) => ({ importPath, symbols: counts.get(importPath)! }));
+}
+
+/**
+ * Compare the files staged at `staged` (a file or a directory) with those
+ * at `target`, the path they were meant for. Returns nothing if nothing was
+ * staged.
+ */
+export async function planFiles(staged: string, target: string): Promise<PlannedFile[]> {
+  if (!existsSync(staged)) return [];
+  if (!(await stat(staged)).isDirectory()) {
+    return [await planFile(staged, target)];
+  }
+  const files: PlannedFile[] = [];
+  for (const entry of await readdir(staged, { withFileTypes: true })) {
+    const path = join(staged, entry.name);
+    files.push(...(await planFiles(path, join(target, entry.name))));
+  }
+  return files.sort((a, b) => compareCanonical(a.path, b.path));
+}
+
+/**
+ * Plan the deletion of files that exist, e.g. stale package files.
+ */
+export async function planDeletions(paths: string[]): Promise<PlannedFile[]> {
+  const files: PlannedFile[] = [];
+  for (const path of paths) {
+    if (existsSync(path)) {
+      files.push({ path, action: "delete", bytes: (await stat(path)).size });
+    }
+  }
+  return files;
+}
+
+/**
+ * Format a plan: the packages to extract, then the files with their actions
+ * and sizes, and totals.
+ */
+export function formatPlan(packages: PlannedPackage[], files: PlannedFile[]): string {
+  const lines = [`Packages to extract (${packages.length}):`];
+  for (const pkg of packages) {
+    lines.push(`  ${pkg.importPath} (${pkg.symbols} symbols)`);
+  }
+  lines.push("", `Files (${files.length}):`);
+  for (const file of files) {
+    lines.push(`  ${file.action.padEnd(9)} ${formatBytes(file.bytes).padStart(9)}  ${file.path}`);
+  }
+  const count = (action: PlannedAction) => files.filter((file) => file.action === action).length;
+  const written = files
+    .filter((file) => file.action === "create" || file.action === "update")
+    .reduce((total, file) => total + file.bytes, 0);
+  lines.push(
+    "",
+    `${count("create")} to create, ${count("update")} to update, ${count("unchanged")} ` +
+      `unchanged, ${count("delete")} to delete; ${formatBytes(written)} to write`,
+  );
+  return lines.join("\n");
+}
+
+/**
+ * Format a size in bytes with binary units, e.g. "1.5 KiB".
+ */
+export function formatBytes(bytes: number): string {
+  const units = ["B", "KiB", "MiB", "GiB"];
+  let size = bytes;
+  let unit = 0;
+  while (size >= 1024 && unit < units.length - 1) {
+    size /= 1024;
+    unit++;
+  }
+  return unit === 0 ? `${size} B` : `${size.toFixed(1)} ${units[unit]}`;
+}
+
+/**
+ * Compare a staged file with the file at its target path.
+ */
+async function planFile(staged: string, target: string): Promise<PlannedFile> {
+  const content = await readFile(staged);
+  if (!existsSync(target)) {
+    return { path: target, action: "create", bytes: content.length };
+  }
+  const unchanged = content.equals(await readFile(target));
+  return { path: target, action: unchanged ? "unchanged" : "update", bytes: content.length };
+}
@@ -0,0 +1,166 @@
+/**
+ * Transform Plugins
+ *
+ * Runs plugins over the output before it's written, so a docs team can
+ * rewrite, enrich, or filter symbol records without forking the extractor,
+ * like injecting company-specific stability metadata. A plugin is an
+ * executable, or a Go file run with `go run`, speaking JSON over stdio: it
+ * reads `{ "version": 1, "package": ..., "symbols": [...] }` from stdin and
+ * writes `{ "symbols": [...] }` to stdout, with a replacement `package` if it
+ * changes the header. Symbols it leaves out are dropped. Plugins run in the
+ * order given, each on the previous one's result. Afterwards the symbols are
+ * put back in canonical order, and the categories, capabilities, and doc
+ * coverage derived from them are rebuilt. A plugin's stderr is logged as
+ * warnings, so its diagnostics show without --verbose and stay off stdout.
+ */
+
+import { spawn } from "child_process";
+import { resolve } from "path";
+import type { SymbolRecord } from "@langchain/ir-schema";
+import { sortSymbols } from "./canonical.js";
+import { buildCapabilities } from "./capabilities.js";
+import type { GoExtractorConfig } from "./config.js";
+import { buildCoverage } from "./coverage.js";
+import { logger } from "./logger.js";
+import { groupCategories, type ExtractorOutput, type OutputPackage } from "./output.js";
+import type { GoSymbolRecord } from "./transformer.js";
+
+/**
+ * Version of the stdio protocol, sent with every request.
+ */
+export const pluginProtocolVersion = 1;
+
+/**
+ * What a plugin reads from stdin.
+ */
+export interface PluginRequest {
+  version: number;
+  package: OutputPackage;
+  symbols: SymbolRecord[];
+}
+
+/**
+ * What a plugin writes to stdout.
+ */
+export interface PluginResponse {
+  /** The package header, if the plugin changes it */
+  package?: OutputPackage;
+  symbols: SymbolRecord[];
+}
+
+/**
+ * The command running a plugin: `go run` for Go files, the file itself
+ * otherwise.
+ */
+export function pluginCommand(plugin: string): { command: string; args: string[] } {
+  const file = resolve(plugin);
+  return file.endsWith(".go")
+    ? { command: "go", args: ["run", file] }
+    : { command: file, args: [] };
+}
+
+/**
+ * Check a plugin's stdout is a response.
+ */
+export function parsePluginResponse(stdout: string): PluginResponse {
+  let response: Partial<PluginResponse> | null;
+  try {
+    response = JSON.parse(stdout) as Partial<PluginResponse> | null;
+  } catch (error) {
+    throw new Error(`wrote invalid JSON: ${(error as Error).message}`, { cause: error });
+  }
+  if (
+    !Array.isArray(response?.symbols) ||
+    !response.symbols.every(
+      (symbol) => typeof symbol?.id === "string" && typeof symbol.source?.path === "string",
+    )
+  ) {
+    throw new Error(
+      "must write an object with a symbols array of records with an id and a source path",
+    );
+  }
+  if (response.package !== undefined && typeof response.package?.packageId !== "string") {
+    throw new Error("must write a package header with a packageId, if any");
+  }
+  return response as PluginResponse;
+}
+
+/**
+ * Run one plugin over an output.
+ */
+export async function runPlugin(plugin: string, output: ExtractorOutput): Promise<ExtractorOutput> {
+  const { command, args } = pluginCommand(plugin);
+  const request: PluginRequest = {
+    version: pluginProtocolVersion,
+    package: output.package,
+    symbols: output.symbols,
+  };
+
+  const { code, stdout, stderr } = await new Promise<{
+    code: number | null;
+    stdout: string;
+    stderr: string;
+  }>((done, fail) => {
+    const child = spawn(command, args, { stdio: ["pipe", "pipe", "pipe"] });
+    const stdout: Buffer[] = [];
+    const stderr: Buffer[] = [];
+    child.stdout.on("data", (chunk: Buffer) => stdout.push(chunk));
+    child.stderr.on("data", (chunk: Buffer) => stderr.push(chunk));
+    child.on("error", fail);
+    child.on("close", (code) =>
+      done({
+        code,
+        stdout: Buffer.concat(stdout).toString("utf-8"),
+        stderr: Buffer.concat(stderr).toString("utf-8"),
+      }),
+    );
+    // A plugin exiting without reading its input fails by its exit code
+    child.stdin.on("error", () => {});
+    child.stdin.end(JSON.stringify(request));
+  }).catch((error: Error) => {
+    throw new Error(`Plugin ${plugin} failed to start: ${error.message}`, { cause: error });
+  });
+
+  if (code !== 0) {
+    const detail = stderr.trim() ? `: ${stderr.trim()}` : "";
+    throw new Error(`Plugin ${plugin} exited with code ${code}${detail}`);
+  }
+  for (const line of stderr.split("\n").filter(Boolean)) {
+    logger.warn(`Plugin ${plugin}: ${line}`);
+  }
+
+  let response: PluginResponse;
+  try {
+    response = parsePluginResponse(stdout);
+  } catch (error) {
+    throw new Error(`Plugin ${plugin} ${(error as Error).message}`, { cause: error });
+  }
+  return { ...output, package: response.package ?? output.package, symbols: response.symbols };
+}
+
+/**
+ * Run plugins over an output in order, and rebuild what's derived from the
+ * symbols they return.
+ */
+export async function applyPlugins(
+  output: ExtractorOutput,
+  plugins: string[],
+  config: GoExtractorConfig,
+): Promise<ExtractorOutput> {
+  if (plugins.length === 0) return output;
+
+  let current = output;
+  for (const plugin of plugins) {
+    const before = current.symbols.length;
+    current = await runPlugin(plugin, current);
+    logger.debug(`Plugin ${plugin}: ${before} symbols in, ${current.symbols.length} out`);
+  }
+  const symbols = sortSymbols(current.symbols as GoSymbolRecord[]);
+  return {
+    ...current,
+    package: { ...current.package, categories: groupCategories(symbols) },
+    capabilities: buildCapabilities(config, symbols),
+    coverage: buildCoverage(symbols),
+    symbols,
+  };
+}
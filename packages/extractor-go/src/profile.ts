@@ -0,0 +1,129 @@
+/**
+ * Extraction Profiles
+ *
+ * Profiles control how much of an extraction is emitted. Every profile runs
+ * the same pipeline and projects the full output down to its subset, though
+ * callers may skip work only the full profile needs, like attaching
+ * examples.
+ */
+
+import type { SymbolKind } from "@langchain/ir-schema";
+import type { ExtractorOutput, OutputPackage } from "./output.js";
+import type { OutputLabels } from "./labels.js";
+import type { Provenance } from "./provenance.js";
+
+/**
+ * How much of an extraction to emit.
+ *
+ * - `full`: every symbol with docs, signatures, members, and metadata
+ * - `summary`: package synopsis plus the name, kind, signature, and docs of
+ *   each exported symbol, without examples
+ * - `signatures`: the name, kind, and signature of each exported symbol,
+ *   without docs
+ */
+export type ExtractionProfile = "full" | "summary" | "signatures";
+
+/**
+ * All extraction profiles.
+ */
+export const extractionProfiles: ExtractionProfile[] = ["full", "summary", "signatures"];
+
+/**
+ * A symbol in the summary or signatures profile.
+ */
+export interface SummarySymbol {
+  id: string;
+  name: string;
+  qualifiedName: string;
+  kind: SymbolKind;
+  /** One-line doc, in the summary profile */
+  summary?: string;
+  /** Doc beyond the one-line summary, in the summary profile */
+  description?: string;
+  signature?: string;
+}
+
+/**
+ * Output of the summary or signatures profile.
+ */
+export interface SummaryOutput {
+  package: OutputPackage;
+  provenance?: Provenance;
+  labels?: OutputLabels;
+  symbols: SummarySymbol[];
+}
+
+/**
+ * Output of any profile.
+ */
+export type ProfiledOutput = ExtractorOutput | SummaryOutput;
+
+/**
+ * Project a full output to the given profile.
+ */
+export function applyProfile(output: ExtractorOutput, profile: ExtractionProfile): ProfiledOutput {
+  switch (profile) {
+    case "summary":
+      return summarizeOutput(output);
+    case "signatures":
+      return signaturesOutput(output);
+    default:
+      return output;
+  }
+}
+
+/**
+ * Reduce a full output to package synopsis and exported symbol names, kinds,
+ * signatures, and docs without examples.
+ */
+export function summarizeOutput(output: ExtractorOutput): SummaryOutput {
+  return {
+    package: {
+      ...output.package,
+      overview: undefined,
+      overviewBlocks: undefined,
+      examples: undefined,
+    },
+    provenance: output.provenance,
+    labels: output.labels,
+    symbols: output.symbols
+      .filter((symbol) => symbol.tags.visibility === "public")
+      .map((symbol) => ({
+        id: symbol.id,
+        name: symbol.name,
+        qualifiedName: symbol.qualifiedName,
+        kind: symbol.kind,
+        summary: symbol.docs.summary || undefined,
+        description: symbol.docs.description || undefined,
+        signature: symbol.signature || undefined,
+      })),
+  };
+}
+
+/**
+ * Reduce a full output to the package header without docs, and exported
+ * symbol names, kinds, and signatures.
+ */
+export function signaturesOutput(output: ExtractorOutput): SummaryOutput {
+  return {
+    package: {
+      ...output.package,
+      synopsis: undefined,
+      overview: undefined,
+      overviewBlocks: undefined,
+      examples: undefined,
+      notes: undefined,
+    },
+    provenance: output.provenance,
+    labels: output.labels,
+    symbols: output.symbols
+      .filter((symbol) => symbol.tags.visibility === "public")
+      .map((symbol) => ({
+        id: symbol.id,
+        name: symbol.name,
+        qualifiedName: symbol.qualifiedName,
+        kind: symbol.kind,
+        signature: symbol.signature || undefined,
+      })),
+  };
+}
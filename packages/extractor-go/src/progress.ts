@@ -0,0 +1,191 @@
+/**
+ * Progress Reporting
+ *
+ * Reports how far an extraction has got, so runs over large repositories
+ * don't look hung. Progress is reported in one of two modes:
+ *
+ * - `bar`: a progress bar redrawn in place, for terminals
+ * - `events`: a log message every few seconds with the packages completed,
+ *   the total, the current package, and the ETA, for CI logs; as JSON, its
+ *   fields are structured
+ */
+
+import { performance } from "perf_hooks";
+import { logger } from "./logger.js";
+
+/**
+ * How progress is reported.
+ */
+export type ProgressMode = "bar" | "events";
+
+/**
+ * Where a progress bar is drawn.
+ */
+export interface ProgressStream {
+  isTTY?: boolean;
+  columns?: number;
+  write(chunk: string): boolean;
+}
+
+/**
+ * Options of a progress reporter.
+ */
+export interface ProgressOptions {
+  /** Defaults to a bar on a terminal with text logs, and events otherwise */
+  mode?: ProgressMode;
+  /** Stream the bar is drawn on (default: stderr) */
+  stream?: ProgressStream;
+  /** Milliseconds between events (default: 5000) */
+  interval?: number;
+  /** Clock, in milliseconds */
+  now?: () => number;
+}
+
+/**
+ * Snapshot of a run's progress.
+ */
+export interface ProgressState {
+  completed: number;
+  total: number;
+  /** Package being extracted ("" for the root) */
+  package?: string;
+  /** Estimated milliseconds left, once a package has completed */
+  etaMs?: number;
+}
+
+/** Milliseconds between redraws of the bar */
+const BAR_INTERVAL = 100;
+
+/** Width of the bar itself, in characters */
+const BAR_WIDTH = 24;
+
+/**
+ * Tracks the packages an extraction has completed and reports them.
+ */
+export class ProgressReporter {
+  readonly mode: ProgressMode;
+  private stream: ProgressStream;
+  private interval: number;
+  private now: () => number;
+  private total = 0;
+  private completed = 0;
+  private current?: string;
+  private started = 0;
+  private reported?: number;
+
+  constructor(options: ProgressOptions = {}) {
+    this.stream = options.stream ?? process.stderr;
+    this.mode =
+      options.mode ?? (this.stream.isTTY && logger.format === "text" ? "bar" : "events");
+    this.interval = options.interval ?? 5000;
+    this.now = options.now ?? (() => performance.now());
+  }
+
+  /**
+   * Start a run over a number of packages.
+   */
+  start(total: number): void {
+    this.total = total;
+    this.completed = 0;
+    this.current = undefined;
+    this.started = this.now();
+    this.reported = undefined;
+  }
+
+  /**
+   * Note the package being extracted.
+   */
+  begin(pkg: string): void {
+    this.current = pkg;
+    this.report();
+  }
+
+  /**
+   * Count a package as completed.
+   */
+  complete(): void {
+    this.completed = Math.min(this.completed + 1, this.total);
+    this.report();
+  }
+
+  /**
+   * End the run: clear the bar, or report the final state if any event was.
+   */
+  finish(): void {
+    if (this.mode === "bar") {
+      if (this.reported !== undefined) this.stream.write("\r\x1b[K");
+    } else if (this.reported !== undefined) {
+      this.emit();
+    }
+    this.reported = undefined;
+  }
+
+  /**
+   * The current progress.
+   */
+  state(): ProgressState {
+    const elapsed = this.now() - this.started;
+    const etaMs =
+      this.completed > 0
+        ? Math.round((elapsed / this.completed) * (this.total - this.completed))
+        : undefined;
+    return { completed: this.completed, total: this.total, package: this.current, etaMs };
+  }
+
+  /**
+   * Redraw the bar or emit an event, if enough time has passed.
+   */
+  private report(): void {
+    const now = this.now();
+    if (this.mode === "bar") {
+      if (this.reported !== undefined && now - this.reported < BAR_INTERVAL) return;
+      this.reported = now;
+      this.stream.write(`\r${formatProgressBar(this.state(), this.stream.columns)}\x1b[K`);
+    } else if (now - (this.reported ?? this.started) >= this.interval) {
+      this.reported = now;
+      this.emit();
+    }
+  }
+
+  private emit(): void {
+    const state = this.state();
+    logger.info(`⏳ ${formatProgress(state)}`, { event: "progress", ...state });
+  }
+}
+
+/**
+ * Format progress as a line: the packages completed, the current package,
+ * and the ETA.
+ */
+export function formatProgress(state: ProgressState): string {
+  const parts = [`${state.completed}/${state.total} packages`];
+  if (state.package !== undefined && state.completed < state.total) {
+    parts.push(state.package || ".");
+  }
+  if (state.etaMs !== undefined && state.completed < state.total) {
+    parts.push(`ETA ${formatDuration(state.etaMs)}`);
+  }
+  return parts.join(", ");
+}
+
+/**
+ * Format progress as a bar followed by its line, cut to fit a terminal.
+ */
+export function formatProgressBar(state: ProgressState, columns = 80): string {
+  const ratio = state.total > 0 ? state.completed / state.total : 1;
+  const filled = Math.round(ratio * BAR_WIDTH);
+  const bar = `[${"#".repeat(filled)}${"-".repeat(BAR_WIDTH - filled)}]`;
+  const line = `${bar} ${Math.floor(ratio * 100)}% ${formatProgress(state)}`;
+  return line.length < columns ? line : `${line.slice(0, Math.max(columns - 2, 0))}…`;
+}
+
+/**
+ * Format milliseconds as a rough duration, like "1m 20s".
+ */
+export function formatDuration(ms: number): string {
+  const seconds = Math.ceil(ms / 1000);
+  if (seconds < 60) return `${seconds}s`;
+  const minutes = Math.floor(seconds / 60);
+  if (minutes < 60) return `${minutes}m ${seconds % 60}s`;
+  return `${Math.floor(minutes / 60)}h ${minutes % 60}m`;
+}
@@ -0,0 +1,173 @@
+/**
+ * Protobuf
+ *
+ * Reads and writes the protobuf binary wire format without a protobuf
+ * dependency, for the SCIP index and the gRPC service. Messages are built and
+ * taken apart field by field, so only varint and length-delimited fields
+ * (strings, bytes, embedded messages, packed varints) are supported.
+ */
+
+/**
+ * A field read from a message: a varint's value, or a length-delimited
+ * field's bytes.
+ */
+export interface ProtoField {
+  field: number;
+  value: number | Uint8Array;
+}
+
+/**
+ * Writes protobuf fields in the binary wire format.
+ */
+export class ProtoWriter {
+  private bytes: number[] = [];
+
+  /** Write a varint field; zero values are left out, as proto3 does. */
+  varint(field: number, value: number): this {
+    if (value === 0) return this;
+    this.tag(field, 0);
+    this.raw(value);
+    return this;
+  }
+
+  /** Write a string field; empty strings are left out. */
+  string(field: number, value: string): this {
+    if (!value) return this;
+    return this.bytesField(field, new TextEncoder().encode(value));
+  }
+
+  /** Write a repeated string field, one entry per value. */
+  strings(field: number, values: string[]): this {
+    for (const value of values) {
+      this.bytesField(field, new TextEncoder().encode(value));
+    }
+    return this;
+  }
+
+  /** Write an embedded message field. */
+  message(field: number, value: ProtoWriter): this {
+    return this.bytesField(field, value.finish());
+  }
+
+  /** Write a packed repeated varint field. */
+  packed(field: number, values: number[]): this {
+    const inner = new ProtoWriter();
+    for (const value of values) inner.raw(value);
+    return this.bytesField(field, inner.finish());
+  }
+
+  finish(): Uint8Array {
+    return Uint8Array.from(this.bytes);
+  }
+
+  private bytesField(field: number, value: Uint8Array): this {
+    this.tag(field, 2);
+    this.raw(value.length);
+    // Not push(...value), which overflows the stack for large messages
+    for (const byte of value) this.bytes.push(byte);
+    return this;
+  }
+
+  private tag(field: number, wireType: number): void {
+    this.raw((field << 3) | wireType);
+  }
+
+  private raw(value: number): void {
+    let rest = value;
+    while (rest > 0x7f) {
+      this.bytes.push((rest & 0x7f) | 0x80);
+      rest = Math.floor(rest / 128);
+    }
+    this.bytes.push(rest);
+  }
+}
+
+/**
+ * Read the fields of a message in order. Fixed-width fields are skipped.
+ */
+export function readProtoFields(bytes: Uint8Array): ProtoField[] {
+  const fields: ProtoField[] = [];
+  let offset = 0;
+  const varint = (): number => {
+    let value = 0;
+    let scale = 1;
+    for (;;) {
+      if (offset >= bytes.length) throw new Error("Truncated protobuf varint");
+      const byte = bytes[offset++];
+      value += (byte & 0x7f) * scale;
+      if (byte < 0x80) return value;
+      scale *= 128;
+    }
+  };
+
+  while (offset < bytes.length) {
+    const tag = varint();
+    const field = Math.floor(tag / 8);
+    switch (tag & 7) {
+      case 0:
+        fields.push({ field, value: varint() });
+        break;
+      case 1:
+        offset += 8;
+        break;
+      case 2: {
+        const length = varint();
+        if (offset + length > bytes.length) throw new Error("Truncated protobuf field");
+        fields.push({ field, value: bytes.subarray(offset, offset + length) });
+        offset += length;
+        break;
+      }
+      case 5:
+        offset += 4;
+        break;
+      default:
+        throw new Error(`Unsupported protobuf wire type ${tag & 7} of field ${field}`);
+    }
+  }
+  return fields;
+}
+
+/**
+ * Get the last value of a string field, or "" if it isn't set.
+ */
+export function protoString(fields: ProtoField[], field: number): string {
+  const value = lastValue(fields, field);
+  return value instanceof Uint8Array ? new TextDecoder().decode(value) : "";
+}
+
+/**
+ * Get every value of a repeated string field.
+ */
+export function protoStrings(fields: ProtoField[], field: number): string[] {
+  return fields
+    .filter((f) => f.field === field && f.value instanceof Uint8Array)
+    .map((f) => new TextDecoder().decode(f.value as Uint8Array));
+}
+
+/**
+ * Get the last value of a varint field, or 0 if it isn't set.
+ */
+export function protoVarint(fields: ProtoField[], field: number): number {
+  const value = lastValue(fields, field);
+  return typeof value === "number" ? value : 0;
+}
+
+/**
+ * Get every embedded message of a repeated message field, as its fields.
+ */
+export function protoMessages(fields: ProtoField[], field: number): ProtoField[][] {
+  return fields
+    .filter((f) => f.field === field && f.value instanceof Uint8Array)
+    .map((f) => readProtoFields(f.value as Uint8Array));
+}
+
+/**
+ * The last value of a field, which wins in protobuf.
+ */
+function lastValue(fields: ProtoField[], field: number): ProtoField["value"] | undefined {
+  let value: ProtoField["value"] | undefined;
+  for (const f of fields) {
+    if (f.field === field) value = f.value;
+  }
+  return value;
+}
@@ -0,0 +1,109 @@
+/**
+ * Provenance
+ *
+ * Records where an output came from, so published references are traceable
+ * and reproducible: the extractor and schema versions, the module path and
+ * version, the VCS commit of the source and whether the working tree had
+ * uncommitted changes, the target platform, and when it was extracted. The
+ * timestamp honors SOURCE_DATE_EPOCH, so reproducible builds write
+ * byte-identical outputs.
+ */
+
+import { execFile } from "child_process";
+import { readFile } from "fs/promises";
+import { promisify } from "util";
+import { hostGoarch, hostGoos } from "./build-constraints.js";
+import { outputSchemaVersion } from "./schema.js";
+import type { OutputPackage } from "./output.js";
+
+/**
+ * Provenance of an output.
+ */
+export interface Provenance {
+  extractor: { name: string; version: string };
+  /** Version of the output schema the output follows */
+  schemaVersion: number;
+  module: { path: string; version: string };
+  /** Commit of the source, when it is in a git working tree */
+  vcs?: {
+    system: "git";
+    revision: string;
+    /** Whether the working tree had uncommitted changes */
+    modified: boolean;
+  };
+  goos: string;
+  goarch: string;
+  /** When the output was extracted, as ISO 8601 (SOURCE_DATE_EPOCH when set) */
+  timestamp: string;
+}
+
+/**
+ * Collect the provenance of an output of the package at `packagePath`, for
+ * the given target platform or the host's.
+ */
+export async function collectProvenance(
+  pkg: OutputPackage,
+  packagePath: string,
+  env: NodeJS.ProcessEnv = process.env,
+  target: { goos?: string; goarch?: string } = {},
+): Promise<Provenance> {
+  const manifest = await extractorManifest();
+  return {
+    extractor: { name: manifest.name, version: manifest.version },
+    schemaVersion: outputSchemaVersion,
+    module: { path: pkg.modulePath || pkg.publishedName, version: pkg.version },
+    vcs: await gitStatus(packagePath),
+    goos: target.goos || hostGoos(env),
+    goarch: target.goarch || hostGoarch(env),
+    timestamp: provenanceTimestamp(env),
+  };
+}
+
+/**
+ * Name and version of the extractor, from its package.json.
+ */
+export async function extractorManifest(): Promise<{ name: string; version: string }> {
+  return JSON.parse(await readFile(new URL("../package.json", import.meta.url), "utf-8")) as {
+    name: string;
+    version: string;
+  };
+}
+
+/**
+ * The extraction timestamp: SOURCE_DATE_EPOCH (seconds since the epoch) when
+ * set, or the current time.
+ */
+export function provenanceTimestamp(env: NodeJS.ProcessEnv = process.env): string {
+  const epoch = env.SOURCE_DATE_EPOCH;
+  if (epoch === undefined || epoch === "") return new Date().toISOString();
+  if (!/^\d+$/.test(epoch)) {
+    throw new Error(`Invalid SOURCE_DATE_EPOCH: ${epoch}`);
+  }
+  return new Date(Number(epoch) * 1000).toISOString();
+}
+
+/**
+ * Add provenance to an output, right after its package header.
+ */
+export function withProvenance<T extends { package: OutputPackage }>(
+  output: T,
+  provenance: Provenance,
+): T & { provenance: Provenance } {
+  return { package: output.package, provenance, ...output };
+}
+
+/**
+ * The commit and working tree state of the git repository a directory is
+ * in, or undefined outside of one (or without git).
+ */
+async function gitStatus(dir: string): Promise<Provenance["vcs"]> {
+  const git = (...args: string[]) =>
+    promisify(execFile)("git", args, { cwd: dir }).then(({ stdout }) => stdout.trim());
+  try {
+    const revision = await git("rev-parse", "HEAD");
+    const status = await git("status", "--porcelain");
+    return { system: "git", revision, modified: status !== "" };
+  } catch {
+    return undefined;
+  }
+}
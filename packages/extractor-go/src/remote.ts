@@ -0,0 +1,278 @@
+/**
+ * Remote Modules
+ *
+ * Downloads a module by import path and version from a Go module proxy
+ * (GOPROXY, https://proxy.golang.org by default), so references can be
+ * generated without cloning repositories: the version is resolved with the
+ * proxy's `.info` endpoint (`latest` with `@latest`), and the module zip is
+ * unpacked into a cache directory laid out like GOMODCACHE, where later runs
+ * find it again.
+ *
+ * Module zips are read without a zip dependency: the proxy serves plain
+ * stored or deflated entries, which are checked against their CRC-32 and
+ * must stay inside the `<path>@<version>/` prefix the zip format of Go
+ * modules requires.
+ */
+
+import { existsSync } from "fs";
+import { mkdir, mkdtemp, rename, rm, writeFile } from "fs/promises";
+import { tmpdir } from "os";
+import { dirname, join, posix } from "path";
+import { crc32, inflateRawSync } from "zlib";
+
+/**
+ * Default proxy, as in the go command.
+ */
+export const defaultGoProxy = "https://proxy.golang.org";
+
+/**
+ * Default directory downloaded modules are cached in.
+ */
+export const defaultModuleCache = join(tmpdir(), "extract-go-modules");
+
+/**
+ * A module import path and version, e.g. github.com/tmc/langchaingo@v0.1.13.
+ */
+export interface ModuleSpec {
+  path: string;
+  /** A version, or "latest" */
+  version: string;
+}
+
+/**
+ * A module downloaded to the cache.
+ */
+export interface DownloadedModule {
+  path: string;
+  /** The resolved version */
+  version: string;
+  /** Directory of the module's files */
+  dir: string;
+  /** Whether the module was already in the cache */
+  cached: boolean;
+}
+
+/**
+ * Options of a module download.
+ */
+export interface DownloadOptions {
+  /** Cache directory (default: a directory in the system temp dir) */
+  cacheDir?: string;
+  /** Environment to read GOPROXY from (default: process.env) */
+  env?: NodeJS.ProcessEnv;
+  /** HTTP client (default: the global fetch) */
+  fetch?: typeof fetch;
+}
+
+/**
+ * Parse `<import path>@<version>`. The version defaults to latest.
+ */
+export function parseModuleSpec(spec: string): ModuleSpec {
+  const at = spec.lastIndexOf("@");
+  const path = at < 0 ? spec : spec.slice(0, at);
+  const version = at < 0 ? "latest" : spec.slice(at + 1);
+  // Like the go command, elements can't start or end with a dot, which also
+  // keeps `..` out of cache paths
+  const elements = path.split("/");
+  if (
+    !/^[\w.~-]+(\/[\w.~-]+)*$/.test(path) ||
+    !elements[0].includes(".") ||
+    elements.some((element) => element.startsWith(".") || element.endsWith("."))
+  ) {
+    throw new Error(`Invalid module path: ${path}`);
+  }
+  if (version !== "latest" && !/^v\d+\.\d+\.\d+(-[\w.-]+)?(\+[\w.-]+)?$/.test(version)) {
+    throw new Error(`Invalid module version: ${version} (expected e.g. v1.2.3 or latest)`);
+  }
+  return { path, version };
+}
+
+/**
+ * The proxy URL GOPROXY selects: its first entry. `direct` and `off` aren't
+ * supported, since modules are only downloaded through a proxy.
+ */
+export function goProxyUrl(env: NodeJS.ProcessEnv = process.env): string {
+  const first = (env.GOPROXY || defaultGoProxy).split(/[,|]/)[0].trim();
+  if (first === "off") {
+    throw new Error("GOPROXY=off disallows downloading modules");
+  }
+  if (first === "direct") {
+    throw new Error("GOPROXY=direct isn't supported; set GOPROXY to a module proxy URL");
+  }
+  return first.replace(/\/+$/, "");
+}
+
+/**
+ * Escape a module path or version for proxy URLs and cache paths, the way
+ * the go command does: uppercase letters become `!` and the lowercase
+ * letter, so paths stay distinct on case-insensitive file systems.
+ */
+export function escapeModulePath(path: string): string {
+  return path.replace(/[A-Z]/g, (letter) => `!${letter.toLowerCase()}`);
+}
+
+/**
+ * Name of a module without its major version suffix, e.g. "langchaingo" for
+ * github.com/tmc/langchaingo and "go-redis" for github.com/redis/go-redis/v9.
+ */
+export function moduleName(path: string): string {
+  const parts = path.split("/");
+  const last = parts.at(-1)!;
+  return /^v\d+$/.test(last) && parts.length > 1 ? parts.at(-2)! : last;
+}
+
+/**
+ * The GitHub repository (`owner/name`) of a module hosted on GitHub.
+ */
+export function githubRepo(path: string): string | undefined {
+  const [host, owner, name] = path.split("/");
+  return host === "github.com" && owner && name ? `${owner}/${name}` : undefined;
+}
+
+/**
+ * The git revision of a version of a module on GitHub: the tag, which is
+ * `sub/dir/v1.2.3` for a module in a subdirectory, or the commit of a
+ * pseudo-version.
+ */
+export function moduleRevision(path: string, version: string): string {
+  const pseudo = version.match(/[-.]\d{14}-([0-9a-f]{12})(\+incompatible)?$/);
+  if (pseudo) return pseudo[1];
+  const subdir = path
+    .split("/")
+    .slice(3)
+    .filter((part, i, parts) => !(i === parts.length - 1 && /^v\d+$/.test(part)))
+    .join("/");
+  return subdir ? `${subdir}/${version}` : version;
+}
+
+/**
+ * Resolve a module version, `latest` included, with the proxy's `.info`
+ * endpoint.
+ */
+export async function resolveModuleVersion(
+  spec: ModuleSpec,
+  options: DownloadOptions = {},
+): Promise<string> {
+  const base = `${goProxyUrl(options.env)}/${escapeModulePath(spec.path)}`;
+  const infoUrl =
+    spec.version === "latest"
+      ? `${base}/@latest`
+      : `${base}/@v/${escapeModulePath(spec.version)}.info`;
+  const info = (await (await proxyRequest(infoUrl, spec, options)).json()) as { Version: string };
+  return info.Version;
+}
+
+/**
+ * Download a module to the cache, unless it is there already, and return
+ * where its files are.
+ */
+export async function downloadModule(
+  spec: ModuleSpec,
+  options: DownloadOptions = {},
+): Promise<DownloadedModule> {
+  const base = `${goProxyUrl(options.env)}/${escapeModulePath(spec.path)}`;
+  const version = await resolveModuleVersion(spec, options);
+
+  const cacheDir = options.cacheDir ?? defaultModuleCache;
+  const dir = join(cacheDir, `${escapeModulePath(spec.path)}@${escapeModulePath(version)}`);
+  if (existsSync(dir)) {
+    return { path: spec.path, version, dir, cached: true };
+  }
+
+  const zipUrl = `${base}/@v/${escapeModulePath(version)}.zip`;
+  const zip = Buffer.from(await (await proxyRequest(zipUrl, spec, options)).arrayBuffer());
+  await mkdir(cacheDir, { recursive: true });
+  const staging = await mkdtemp(join(cacheDir, ".download-"));
+  try {
+    const prefix = `${spec.path}@${version}/`;
+    for (const [name, data] of readZip(zip)) {
+      if (name.endsWith("/")) continue;
+      const relative = name.startsWith(prefix) ? name.slice(prefix.length) : "";
+      if (!relative || posix.normalize(relative) !== relative || relative.startsWith("../")) {
+        throw new Error(`${spec.path}@${version}: unexpected file in module zip: ${name}`);
+      }
+      const file = join(staging, relative);
+      await mkdir(dirname(file), { recursive: true });
+      await writeFile(file, data);
+    }
+    await mkdir(dirname(dir), { recursive: true });
+    await rename(staging, dir);
+  } catch (error) {
+    await rm(staging, { recursive: true, force: true });
+    throw error;
+  }
+  return { path: spec.path, version, dir, cached: false };
+}
+
+/**
+ * Get a proxy URL of a module, failing on errors and on modules the proxy
+ * doesn't have.
+ */
+async function proxyRequest(
+  url: string,
+  spec: ModuleSpec,
+  options: DownloadOptions,
+): Promise<Response> {
+  const response = await (options.fetch ?? fetch)(url);
+  if (response.status === 404 || response.status === 410) {
+    throw new Error(`${spec.path}@${spec.version} not found on ${goProxyUrl(options.env)}`);
+  }
+  if (!response.ok) {
+    throw new Error(`${url}: ${response.status} ${response.statusText}`);
+  }
+  return response;
+}
+
+/**
+ * Read the entries of a zip archive, by name. Supports stored and deflated
+ * entries, without zip64.
+ */
+export function readZip(zip: Buffer): Map<string, Buffer> {
+  // The end of central directory record is at most 64 KiB + 22 bytes from the end
+  let end = -1;
+  for (let i = zip.length - 22; i >= Math.max(0, zip.length - 65557); i--) {
+    if (zip.readUInt32LE(i) === 0x06054b50) {
+      end = i;
+      break;
+    }
+  }
+  if (end < 0) throw new Error("Invalid zip: no end of central directory");
+
+  const entries = new Map<string, Buffer>();
+  const count = zip.readUInt16LE(end + 10);
+  let offset = zip.readUInt32LE(end + 16);
+  for (let i = 0; i < count; i++) {
+    if (zip.readUInt32LE(offset) !== 0x02014b50) {
+      throw new Error("Invalid zip: bad central directory entry");
+    }
+    const method = zip.readUInt16LE(offset + 10);
+    const crc = zip.readUInt32LE(offset + 16);
+    const compressedSize = zip.readUInt32LE(offset + 20);
+    const nameLength = zip.readUInt16LE(offset + 28);
+    const extraLength = zip.readUInt16LE(offset + 30);
+    const commentLength = zip.readUInt16LE(offset + 32);
+    const localOffset = zip.readUInt32LE(offset + 42);
+    const name = zip.toString("utf-8", offset + 46, offset + 46 + nameLength);
+    if (compressedSize === 0xffffffff || localOffset === 0xffffffff) {
+      throw new Error(`Unsupported zip64 entry: ${name}`);
+    }
+
+    const dataOffset =
+      localOffset + 30 + zip.readUInt16LE(localOffset + 26) + zip.readUInt16LE(localOffset + 28);
+    const raw = zip.subarray(dataOffset, dataOffset + compressedSize);
+    let data: Buffer;
+    if (method === 0) {
+      data = raw;
+    } else if (method === 8) {
+      data = inflateRawSync(raw);
+    } else {
+      throw new Error(`Unsupported zip compression method ${method}: ${name}`);
+    }
+    if (crc32(data) !== crc) {
+      throw new Error(`Invalid zip: checksum mismatch in ${name}`);
+    }
+    entries.set(name, data);
+    offset += 46 + nameLength + extraLength + commentLength;
+  }
+  return entries;
+}
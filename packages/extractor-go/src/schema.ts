@@ -0,0 +1,525 @@
+/**
+ * Output Schema
+ *
+ * A versioned JSON Schema of the extractor output, and the validator that
+ * checks outputs against it before they are written. Downstream tooling can
+ * verify output files with `extract-go validate` or with any JSON Schema
+ * (2020-12) validator, using the schema `extract-go schema` prints.
+ */
+
+/**
+ * Version of the output schema. Bumped on changes that old consumers can't
+ * read: removed or renamed fields, or changed field types.
+ */
+export const outputSchemaVersion = 1;
+
+/**
+ * A JSON Schema, limited to the keywords the validator supports.
+ */
+export interface JsonSchema {
+  $schema?: string;
+  $id?: string;
+  $ref?: string;
+  $defs?: Record<string, JsonSchema>;
+  title?: string;
+  description?: string;
+  type?: JsonType | JsonType[];
+  enum?: unknown[];
+  const?: unknown;
+  properties?: Record<string, JsonSchema>;
+  required?: string[];
+  additionalProperties?: boolean | JsonSchema;
+  items?: JsonSchema;
+  minimum?: number;
+  anyOf?: JsonSchema[];
+  if?: JsonSchema;
+  then?: JsonSchema;
+  else?: JsonSchema;
+}
+
+type JsonType = "object" | "array" | "string" | "number" | "integer" | "boolean" | "null";
+
+/**
+ * A value that doesn't match the schema.
+ */
+export interface SchemaError {
+  /** JSON Pointer of the value, e.g. "/symbols/3/kind" */
+  path: string;
+  message: string;
+}
+
+/**
+ * Symbol kinds of the IR.
+ */
+const symbolKinds = [
+  "module",
+  "class",
+  "function",
+  "method",
+  "property",
+  "attribute",
+  "interface",
+  "typeAlias",
+  "enum",
+  "enumMember",
+  "variable",
+  "namespace",
+  "constructor",
+  "parameter",
+];
+
+const ref = (name: string): JsonSchema => ({ $ref: `#/$defs/${name}` });
+const string: JsonSchema = { type: "string" };
+const boolean: JsonSchema = { type: "boolean" };
+const strings: JsonSchema = { type: "array", items: string };
+const arrayOf = (name: string): JsonSchema => ({ type: "array", items: ref(name) });
+const count: JsonSchema = { type: "integer", minimum: 0 };
+
+/**
+ * The JSON Schema of the extractor output: the full document, or the
+ * summary or signatures profile's projection of it, told apart by
+ * `capabilities`, which only the full document has. Symbols may carry
+ * Go-specific fields beyond the ones listed.
+ */
+export const outputSchema: JsonSchema = {
+  $schema: "https://json-schema.org/draft/2020-12/schema",
+  $id: `urn:langchain:extractor-go:output:${outputSchemaVersion}`,
+  title: "Go extractor output",
+  type: "object",
+  if: { required: ["capabilities"] },
+  then: ref("fullOutput"),
+  else: ref("summaryOutput"),
+  $defs: {
+    fullOutput: {
+      type: "object",
+      required: ["package", "symbols"],
+      properties: {
+        package: ref("package"),
+        provenance: ref("provenance"),
+        labels: ref("labels"),
+        capabilities: {
+          type: "object",
+          additionalProperties: {
+            type: "object",
+            required: ["enabled", "applied"],
+            properties: { enabled: boolean, applied: boolean },
+            additionalProperties: false,
+          },
+        },
+        coverage: ref("coverage"),
+        symbols: arrayOf("symbol"),
+      },
+      additionalProperties: false,
+    },
+    summaryOutput: {
+      type: "object",
+      required: ["package", "symbols"],
+      properties: {
+        package: ref("package"),
+        provenance: ref("provenance"),
+        labels: ref("labels"),
+        symbols: arrayOf("summarySymbol"),
+      },
+      additionalProperties: false,
+    },
+    package: {
+      type: "object",
+      required: [
+        "packageId",
+        "displayName",
+        "publishedName",
+        "language",
+        "ecosystem",
+        "version",
+        "repo",
+      ],
+      properties: {
+        packageId: string,
+        displayName: string,
+        publishedName: string,
+        language: { const: "go" },
+        ecosystem: { const: "go" },
+        version: string,
+        modulePath: string,
+        repo: {
+          type: "object",
+          required: ["owner", "name", "sha", "path"],
+          properties: { owner: string, name: string, sha: string, path: string },
+          additionalProperties: false,
+        },
+        synopsis: string,
+        overview: string,
+        overviewBlocks: arrayOf("docBlock"),
+        owners: strings,
+        duplicates: {
+          type: "array",
+          items: {
+            type: "object",
+            required: ["canonical", "aliases", "contentHash"],
+            properties: { canonical: string, aliases: strings, contentHash: string },
+          },
+        },
+        examples: arrayOf("example"),
+        notes: {
+          type: "object",
+          additionalProperties: {
+            type: "array",
+            items: {
+              type: "object",
+              required: ["uid", "body", "source"],
+              properties: { uid: string, body: string, source: ref("source") },
+            },
+          },
+        },
+        categories: {
+          type: "array",
+          items: {
+            type: "object",
+            required: ["name", "symbolIds"],
+            properties: { name: string, symbolIds: strings },
+            additionalProperties: false,
+          },
+        },
+        errors: {
+          type: "array",
+          items: {
+            type: "object",
+            required: ["kind", "package", "message"],
+            properties: {
+              kind: { enum: ["parse", "package", "import", "timeout", "memory"] },
+              package: string,
+              file: string,
+              message: string,
+            },
+            additionalProperties: false,
+          },
+        },
+      },
+      additionalProperties: false,
+    },
+    provenance: {
+      type: "object",
+      required: ["extractor", "schemaVersion", "module", "goos", "goarch", "timestamp"],
+      properties: {
+        extractor: {
+          type: "object",
+          required: ["name", "version"],
+          properties: { name: string, version: string },
+          additionalProperties: false,
+        },
+        schemaVersion: count,
+        module: {
+          type: "object",
+          required: ["path", "version"],
+          properties: { path: string, version: string },
+          additionalProperties: false,
+        },
+        vcs: {
+          type: "object",
+          required: ["system", "revision", "modified"],
+          properties: { system: { const: "git" }, revision: string, modified: boolean },
+          additionalProperties: false,
+        },
+        goos: string,
+        goarch: string,
+        timestamp: string,
+      },
+      additionalProperties: false,
+    },
+    labels: {
+      type: "object",
+      required: ["locale"],
+      properties: {
+        locale: string,
+        labels: { type: "object", additionalProperties: string },
+        bundleUrl: string,
+      },
+      additionalProperties: false,
+    },
+    coverageCount: {
+      type: "object",
+      required: ["documented", "total", "percent"],
+      properties: {
+        documented: count,
+        total: count,
+        percent: { type: "number", minimum: 0 },
+        kinds: { type: "object", additionalProperties: ref("coverageCount") },
+        package: string,
+        undocumented: strings,
+      },
+    },
+    coverage: {
+      type: "object",
+      required: ["documented", "total", "percent", "kinds", "packages"],
+      properties: {
+        kinds: { type: "object", additionalProperties: ref("coverageCount") },
+        packages: arrayOf("coverageCount"),
+      },
+    },
+    summarySymbol: {
+      type: "object",
+      required: ["id", "name", "qualifiedName", "kind"],
+      properties: {
+        id: string,
+        name: string,
+        qualifiedName: string,
+        kind: { enum: symbolKinds },
+        summary: string,
+        description: string,
+        signature: string,
+      },
+      additionalProperties: false,
+    },
+    symbol: {
+      type: "object",
+      required: [
+        "id",
+        "packageId",
+        "language",
+        "kind",
+        "name",
+        "qualifiedName",
+        "display",
+        "signature",
+        "docs",
+        "source",
+        "urls",
+        "tags",
+      ],
+      properties: {
+        id: string,
+        packageId: string,
+        language: { const: "go" },
+        kind: { enum: symbolKinds },
+        name: string,
+        qualifiedName: string,
+        display: {
+          type: "object",
+          required: ["name", "qualified"],
+          properties: { name: string, qualified: string },
+        },
+        signature: string,
+        docs: ref("docs"),
+        params: {
+          type: "array",
+          items: {
+            type: "object",
+            required: ["name", "type", "required"],
+            properties: { name: string, type: string, required: boolean },
+          },
+        },
+        returns: { type: "object", required: ["type"], properties: { type: string } },
+        members: {
+          type: "array",
+          items: {
+            type: "object",
+            required: ["name", "refId", "kind", "visibility"],
+            properties: {
+              name: string,
+              refId: string,
+              kind: { enum: symbolKinds },
+              visibility: ref("visibility"),
+            },
+          },
+        },
+        source: ref("source"),
+        urls: { type: "object", required: ["canonical"], properties: { canonical: string } },
+        tags: {
+          type: "object",
+          required: ["stability", "visibility"],
+          properties: {
+            stability: { enum: ["experimental", "beta", "stable", "deprecated"] },
+            visibility: ref("visibility"),
+          },
+        },
+        category: string,
+        related: {
+          type: "array",
+          items: {
+            type: "object",
+            required: ["name"],
+            properties: { name: string, refId: string, url: string },
+          },
+        },
+      },
+    },
+    visibility: { enum: ["public", "protected", "private"] },
+    source: {
+      type: "object",
+      required: ["repo", "sha", "path", "line"],
+      properties: {
+        repo: string,
+        sha: string,
+        path: string,
+        line: count,
+        endLine: count,
+      },
+    },
+    docs: {
+      type: "object",
+      required: ["summary"],
+      properties: {
+        summary: string,
+        description: string,
+        blocks: arrayOf("docBlock"),
+        deprecated: {
+          type: "object",
+          required: ["isDeprecated"],
+          properties: { isDeprecated: { const: true }, message: string },
+        },
+        examples: arrayOf("example"),
+        annotations: { type: "object", additionalProperties: { anyOf: [string, { const: true }] } },
+        markdown: string,
+        html: string,
+        truncated: boolean,
+        fullDocsUrl: string,
+        sidecar: {
+          type: "object",
+          required: ["path", "markdown"],
+          properties: { path: string, markdown: string },
+          additionalProperties: false,
+        },
+      },
+    },
+    example: {
+      type: "object",
+      required: ["name", "code"],
+      properties: {
+        name: string,
+        title: string,
+        description: string,
+        code: string,
+        language: string,
+        output: string,
+        unordered: boolean,
+        wholeFile: boolean,
+        source: ref("source"),
+      },
+    },
+    docBlock: {
+      type: "object",
+      required: ["kind"],
+      properties: {
+        kind: { enum: ["paragraph", "heading", "list", "code", "verbatim", "callout"] },
+        text: { anyOf: [string, arrayOf("docText")] },
+        ordered: boolean,
+        items: {
+          type: "array",
+          items: {
+            type: "object",
+            required: ["text"],
+            properties: { number: string, text: arrayOf("docText") },
+            additionalProperties: false,
+          },
+        },
+        language: string,
+        callout: { enum: ["security", "concurrency"] },
+      },
+      additionalProperties: false,
+    },
+    docText: {
+      type: "object",
+      required: ["kind", "text"],
+      properties: {
+        kind: { enum: ["plain", "link", "docLink"] },
+        text: string,
+        url: string,
+        target: string,
+        refId: string,
+      },
+      additionalProperties: false,
+    },
+  },
+};
+
+/**
+ * Validate an output document against the output schema.
+ */
+export function validateOutput(output: unknown): SchemaError[] {
+  return validateJson(output, outputSchema);
+}
+
+/**
+ * Validate a JSON value against a schema, resolving `$ref`s against the
+ * `$defs` of the root schema.
+ */
+export function validateJson(
+  value: unknown,
+  schema: JsonSchema,
+  root: JsonSchema = schema,
+  path = "",
+): SchemaError[] {
+  if (schema.$ref) {
+    const name = schema.$ref.replace(/^#\/\$defs\//, "");
+    const target = root.$defs?.[name];
+    if (!target) throw new Error(`Unknown schema reference: ${schema.$ref}`);
+    return validateJson(value, target, root, path);
+  }
+
+  const error = (message: string): SchemaError[] => [{ path: path || "/", message }];
+  if (schema.type) {
+    const types = Array.isArray(schema.type) ? schema.type : [schema.type];
+    if (!types.some((type) => hasType(value, type))) {
+      return error(`expected ${types.join(" or ")}, got ${typeOf(value)}`);
+    }
+  }
+  if (schema.const !== undefined && value !== schema.const) {
+    return error(`expected ${JSON.stringify(schema.const)}`);
+  }
+  if (schema.enum && !schema.enum.includes(value)) {
+    return error(`expected one of ${schema.enum.map((v) => JSON.stringify(v)).join(", ")}`);
+  }
+  if (schema.minimum !== undefined && typeof value === "number" && value < schema.minimum) {
+    return error(`expected at least ${schema.minimum}`);
+  }
+  if (schema.anyOf) {
+    const matches = schema.anyOf.some((option) => !validateJson(value, option, root, path).length);
+    if (!matches) return error("matches none of the allowed schemas");
+  }
+  if (schema.if) {
+    const branch = validateJson(value, schema.if, root, path).length ? schema.else : schema.then;
+    if (branch) return validateJson(value, branch, root, path);
+  }
+
+  const errors: SchemaError[] = [];
+  if (Array.isArray(value) && schema.items) {
+    for (const [index, item] of value.entries()) {
+      errors.push(...validateJson(item, schema.items, root, `${path}/${index}`));
+    }
+  }
+  if (typeOf(value) === "object") {
+    const object = value as Record<string, unknown>;
+    for (const key of schema.required ?? []) {
+      if (object[key] === undefined) errors.push(...error(`missing required property "${key}"`));
+    }
+    for (const [key, item] of Object.entries(object)) {
+      if (item === undefined) continue;
+      const property = schema.properties?.[key] ?? schema.additionalProperties;
+      const itemPath = `${path}/${key.replace(/~/g, "~0").replace(/\//g, "~1")}`;
+      if (property === false) {
+        errors.push({ path: itemPath, message: "unexpected property" });
+      } else if (property && property !== true) {
+        errors.push(...validateJson(item, property, root, itemPath));
+      }
+    }
+  }
+  return errors;
+}
+
+/**
+ * Format schema errors one per line, as `path: message`.
+ */
+export function formatSchemaErrors(errors: SchemaError[]): string {
+  return errors.map((error) => `${error.path}: ${error.message}`).join("\n");
+}
+
+function typeOf(value: unknown): string {
+  if (value === null) return "null";
+  if (Array.isArray(value)) return "array";
+  return typeof value;
+}
+
+function hasType(value: unknown, type: JsonType): boolean {
+  if (type === "integer") return Number.isInteger(value);
+  return typeOf(value) === type;
+}
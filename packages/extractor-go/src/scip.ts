@@ -0,0 +1,260 @@
+/**
+ * SCIP Index
+ *
+ * Encodes the exported API as a SCIP index (https://github.com/sourcegraph/scip)
+ * so code-intelligence hovers and the docs site share one extraction pass:
+ * a document per source file with the definition occurrence of each symbol
+ * and its documentation, signature, kind, and implemented interfaces.
+ * Symbols are named the way scip-go names them, e.g.
+ * "scip-go gomod github.com/org/mod v1.2.0 `github.com/org/mod/sub`/Client#Get().",
+ * so references from other SCIP indexes resolve to them.
+ *
+ * The index is written as protobuf without a protobuf dependency, using the
+ * handful of messages and fields it needs.
+ */
+
+import { readFile, writeFile } from "fs/promises";
+import { join, resolve } from "path";
+import { pathToFileURL } from "url";
+import type { ExtractorOutput } from "./output.js";
+import type { GoSymbolRecord } from "./transformer.js";
+import { ProtoWriter } from "./protobuf.js";
+
+/**
+ * SCIP symbol kinds (`SymbolInformation.Kind`) by IR symbol kind.
+ */
+const SCIP_KINDS: Record<string, number> = {
+  class: 49, // Struct
+  interface: 21, // Interface
+  typeAlias: 55, // TypeAlias
+  function: 17, // Function
+  method: 26, // Method
+  variable: 61, // Variable
+};
+
+/**
+ * `SymbolInformation.Kind` of constants, which the IR lists as variables.
+ */
+const SCIP_CONSTANT = 8;
+
+/**
+ * `SymbolRole.Definition`.
+ */
+const DEFINITION_ROLE = 1;
+
+/**
+ * `PositionEncoding.UTF16CodeUnitOffsetFromLineStart`, the offsets of JS strings.
+ */
+const UTF16_POSITIONS = 2;
+
+/**
+ * A SCIP index.
+ */
+export interface ScipIndex {
+  metadata: { toolName: string; toolVersion: string; projectRoot: string };
+  documents: ScipDocument[];
+}
+
+/**
+ * A source file of a SCIP index.
+ */
+export interface ScipDocument {
+  relativePath: string;
+  occurrences: ScipOccurrence[];
+  symbols: ScipSymbol[];
+}
+
+/**
+ * The definition of a symbol in a document.
+ */
+export interface ScipOccurrence {
+  /** `[line, startCharacter, endCharacter]`, 0-based */
+  range: [number, number, number];
+  symbol: string;
+}
+
+/**
+ * Documentation and relationships of a symbol.
+ */
+export interface ScipSymbol {
+  symbol: string;
+  kind: number;
+  displayName: string;
+  /** Markdown paragraphs */
+  documentation: string[];
+  signature: string;
+  /** Symbol of the type declaring a method */
+  enclosingSymbol?: string;
+  /** Symbols of the in-package interfaces the type implements */
+  implements: string[];
+}
+
+/**
+ * Build the SCIP index of the public API of an output. `sources` holds the
+ * contents of the source files by path, to find where each name is defined.
+ */
+export function buildScipIndex(
+  output: ExtractorOutput,
+  sources: Record<string, string>,
+): ScipIndex {
+  const pkg = output.package;
+  const symbols = (output.symbols as GoSymbolRecord[]).filter(
+    (symbol) => symbol.tags.visibility === "public" && !symbol.testHelper,
+  );
+  const scipSymbols = new Map(symbols.map((s) => [s.qualifiedName, scipSymbol(output, s)]));
+  const lines = new Map<string, string[]>();
+
+  const documents = new Map<string, ScipDocument>();
+  for (const symbol of symbols) {
+    const path = symbol.source.path;
+    if (!documents.has(path)) {
+      documents.set(path, { relativePath: path, occurrences: [], symbols: [] });
+      lines.set(path, sources[path]?.split("\n") ?? []);
+    }
+    const document = documents.get(path)!;
+    const name = scipSymbols.get(symbol.qualifiedName)!;
+
+    const line = lines.get(path)![symbol.source.line - 1];
+    const column = line === undefined ? -1 : nameColumn(line, symbol);
+    if (column >= 0) {
+      const range: ScipOccurrence["range"] = [
+        symbol.source.line - 1,
+        column,
+        column + symbol.name.length,
+      ];
+      document.occurrences.push({ range, symbol: name });
+    }
+
+    const { summary, description, deprecated } = symbol.docs;
+    const documentation = [description ?? summary].filter(Boolean);
+    if (deprecated) documentation.unshift(`**Deprecated:** ${deprecated.message ?? ""}`.trim());
+    const receiver = symbol.kind === "method" ? symbol.qualifiedName.split(".")[0] : undefined;
+    document.symbols.push({
+      symbol: name,
+      kind: symbol.signature.startsWith("const ") ? SCIP_CONSTANT : (SCIP_KINDS[symbol.kind] ?? 0),
+      displayName: symbol.name,
+      documentation,
+      signature: symbol.signature,
+      enclosingSymbol: receiver ? scipSymbols.get(receiver) : undefined,
+      implements: (symbol.relations?.implements ?? []).flatMap((iface) => {
+        const target = scipSymbols.get(iface);
+        return target ? [target] : [];
+      }),
+    });
+  }
+
+  return {
+    metadata: {
+      toolName: "extract-go",
+      toolVersion: pkg.version,
+      projectRoot: pathToFileURL(resolve(pkg.repo.path)).href,
+    },
+    documents: [...documents.values()],
+  };
+}
+
+/**
+ * Write the SCIP index of an output, reading its source files from the
+ * package path.
+ */
+export async function writeScip(output: ExtractorOutput, file: string): Promise<void> {
+  const sources: Record<string, string> = {};
+  for (const path of new Set(output.symbols.map((symbol) => symbol.source.path))) {
+    sources[path] = await readFile(join(output.package.repo.path, path), "utf-8").catch(() => "");
+  }
+  await writeFile(file, encodeScipIndex(buildScipIndex(output, sources)));
+}
+
+/**
+ * Name a symbol as scip-go does: the module and its version, the import
+ * path of the symbol's package as a namespace, then type, term, or method
+ * descriptors.
+ */
+function scipSymbol(output: ExtractorOutput, symbol: GoSymbolRecord): string {
+  const pkg = output.package;
+  const module = pkg.modulePath || pkg.publishedName;
+  const dir = symbol.source.path.split("/").slice(0, -1).join("/");
+  const importPath = dir ? `${module}/${dir}` : module;
+  const prefix = `scip-go gomod ${escapePackage(module)} ${escapePackage(pkg.version)} `;
+  const namespace = `${escapeName(importPath)}/`;
+
+  const [owner, member] = symbol.qualifiedName.split(".");
+  switch (symbol.kind) {
+    case "method":
+      return `${prefix}${namespace}${escapeName(owner)}#${escapeName(member)}().`;
+    case "function":
+      return `${prefix}${namespace}${escapeName(owner)}().`;
+    case "variable":
+      return `${prefix}${namespace}${escapeName(owner)}.`;
+    default:
+      return `${prefix}${namespace}${escapeName(owner)}#`;
+  }
+}
+
+/**
+ * Escape a descriptor name: names of other than identifier characters are
+ * wrapped in backticks.
+ */
+function escapeName(name: string): string {
+  return /^[\w+$-]+$/.test(name) ? name : `\`${name.replace(/`/g, "``")}\``;
+}
+
+/**
+ * Escape a package component, whose spaces are doubled.
+ */
+function escapePackage(value: string): string {
+  return value ? value.replace(/ /g, "  ") : ".";
+}
+
+/**
+ * Find the column of a symbol's name on its declaration line, skipping a
+ * method's receiver.
+ */
+function nameColumn(line: string, symbol: GoSymbolRecord): number {
+  const start = symbol.kind === "method" ? line.indexOf(")") + 1 : 0;
+  const match = new RegExp(`\\b${symbol.name}\\b`).exec(line.slice(start));
+  return match ? start + match.index : -1;
+}
+
+/**
+ * Encode an index as a SCIP protobuf message.
+ */
+export function encodeScipIndex(index: ScipIndex): Uint8Array {
+  const toolInfo = new ProtoWriter()
+    .string(1, index.metadata.toolName)
+    .string(2, index.metadata.toolVersion);
+  const metadata = new ProtoWriter()
+    .message(2, toolInfo)
+    .string(3, index.metadata.projectRoot)
+    .varint(4, 1); // TextEncoding.UTF8
+
+  const writer = new ProtoWriter().message(1, metadata);
+  for (const document of index.documents) {
+    const doc = new ProtoWriter().string(1, document.relativePath);
+    for (const occurrence of document.occurrences) {
+      doc.message(
+        2,
+        new ProtoWriter()
+          .packed(1, occurrence.range)
+          .string(2, occurrence.symbol)
+          .varint(3, DEFINITION_ROLE),
+      );
+    }
+    for (const symbol of document.symbols) {
+      const info = new ProtoWriter().string(1, symbol.symbol);
+      for (const paragraph of symbol.documentation) info.string(3, paragraph);
+      for (const target of symbol.implements) {
+        info.message(4, new ProtoWriter().string(1, target).varint(3, 1));
+      }
+      info
+        .varint(5, symbol.kind)
+        .string(6, symbol.displayName)
+        .message(7, new ProtoWriter().string(4, "go").string(5, symbol.signature));
+      if (symbol.enclosingSymbol) info.string(8, symbol.enclosingSymbol);
+      doc.message(3, info);
+    }
+    doc.string(4, "go").varint(6, UTF16_POSITIONS);
+    writer.message(2, doc);
+  }
+  return writer.finish();
+}
@@ -0,0 +1,100 @@
+/**
+ * Search Index Records
+ *
+ * Flattens an extraction into search documents (the IR `SearchRecord`): the
+ * symbol's title, kind, excerpt, package, URL, breadcrumbs, and keywords to
+ * boost. Adapters serialize the records for a search engine, so the docs
+ * site's search ingests Go references without custom glue code.
+ */
+
+import type { SearchRecord } from "@langchain/ir-schema";
+import type { ExtractorOutput } from "./output.js";
+import type { GoSymbolRecord } from "./transformer.js";
+
+/**
+ * Maximum length of a search excerpt.
+ */
+const EXCERPT_LENGTH = 150;
+
+/**
+ * Serializes search records for a search engine.
+ */
+export interface SearchIndexAdapter {
+  /** Render the records as the contents of an import file */
+  serialize(records: SearchRecord[]): string;
+}
+
+/**
+ * Search index adapters by name. Add an entry to support another engine.
+ *
+ * - `records`: a JSON array of IR search records
+ * - `algolia`: a JSON array of Algolia objects, keyed by `objectID`
+ * - `typesense`: JSONL documents for Typesense's import endpoint
+ */
+export const searchIndexAdapters: Record<string, SearchIndexAdapter> = {
+  records: {
+    serialize: (records) => JSON.stringify(records, null, 2),
+  },
+  algolia: {
+    serialize: (records) =>
+      JSON.stringify(
+        records.map((record) => ({ objectID: record.id, ...record })),
+        null,
+        2,
+      ),
+  },
+  typesense: {
+    serialize: (records) => records.map((record) => `${JSON.stringify(record)}\n`).join(""),
+  },
+};
+
+/**
+ * Build the search records of the public API of an output: a record per
+ * type, function, method, constant, and variable. Test helpers aren't
+ * indexed.
+ */
+export function searchRecords(output: ExtractorOutput): SearchRecord[] {
+  const pkg = output.package;
+  return (output.symbols as GoSymbolRecord[])
+    .filter((symbol) => symbol.tags.visibility === "public" && !symbol.testHelper)
+    .map((symbol) => {
+      const path = symbol.qualifiedName.split(".");
+      return {
+        id: symbol.id,
+        url: symbol.urls.canonical,
+        title: symbol.qualifiedName,
+        breadcrumbs: [pkg.displayName, ...path.slice(0, -1)],
+        excerpt: excerpt(symbol.docs.summary),
+        keywords: keywords(symbol, pkg.displayName),
+        kind: symbol.kind,
+        language: "go",
+        packageId: symbol.packageId,
+      };
+    });
+}
+
+/**
+ * Keywords to boost a symbol by: its name and the words of its camel-cased
+ * name, its package-qualified name as Go code refers to it, and its category.
+ */
+function keywords(symbol: GoSymbolRecord, packageName: string): string[] {
+  const words = symbol.name.match(/[A-Z]+(?![a-z])|[A-Z]?[a-z]+|\d+/g) ?? [];
+  const all = [
+    symbol.name,
+    `${packageName}.${symbol.qualifiedName}`,
+    ...words.map((word) => word.toLowerCase()),
+    symbol.category,
+    symbol.docs.deprecated ? "deprecated" : undefined,
+  ];
+  return [...new Set(all.filter((keyword): keyword is string => Boolean(keyword)))];
+}
+
+/**
+ * Shorten a summary to an excerpt, cutting at a word boundary.
+ */
+function excerpt(summary: string): string {
+  if (summary.length <= EXCERPT_LENGTH) return summary;
+  const cut = summary.slice(0, EXCERPT_LENGTH - 1);
+  const space = cut.lastIndexOf(" ");
+  return `${space > 0 ? cut.slice(0, space) : cut}…`;
+}
@@ -0,0 +1,371 @@
+/**
+ * Extraction Server
+ *
+ * A long-running HTTP server that extracts modules on demand, so the docs
+ * backend can request references instead of shelling out per build:
+ *
+ * - `POST /extract` with `{ "module": "<import path>", "version"?, "profile"? }`
+ *   extracts a module version (latest by default) and returns the output
+ *   document
+ * - `GET /symbol/{id}` returns a symbol of a module extracted so far, from
+ *   the most recently requested one that has it; `?module=` and `?version=`
+ *   narrow the search
+ *
+ * Extractions are kept in an LRU cache of module versions, which the gRPC
+ * service shares. Concurrent requests for the same version share one
+ * extraction, and failed extractions aren't cached. `latest` is resolved to
+ * the version it names, which is trusted for a few minutes, so it shares
+ * that version's extraction and picks up new releases.
+ */
+
+import { createServer, type IncomingMessage, type Server } from "http";
+import type { SymbolRecord } from "@langchain/ir-schema";
+import {
+  extractionProfiles,
+  type ExtractionProfile,
+  type ProfiledOutput,
+  type SummarySymbol,
+} from "./profile.js";
+import { parseModuleSpec } from "./remote.js";
+
+/**
+ * Default number of module versions the server keeps.
+ */
+export const defaultServeCacheSize = 32;
+
+/**
+ * Default time a module's latest version is trusted, in milliseconds.
+ */
+export const defaultLatestTtlMs = 5 * 60 * 1000;
+
+/**
+ * Largest request body accepted, in bytes.
+ */
+const MAX_BODY_BYTES = 64 * 1024;
+
+const SYMBOL_ROUTE = /^\/symbol\/([^/]+)\/?$/;
+
+/**
+ * Body of a `POST /extract` request.
+ */
+export interface ServeExtractRequest {
+  /** Module import path, e.g. "github.com/tmc/langchaingo" */
+  module: string;
+  /** A version, or "latest" (the default) */
+  version?: string;
+  profile?: ExtractionProfile;
+}
+
+/**
+ * An extraction of a module version.
+ */
+export interface ServedExtraction {
+  module: string;
+  /** The resolved version */
+  version: string;
+  output: ProfiledOutput;
+}
+
+/**
+ * Extracts a module version, e.g. by downloading it through the Go proxy.
+ */
+export type ModuleExtractor = (
+  request: Required<ServeExtractRequest>,
+) => Promise<ServedExtraction>;
+
+/**
+ * Resolves the latest version of a module, e.g. with the Go proxy's
+ * `@latest` endpoint.
+ */
+export type LatestResolver = (module: string) => Promise<string>;
+
+/**
+ * Options of an extraction cache.
+ */
+export interface ExtractionCacheOptions {
+  /**
+   * Resolves `latest` before extracting. Without it, `latest` extractions
+   * are kept apart and only for the TTL
+   */
+  resolveLatest?: LatestResolver;
+  /** How long a module's latest version is trusted (default: 5 minutes) */
+  latestTtlMs?: number;
+  /** Called with the extractions the cache drops to make room */
+  onEvict?: (extraction: ServedExtraction) => void;
+}
+
+/**
+ * A map that keeps the most recently used entries up to a capacity.
+ */
+export class LruCache<K, V> {
+  private entries = new Map<K, V>();
+  private capacity: number;
+  private onEvict?: (value: V, key: K) => void;
+
+  constructor(capacity: number, onEvict?: (value: V, key: K) => void) {
+    if (!(capacity >= 1)) {
+      throw new Error(`Invalid cache size: ${capacity}`);
+    }
+    this.capacity = capacity;
+    this.onEvict = onEvict;
+  }
+
+  /**
+   * Get an entry, marking it as the most recently used.
+   */
+  get(key: K): V | undefined {
+    const value = this.entries.get(key);
+    if (value !== undefined) {
+      this.entries.delete(key);
+      this.entries.set(key, value);
+    }
+    return value;
+  }
+
+  /**
+   * Store an entry, dropping the least recently used one when full.
+   */
+  set(key: K, value: V): void {
+    this.entries.delete(key);
+    this.entries.set(key, value);
+    if (this.entries.size > this.capacity) {
+      const [oldest, evicted] = this.entries.entries().next().value!;
+      this.entries.delete(oldest);
+      this.onEvict?.(evicted, oldest);
+    }
+  }
+
+  /**
+   * Drop an entry.
+   */
+  delete(key: K): void {
+    this.entries.delete(key);
+  }
+
+  /**
+   * The entries, most recently used first.
+   */
+  values(): V[] {
+    return [...this.entries.values()].reverse();
+  }
+
+  get size(): number {
+    return this.entries.size;
+  }
+}
+
+/**
+ * A symbol of any profile.
+ */
+export type ServedSymbol = SymbolRecord | SummarySymbol;
+
+/**
+ * A cached extraction: pending, or done with its symbols indexed by ID.
+ */
+interface CacheEntry {
+  pending: Promise<ServedExtraction>;
+  done?: ServedExtraction & { symbols: Map<string, ServedSymbol> };
+  /** When an unresolved `latest` extraction goes stale */
+  expires?: number;
+}
+
+/**
+ * Extractions of module versions, kept in an LRU cache. Concurrent requests
+ * for a version share one extraction; failed ones are dropped.
+ */
+export class ExtractionCache {
+  private entries: LruCache<string, CacheEntry>;
+  private extractModule: ModuleExtractor;
+  private options: ExtractionCacheOptions;
+  /** Latest versions of modules, and until when they're trusted */
+  private latest = new Map<string, { version: Promise<string>; expires: number }>();
+
+  constructor(
+    extract: ModuleExtractor,
+    size = defaultServeCacheSize,
+    options: ExtractionCacheOptions = {},
+  ) {
+    this.extractModule = extract;
+    this.options = options;
+    this.entries = new LruCache(size, (entry) => {
+      entry.pending.then(
+        (extraction) => options.onEvict?.(extraction),
+        () => {},
+      );
+    });
+  }
+
+  /**
+   * Extract a module version, or reuse its extraction.
+   */
+  async extract(request: Required<ServeExtractRequest>): Promise<ServedExtraction> {
+    return (await this.entry(request)).pending;
+  }
+
+  /**
+   * Get a symbol of a module version by ID, extracting it if needed.
+   */
+  async symbol(
+    request: Required<ServeExtractRequest>,
+    id: string,
+  ): Promise<ServedSymbol | undefined> {
+    const entry = await this.entry(request);
+    await entry.pending;
+    return entry.done?.symbols.get(id);
+  }
+
+  /**
+   * Find a symbol among the finished extractions, most recently used first,
+   * optionally of a module or a resolved version only.
+   */
+  findSymbol(
+    id: string,
+    filter: { module?: string; version?: string } = {},
+  ): ServedSymbol | undefined {
+    for (const { done } of this.entries.values()) {
+      if (!done || (filter.module && done.module !== filter.module)) continue;
+      if (filter.version && done.version !== filter.version) continue;
+      const symbol = done.symbols.get(id);
+      if (symbol) return symbol;
+    }
+    return undefined;
+  }
+
+  private async entry(request: Required<ServeExtractRequest>): Promise<CacheEntry> {
+    const version = await this.resolve(request);
+    const key = `${request.module}@${version}#${request.profile}`;
+    const cached = this.entries.get(key);
+    if (cached && (cached.expires ?? Infinity) > Date.now()) return cached;
+
+    const entry: CacheEntry = {
+      pending: this.extractModule({ ...request, version }).then(
+        (extraction) => {
+          const symbols = new Map<string, ServedSymbol>();
+          for (const symbol of extraction.output.symbols) {
+            symbols.set(symbol.id, symbol);
+          }
+          entry.done = { ...extraction, symbols };
+          return extraction;
+        },
+        (error) => {
+          if (this.entries.get(key) === entry) this.entries.delete(key);
+          throw error;
+        },
+      ),
+      expires: version === "latest" ? Date.now() + this.latestTtlMs : undefined,
+    };
+    this.entries.set(key, entry);
+    return entry;
+  }
+
+  /**
+   * The version a request is for: `latest` resolved, while its resolution
+   * is trusted, if there's a resolver.
+   */
+  private async resolve(request: Required<ServeExtractRequest>): Promise<string> {
+    const { module, version } = request;
+    const { resolveLatest } = this.options;
+    if (version !== "latest" || !resolveLatest) return version;
+
+    const cached = this.latest.get(module);
+    if (cached && cached.expires > Date.now()) return cached.version;
+    const latest = { version: resolveLatest(module), expires: Date.now() + this.latestTtlMs };
+    this.latest.set(module, latest);
+    latest.version.catch(() => {
+      if (this.latest.get(module) === latest) this.latest.delete(module);
+    });
+    return latest.version;
+  }
+
+  private get latestTtlMs(): number {
+    return this.options.latestTtlMs ?? defaultLatestTtlMs;
+  }
+}
+
+/**
+ * Check the body of a `POST /extract` request and fill in its defaults.
+ * Module paths and versions follow the rules of `module <path>@<version>`
+ * sources.
+ */
+export function parseExtractRequest(body: unknown): Required<ServeExtractRequest> {
+  const request = body as Partial<ServeExtractRequest> | null;
+  if (typeof request?.module !== "string" || !request.module) {
+    throw new Error("module is required");
+  }
+  const version = request.version || "latest";
+  if (typeof version !== "string") {
+    throw new Error("version must be a string");
+  }
+  // Module paths and versions end up in proxy URLs and cache paths
+  parseModuleSpec(`${request.module}@${version}`);
+  const profile = request.profile || "full";
+  if (!extractionProfiles.includes(profile)) {
+    throw new Error(`Unknown profile: ${profile}`);
+  }
+  return { module: request.module, version, profile };
+}
+
+/**
+ * Create an HTTP server answering `POST /extract` and `GET /symbol/{id}`
+ * from an extraction cache.
+ */
+export function createExtractionServer(cache: ExtractionCache): Server {
+  return createServer(async (request, response) => {
+    const send = (status: number, body: unknown) => {
+      response.writeHead(status, { "Content-Type": "application/json" });
+      response.end(JSON.stringify(body));
+    };
+
+    const url = new URL(request.url ?? "/", "http://localhost");
+    const symbolMatch = SYMBOL_ROUTE.exec(url.pathname);
+    if (url.pathname !== "/extract" && !symbolMatch) {
+      return send(404, { error: "Not found" });
+    }
+    const method = symbolMatch ? "GET" : "POST";
+    if (request.method !== method) {
+      return send(405, { error: `Method not allowed: ${request.method}` });
+    }
+
+    if (symbolMatch) {
+      let id: string;
+      try {
+        id = decodeURIComponent(symbolMatch[1]);
+      } catch {
+        return send(400, { error: `Malformed symbol id: ${symbolMatch[1]}` });
+      }
+      const symbol = cache.findSymbol(id, {
+        module: url.searchParams.get("module") ?? undefined,
+        version: url.searchParams.get("version") ?? undefined,
+      });
+      return symbol ? send(200, symbol) : send(404, { error: `Unknown symbol: ${id}` });
+    }
+
+    let extractRequest: Required<ServeExtractRequest>;
+    try {
+      extractRequest = parseExtractRequest(JSON.parse(await readBody(request)));
+    } catch (error) {
+      return send(400, { error: error instanceof Error ? error.message : String(error) });
+    }
+    try {
+      send(200, (await cache.extract(extractRequest)).output);
+    } catch (error) {
+      send(500, { error: error instanceof Error ? error.message : String(error) });
+    }
+  });
+}
+
+/**
+ * Read a request body as UTF-8, up to `MAX_BODY_BYTES`.
+ */
+async function readBody(request: IncomingMessage): Promise<string> {
+  const chunks: Buffer[] = [];
+  let size = 0;
+  for await (const chunk of request) {
+    size += (chunk as Buffer).length;
+    if (size > MAX_BODY_BYTES) {
+      throw new Error(`Request body is larger than ${MAX_BODY_BYTES} bytes`);
+    }
+    chunks.push(chunk as Buffer);
+  }
+  return Buffer.concat(chunks).toString("utf-8");
+}
@@ -0,0 +1,52 @@
+/**
+ * Sidecar Docs
+ *
+ * Loads supplementary symbol documentation from a directory of Markdown
+ * files, so long-form guides and examples that don't belong in source
+ * comments still appear on reference pages. Each file documents the symbol
+ * its name points to: `Client.md`, `Client.Get.md`, or the full symbol ID.
+ */
+
+import { readdir, readFile } from "fs/promises";
+import { basename, join, relative } from "path";
+import { logger } from "./logger.js";
+
+/**
+ * Supplementary documentation of a symbol.
+ */
+export interface SidecarDoc {
+  /** Path of the Markdown file, relative to the package path */
+  path: string;
+  markdown: string;
+}
+
+/**
+ * Load the Markdown files of a sidecar directory, including subdirectories,
+ * keyed by file name without the `.md` extension. When two files share a
+ * name, the first in path order wins.
+ */
+export async function loadSidecarDocs(
+  packagePath: string,
+  dir: string,
+): Promise<Record<string, SidecarDoc>> {
+  const root = join(packagePath, dir);
+  let entries: string[];
+  try {
+    entries = await readdir(root, { recursive: true });
+  } catch {
+    throw new Error(`Sidecar docs directory not found: ${root}`);
+  }
+
+  const docs: Record<string, SidecarDoc> = {};
+  for (const entry of entries.filter((name) => name.endsWith(".md")).sort()) {
+    const file = join(root, entry);
+    const key = basename(entry, ".md");
+    const path = relative(packagePath, file).replace(/\\/g, "/");
+    if (docs[key]) {
+      logger.warn(`${path} has the same name as ${docs[key].path}; skipping it`);
+      continue;
+    }
+    docs[key] = { path, markdown: (await readFile(file, "utf-8")).trim() };
+  }
+  return docs;
+}
@@ -0,0 +1,169 @@
+/**
+ * Incremental Updates
+ *
+ * Updates a split output for the packages changed since a git ref, for fast
+ * CI runs: git lists the Go files changed in the working tree since the ref
+ * (and untracked ones), they are mapped to their packages, which are
+ * re-extracted with the module's packages that import them (whose constants
+ * can depend on theirs), and their symbols are merged into the existing
+ * output. Only those packages and the ones they import are parsed. Package
+ * files of unchanged packages are kept as they are; the manifest's
+ * categories, capabilities, and coverage are recomputed over the merged
+ * symbols.
+ */
+
+import { execFile } from "child_process";
+import { readFile } from "fs/promises";
+import { join, posix } from "path";
+import { promisify } from "util";
+import type { GoExtractorConfig } from "./config.js";
+import { buildCapabilities } from "./capabilities.js";
+import { compareCanonical, sortSymbols } from "./canonical.js";
+import { decompress } from "./compress.js";
+import { buildCoverage } from "./coverage.js";
+import { sortExtractionErrors } from "./errors.js";
+import { groupCategories, type ExtractorOutput, type OutputNote } from "./output.js";
+import { splitManifestFile, type SplitManifest, type SplitPackage } from "./split.js";
+import type { GoSymbolRecord } from "./transformer.js";
+import { isWatchedFile } from "./watch.js";
+
+/**
+ * A split output read back from its directory.
+ */
+export interface LoadedSplitOutput {
+  manifest: SplitManifest;
+  packages: SplitPackage[];
+  /** Uncompressed contents of the files, keyed by path, as split output renders them */
+  files: Map<string, string>;
+}
+
+/**
+ * The changes since a git ref a split output is to be updated for.
+ */
+export interface SinceChanges {
+  /** The split output to update */
+  previous: LoadedSplitOutput;
+  /**
+   * Package directories to re-extract: the changed ones and the packages
+   * importing them, or undefined if every package is to be extracted
+   */
+  dirs?: string[];
+  /** Package directories to parse for them: those and the packages they import */
+  parseDirs?: string[];
+}
+
+/**
+ * The Go files changed in the git working tree at `dir` since `ref`,
+ * including untracked ones, relative to `dir` with forward slashes. Returns
+ * undefined if a go.mod changed, since that can affect every package.
+ */
+export async function changedGoFiles(dir: string, ref: string): Promise<string[] | undefined> {
+  const git = (...args: string[]) =>
+    promisify(execFile)("git", args, { cwd: dir, maxBuffer: 64 * 1024 * 1024 }).then(
+      ({ stdout }) => stdout.split("\n").filter((line) => line !== ""),
+    );
+  let files: string[];
+  try {
+    files = [
+      // Without rename detection, a file moved between packages lists both
+      ...(await git("diff", "--name-only", "--no-renames", "--relative", ref, "--", ".")),
+      ...(await git("ls-files", "--others", "--exclude-standard", "--", ".")),
+    ];
+  } catch (error) {
+    const message = (error as { stderr?: string }).stderr?.trim() || (error as Error).message;
+    throw new Error(`Can't list the files changed since ${ref}: ${message}`, { cause: error });
+  }
+  const changed = [...new Set(files.filter(isWatchedFile))].sort(compareCanonical);
+  return changed.some((file) => posix.basename(file) === "go.mod") ? undefined : changed;
+}
+
+/**
+ * Read a split output: the manifest and its package files, which may be
+ * compressed. Files listed in the manifest must exist.
+ */
+export async function loadSplitOutput(dir: string): Promise<LoadedSplitOutput> {
+  let manifest: SplitManifest;
+  const files = new Map<string, string>();
+  try {
+    const content = await readFile(join(dir, splitManifestFile), "utf-8");
+    manifest = JSON.parse(content) as SplitManifest;
+    files.set(splitManifestFile, content);
+  } catch (error) {
+    throw new Error(`No split output to update in ${dir}: ${(error as Error).message}`, {
+      cause: error,
+    });
+  }
+  const packages: SplitPackage[] = [];
+  for (const entry of manifest.packages) {
+    const content = decompress(await readFile(join(dir, entry.file))).toString("utf-8");
+    packages.push(JSON.parse(content) as SplitPackage);
+    files.set(entry.file, content);
+  }
+  return { manifest, packages, files };
+}
+
+/**
+ * Merge a fresh extraction into a previous split output, for the changed
+ * package directories. Symbols of the changed packages are replaced
+ * (packages without symbols left are dropped), package notes, examples, and
+ * errors from their files are replaced, and package docs are kept unless the
+ * fresh extraction has them. The fresh extraction can cover more packages,
+ * like the ones the changed packages import; only the changed ones are taken.
+ */
+export function mergeSplitOutput(
+  previous: LoadedSplitOutput,
+  fresh: ExtractorOutput,
+  changedDirs: string[],
+  config: GoExtractorConfig,
+): ExtractorOutput {
+  const changed = new Set(changedDirs);
+  const inChanged = (path: string) => changed.has(posix.dirname(path));
+  const module = fresh.package.modulePath || fresh.package.publishedName;
+  const importPathOf = (dir: string) => (dir === "." ? module : `${module}/${dir}`);
+  const changedImportPaths = new Set(changedDirs.map(importPathOf));
+
+  const symbols = sortSymbols([
+    ...previous.packages
+      .filter((pkg) => !changedImportPaths.has(pkg.importPath))
+      .flatMap((pkg) => pkg.symbols),
+    ...(fresh.symbols as GoSymbolRecord[]).filter((symbol) => inChanged(symbol.source.path)),
+  ]);
+
+  const old = previous.manifest.package;
+  const examples = [
+    ...(old.examples ?? []).filter((example) => !inChanged(example.source.path)),
+    ...(fresh.package.examples ?? []).filter((example) => inChanged(example.source.path)),
+  ];
+  const notes: Record<string, OutputNote[]> = {};
+  for (const [marker, list] of Object.entries(old.notes ?? {})) {
+    const kept = list.filter((note) => !inChanged(note.source.path));
+    if (kept.length > 0) notes[marker] = kept;
+  }
+  for (const [marker, list] of Object.entries(fresh.package.notes ?? {})) {
+    const taken = list.filter((note) => inChanged(note.source.path));
+    if (taken.length > 0) notes[marker] = [...(notes[marker] ?? []), ...taken];
+  }
+  const errors = sortExtractionErrors([
+    ...(old.errors ?? []).filter((error) => !changed.has(error.package || ".")),
+    ...(fresh.package.errors ?? []).filter((error) => changed.has(error.package || ".")),
+  ]);
+
+  return {
+    package: {
+      ...fresh.package,
+      synopsis: fresh.package.synopsis ?? old.synopsis,
+      overview: fresh.package.overview ?? old.overview,
+      overviewBlocks: fresh.package.overviewBlocks ?? old.overviewBlocks,
+      owners: fresh.package.owners ?? old.owners,
+      examples: examples.length > 0 ? examples : undefined,
+      notes: Object.keys(notes).length > 0 ? notes : undefined,
+      categories: groupCategories(symbols),
+      errors: errors.length > 0 ? errors : undefined,
+    },
+    provenance: fresh.provenance,
+    labels: fresh.labels,
+    capabilities: buildCapabilities(config, symbols),
+    coverage: buildCoverage(symbols),
+    symbols,
+  };
+}
@@ -0,0 +1,73 @@
+/**
+ * Single Files
+ *
+ * Extracts a standalone Go file, or Go source read from stdin, without a
+ * module around it, for editor integrations and quick debugging: the source
+ * is copied to a temporary directory and extracted as a package of its own,
+ * named after its package clause. Extraction is best-effort as always, so
+ * the file doesn't have to compile or resolve its imports.
+ */
+
+import { mkdtemp, readFile, writeFile } from "fs/promises";
+import { tmpdir } from "os";
+import { basename, join } from "path";
+
+/**
+ * Name a file read from stdin is extracted as.
+ */
+export const stdinFileName = "stdin.go";
+
+/**
+ * A single file, copied to a directory of its own.
+ */
+export interface StagedFile {
+  /** Directory to extract, which holds only the file */
+  dir: string;
+  /** Name of the file in the directory */
+  file: string;
+  /** Name in the package clause, if the file has one */
+  packageName?: string;
+}
+
+/**
+ * Whether positional sources name a single Go file.
+ */
+export function isGoFileSource(source: string[]): boolean {
+  return source.length === 1 && source[0].endsWith(".go");
+}
+
+/**
+ * Get the name in a file's package clause.
+ */
+export function packageClause(content: string): string | undefined {
+  return content.match(/^package\s+(\w+)/m)?.[1];
+}
+
+/**
+ * Copy Go source to a temporary directory, as the file it was read from or
+ * as `stdin.go`.
+ */
+export async function stageGoFile(content: string, file = stdinFileName): Promise<StagedFile> {
+  const dir = await mkdtemp(join(tmpdir(), "extract-go-file-"));
+  const name = basename(file);
+  await writeFile(join(dir, name), content, "utf-8");
+  return { dir, file: name, packageName: packageClause(content) };
+}
+
+/**
+ * Stage a Go file read from disk.
+ */
+export async function stageGoFileFrom(path: string): Promise<StagedFile> {
+  return stageGoFile(await readFile(path, "utf-8"), path);
+}
+
+/**
+ * Read a stream to its end as UTF-8, like stdin.
+ */
+export async function readStream(stream: NodeJS.ReadableStream): Promise<string> {
+  const chunks: Buffer[] = [];
+  for await (const chunk of stream) {
+    chunks.push(typeof chunk === "string" ? Buffer.from(chunk) : chunk);
+  }
+  return Buffer.concat(chunks).toString("utf-8");
+}
@@ -0,0 +1,73 @@
+/**
+ * Slug Collisions
+ *
+ * Go identifiers are case-sensitive, but the pages and anchors generated from
+ * them often end up on case-insensitive, Unicode-normalizing filesystems and
+ * URLs. Symbols such as `Client` and `client` would then overwrite each other.
+ */
+
+import type { SymbolRecord } from "@langchain/ir-schema";
+import { compareCanonical } from "./canonical.js";
+
+/**
+ * Symbols whose URLs normalize to the same slug.
+ */
+export interface SlugCollision {
+  /** The shared normalized slug */
+  slug: string;
+  /** Symbol IDs in the collision, in the order their URLs were assigned */
+  symbolIds: string[];
+  /** Disambiguated URLs, parallel to `symbolIds` */
+  urls: string[];
+}
+
+/**
+ * Normalize a URL or anchor the way a case-insensitive, Unicode-normalizing
+ * filesystem would compare it.
+ */
+export function normalizeSlug(slug: string): string {
+  return slug.normalize("NFKC").toLowerCase();
+}
+
+/**
+ * Give every symbol a canonical URL that is unique after normalization.
+ *
+ * Colliding URLs are in canonical order, so `Client` sorts before
+ * `client`. The first keeps its URL and the rest get `-2`, `-3`, ... suffixes,
+ * which can't clash with Go identifiers. Returns the collisions that were
+ * resolved.
+ */
+export function resolveSlugCollisions(symbols: SymbolRecord[]): SlugCollision[] {
+  const groups = new Map<string, SymbolRecord[]>();
+  for (const symbol of symbols) {
+    const slug = normalizeSlug(symbol.urls.canonical);
+    const group = groups.get(slug) ?? [];
+    group.push(symbol);
+    groups.set(slug, group);
+  }
+
+  const taken = new Set(groups.keys());
+  const collisions: SlugCollision[] = [];
+  for (const [slug, group] of groups) {
+    if (group.length < 2) continue;
+
+    group.sort(
+      (a, b) =>
+        compareCanonical(a.urls.canonical, b.urls.canonical) || compareCanonical(a.id, b.id),
+    );
+    for (const symbol of group.slice(1)) {
+      let n = 2;
+      while (taken.has(normalizeSlug(`${symbol.urls.canonical}-${n}`))) n++;
+      symbol.urls.canonical = `${symbol.urls.canonical}-${n}`;
+      taken.add(normalizeSlug(symbol.urls.canonical));
+    }
+
+    collisions.push({
+      slug,
+      symbolIds: group.map((s) => s.id),
+      urls: group.map((s) => s.urls.canonical),
+    });
+  }
+
+  return collisions;
+}
@@ -0,0 +1,97 @@
+/**
+ * Split Output
+ *
+ * Writes an extraction as a file per package instead of one document, so
+ * incremental publishing only uploads the packages that changed: each
+ * package's symbols go to `<import path>.json`, and `manifest.json` holds
+ * the header of the document (package, labels, capabilities, coverage) and
+ * lists the package files with their symbol counts and SHA-256 checksums.
+ * Package files can be compressed; the manifest records the encoding and
+ * stays plain JSON, and checksums are of the uncompressed JSON, so they
+ * don't change with the compressor's version.
+ */
+
+import { createHash } from "crypto";
+import { posix } from "path";
+import type { ExtractorOutput } from "./output.js";
+import { compareCanonical } from "./canonical.js";
+import { compressionExtensions, type Compression } from "./compress.js";
+import type { GoSymbolRecord } from "./transformer.js";
+
+/**
+ * Name of the manifest file of a split output.
+ */
+export const splitManifestFile = "manifest.json";
+
+/**
+ * A package file of a split output.
+ */
+export interface SplitPackage {
+  importPath: string;
+  symbols: GoSymbolRecord[];
+}
+
+/**
+ * A package file listed in the manifest.
+ */
+export interface SplitManifestEntry {
+  importPath: string;
+  /** Path of the file, relative to the manifest */
+  file: string;
+  symbols: number;
+  /** SHA-256 of the file's uncompressed contents, hex-encoded */
+  sha256: string;
+}
+
+/**
+ * Manifest of a split output: the document without its symbols, and the
+ * package files.
+ */
+export type SplitManifest = Omit<ExtractorOutput, "symbols"> & {
+  /** Compression of the package files */
+  encoding?: Compression;
+  packages: SplitManifestEntry[];
+};
+
+/**
+ * Split an output into package files, keyed by path. The manifest is the
+ * last file. With a compression, package files get its extension, and are
+ * to be compressed when written.
+ */
+export function splitOutput(
+  output: ExtractorOutput,
+  compression?: Compression,
+): Map<string, string> {
+  const module = output.package.modulePath || output.package.publishedName;
+  const byPackage = new Map<string, GoSymbolRecord[]>();
+  for (const symbol of output.symbols as GoSymbolRecord[]) {
+    const dir = posix.dirname(symbol.source.path);
+    const importPath = dir === "." ? module : `${module}/${dir}`;
+    byPackage.set(importPath, [...(byPackage.get(importPath) ?? []), symbol]);
+  }
+
+  const files = new Map<string, string>();
+  const packages: SplitManifestEntry[] = [];
+  for (const importPath of [...byPackage.keys()].sort(compareCanonical)) {
+    const extension = compression ? compressionExtensions[compression] : "";
+    const file = `${importPath}.json${extension}`;
+    const symbols = byPackage.get(importPath)!;
+    const pkg: SplitPackage = { importPath, symbols };
+    const content = JSON.stringify(pkg, null, 2);
+    const sha256 = createHash("sha256").update(content).digest("hex");
+    files.set(file, content);
+    packages.push({ importPath, file, symbols: symbols.length, sha256 });
+  }
+
+  const manifest: SplitManifest = {
+    package: output.package,
+    provenance: output.provenance,
+    labels: output.labels,
+    capabilities: output.capabilities,
+    coverage: output.coverage,
+    encoding: compression,
+    packages,
+  };
+  files.set(splitManifestFile, JSON.stringify(manifest, null, 2));
+  return files;
+}
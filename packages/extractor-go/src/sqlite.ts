@@ -0,0 +1,211 @@
+/**
+ * SQLite Index
+ *
+ * Writes an extraction into a SQLite database for ad-hoc queries and the
+ * docs site's server-side lookups: a table each for packages, symbols,
+ * signatures, and docs, and a `refs` table of cross-references between
+ * symbols (type references, members, implemented interfaces, doc links, and
+ * "See also" links). Uses the built-in `node:sqlite` module (Node.js 22.5+).
+ */
+
+import type { ExtractorOutput } from "./output.js";
+import type { GoSymbolRecord } from "./transformer.js";
+import { docLinkSpans } from "./doc-comment.js";
+
+/**
+ * Tables and indexes of the database.
+ */
+export const sqliteSchema = `
+CREATE TABLE packages (
+  id TEXT PRIMARY KEY,
+  name TEXT NOT NULL,
+  published_name TEXT NOT NULL,
+  language TEXT NOT NULL,
+  version TEXT NOT NULL,
+  repo TEXT NOT NULL,
+  sha TEXT NOT NULL,
+  path TEXT NOT NULL,
+  synopsis TEXT,
+  provenance TEXT
+);
+CREATE TABLE symbols (
+  id TEXT PRIMARY KEY,
+  package_id TEXT NOT NULL REFERENCES packages (id),
+  name TEXT NOT NULL,
+  qualified_name TEXT NOT NULL,
+  kind TEXT NOT NULL,
+  visibility TEXT NOT NULL,
+  stability TEXT NOT NULL,
+  url TEXT NOT NULL,
+  source_path TEXT NOT NULL,
+  source_line INTEGER NOT NULL
+);
+CREATE TABLE signatures (
+  symbol_id TEXT PRIMARY KEY REFERENCES symbols (id),
+  signature TEXT NOT NULL,
+  params TEXT,
+  returns TEXT
+);
+CREATE TABLE docs (
+  symbol_id TEXT PRIMARY KEY REFERENCES symbols (id),
+  summary TEXT NOT NULL,
+  description TEXT,
+  deprecated INTEGER NOT NULL,
+  deprecation_message TEXT
+);
+CREATE TABLE refs (
+  from_id TEXT NOT NULL REFERENCES symbols (id),
+  to_id TEXT,
+  to_name TEXT NOT NULL,
+  kind TEXT NOT NULL
+);
+CREATE INDEX symbols_name ON symbols (name);
+CREATE INDEX symbols_qualified_name ON symbols (qualified_name);
+CREATE INDEX refs_from_id ON refs (from_id);
+CREATE INDEX refs_to_id ON refs (to_id);
+`;
+
+/**
+ * A value of a table row.
+ */
+export type SqliteValue = string | number | null;
+
+/**
+ * Rows of each table, keyed by table name, with values by column name.
+ */
+export type SqliteTables = Record<
+  "packages" | "symbols" | "signatures" | "docs" | "refs",
+  Array<Record<string, SqliteValue>>
+>;
+
+/**
+ * Kind of a cross-reference in the `refs` table.
+ *
+ * - `type`: a type named in the signature
+ * - `member`: a field or method of a type
+ * - `implements`: an interface the type implements
+ * - `extends`: a type the struct or interface embeds
+ * - `docLink`: a `[Name]` link in the doc comment
+ * - `related`: a symbol named in a "See also" sentence
+ */
+export type SqliteRefKind =
+  | "type"
+  | "member"
+  | "implements"
+  | "extends"
+  | "docLink"
+  | "related";
+
+/**
+ * Build the table rows of an output.
+ */
+export function sqliteTables(output: ExtractorOutput): SqliteTables {
+  const pkg = output.package;
+  const symbols = output.symbols as GoSymbolRecord[];
+  const idsByName = new Map(symbols.map((symbol) => [symbol.qualifiedName, symbol.id]));
+  const tables: SqliteTables = {
+    packages: [
+      {
+        id: pkg.packageId,
+        name: pkg.displayName,
+        published_name: pkg.publishedName,
+        language: pkg.language,
+        version: pkg.version,
+        repo: pkg.repo.owner && `${pkg.repo.owner}/${pkg.repo.name}`,
+        sha: pkg.repo.sha,
+        path: pkg.repo.path,
+        synopsis: pkg.synopsis ?? null,
+        provenance: output.provenance ? JSON.stringify(output.provenance) : null,
+      },
+    ],
+    symbols: [],
+    signatures: [],
+    docs: [],
+    refs: [],
+  };
+
+  for (const symbol of symbols) {
+    tables.symbols.push({
+      id: symbol.id,
+      package_id: symbol.packageId,
+      name: symbol.name,
+      qualified_name: symbol.qualifiedName,
+      kind: symbol.kind,
+      visibility: symbol.tags.visibility,
+      stability: symbol.tags.stability,
+      url: symbol.urls.canonical,
+      source_path: symbol.source.path,
+      source_line: symbol.source.line,
+    });
+    tables.signatures.push({
+      symbol_id: symbol.id,
+      signature: symbol.signature,
+      params: symbol.params
+        ? JSON.stringify(symbol.params.map(({ name, type }) => ({ name, type })))
+        : null,
+      returns: symbol.returns?.type ?? null,
+    });
+    tables.docs.push({
+      symbol_id: symbol.id,
+      summary: symbol.docs.summary,
+      description: symbol.docs.description ?? null,
+      deprecated: symbol.docs.deprecated ? 1 : 0,
+      deprecation_message: symbol.docs.deprecated?.message ?? null,
+    });
+
+    const ref = (kind: SqliteRefKind, name: string, refId?: string) =>
+      tables.refs.push({ from_id: symbol.id, to_id: refId ?? null, to_name: name, kind });
+    for (const typeRef of symbol.typeRefs ?? []) {
+      ref("type", typeRef.qualifiedName ?? typeRef.name, typeRef.refId);
+    }
+    for (const member of symbol.members ?? []) {
+      ref("member", member.name, member.kind === "method" ? member.refId : undefined);
+    }
+    for (const name of symbol.relations?.implements ?? []) {
+      ref("implements", name, idsByName.get(name));
+    }
+    for (const name of symbol.relations?.extends ?? []) {
+      ref("extends", name, idsByName.get(name));
+    }
+    for (const link of docLinkSpans(symbol.docs.blocks ?? [])) {
+      ref("docLink", link.target, link.refId);
+    }
+    for (const related of symbol.related ?? []) {
+      ref("related", related.name, related.refId);
+    }
+  }
+  return tables;
+}
+
+/**
+ * Write an output to a new SQLite database file, replacing its tables.
+ */
+export async function writeSqlite(output: ExtractorOutput, file: string): Promise<void> {
+  let sqlite: typeof import("node:sqlite");
+  try {
+    sqlite = await import("node:sqlite");
+  } catch {
+    throw new Error(`SQLite output needs Node.js 22.5 or later (running ${process.version})`);
+  }
+
+  const db = new sqlite.DatabaseSync(file);
+  try {
+    db.exec("BEGIN");
+    for (const table of ["refs", "docs", "signatures", "symbols", "packages"]) {
+      db.exec(`DROP TABLE IF EXISTS ${table}`);
+    }
+    db.exec(sqliteSchema);
+    for (const [table, rows] of Object.entries(sqliteTables(output))) {
+      if (rows.length === 0) continue;
+      const columns = Object.keys(rows[0]);
+      const insert = db.prepare(
+        `INSERT INTO ${table} (${columns.join(", ")}) ` +
+          `VALUES (${columns.map(() => "?").join(", ")})`,
+      );
+      for (const row of rows) insert.run(...columns.map((column) => row[column]));
+    }
+    db.exec("COMMIT");
+  } finally {
+    db.close();
+  }
+}
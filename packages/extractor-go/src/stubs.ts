@@ -0,0 +1,308 @@
+/**
+ * Go Stubs
+ *
+ * Renders the exported API surface of an extraction as Go files that keep
+ * the declarations and doc comments but none of the implementation: bodies
+ * are empty, or panic where results are required. The stubs compile on their
+ * own, for API review, license-safe sharing, and other analysis tools.
+ */
+
+import { readdir, readFile } from "fs/promises";
+import { dirname, join } from "path";
+import type { ExtractionResult, GoConst, GoMethod, GoType } from "./extractor.js";
+import { importName, parseImports, type FileImports } from "./imports.js";
+import { splitTopLevel } from "./type-expr.js";
+
+/**
+ * Render one stub file per source file with exported declarations, keyed by
+ * the source path relative to the package path. Test files are left out.
+ */
+export async function renderStubs(
+  result: ExtractionResult,
+  packagePath: string,
+): Promise<Map<string, string>> {
+  const decls = new Map<string, Array<{ line: number; text: string }>>();
+  const add = (sourceFile: string, line: number, text: string) => {
+    if (sourceFile.endsWith("_test.go")) return;
+    decls.set(sourceFile, [...(decls.get(sourceFile) ?? []), { line, text }]);
+  };
+  const sources = new Map<string, string>();
+  const source = async (sourceFile: string) => {
+    if (!sources.has(sourceFile)) {
+      sources.set(sourceFile, await readFile(join(packagePath, sourceFile), "utf-8"));
+    }
+    return sources.get(sourceFile)!;
+  };
+
+  for (const constant of result.constants) {
+    const text = stubConstant(constant, parseImports(await source(constant.sourceFile)));
+    if (text) add(constant.sourceFile, constant.startLine, text);
+  }
+  for (const type of result.types) {
+    add(type.sourceFile, type.startLine, stubType(type));
+    for (const method of type.methods) {
+      const receiver = method.promotedFrom ? type.name : undefined;
+      add(type.sourceFile, type.startLine, stubFunction(method, receiver));
+    }
+  }
+  for (const func of result.functions) {
+    add(func.sourceFile, func.startLine, stubFunction(func));
+  }
+
+  // Unexported types the declarations refer to are declared opaque, so the
+  // stubs of each package compile
+  const packages = new Map<string, string[]>();
+  for (const sourceFile of decls.keys()) {
+    const dir = dirname(sourceFile);
+    packages.set(dir, [...(packages.get(dir) ?? []), sourceFile]);
+  }
+  for (const [dir, files] of packages) {
+    const declared = new Set(
+      result.types.filter((t) => dirname(t.sourceFile) === dir).map((t) => t.name),
+    );
+    const code = files.flatMap((file) => decls.get(file)!.map((d) => d.text)).join("\n");
+    const referenced = identifiers(code);
+    for (const type of await unexportedTypes(packagePath, dir)) {
+      if (referenced.has(type.name) && !declared.has(type.name)) {
+        add(type.sourceFile, type.line, type.text);
+      }
+    }
+  }
+
+  const stubs = new Map<string, string>();
+  for (const [sourceFile, fileDecls] of [...decls].sort(([a], [b]) => a.localeCompare(b))) {
+    const content = await source(sourceFile);
+    const packageClause = content.match(/^package\s+(\w+)/m);
+    if (!packageClause) continue;
+
+    const body = fileDecls
+      .sort((a, b) => a.line - b.line)
+      .map((d) => d.text)
+      .join("\n\n");
+    const imports = usedImports(body, parseImports(content));
+    const header = content.slice(0, packageClause.index);
+
+    let stub = `${header}package ${packageClause[1]}\n\n`;
+    if (imports.length > 0) {
+      stub += `import (\n${imports.map((i) => `\t${i}`).join("\n")}\n)\n\n`;
+    }
+    stubs.set(sourceFile, `${stub}${body}\n`);
+  }
+  return stubs;
+}
+
+/**
+ * Render a doc comment as `//` lines.
+ */
+function docComment(doc?: string): string {
+  if (!doc) return "";
+  return doc
+    .split("\n")
+    .map((line) => (line ? `// ${line}` : "//"))
+    .join("\n")
+    .concat("\n");
+}
+
+/**
+ * Render a constant or variable. Constants use their folded value, since the
+ * expression may refer to unexported names; declarations that can't be
+ * rendered without them are skipped. Variables keep their type, or the type
+ * of a function or composite literal, and their value only if it refers to
+ * no unexported names but the file's imports.
+ */
+function stubConstant(constant: GoConst, imports: FileImports): string | undefined {
+  const doc = docComment(constant.doc);
+  if (constant.kind === "const") {
+    const evaluated = constant.evaluated;
+    if (evaluated) {
+      const type =
+        constant.type ?? (evaluated.type.startsWith("untyped") ? undefined : evaluated.type);
+      return `${doc}const ${constant.name}${type ? ` ${type}` : ""} = ${evaluated.value}`;
+    }
+    if (!constant.value || hasUnexported(constant.value, imports)) return undefined;
+    const type = constant.type ? ` ${constant.type}` : "";
+    return `${doc}const ${constant.name}${type} = ${constant.value}`;
+  }
+
+  if (constant.type) return `${doc}var ${constant.name} ${constant.type}`;
+  const literal = constant.funcLiteral;
+  if (literal) {
+    const params = literal.parameters.map((p) => (p.name ? `${p.name} ${p.type}` : p.type));
+    const returns = literal.returns ? ` ${literal.returns}` : "";
+    return `${doc}var ${constant.name} func(${params.join(", ")})${returns}`;
+  }
+  if (!constant.value) return undefined;
+  if (!hasUnexported(constant.value, imports)) {
+    return `${doc}var ${constant.name} = ${constant.value}`;
+  }
+  const composite = constant.value.match(/^&?(?:\w+\.)?[A-Za-z_]\w*(?=\s*\{)/);
+  return composite ? `${doc}var ${constant.name} = ${composite[0]}{}` : undefined;
+}
+
+/**
+ * Render a type declaration with its exported fields or the methods of an
+ * interface.
+ */
+function stubType(type: GoType): string {
+  const doc = docComment(type.doc);
+  if (type.kind === "alias") return `${doc}${type.signature}`;
+
+  const head = type.signature;
+  if (type.kind === "interface") {
+    const body = exportedMethods(type.interfaceBody ?? "").replace(/^\n+|\s+$/g, "");
+    return `${doc}${head} {\n${body}\n}`;
+  }
+
+  const fields = type.fields.map((field) => {
+    const fieldDoc = docComment(field.doc).replace(/^(?=.)/gm, "\t");
+    const tag = field.tag ? ` \`${field.tag}\`` : "";
+    return `${fieldDoc}\t${field.name} ${field.type}${tag}`;
+  });
+  return fields.length > 0 ? `${doc}${head} {\n${fields.join("\n")}\n}` : `${doc}${head}{}`;
+}
+
+/**
+ * Render a function or method with an empty body, or one that panics if it
+ * has results. Promoted methods are declared on the embedding type.
+ */
+function stubFunction(func: GoMethod, receiverType?: string): string {
+  let signature = func.signature;
+  if (receiverType) {
+    signature = signature.replace(/^func\s+\((\w+)\s+\*?\w+/, `func ($1 *${receiverType}`);
+  }
+  const body = func.returns ? ' {\n\tpanic("stub")\n}' : " {}";
+  return `${docComment(func.doc)}${signature}${body}`;
+}
+
+/**
+ * Import specs for the packages the code refers to, ignoring comments.
+ */
+function usedImports(code: string, imports: FileImports): string[] {
+  const lines = code.split("\n").filter((line) => !line.trim().startsWith("//"));
+  const used = new Set(Array.from(lines.join("\n").matchAll(/\b(\w+)\.[A-Z]/g), (m) => m[1]));
+
+  return Array.from(imports)
+    .filter(([name]) => used.has(name))
+    .map(([name, path]) => (importName(path) === name ? `"${path}"` : `${name} "${path}"`))
+    .sort((a, b) => a.replace(/^\w+ /, "").localeCompare(b.replace(/^\w+ /, "")));
+}
+
+/**
+ * An interface body without its unexported methods and their doc comments.
+ * Embedded interfaces are kept.
+ */
+function exportedMethods(body: string): string {
+  const kept: string[] = [];
+  let comments: string[] = [];
+  // Parentheses still open in an unexported method spanning lines
+  let skipping = 0;
+  for (const line of body.split("\n")) {
+    if (skipping > 0) {
+      skipping += parenDepth(line);
+      continue;
+    }
+    const trimmed = line.trim();
+    if (trimmed.startsWith("//")) {
+      comments.push(line);
+      continue;
+    }
+    const method = trimmed.match(/^([A-Za-z_]\w*)\s*\(/);
+    if (method && !/^[A-Z]/.test(method[1])) {
+      skipping = Math.max(0, parenDepth(line));
+    } else {
+      kept.push(...comments, line);
+    }
+    comments = [];
+  }
+  return [...kept, ...comments].join("\n");
+}
+
+/**
+ * The parentheses a line opens, less those it closes.
+ */
+function parenDepth(line: string): number {
+  return (line.match(/\(/g)?.length ?? 0) - (line.match(/\)/g)?.length ?? 0);
+}
+
+/**
+ * Go's keywords and predeclared identifiers, which aren't unexported names.
+ */
+const predeclared = new Set(
+  (
+    "break case chan const continue default defer else fallthrough for func go goto if import " +
+    "interface map package range return select struct switch type var " +
+    "any bool byte comparable complex64 complex128 error float32 float64 int int8 int16 int32 " +
+    "int64 rune string uint uint8 uint16 uint32 uint64 uintptr " +
+    "true false iota nil append cap clear close complex copy delete imag len make max min new " +
+    "panic print println real recover"
+  ).split(" "),
+);
+
+/**
+ * The unqualified identifiers code refers to, ignoring comments and string
+ * literals.
+ */
+function identifiers(code: string): Set<string> {
+  const stripped = code
+    .replace(/"(?:[^"\\\n]|\\.)*"|`[^`]*`|'(?:[^'\\\n]|\\.)*'/g, '""')
+    .replace(/\/\/.*$/gm, "");
+  return new Set(Array.from(stripped.matchAll(/(?<![.\w])[A-Za-z_]\w*/g), (m) => m[0]));
+}
+
+/**
+ * Whether an expression refers to an unexported name other than an import.
+ */
+function hasUnexported(expression: string, imports: FileImports): boolean {
+  return [...identifiers(expression)].some(
+    (name) => !/^[A-Z]/.test(name) && !predeclared.has(name) && !imports.has(name),
+  );
+}
+
+/**
+ * Opaque declarations of the unexported types of a package directory:
+ * interfaces are empty, types of a predeclared or exported type keep it, and
+ * others are empty structs. Type parameters are constrained by `any`.
+ */
+async function unexportedTypes(
+  packagePath: string,
+  dir: string,
+): Promise<Array<{ name: string; sourceFile: string; line: number; text: string }>> {
+  const files = (await readdir(join(packagePath, dir))).filter(
+    (file) => file.endsWith(".go") && !file.endsWith("_test.go"),
+  );
+  const types = [];
+  for (const file of files.sort()) {
+    const content = await readFile(join(packagePath, dir, file), "utf-8");
+    // Declarations of a `type (...)` group are indented once, as gofmt does
+    const groups = Array.from(content.matchAll(/^type[ \t]*\([^\n]*\n[\s\S]*?^\)/gm), (m) => [
+      m.index,
+      m.index + m[0].length,
+    ]);
+    for (const match of content.matchAll(/^(?:type[ \t]+|\t)([a-z_]\w*)/gm)) {
+      const grouped = groups.some(([start, end]) => match.index > start && match.index < end);
+      if (match[0].startsWith("\t") && !grouped) continue;
+      let end = match.index + match[0].length;
+      let params = "";
+      if (content[end] === "[") {
+        const start = end;
+        for (let depth = 0; end < content.length; end++) {
+          if (content[end] === "[") depth++;
+          else if (content[end] === "]" && --depth === 0) break;
+        }
+        const names = splitTopLevel(content.slice(start + 1, end)).map((p) => p.split(/\s+/)[0]);
+        params = `[${names.join(", ")} any]`;
+        end++;
+      }
+      const rest = content.slice(end).match(/^[ \t]*(=?)[ \t]*([^\n]*)/)!;
+      const underlying = rest[2].replace(/\/\/.*$/, "").trim();
+      const text = underlying.startsWith("interface")
+        ? `type ${match[1]}${params} interface{}`
+        : /^(?:\w+\.[A-Z]\w*|[A-Z]\w*)$/.test(underlying) || predeclared.has(underlying)
+          ? `type ${match[1]}${params} ${rest[1] ? "= " : ""}${underlying}`
+          : `type ${match[1]}${params} struct{}`;
+      const line = content.slice(0, match.index).split("\n").length;
+      types.push({ name: match[1], sourceFile: join(dir, file), line, text });
+    }
+  }
+  return types;
+}
@@ -0,0 +1,166 @@
+/**
+ * Extraction Timings
+ *
+ * Records how long each package spends in each extraction phase so slow runs
+ * can be traced to the packages that dominate them.
+ */
+
+import { performance } from "perf_hooks";
+
+/**
+ * A phase of an extraction run.
+ *
+ * - `parse`: reading and parsing source files
+ * - `typecheck`: cross-file resolution (sealed interfaces, constant folding,
+ *   constructor defaults)
+ * - `analyze`: transforming parsed declarations to IR symbols
+ * - `render`: building and serializing the output document
+ */
+export type TimingPhase = "parse" | "typecheck" | "analyze" | "render";
+
+/**
+ * All timing phases, in pipeline order.
+ */
+export const timingPhases: TimingPhase[] = ["parse", "typecheck", "analyze", "render"];
+
+/**
+ * Milliseconds spent in each phase.
+ */
+export type PhaseTimings = Partial<Record<TimingPhase, number>>;
+
+/**
+ * Time spent on one package directory.
+ */
+export interface PackageTiming {
+  /** Directory relative to the package path ("" for the root) */
+  package: string;
+  files: number;
+  phases: PhaseTimings;
+  total: number;
+}
+
+/**
+ * Timings of a whole run.
+ */
+export interface TimingReport {
+  /** Per-package timings, slowest first */
+  packages: PackageTiming[];
+  /** Time spent on work that spans packages */
+  run: PhaseTimings;
+  total: number;
+}
+
+/**
+ * Collects phase timings by source file and aggregates them per package.
+ */
+export class TimingRecorder {
+  private packages = new Map<string, { files: Set<string>; phases: PhaseTimings }>();
+  private run: PhaseTimings = {};
+
+  /**
+   * Add time spent on a source file (relative to the package path).
+   */
+  recordFile(file: string, phase: TimingPhase, ms: number): void {
+    const slash = file.lastIndexOf("/");
+    const dir = slash === -1 ? "" : file.slice(0, slash);
+    const entry = this.packages.get(dir) ?? { files: new Set<string>(), phases: {} };
+    entry.files.add(file);
+    entry.phases[phase] = (entry.phases[phase] ?? 0) + ms;
+    this.packages.set(dir, entry);
+  }
+
+  /**
+   * Add time spent on work that spans packages.
+   */
+  recordRun(phase: TimingPhase, ms: number): void {
+    this.run[phase] = (this.run[phase] ?? 0) + ms;
+  }
+
+  /**
+   * Run `fn`, charging its time to a source file.
+   */
+  timeFile<T>(file: string, phase: TimingPhase, fn: () => T): T {
+    const start = performance.now();
+    try {
+      return fn();
+    } finally {
+      this.recordFile(file, phase, performance.now() - start);
+    }
+  }
+
+  /**
+   * Run `fn`, charging its time to the run as a whole.
+   */
+  timeRun<T>(phase: TimingPhase, fn: () => T): T {
+    const start = performance.now();
+    try {
+      return fn();
+    } finally {
+      this.recordRun(phase, performance.now() - start);
+    }
+  }
+
+  /**
+   * Aggregate the recorded timings.
+   */
+  report(): TimingReport {
+    const packages = Array.from(this.packages, ([dir, entry]) => ({
+      package: dir,
+      files: entry.files.size,
+      phases: entry.phases,
+      total: sumPhases(entry.phases),
+    })).sort((a, b) => b.total - a.total || a.package.localeCompare(b.package));
+
+    const total = packages.reduce((sum, pkg) => sum + pkg.total, 0) + sumPhases(this.run);
+    return { packages, run: this.run, total };
+  }
+}
+
+/**
+ * Format a timing report as a table, slowest packages first.
+ */
+export function formatTimings(report: TimingReport, limit = 20): string {
+  const header = ["Package", "Files", ...timingPhases, "Total"];
+  const row = (name: string, files: string, phases: PhaseTimings, total: number) => [
+    name,
+    files,
+    ...timingPhases.map((phase) => formatMs(phases[phase])),
+    formatMs(total),
+  ];
+
+  const totals: PhaseTimings = { ...report.run };
+  for (const pkg of report.packages) {
+    for (const [phase, ms] of Object.entries(pkg.phases) as Array<[TimingPhase, number]>) {
+      totals[phase] = (totals[phase] ?? 0) + ms;
+    }
+  }
+  const files = report.packages.reduce((sum, pkg) => sum + pkg.files, 0);
+
+  const rows = [
+    header,
+    ...report.packages
+      .slice(0, limit)
+      .map((pkg) => row(pkg.package || ".", String(pkg.files), pkg.phases, pkg.total)),
+    row("(cross-package)", "", report.run, sumPhases(report.run)),
+    row("Total", String(files), totals, report.total),
+  ];
+
+  const widths = header.map((_, i) => Math.max(...rows.map((r) => r[i].length)));
+  const lines = rows.map((r) =>
+    r.map((cell, i) => (i === 0 ? cell.padEnd(widths[i]) : cell.padStart(widths[i]))).join("  "),
+  );
+
+  const hidden = report.packages.length - limit;
+  if (hidden > 0) {
+    lines.splice(limit + 1, 0, `... ${hidden} more package(s)`);
+  }
+  return lines.join("\n");
+}
+
+function sumPhases(phases: PhaseTimings): number {
+  return Object.values(phases).reduce((sum, ms) => sum + ms, 0);
+}
+
+function formatMs(ms?: number): string {
+  return ms === undefined ? "-" : `${ms.toFixed(1)}ms`;
+}
@@ -0,0 +1,105 @@
+/**
+ * Navigation Tree
+ *
+ * Builds the table of contents of the public API (module → package →
+ * symbol kind → symbol), so the docs sidebar can be built from one small
+ * file instead of every package file. Nodes carry their title, slug, and a
+ * deprecation flag; a type's methods are nested under it. Kind groups use
+ * the MDX pages' headings (Types, Functions, Constants, Variables), in the
+ * output's locale.
+ */
+
+import { posix } from "path";
+import type { SymbolKind } from "@langchain/ir-schema";
+import type { ExtractorOutput } from "./output.js";
+import type { GoSymbolRecord } from "./transformer.js";
+import { defaultLabels, type Labels } from "./labels.js";
+import { compareCanonical } from "./canonical.js";
+
+/**
+ * Kind of a navigation node: the module, a package, a group of symbols of
+ * one kind, or a symbol.
+ */
+export type TocNodeKind = "module" | "package" | "group" | SymbolKind;
+
+/**
+ * A node of the navigation tree.
+ */
+export interface TocNode {
+  kind: TocNodeKind;
+  title: string;
+  /** Canonical URL of a symbol, or path of a package */
+  slug?: string;
+  /** Symbol ID */
+  id?: string;
+  deprecated?: true;
+  children?: TocNode[];
+}
+
+/**
+ * Build the navigation tree of the public API of an output.
+ */
+export function buildToc(output: ExtractorOutput): TocNode {
+  const pkg = output.package;
+  const labels = output.labels?.labels ?? defaultLabels;
+  const symbols = (output.symbols as GoSymbolRecord[]).filter(
+    (symbol) => symbol.tags.visibility === "public" && !symbol.testHelper,
+  );
+  const byId = new Map(symbols.map((symbol) => [symbol.id, symbol]));
+
+  const byPackage = new Map<string, GoSymbolRecord[]>();
+  for (const symbol of symbols) {
+    if (symbol.kind === "method") continue;
+    const dir = posix.dirname(symbol.source.path);
+    byPackage.set(dir, [...(byPackage.get(dir) ?? []), symbol]);
+  }
+
+  const packages = [...byPackage.keys()].sort(compareCanonical).map((dir): TocNode => {
+    const topLevel = byPackage.get(dir)!;
+    const values = topLevel.filter((s) => s.kind === "variable");
+    const groups: Array<[keyof Labels, GoSymbolRecord[]]> = [
+      ["types", topLevel.filter((s) => s.kind !== "function" && s.kind !== "variable")],
+      ["functions", topLevel.filter((s) => s.kind === "function")],
+      ["constants", values.filter((s) => s.signature.startsWith("const "))],
+      ["variables", values.filter((s) => !s.signature.startsWith("const "))],
+    ];
+    return {
+      kind: "package",
+      title: posix.join(pkg.displayName, dir),
+      slug: dir === "." ? "/" : `/${dir}`,
+      children: groups
+        .filter(([, group]) => group.length > 0)
+        .map(([label, group]) => ({
+          kind: "group",
+          title: labels[label],
+          children: group.map((symbol) => {
+            const methods = (symbol.members ?? []).flatMap((member) => {
+              const method = member.kind === "method" ? byId.get(member.refId) : undefined;
+              return method ? [symbolNode(method)] : [];
+            });
+            return { ...symbolNode(symbol), children: methods.length ? methods : undefined };
+          }),
+        })),
+    };
+  });
+
+  return {
+    kind: "module",
+    title: pkg.modulePath || pkg.displayName,
+    slug: "/",
+    children: packages,
+  };
+}
+
+/**
+ * The navigation node of a symbol.
+ */
+function symbolNode(symbol: GoSymbolRecord): TocNode {
+  return {
+    kind: symbol.kind,
+    title: symbol.name,
+    slug: symbol.urls.canonical,
+    id: symbol.id,
+    deprecated: symbol.docs.deprecated ? true : undefined,
+  };
+}
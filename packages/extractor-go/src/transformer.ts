@@ -4,24 +4,276 @@
  * Transforms parsed Go types to IR format.
  */
 
+import { posix } from "path";
+import { performance } from "perf_hooks";
 import type {
   GoType,
   GoMethod,
   GoField,
   GoConst,
   GoParameter,
+  GoTypeParam,
+  GoFieldDefault,
+  GoExternalImplementation,
+  GoTestFunction,
   ExtractionResult,
 } from "./extractor.js";
-import type { GoExtractorConfig } from "./config.js";
+import {
+  defaultFeedbackUrlTemplate,
+  type GoExtractorConfig,
+  type VisibilityTier,
+} from "./config.js";
+import { buildPackageId } from "./output.js";
+import { resolveSlugCollisions } from "./slugs.js";
+import { applyInternalPolicy } from "./internal-packages.js";
+import {
+  extractCallChains,
+  inferChainOrder,
+  isChainable,
+  type GoBuilderInfo,
+} from "./builders.js";
+import { groupLifecycle, isConstructorOf, type GoLifecycle } from "./lifecycle.js";
+import { iteratorOf, type GoIteratorInfo } from "./iterators.js";
+import {
+  defaultDocTags,
+  docToMarkdown,
+  markCallouts,
+  parseDocComment,
+  parseDocText,
+  resolveDocLinks,
+  seeAlsoTargets,
+  splitDocTags,
+  type DocAnnotations,
+  type DocBlock,
+  type DocLinkTarget,
+} from "./doc-comment.js";
+import { buildExample, type GoSymbolExample } from "./examples.js";
+import { renderMarkdown } from "./markdown.js";
+import { renderHtml } from "./html.js";
+import { formatExampleProblems, verifyExamples } from "./verify-examples.js";
+import { formatLabel, resolveLabels, type Labels } from "./labels.js";
+import type { TimingRecorder } from "./timings.js";
+import { namespaceDependencies, type DependencyInfo } from "./vendor.js";
+import { defaultDocLimits, docLimitFor, truncateDoc } from "./truncate.js";
+import type { GoConstValue } from "./const-eval.js";
+import type { SidecarDoc } from "./sidecar.js";
+import { logger } from "./logger.js";
+import {
+  collectNamedTypes,
+  parseResultTypes,
+  parseTypeExpr,
+  type GoTypeExpr,
+} from "./type-expr.js";
 import type {
   SymbolRecord,
   SymbolKind,
   SymbolSource,
   SymbolParam,
+  SymbolReturns,
   SymbolDocs,
+  SymbolUrls,
   MemberReference,
+  DeprecationInfo,
+  TypeReference,
+  TypeParam,
+  SymbolRelations,
 } from "@langchain/ir-schema";
 
+/**
+ * A parameter with its structured Go type.
+ */
+export interface GoSymbolParam extends SymbolParam {
+  typeExpr: GoTypeExpr;
+}
+
+/**
+ * Return information with its structured Go type.
+ */
+export interface GoSymbolReturns extends SymbolReturns {
+  typeExpr: GoTypeExpr;
+}
+
+/**
+ * A deprecation notice with the successor API its message names.
+ */
+export interface GoDeprecationInfo extends DeprecationInfo {
+  /** The symbol or field a "Use X instead" message points to */
+  replacedBy?: GoReplacement;
+}
+
+/**
+ * The successor of a deprecated API: a symbol or field of the package
+ * (`refId`), or a declaration of another package (`url`).
+ */
+export interface GoReplacement {
+  name: string;
+  refId?: string;
+  url?: string;
+}
+
+/**
+ * A symbol named in a "See also" sentence: a symbol of the package (`refId`),
+ * or a declaration of another package (`url`).
+ */
+export interface GoRelatedSymbol extends DocLinkTarget {
+  name: string;
+}
+
+/**
+ * A member reference with its structured Go type (for fields).
+ */
+export interface GoMemberReference extends MemberReference {
+  typeExpr?: GoTypeExpr;
+  /** Doc comment of a field, from the lines above it or its trailing comment */
+  doc?: string;
+  /** Set when the member's doc has a `Deprecated:` paragraph */
+  deprecated?: GoDeprecationInfo;
+  /** Annotation tags of a field's doc, like `@since` */
+  annotations?: DocAnnotations;
+  /** Documentation tier, when not public */
+  tier?: VisibilityTier;
+  /** Values constructors assign to the field */
+  defaults?: GoFieldDefault[];
+  /** Method returning its receiver type, so calls can be chained */
+  chainable?: boolean;
+  /** Method returning an iter.Seq or iter.Seq2 */
+  iterator?: boolean;
+  /** Unexported embedded type a method is promoted from */
+  promotedFrom?: string;
+}
+
+/**
+ * Symbol URLs including the doc feedback link.
+ */
+export interface GoSymbolUrls extends SymbolUrls {
+  /** Link that opens a pre-filled "report a doc issue" form */
+  feedback?: string;
+}
+
+/**
+ * Symbol docs with the truncation marker.
+ */
+export interface GoSymbolDocs extends SymbolDocs {
+  /** Set when the description was cut down to fit the doc limits */
+  truncated?: boolean;
+  /** Link to the full doc comment in the source, when the description was truncated */
+  fullDocsUrl?: string;
+  /** The doc comment as structured blocks; left out when the description was truncated */
+  blocks?: DocBlock[];
+  deprecated?: GoDeprecationInfo;
+  examples?: GoSymbolExample[];
+  /** Annotation tags split off the doc comment, like `@since` and `@category` */
+  annotations?: DocAnnotations;
+  /** The doc comment rendered as CommonMark, when Markdown rendering is on */
+  markdown?: string;
+  /** The doc comment rendered as HTML, when HTML rendering is on */
+  html?: string;
+  /** Supplementary Markdown from the sidecar docs directory */
+  sidecar?: SidecarDoc;
+}
+
+/**
+ * Value of a constant or variable.
+ */
+export interface GoSymbolValue {
+  /** Source expression, e.g. "30 * time.Second" */
+  expression: string;
+  /** Folded value and type of a constant expression */
+  evaluated?: GoConstValue;
+}
+
+/**
+ * How a function or method relates to context.Context.
+ */
+export interface GoContextInfo {
+  /** Whether the first parameter is a context.Context */
+  acceptsContext: boolean;
+  /** ID of the context-accepting variant of a context-free API (Foo -> FooContext) */
+  contextVariant?: string;
+  /** ID of the context-free variant of a context-accepting API (FooContext -> Foo) */
+  contextFreeVariant?: string;
+}
+
+/**
+ * A benchmark or fuzz target exercising a symbol.
+ */
+export interface GoTestReference {
+  kind: GoTestFunction["kind"];
+  name: string;
+  doc?: string;
+  source: SymbolSource;
+}
+
+/**
+ * IR symbol record with Go-specific structure.
+ */
+export interface GoSymbolRecord extends SymbolRecord {
+  docs: GoSymbolDocs;
+  urls: GoSymbolUrls;
+  params?: GoSymbolParam[];
+  returns?: GoSymbolReturns;
+  members?: GoMemberReference[];
+  /** Teams or users maintaining the symbol's source file */
+  owners?: string[];
+  /** Source paths of byte-identical copies of the symbol's package */
+  aliases?: string[];
+  /** Documentation tier, when not public */
+  tier?: VisibilityTier;
+  /** Value of a constant or variable */
+  value?: GoSymbolValue;
+  /** Declared type of a constant or variable, or the aliased type of an alias */
+  typeExpr?: GoTypeExpr;
+  /** Interface with unexported methods, implementable only inside its package */
+  sealed?: boolean;
+  /** In-package implementations of an interface: inferred if sealed, or asserted */
+  implementations?: TypeReference[];
+  /** context.Context usage of a function or method */
+  context?: GoContextInfo;
+  /** Body-less declaration implemented in assembly or linked from another package */
+  externalImplementation?: GoExternalImplementation;
+  /** Declared in an `internal/...` package, not importable outside its module */
+  internal?: boolean;
+  /** Vendored dependency the symbol comes from */
+  dependency?: DependencyInfo;
+  /** Exported only from a `_test.go` file; documented under testing helpers */
+  testHelper?: boolean;
+  /** Benchmarks and fuzz targets exercising the symbol */
+  tests?: GoTestReference[];
+  /** Chainable methods of a builder type */
+  builder?: GoBuilderInfo;
+  /** Method returning its receiver type, so calls can be chained */
+  chainable?: boolean;
+  /** Constructors and methods of a type, by lifecycle stage */
+  lifecycle?: GoLifecycle;
+  /** Range-over-func iterator returned by a function or method */
+  iterator?: GoIteratorInfo;
+  /** Declared in a generated file (`// Code generated ... DO NOT EDIT.`) */
+  generated?: boolean;
+  /** Unexported embedded type a method is promoted from */
+  promotedFrom?: string;
+  /** Build constraint of the declaring file, e.g. "linux && amd64" from `//go:build` */
+  buildConstraint?: string;
+  /** Functional area from a `@category` tag or `Category:` line, for grouped navigation */
+  category?: string;
+  /** Symbols linked from "See also" sentences of the doc comment */
+  related?: GoRelatedSymbol[];
+}
+
+/**
+ * Name suffixes of context-accepting variants, e.g. QueryContext for Query.
+ */
+const CONTEXT_VARIANT_SUFFIXES = ["WithContext", "Context", "Ctx"];
+
+/**
+ * Matches a `Visibility:` annotation line in a doc comment.
+ */
+const TIER_ANNOTATION = /^Visibility:\s*(public|partner|internal)\s*$/m;
+
+/**
+ * Matches a `Category:` line in a doc comment, the alternative to `@category`.
+ */
+const CATEGORY_ANNOTATION = /^Category:[ \t]*(\S.*?)[ \t]*$/m;
+
 /**
  * Transforms Go extraction result to IR symbols.
  */
@@ -29,42 +281,73 @@ export class GoTransformer {
   private result: ExtractionResult;
   private config: GoExtractorConfig;
   private packageId: string;
+  private typeIds: Map<string, string>;
+  private includedTiers: Set<VisibilityTier>;
+  private timings?: TimingRecorder;
+  /** Method call chains in doc comment code, for builder chain order */
+  private exampleChains: string[][];
+  private labels: Labels;
 
-  constructor(result: ExtractionResult, config: GoExtractorConfig) {
+  constructor(result: ExtractionResult, config: GoExtractorConfig, timings?: TimingRecorder) {
     this.result = result;
     this.config = config;
-    this.packageId = `pkg_go_${config.packageName.replace(/[^a-zA-Z0-9]/g, "_")}`;
+    this.timings = timings;
+    this.includedTiers = new Set(config.includeTiers ?? ["public"]);
+    this.packageId = buildPackageId(config.packageName);
+    this.typeIds = new Map(result.types.map((t) => [t.name, `${this.packageId}:${t.name}`]));
+    this.exampleChains = config.inferChainOrder ? this.collectExampleChains() : [];
+    this.labels = resolveLabels(config.localeBundle);
   }
 
   /**
    * Transform all types, functions, and constants to IR symbols.
    * Also emits methods as separate top-level symbols so they have their own pages.
    */
-  transform(): SymbolRecord[] {
-    const symbols: SymbolRecord[] = [];
+  transform(): GoSymbolRecord[] {
+    const symbols: GoSymbolRecord[] = [];
 
     // Transform types (structs, interfaces)
     for (const type of this.result.types) {
-      symbols.push(this.transformType(type));
+      const tier = this.tierOf(type.name, type.doc);
+      if (!this.includedTiers.has(tier) || !this.selected(type.name)) continue;
+      this.timed(type.sourceFile, () => {
+        symbols.push(this.withTier(this.transformType(type, tier), tier));
 
-      // Also emit methods as separate top-level symbols
-      for (const method of type.methods) {
-        symbols.push(this.transformMethodAsSymbol(method, type));
-      }
+        // Also emit methods as separate top-level symbols
+        for (const method of type.methods) {
+          const methodTier = this.tierOf(`${type.name}.${method.name}`, method.doc, tier);
+          if (!this.includedTiers.has(methodTier)) continue;
+          if (this.excluded(`${type.name}.${method.name}`)) continue;
+          symbols.push(this.withTier(this.transformMethodAsSymbol(method, type), methodTier));
+        }
+      });
     }
 
     // Transform top-level functions
     for (const func of this.result.functions) {
-      symbols.push(this.transformFunction(func));
+      const tier = this.tierOf(func.name, func.doc);
+      if (!this.includedTiers.has(tier) || !this.selected(func.name)) continue;
+      this.timed(func.sourceFile, () => {
+        symbols.push(this.withTier(this.transformFunction(func), tier));
+      });
     }
 
     // Transform constants and variables
     for (const constant of this.result.constants) {
-      symbols.push(this.transformConstant(constant));
+      const tier = this.tierOf(constant.name, constant.doc);
+      if (!this.includedTiers.has(tier) || !this.selected(constant.name)) continue;
+      this.timed(constant.sourceFile, () => {
+        symbols.push(this.withTier(this.transformConstant(constant), tier));
+      });
+    }
+
+    const postStart = performance.now();
+    if (this.config.vendoredPackages === "dependencies") {
+      namespaceDependencies(symbols, this.packageId, this.result.vendorModules);
     }
 
     // Deduplicate by ID, preferring symbols with source file info
-    const symbolMap = new Map<string, SymbolRecord>();
+    const symbolMap = new Map<string, GoSymbolRecord>();
     for (const symbol of symbols) {
       const existing = symbolMap.get(symbol.id);
       if (!existing) {
@@ -75,29 +358,214 @@ export class GoTransformer {
       }
     }
 
-    return Array.from(symbolMap.values());
+    const result = applyInternalPolicy(
+      Array.from(symbolMap.values()),
+      this.config.internalPackages ?? "include",
+    );
+    for (const collision of resolveSlugCollisions(result)) {
+      logger.warn(
+        `${collision.symbolIds.join(", ")} share the slug "${collision.slug}"; ` +
+          `using ${collision.urls.join(", ")}`,
+      );
+    }
+    this.annotateContext(result);
+    const generatedFiles = new Set(this.result.generatedFiles);
+    const symbolIds = new Set(result.map((symbol) => symbol.id));
+    const symbolsById = new Map(result.map((symbol) => [symbol.id, symbol]));
+    const symbolUrl = (refId: string) => this.buildSymbolUrl(symbolsById.get(refId));
+    const examples = this.examplesByTarget();
+    const tests = this.testFunctionsByTarget(new Set(result.map((s) => s.qualifiedName)));
+    const sidecarKeys = new Set(Object.keys(this.result.sidecarDocs ?? {}));
+    for (const symbol of result) {
+      const symbolExamples = examples.get(symbol.qualifiedName);
+      if (symbolExamples) {
+        symbol.docs.examples = symbolExamples;
+      }
+      const symbolTests = tests.get(symbol.qualifiedName);
+      if (symbolTests) {
+        symbol.tests = symbolTests;
+      }
+      // Collected before truncation, which drops the blocks
+      const related = this.relatedSymbols(symbol, symbolIds);
+      if (related.length) {
+        symbol.related = related;
+      }
+      this.truncateDocs(symbol);
+      const sidecarKey = this.sidecarKeyOf(symbol);
+      if (sidecarKey) {
+        symbol.docs.sidecar = this.result.sidecarDocs![sidecarKey];
+        sidecarKeys.delete(sidecarKey);
+      }
+      if (symbol.docs.annotations?.experimental) {
+        symbol.tags.stability = "experimental";
+      }
+      if (typeof symbol.docs.annotations?.since === "string") {
+        symbol.versionInfo = { since: symbol.docs.annotations.since };
+      }
+      const tagged = symbol.docs.annotations?.category;
+      const category = typeof tagged === "string" ? tagged : this.categoryOf(symbol);
+      if (category) {
+        symbol.category = category;
+      }
+      if (symbol.docs.deprecated) {
+        symbol.tags.stability = "deprecated";
+        this.resolveReplacement(symbol.docs.deprecated, symbolIds, symbol.source?.path);
+      }
+      for (const member of symbol.members ?? []) {
+        if (member.deprecated) {
+          this.resolveReplacement(member.deprecated, symbolIds, symbol.source?.path, symbol);
+        }
+      }
+      if (symbol.docs.blocks) {
+        symbol.docs.blocks = resolveDocLinks(symbol.docs.blocks, (target) =>
+          this.resolveDocLink(target, symbolIds, symbol.source?.path),
+        );
+        if (this.config.renderMarkdown) {
+          symbol.docs.markdown = renderMarkdown(symbol.docs.blocks, { symbolUrl });
+        }
+        if (this.config.renderHtml) {
+          symbol.docs.html = renderHtml(symbol.docs.blocks, { symbolUrl });
+        }
+      }
+      const feedback = this.buildFeedbackUrl(symbol);
+      if (feedback) {
+        symbol.urls.feedback = feedback;
+      }
+      const aliases = this.aliasesOf(symbol.source?.path);
+      if (aliases) {
+        symbol.aliases = aliases;
+      }
+      if (symbol.source?.path.endsWith("_test.go")) {
+        symbol.testHelper = true;
+      }
+      if (symbol.source && generatedFiles.has(symbol.source.path)) {
+        symbol.generated = true;
+      }
+      const buildConstraint = symbol.source && this.result.buildConstraints?.[symbol.source.path];
+      if (buildConstraint) {
+        symbol.buildConstraint = buildConstraint;
+      }
+    }
+    this.timings?.recordRun("analyze", performance.now() - postStart);
+    for (const key of sidecarKeys) {
+      logger.warn(`${this.result.sidecarDocs![key].path} documents no symbol`);
+    }
+    this.checkExamples();
+
+    return result;
+  }
+
+  /**
+   * Verify the examples against the package, if configured, warning about or
+   * failing on references to APIs the package doesn't declare.
+   */
+  private checkExamples(): void {
+    const mode = this.config.verifyExamples ?? "off";
+    if (mode === "off") return;
+
+    const problems = verifyExamples(this.result);
+    if (problems.length === 0) return;
+    if (mode === "error") {
+      throw new Error(
+        `${problems.length} example(s) refer to APIs the package doesn't declare:\n` +
+          formatExampleProblems(problems),
+      );
+    }
+    for (const line of formatExampleProblems(problems).split("\n")) {
+      logger.warn(line);
+    }
+  }
+
+  /**
+   * Group the example functions by the symbol they document. Package
+   * examples go to the package header instead, and examples whose symbol
+   * isn't in the package are dropped, as godoc does.
+   */
+  private examplesByTarget(): Map<string, GoSymbolExample[]> {
+    const examples = new Map<string, GoSymbolExample[]>();
+    for (const example of this.result.examples ?? []) {
+      if (!example.target) continue;
+      const symbolExample = buildExample(example, this.config);
+      examples.set(example.target, [...(examples.get(example.target) ?? []), symbolExample]);
+    }
+    return examples;
+  }
+
+  /**
+   * Group the benchmark and fuzz functions by the symbols they exercise: the
+   * symbol their name points to, or else the symbols their body refers to,
+   * through the package's import name or, in internal tests, unqualified.
+   */
+  private testFunctionsByTarget(qualifiedNames: Set<string>): Map<string, GoTestReference[]> {
+    const tests = new Map<string, GoTestReference[]>();
+    for (const test of this.result.testFunctions ?? []) {
+      const dir = posix.dirname(test.sourceFile);
+      const importPath = dir === "." ? this.result.moduleName : `${this.result.moduleName}/${dir}`;
+      const imports = this.result.imports?.[test.sourceFile] ?? {};
+
+      const targets =
+        test.target && qualifiedNames.has(test.target)
+          ? [test.target]
+          : test.references.flatMap((reference) => {
+              const [qualifier, name] = reference.includes(".")
+                ? reference.split(".")
+                : [undefined, reference];
+              if (qualifier !== undefined && imports[qualifier] !== importPath) return [];
+              return qualifiedNames.has(name) ? [name] : [];
+            });
+
+      const reference: GoTestReference = {
+        kind: test.kind,
+        name: test.name,
+        doc: test.doc,
+        source: this.buildSourceLocation(test.sourceFile, test.startLine),
+      };
+      for (const target of targets) {
+        tests.set(target, [...(tests.get(target) ?? []), reference]);
+      }
+    }
+    return tests;
+  }
+
+  /**
+   * Run `fn`, charging its time to the analyze phase of a source file.
+   */
+  private timed(file: string, fn: () => void): void {
+    if (this.timings) {
+      this.timings.timeFile(file, "analyze", fn);
+    } else {
+      fn();
+    }
   }
 
   /**
    * Transform a Go type to an IR symbol.
    */
-  private transformType(type: GoType): SymbolRecord {
+  private transformType(type: GoType, tier: VisibilityTier = "public"): GoSymbolRecord {
     // For Go, use just the symbol name as the qualified name
     // The module path is implicit from the package context
     const qualifiedName = type.name;
 
-    const members: MemberReference[] = [];
+    const members: GoMemberReference[] = [];
 
-    // Add methods
+    // Add methods in the included tiers
     for (const method of type.methods) {
-      members.push(this.transformMethod(method, type));
+      const memberTier = this.tierOf(`${type.name}.${method.name}`, method.doc, tier);
+      if (this.includedTiers.has(memberTier) && !this.excluded(`${type.name}.${method.name}`)) {
+        members.push(this.withTier(this.transformMethod(method, type), memberTier));
+      }
     }
 
     // Add fields for structs
     for (const field of type.fields) {
-      members.push(this.transformField(field, type));
+      const memberTier = this.tierOf(`${type.name}.${field.name}`, field.doc, tier);
+      if (this.includedTiers.has(memberTier) && !this.excluded(`${type.name}.${field.name}`)) {
+        members.push(this.withTier(this.transformField(field, type), memberTier));
+      }
     }
 
+    const aliasOf = type.aliasOf ? this.resolveTypeExpr(parseTypeExpr(type.aliasOf)) : undefined;
+
     // In Go, exported symbols start with uppercase letter
     const isExported = /^[A-Z]/.test(type.name);
     const visibility = isExported ? "public" : "private";
@@ -105,7 +573,7 @@ export class GoTransformer {
     // Use qualified name for ID to ensure uniqueness across packages
     const symbolId = qualifiedName.replace(/\./g, "_");
 
-    const symbol: SymbolRecord = {
+    const symbol: GoSymbolRecord = {
       id: `${this.packageId}:${symbolId}`,
       packageId: this.packageId,
       name: type.name,
@@ -118,8 +586,24 @@ export class GoTransformer {
       },
       signature: type.signature,
       docs: this.buildDocs(type.doc),
+      typeParams: this.transformTypeParams(type.typeParams),
+      sealed: type.sealed,
+      implementations: type.implementations?.map((name) => ({
+        name,
+        refId: this.typeIds.get(name),
+      })),
+      relations: this.buildRelations(type),
+      typeExpr: aliasOf,
+      typeRefs: this.buildTypeRefs(
+        [
+          ...members.flatMap((m) => (m.typeExpr ? [m.typeExpr] : [])),
+          ...(aliasOf ? [aliasOf] : []),
+        ],
+        type.typeParams,
+      ),
       members,
       source: this.buildSourceLocation(type.sourceFile, type.startLine),
+      owners: this.ownersOf(type.sourceFile),
       urls: {
         canonical: `/${qualifiedName}`,
       },
@@ -129,13 +613,114 @@ export class GoTransformer {
       },
     };
 
+    if (type.sealed) {
+      symbol.docs = this.withSealedNote(symbol.docs, type.implementations ?? []);
+    }
+
+    const chainable = members.filter((m) => m.chainable).map((m) => m.name);
+    if (chainable.length > 0) {
+      symbol.builder = { chainable, order: inferChainOrder(this.exampleChains, chainable) };
+    }
+
+    const lifecycle = groupLifecycle(
+      this.result.functions
+        .filter((f) => isConstructorOf(f.returns, type.name))
+        .filter((f) => this.includedTiers.has(this.tierOf(f.name, f.doc)) && this.selected(f.name))
+        .map((f) => ({ name: f.name, refId: `${this.packageId}:${f.name}` })),
+      members.filter((m) => m.kind === "method").map((m) => ({ name: m.name, refId: m.refId })),
+    );
+    if (lifecycle) {
+      symbol.lifecycle = lifecycle;
+    }
+
     return symbol;
   }
 
+  /**
+   * Find the method call chains in the package's doc comments.
+   */
+  private collectExampleChains(): string[][] {
+    const docs = [
+      this.result.packageDoc,
+      ...this.result.types.flatMap((t) => [t.doc, ...t.methods.map((m) => m.doc)]),
+      ...this.result.functions.map((f) => f.doc),
+    ];
+    return docs.flatMap((doc) => (doc ? extractCallChains(doc) : []));
+  }
+
+  /**
+   * Flag functions and methods taking a context.Context first and, unless
+   * disabled, link Foo/FooContext variant pairs to each other.
+   */
+  private annotateContext(symbols: GoSymbolRecord[]): void {
+    const callables = symbols.filter((s) => s.kind === "function" || s.kind === "method");
+    const byName = new Map(callables.map((s) => [s.qualifiedName, s]));
+
+    for (const symbol of callables) {
+      const first = symbol.params?.[0]?.typeExpr;
+      if (first?.kind === "named" && first.package === "context" && first.name === "Context") {
+        symbol.context = { ...symbol.context, acceptsContext: true };
+      }
+    }
+
+    if (this.config.pairContextVariants === false) return;
+
+    for (const symbol of callables) {
+      if (!symbol.context?.acceptsContext) continue;
+
+      const suffix = CONTEXT_VARIANT_SUFFIXES.find(
+        (s) => symbol.name.endsWith(s) && symbol.name.length > s.length,
+      );
+      if (!suffix) continue;
+
+      const base = byName.get(symbol.qualifiedName.slice(0, -suffix.length));
+      if (!base || base.context?.acceptsContext) continue;
+
+      symbol.context.contextFreeVariant = base.id;
+      base.context = { acceptsContext: false, contextVariant: symbol.id };
+    }
+  }
+
+  /**
+   * Append a note to the docs of a sealed interface explaining that it
+   * cannot be implemented outside the package, to the description and as a
+   * paragraph block, where exported implementations are doc links.
+   */
+  private withSealedNote(docs: GoSymbolDocs, implementations: string[]): GoSymbolDocs {
+    let note = this.labels.sealedNote;
+    let paragraph = this.labels.sealedNote;
+    if (implementations.length > 0) {
+      const list = implementations.map((name) => `\`${name}\``).join(", ");
+      note += ` ${formatLabel(this.labels.sealedImplementations, { list })}`;
+      const links = implementations
+        .map((name) => (/^[A-Z]/.test(name) ? `[${name}]` : name))
+        .join(", ");
+      paragraph += ` ${formatLabel(this.labels.sealedImplementations, { list: links })}`;
+    }
+
+    return {
+      ...docs,
+      description: docs.description ? `${docs.description}\n\n${note}` : note,
+      blocks: [...(docs.blocks ?? []), { kind: "paragraph", text: parseDocText(paragraph) }],
+    };
+  }
+
+  /**
+   * Note that a method is promoted from an unexported embedded type.
+   */
+  private withPromotedNote(docs: SymbolDocs, promotedFrom?: string): SymbolDocs {
+    if (!promotedFrom) return docs;
+    const note = formatLabel(this.labels.promotedNote, { type: promotedFrom });
+    return {
+      ...docs,
+      description: docs.description ? `${docs.description}\n\n${note}` : note,
+    };
+  }
+
   /**
    * Transform a Go function to an IR symbol.
    */
-  private transformFunction(func: GoMethod): SymbolRecord {
+  private transformFunction(func: GoMethod): GoSymbolRecord {
     // For Go, use just the symbol name as the qualified name
     // The module path is implicit from the package context
     const qualifiedName = func.name;
@@ -147,7 +732,10 @@ export class GoTransformer {
     // Use qualified name for ID to ensure uniqueness across packages
     const symbolId = qualifiedName.replace(/\./g, "_");
 
-    const symbol: SymbolRecord = {
+    const params = func.parameters.map((p) => this.transformParameter(p));
+    const returns = this.transformReturns(func.returns);
+
+    const symbol: GoSymbolRecord = {
       id: `${this.packageId}:${symbolId}`,
       packageId: this.packageId,
       name: func.name,
@@ -160,9 +748,14 @@ export class GoTransformer {
       },
       signature: func.signature,
       docs: this.buildDocs(func.doc),
-      params: func.parameters.map((p) => this.transformParameter(p)),
-      returns: func.returns ? { type: func.returns } : undefined,
-      source: this.buildSourceLocation("", func.startLine),
+      params,
+      returns,
+      typeParams: this.transformTypeParams(func.typeParams),
+      typeRefs: this.buildTypeRefs(this.signatureTypes(params, returns), func.typeParams),
+      source: this.buildSourceLocation(func.sourceFile, func.startLine),
+      owners: this.ownersOf(func.sourceFile),
+      externalImplementation: func.externalImplementation,
+      iterator: iteratorOf(returns?.typeExpr, func.name, { hasParams: params.length > 0 }),
       urls: {
         canonical: `/${qualifiedName}`,
       },
@@ -178,7 +771,7 @@ export class GoTransformer {
   /**
    * Transform a constant/variable to an IR symbol.
    */
-  private transformConstant(constant: GoConst): SymbolRecord {
+  private transformConstant(constant: GoConst): GoSymbolRecord {
     // For Go, use just the symbol name as the qualified name
     // The module path is implicit from the package context
     const qualifiedName = constant.name;
@@ -186,6 +779,7 @@ export class GoTransformer {
     const signature = constant.type
       ? `${constant.kind} ${constant.name} ${constant.type}`
       : `${constant.kind} ${constant.name}`;
+    const typeExpr = constant.type ? this.resolveTypeExpr(parseTypeExpr(constant.type)) : undefined;
 
     // In Go, exported symbols start with uppercase letter
     const isExported = /^[A-Z]/.test(constant.name);
@@ -194,7 +788,7 @@ export class GoTransformer {
     // Use qualified name for ID to ensure uniqueness across packages
     const symbolId = qualifiedName.replace(/\./g, "_");
 
-    const symbol: SymbolRecord = {
+    const symbol: GoSymbolRecord = {
       id: `${this.packageId}:${symbolId}`,
       packageId: this.packageId,
       name: constant.name,
@@ -207,7 +801,17 @@ export class GoTransformer {
       },
       signature,
       docs: this.buildDocs(constant.doc),
+      typeExpr,
+      typeRefs: typeExpr ? this.buildTypeRefs([typeExpr]) : undefined,
+      params: constant.funcLiteral?.parameters.map((p) => this.transformParameter(p)),
+      returns: constant.funcLiteral
+        ? this.transformReturns(constant.funcLiteral.returns)
+        : undefined,
+      value: constant.value
+        ? { expression: constant.value, evaluated: constant.evaluated }
+        : undefined,
       source: this.buildSourceLocation(constant.sourceFile, constant.startLine),
+      owners: this.ownersOf(constant.sourceFile),
       urls: {
         canonical: `/${qualifiedName}`,
       },
@@ -234,7 +838,7 @@ export class GoTransformer {
   /**
    * Transform a method to a member reference.
    */
-  private transformMethod(method: GoMethod, type: GoType): MemberReference {
+  private transformMethod(method: GoMethod, type: GoType): GoMemberReference {
     // In Go, exported symbols start with uppercase letter
     const isExported = /^[A-Z]/.test(method.name);
     const visibility = isExported ? "public" : "private";
@@ -244,13 +848,16 @@ export class GoTransformer {
       refId: this.buildMemberSymbolId(type.name, method.name),
       kind: "method",
       visibility,
+      chainable: isChainable(method, type.name) || undefined,
+      iterator: /^iter\.Seq2?\[/.test(method.returns) || undefined,
+      promotedFrom: method.promotedFrom,
     };
   }
 
   /**
    * Transform a method to a top-level symbol (for dedicated page).
    */
-  private transformMethodAsSymbol(method: GoMethod, type: GoType): SymbolRecord {
+  private transformMethodAsSymbol(method: GoMethod, type: GoType): GoSymbolRecord {
     const qualifiedName = `${type.name}.${method.name}`;
     const symbolId = qualifiedName.replace(/\./g, "_");
 
@@ -258,6 +865,9 @@ export class GoTransformer {
     const isExported = /^[A-Z]/.test(method.name);
     const visibility = isExported ? "public" : "private";
 
+    const params = method.parameters.map((p) => this.transformParameter(p));
+    const returns = this.transformReturns(method.returns);
+
     return {
       id: `${this.packageId}:${symbolId}`,
       packageId: this.packageId,
@@ -270,10 +880,19 @@ export class GoTransformer {
         qualified: qualifiedName,
       },
       signature: method.signature,
-      docs: this.buildDocs(method.doc),
-      params: method.parameters.map((p) => this.transformParameter(p)),
-      returns: method.returns ? { type: method.returns } : undefined,
-      source: this.buildSourceLocation(type.sourceFile, method.startLine),
+      docs: this.withPromotedNote(this.buildDocs(method.doc), method.promotedFrom),
+      params,
+      returns,
+      typeRefs: this.buildTypeRefs(this.signatureTypes(params, returns), type.typeParams),
+      source: this.buildSourceLocation(method.sourceFile, method.startLine),
+      owners: this.ownersOf(method.sourceFile),
+      externalImplementation: method.externalImplementation,
+      chainable: isChainable(method, type.name) || undefined,
+      iterator: iteratorOf(returns?.typeExpr, method.name, {
+        receiver: method.receiver,
+        hasParams: params.length > 0,
+      }),
+      promotedFrom: method.promotedFrom,
       urls: {
         canonical: `/${qualifiedName}`,
       },
@@ -287,10 +906,13 @@ export class GoTransformer {
   /**
    * Transform a field to a member reference.
    */
-  private transformField(field: GoField, type: GoType): MemberReference {
+  private transformField(field: GoField, type: GoType): GoMemberReference {
     // In Go, exported symbols start with uppercase letter
     const isExported = /^[A-Z]/.test(field.name);
     const visibility = isExported ? "public" : "private";
+    const { prose, annotations } = field.doc
+      ? splitDocTags(field.doc, this.config.docTags ?? defaultDocTags)
+      : { prose: undefined, annotations: undefined };
 
     return {
       name: field.name,
@@ -298,20 +920,238 @@ export class GoTransformer {
       kind: "property",
       visibility,
       type: field.type,
+      typeExpr: this.resolveTypeExpr(
+        field.typeExpr ? structuredClone(field.typeExpr) : parseTypeExpr(field.type),
+      ),
+      doc: prose ? this.splitDeprecation(prose).prose.trim() || undefined : undefined,
+      deprecated: this.parseDeprecation(prose),
+      annotations,
+      defaults: field.defaults,
     };
   }
 
+  /**
+   * Resolve the visibility tier of a symbol from its `Visibility:` annotation,
+   * the configured name patterns, or the tier it inherits from its parent.
+   */
+  private tierOf(name: string, doc?: string, inherited: VisibilityTier = "public"): VisibilityTier {
+    const annotation = doc?.match(TIER_ANNOTATION);
+    if (annotation) {
+      return annotation[1] as VisibilityTier;
+    }
+
+    for (const [pattern, tier] of Object.entries(this.config.tiers ?? {})) {
+      if (matchesNamePattern(name, pattern)) {
+        return tier;
+      }
+    }
+
+    return inherited;
+  }
+
+  /**
+   * Whether a top-level symbol passes the configured symbol filters: it
+   * matches an include pattern, if there are any, and no exclude pattern.
+   */
+  private selected(name: string): boolean {
+    const include = this.config.includeSymbols ?? [];
+    if (include.length > 0 && !include.some((pattern) => matchesNamePattern(name, pattern))) {
+      return false;
+    }
+    return !this.excluded(name);
+  }
+
+  /**
+   * Whether a symbol or member (`Type.Name`) matches an exclude pattern.
+   */
+  private excluded(name: string): boolean {
+    return (this.config.excludeSymbols ?? []).some((pattern) => matchesNamePattern(name, pattern));
+  }
+
+  /**
+   * The category of a symbol from the configured name patterns, if any.
+   */
+  private categoryOf(symbol: GoSymbolRecord): string | undefined {
+    const categories = Object.entries(this.config.categories ?? {});
+    return categories.find(([pattern]) => matchesNamePattern(symbol.qualifiedName, pattern))?.[1];
+  }
+
+  /**
+   * Record a non-public tier on a symbol or member.
+   */
+  private withTier<T extends { tier?: VisibilityTier }>(record: T, tier: VisibilityTier): T {
+    if (tier !== "public") {
+      record.tier = tier;
+    }
+    return record;
+  }
+
+  /**
+   * Parse a Go `Deprecated:` paragraph from a doc comment.
+   */
+  private parseDeprecation(doc?: string): DeprecationInfo | undefined {
+    return doc ? this.splitDeprecation(doc).deprecated : undefined;
+  }
+
+  /**
+   * Split the deprecation notice off a doc comment: a paragraph starting with
+   * `Deprecated:`, or a legacy `DEPRECATED:` line, which runs to the end of
+   * its paragraph. Returns the remaining prose.
+   */
+  private splitDeprecation(doc: string): { prose: string; deprecated?: DeprecationInfo } {
+    const paragraphs = doc.split(/\n[ \t]*\n/);
+    for (const [index, paragraph] of paragraphs.entries()) {
+      // Code blocks may show deprecated APIs
+      if (/^[ \t]/.test(paragraph)) continue;
+      const text = paragraph.trim();
+      const match = text.match(/^(Deprecated|DEPRECATED):/m);
+      if (!match || (match[1] === "Deprecated" && match.index !== 0)) continue;
+
+      const message = text.slice(match.index! + match[0].length).replace(/\s+/g, " ").trim();
+      const before = text.slice(0, match.index).trimEnd();
+      const prose = [
+        ...paragraphs.slice(0, index),
+        ...(before ? [before] : []),
+        ...paragraphs.slice(index + 1),
+      ].join("\n\n");
+      return { prose, deprecated: { isDeprecated: true, message: message || undefined } };
+    }
+    return { prose: doc };
+  }
+
+  /**
+   * Resolve the API a deprecation message tells to use instead ("Use X
+   * instead", "use [pkg.Name]") and record it as `replacedBy`. Deprecated
+   * fields resolve against the fields and methods of their type first.
+   */
+  private resolveReplacement(
+    deprecated: GoDeprecationInfo,
+    symbolIds: Set<string>,
+    file?: string,
+    parent?: GoSymbolRecord,
+  ): void {
+    const match = deprecated.message?.match(/\b[Uu]se\s+\[?\*?([\w/.]+)/);
+    const name = match?.[1].replace(/\.+$/, "");
+    if (!name) return;
+
+    const sibling = parent?.members?.find((m) => m.name === name);
+    const target = sibling?.refId
+      ? { refId: sibling.refId }
+      : this.resolveDocLink(name, symbolIds, file);
+    if (target) {
+      deprecated.replacement = name;
+      deprecated.replacedBy = { name, ...target };
+    }
+  }
+
   /**
    * Transform a parameter.
    */
-  private transformParameter(param: GoParameter): SymbolParam {
+  private transformParameter(param: GoParameter): GoSymbolParam {
     return {
       name: param.name,
       type: param.type,
+      typeExpr: this.resolveTypeExpr(parseTypeExpr(param.type)),
       required: true,
     };
   }
 
+  /**
+   * Transform a result list. Multiple results become a tuple.
+   */
+  private transformReturns(returns: string): GoSymbolReturns | undefined {
+    if (!returns) return undefined;
+
+    const results = parseResultTypes(returns).map((r) => this.resolveTypeExpr(r));
+    return {
+      type: returns,
+      typeExpr: results.length === 1 ? results[0] : { kind: "tuple", elems: results },
+    };
+  }
+
+  /**
+   * Build the relations of a type: the exported types a struct or interface
+   * embeds (`extends`) and the interfaces it's asserted to implement.
+   */
+  private buildRelations(type: GoType): SymbolRelations | undefined {
+    const relations: SymbolRelations = {};
+    if (type.interfaceBody !== undefined) {
+      const literal = parseTypeExpr(`interface {${type.interfaceBody}}`);
+      const embedded = literal.kind === "interface" ? literal.embeds : [];
+      const names = embedded.flatMap((e) =>
+        e.kind === "named" && (e.package || /^[A-Z]/.test(e.name))
+          ? [e.package ? `${e.package}.${e.name}` : e.name]
+          : [],
+      );
+      if (names.length > 0) relations.extends = names;
+    }
+    if (type.structEmbeds) relations.extends = type.structEmbeds;
+    if (type.implements) relations.implements = type.implements;
+    return Object.keys(relations).length > 0 ? relations : undefined;
+  }
+
+  /**
+   * Transform type parameters to IR type params.
+   */
+  private transformTypeParams(typeParams?: GoTypeParam[]): TypeParam[] | undefined {
+    if (!typeParams?.length) return undefined;
+    return typeParams.map((tp) => ({ name: tp.name, constraint: tp.constraint }));
+  }
+
+  /**
+   * Collect the structured types of a signature's parameters and results.
+   */
+  private signatureTypes(params: GoSymbolParam[], returns?: GoSymbolReturns): GoTypeExpr[] {
+    const types = params.map((p) => p.typeExpr);
+    if (returns) types.push(returns.typeExpr);
+    return types;
+  }
+
+  /**
+   * Link named types declared in the extracted package to their symbol IDs.
+   */
+  private resolveTypeExpr(expr: GoTypeExpr): GoTypeExpr {
+    for (const named of collectNamedTypes(expr)) {
+      const refId = !named.package ? this.typeIds.get(named.name) : undefined;
+      if (refId) {
+        named.refId = refId;
+      }
+    }
+    return expr;
+  }
+
+  /**
+   * Build the flat list of linkable type references used by a symbol.
+   * Predeclared types and type parameters are not linkable and are skipped.
+   */
+  private buildTypeRefs(
+    exprs: GoTypeExpr[],
+    typeParams?: GoTypeParam[],
+  ): TypeReference[] | undefined {
+    const skip = new Set(typeParams?.map((tp) => tp.name));
+    const refs = new Map<string, TypeReference>();
+
+    for (const expr of exprs) {
+      for (const named of collectNamedTypes(this.resolveTypeExpr(expr))) {
+        if (named.builtin || skip.has(named.name)) continue;
+        if (!named.package && !named.refId) continue;
+
+        const qualifiedName = named.package ? `${named.package}.${named.name}` : named.name;
+        if (refs.has(qualifiedName)) continue;
+
+        const ref: TypeReference = { name: named.name, qualifiedName };
+        if (named.refId) {
+          ref.refId = named.refId;
+        } else {
+          ref.external = true;
+        }
+        refs.set(qualifiedName, ref);
+      }
+    }
+
+    return refs.size > 0 ? Array.from(refs.values()) : undefined;
+  }
+
   /**
    * Map Go kind to IR kind.
    */
@@ -333,24 +1173,115 @@ export class GoTransformer {
   /**
    * Build the docs object for a symbol.
    */
-  private buildDocs(doc?: string): SymbolDocs {
-    if (!doc) {
-      return { summary: "" };
+  private buildDocs(rawDoc?: string): GoSymbolDocs {
+    const category = rawDoc?.match(CATEGORY_ANNOTATION)?.[1];
+    const annotated = rawDoc?.replace(TIER_ANNOTATION, "").replace(CATEGORY_ANNOTATION, "") ?? "";
+    const tagged = splitDocTags(annotated, this.config.docTags ?? defaultDocTags);
+    const { prose: untagged } = tagged;
+    // A `Category:` line is recorded like the `@category` tag
+    const annotations = category ? { ...tagged.annotations, category } : tagged.annotations;
+    const { prose, deprecated } = this.splitDeprecation(untagged);
+    const doc = prose.trim();
+    const docs: GoSymbolDocs = { summary: "" };
+
+    if (doc) {
+      const description = docToMarkdown(doc);
+      docs.summary = this.extractSummary(doc) || "";
+      docs.blocks = markCallouts(parseDocComment(doc));
+
+      // Add description if it's different from summary
+      if (description && description !== docs.summary) {
+        docs.description = description;
+      }
+    }
+    if (deprecated) {
+      docs.deprecated = deprecated;
+    }
+    if (annotations) {
+      docs.annotations = annotations;
     }
 
-    const summary = this.extractSummary(doc) || "";
-    const description = this.goDocToMarkdown(doc);
+    return docs;
+  }
 
-    const docs: SymbolDocs = {
-      summary,
-    };
+  /**
+   * Cut an oversized description down to the doc limit for the symbol's kind,
+   * marking it as truncated and linking to the full doc comment.
+   */
+  private truncateDocs(symbol: GoSymbolRecord): void {
+    const { description } = symbol.docs;
+    if (!description) return;
 
-    // Add description if it's different from summary
-    if (description && description !== summary) {
-      docs.description = description;
+    const limit = docLimitFor(this.config.docLimits ?? defaultDocLimits, symbol.kind);
+    const truncated = truncateDoc(description, limit);
+    if (truncated === undefined) return;
+
+    const { path, line } = symbol.source;
+    const url = this.buildSourceUrl(path, line);
+    const note = url
+      ? formatLabel(this.labels.truncatedNote, { url })
+      : formatLabel(this.labels.truncatedNoteNoUrl, { path });
+    symbol.docs.description = `${truncated}\n\n${note}`;
+    symbol.docs.truncated = true;
+    delete symbol.docs.blocks;
+    if (url) {
+      symbol.docs.fullDocsUrl = url;
     }
+  }
 
-    return docs;
+  /**
+   * The sidecar doc file name of a symbol: its ID, its ID without the
+   * package, or its qualified name.
+   */
+  private sidecarKeyOf(symbol: GoSymbolRecord): string | undefined {
+    const docs = this.result.sidecarDocs;
+    if (!docs) return undefined;
+    const keys = [symbol.id, symbol.id.slice(symbol.id.indexOf(":") + 1), symbol.qualifiedName];
+    return keys.find((key) => Object.hasOwn(docs, key));
+  }
+
+  /**
+   * Resolve the doc links of a symbol's "See also" sentences. Links that
+   * don't resolve and links to the symbol itself are left out.
+   */
+  private relatedSymbols(symbol: GoSymbolRecord, symbolIds: Set<string>): GoRelatedSymbol[] {
+    const related: GoRelatedSymbol[] = [];
+    for (const name of seeAlsoTargets(symbol.docs.blocks ?? [])) {
+      const target = this.resolveDocLink(name, symbolIds, symbol.source?.path);
+      if (target && target.refId !== symbol.id) {
+        related.push({ name, ...target });
+      }
+    }
+    return related;
+  }
+
+  /**
+   * Resolve a doc link target against the package scope and the imports of
+   * the file the doc comment is in. `Name` and `Type.Method` link to symbols
+   * of the package; `pkg.Name`, `pkg.Type.Method`, and `path/to/pkg.Name`
+   * link to pkg.go.dev, taking a package name that isn't imported as a
+   * standard library path.
+   */
+  private resolveDocLink(
+    target: string,
+    symbolIds: Set<string>,
+    file?: string,
+  ): DocLinkTarget | undefined {
+    const slash = target.lastIndexOf("/");
+    const dot = target.indexOf(".", slash + 1);
+    const qualifier = dot === -1 ? "" : target.slice(0, dot);
+    const name = dot === -1 ? target : target.slice(dot + 1);
+
+    if (!qualifier || (slash === -1 && this.typeIds.has(qualifier))) {
+      const refId = `${this.packageId}:${target.replace(/\./g, "_")}`;
+      return symbolIds.has(refId) ? { refId } : undefined;
+    }
+    if (slash === -1 && !/^[a-z]/.test(qualifier)) {
+      return undefined;
+    }
+    const imports = file ? this.result.imports?.[file] : undefined;
+    const importPath = slash === -1 ? (imports?.[qualifier] ?? qualifier) : qualifier;
+    return { url: `https://pkg.go.dev/${importPath}#${name}` };
   }
 
   /**
@@ -371,34 +1302,95 @@ export class GoTransformer {
   }
 
   /**
-   * Convert Go doc to Markdown.
+   * Build GitHub source URL.
    */
-  private goDocToMarkdown(doc?: string): string | undefined {
-    if (!doc) return undefined;
+  private buildSourceUrl(file: string, line: number): string {
+    if (!this.config.repo || !this.config.sha) {
+      return "";
+    }
+
+    return `https://github.com/${this.config.repo}/blob/${this.config.sha}/${file}#L${line}`;
+  }
 
-    return (
-      doc
-        // Convert indented lines to code blocks
-        .replace(/^(\t|    )(.+)$/gm, "```go\n$2\n```")
-        // Convert BUG(name): to warning
-        .replace(/^BUG\((\w+)\):\s*/gm, "**Bug ($1):** ")
-        // Convert DEPRECATED: to deprecation notice
-        .replace(/^DEPRECATED:\s*/gm, "**Deprecated:** ")
-        // Clean up consecutive code blocks
-        .replace(/```\n```go\n/g, "")
-        .trim()
+  /**
+   * Build the URL rendered docs link a symbol of the package with, from the
+   * symbol URL template or the symbol's canonical URL.
+   */
+  private buildSymbolUrl(symbol?: GoSymbolRecord): string | undefined {
+    const template = this.config.symbolUrlTemplate;
+    if (!symbol || !template) {
+      return symbol?.urls.canonical;
+    }
+
+    const values: Record<string, string> = {
+      package: this.config.packageName,
+      symbol: symbol.qualifiedName,
+      id: symbol.id,
+    };
+    return template.replace(/\{(\w+)\}/g, (placeholder, key: string) =>
+      key in values ? encodeURIComponent(values[key]) : placeholder,
     );
   }
 
   /**
-   * Build GitHub source URL.
+   * Build the "report a doc issue" URL for a symbol from the feedback template.
+   * Returns undefined when the template needs a repository and none is configured.
    */
-  private buildSourceUrl(file: string, line: number): string {
-    if (!this.config.repo || !this.config.sha) {
-      return "";
+  private buildFeedbackUrl(symbol: GoSymbolRecord): string | undefined {
+    const template = this.config.feedbackUrlTemplate ?? defaultFeedbackUrlTemplate;
+    if (template.includes("{repo}") && !this.config.repo) {
+      return undefined;
     }
 
-    return `https://github.com/${this.config.repo}/blob/${this.config.sha}/${file}#L${line}`;
+    const { path, line } = symbol.source;
+    const owners = symbol.owners ?? [];
+    const sourceUrl = this.buildSourceUrl(path, line);
+
+    const body = [
+      `Documentation issue for \`${symbol.qualifiedName}\``,
+      "",
+      `Source: ${sourceUrl || `${path}:${line}`}`,
+      ...(owners.length ? [`Owners: ${owners.join(" ")}`] : []),
+      "",
+      "Describe the problem:",
+    ].join("\n");
+
+    const values: Record<string, string> = {
+      repo: this.config.repo,
+      sha: this.config.sha,
+      file: path,
+      line: String(line),
+      symbol: symbol.qualifiedName,
+      owners: owners.join(","),
+      title: `Docs: ${symbol.qualifiedName}`,
+      body,
+    };
+
+    return template.replace(/\{(\w+)\}/g, (placeholder, key: string) =>
+      key in values ? (key === "repo" ? values[key] : encodeURIComponent(values[key])) : placeholder,
+    );
+  }
+
+  /**
+   * Get the owners of a source file, if any are known.
+   */
+  private ownersOf(file: string): string[] | undefined {
+    const owners = this.result.ownership?.fileOwners[file];
+    return owners?.length ? owners : undefined;
+  }
+
+  /**
+   * Get the paths of the duplicate copies of a source file, if its package was
+   * deduplicated.
+   */
+  private aliasesOf(file?: string): string[] | undefined {
+    if (!file || !this.result.duplicates) return undefined;
+
+    const slash = file.lastIndexOf("/");
+    const dir = slash === -1 ? "" : file.slice(0, slash);
+    const name = file.slice(slash + 1);
+    const duplicate = this.result.duplicates.find((d) => d.canonical === dir);
+    return duplicate?.aliases.map((alias) => (alias ? `${alias}/${name}` : name));
   }
 
   /**
@@ -413,3 +1405,14 @@ export class GoTransformer {
     };
   }
 }
+
+/**
+ * Whether a symbol name matches a pattern, where `*` matches any text.
+ */
+function matchesNamePattern(name: string, pattern: string): boolean {
+  return new RegExp(`^${pattern.split("*").map(escapeRegExp).join(".*")}$`).test(name);
+}
+
+function escapeRegExp(text: string): string {
+  return text.replace(/[.+?^${}()|[\]\\]/g, "\\$&");
+}
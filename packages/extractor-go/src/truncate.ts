@@ -0,0 +1,71 @@
+/**
+ * Doc Truncation
+ *
+ * Some packages, generated ones in particular, embed entire specifications in
+ * their doc comments. Docs over a size limit are cut down to their synopsis
+ * and first few sections so a single symbol can't dominate the output.
+ */
+
+import type { SymbolKind } from "@langchain/ir-schema";
+
+/**
+ * Size limit for a symbol's documentation.
+ */
+export interface DocLimit {
+  /** Maximum length of the description in characters */
+  maxChars: number;
+  /** Maximum number of `# Heading` sections kept after the introduction */
+  maxSections: number;
+}
+
+/**
+ * Doc limits: a default for every symbol plus overrides by symbol kind.
+ */
+export interface DocLimits {
+  default: DocLimit;
+  kinds?: Partial<Record<SymbolKind, DocLimit>>;
+}
+
+/**
+ * Default doc limits. Types carry the package's conceptual docs more often
+ * than functions, so they get more room.
+ */
+export const defaultDocLimits: DocLimits = {
+  default: { maxChars: 8_000, maxSections: 4 },
+  kinds: {
+    class: { maxChars: 20_000, maxSections: 8 },
+    interface: { maxChars: 20_000, maxSections: 8 },
+  },
+};
+
+/**
+ * Get the limit that applies to a symbol kind.
+ */
+export function docLimitFor(limits: DocLimits, kind: SymbolKind): DocLimit {
+  return limits.kinds?.[kind] ?? limits.default;
+}
+
+/**
+ * Truncate a Markdown description to a limit. Keeps the introduction (whose
+ * first paragraph is the synopsis) and the first `maxSections` sections, then
+ * drops whole paragraphs from the end until the text fits `maxChars`. The first
+ * paragraph is always kept. Returns undefined when the text is within the limit.
+ */
+export function truncateDoc(text: string, limit: DocLimit): string | undefined {
+  if (text.length <= limit.maxChars) {
+    return undefined;
+  }
+
+  const [intro, ...sections] = text.split(/\n(?=# )/);
+  const kept = [intro, ...sections.slice(0, limit.maxSections)].join("\n").trimEnd();
+
+  const paragraphs = kept.split(/\n\s*\n/);
+  let result = paragraphs[0];
+  for (const paragraph of paragraphs.slice(1)) {
+    const next = `${result}\n\n${paragraph}`;
+    if (next.length > limit.maxChars) break;
+    result = next;
+  }
+
+  return result;
+}
@@ -0,0 +1,559 @@
+/**
+ * Go Type Expressions
+ *
+ * Parses Go type expressions (as they appear in signatures) into a
+ * structured tree so that every named component can be cross-linked.
+ */
+
+/**
+ * A parsed Go type expression.
+ */
+export type GoTypeExpr =
+  | GoNamedTypeExpr
+  | { kind: "pointer"; elem: GoTypeExpr }
+  | { kind: "slice"; elem: GoTypeExpr }
+  | { kind: "array"; length: string; elem: GoTypeExpr }
+  | { kind: "map"; key: GoTypeExpr; value: GoTypeExpr }
+  | { kind: "chan"; dir: "both" | "send" | "recv"; elem: GoTypeExpr }
+  | { kind: "variadic"; elem: GoTypeExpr }
+  | { kind: "func"; params: GoTypeExpr[]; results: GoTypeExpr[] }
+  | { kind: "tuple"; elems: GoTypeExpr[] }
+  | { kind: "struct"; fields: GoStructFieldExpr[] }
+  | { kind: "interface"; methods: GoInterfaceMethodExpr[]; embeds: GoTypeExpr[] }
+  | { kind: "raw"; text: string };
+
+/**
+ * A field of an anonymous struct type.
+ */
+export interface GoStructFieldExpr {
+  /** Field name; for embedded fields, the name of the embedded type */
+  name: string;
+  type: GoTypeExpr;
+  tag?: string;
+  doc?: string;
+  embedded?: boolean;
+}
+
+/**
+ * A method of an anonymous interface type.
+ */
+export interface GoInterfaceMethodExpr {
+  name: string;
+  params: GoTypeExpr[];
+  results: GoTypeExpr[];
+  doc?: string;
+}
+
+/**
+ * A named type, optionally package-qualified and instantiated with type arguments.
+ */
+export interface GoNamedTypeExpr {
+  kind: "named";
+  name: string;
+  /** Package qualifier (e.g., "context" in context.Context) */
+  package?: string;
+  /** Type arguments of a generic instantiation (e.g., [int] in Result[int]) */
+  typeArgs?: GoTypeExpr[];
+  /** Whether the name is a predeclared Go type */
+  builtin?: boolean;
+  /** Symbol ID when the type is declared in the extracted package */
+  refId?: string;
+}
+
+/**
+ * Predeclared Go types.
+ */
+const BUILTIN_TYPES = new Set([
+  "any",
+  "bool",
+  "byte",
+  "comparable",
+  "complex64",
+  "complex128",
+  "error",
+  "float32",
+  "float64",
+  "int",
+  "int8",
+  "int16",
+  "int32",
+  "int64",
+  "rune",
+  "string",
+  "uint",
+  "uint8",
+  "uint16",
+  "uint32",
+  "uint64",
+  "uintptr",
+]);
+
+/**
+ * Check if a name is a predeclared Go type.
+ */
+export function isBuiltinType(name: string): boolean {
+  return BUILTIN_TYPES.has(name);
+}
+
+/**
+ * Parse a single Go type expression.
+ */
+export function parseTypeExpr(text: string): GoTypeExpr {
+  const type = text.trim();
+
+  if (type.startsWith("...")) {
+    return { kind: "variadic", elem: parseTypeExpr(type.slice(3)) };
+  }
+
+  if (type.startsWith("*")) {
+    return { kind: "pointer", elem: parseTypeExpr(type.slice(1)) };
+  }
+
+  if (type.startsWith("[]")) {
+    return { kind: "slice", elem: parseTypeExpr(type.slice(2)) };
+  }
+
+  if (type.startsWith("[")) {
+    const close = findClosing(type, 0, "[", "]");
+    return {
+      kind: "array",
+      length: type.slice(1, close).trim(),
+      elem: parseTypeExpr(type.slice(close + 1)),
+    };
+  }
+
+  if (/^map\s*\[/.test(type)) {
+    const open = type.indexOf("[");
+    const close = findClosing(type, open, "[", "]");
+    return {
+      kind: "map",
+      key: parseTypeExpr(type.slice(open + 1, close)),
+      value: parseTypeExpr(type.slice(close + 1)),
+    };
+  }
+
+  if (type.startsWith("<-chan")) {
+    return { kind: "chan", dir: "recv", elem: parseTypeExpr(type.slice(6)) };
+  }
+  if (/^chan\s*<-/.test(type)) {
+    return { kind: "chan", dir: "send", elem: parseTypeExpr(type.replace(/^chan\s*<-/, "")) };
+  }
+  if (/^chan\b/.test(type)) {
+    return { kind: "chan", dir: "both", elem: parseTypeExpr(type.slice(4)) };
+  }
+
+  if (/^func\s*\(/.test(type)) {
+    const open = type.indexOf("(");
+    const close = findClosing(type, open, "(", ")");
+    return {
+      kind: "func",
+      params: parseParamTypes(type.slice(open + 1, close)),
+      results: parseResultTypes(type.slice(close + 1)),
+    };
+  }
+
+  if (/^interface\s*\{/.test(type)) {
+    return parseInterfaceBody(literalBody(type));
+  }
+  if (/^struct\s*\{/.test(type)) {
+    return parseStructBody(literalBody(type));
+  }
+
+  if (type.startsWith("(")) {
+    const elems = parseResultTypes(type);
+    return elems.length === 1 ? elems[0] : { kind: "tuple", elems };
+  }
+
+  const named = type.match(/^(?:(\w+)\.)?(\w+)(\[[\s\S]*\])?$/);
+  if (named) {
+    const [, pkg, name, args] = named;
+    const expr: GoNamedTypeExpr = { kind: "named", name };
+    if (pkg) {
+      expr.package = pkg;
+    } else if (isBuiltinType(name)) {
+      expr.builtin = true;
+    }
+    if (args) {
+      expr.typeArgs = splitTopLevel(args.slice(1, -1)).map(parseTypeExpr);
+    }
+    return expr;
+  }
+
+  return { kind: "raw", text: type };
+}
+
+/**
+ * Parse a result list such as `error` or `([]byte, error)`.
+ * Named results (`(n int, err error)`) keep only their types.
+ */
+export function parseResultTypes(text: string): GoTypeExpr[] {
+  const trimmed = text.trim();
+  if (!trimmed) return [];
+
+  if (trimmed.startsWith("(") && findClosing(trimmed, 0, "(", ")") === trimmed.length - 1) {
+    return parseParamTypes(trimmed.slice(1, -1));
+  }
+
+  return [parseTypeExpr(trimmed)];
+}
+
+/**
+ * Parse the types of a parameter list, dropping parameter names.
+ * Handles grouped names such as `a, b string`.
+ */
+export function parseParamTypes(text: string): GoTypeExpr[] {
+  const parts = splitTopLevel(text);
+  const named = parts.some((p) => splitNameAndType(p) !== undefined);
+  const types: GoTypeExpr[] = [];
+  let pending = 0;
+
+  for (const part of parts) {
+    if (!named) {
+      types.push(parseTypeExpr(part));
+      continue;
+    }
+
+    const split = splitNameAndType(part);
+    if (!split) {
+      // A bare name sharing the type of the next parameter
+      pending++;
+      continue;
+    }
+
+    const type = parseTypeExpr(split.type);
+    for (; pending > 0; pending--) {
+      types.push(type);
+    }
+    types.push(type);
+  }
+
+  return types;
+}
+
+/**
+ * Collect every named type referenced by an expression, depth first.
+ */
+export function collectNamedTypes(expr: GoTypeExpr): GoNamedTypeExpr[] {
+  const named: GoNamedTypeExpr[] = [];
+
+  const visit = (e: GoTypeExpr): void => {
+    switch (e.kind) {
+      case "named":
+        named.push(e);
+        e.typeArgs?.forEach(visit);
+        break;
+      case "pointer":
+      case "slice":
+      case "array":
+      case "chan":
+      case "variadic":
+        visit(e.elem);
+        break;
+      case "map":
+        visit(e.key);
+        visit(e.value);
+        break;
+      case "func":
+        e.params.forEach(visit);
+        e.results.forEach(visit);
+        break;
+      case "tuple":
+        e.elems.forEach(visit);
+        break;
+      case "struct":
+        e.fields.forEach((f) => visit(f.type));
+        break;
+      case "interface":
+        e.embeds.forEach(visit);
+        e.methods.forEach((m) => {
+          m.params.forEach(visit);
+          m.results.forEach(visit);
+        });
+        break;
+      default:
+        break;
+    }
+  };
+
+  visit(expr);
+  return named;
+}
+
+/**
+ * Render a type expression as single-line Go source.
+ */
+export function formatTypeExpr(expr: GoTypeExpr): string {
+  switch (expr.kind) {
+    case "named": {
+      const name = expr.package ? `${expr.package}.${expr.name}` : expr.name;
+      return expr.typeArgs ? `${name}[${expr.typeArgs.map(formatTypeExpr).join(", ")}]` : name;
+    }
+    case "pointer":
+      return `*${formatTypeExpr(expr.elem)}`;
+    case "slice":
+      return `[]${formatTypeExpr(expr.elem)}`;
+    case "array":
+      return `[${expr.length}]${formatTypeExpr(expr.elem)}`;
+    case "map":
+      return `map[${formatTypeExpr(expr.key)}]${formatTypeExpr(expr.value)}`;
+    case "chan": {
+      const prefix = expr.dir === "recv" ? "<-chan" : expr.dir === "send" ? "chan<-" : "chan";
+      return `${prefix} ${formatTypeExpr(expr.elem)}`;
+    }
+    case "variadic":
+      return `...${formatTypeExpr(expr.elem)}`;
+    case "func":
+      return `func(${expr.params.map(formatTypeExpr).join(", ")})${formatResults(expr.results)}`;
+    case "tuple":
+      return `(${expr.elems.map(formatTypeExpr).join(", ")})`;
+    case "struct": {
+      const fields = expr.fields.map((f) => {
+        const type = formatTypeExpr(f.type);
+        const decl = f.embedded ? type : `${f.name} ${type}`;
+        return f.tag ? `${decl} \`${f.tag}\`` : decl;
+      });
+      return fields.length ? `struct{ ${fields.join("; ")} }` : "struct{}";
+    }
+    case "interface": {
+      const elems = [
+        ...expr.embeds.map(formatTypeExpr),
+        ...expr.methods.map(
+          (m) => `${m.name}(${m.params.map(formatTypeExpr).join(", ")})${formatResults(m.results)}`,
+        ),
+      ];
+      return elems.length ? `interface{ ${elems.join("; ")} }` : "interface{}";
+    }
+    case "raw":
+      return expr.text;
+  }
+}
+
+function formatResults(results: GoTypeExpr[]): string {
+  if (results.length === 0) return "";
+  if (results.length === 1) return ` ${formatTypeExpr(results[0])}`;
+  return ` (${results.map(formatTypeExpr).join(", ")})`;
+}
+
+/**
+ * Get the text between the outer braces of a struct or interface literal.
+ */
+function literalBody(type: string): string {
+  const open = type.indexOf("{");
+  const close = findClosing(type, open, "{", "}");
+  return type.slice(open + 1, close);
+}
+
+/**
+ * Split a struct or interface body into declarations, pairing each with
+ * the `//` comment lines directly above it, or else its trailing comment.
+ */
+function splitDeclarations(body: string): Array<{ decl: string; doc?: string }> {
+  const decls: Array<{ decl: string; doc?: string }> = [];
+  let docLines: string[] = [];
+  let trailing: string | undefined;
+  let current = "";
+  let depth = 0;
+
+  const flush = () => {
+    const decl = current.trim();
+    if (decl) {
+      decls.push({ decl, doc: docLines.length ? docLines.join("\n") : trailing });
+      docLines = [];
+    }
+    current = "";
+  };
+
+  for (const line of body.split("\n")) {
+    const trimmed = line.trim();
+    if (depth === 0 && trimmed.startsWith("//")) {
+      docLines.push(trimmed.replace(/^\/\/\s*/, ""));
+      continue;
+    }
+    if (depth === 0 && !trimmed) {
+      docLines = [];
+      continue;
+    }
+
+    // Brackets and semicolons only count outside comments, strings and tags
+    const { code, comment } = splitLineComment(line);
+    let quote: string | undefined;
+    for (let i = 0; i < code.length; i++) {
+      const char = code[i];
+      if (quote) {
+        if (char === "\\" && quote !== "`") {
+          current += char + (code[i + 1] ?? "");
+          i++;
+          continue;
+        }
+        if (char === quote) quote = undefined;
+      } else if (char === '"' || char === "`" || char === "'") {
+        quote = char;
+      } else if (char === "(" || char === "[" || char === "{") {
+        depth++;
+      } else if (char === ")" || char === "]" || char === "}") {
+        depth--;
+      } else if (char === ";" && depth === 0) {
+        flush();
+        continue;
+      }
+      current += char;
+    }
+
+    if (depth === 0) {
+      trailing = comment;
+      flush();
+      trailing = undefined;
+    } else {
+      // Comments inside a nested literal belong to its own declarations
+      current += line.slice(code.length) + "\n";
+    }
+  }
+  flush();
+
+  return decls;
+}
+
+/**
+ * Split a line into its code and trailing `//` comment, ignoring `//`
+ * inside strings and struct tags.
+ */
+function splitLineComment(line: string): { code: string; comment?: string } {
+  let quote: string | undefined;
+  for (let i = 0; i < line.length; i++) {
+    const char = line[i];
+    if (quote) {
+      if (char === "\\" && quote !== "`") i++;
+      else if (char === quote) quote = undefined;
+    } else if (char === '"' || char === "`" || char === "'") {
+      quote = char;
+    } else if (char === "/" && line[i + 1] === "/") {
+      return { code: line.slice(0, i), comment: line.slice(i + 2).trim() || undefined };
+    }
+  }
+  return { code: line };
+}
+
+/**
+ * Parse the body of an anonymous struct type.
+ */
+function parseStructBody(body: string): GoTypeExpr {
+  const fields: GoStructFieldExpr[] = [];
+
+  for (const { decl, doc } of splitDeclarations(body)) {
+    const tagMatch = decl.match(/\s*`([^`]*)`\s*$/);
+    const tag = tagMatch?.[1];
+    const text = tagMatch ? decl.slice(0, tagMatch.index).trim() : decl;
+
+    const named = text.match(/^(\w+(?:\s*,\s*\w+)*)\s+(\S[\s\S]*)$/);
+    if (named && !["chan", "func", "map", "struct", "interface"].includes(named[1])) {
+      const type = parseTypeExpr(named[2]);
+      for (const name of named[1].split(/\s*,\s*/)) {
+        fields.push({ name, type, tag, doc });
+      }
+      continue;
+    }
+
+    // Embedded field: the field name is the type name
+    const type = parseTypeExpr(text);
+    const name = text.replace(/^\*/, "").replace(/\[[\s\S]*$/, "").split(".").pop() ?? text;
+    fields.push({ name, type, tag, doc, embedded: true });
+  }
+
+  return { kind: "struct", fields };
+}
+
+/**
+ * Parse the body of an anonymous interface type.
+ */
+function parseInterfaceBody(body: string): GoTypeExpr {
+  const methods: GoInterfaceMethodExpr[] = [];
+  const embeds: GoTypeExpr[] = [];
+
+  for (const { decl, doc } of splitDeclarations(body)) {
+    const method = decl.match(/^(\w+)\s*\(/);
+    if (!method) {
+      embeds.push(parseTypeExpr(decl));
+      continue;
+    }
+
+    const open = decl.indexOf("(");
+    const close = findClosing(decl, open, "(", ")");
+    methods.push({
+      name: method[1],
+      params: parseParamTypes(decl.slice(open + 1, close)),
+      results: parseResultTypes(decl.slice(close + 1)),
+      doc,
+    });
+  }
+
+  return { kind: "interface", methods, embeds };
+}
+
+/**
+ * Split a `name Type` parameter into its parts, or return undefined
+ * when the text is a bare type or a bare name.
+ */
+function splitNameAndType(param: string): { name: string; type: string } | undefined {
+  const match = param.match(/^(\w+)\s+(\S[\s\S]*)$/);
+  if (!match || ["chan", "func", "map", "struct", "interface"].includes(match[1])) {
+    return undefined;
+  }
+  return { name: match[1], type: match[2] };
+}
+
+/**
+ * Split by commas that are not nested inside brackets, braces, or parentheses.
+ */
+export function splitTopLevel(text: string): string[] {
+  const result: string[] = [];
+  let current = "";
+  let depth = 0;
+
+  for (const char of text) {
+    if (char === "(" || char === "[" || char === "{") {
+      depth++;
+    } else if (char === ")" || char === "]" || char === "}") {
+      depth--;
+    } else if (char === "," && depth === 0) {
+      if (current.trim()) result.push(current.trim());
+      current = "";
+      continue;
+    }
+    current += char;
+  }
+
+  if (current.trim()) {
+    result.push(current.trim());
+  }
+
+  return result;
+}
+
+/**
+ * Find the index of the bracket closing the one at `openIndex`, skipping
+ * strings, struct tags and line comments.
+ */
+function findClosing(text: string, openIndex: number, open: string, close: string): number {
+  let depth = 0;
+  let quote: string | undefined;
+  for (let i = openIndex; i < text.length; i++) {
+    const char = text[i];
+    if (quote) {
+      if (char === "\\" && quote !== "`") i++;
+      else if (char === quote) quote = undefined;
+    } else if (char === '"' || char === "`" || char === "'") {
+      quote = char;
+    } else if (char === "/" && text[i + 1] === "/") {
+      // Skip line comments of multi-line literals
+      const end = text.indexOf("\n", i);
+      if (end === -1) break;
+      i = end;
+    } else if (char === open) {
+      depth++;
+    } else if (char === close) {
+      depth--;
+      if (depth === 0) return i;
+    }
+  }
+  return text.length - 1;
+}
@@ -0,0 +1,161 @@
+/**
+ * Unified Reference Output
+ *
+ * Maps an extraction onto the reference schema the TypeScript and Python
+ * extractors emit, so the docs renderer treats every language alike: the
+ * same node kinds, the same link model (`typeRefs` with a `refId` inside the
+ * package and `external` outside it, Markdown links in prose), and the same
+ * deprecation shape. Go-only fields are left out, and Go concepts without a
+ * counterpart map onto the closest shared one:
+ *
+ * - structs are classes, and constants are variables
+ * - a method's receiver is its parent type, like `self` or `this`: the
+ *   method is `Type.Method`, listed in the type's members, and the receiver
+ *   isn't a parameter
+ * - methods promoted from unexported embedded types are methods of the type
+ * - doc links resolve to canonical URLs in the Markdown description, and
+ *   sidecar docs are appended to it
+ * - a deprecation's successor is its `replacement` name
+ */
+
+import type {
+  DeprecationInfo,
+  MemberReference,
+  SymbolDocs,
+  SymbolRecord,
+  SymbolTags,
+} from "@langchain/ir-schema";
+import type { ExtractorOutput, OutputPackage } from "./output.js";
+import type { Provenance } from "./provenance.js";
+import type { GoDeprecationInfo, GoSymbolRecord } from "./transformer.js";
+import { renderMarkdown } from "./markdown.js";
+
+/**
+ * Package header shared by the extractors.
+ */
+export type UnifiedPackage = Pick<
+  OutputPackage,
+  "packageId" | "displayName" | "publishedName" | "language" | "ecosystem" | "version" | "repo"
+>;
+
+/**
+ * An extraction in the cross-language reference schema.
+ */
+export interface UnifiedOutput {
+  package: UnifiedPackage;
+  provenance?: Provenance;
+  symbols: SymbolRecord[];
+}
+
+/**
+ * Map a full output onto the cross-language reference schema.
+ */
+export function unifyOutput(output: ExtractorOutput): UnifiedOutput {
+  const symbols = output.symbols as GoSymbolRecord[];
+  const urls = new Map(symbols.map((symbol) => [symbol.id, symbol.urls.canonical]));
+  const { packageId, displayName, publishedName, language, ecosystem, version, repo } =
+    output.package;
+  return {
+    package: { packageId, displayName, publishedName, language, ecosystem, version, repo },
+    provenance: output.provenance,
+    symbols: symbols.map((symbol) => unifySymbol(symbol, (refId) => urls.get(refId))),
+  };
+}
+
+/**
+ * Map a symbol onto the cross-language schema, resolving doc links to
+ * symbols of the package with `symbolUrl`.
+ */
+export function unifySymbol(
+  symbol: GoSymbolRecord,
+  symbolUrl: (refId: string) => string | undefined = () => undefined,
+): SymbolRecord {
+  const record: SymbolRecord = {
+    id: symbol.id,
+    packageId: symbol.packageId,
+    language: symbol.language,
+    kind: symbol.kind,
+    name: symbol.name,
+    qualifiedName: symbol.qualifiedName,
+    display: { name: symbol.display.name, qualified: symbol.display.qualified },
+    signature: symbol.signature,
+    docs: unifyDocs(symbol, symbolUrl),
+    source: {
+      repo: symbol.source.repo,
+      sha: symbol.source.sha,
+      path: symbol.source.path,
+      line: symbol.source.line,
+      endLine: symbol.source.endLine,
+    },
+    urls: { canonical: symbol.urls.canonical, anchors: symbol.urls.anchors },
+    tags: unifyTags(symbol.tags),
+  };
+  if (symbol.params) {
+    record.params = symbol.params.map((param) => ({
+      name: param.name,
+      type: param.type,
+      description: param.description,
+      default: param.default,
+      required: param.required,
+    }));
+  }
+  if (symbol.returns) {
+    record.returns = { type: symbol.returns.type, description: symbol.returns.description };
+  }
+  if (symbol.typeRefs) record.typeRefs = symbol.typeRefs;
+  if (symbol.typeParams) record.typeParams = symbol.typeParams;
+  if (symbol.members) record.members = symbol.members.map(unifyMember);
+  if (symbol.relations) record.relations = symbol.relations;
+  if (symbol.versionInfo) record.versionInfo = symbol.versionInfo;
+  return record;
+}
+
+/**
+ * Docs in the shared shape: Markdown prose with resolved links.
+ */
+function unifyDocs(
+  symbol: GoSymbolRecord,
+  symbolUrl: (refId: string) => string | undefined,
+): SymbolDocs {
+  const { summary, blocks, description, html, sidecar, examples, deprecated } = symbol.docs;
+  const prose = [blocks ? renderMarkdown(blocks, { symbolUrl }) : description, sidecar?.markdown];
+  const docs: SymbolDocs = { summary };
+  const text = prose.filter(Boolean).join("\n\n");
+  if (text) docs.description = text;
+  if (html) docs.descriptionHtml = html;
+  if (examples) {
+    docs.examples = examples.map(({ title, code, language }) => ({ title, code, language }));
+  }
+  if (deprecated) {
+    docs.deprecated = unifyDeprecation(deprecated, symbol.versionInfo?.deprecation?.since);
+  }
+  return docs;
+}
+
+/**
+ * A deprecation notice in the shared shape, naming its successor.
+ */
+function unifyDeprecation(deprecated: GoDeprecationInfo, since?: string): DeprecationInfo {
+  return {
+    isDeprecated: true,
+    message: deprecated.message,
+    since: deprecated.since ?? since,
+    replacement: deprecated.replacedBy?.name ?? deprecated.replacement,
+  };
+}
+
+/**
+ * A member reference without the Go-specific fields.
+ */
+function unifyMember(member: MemberReference): MemberReference {
+  const { name, refId, kind, visibility, type, signature, params } = member;
+  return { name, refId, kind, visibility, type, signature, params };
+}
+
+/**
+ * Tags without the Go-specific fields.
+ */
+function unifyTags(tags: SymbolTags): SymbolTags {
+  const { stability, visibility, isAsync, isGenerator, isAbstract, isStatic } = tags;
+  return { stability, visibility, isAsync, isGenerator, isAbstract, isStatic };
+}
@@ -0,0 +1,119 @@
+/**
+ * Vendored Dependencies
+ *
+ * Detects third-party packages copied into a `vendor/` tree and, when they are
+ * extracted, moves their symbols into a separate `dependencies` namespace so
+ * they aren't mistaken for the module's own API.
+ */
+
+import type { GoSymbolRecord } from "./transformer.js";
+
+/**
+ * A module listed in `vendor/modules.txt`.
+ */
+export interface VendorModule {
+  path: string;
+  version?: string;
+}
+
+/**
+ * The vendored dependency a symbol comes from.
+ */
+export interface DependencyInfo {
+  /** Import path of the symbol's package, e.g. "github.com/pkg/errors" */
+  package: string;
+  /** Module providing the package, when listed in vendor/modules.txt */
+  module?: string;
+  version?: string;
+}
+
+/**
+ * Get the import path of a vendored source file, or undefined when the file
+ * isn't under a `vendor/` directory.
+ */
+export function vendorImportPath(path: string): string | undefined {
+  const dirs = path.split("/").slice(0, -1);
+  const vendor = dirs.lastIndexOf("vendor");
+  return vendor === -1 || vendor === dirs.length - 1
+    ? undefined
+    : dirs.slice(vendor + 1).join("/");
+}
+
+/**
+ * Parse the `# module version` lines of a `vendor/modules.txt` file.
+ */
+export function parseModulesTxt(content: string): VendorModule[] {
+  const modules: VendorModule[] = [];
+  for (const line of content.split("\n")) {
+    const match = line.match(/^# (\S+)(?: (v\S+))?/);
+    if (match) {
+      modules.push({ path: match[1], version: match[2] });
+    }
+  }
+  return modules;
+}
+
+/**
+ * Move the symbols of vendored packages into the `dependencies` namespace:
+ * their IDs, qualified names, and URLs are prefixed with the package's import
+ * path and they are tagged with the module they come from. Must run before
+ * symbols are deduplicated by ID, since vendored names often shadow the
+ * module's own.
+ */
+export function namespaceDependencies(
+  symbols: GoSymbolRecord[],
+  packageId: string,
+  modules: VendorModule[] = [],
+): void {
+  const idPrefix = `${packageId}:`;
+  const vendored = new Map<GoSymbolRecord, string>();
+  for (const symbol of symbols) {
+    const importPath = vendorImportPath(symbol.source.path);
+    if (importPath) vendored.set(symbol, importPath);
+  }
+  if (vendored.size === 0) return;
+
+  // Names defined by each vendored package, to keep references within it
+  const namesByPackage = new Map<string, Set<string>>();
+  for (const [symbol, importPath] of vendored) {
+    const names = namesByPackage.get(importPath) ?? new Set<string>();
+    names.add(symbol.id.slice(idPrefix.length));
+    namesByPackage.set(importPath, names);
+  }
+
+  for (const [symbol, importPath] of vendored) {
+    const names = namesByPackage.get(importPath)!;
+    const namespaced = (id: string) =>
+      `${idPrefix}dependencies/${importPath}/${id.slice(idPrefix.length)}`;
+
+    symbol.id = namespaced(symbol.id);
+    symbol.qualifiedName = `${importPath}.${symbol.qualifiedName}`;
+    symbol.display.qualified = symbol.qualifiedName;
+    symbol.urls.canonical = `/dependencies/${symbol.qualifiedName}`;
+    for (const member of symbol.members ?? []) {
+      member.refId = namespaced(member.refId);
+    }
+    for (const ref of symbol.typeRefs ?? []) {
+      if (!ref.refId) continue;
+      if (names.has(ref.refId.slice(idPrefix.length))) {
+        ref.refId = namespaced(ref.refId);
+      } else {
+        delete ref.refId;
+      }
+    }
+
+    const module = modules
+      .filter((m) => importPath === m.path || importPath.startsWith(`${m.path}/`))
+      .sort((a, b) => b.path.length - a.path.length)[0];
+    symbol.dependency = { package: importPath, module: module?.path, version: module?.version };
+  }
+
+  // Module symbols can't link to vendored types by their bare name any more
+  const ids = new Set(symbols.map((symbol) => symbol.id));
+  for (const symbol of symbols) {
+    if (vendored.has(symbol)) continue;
+    for (const ref of symbol.typeRefs ?? []) {
+      if (ref.refId && !ids.has(ref.refId)) delete ref.refId;
+    }
+  }
+}
@@ -0,0 +1,161 @@
+/**
+ * Example Verification
+ *
+ * Checks Example functions and marked doc comment code blocks against the
+ * extracted package, so examples that still call removed or renamed APIs
+ * are caught when the docs are built rather than by readers. References to
+ * the package (`pkg.Name` and `pkg.Type.Method`) must name declarations the
+ * package still has, and every Example function must document one.
+ */
+
+import { posix } from "path";
+
+import { parseDocComment } from "./doc-comment.js";
+import type { ExtractionResult } from "./extractor.js";
+
+/**
+ * First line of a doc comment code block that asks for verification.
+ */
+export const VERIFY_MARKER = "// verify";
+
+/**
+ * A reference of an example to an API the package doesn't have.
+ */
+export interface ExampleProblem {
+  /** Example function, or the symbol whose doc comment has the code block */
+  example: string;
+  /** Missing name, e.g. "Client.Fetch" */
+  reference: string;
+  sourceFile: string;
+  line: number;
+}
+
+const SELECTOR = /\b([A-Za-z_]\w*)\.([A-Z]\w*)(?:\[[^\]\n]*\])?(?:\.([A-Z]\w*))?/g;
+
+/**
+ * Find the references of examples and marked code blocks to APIs the
+ * package doesn't declare. Examples refer to the package through the name
+ * their file imports it as; code blocks through the package's own name.
+ */
+export function verifyExamples(result: ExtractionResult): ExampleProblem[] {
+  const declared = declaredNames(result);
+  const problems: ExampleProblem[] = [];
+
+  for (const example of result.examples ?? []) {
+    if (example.target && !declared.names.has(example.target)) {
+      problems.push({
+        example: example.name,
+        reference: example.target,
+        sourceFile: example.sourceFile,
+        line: example.startLine,
+      });
+    }
+    const dir = posix.dirname(example.sourceFile);
+    const importPath = dir === "." ? result.moduleName : `${result.moduleName}/${dir}`;
+    const imports = result.imports?.[example.sourceFile] ?? {};
+    const aliases = Object.keys(imports).filter((name) => imports[name] === importPath);
+    for (const [reference, offset] of missingReferences(example.code, aliases, declared)) {
+      problems.push({
+        example: example.name,
+        reference,
+        sourceFile: example.sourceFile,
+        // The code starts on the line after the function header
+        line: example.wholeFile ? offset + 1 : example.startLine + 1 + offset,
+      });
+    }
+  }
+
+  const documented = [
+    ...result.types.flatMap((type) => [
+      { name: type.name, doc: type.doc, file: type.sourceFile, line: type.startLine },
+      ...type.methods.map((method) => ({
+        name: `${type.name}.${method.name}`,
+        doc: method.doc,
+        file: method.sourceFile,
+        line: method.startLine,
+      })),
+    ]),
+    ...[...result.functions, ...result.constants].map((decl) => ({
+      name: decl.name,
+      doc: decl.doc,
+      file: decl.sourceFile,
+      line: decl.startLine,
+    })),
+  ];
+  for (const { name, doc, file, line } of documented) {
+    if (!doc?.includes(VERIFY_MARKER)) continue;
+    const packageName = result.packageNames?.[posix.dirname(file)];
+    for (const block of parseDocComment(doc)) {
+      if (block.kind !== "code" || !block.text.startsWith(VERIFY_MARKER)) continue;
+      const aliases = packageName ? [packageName] : [];
+      for (const [reference] of missingReferences(block.text, aliases, declared)) {
+        problems.push({ example: name, reference, sourceFile: file, line });
+      }
+    }
+  }
+
+  return problems;
+}
+
+/**
+ * Format example problems one per line, as `file:line: message`.
+ */
+export function formatExampleProblems(problems: ExampleProblem[]): string {
+  return problems
+    .map(
+      (problem) =>
+        `${problem.sourceFile}:${problem.line}: ${problem.example} refers to ` +
+        `${problem.reference}, which the package doesn't declare`,
+    )
+    .join("\n");
+}
+
+/**
+ * Names the package declares: types, functions, constants, variables, and
+ * `Type.Method` for methods, and which of them are types.
+ */
+interface DeclaredNames {
+  names: Set<string>;
+  types: Set<string>;
+}
+
+/**
+ * Collect the names the package declares.
+ */
+function declaredNames(result: ExtractionResult): DeclaredNames {
+  const names = new Set<string>();
+  const types = new Set<string>();
+  for (const type of result.types) {
+    names.add(type.name);
+    types.add(type.name);
+    for (const method of type.methods) names.add(`${type.name}.${method.name}`);
+  }
+  for (const decl of [...result.functions, ...result.constants]) names.add(decl.name);
+  return { names, types };
+}
+
+/**
+ * Find `alias.Name` and `alias.Type.Method` references to undeclared names,
+ * with the index of the line they are on.
+ */
+function missingReferences(
+  code: string,
+  aliases: string[],
+  declared: DeclaredNames,
+): Array<[string, number]> {
+  const missing: Array<[string, number]> = [];
+  for (const [index, line] of code.split("\n").entries()) {
+    // Comments and strings may mention anything
+    const source = line.replace(/"(?:[^"\\]|\\.)*"|`[^`]*`/g, '""').replace(/\/\/.*$/, "");
+    for (const [, alias, name, member] of source.matchAll(SELECTOR)) {
+      if (!aliases.includes(alias)) continue;
+      if (!declared.names.has(name)) {
+        missing.push([name, index]);
+      } else if (member && declared.types.has(name) && !declared.names.has(`${name}.${member}`)) {
+        // A method expression, like pkg.Client.Get
+        missing.push([`${name}.${member}`, index]);
+      }
+    }
+  }
+  return missing;
+}
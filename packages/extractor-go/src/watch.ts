@@ -0,0 +1,96 @@
+/**
+ * Watch Mode
+ *
+ * Watches a module for source changes and reports them in batches, for a
+ * fast local docs-preview loop: the CLI re-extracts with a warm parse cache,
+ * so only the changed files are parsed again, and rewrites only the package
+ * files of a split output whose contents changed.
+ */
+
+import { watch, type FSWatcher } from "fs";
+import { posix } from "path";
+
+/**
+ * Options of a source watcher.
+ */
+export interface WatchOptions {
+  /** How long to wait for more changes before reporting a batch (default: 100 ms) */
+  debounceMs?: number;
+}
+
+/**
+ * Files a rewrite of a split output writes and removes.
+ */
+export interface SplitChanges {
+  /** Files that are new or whose contents changed */
+  written: string[];
+  /** Files of the previous output that are gone */
+  removed: string[];
+}
+
+/**
+ * Default time to wait for more changes before reporting a batch.
+ */
+export const defaultWatchDebounceMs = 100;
+
+/**
+ * Whether a changed file affects the extraction: Go sources and go.mod.
+ */
+export function isWatchedFile(file: string): boolean {
+  return file.endsWith(".go") || posix.basename(file) === "go.mod";
+}
+
+/**
+ * Watch the Go sources under `root`, calling `onChange` with the paths of
+ * the changed files (relative to `root`, with forward slashes) once changes
+ * settle. Returns a function that stops watching.
+ */
+export function watchSources(
+  root: string,
+  onChange: (files: string[]) => void,
+  options: WatchOptions = {},
+): () => void {
+  const debounceMs = options.debounceMs ?? defaultWatchDebounceMs;
+  const pending = new Set<string>();
+  let timer: NodeJS.Timeout | undefined;
+
+  const watcher: FSWatcher = watch(root, { recursive: true }, (_event, filename) => {
+    if (!filename) return;
+    const file = filename.toString().replace(/\\/g, "/");
+    if (!isWatchedFile(file)) return;
+    pending.add(file);
+    clearTimeout(timer);
+    timer = setTimeout(() => {
+      const files = [...pending].sort();
+      pending.clear();
+      onChange(files);
+    }, debounceMs);
+  });
+
+  return () => {
+    clearTimeout(timer);
+    watcher.close();
+  };
+}
+
+/**
+ * The package directories changed files are in, sorted, with "." for the
+ * module root.
+ */
+export function affectedPackages(files: string[]): string[] {
+  return [...new Set(files.map((file) => posix.dirname(file)))].sort();
+}
+
+/**
+ * Compare two renderings of a split output, keyed by path: the files to
+ * write because they are new or changed, and the files to remove.
+ */
+export function splitChanges(
+  previous: Map<string, string>,
+  next: Map<string, string>,
+): SplitChanges {
+  return {
+    written: [...next].filter(([file, content]) => previous.get(file) !== content).map(([f]) => f),
+    removed: [...previous.keys()].filter((file) => !next.has(file)),
+  };
+}
@@ -0,0 +1,244 @@
+/**
+ * Worker Pool
+ *
+ * Runs tasks on a bounded number of worker threads, so parsing the files of
+ * modules with hundreds of packages uses every core, and bounds concurrent
+ * output writes. Workers start on demand, up to the pool size, and each
+ * takes one task at a time from a shared queue. Callers decide the order
+ * results are consumed in, so extractions stay deterministic whichever
+ * worker finishes first.
+ *
+ * Workers also contain runaway tasks: a task can be given a timeout, after
+ * which its worker is terminated, and workers started with a heap limit die
+ * alone when a task exceeds it. Either fails the task with a
+ * `WorkerLimitError` and the worker is replaced on demand.
+ *
+ * Only busy workers keep the process alive: a caller awaiting a task keeps
+ * it running until the reply, while idle workers of a pool that was never
+ * closed, like after a failed extraction, don't hold it open.
+ */
+
+import { availableParallelism } from "os";
+import { extname } from "path";
+import { fileURLToPath } from "url";
+import type { Worker } from "worker_threads";
+
+/**
+ * Entry point of the parse workers, with the extension of this module, so
+ * it resolves both from source and from a build.
+ */
+export const parseWorkerUrl = new URL(
+  `./parse-worker${extname(fileURLToPath(import.meta.url))}`,
+  import.meta.url,
+);
+
+/**
+ * Default number of workers: GOMAXPROCS if set, as `go` tooling does, or the
+ * number of cores.
+ */
+export function defaultWorkerCount(env: NodeJS.ProcessEnv = process.env): number {
+  const gomaxprocs = Number(env.GOMAXPROCS);
+  return Number.isInteger(gomaxprocs) && gomaxprocs > 0 ? gomaxprocs : availableParallelism();
+}
+
+/**
+ * Parse a worker count option.
+ */
+export function parseWorkerCount(value: string): number {
+  const workers = Number(value);
+  if (!Number.isInteger(workers) || workers < 1) {
+    throw new Error(`Invalid --workers: ${value} (expected a positive integer)`);
+  }
+  return workers;
+}
+
+/**
+ * Call `fn` for each item with at most `limit` calls pending at a time, so
+ * writes don't queue up unboundedly. Rejects with the first failure once the
+ * pending calls settle.
+ */
+export async function forEachLimit<T>(
+  items: T[],
+  limit: number,
+  fn: (item: T) => Promise<void>,
+): Promise<void> {
+  let next = 0;
+  const lane = async () => {
+    while (next < items.length) {
+      await fn(items[next++]);
+    }
+  };
+  const lanes = Array.from({ length: Math.min(limit, items.length) }, lane);
+  const results = await Promise.allSettled(lanes);
+  const failed = results.find((result) => result.status === "rejected");
+  if (failed) throw failed.reason;
+}
+
+/**
+ * Smallest heap limit a worker is started with, in megabytes.
+ */
+export const minWorkerMemoryMb = 32;
+
+/**
+ * Heap limit of each worker, in megabytes, sharing a memory budget. Below
+ * `minWorkerMemoryMb`, workers couldn't start.
+ */
+export function workerMemoryLimit(maxMemoryMb: number, workers: number): number {
+  const limit = Math.floor(maxMemoryMb / workers);
+  if (limit < minWorkerMemoryMb) {
+    throw new Error(
+      `--max-memory ${maxMemoryMb} leaves ${limit} MB per worker, under ${minWorkerMemoryMb} MB ` +
+        "(lower --workers or raise --max-memory)",
+    );
+  }
+  return limit;
+}
+
+/**
+ * A task that ran out of time or memory, and took its worker down.
+ */
+export class WorkerLimitError extends Error {
+  readonly limit: "timeout" | "memory";
+
+  constructor(limit: "timeout" | "memory", message: string) {
+    super(message);
+    this.limit = limit;
+  }
+}
+
+/**
+ * The part of a worker thread the pool uses.
+ */
+export type PoolWorker = Pick<Worker, "postMessage" | "on" | "terminate" | "ref" | "unref">;
+
+/**
+ * A worker's reply to a task: its result, or the message of the error it
+ * failed with.
+ */
+export interface WorkerReply<R> {
+  result?: R;
+  error?: string;
+}
+
+/**
+ * A queued or running task.
+ */
+interface Job<T, R> {
+  task: T;
+  timeoutMs?: number | (() => number);
+  timer?: NodeJS.Timeout;
+  resolve: (result: R) => void;
+  reject: (error: Error) => void;
+}
+
+/**
+ * Runs tasks on up to `size` workers.
+ */
+export class WorkerPool<T, R> {
+  private size: number;
+  private spawn: () => PoolWorker;
+  private workers: PoolWorker[] = [];
+  private idle: PoolWorker[] = [];
+  private queue: Array<Job<T, R>> = [];
+  private running = new Map<PoolWorker, Job<T, R>>();
+
+  /**
+   * A pool of `size` workers created by `spawn`.
+   */
+  constructor(size: number, spawn: () => PoolWorker) {
+    this.size = size;
+    this.spawn = spawn;
+  }
+
+  /**
+   * Run a task on the next free worker, terminating the worker if the task
+   * takes longer than `timeoutMs` from the moment it starts. A function is
+   * called as the task starts, for time budgets shared between tasks; a task
+   * with no time left fails without running.
+   */
+  run(task: T, timeoutMs?: number | (() => number)): Promise<R> {
+    return new Promise<R>((resolve, reject) => {
+      this.queue.push({ task, timeoutMs, resolve, reject });
+      this.dispatch();
+    });
+  }
+
+  /**
+   * Stop every worker. Queued tasks are left pending.
+   */
+  async close(): Promise<void> {
+    const workers = this.workers;
+    this.workers = [];
+    this.idle = [];
+    for (const job of this.running.values()) clearTimeout(job.timer);
+    this.running.clear();
+    await Promise.all(workers.map((worker) => worker.terminate()));
+  }
+
+  private dispatch(): void {
+    while (this.queue.length > 0) {
+      const worker =
+        this.idle.pop() ?? (this.workers.length < this.size ? this.start() : undefined);
+      if (!worker) return;
+      const job = this.queue.shift()!;
+      const timeoutMs = typeof job.timeoutMs === "function" ? job.timeoutMs() : job.timeoutMs;
+      if (timeoutMs !== undefined && timeoutMs <= 0) {
+        job.reject(new WorkerLimitError("timeout", "timed out before it started"));
+        this.idle.push(worker);
+        continue;
+      }
+      this.running.set(worker, job);
+      if (timeoutMs !== undefined) {
+        job.timer = setTimeout(() => {
+          this.remove(worker, new WorkerLimitError("timeout", `timed out after ${timeoutMs} ms`));
+          void worker.terminate();
+        }, timeoutMs);
+      }
+      worker.ref();
+      worker.postMessage(job.task);
+    }
+  }
+
+  private start(): PoolWorker {
+    const worker = this.spawn();
+    worker.unref();
+    this.workers.push(worker);
+    worker.on("message", (reply: WorkerReply<R>) => {
+      if (!this.workers.includes(worker)) return;
+      const job = this.running.get(worker);
+      this.running.delete(worker);
+      clearTimeout(job?.timer);
+      if (reply.error !== undefined) {
+        job?.reject(new Error(reply.error));
+      } else {
+        job?.resolve(reply.result as R);
+      }
+      worker.unref();
+      this.idle.push(worker);
+      this.dispatch();
+    });
+    // A worker that crashes fails its task and is replaced on demand
+    worker.on("error", (error: Error & { code?: string }) => {
+      const outOfMemory = error.code === "ERR_WORKER_OUT_OF_MEMORY";
+      this.remove(worker, outOfMemory ? new WorkerLimitError("memory", error.message) : error);
+    });
+    worker.on("exit", (code: number) => {
+      this.remove(worker, new Error(`Worker exited with code ${code}`));
+    });
+    return worker;
+  }
+
+  /**
+   * Drop a worker that stopped, failing its task.
+   */
+  private remove(worker: PoolWorker, error: Error): void {
+    if (!this.workers.includes(worker)) return;
+    this.workers = this.workers.filter((w) => w !== worker);
+    this.idle = this.idle.filter((w) => w !== worker);
+    const job = this.running.get(worker);
+    this.running.delete(worker);
+    clearTimeout(job?.timer);
+    job?.reject(error);
+    this.dispatch();
+  }
+}
@@ -0,0 +1,251 @@
+/**
+ * Go Workspaces
+ *
+ * Discovers the member modules of a `go.work` workspace, so a multi-module
+ * repository is extracted in one run: each module with its own module path
+ * and version, and without the files of members nested inside it. A
+ * combined manifest lists the module outputs with their symbol counts and
+ * SHA-256 checksums.
+ *
+ * Versions come from git tags on the checked-out commit, the way the Go
+ * module proxy derives them: `v1.2.3` for a module at the repository root,
+ * and `sub/dir/v1.2.3` for a module in `sub/dir`. Untagged commits get a
+ * pseudo-version after the latest tag they descend from.
+ */
+
+import { execFile } from "child_process";
+import { createHash } from "crypto";
+import { existsSync } from "fs";
+import { readFile } from "fs/promises";
+import { join, posix } from "path";
+import { promisify } from "util";
+import { compareVersions } from "./history.js";
+
+/**
+ * Name of the workspace file.
+ */
+export const goWorkFileName = "go.work";
+
+/**
+ * Name of the combined manifest of a workspace extraction.
+ */
+export const workspaceManifestFile = "workspace.json";
+
+/**
+ * Directives of a `go.work` file that matter for extraction.
+ */
+export interface GoWork {
+  /** Go version of the workspace */
+  go?: string;
+  /** Directories of the member modules, as written */
+  use: string[];
+}
+
+/**
+ * A member module of a workspace.
+ */
+export interface WorkspaceModule {
+  /** Directory relative to the workspace root, with forward slashes ("." for the root) */
+  dir: string;
+  /** Module path from the module's go.mod */
+  modulePath: string;
+  /** Version of the checked-out commit, or 0.0.0 outside a git repository */
+  version: string;
+}
+
+/**
+ * A workspace and its member modules, in `use` order.
+ */
+export interface GoWorkspace {
+  root: string;
+  go?: string;
+  modules: WorkspaceModule[];
+}
+
+/**
+ * A module output listed in the combined manifest.
+ */
+export interface WorkspaceManifestEntry {
+  modulePath: string;
+  version: string;
+  dir: string;
+  /** Path of the module output, relative to the manifest */
+  file: string;
+  symbols: number;
+  /** SHA-256 of the module output, hex-encoded */
+  sha256: string;
+}
+
+/**
+ * Combined manifest of a workspace extraction.
+ */
+export interface WorkspaceManifest {
+  workspace: { name: string; go?: string };
+  modules: WorkspaceManifestEntry[];
+}
+
+/**
+ * Tags that are valid module versions.
+ */
+const SEMVER = /^v\d+\.\d+\.\d+(-[\w.-]+)?(\+[\w.-]+)?$/;
+
+/**
+ * Parse a `go.work` file. Directives other than `go` and `use` are skipped.
+ */
+export function parseGoWork(text: string): GoWork {
+  const work: GoWork = { use: [] };
+  let block: string | undefined;
+  text.split(/\r?\n/).forEach((raw, i) => {
+    const line = raw.replace(/\/\/.*$/, "").trim();
+    if (line === "") return;
+    if (block !== undefined) {
+      if (line === ")") {
+        block = undefined;
+      } else if (block === "use") {
+        work.use.push(unquote(line, i + 1));
+      }
+      return;
+    }
+    const [, directive, rest] = line.match(/^(\w+)\s*(.*)$/) ?? [];
+    if (!directive) {
+      throw new Error(`${goWorkFileName}:${i + 1}: unexpected "${line}"`);
+    }
+    if (rest === "(") {
+      block = directive;
+    } else if (directive === "go") {
+      work.go = rest;
+    } else if (directive === "use") {
+      work.use.push(unquote(rest, i + 1));
+    }
+  });
+  if (block !== undefined) {
+    throw new Error(`${goWorkFileName}: unterminated ${block} block`);
+  }
+  return work;
+}
+
+/**
+ * Load the workspace rooted at `dir`, or return undefined if the directory
+ * has no `go.work`. Fails if a member has no go.mod.
+ */
+export async function loadWorkspace(dir: string): Promise<GoWorkspace | undefined> {
+  const file = join(dir, goWorkFileName);
+  if (!existsSync(file)) return undefined;
+  const work = parseGoWork(await readFile(file, "utf-8"));
+
+  const modules: WorkspaceModule[] = [];
+  for (const use of work.use) {
+    const memberDir = posix.normalize(use.replace(/\\/g, "/"));
+    if (memberDir.startsWith("..") || posix.isAbsolute(memberDir)) {
+      throw new Error(`${file}: ${use} is outside the workspace`);
+    }
+    let goMod: string;
+    try {
+      goMod = await readFile(join(dir, memberDir, "go.mod"), "utf-8");
+    } catch {
+      throw new Error(`${file}: ${use} has no go.mod`);
+    }
+    const modulePath = goMod.match(/^module\s+"?([^"\s]+)"?/m)?.[1];
+    if (!modulePath) {
+      throw new Error(`${file}: ${use}/go.mod has no module directive`);
+    }
+    const version = (await moduleVersion(join(dir, memberDir))) ?? "0.0.0";
+    modules.push({ dir: memberDir, modulePath, version });
+  }
+  return { root: dir, go: work.go, modules };
+}
+
+/**
+ * The version of the module in `dir` at the checked-out commit: the highest
+ * version tag on the commit, or else a pseudo-version. Undefined outside a
+ * git repository or one without commits.
+ */
+export async function moduleVersion(dir: string): Promise<string | undefined> {
+  const git = (...args: string[]) =>
+    promisify(execFile)("git", args, { cwd: dir }).then(({ stdout }) => stdout.trim());
+  try {
+    const prefix = await git("rev-parse", "--show-prefix");
+    const latest = (tags: string) =>
+      tags
+        .split("\n")
+        .map((tag) => tag.slice(prefix.length))
+        .filter((version) => SEMVER.test(version))
+        .sort(compareVersions)
+        .at(-1);
+    const tagged = latest(await git("tag", "--points-at", "HEAD", "--list", `${prefix}v*`));
+    if (tagged) return tagged;
+
+    const base = latest(await git("tag", "--merged", "HEAD", "--list", `${prefix}v*`));
+    const [time, revision] = (await git("show", "-s", "--format=%ct %H", "HEAD")).split(" ");
+    return pseudoVersion(base, new Date(Number(time) * 1000), revision);
+  } catch {
+    return undefined;
+  }
+}
+
+/**
+ * The pseudo-version of a commit made at `time`, after the latest version
+ * tag it descends from, as the Go module proxy derives it:
+ * `v0.0.0-20261016120000-0123456789ab` without a tag,
+ * `v1.2.4-0.20261016120000-0123456789ab` after `v1.2.3`, and
+ * `v1.3.0-rc.1.0.20261016120000-0123456789ab` after `v1.3.0-rc.1`.
+ */
+export function pseudoVersion(base: string | undefined, time: Date, revision: string): string {
+  const suffix = `${time.toISOString().replace(/\D/g, "").slice(0, 14)}-${revision.slice(0, 12)}`;
+  const [, major, minor, patch, pre] = base?.match(/^v(\d+)\.(\d+)\.(\d+)(-[\w.-]+)?/) ?? [];
+  if (major === undefined) return `v0.0.0-${suffix}`;
+  if (pre) return `v${major}.${minor}.${patch}${pre}.0.${suffix}`;
+  return `v${major}.${minor}.${Number(patch) + 1}-0.${suffix}`;
+}
+
+/**
+ * Exclude patterns for the members nested inside a module's directory,
+ * relative to that directory, so their files are left to their own module.
+ */
+export function nestedModuleExcludes(module: WorkspaceModule, workspace: GoWorkspace): string[] {
+  const prefix = module.dir === "." ? "" : `${module.dir}/`;
+  return workspace.modules
+    .filter((other) => other !== module && other.dir.startsWith(prefix) && other.dir !== ".")
+    .map((other) => `${other.dir.slice(prefix.length)}/**`);
+}
+
+/**
+ * Path of a module's output in a workspace extraction.
+ */
+export function workspaceModuleFile(module: WorkspaceModule): string {
+  return `${module.modulePath}.json`;
+}
+
+/**
+ * Build the combined manifest of the module outputs, given their symbol
+ * counts and serialized contents.
+ */
+export function buildWorkspaceManifest(
+  name: string,
+  workspace: GoWorkspace,
+  outputs: Array<{ module: WorkspaceModule; symbols: number; content: string }>,
+): WorkspaceManifest {
+  return {
+    workspace: { name, go: workspace.go },
+    modules: outputs.map(({ module, symbols, content }) => ({
+      modulePath: module.modulePath,
+      version: module.version,
+      dir: module.dir,
+      file: workspaceModuleFile(module),
+      symbols,
+      sha256: createHash("sha256").update(content).digest("hex"),
+    })),
+  };
+}
+
+/**
+ * Strip the quotes of a quoted `use` path.
+ */
+function unquote(path: string, line: number): string {
+  if (!path.startsWith('"') && !path.startsWith("`")) return path;
+  const quote = path[0];
+  if (path.length < 2 || !path.endsWith(quote)) {
+    throw new Error(`${goWorkFileName}:${line}: unterminated path ${path}`);
+  }
+  return quote === '"' ? (JSON.parse(path) as string) : path.slice(1, -1);
+}
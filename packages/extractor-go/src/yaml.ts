@@ -0,0 +1,314 @@
+/**
+ * YAML Subset
+ *
+ * Parses the subset of YAML that configuration files use, without a
+ * dependency: block mappings and sequences (including sequences of
+ * mappings), flow sequences and mappings of scalars (`[a, b]`, `{a: 1}`),
+ * plain, single-quoted, and double-quoted scalars, and `#` comments. Plain
+ * scalars are read as booleans, null, numbers, or strings, as in YAML 1.2.
+ * Anchors, tags, block scalars (`|`, `>`), and multiple documents aren't
+ * supported and are reported as errors, as are tabs in indentation.
+ */
+
+/**
+ * A content line: its indentation and text without the trailing comment.
+ */
+interface YamlLine {
+  number: number;
+  indent: number;
+  text: string;
+}
+
+/**
+ * Characters a plain scalar can't start with, because they start YAML
+ * constructs the subset doesn't support.
+ */
+const UNSUPPORTED_INDICATORS = /^[&*!|>%@`]/;
+
+/**
+ * Parse a YAML document. An empty document is null.
+ */
+export function parseYaml(text: string): unknown {
+  const lines: YamlLine[] = [];
+  text.split(/\r?\n/).forEach((raw, i) => {
+    const content = stripComment(raw).trimEnd();
+    if (content.trim() === "") return;
+    const indentation = content.match(/^[ \t]*/)![0];
+    if (indentation.includes("\t")) {
+      throw new YamlError(i + 1, "tabs can't be used for indentation");
+    }
+    if (content === "---" || content === "...") {
+      throw new YamlError(i + 1, "multiple documents aren't supported");
+    }
+    lines.push({ number: i + 1, indent: indentation.length, text: content.trim() });
+  });
+  if (lines.length === 0) return null;
+
+  const parser = new YamlParser(lines);
+  const value = parser.block(lines[0].indent);
+  parser.expectEnd();
+  return value;
+}
+
+/**
+ * A syntax error, with the line it's on.
+ */
+export class YamlError extends Error {
+  line: number;
+
+  constructor(line: number, message: string) {
+    super(`line ${line}: ${message}`);
+    this.name = "YamlError";
+    this.line = line;
+  }
+}
+
+/**
+ * Recursive-descent parser over the content lines.
+ */
+class YamlParser {
+  lines: YamlLine[];
+  pos = 0;
+
+  constructor(lines: YamlLine[]) {
+    this.lines = lines;
+  }
+
+  /**
+   * Parse the mapping or sequence starting at the current line.
+   */
+  block(indent: number): unknown {
+    return isSequenceItem(this.current.text) ? this.sequence(indent) : this.mapping(indent);
+  }
+
+  /**
+   * Fail on lines left over after the top-level block.
+   */
+  expectEnd(): void {
+    const line = this.lines[this.pos];
+    if (line) throw new YamlError(line.number, `unexpected "${line.text}"`);
+  }
+
+  /**
+   * Parse a block sequence of items at `indent`.
+   */
+  private sequence(indent: number): unknown[] {
+    const items: unknown[] = [];
+    while (this.at(indent) && isSequenceItem(this.current.text)) {
+      const line = this.current;
+      const rest = line.text.slice(1).trimStart();
+      if (rest === "") {
+        this.pos++;
+        items.push(this.nested(indent, false));
+      } else if (entryKey(rest, line.number)) {
+        // A mapping item: its keys line up with the first one
+        const itemIndent = indent + line.text.length - rest.length;
+        this.lines[this.pos] = { ...line, indent: itemIndent, text: rest };
+        items.push(this.mapping(itemIndent));
+      } else {
+        this.pos++;
+        items.push(parseValue(rest, line.number));
+      }
+    }
+    this.expectDedent(indent);
+    return items;
+  }
+
+  /**
+   * Parse a block mapping of entries at `indent`.
+   */
+  private mapping(indent: number): Record<string, unknown> {
+    const entries: Record<string, unknown> = {};
+    while (this.at(indent)) {
+      const line = this.current;
+      const entry = entryKey(line.text, line.number);
+      if (!entry) {
+        throw new YamlError(line.number, `expected a "key: value" entry`);
+      }
+      if (Object.hasOwn(entries, entry.key)) {
+        throw new YamlError(line.number, `duplicate key "${entry.key}"`);
+      }
+      this.pos++;
+      entries[entry.key] =
+        entry.rest === "" ? this.nested(indent, true) : parseValue(entry.rest, line.number);
+    }
+    this.expectDedent(indent);
+    return entries;
+  }
+
+  /**
+   * Parse the value of an entry or item that continues on the next lines:
+   * a more indented block, or, for mapping entries, a sequence at the same
+   * indentation. Null when there is none.
+   */
+  private nested(indent: number, sameIndentSequence: boolean): unknown {
+    const next = this.lines[this.pos];
+    if (next && next.indent > indent) return this.block(next.indent);
+    if (next && sameIndentSequence && next.indent === indent && isSequenceItem(next.text)) {
+      return this.sequence(indent);
+    }
+    return null;
+  }
+
+  /**
+   * Fail if the block is followed by a line indented deeper than it.
+   */
+  private expectDedent(indent: number): void {
+    const line = this.lines[this.pos];
+    if (line && line.indent > indent) {
+      throw new YamlError(line.number, "unexpected indentation");
+    }
+  }
+
+  /**
+   * Whether the current line is at `indent`.
+   */
+  private at(indent: number): boolean {
+    return this.pos < this.lines.length && this.current.indent === indent;
+  }
+
+  /**
+   * The line being parsed.
+   */
+  private get current(): YamlLine {
+    return this.lines[this.pos];
+  }
+}
+
+/**
+ * Whether a line is a block sequence item.
+ */
+function isSequenceItem(text: string): boolean {
+  return text === "-" || text.startsWith("- ");
+}
+
+/**
+ * Split a `key: value` entry into its key and the rest of the line, or
+ * return undefined when the text isn't an entry.
+ */
+function entryKey(text: string, line: number): { key: string; rest: string } | undefined {
+  if (text.startsWith('"') || text.startsWith("'")) {
+    const end = quotedEnd(text);
+    if (end < 0) throw new YamlError(line, "unterminated quoted key");
+    const after = text.slice(end + 1);
+    if (!/^\s*:(\s|$)/.test(after)) return undefined;
+    return {
+      key: parseQuoted(text.slice(0, end + 1), line),
+      rest: after.replace(/^\s*:/, "").trim(),
+    };
+  }
+  if (text.startsWith("[") || text.startsWith("{")) return undefined;
+  const match = text.match(/^([^:]*?):(\s|$)/);
+  if (!match) return undefined;
+  return { key: match[1].trim(), rest: text.slice(match[0].length).trim() };
+}
+
+/**
+ * Parse an inline value: a flow sequence or mapping, or a scalar.
+ */
+function parseValue(text: string, line: number): unknown {
+  if (text.startsWith("[") || text.startsWith("{")) {
+    const close = text[0] === "[" ? "]" : "}";
+    if (!text.endsWith(close)) throw new YamlError(line, `expected "${close}"`);
+    const items = splitFlow(text.slice(1, -1), line);
+    if (close === "]") return items.map((item) => parseScalar(item, line));
+    const entries: Record<string, unknown> = {};
+    for (const item of items) {
+      const entry = entryKey(item, line);
+      if (!entry) throw new YamlError(line, `expected "key: value" in "${item}"`);
+      entries[entry.key] = parseScalar(entry.rest, line);
+    }
+    return entries;
+  }
+  return parseScalar(text, line);
+}
+
+/**
+ * Split the items of a flow collection at commas outside quotes.
+ */
+function splitFlow(text: string, line: number): string[] {
+  const items: string[] = [];
+  let start = 0;
+  for (let i = 0; i <= text.length; i++) {
+    if (text[i] === '"' || text[i] === "'") {
+      const end = quotedEnd(text.slice(i));
+      if (end < 0) throw new YamlError(line, "unterminated quoted string");
+      i += end;
+    } else if (text[i] === "[" || text[i] === "{") {
+      throw new YamlError(line, "nested flow collections aren't supported");
+    } else if (text[i] === "," || i === text.length) {
+      const item = text.slice(start, i).trim();
+      if (item !== "") items.push(item);
+      start = i + 1;
+    }
+  }
+  return items;
+}
+
+/**
+ * Parse a scalar: quoted strings as written, plain scalars as booleans,
+ * null, numbers, or strings.
+ */
+function parseScalar(text: string, line: number): unknown {
+  if (text.startsWith('"') || text.startsWith("'")) {
+    if (quotedEnd(text) !== text.length - 1) {
+      throw new YamlError(line, `unexpected text after quoted string: ${text}`);
+    }
+    return parseQuoted(text, line);
+  }
+  if (UNSUPPORTED_INDICATORS.test(text)) {
+    throw new YamlError(line, `unsupported YAML syntax: ${text} (quote the value)`);
+  }
+  if (text === "" || text === "~" || text === "null") return null;
+  if (text === "true" || text === "false") return text === "true";
+  if (/^[-+]?(\d+|\d*\.\d+)([eE][-+]?\d+)?$/.test(text)) return Number(text);
+  return text;
+}
+
+/**
+ * Parse a single- or double-quoted string.
+ */
+function parseQuoted(text: string, line: number): string {
+  if (text[0] === "'") return text.slice(1, -1).replace(/''/g, "'");
+  try {
+    return JSON.parse(text) as string;
+  } catch {
+    throw new YamlError(line, `invalid double-quoted string: ${text}`);
+  }
+}
+
+/**
+ * Index of the quote closing the quoted string `text` starts with, or -1.
+ */
+function quotedEnd(text: string): number {
+  const quote = text[0];
+  for (let i = 1; i < text.length; i++) {
+    if (quote === '"' && text[i] === "\\") {
+      i++;
+    } else if (text[i] === quote) {
+      if (quote === "'" && text[i + 1] === "'") {
+        i++;
+      } else {
+        return i;
+      }
+    }
+  }
+  return -1;
+}
+
+/**
+ * Remove a `#` comment from a line: one at its start or after whitespace,
+ * outside quotes.
+ */
+function stripComment(line: string): string {
+  for (let i = 0; i < line.length; i++) {
+    if ((line[i] === '"' || line[i] === "'") && (i === 0 || /[\s:[{,-]/.test(line[i - 1]))) {
+      const end = quotedEnd(line.slice(i));
+      if (end < 0) return line;
+      i += end;
+    } else if (line[i] === "#" && (i === 0 || /\s/.test(line[i - 1]))) {
+      return line.slice(0, i);
+    }
+  }
+  return line;
+}